@@ -0,0 +1,265 @@
+package cli
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/targets"
+	"github.com/konveyor/test-harness/pkg/util"
+	"github.com/spf13/cobra"
+	yaml2 "gopkg.in/yaml.v2"
+)
+
+var (
+	archiveStore         string
+	archiveOutput        string
+	archiveTarget        string
+	archiveTargetConfigs []string
+)
+
+// NewArchiveCmd creates the archive command
+func NewArchiveCmd() *cobra.Command {
+	archiveCmd := &cobra.Command{
+		Use:   "archive [run-id]",
+		Short: "Bundle a recorded run into a tar.gz for CI artifacts or bug reports",
+		Long: `Bundle a run recorded in the results store (default: the latest run) into a
+single tar.gz with, for every test:
+
+  test.yaml             the test definition
+  expected-output.yaml  its expected output file, if any
+  output.yaml           the actual output of the run
+  logs/                 the analysis and provider logs of its work directory
+  failure.txt           validation errors and diffs, for failed tests
+
+and the run record as run.json. Secrets of the target configurations (given with
+--target-config or auto-discovered in .koncur/config) and the values of password,
+token, secret and authorization fields are redacted from every file.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log := util.GetLogger()
+
+			// Loading target configs registers their secrets for redaction
+			files := archiveTargetConfigs
+			if len(files) == 0 {
+				files, _ = filepath.Glob(".koncur/config/target-*.yaml")
+			}
+			for _, file := range files {
+				if _, err := config.LoadTargetConfig(file); err != nil {
+					log.Info("Could not load target config, its secrets are only redacted by field name", "file", file, "error", err.Error())
+				}
+			}
+
+			store, err := OpenResultsStore(archiveStore)
+			if err != nil {
+				return configError("%w", err)
+			}
+			runs, err := store.ListRuns()
+			if err != nil {
+				return err
+			}
+			runID := ""
+			if len(args) == 1 {
+				runID = args[0]
+			}
+			run := findRun(runs, runID, archiveTarget)
+			if run == nil {
+				if runID != "" {
+					return configError("run %s not found in results store %s", runID, archiveStore)
+				}
+				return configError("no recorded run in results store %s", archiveStore)
+			}
+
+			output := archiveOutput
+			if output == "" {
+				output = fmt.Sprintf("koncur-run-%s.tar.gz", run.ID)
+			}
+			count, err := writeRunArchive(output, store, run)
+			if err != nil {
+				return err
+			}
+			color.Green("%s Archived run %s (%d file(s)) to %s", symbolPass, run.ID, count, output)
+			return nil
+		},
+	}
+
+	archiveCmd.Flags().StringVar(&archiveStore, "results-store", defaultResultsStore, "Results store location to read the run from")
+	archiveCmd.Flags().StringVarP(&archiveOutput, "output", "o", "", "Archive file to write (default: koncur-run-<id>.tar.gz)")
+	archiveCmd.Flags().StringVarP(&archiveTarget, "target", "t", "", "Archive the latest run on this target")
+	archiveCmd.Flags().StringArrayVarP(&archiveTargetConfigs, "target-config", "c", nil, "Target configuration whose secrets are redacted (repeatable, default: .koncur/config/target-*.yaml)")
+
+	return archiveCmd
+}
+
+// findRun returns the run with the given ID, or the latest run (on a target, if given)
+func findRun(runs []*RunRecord, id, target string) *RunRecord {
+	for i := len(runs) - 1; i >= 0; i-- {
+		run := runs[i]
+		if id != "" && run.ID != id {
+			continue
+		}
+		if target != "" && run.Target != target {
+			continue
+		}
+		return run
+	}
+	return nil
+}
+
+// runArchive writes redacted files into a tar.gz under a directory named after the run
+type runArchive struct {
+	tw      *tar.Writer
+	root    string
+	modTime time.Time
+	count   int
+}
+
+func (a *runArchive) add(name string, data []byte) error {
+	data = []byte(util.RedactText(string(data)))
+	header := &tar.Header{
+		Name:    path.Join(a.root, name),
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: a.modTime,
+	}
+	if err := a.tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+	if _, err := a.tw.Write(data); err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+	a.count++
+	return nil
+}
+
+// addFile adds a file from disk; files that no longer exist are skipped
+func (a *runArchive) addFile(name, file string) error {
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	return a.add(name, data)
+}
+
+// writeRunArchive writes the archive of a run and returns the number of files in it
+func writeRunArchive(output string, store ResultsStore, run *RunRecord) (int, error) {
+	f, err := os.Create(output)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create archive %s: %w", output, err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	archive := &runArchive{tw: tar.NewWriter(gz), root: "koncur-run-" + run.ID, modTime: run.StartedAt}
+
+	record, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal run: %w", err)
+	}
+	if err := archive.add("run.json", record); err != nil {
+		return 0, err
+	}
+
+	if run.Summary != nil {
+		for _, result := range run.Summary.Tests {
+			if err := archiveTest(archive, store, run, result); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if err := archive.tw.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write archive: %w", err)
+	}
+	return archive.count, nil
+}
+
+// archiveTest adds the definition, outputs, logs and failure details of a test
+func archiveTest(archive *runArchive, store ResultsStore, run *RunRecord, result TestResult) error {
+	dir := result.Name
+	if result.TestFile != "" {
+		testFile, _ := config.SplitTestRef(result.TestFile)
+		if err := archive.addFile(path.Join(dir, "test.yaml"), testFile); err != nil {
+			return err
+		}
+	}
+	if result.ExpectedFile != "" {
+		if err := archive.addFile(path.Join(dir, "expected-output.yaml"), result.ExpectedFile); err != nil {
+			return err
+		}
+	}
+
+	// Prefer the raw output of the work directory, falling back to the normalized stored one
+	if _, err := os.Stat(result.OutputFile); result.OutputFile != "" && err == nil {
+		if err := archive.addFile(path.Join(dir, "output.yaml"), result.OutputFile); err != nil {
+			return err
+		}
+	} else {
+		stored, err := store.LoadOutput(run.ID, run.Target, result.Name)
+		if err != nil {
+			return fmt.Errorf("failed to load output of %s: %w", result.Name, err)
+		}
+		if stored != nil {
+			data, err := yaml2.Marshal(stored)
+			if err != nil {
+				return fmt.Errorf("failed to marshal output of %s: %w", result.Name, err)
+			}
+			if err := archive.add(path.Join(dir, "output.yaml"), data); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, logFile := range findLogFiles(result.WorkDir) {
+		name := filepath.Base(logFile)
+		if abs, err := filepath.Abs(result.WorkDir); err == nil {
+			if rel, err := filepath.Rel(abs, logFile); err == nil {
+				name = filepath.ToSlash(rel)
+			}
+		}
+		if err := archive.addFile(path.Join(dir, "logs", name), logFile); err != nil {
+			return err
+		}
+	}
+
+	// Command lines, console output and settings the target recorded
+	if result.WorkDir != "" {
+		artifactsDir := targets.ArtifactsDir(result.WorkDir)
+		err := filepath.WalkDir(artifactsDir, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return nil
+			}
+			rel, err := filepath.Rel(artifactsDir, file)
+			if err != nil {
+				return nil
+			}
+			return archive.addFile(path.Join(dir, "artifacts", filepath.ToSlash(rel)), file)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if result.Status == "failed" || result.Status == "flaky" {
+		if err := archive.add(path.Join(dir, "failure.txt"), []byte(failureDetails(result))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
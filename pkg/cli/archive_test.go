@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konveyor/test-harness/pkg/util"
+	"github.com/konveyor/test-harness/pkg/validator"
+)
+
+func TestWriteRunArchive(t *testing.T) {
+	dir := t.TempDir()
+	util.RegisterSecret("archive-hub-secret")
+
+	testFile := filepath.Join(dir, "tests", "failing", "test.yaml")
+	workDir := filepath.Join(dir, "work", "failing")
+	for _, d := range []string{filepath.Dir(testFile), filepath.Join(workDir, "output"), filepath.Join(workDir, "source"), filepath.Join(workDir, "artifacts")} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string]string{
+		testFile: "name: failing\n",
+		filepath.Join(workDir, "output", "output.yaml"):    "- name: ruleset\n",
+		filepath.Join(workDir, "output", "analysis.log"):   "login with archive-hub-secret\npassword: plain\n",
+		filepath.Join(workDir, "source", "ignored.log"):    "cloned sources are not archived\n",
+		filepath.Join(workDir, "artifacts", "command.txt"): "kantra analyze --input /app\n",
+		filepath.Join(dir, "tests", "failing", "exp.yaml"): "[]\n",
+	}
+	for file, content := range files {
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run := NewRunRecord(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), "kantra", "", &TestSummary{Tests: []TestResult{
+		{
+			Name:         "failing",
+			TestFile:     testFile,
+			Status:       "failed",
+			WorkDir:      workDir,
+			OutputFile:   filepath.Join(workDir, "output", "output.yaml"),
+			ExpectedFile: filepath.Join(dir, "tests", "failing", "exp.yaml"),
+			ValidationErrors: []validator.ValidationError{
+				{Path: "ruleset/violations/rule-1", Message: "Did not find expected violation"},
+			},
+		},
+		{Name: "skipped", Status: "skipped"},
+	}})
+	store, err := OpenResultsStore(filepath.Join(dir, "results"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := filepath.Join(dir, "run.tar.gz")
+	count, err := writeRunArchive(output, store, run)
+	if err != nil {
+		t.Fatalf("writeRunArchive() error = %v", err)
+	}
+
+	contents := readArchive(t, output)
+	root := "koncur-run-" + run.ID + "/"
+	for _, name := range []string{"run.json", "failing/test.yaml", "failing/expected-output.yaml", "failing/output.yaml", "failing/logs/output/analysis.log", "failing/artifacts/command.txt", "failing/failure.txt"} {
+		if _, ok := contents[root+name]; !ok {
+			t.Errorf("expected %s in archive, got %v", name, archiveNames(contents))
+		}
+	}
+	if count != len(contents) || len(contents) != 7 {
+		t.Errorf("expected 7 files, got %d (count %d): %v", len(contents), count, archiveNames(contents))
+	}
+	logContent := contents[root+"failing/logs/output/analysis.log"]
+	if strings.Contains(logContent, "archive-hub-secret") || strings.Contains(logContent, "plain") {
+		t.Errorf("expected secrets to be redacted, got %q", logContent)
+	}
+	if !strings.Contains(contents[root+"failing/failure.txt"], "Did not find expected violation") {
+		t.Errorf("expected validation errors in failure.txt, got %q", contents[root+"failing/failure.txt"])
+	}
+}
+
+func readArchive(t *testing.T, file string) map[string]string {
+	t.Helper()
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	contents := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return contents
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents[header.Name] = string(data)
+	}
+}
+
+func archiveNames(m map[string]string) []string {
+	var k []string
+	for key := range m {
+		k = append(k, key)
+	}
+	return k
+}
@@ -0,0 +1,404 @@
+package cli
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/targets"
+	"github.com/konveyor/test-harness/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultKantraURL is where kantra release binaries are downloaded from
+	defaultKantraURL = "https://github.com/konveyor/kantra/releases/download/{version}/kantra.{os}.{arch}.zip"
+	// kantraCacheDir keeps downloaded kantra releases between bisections
+	kantraCacheDir = ".koncur/cache/kantra"
+	// kantraDownloadTimeout bounds the download of a kantra release
+	kantraDownloadTimeout = 10 * time.Minute
+)
+
+var (
+	bisectKantraVersions []string
+	bisectKantraPath     string
+	bisectKantraURL      string
+	bisectRulesRange     string
+	bisectRulesRepo      string
+	bisectTargetType     string
+	bisectTargetConfig   string
+)
+
+// NewBisectCmd creates the bisect command
+func NewBisectCmd() *cobra.Command {
+	bisectCmd := &cobra.Command{
+		Use:   "bisect <test-file-or-directory>",
+		Short: "Find the first kantra version or rules commit that fails a test",
+		Long: `Bisect a range of kantra versions or rules repository commits to find the first
+one a test fails with.
+
+With --kantra-versions the candidates are kantra releases, oldest first. Each is taken
+from --kantra-path, or downloaded from --kantra-url into .koncur/cache/kantra:
+
+  koncur bisect tests/daytrader --kantra-versions v0.6.0,v0.6.1,v0.6.2,v0.7.0
+
+With --rules <good>..<bad> the candidates are the commits of the git repository
+holding the test's local rules (or --rules-repo). Each is checked out into a
+temporary worktree, and the test runs against the rules in it:
+
+  koncur bisect tests/daytrader --rules v0.7.0..main
+
+The first candidate must pass and the last one fail; both are checked before bisecting.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (len(bisectKantraVersions) > 0) == (bisectRulesRange != "") {
+				return configError("bisect needs either --kantra-versions or --rules")
+			}
+			testFiles, err := discoverTestFiles(args, &testSelector{})
+			if err != nil {
+				return err
+			}
+			if len(testFiles) != 1 {
+				return configError("bisect runs a single test, %d selected", len(testFiles))
+			}
+			testFile := testFiles[0]
+
+			var candidates []string
+			var run func(candidate string) (bool, error)
+			if len(bisectKantraVersions) > 0 {
+				candidates = bisectKantraVersions
+				run, err = kantraVersionRunner(testFile)
+			} else {
+				var cleanup func()
+				candidates, run, cleanup, err = rulesCommitRunner(testFile, bisectRulesRange)
+				if cleanup != nil {
+					defer cleanup()
+				}
+			}
+			if err != nil {
+				return err
+			}
+			if len(candidates) < 2 {
+				return configError("bisect needs at least two candidates, got %d", len(candidates))
+			}
+
+			firstBad, err := bisect(candidates, run)
+			if err != nil {
+				return err
+			}
+			fmt.Println()
+			color.Red("%s First bad: %s", symbolFail, candidates[firstBad])
+			color.Green("%s Last good: %s", symbolPass, candidates[firstBad-1])
+			return nil
+		},
+	}
+
+	bisectCmd.Flags().StringSliceVar(&bisectKantraVersions, "kantra-versions", nil, "kantra versions to bisect, oldest (good) to newest (bad), comma-separated")
+	bisectCmd.Flags().StringVar(&bisectKantraPath, "kantra-path", "", "Path of each kantra version's binary, {version} is replaced (default: download from --kantra-url)")
+	bisectCmd.Flags().StringVar(&bisectKantraURL, "kantra-url", defaultKantraURL, "URL of each kantra version's binary or zip archive; {version}, {os} and {arch} are replaced")
+	bisectCmd.Flags().StringVar(&bisectRulesRange, "rules", "", "Range of rules commits to bisect, as <good>..<bad>")
+	bisectCmd.Flags().StringVar(&bisectRulesRepo, "rules-repo", "", "Git repository of the rules (default: the repository of the test's local rules)")
+	bisectCmd.Flags().StringVarP(&bisectTargetType, "target", "t", "kantra", "Target type to use with --rules")
+	bisectCmd.Flags().StringVarP(&bisectTargetConfig, "target-config", "c", "", "Path to target configuration file (default: .koncur/config/target-<type>.yaml if present)")
+
+	return bisectCmd
+}
+
+// bisect returns the index of the first candidate run reports as failing. The
+// candidates are ordered, the first one passing and the last one failing.
+func bisect(candidates []string, run func(candidate string) (bool, error)) (int, error) {
+	check := func(i int) (bool, error) {
+		fmt.Printf("\n[%s] Testing %s\n", symbolRun, candidates[i])
+		passed, err := run(candidates[i])
+		if err != nil {
+			return false, fmt.Errorf("failed to test %s: %w", candidates[i], err)
+		}
+		if passed {
+			color.Green("  %s %s is good", symbolPass, candidates[i])
+		} else {
+			color.Red("  %s %s is bad", symbolFail, candidates[i])
+		}
+		return passed, nil
+	}
+
+	good, bad := 0, len(candidates)-1
+	if passed, err := check(good); err != nil {
+		return 0, err
+	} else if !passed {
+		return 0, configError("the first candidate %s fails already, start from an older one", candidates[good])
+	}
+	if passed, err := check(bad); err != nil {
+		return 0, err
+	} else if passed {
+		return 0, configError("the last candidate %s passes, nothing to bisect", candidates[bad])
+	}
+
+	for bad-good > 1 {
+		mid := (good + bad) / 2
+		fmt.Printf("\nBisecting: %d candidate(s) left\n", bad-good-1)
+		passed, err := check(mid)
+		if err != nil {
+			return 0, err
+		}
+		if passed {
+			good = mid
+		} else {
+			bad = mid
+		}
+	}
+	return bad, nil
+}
+
+// kantraVersionRunner runs the test with the kantra binary of a version
+func kantraVersionRunner(testFile string) (func(string) (bool, error), error) {
+	targetConfig, err := loadTargetConfig(bisectTargetConfig, "kantra")
+	if err != nil {
+		return nil, err
+	}
+	if targetConfig.Type != "kantra" {
+		return nil, configError("--kantra-versions needs a kantra target, got %s", targetConfig.Type)
+	}
+
+	return func(version string) (bool, error) {
+		binary, err := kantraBinary(version)
+		if err != nil {
+			return false, err
+		}
+		versionConfig := *targetConfig
+		var kantra config.KantraConfig
+		if targetConfig.Kantra != nil {
+			kantra = *targetConfig.Kantra
+		}
+		kantra.BinaryPath = binary
+		versionConfig.Kantra = &kantra
+		return runBisectTest(testFile, &versionConfig, nil)
+	}, nil
+}
+
+// kantraBinary returns the path of a kantra version's binary, downloading it when needed
+func kantraBinary(version string) (string, error) {
+	if bisectKantraPath != "" {
+		return absPath(strings.ReplaceAll(bisectKantraPath, "{version}", version)), nil
+	}
+
+	dir := filepath.Join(kantraCacheDir, version)
+	binary := absPath(filepath.Join(dir, "kantra"))
+	if _, err := os.Stat(binary); err == nil {
+		return binary, nil
+	}
+
+	url := strings.NewReplacer("{version}", version, "{os}", runtime.GOOS, "{arch}", runtime.GOARCH).Replace(bisectKantraURL)
+	util.GetLogger().Info("Downloading kantra", "version", version, "url", url)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create kantra cache directory: %w", err)
+	}
+	download := filepath.Join(dir, "download-"+filepath.Base(url))
+	if err := downloadFile(url, download); err != nil {
+		return "", fmt.Errorf("failed to download kantra %s: %w", version, err)
+	}
+	defer os.Remove(download)
+
+	if !strings.HasSuffix(download, ".zip") {
+		if err := os.Rename(download, binary); err != nil {
+			return "", fmt.Errorf("failed to install kantra %s: %w", version, err)
+		}
+		return binary, os.Chmod(binary, 0755)
+	}
+	if err := extractKantra(download, binary); err != nil {
+		return "", fmt.Errorf("failed to extract kantra %s: %w", version, err)
+	}
+	return binary, nil
+}
+
+func downloadFile(url, path string) error {
+	client := &http.Client{Timeout: kantraDownloadTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// extractKantra extracts the kantra binary of a release archive, the file whose
+// name contains "kantra" (e.g. darwin-kantra or kantra.exe)
+func extractKantra(archive, binary string) error {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, file := range r.File {
+		if file.FileInfo().IsDir() || !strings.Contains(filepath.Base(file.Name), "kantra") {
+			continue
+		}
+		src, err := file.Open()
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		dst, err := os.OpenFile(binary, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(dst, src); err != nil {
+			dst.Close()
+			return err
+		}
+		return dst.Close()
+	}
+	return fmt.Errorf("no kantra binary in %s", archive)
+}
+
+// rulesCommitRunner lists the commits of a rules range, oldest first, and runs the
+// test with its local rules taken from a worktree of each commit
+func rulesCommitRunner(testFile, rulesRange string) ([]string, func(string) (bool, error), func(), error) {
+	good, bad, ok := strings.Cut(rulesRange, "..")
+	if !ok || good == "" || bad == "" {
+		return nil, nil, nil, configError("--rules must be a range <good>..<bad>, got %q", rulesRange)
+	}
+	test, err := config.Load(testFile)
+	if err != nil {
+		return nil, nil, nil, configError("failed to load test: %w", err)
+	}
+	targetConfig, err := loadTargetConfig(bisectTargetConfig, bisectTargetType)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	repo := bisectRulesRepo
+	if repo == "" {
+		for _, rule := range test.Analysis.Rules {
+			if config.IsGitURL(rule) {
+				continue
+			}
+			repo = rule
+			if info, err := os.Stat(rule); err == nil && !info.IsDir() {
+				repo = filepath.Dir(rule)
+			}
+			break
+		}
+		if repo == "" {
+			return nil, nil, nil, configError("test %s has no local rules, set --rules-repo", test.Name)
+		}
+	}
+	root, err := gitOutputIn(repo, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, nil, nil, configError("rules are not in a git repository: %w", err)
+	}
+	root = strings.TrimSpace(root)
+
+	commits, err := rulesCommits(root, good, bad)
+	if err != nil {
+		return nil, nil, nil, configError("%w", err)
+	}
+
+	worktrees, err := os.MkdirTemp("", "koncur-bisect-")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to create worktree directory: %w", err)
+	}
+	cleanup := func() {
+		gitOutputIn(root, "worktree", "prune")
+		os.RemoveAll(worktrees)
+	}
+
+	run := func(commit string) (bool, error) {
+		worktree := filepath.Join(worktrees, commit)
+		if _, err := gitOutputIn(root, "worktree", "add", "--detach", worktree, commit); err != nil {
+			return false, err
+		}
+		defer gitOutputIn(root, "worktree", "remove", "--force", worktree)
+
+		rules, err := rebaseRules(test.Analysis.Rules, root, worktree)
+		if err != nil {
+			return false, err
+		}
+		return runBisectTest(testFile, targetConfig, rules)
+	}
+	return commits, run, cleanup, nil
+}
+
+// rulesCommits lists the commits from good to bad, oldest first, abbreviated
+func rulesCommits(repo, good, bad string) ([]string, error) {
+	first, err := gitOutputIn(repo, "rev-parse", "--short", good)
+	if err != nil {
+		return nil, err
+	}
+	out, err := gitOutputIn(repo, "rev-list", "--reverse", "--ancestry-path", "--abbrev-commit", good+".."+bad)
+	if err != nil {
+		return nil, err
+	}
+	commits := []string{strings.TrimSpace(first)}
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			commits = append(commits, line)
+		}
+	}
+	return commits, nil
+}
+
+// rebaseRules moves the local rules inside a repository onto the same paths in a worktree
+func rebaseRules(rules []string, repo, worktree string) ([]string, error) {
+	rebased := make([]string, 0, len(rules))
+	found := false
+	for _, rule := range rules {
+		if config.IsGitURL(rule) {
+			rebased = append(rebased, rule)
+			continue
+		}
+		rel, err := filepath.Rel(repo, absPath(rule))
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			rebased = append(rebased, rule)
+			continue
+		}
+		rebased = append(rebased, filepath.Join(worktree, rel))
+		found = true
+	}
+	if !found {
+		return nil, configError("none of the test's rules are in %s", repo)
+	}
+	return rebased, nil
+}
+
+// runBisectTest runs the test, with other rules when given, and reports whether it passed
+func runBisectTest(testFile string, targetConfig *config.TargetConfig, rules []string) (bool, error) {
+	test, err := config.Load(testFile)
+	if err != nil {
+		return false, configError("failed to load test: %w", err)
+	}
+	if rules != nil {
+		test.Analysis.Rules = rules
+		test.Analysis.ParseGitURLs()
+	}
+	target, err := targets.NewTarget(targetConfig)
+	if err != nil {
+		return false, configError("failed to create target: %w", err)
+	}
+	// A candidate the analysis fails with is bad, a broken test is not
+	result, err := runLoadedTest(testFile, test, target, targetConfig)
+	if err != nil && (result == nil || result.FailureKind == FailureConfig) {
+		return false, err
+	}
+	return result.Status == "passed", nil
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestBisect(t *testing.T) {
+	candidates := []string{"v1", "v2", "v3", "v4", "v5", "v6", "v7"}
+	tests := []struct {
+		name     string
+		firstBad int
+		want     int
+		wantErr  bool
+	}{
+		{name: "regression in the middle", firstBad: 3, want: 3},
+		{name: "regression in the last candidate", firstBad: 6, want: 6},
+		{name: "regression right after the first", firstBad: 1, want: 1},
+		{name: "first candidate already bad", firstBad: 0, wantErr: true},
+		{name: "last candidate good", firstBad: 7, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tested []string
+			got, err := bisect(candidates, func(candidate string) (bool, error) {
+				tested = append(tested, candidate)
+				return slices.Index(candidates, candidate) < tt.firstBad, nil
+			})
+			if tt.wantErr {
+				if err == nil || exitCodeFor(err) != ExitCodeConfigError {
+					t.Errorf("expected a config error, got %d, %v", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("first bad = %s, want %s", candidates[got], candidates[tt.want])
+			}
+			// Both ends and at most log2(7) candidates in between
+			if len(tested) > 5 {
+				t.Errorf("tested too many candidates: %v", tested)
+			}
+		})
+	}
+
+	_, err := bisect(candidates, func(string) (bool, error) { return false, errors.New("download failed") })
+	if err == nil {
+		t.Error("expected errors to stop the bisection")
+	}
+}
+
+func TestRulesCommits(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return string(out)
+	}
+	git("init", "-q")
+	for _, version := range []string{"1", "2", "3", "4"} {
+		if err := os.WriteFile(filepath.Join(repo, "rules.yaml"), []byte("# "+version+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", ".")
+		git("commit", "-q", "-m", version)
+		git("tag", "v"+version)
+	}
+
+	commits, err := rulesCommits(repo, "v1", "v4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 4 {
+		t.Fatalf("expected v1 and the 3 commits after it, got %v", commits)
+	}
+	if first := git("rev-parse", "--short", "v1"); first != commits[0]+"\n" {
+		t.Errorf("expected v1 (%s) first, got %v", first, commits)
+	}
+
+	rules, err := rebaseRules([]string{filepath.Join(repo, "rules.yaml"), "https://github.com/example/rules.git"}, repo, "/tmp/worktree")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"/tmp/worktree/rules.yaml", "https://github.com/example/rules.git"}; !reflect.DeepEqual(rules, want) {
+		t.Errorf("rules = %v, want %v", rules, want)
+	}
+	if _, err := rebaseRules([]string{"/elsewhere/rules.yaml"}, repo, "/tmp/worktree"); err == nil {
+		t.Error("expected an error when no rules are in the repository")
+	}
+}
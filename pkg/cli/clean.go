@@ -62,7 +62,7 @@ func cleanAllOutputs(outputBaseDir string) error {
 		return fmt.Errorf("failed to remove directory: %w", err)
 	}
 
-	color.Green("✓ All outputs cleaned")
+	color.Green("%s All outputs cleaned", symbolPass)
 	return nil
 }
 
@@ -140,13 +140,13 @@ func cleanOldOutputs(outputBaseDir string) error {
 		dirPath := filepath.Join(outputBaseDir, dir)
 		err := os.RemoveAll(dirPath)
 		if err != nil {
-			color.Red("✗ Failed to delete %s: %v", dir, err)
+			color.Red("%s Failed to delete %s: %v", symbolFail, dir, err)
 			continue
 		}
 		deletedCount++
 	}
 
-	color.Green("\n✓ Cleaned up %d old run(s)", deletedCount)
+	color.Green("\n%s Cleaned up %d old run(s)", symbolPass, deletedCount)
 	return nil
 }
 
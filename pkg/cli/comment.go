@@ -0,0 +1,262 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/konveyor/test-harness/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	commentOutputFile string
+	commentPost       bool
+	commentRepo       string
+	commentPR         int
+	commentToken      string
+	commentAPIURL     string
+)
+
+const (
+	// commentMarker identifies koncur's comment so reruns update it instead of adding another
+	commentMarker = "<!-- koncur-results -->"
+	// maxCommentErrorsPerRuleSet is the number of validation errors shown for each ruleset
+	maxCommentErrorsPerRuleSet = 5
+	// maxCommentLength keeps the body safely below GitHub's 65536 character limit
+	maxCommentLength = 60000
+	// githubTimeout bounds each GitHub API request
+	githubTimeout = 30 * time.Second
+)
+
+// NewCommentCmd creates the comment command
+func NewCommentCmd() *cobra.Command {
+	commentCmd := &cobra.Command{
+		Use:   "comment <results-file>",
+		Short: "Render failed validations as a GitHub pull request comment",
+		Long: `Render the failed tests of a results file written by
+'koncur run --output-format json|yaml --output-file <file>' as a single Markdown
+comment, grouped by test and ruleset.
+
+With --post the comment is published on the pull request through the GitHub API.
+A previous koncur comment on the same pull request is updated instead of adding a new one.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log := util.GetLogger()
+
+			summary, err := LoadResults(args[0])
+			if err != nil {
+				return err
+			}
+			body := FormatPRComment(summary)
+
+			if commentOutputFile != "" {
+				if err := os.WriteFile(commentOutputFile, []byte(body), 0644); err != nil {
+					return fmt.Errorf("failed to write comment: %w", err)
+				}
+				log.Info("Wrote comment body", "file", commentOutputFile)
+			} else if !commentPost {
+				fmt.Print(body)
+			}
+
+			if !commentPost {
+				return nil
+			}
+			if commentRepo == "" || commentPR == 0 || commentToken == "" {
+				return configError("--post requires --repo, --pr and a token (--token or $GITHUB_TOKEN)")
+			}
+			client := &githubClient{apiURL: commentAPIURL, token: commentToken}
+			url, err := client.upsertComment(commentRepo, commentPR, body)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s Comment posted: %s\n", symbolPass, url)
+			return nil
+		},
+	}
+
+	apiURL := os.Getenv("GITHUB_API_URL")
+	if apiURL == "" {
+		apiURL = "https://api.github.com"
+	}
+	commentCmd.Flags().StringVarP(&commentOutputFile, "output", "o", "", "Write the comment body to this file instead of stdout")
+	commentCmd.Flags().BoolVar(&commentPost, "post", false, "Post the comment on the pull request")
+	commentCmd.Flags().StringVar(&commentRepo, "repo", os.Getenv("GITHUB_REPOSITORY"), "Repository as owner/name (default: $GITHUB_REPOSITORY)")
+	commentCmd.Flags().IntVar(&commentPR, "pr", 0, "Pull request number")
+	commentCmd.Flags().StringVar(&commentToken, "token", os.Getenv("GITHUB_TOKEN"), "GitHub token (default: $GITHUB_TOKEN)")
+	commentCmd.Flags().StringVar(&commentAPIURL, "api-url", apiURL, "GitHub API URL (default: $GITHUB_API_URL or https://api.github.com)")
+
+	return commentCmd
+}
+
+// FormatPRComment renders the failed tests of a run as a Markdown pull request comment.
+// Errors are capped per ruleset and whole tests are dropped once the body gets too long.
+func FormatPRComment(summary *TestSummary) string {
+	var sb strings.Builder
+	sb.WriteString(commentMarker + "\n")
+	sb.WriteString("## Koncur Test Results\n\n")
+	fmt.Fprintf(&sb, "**%d total** — ✅ %d passed, ❌ %d failed, ", summary.Total, summary.Passed, summary.Failed)
+	if summary.Flaky > 0 {
+		fmt.Fprintf(&sb, "⚠️ %d flaky, ", summary.Flaky)
+	}
+	fmt.Fprintf(&sb, "⏭️ %d skipped in %s\n\n", summary.Skipped, summary.Duration)
+
+	var failed []TestResult
+	for _, result := range summary.Tests {
+		if result.Status == "failed" || result.Status == "flaky" {
+			failed = append(failed, result)
+		}
+	}
+	if len(failed) == 0 {
+		sb.WriteString("All tests passed. 🎉\n")
+		return sb.String()
+	}
+
+	for i, result := range failed {
+		section := formatCommentTest(result)
+		if sb.Len()+len(section) > maxCommentLength {
+			fmt.Fprintf(&sb, "_…%d more failing test(s) omitted, see the full report._\n", len(failed)-i)
+			break
+		}
+		sb.WriteString(section)
+	}
+	return sb.String()
+}
+
+// formatCommentTest renders one failed test as a collapsible section
+func formatCommentTest(result TestResult) string {
+	var sb strings.Builder
+	label := "failed"
+	if result.Status == "flaky" {
+		label = fmt.Sprintf("flaky, passed %d of %d attempts", result.PassedAttempts, result.Attempts)
+	}
+	fmt.Fprintf(&sb, "<details>\n<summary><b>%s</b> (%s) — %s, %d validation error(s)</summary>\n\n",
+		result.Name, result.Target, label, len(result.ValidationErrors))
+
+	if result.ErrorMessage != "" {
+		fmt.Fprintf(&sb, "> %s\n\n", markdownCell(result.ErrorMessage))
+	}
+	for _, group := range GroupValidationErrors(result.ValidationErrors) {
+		fmt.Fprintf(&sb, "**%s** (%d)\n\n", group.RuleSet, len(group.Errors))
+		collapsed := CollapseValidationErrors(group.Errors)
+		for i, verr := range collapsed {
+			if i == maxCommentErrorsPerRuleSet {
+				fmt.Fprintf(&sb, "- …and %d more\n", len(collapsed)-maxCommentErrorsPerRuleSet)
+				break
+			}
+			fmt.Fprintf(&sb, "- `%s`: %s%s\n", verr.Path, markdownCell(verr.Message), verr.similarSuffix())
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("</details>\n\n")
+	return sb.String()
+}
+
+// githubClient is a minimal client for the GitHub issue comments API
+type githubClient struct {
+	apiURL string
+	token  string
+}
+
+type githubComment struct {
+	ID      int64  `json:"id,omitempty"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url,omitempty"`
+}
+
+// upsertComment updates koncur's previous comment on the pull request, or creates one
+func (c *githubClient) upsertComment(repo string, pr int, body string) (string, error) {
+	comments, err := c.listComments(repo, pr)
+	if err != nil {
+		return "", fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	var result githubComment
+	for _, existing := range comments {
+		if strings.HasPrefix(existing.Body, commentMarker) {
+			editURL := fmt.Sprintf("%s/repos/%s/issues/comments/%d", strings.TrimSuffix(c.apiURL, "/"), repo, existing.ID)
+			if err := c.do(http.MethodPatch, editURL, githubComment{Body: body}, &result); err != nil {
+				return "", fmt.Errorf("failed to update comment: %w", err)
+			}
+			return result.HTMLURL, nil
+		}
+	}
+
+	createURL := fmt.Sprintf("%s/repos/%s/issues/%d/comments", strings.TrimSuffix(c.apiURL, "/"), repo, pr)
+	if err := c.do(http.MethodPost, createURL, githubComment{Body: body}, &result); err != nil {
+		return "", fmt.Errorf("failed to create comment: %w", err)
+	}
+	return result.HTMLURL, nil
+}
+
+// listComments lists all comments of the pull request, following the pages of the list
+func (c *githubClient) listComments(repo string, pr int) ([]githubComment, error) {
+	var comments []githubComment
+	pageURL := fmt.Sprintf("%s/repos/%s/issues/%d/comments?per_page=100", strings.TrimSuffix(c.apiURL, "/"), repo, pr)
+	for pageURL != "" {
+		var page []githubComment
+		header, err := c.send(http.MethodGet, pageURL, nil, &page)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, page...)
+		pageURL = nextPageURL(header.Get("Link"))
+	}
+	return comments, nil
+}
+
+// nextPageURL returns the rel="next" URL of a Link header, or "" on the last page
+func nextPageURL(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		target, params, ok := strings.Cut(part, ";")
+		if ok && strings.Contains(params, `rel="next"`) {
+			return strings.Trim(strings.TrimSpace(target), "<>")
+		}
+	}
+	return ""
+}
+
+// do sends a JSON request and decodes the JSON response into out
+func (c *githubClient) do(method, url string, in, out any) error {
+	_, err := c.send(method, url, in, out)
+	return err
+}
+
+// send sends a JSON request, decodes the JSON response into out and returns its headers
+func (c *githubClient) send(method, url string, in, out any) (http.Header, error) {
+	var reqBody io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, url, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := &http.Client{Timeout: githubTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("GitHub API returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp.Header, json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/konveyor/test-harness/pkg/validator"
+)
+
+func TestFormatPRComment(t *testing.T) {
+	out := FormatPRComment(sampleSummary())
+
+	if !strings.HasPrefix(out, commentMarker) {
+		t.Error("Expected comment to start with the koncur marker")
+	}
+	for _, want := range []string{
+		"<summary><b>failing</b> (kantra) — failed, 3 validation error(s)</summary>",
+		"**azure/springboot** (2)",
+		"- `azure/springboot/violations/rule-1`: Did not find expected incident",
+		"**missing** (1)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected comment to contain %q\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "<b>passing</b>") {
+		t.Error("Passing tests should not be listed")
+	}
+}
+
+func TestFormatPRCommentTruncates(t *testing.T) {
+	summary := &TestSummary{Total: 400, Failed: 400, Duration: "1s"}
+	for i := 0; i < 400; i++ {
+		result := TestResult{Name: fmt.Sprintf("test-%d", i), Target: "kantra", Status: "failed"}
+		for j := 0; j < 20; j++ {
+			result.ValidationErrors = append(result.ValidationErrors, validator.ValidationError{
+				Path:    fmt.Sprintf("rs/violations/rule-%d", j),
+				Message: strings.Repeat("x", 50),
+			})
+		}
+		summary.Tests = append(summary.Tests, result)
+	}
+
+	out := FormatPRComment(summary)
+	if len(out) > maxCommentLength+200 {
+		t.Errorf("Comment length %d exceeds limit", len(out))
+	}
+	if !strings.Contains(out, "more failing test(s) omitted") {
+		t.Error("Expected omitted tests note")
+	}
+	if !strings.Contains(out, "…and 15 more") {
+		t.Error("Expected errors per ruleset to be capped")
+	}
+}
+
+func TestUpsertComment(t *testing.T) {
+	var created, updated string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in githubComment
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/o/r/issues/7/comments" && r.URL.Query().Get("page") == "":
+			// The earlier comment is on the second page
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/o/r/issues/7/comments?page=2>; rel="next", <%[1]s/repos/o/r/issues/7/comments?page=2>; rel="last"`, server.URL))
+			json.NewEncoder(w).Encode([]githubComment{{ID: 1, Body: "lgtm"}})
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/o/r/issues/7/comments":
+			json.NewEncoder(w).Encode([]githubComment{{ID: 2, Body: commentMarker + "\nold"}})
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/o/r/issues/comments/2":
+			json.NewDecoder(r.Body).Decode(&in)
+			updated = in.Body
+			json.NewEncoder(w).Encode(githubComment{ID: 2, HTMLURL: "https://example.com/2"})
+		case r.Method == http.MethodPost:
+			json.NewDecoder(r.Body).Decode(&in)
+			created = in.Body
+			json.NewEncoder(w).Encode(githubComment{ID: 3})
+		default:
+			http.Error(w, "unexpected request", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &githubClient{apiURL: server.URL, token: "t"}
+	url, err := client.upsertComment("o/r", 7, commentMarker+"\nnew")
+	if err != nil {
+		t.Fatalf("upsertComment returned error: %v", err)
+	}
+	if url != "https://example.com/2" || updated != commentMarker+"\nnew" || created != "" {
+		t.Errorf("Expected existing comment to be updated, got url=%q updated=%q created=%q", url, updated, created)
+	}
+}
+
+func TestCommentPostRequiresRepo(t *testing.T) {
+	out, err := FormatResults(sampleSummary(), OutputFormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resultsFile := filepath.Join(t.TempDir(), "results.json")
+	if err := os.WriteFile(resultsFile, []byte(out), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := NewCommentCmd()
+	cmd.SetArgs([]string{resultsFile, "--post", "--repo", "", "--pr", "0", "-o", filepath.Join(t.TempDir(), "comment.md")})
+	err = cmd.Execute()
+	if err == nil || exitCodeFor(err) != ExitCodeConfigError {
+		t.Errorf("expected a config error, got %v (exit code %d)", err, exitCodeFor(err))
+	}
+}
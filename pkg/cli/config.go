@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
 	"github.com/konveyor/analyzer-lsp/provider"
@@ -15,9 +17,31 @@ import (
 )
 
 var (
-	configOutputFile string
-	configType       string
+	configOutputFile     string
+	configType           string
 	configNonInteractive bool
+
+	// Answers to the target prompts
+	configBinaryPath    string
+	configMavenSettings string
+	configURL           string
+	configAuth          string
+	configToken         string
+	configUsername      string
+	configPassword      string
+	configBrowser       string
+	configHeadless      bool
+	configHost          string
+	configPort          int
+	configExtensionID   string
+	configWorkspaceDir  string
+
+	// Answers to the test prompts
+	configName          string
+	configDescription   string
+	configApplication   string
+	configLabelSelector string
+	configMode          string
 )
 
 // NewConfigCmd creates the config command with subcommands
@@ -31,6 +55,7 @@ func NewConfigCmd() *cobra.Command {
 	// Add subcommands
 	configCmd.AddCommand(NewConfigTargetCmd())
 	configCmd.AddCommand(NewConfigTestCmd())
+	configCmd.AddCommand(NewConfigProfilesCmd())
 
 	return configCmd
 }
@@ -47,12 +72,33 @@ Supported target types:
   - tackle-hub: Tackle Hub API execution
   - tackle-ui: Tackle UI browser automation (not implemented)
   - kai-rpc: Kai analyzer RPC (not implemented)
-  - vscode: VSCode extension execution (not implemented)`,
+  - vscode: VSCode extension execution (not implemented)
+
+Every prompt can be answered with a flag; answered prompts are not asked. With
+--non-interactive nothing is asked: unanswered prompts take their default, e.g.
+
+  koncur config target --non-interactive -t tackle-hub \
+    --url https://hub.example.com --token env:HUB_TOKEN`,
 		RunE: runConfigTarget,
 	}
 
 	cmd.Flags().StringVarP(&configOutputFile, "output", "o", "", "Output file path (default: .koncur/config/target-<type>.yaml)")
 	cmd.Flags().StringVarP(&configType, "type", "t", "", "Target type (kantra, tackle-hub, tackle-ui, kai-rpc, vscode)")
+	cmd.Flags().BoolVar(&configNonInteractive, "non-interactive", false, "Do not prompt, use flags and defaults")
+	cmd.Flags().StringVar(&configBinaryPath, "binary-path", "", "Kantra or VSCode binary path (kantra, vscode)")
+	cmd.Flags().StringVar(&configMavenSettings, "maven-settings", "", "Maven settings.xml path (kantra, tackle-hub)")
+	cmd.Flags().StringVar(&configURL, "url", "", "Hub or UI URL (tackle-hub, tackle-ui)")
+	cmd.Flags().StringVar(&configURL, "hub-url", "", "Alias of --url")
+	cmd.Flags().StringVar(&configAuth, "auth", "", "Authentication method: token, password or none (tackle-hub, default: from the credentials given)")
+	cmd.Flags().StringVar(&configToken, "token", "", "API token, or an env:VAR / file:path reference (tackle-hub)")
+	cmd.Flags().StringVar(&configUsername, "username", "", "Username (tackle-hub, tackle-ui)")
+	cmd.Flags().StringVar(&configPassword, "password", "", "Password, or an env:VAR / file:path reference (tackle-hub, tackle-ui)")
+	cmd.Flags().StringVar(&configBrowser, "browser", "", "Browser: chrome or firefox (tackle-ui)")
+	cmd.Flags().BoolVar(&configHeadless, "headless", true, "Run the browser headless (tackle-ui)")
+	cmd.Flags().StringVar(&configHost, "host", "", "Kai RPC host (kai-rpc)")
+	cmd.Flags().IntVar(&configPort, "port", 0, "Kai RPC port (kai-rpc)")
+	cmd.Flags().StringVar(&configExtensionID, "extension-id", "", "Extension ID (vscode)")
+	cmd.Flags().StringVar(&configWorkspaceDir, "workspace-dir", "", "Workspace directory (vscode)")
 
 	return cmd
 }
@@ -67,21 +113,76 @@ func NewConfigTestCmd() *cobra.Command {
 A test definition specifies:
   - The application to analyze
   - Analysis parameters (label selector, mode)
-  - Expected results (exit code, output)`,
+  - Expected results (exit code, output)
+
+Every prompt can be answered with a flag; answered prompts are not asked. With
+--non-interactive nothing is asked, and --name and --application are required:
+
+  koncur config test --non-interactive --name daytrader \
+    --application https://github.com/example/daytrader.git#main --mode full`,
 		RunE: runConfigTest,
 	}
 
 	cmd.Flags().StringVarP(&configOutputFile, "output", "o", "", "Output file path (default: ./test.yaml)")
+	cmd.Flags().BoolVar(&configNonInteractive, "non-interactive", false, "Do not prompt, use flags and defaults")
+	cmd.Flags().StringVar(&configName, "name", "", "Test name")
+	cmd.Flags().StringVar(&configDescription, "description", "", "Test description")
+	cmd.Flags().StringVar(&configApplication, "application", "", "Application path or git URL")
+	cmd.Flags().StringVar(&configLabelSelector, "label-selector", "", "Label selector")
+	cmd.Flags().StringVar(&configMode, "mode", "", "Analysis mode: source-only, full or discovery (default: source-only)")
 
 	return cmd
 }
 
+// promptText returns the value of a flag if it was given, its default in
+// non-interactive mode, or the answer to the prompt. Optional prompts can be
+// interrupted; required ones have no default and must be given non-interactively.
+func promptText(cmd *cobra.Command, flag, value string, prompt promptui.Prompt, required bool) (string, error) {
+	if cmd.Flags().Changed(flag) {
+		return value, nil
+	}
+	if configNonInteractive {
+		if required && prompt.Default == "" {
+			return "", configError("--%s is required with --non-interactive", flag)
+		}
+		return prompt.Default, nil
+	}
+	answer, err := prompt.Run()
+	if err != nil && (required || err != promptui.ErrInterrupt) {
+		return "", err
+	}
+	return answer, nil
+}
+
+// promptSelect returns the value of a flag if it was given, the first item in
+// non-interactive mode, or the selected item. Flag values must be one of the items.
+func promptSelect(cmd *cobra.Command, flag, value, label string, items []string) (string, error) {
+	if cmd.Flags().Changed(flag) {
+		if !slices.Contains(items, value) {
+			return "", configError("invalid --%s %q: must be one of %s", flag, value, strings.Join(items, ", "))
+		}
+		return value, nil
+	}
+	if configNonInteractive {
+		return items[0], nil
+	}
+	prompt := promptui.Select{
+		Label: label,
+		Items: items,
+	}
+	_, result, err := prompt.Run()
+	return result, err
+}
+
 func runConfigTarget(cmd *cobra.Command, args []string) error {
 	log := util.GetLogger()
 
 	// Prompt for target type if not provided
 	targetType := configType
 	if targetType == "" {
+		if configNonInteractive {
+			return configError("--type is required with --non-interactive")
+		}
 		prompt := promptui.Select{
 			Label: "Select target type",
 			Items: []string{"kantra", "tackle-hub", "tackle-ui", "kai-rpc", "vscode"},
@@ -99,17 +200,17 @@ func runConfigTarget(cmd *cobra.Command, args []string) error {
 
 	switch targetType {
 	case "kantra":
-		targetConfig, err = createKantraConfig()
+		targetConfig, err = createKantraConfig(cmd)
 	case "tackle-hub":
-		targetConfig, err = createTackleHubConfig()
+		targetConfig, err = createTackleHubConfig(cmd)
 	case "tackle-ui":
-		targetConfig, err = createTackleUIConfig()
+		targetConfig, err = createTackleUIConfig(cmd)
 	case "kai-rpc":
-		targetConfig, err = createKaiRPCConfig()
+		targetConfig, err = createKaiRPCConfig(cmd)
 	case "vscode":
-		targetConfig, err = createVSCodeConfig()
+		targetConfig, err = createVSCodeConfig(cmd)
 	default:
-		return fmt.Errorf("unsupported target type: %s", targetType)
+		return configError("unsupported target type: %s", targetType)
 	}
 
 	if err != nil {
@@ -134,13 +235,13 @@ func runConfigTarget(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	// Write to file
-	if err := os.WriteFile(outputFile, data, 0644); err != nil {
+	// Write to file - owner only, since it may hold inline credentials
+	if err := os.WriteFile(outputFile, data, 0600); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
 	log.Info("Target configuration created", "file", outputFile, "type", targetType)
-	fmt.Printf("✓ Created target configuration: %s\n", outputFile)
+	fmt.Printf("%s Created target configuration: %s\n", symbolPass, outputFile)
 
 	return nil
 }
@@ -149,7 +250,7 @@ func runConfigTest(cmd *cobra.Command, args []string) error {
 	log := util.GetLogger()
 
 	// Prompt for test details
-	testConfig, err := createTestConfig()
+	testConfig, err := createTestConfig(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to create test config: %w", err)
 	}
@@ -180,22 +281,21 @@ func runConfigTest(cmd *cobra.Command, args []string) error {
 	}
 
 	log.Info("Test configuration created", "file", outputFile)
-	fmt.Printf("✓ Created test configuration: %s\n", outputFile)
+	fmt.Printf("%s Created test configuration: %s\n", symbolPass, outputFile)
 
 	return nil
 }
 
 // createKantraConfig creates a Kantra target configuration interactively
-func createKantraConfig() (*config.TargetConfig, error) {
+func createKantraConfig(cmd *cobra.Command) (*config.TargetConfig, error) {
 	kantraConfig := &config.KantraConfig{}
 
 	// Prompt for binary path (optional)
-	prompt := promptui.Prompt{
+	binaryPath, err := promptText(cmd, "binary-path", configBinaryPath, promptui.Prompt{
 		Label:   "Kantra binary path (optional, press Enter to use PATH)",
 		Default: "",
-	}
-	binaryPath, err := prompt.Run()
-	if err != nil && err != promptui.ErrInterrupt {
+	}, false)
+	if err != nil {
 		return nil, err
 	}
 	if binaryPath != "" {
@@ -203,12 +303,11 @@ func createKantraConfig() (*config.TargetConfig, error) {
 	}
 
 	// Prompt for Maven settings (optional)
-	prompt = promptui.Prompt{
+	mavenSettings, err := promptText(cmd, "maven-settings", configMavenSettings, promptui.Prompt{
 		Label:   "Maven settings.xml path (optional, press Enter to skip)",
 		Default: "",
-	}
-	mavenSettings, err := prompt.Run()
-	if err != nil && err != promptui.ErrInterrupt {
+	}, false)
+	if err != nil {
 		return nil, err
 	}
 	if mavenSettings != "" {
@@ -221,72 +320,99 @@ func createKantraConfig() (*config.TargetConfig, error) {
 	}, nil
 }
 
+// hubAuthMethods are the authentication methods of the Hub, by --auth value
+var hubAuthMethods = map[string]string{
+	"token":    "Token",
+	"password": "Username/Password",
+	"none":     "None",
+}
+
 // createTackleHubConfig creates a Tackle Hub target configuration interactively
-func createTackleHubConfig() (*config.TargetConfig, error) {
+func createTackleHubConfig(cmd *cobra.Command) (*config.TargetConfig, error) {
 	tackleHubConfig := &config.TackleHubConfig{}
 
 	// Prompt for URL (required)
-	prompt := promptui.Prompt{
+	url, err := promptText(cmd, urlFlag(cmd), configURL, promptui.Prompt{
 		Label:   "Tackle Hub URL",
 		Default: "http://localhost:8081",
-	}
-	url, err := prompt.Run()
+	}, true)
 	if err != nil {
 		return nil, err
 	}
 	tackleHubConfig.URL = url
 
-	// Prompt for authentication method
-	authPrompt := promptui.Select{
-		Label: "Authentication method",
-		Items: []string{"Token", "Username/Password", "None"},
-	}
-	_, authMethod, err := authPrompt.Run()
-	if err != nil {
-		return nil, err
+	// Prompt for authentication method, unless the credentials given imply it
+	var authMethod string
+	switch {
+	case cmd.Flags().Changed("auth"):
+		method, ok := hubAuthMethods[configAuth]
+		if !ok {
+			return nil, configError("invalid --auth %q: must be token, password or none", configAuth)
+		}
+		authMethod = method
+	case cmd.Flags().Changed("token"):
+		authMethod = hubAuthMethods["token"]
+	case cmd.Flags().Changed("username") || cmd.Flags().Changed("password"):
+		authMethod = hubAuthMethods["password"]
+	case configNonInteractive:
+		authMethod = hubAuthMethods["none"]
+	default:
+		authPrompt := promptui.Select{
+			Label: "Authentication method",
+			Items: []string{"Token", "Username/Password", "None"},
+		}
+		_, authMethod, err = authPrompt.Run()
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	switch authMethod {
 	case "Token":
-		prompt = promptui.Prompt{
-			Label: "API Token",
+		token, err := promptText(cmd, "token", configToken, promptui.Prompt{
+			Label: "API Token (or env:VAR / file:path reference)",
 			Mask:  '*',
-		}
-		token, err := prompt.Run()
+		}, true)
 		if err != nil {
 			return nil, err
 		}
-		tackleHubConfig.Token = token
+		// Keep references out of the inline field so the token never lands in the file
+		if config.IsSecretRef(token) {
+			tackleHubConfig.TokenFrom = token
+		} else {
+			tackleHubConfig.Token = token
+		}
 
 	case "Username/Password":
-		prompt = promptui.Prompt{
+		username, err := promptText(cmd, "username", configUsername, promptui.Prompt{
 			Label:   "Username",
 			Default: "admin",
-		}
-		username, err := prompt.Run()
+		}, true)
 		if err != nil {
 			return nil, err
 		}
 		tackleHubConfig.Username = username
 
-		prompt = promptui.Prompt{
-			Label: "Password",
+		password, err := promptText(cmd, "password", configPassword, promptui.Prompt{
+			Label: "Password (or env:VAR / file:path reference)",
 			Mask:  '*',
-		}
-		password, err := prompt.Run()
+		}, true)
 		if err != nil {
 			return nil, err
 		}
-		tackleHubConfig.Password = password
+		if config.IsSecretRef(password) {
+			tackleHubConfig.PasswordFrom = password
+		} else {
+			tackleHubConfig.Password = password
+		}
 	}
 
 	// Prompt for Maven settings (optional)
-	prompt = promptui.Prompt{
+	mavenSettings, err := promptText(cmd, "maven-settings", configMavenSettings, promptui.Prompt{
 		Label:   "Maven settings.xml path (optional, press Enter to skip)",
 		Default: "",
-	}
-	mavenSettings, err := prompt.Run()
-	if err != nil && err != promptui.ErrInterrupt {
+	}, false)
+	if err != nil {
 		return nil, err
 	}
 	if mavenSettings != "" {
@@ -299,61 +425,66 @@ func createTackleHubConfig() (*config.TargetConfig, error) {
 	}, nil
 }
 
+// urlFlag returns the name the URL was given with, --url or its alias --hub-url
+func urlFlag(cmd *cobra.Command) string {
+	if cmd.Flags().Changed("hub-url") {
+		return "hub-url"
+	}
+	return "url"
+}
+
 // createTackleUIConfig creates a Tackle UI target configuration interactively
-func createTackleUIConfig() (*config.TargetConfig, error) {
-	fmt.Println("⚠ Warning: Tackle UI target is not yet implemented")
+func createTackleUIConfig(cmd *cobra.Command) (*config.TargetConfig, error) {
+	fmt.Printf("%s Warning: Tackle UI target is not yet implemented\n", symbolWarn)
 
 	tackleUIConfig := &config.TackleUIConfig{}
 
-	prompt := promptui.Prompt{
+	url, err := promptText(cmd, urlFlag(cmd), configURL, promptui.Prompt{
 		Label:   "Tackle UI URL",
 		Default: "http://localhost:8080",
-	}
-	url, err := prompt.Run()
+	}, true)
 	if err != nil {
 		return nil, err
 	}
 	tackleUIConfig.URL = url
 
-	prompt = promptui.Prompt{
+	username, err := promptText(cmd, "username", configUsername, promptui.Prompt{
 		Label:   "Username",
 		Default: "admin",
-	}
-	username, err := prompt.Run()
+	}, true)
 	if err != nil {
 		return nil, err
 	}
 	tackleUIConfig.Username = username
 
-	prompt = promptui.Prompt{
-		Label: "Password",
+	password, err := promptText(cmd, "password", configPassword, promptui.Prompt{
+		Label: "Password (or env:VAR / file:path reference)",
 		Mask:  '*',
-	}
-	password, err := prompt.Run()
+	}, true)
 	if err != nil {
 		return nil, err
 	}
-	tackleUIConfig.Password = password
-
-	browserPrompt := promptui.Select{
-		Label: "Browser",
-		Items: []string{"chrome", "firefox"},
+	if config.IsSecretRef(password) {
+		tackleUIConfig.PasswordFrom = password
+	} else {
+		tackleUIConfig.Password = password
 	}
-	_, browser, err := browserPrompt.Run()
+
+	browser, err := promptSelect(cmd, "browser", configBrowser, "Browser", []string{"chrome", "firefox"})
 	if err != nil {
 		return nil, err
 	}
 	tackleUIConfig.Browser = browser
 
-	headlessPrompt := promptui.Select{
-		Label: "Headless mode",
-		Items: []string{"true", "false"},
-	}
-	_, headless, err := headlessPrompt.Run()
-	if err != nil {
-		return nil, err
+	headless := configHeadless
+	if !cmd.Flags().Changed("headless") && !configNonInteractive {
+		answer, err := promptSelect(cmd, "headless", "", "Headless mode", []string{"true", "false"})
+		if err != nil {
+			return nil, err
+		}
+		headless = answer == "true"
 	}
-	tackleUIConfig.Headless = headless == "true"
+	tackleUIConfig.Headless = headless
 
 	return &config.TargetConfig{
 		Type:     "tackle-ui",
@@ -362,31 +493,31 @@ func createTackleUIConfig() (*config.TargetConfig, error) {
 }
 
 // createKaiRPCConfig creates a Kai RPC target configuration interactively
-func createKaiRPCConfig() (*config.TargetConfig, error) {
-	fmt.Println("⚠ Warning: Kai RPC target is not yet implemented")
+func createKaiRPCConfig(cmd *cobra.Command) (*config.TargetConfig, error) {
+	fmt.Printf("%s Warning: Kai RPC target is not yet implemented\n", symbolWarn)
 
 	kaiRPCConfig := &config.KaiRPCConfig{}
 
-	prompt := promptui.Prompt{
+	host, err := promptText(cmd, "host", configHost, promptui.Prompt{
 		Label:   "Kai RPC Host",
 		Default: "localhost",
-	}
-	host, err := prompt.Run()
+	}, true)
 	if err != nil {
 		return nil, err
 	}
 	kaiRPCConfig.Host = host
 
-	prompt = promptui.Prompt{
-		Label:   "Kai RPC Port",
-		Default: "8080",
-	}
-	portStr, err := prompt.Run()
-	if err != nil {
-		return nil, err
+	port := configPort
+	if !cmd.Flags().Changed("port") {
+		portStr, err := promptText(cmd, "port", "", promptui.Prompt{
+			Label:   "Kai RPC Port",
+			Default: "8080",
+		}, true)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Sscanf(portStr, "%d", &port)
 	}
-	var port int
-	fmt.Sscanf(portStr, "%d", &port)
 	kaiRPCConfig.Port = port
 
 	return &config.TargetConfig{
@@ -396,39 +527,36 @@ func createKaiRPCConfig() (*config.TargetConfig, error) {
 }
 
 // createVSCodeConfig creates a VSCode target configuration interactively
-func createVSCodeConfig() (*config.TargetConfig, error) {
-	fmt.Println("⚠ Warning: VSCode target is not yet implemented")
+func createVSCodeConfig(cmd *cobra.Command) (*config.TargetConfig, error) {
+	fmt.Printf("%s Warning: VSCode target is not yet implemented\n", symbolWarn)
 
 	vscodeConfig := &config.VSCodeConfig{}
 
-	prompt := promptui.Prompt{
+	binaryPath, err := promptText(cmd, "binary-path", configBinaryPath, promptui.Prompt{
 		Label:   "VSCode binary path (optional, press Enter to use PATH)",
 		Default: "",
-	}
-	binaryPath, err := prompt.Run()
-	if err != nil && err != promptui.ErrInterrupt {
+	}, false)
+	if err != nil {
 		return nil, err
 	}
 	if binaryPath != "" {
 		vscodeConfig.BinaryPath = binaryPath
 	}
 
-	prompt = promptui.Prompt{
+	extensionID, err := promptText(cmd, "extension-id", configExtensionID, promptui.Prompt{
 		Label:   "Extension ID",
 		Default: "konveyor.konveyor-analyzer",
-	}
-	extensionID, err := prompt.Run()
+	}, true)
 	if err != nil {
 		return nil, err
 	}
 	vscodeConfig.ExtensionID = extensionID
 
-	prompt = promptui.Prompt{
+	workspaceDir, err := promptText(cmd, "workspace-dir", configWorkspaceDir, promptui.Prompt{
 		Label:   "Workspace directory (optional, press Enter to skip)",
 		Default: "",
-	}
-	workspaceDir, err := prompt.Run()
-	if err != nil && err != promptui.ErrInterrupt {
+	}, false)
+	if err != nil {
 		return nil, err
 	}
 	if workspaceDir != "" {
@@ -442,26 +570,24 @@ func createVSCodeConfig() (*config.TargetConfig, error) {
 }
 
 // createTestConfig creates a test configuration interactively
-func createTestConfig() (*config.TestDefinition, error) {
+func createTestConfig(cmd *cobra.Command) (*config.TestDefinition, error) {
 	testConfig := &config.TestDefinition{}
 
 	// Prompt for test name
-	prompt := promptui.Prompt{
+	name, err := promptText(cmd, "name", configName, promptui.Prompt{
 		Label: "Test name",
-	}
-	name, err := prompt.Run()
+	}, true)
 	if err != nil {
 		return nil, err
 	}
 	testConfig.Name = name
 
 	// Prompt for description (optional)
-	prompt = promptui.Prompt{
+	description, err := promptText(cmd, "description", configDescription, promptui.Prompt{
 		Label:   "Test description (optional, press Enter to skip)",
 		Default: "",
-	}
-	description, err := prompt.Run()
-	if err != nil && err != promptui.ErrInterrupt {
+	}, false)
+	if err != nil {
 		return nil, err
 	}
 	testConfig.Description = description
@@ -470,32 +596,26 @@ func createTestConfig() (*config.TestDefinition, error) {
 	testConfig.Analysis = config.AnalysisConfig{}
 
 	// Prompt for application path
-	prompt = promptui.Prompt{
+	application, err := promptText(cmd, "application", configApplication, promptui.Prompt{
 		Label: "Application path or git URL",
-	}
-	application, err := prompt.Run()
+	}, true)
 	if err != nil {
 		return nil, err
 	}
 	testConfig.Analysis.Application = application
 
 	// Prompt for label selector (optional)
-	prompt = promptui.Prompt{
+	labelSelector, err := promptText(cmd, "label-selector", configLabelSelector, promptui.Prompt{
 		Label:   "Label selector (optional, press Enter to skip)",
 		Default: "",
-	}
-	labelSelector, err := prompt.Run()
-	if err != nil && err != promptui.ErrInterrupt {
+	}, false)
+	if err != nil {
 		return nil, err
 	}
 	testConfig.Analysis.LabelSelector = labelSelector
 
 	// Prompt for analysis mode
-	modePrompt := promptui.Select{
-		Label: "Analysis mode",
-		Items: []string{"source-only", "full"},
-	}
-	_, mode, err := modePrompt.Run()
+	mode, err := promptSelect(cmd, "mode", configMode, "Analysis mode", []string{"source-only", "full", "discovery"})
 	if err != nil {
 		return nil, err
 	}
@@ -503,13 +623,12 @@ func createTestConfig() (*config.TestDefinition, error) {
 
 	// Expect section
 	testConfig.Expect = config.ExpectConfig{
-		ExitCode: 0,
 		Output: config.ExpectedOutput{
 			Result: []konveyor.RuleSet{},
 		},
 	}
 
-	fmt.Println("\n✓ Test configuration created")
+	fmt.Printf("\n%s Test configuration created\n", symbolPass)
 	fmt.Println("  Note: You'll need to run 'koncur generate' to populate expected outputs")
 
 	return testConfig, nil
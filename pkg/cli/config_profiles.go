@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// targetTypes are the supported target types
+var targetTypes = []string{"kantra", "tackle-hub", "tackle-ui", "kai-rpc", "vscode"}
+
+// NewConfigProfilesCmd creates the config profiles command
+func NewConfigProfilesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "profiles",
+		Short: "List the tolerance profiles and the differences they accept",
+		Long: `List the named tolerance profiles that can be selected with 'validation.profile'
+in a test or target config, the targets using them by default and the validation
+settings they apply beneath the test's own settings.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, profile := range config.ToleranceProfiles() {
+				var defaults []string
+				for _, target := range targetTypes {
+					if config.DefaultProfile(target) == profile.Name {
+						defaults = append(defaults, target)
+					}
+				}
+				fmt.Printf("%s\n  %s\n  Default for: %s\n", profile.Name, profile.Description, strings.Join(defaults, ", "))
+				if err := printProfileSettings("Tolerances", profile.Tolerances); err != nil {
+					return err
+				}
+				if profile.StrictTolerances != nil {
+					if err := printProfileSettings("Strict tolerances", *profile.StrictTolerances); err != nil {
+						return err
+					}
+				}
+				fmt.Println()
+			}
+			return nil
+		},
+	}
+}
+
+// printProfileSettings prints the validation settings of a profile as indented YAML
+func printProfileSettings(title string, settings config.ValidationConfig) error {
+	var buf strings.Builder
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(settings); err != nil {
+		return fmt.Errorf("failed to marshal profile: %w", err)
+	}
+	data := strings.TrimRight(buf.String(), "\n")
+	if data == "{}" {
+		return nil
+	}
+	fmt.Printf("  %s:\n", title)
+	for _, line := range strings.Split(data, "\n") {
+		fmt.Printf("    %s\n", line)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/konveyor/analyzer-lsp/provider"
+	"github.com/konveyor/test-harness/pkg/config"
+)
+
+func TestConfigNonInteractive(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HUB_TOKEN", "config-test-hub-token")
+
+	tests := []struct {
+		name     string
+		args     []string
+		exitCode int
+		check    func(t *testing.T, output string)
+	}{
+		{
+			name: "hub target with token reference",
+			args: []string{"target", "--non-interactive", "-t", "tackle-hub", "--hub-url", "https://hub.example.com", "--token", "env:HUB_TOKEN"},
+			check: func(t *testing.T, output string) {
+				cfg, err := config.LoadTargetConfig(output)
+				if err != nil {
+					t.Fatal(err)
+				}
+				hub := cfg.TackleHub
+				if hub.URL != "https://hub.example.com" || hub.TokenFrom != "env:HUB_TOKEN" || hub.Token != "config-test-hub-token" {
+					t.Errorf("unexpected hub config %+v", hub)
+				}
+			},
+		},
+		{
+			name: "kantra target with defaults",
+			args: []string{"target", "--non-interactive", "-t", "kantra", "--maven-settings", "settings.xml"},
+			check: func(t *testing.T, output string) {
+				cfg, err := config.LoadTargetConfig(output)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if cfg.Kantra.BinaryPath != "" || cfg.Kantra.MavenSettings != "settings.xml" {
+					t.Errorf("unexpected kantra config %+v", cfg.Kantra)
+				}
+			},
+		},
+		{
+			name:     "target type required",
+			args:     []string{"target", "--non-interactive"},
+			exitCode: ExitCodeConfigError,
+		},
+		{
+			name:     "hub password required",
+			args:     []string{"target", "--non-interactive", "-t", "tackle-hub", "--username", "admin"},
+			exitCode: ExitCodeConfigError,
+		},
+		{
+			name: "test",
+			args: []string{"test", "--non-interactive", "--name", "daytrader", "--application", "https://github.com/example/daytrader.git", "--mode", "full"},
+			check: func(t *testing.T, output string) {
+				test, err := config.Load(output)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if test.Name != "daytrader" || test.Analysis.AnalysisMode != provider.FullAnalysisMode || test.Analysis.LabelSelector != "" {
+					t.Errorf("unexpected test %+v", test)
+				}
+			},
+		},
+		{
+			name:     "test application required",
+			args:     []string{"test", "--non-interactive", "--name", "daytrader"},
+			exitCode: ExitCodeConfigError,
+		},
+		{
+			name:     "invalid analysis mode",
+			args:     []string{"test", "--non-interactive", "--name", "daytrader", "--application", "app", "--mode", "binary"},
+			exitCode: ExitCodeConfigError,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := filepath.Join(dir, filepath.Base(t.Name())+".yaml")
+			cmd := NewConfigCmd()
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+			cmd.SetArgs(append(tt.args, "-o", output))
+			err := cmd.Execute()
+			if tt.exitCode != 0 {
+				if err == nil || exitCodeFor(err) != tt.exitCode {
+					t.Fatalf("case %d: expected exit code %d, got error %v", i, tt.exitCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, output)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/konveyor/test-harness/pkg/diff"
+	"github.com/konveyor/test-harness/pkg/validator"
+)
+
+// Console status symbols, replaced with ASCII when output is not decorated
+var (
+	symbolPass   = "✓"
+	symbolFail   = "✗"
+	symbolSkip   = "⊘"
+	symbolWarn   = "⚠"
+	symbolRun    = "⟳"
+	symbolDryRun = "⇢"
+
+	// Progress bar cells
+	progressDone = "█"
+	progressTodo = "░"
+)
+
+// configureConsole disables colors and decorative symbols when --no-color or
+// NO_COLOR is set, or when stdout is not a terminal (e.g. CI logs, pipes)
+func configureConsole(noColor bool) {
+	if !noColor && os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout) {
+		return
+	}
+	color.NoColor = true
+	symbolPass = "+"
+	symbolFail = "x"
+	symbolSkip = "-"
+	symbolWarn = "!"
+	symbolRun = "*"
+	symbolDryRun = ">"
+	progressDone = "#"
+	progressTodo = "-"
+}
+
+// isTerminal reports whether f is a character device such as a TTY
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// printValidationDiffs prints unified diffs with removed lines in red and added lines in green
+func printValidationDiffs(diffs []validator.ValidationDiff) {
+	if len(diffs) == 0 {
+		return
+	}
+	fmt.Printf("    Diffs (%d):\n\n", len(diffs))
+	for _, d := range diffs {
+		printUnifiedDiff(d.Diff)
+		fmt.Println()
+	}
+}
+
+// printUnifiedDiff prints a unified diff, indented, with removed lines in red and added lines in green
+func printUnifiedDiff(text string) {
+	for _, line := range diff.SplitLines(text) {
+		switch {
+		case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+			color.New(color.Bold).Printf("    %s\n", line)
+		case strings.HasPrefix(line, "@@"):
+			color.Cyan("    %s", line)
+		case strings.HasPrefix(line, "-"):
+			color.Red("    %s", line)
+		case strings.HasPrefix(line, "+"):
+			color.Green("    %s", line)
+		default:
+			fmt.Printf("    %s\n", line)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestConfigureConsoleNoColor(t *testing.T) {
+	oldNoColor, oldPass, oldFail := color.NoColor, symbolPass, symbolFail
+	defer func() { color.NoColor, symbolPass, symbolFail = oldNoColor, oldPass, oldFail }()
+
+	color.NoColor = false
+	configureConsole(true)
+
+	if !color.NoColor {
+		t.Error("Expected colors to be disabled")
+	}
+	if symbolPass != "+" || symbolFail != "x" {
+		t.Errorf("Expected ASCII symbols, got %q and %q", symbolPass, symbolFail)
+	}
+}
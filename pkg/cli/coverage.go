@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/coverage"
+	"github.com/konveyor/test-harness/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	coverageRules       []string
+	coverageResultsFile string
+	coverageJSON        bool
+	coverageAll         bool
+)
+
+// NewCoverageCmd creates the coverage command
+func NewCoverageCmd() *cobra.Command {
+	coverageCmd := &cobra.Command{
+		Use:   "coverage [test-directory]",
+		Short: "Report which rules are not exercised by any test",
+		Long: `Cross-reference the rules declared in the configured rulesets with the rules
+that appear in violations, insights, unmatched or skipped across all tests, and list
+the rules that no test exercises.
+
+Rulesets are read from --rules paths and from local rule paths referenced by the
+tests. By default the tests' expected outputs are used; pass --results with a results
+file from 'koncur run --output-format json|yaml' to use the actual outputs of a run.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log := util.GetLogger()
+
+			testDir := defaultTestDir()
+			if len(args) == 1 {
+				testDir = args[0]
+			}
+
+			testFiles, err := findTestFiles(testDir)
+			if err != nil {
+				return fmt.Errorf("failed to find test files: %w", err)
+			}
+
+			rulePaths := append([]string{}, coverageRules...)
+			var outputs []coverage.TestOutput
+			for _, testFile := range testFiles {
+				test, err := config.Load(testFile)
+				if err != nil {
+					log.Info("Skipping test that failed to load", "file", testFile, "error", err.Error())
+					continue
+				}
+				rulePaths = append(rulePaths, localRulePaths(test)...)
+				if coverageResultsFile == "" {
+					outputs = append(outputs, coverage.TestOutput{Test: test.Name, RuleSets: test.Expect.Output.Result})
+				}
+			}
+
+			if coverageResultsFile != "" {
+				outputs, err = outputsFromResults(coverageResultsFile)
+				if err != nil {
+					return err
+				}
+			}
+
+			if len(rulePaths) == 0 {
+				return fmt.Errorf("no rulesets configured: pass --rules or reference local rules from the tests")
+			}
+			rules, err := coverage.LoadRules(rulePaths)
+			if err != nil {
+				return err
+			}
+			log.Info("Loaded rules", "rules", len(rules), "paths", len(rulePaths), "outputs", len(outputs))
+
+			report := coverage.Compute(rules, outputs)
+			if coverageJSON {
+				data, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal coverage: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			printCoverage(report, coverageAll)
+			return nil
+		},
+	}
+
+	coverageCmd.Flags().StringArrayVar(&coverageRules, "rules", nil, "Ruleset file or directory to measure coverage for (repeatable)")
+	coverageCmd.Flags().StringVar(&coverageResultsFile, "results", "", "Use actual outputs from a results file instead of expected outputs")
+	coverageCmd.Flags().BoolVar(&coverageJSON, "json", false, "Print the coverage report as JSON")
+	coverageCmd.Flags().BoolVar(&coverageAll, "all", false, "List every rule, not only uncovered ones")
+
+	return coverageCmd
+}
+
+// localRulePaths returns the rule paths of a test that exist on disk.
+// Git URLs are skipped, they are only available after cloning during a run.
+func localRulePaths(test *config.TestDefinition) []string {
+	var paths []string
+	for i, rule := range test.Analysis.Rules {
+		if i < len(test.Analysis.RulesGitComponents) && test.Analysis.RulesGitComponents[i] != nil {
+			continue
+		}
+		path := rule
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(test.GetTestDir(), path)
+		}
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// outputsFromResults loads the normalized actual output of every executed test in a results file
+func outputsFromResults(resultsFile string) ([]coverage.TestOutput, error) {
+	summary, err := LoadResults(resultsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var outputs []coverage.TestOutput
+	for _, result := range summary.Tests {
+		if result.OutputFile == "" {
+			continue
+		}
+		rulesets, err := loadNormalizedOutput(result.OutputFile, filepath.Dir(result.TestFile), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load output of %s: %w", result.Name, err)
+		}
+		outputs = append(outputs, coverage.TestOutput{Test: result.Name, RuleSets: rulesets})
+	}
+	return outputs, nil
+}
+
+// printCoverage prints per-ruleset coverage followed by the uncovered rules
+func printCoverage(report *coverage.Report, all bool) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RULESET\tRULES\tMATCHED\tUNMATCHED\tSKIPPED\tUNCOVERED\tCOVERAGE")
+	for _, rs := range report.RuleSets {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%.0f%%\n",
+			rs.Name, rs.Total, rs.Matched, rs.Unmatched, rs.Skipped, rs.Uncovered, rs.Percent())
+	}
+	w.Flush()
+
+	fmt.Println()
+	for _, rs := range report.RuleSets {
+		for _, rule := range rs.Rules {
+			switch {
+			case rule.Status == coverage.Uncovered:
+				color.Red("  %s %s/%s (%s)", symbolFail, rs.Name, rule.RuleID, rule.File)
+			case all:
+				color.Green("  %s %s/%s - %s by %d test(s)", symbolPass, rs.Name, rule.RuleID, rule.Status, len(rule.Tests))
+			}
+		}
+	}
+
+	fmt.Printf("\nCoverage: %d/%d rules exercised (%.1f%%)\n", report.Covered, report.Total, report.Percent())
+}
@@ -0,0 +1,334 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/targets"
+	"github.com/spf13/cobra"
+)
+
+var (
+	doctorTargetConfigs []string
+	doctorJSON          bool
+)
+
+// Outcomes of a doctor check
+const (
+	checkOK   = "ok"
+	checkWarn = "warn"
+	checkFail = "fail"
+)
+
+// Free disk space thresholds for analysis work directories, container images and Maven caches
+const (
+	diskSpaceWarn = 10 << 30
+	diskSpaceFail = 2 << 30
+)
+
+// doctorCommandTimeout bounds every external command run by doctor
+const doctorCommandTimeout = 30 * time.Second
+
+// doctorCheck is the outcome of one environment check
+type doctorCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// NewDoctorCmd creates the doctor command
+func NewDoctorCmd() *cobra.Command {
+	doctorCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the environment for running tests",
+		Long: `Check that the tools and services koncur depends on are available:
+
+  - kantra is in PATH (or at the configured binaryPath) and runnable
+  - podman or docker works (kantra runs providers in containers)
+  - git is available (applications and rules are cloned from git)
+  - the Hub of every tackle-hub target config is reachable and accepts the credentials
+  - there is enough free disk space for work directories
+
+Target configs are the ones given with --target-config, or those auto-discovered in
+.koncur/config. Exits with the execution failure code when a check fails.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			targetConfigs, err := doctorTargets(doctorTargetConfigs)
+			if err != nil {
+				return err
+			}
+
+			checks := runDoctorChecks(cmd.Context(), targetConfigs)
+
+			failed := 0
+			for _, check := range checks {
+				if check.Status == checkFail {
+					failed++
+				}
+			}
+
+			if doctorJSON {
+				data, err := json.MarshalIndent(checks, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal checks: %w", err)
+				}
+				fmt.Println(string(data))
+			} else {
+				printDoctorChecks(checks)
+			}
+
+			if failed > 0 {
+				return withExitCode(ExitCodeExecutionFailure, "%d of %d check(s) failed", failed, len(checks))
+			}
+			return nil
+		},
+	}
+
+	doctorCmd.Flags().StringArrayVarP(&doctorTargetConfigs, "target-config", "c", nil, "Target configuration file to check (repeatable, default: .koncur/config/target-*.yaml)")
+	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "Print checks as JSON")
+
+	return doctorCmd
+}
+
+// doctorTargets loads the given target configs, or the auto-discovered ones
+func doctorTargets(files []string) ([]*config.TargetConfig, error) {
+	if len(files) == 0 {
+		discovered, err := filepath.Glob(".koncur/config/target-*.yaml")
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover target configs: %w", err)
+		}
+		files = discovered
+	}
+
+	var targetConfigs []*config.TargetConfig
+	for _, file := range files {
+		targetConfig, err := config.LoadTargetConfig(file)
+		if err != nil {
+			return nil, configError("failed to load target config %s: %w", file, err)
+		}
+		targetConfigs = append(targetConfigs, targetConfig)
+	}
+	return targetConfigs, nil
+}
+
+// runDoctorChecks runs every environment check
+func runDoctorChecks(ctx context.Context, targetConfigs []*config.TargetConfig) []doctorCheck {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var kantraConfig *config.KantraConfig
+	var hubConfigs []*config.TackleHubConfig
+	for _, targetConfig := range targetConfigs {
+		switch {
+		case targetConfig.Type == "kantra" && targetConfig.Kantra != nil:
+			kantraConfig = targetConfig.Kantra
+		case targetConfig.Type == "tackle-hub" && targetConfig.TackleHub != nil:
+			hubConfigs = append(hubConfigs, targetConfig.TackleHub)
+		}
+	}
+
+	checks := []doctorCheck{
+		checkKantra(ctx, kantraConfig),
+		checkContainerRuntime(ctx),
+		checkGit(ctx),
+	}
+	for _, hubConfig := range hubConfigs {
+		checks = append(checks, checkHub(hubConfig))
+	}
+	return append(checks, checkDiskSpace((&config.TestDefinition{}).GetWorkDir()))
+}
+
+func checkKantra(ctx context.Context, cfg *config.KantraConfig) doctorCheck {
+	check := doctorCheck{Name: "kantra"}
+	binary := "kantra"
+	if cfg != nil && cfg.BinaryPath != "" {
+		binary = cfg.BinaryPath
+	}
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		check.Status = checkFail
+		check.Message = fmt.Sprintf("%s not found: %v", binary, err)
+		check.Hint = "Install kantra (https://github.com/konveyor/kantra/releases) and add it to PATH, or set kantra.binaryPath in .koncur/config/target-kantra.yaml"
+		return check
+	}
+	out, err := runDoctorCommand(ctx, path, "version")
+	if err != nil {
+		check.Status = checkFail
+		check.Message = fmt.Sprintf("%s version failed: %v", path, err)
+		check.Hint = "Check that the kantra binary matches your OS and architecture and is executable"
+		return check
+	}
+	check.Status = checkOK
+	check.Message = fmt.Sprintf("%s (%s)", path, firstLine(out))
+	return check
+}
+
+func checkContainerRuntime(ctx context.Context) doctorCheck {
+	check := doctorCheck{Name: "container runtime"}
+	runtimes := []string{"podman", "docker"}
+	// kantra honors CONTAINER_TOOL, so check the runtime it would use
+	if tool := os.Getenv("CONTAINER_TOOL"); tool != "" {
+		runtimes = []string{tool}
+	}
+
+	var problems []string
+	for _, runtime := range runtimes {
+		path, err := exec.LookPath(runtime)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s not found", runtime))
+			continue
+		}
+		if _, err := runDoctorCommand(ctx, path, "info"); err != nil {
+			problems = append(problems, fmt.Sprintf("%s info failed: %v", runtime, err))
+			continue
+		}
+		check.Status = checkOK
+		check.Message = path
+		return check
+	}
+	check.Status = checkFail
+	check.Message = strings.Join(problems, "; ")
+	check.Hint = "Install podman or docker and make sure it runs for the current user (e.g. 'podman machine start' on macOS, or add the user to the docker group)"
+	return check
+}
+
+func checkGit(ctx context.Context) doctorCheck {
+	check := doctorCheck{Name: "git"}
+	path, err := exec.LookPath("git")
+	if err != nil {
+		check.Status = checkFail
+		check.Message = "git not found in PATH"
+		check.Hint = "Install git; applications and rules given as git URLs are cloned with it"
+		return check
+	}
+	out, err := runDoctorCommand(ctx, path, "--version")
+	if err != nil {
+		check.Status = checkFail
+		check.Message = fmt.Sprintf("git --version failed: %v", err)
+		return check
+	}
+	check.Status = checkOK
+	check.Message = firstLine(out)
+	return check
+}
+
+func checkHub(cfg *config.TackleHubConfig) doctorCheck {
+	check := doctorCheck{Name: "tackle-hub " + cfg.URL}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(cfg.URL)
+	if err != nil {
+		check.Status = checkFail
+		check.Message = fmt.Sprintf("Hub is not reachable: %v", err)
+		check.Hint = "Check tackleHub.url, that the Hub is running ('make hub-status') or port-forwarded ('make hub-forward')"
+		return check
+	}
+	resp.Body.Close()
+
+	target, err := targets.NewTackleHubTarget(cfg)
+	if err != nil {
+		check.Status = checkFail
+		check.Message = err.Error()
+		return check
+	}
+	if err := target.CheckConnection(); err != nil {
+		check.Status = checkFail
+		check.Message = err.Error()
+		check.Hint = "Check the Hub credentials (username/password, token, or their *From references) and that the user may list applications"
+		return check
+	}
+	check.Status = checkOK
+	check.Message = "reachable and authenticated"
+	return check
+}
+
+func checkDiskSpace(workDir string) doctorCheck {
+	check := doctorCheck{Name: "disk space"}
+
+	// The work directory is created on the first run; check the filesystem it would be on
+	dir := workDir
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	free, err := freeDiskSpace(dir)
+	if err != nil {
+		check.Status = checkWarn
+		check.Message = fmt.Sprintf("could not determine free space of %s: %v", dir, err)
+		return check
+	}
+	check.Message = fmt.Sprintf("%s free in %s", formatBytes(free), dir)
+	switch {
+	case free < diskSpaceFail:
+		check.Status = checkFail
+	case free < diskSpaceWarn:
+		check.Status = checkWarn
+	default:
+		check.Status = checkOK
+		return check
+	}
+	check.Hint = fmt.Sprintf("Free up space (e.g. 'koncur clean', 'podman image prune') or set workDir in tests to a larger filesystem; at least %s is recommended", formatBytes(diskSpaceWarn))
+	return check
+}
+
+// runDoctorCommand runs a command with the doctor timeout and returns its combined output
+func runDoctorCommand(ctx context.Context, binary string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, doctorCommandTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, binary, args...).CombinedOutput()
+	if err != nil {
+		if msg := firstLine(string(out)); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// formatBytes formats a byte count in binary units
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func printDoctorChecks(checks []doctorCheck) {
+	for _, check := range checks {
+		switch check.Status {
+		case checkOK:
+			color.Green("%s %s: %s", symbolPass, check.Name, check.Message)
+		case checkWarn:
+			color.Yellow("%s %s: %s", symbolWarn, check.Name, check.Message)
+		default:
+			color.Red("%s %s: %s", symbolFail, check.Name, check.Message)
+		}
+		if check.Hint != "" && check.Status != checkOK {
+			fmt.Printf("    hint: %s\n", check.Hint)
+		}
+	}
+}
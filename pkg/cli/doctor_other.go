@@ -0,0 +1,13 @@
+//go:build !unix
+
+package cli
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// freeDiskSpace is not implemented on this platform
+func freeDiskSpace(dir string) (uint64, error) {
+	return 0, fmt.Errorf("not supported on %s", runtime.GOOS)
+}
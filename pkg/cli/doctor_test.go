@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/konveyor/test-harness/pkg/config"
+)
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    uint64
+		want string
+	}{
+		{512, "512 B"},
+		{2048, "2.0 KiB"},
+		{3 << 20, "3.0 MiB"},
+		{10 << 30, "10.0 GiB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.n); got != tt.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestCheckKantra_NotFound(t *testing.T) {
+	check := checkKantra(t.Context(), &config.KantraConfig{BinaryPath: filepath.Join(t.TempDir(), "kantra")})
+	if check.Status != checkFail || check.Hint == "" {
+		t.Errorf("expected a failed check with a hint, got %+v", check)
+	}
+}
+
+func TestCheckDiskSpace_MissingWorkDir(t *testing.T) {
+	dir := t.TempDir()
+	check := checkDiskSpace(filepath.Join(dir, "not", "created"))
+	if check.Status == "" || check.Message == "" {
+		t.Errorf("expected the nearest existing parent to be checked, got %+v", check)
+	}
+}
+
+func TestCheckHub(t *testing.T) {
+	t.Run("unreachable", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		url := server.URL
+		server.Close()
+
+		check := checkHub(&config.TackleHubConfig{URL: url})
+		if check.Status != checkFail || check.Hint == "" {
+			t.Errorf("expected a failed check with a hint, got %+v", check)
+		}
+	})
+
+	t.Run("rejected credentials", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		check := checkHub(&config.TackleHubConfig{URL: server.URL, Token: "invalid"})
+		if check.Status != checkFail {
+			t.Errorf("expected a failed check, got %+v", check)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Process exit codes, so CI can branch on the kind of failure
+const (
+	// ExitCodeSuccess means every test passed (or was skipped)
+	ExitCodeSuccess = 0
+	// ExitCodeValidationFailure means a test ran but its output or exit code did not match expectations
+	ExitCodeValidationFailure = 1
+	// ExitCodeExecutionFailure means the tool under test or the environment failed
+	ExitCodeExecutionFailure = 2
+	// ExitCodeConfigError means a test definition, target config or flag is invalid
+	ExitCodeConfigError = 3
+	// ExitCodeFlaky is returned by 'koncur run --repeat' when a test both passed and failed
+	ExitCodeFlaky = 4
+)
+
+// Failure kinds recorded on failed test results
+const (
+	FailureValidation = "validation"
+	FailureExecution  = "execution"
+	FailureConfig     = "config"
+)
+
+// exitError makes a command exit with a specific process exit code
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode wraps a formatted error so Execute exits with the given code
+func withExitCode(code int, format string, args ...any) error {
+	return &exitError{code: code, err: fmt.Errorf(format, args...)}
+}
+
+// configError reports an invalid test definition, target config or flag
+func configError(format string, args ...any) error {
+	return withExitCode(ExitCodeConfigError, format, args...)
+}
+
+// exitCodeFor returns the process exit code for an error returned by a command.
+// Errors that were not classified are treated as execution failures.
+func exitCodeFor(err error) int {
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return ExitCodeExecutionFailure
+}
+
+// failureExitCode picks the exit code for a set of failure kinds.
+// Configuration errors win over execution failures, which win over validation failures.
+func failureExitCode(kinds map[string]int) int {
+	switch {
+	case kinds[FailureConfig] > 0:
+		return ExitCodeConfigError
+	case kinds[FailureExecution] > 0:
+		return ExitCodeExecutionFailure
+	case kinds[FailureValidation] > 0:
+		return ExitCodeValidationFailure
+	}
+	return ExitCodeSuccess
+}
+
+// runExitCode derives the exit code of a run from its test results
+func runExitCode(results []TestResult) int {
+	kinds := map[string]int{}
+	flaky := false
+	for _, result := range results {
+		switch result.Status {
+		case "failed":
+			kind := result.FailureKind
+			if kind == "" {
+				kind = FailureValidation
+			}
+			kinds[kind]++
+		case "flaky":
+			flaky = true
+		}
+	}
+	if code := failureExitCode(kinds); code != ExitCodeSuccess {
+		return code
+	}
+	if flaky {
+		return ExitCodeFlaky
+	}
+	return ExitCodeSuccess
+}
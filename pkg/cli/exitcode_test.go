@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestRunExitCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []TestResult
+		want    int
+	}{
+		{"all passed", []TestResult{{Status: "passed"}, {Status: "skipped"}}, ExitCodeSuccess},
+		{"validation failure", []TestResult{{Status: "passed"}, {Status: "failed", FailureKind: FailureValidation}}, ExitCodeValidationFailure},
+		{"unclassified failure", []TestResult{{Status: "failed"}}, ExitCodeValidationFailure},
+		{"execution beats validation", []TestResult{{Status: "failed", FailureKind: FailureValidation}, {Status: "failed", FailureKind: FailureExecution}}, ExitCodeExecutionFailure},
+		{"config beats execution", []TestResult{{Status: "failed", FailureKind: FailureExecution}, {Status: "failed", FailureKind: FailureConfig}}, ExitCodeConfigError},
+		{"flaky only", []TestResult{{Status: "passed"}, {Status: "flaky"}}, ExitCodeFlaky},
+		{"failure beats flaky", []TestResult{{Status: "flaky"}, {Status: "failed", FailureKind: FailureValidation}}, ExitCodeValidationFailure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runExitCode(tt.results); got != tt.want {
+				t.Errorf("runExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitCodeFor(t *testing.T) {
+	if got := exitCodeFor(configError("bad flag")); got != ExitCodeConfigError {
+		t.Errorf("config error exit code = %d, want %d", got, ExitCodeConfigError)
+	}
+	wrapped := fmt.Errorf("context: %w", withExitCode(ExitCodeValidationFailure, "1 test failed"))
+	if got := exitCodeFor(wrapped); got != ExitCodeValidationFailure {
+		t.Errorf("wrapped exit code = %d, want %d", got, ExitCodeValidationFailure)
+	}
+	if got := exitCodeFor(errors.New("boom")); got != ExitCodeExecutionFailure {
+		t.Errorf("unclassified exit code = %d, want %d", got, ExitCodeExecutionFailure)
+	}
+}
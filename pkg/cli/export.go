@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/ruletest"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportOutputDir string
+	exportProvider  string
+	exportForce     bool
+)
+
+// NewExportCmd creates the export command
+func NewExportCmd() *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export tests to other test suites",
+	}
+
+	ruleTestCmd := &cobra.Command{
+		Use:   "rule-test <test-file-or-directory>...",
+		Short: "Export tests as kantra rule tests (*.test.yaml)",
+		Long: `Convert koncur tests into the rule tests run by 'kantra test', one
+<output>/<test-name>.test.yaml per test:
+
+  koncur export rule-test tests/my-rules -o rules/tests
+
+Every expected violation and insight becomes a test case checking its number of
+incidents, and rules expected absent become isUnmatched test cases. The local
+application and rules of the test are referenced relative to the tests file.
+Expectations without a rule test equivalent (tags, git applications and rules,
+several rules paths) are reported.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			testFiles, err := discoverTestFiles(args, &testSelector{})
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(exportOutputDir, 0755); err != nil {
+				return fmt.Errorf("failed to create directory %s: %w", exportOutputDir, err)
+			}
+
+			written := 0
+			for _, testFile := range testFiles {
+				ok, err := exportRuleTest(testFile, exportOutputDir, exportProvider, exportForce)
+				if err != nil {
+					return err
+				}
+				if ok {
+					written++
+				}
+			}
+			fmt.Printf("\nExported %d of %d test(s) into %s\n", written, len(testFiles), exportOutputDir)
+			return nil
+		},
+	}
+	ruleTestCmd.Flags().StringVarP(&exportOutputDir, "output", "o", ".", "Directory to write the rule tests to")
+	ruleTestCmd.Flags().StringVar(&exportProvider, "provider", "java", "Provider that analyzes the application")
+	ruleTestCmd.Flags().BoolVar(&exportForce, "force", false, "Overwrite existing rule tests")
+
+	exportCmd.AddCommand(ruleTestCmd)
+	return exportCmd
+}
+
+// exportRuleTest writes the rule tests of a test, and reports whether they were written
+func exportRuleTest(testFile, outputDir, providerName string, force bool) (bool, error) {
+	test, err := config.Load(testFile)
+	if err != nil {
+		return false, configError("failed to load test %s: %w", testFile, err)
+	}
+	output := filepath.Join(outputDir, test.Name+".test.yaml")
+	if _, err := os.Stat(output); err == nil && !force {
+		color.Yellow("%s Kept existing %s (use --force to overwrite)", symbolWarn, output)
+		return false, nil
+	}
+
+	file, warnings := ruletest.Export(test, output, providerName)
+	if len(file.Tests) == 0 {
+		color.Yellow("%s Skipped %s: no expected violations, insights or absent rules", symbolSkip, testFile)
+		return false, nil
+	}
+	if err := ruletest.Save(output, file); err != nil {
+		return false, err
+	}
+
+	color.Green("%s Exported %s (%d rule(s))", symbolPass, output, len(file.Tests))
+	for _, warning := range warnings {
+		color.Yellow("    %s %s", symbolWarn, warning)
+	}
+	return true, nil
+}
@@ -15,161 +15,215 @@ import (
 	"github.com/konveyor/test-harness/pkg/util"
 	"github.com/spf13/cobra"
 	yaml2 "gopkg.in/yaml.v2"
-	"gopkg.in/yaml.v3"
 )
 
 var (
 	testDir             string
 	outputDir           string
-	generateFilter      string
+	generateFilters     []string
+	generateExcludes    []string
+	generateTags        []string
+	generateExcludeTags []string
 	dryRun              bool
 	targetTypeGen       string
 	targetConfigFileGen string
+	generateMissingOnly bool
+	generateChanged     string
+	generateFromOutput  string
+	generateReview      bool
+	generateToolVersion string
 )
 
 // NewGenerateCmd creates the generate command
 func NewGenerateCmd() *cobra.Command {
 	generateCmd := &cobra.Command{
-		Use:   "generate",
+		Use:   "generate [test-file-or-directory]...",
 		Short: "Generate expected outputs for tests",
 		Long: `Generate expected outputs by running tests and capturing their actual results.
 This command will:
-  1. Find all test.yaml files in the specified directory
+  1. Find all test.yaml files in the given paths (default: --test-dir) selected
+     by --filter, --exclude and --tags
   2. Execute each test using the specified target (default: kantra)
   3. Save the actual output as the expected output for each test
 
 This is useful when:
   - Creating new tests and need to capture baseline outputs
   - Updating tests after tool behavior changes
-  - Regenerating outputs after fixing test definitions`,
+  - Regenerating outputs after fixing test definitions
+
+With --missing-only and --changed-since <ref> only tests without an expected output,
+or whose inputs changed since a git ref, are generated (either, when both are given).
+
+With --from-output <file> the expected output of a single test is converted from an
+analysis output produced elsewhere (a CI artifact, a Hub download) without executing
+the target:
+
+  koncur generate tests/daytrader --from-output ~/Downloads/output.yaml
+
+With --review the changes to each ruleset of an existing expected output are shown as
+a diff and only the accepted ones are saved, so regressions are not baked into baselines.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			log := util.GetLogger()
 
-			// Find all test.yaml files
-			log.Info("Searching for test files", "directory", testDir)
-			testFiles, err := findTestFiles(testDir)
+			selector, err := newTestSelector(generateFilters, generateExcludes, generateTags, generateExcludeTags)
 			if err != nil {
-				return fmt.Errorf("failed to find test files: %w", err)
+				return err
+			}
+			// Test paths given as arguments take precedence over --test-dir
+			paths := args
+			if len(paths) == 0 {
+				paths = []string{testDir}
+			}
+			testFiles, err := discoverTestFiles(paths, selector)
+			if err != nil {
+				return err
 			}
 
+			// Incremental generation leaves up-to-date expected outputs alone
+			discovered := len(testFiles)
+			testFiles, err = incrementalSelection(testFiles, generateMissingOnly, generateChanged)
+			if err != nil {
+				return err
+			}
+			if upToDate := discovered - len(testFiles); upToDate > 0 {
+				fmt.Printf("Skipping %d of %d test(s) with up-to-date expected outputs\n", upToDate, discovered)
+			}
 			if len(testFiles) == 0 {
-				return fmt.Errorf("no test files found in %s", testDir)
+				color.Green("%s All expected outputs are up to date", symbolPass)
+				return nil
+			}
+			if generateFromOutput != "" && len(testFiles) != 1 {
+				return configError("--from-output generates a single test, %d selected", len(testFiles))
 			}
 
-			log.Info("Found test files", "count", len(testFiles))
+			// Load or create target config once for all tests, like run
+			targetConfig, err := loadTargetConfig(targetConfigFileGen, targetTypeGen)
+			if err != nil {
+				return err
+			}
+			log.Info("Using target", "type", targetConfig.Type)
+			skipEnv := config.NewSkipEnvironment(targetConfig)
+
+			// The version of the tool is recorded with each expected output; an output
+			// converted with --from-output was not produced by the local tool
+			toolVersion := generateToolVersion
+			if toolVersion == "" && generateFromOutput == "" && !dryRun {
+				toolVersion = detectToolVersion(targetConfig)
+			}
 
-			// Filter tests if pattern provided
-			if generateFilter != "" {
-				filtered := []string{}
-				for _, tf := range testFiles {
-					testName := filepath.Base(filepath.Dir(tf))
-					if strings.Contains(testName, generateFilter) {
-						filtered = append(filtered, tf)
-					}
-				}
-				testFiles = filtered
-				log.Info("Filtered test files", "count", len(testFiles), "pattern", generateFilter)
+			var review *reviewer
+			if generateReview && !dryRun {
+				review = newReviewer(os.Stdin)
 			}
 
-			if len(testFiles) == 0 {
-				return fmt.Errorf("no test files matched filter: %s", generateFilter)
+			// Tests requiring providers the target does not have are skipped
+			if skipEnv.Providers == nil && generateFromOutput == "" && requireProviders(testFiles) {
+				if target, err := targets.NewTarget(targetConfig); err == nil {
+					skipEnv.Providers = targetProviders(target)
+				}
 			}
 
 			// Process each test
 			successCount := 0
-			failCount := 0
 			skippedCount := 0
+			// failures counts failed tests by failure kind to pick the exit code
+			failures := map[string]int{}
 
 			for i, testFile := range testFiles {
-				testName := filepath.Base(filepath.Dir(testFile))
+				testName := resultName(testFile)
 				fmt.Printf("\n[%d/%d] Processing: %s\n", i+1, len(testFiles), testName)
 
 				// Load test definition (skip loading expected output since we're generating it)
 				test, err := config.LoadWithOptions(testFile, true)
 				if err != nil {
-					color.Red("  ✗ Failed to load: %v", err)
-					failCount++
+					color.Red("  %s Failed to load: %v", symbolFail, err)
+					failures[FailureConfig]++
 					continue
 				}
 
 				// Check if test is marked as skipped
-				if isTestSkipped(testFile) {
-					color.Yellow("  ⊘ Skipped (marked as SKIPPED in file)")
+				if reason, skipped := testSkip(testFile, skipEnv); skipped {
+					color.Yellow("  %s Skipped%s", symbolSkip, skipSuffix(reason))
+					skippedCount++
+					continue
+				}
+				if test.Kind == config.KindListLabels || test.Kind == config.KindTransform {
+					color.Yellow("  %s Skipped (%s tests have no expected output)", symbolSkip, test.Kind)
+					skippedCount++
+					continue
+				}
+				if test.Expect.Providers != nil && len(test.Expect.Providers.Output) > 0 {
+					color.Yellow("  %s Skipped (expected output is split per provider, update it manually)", symbolSkip)
 					skippedCount++
 					continue
 				}
 
 				// Validate test definition (skip expected output validation since we're generating it)
 				if err := validateTestForGeneration(test); err != nil {
-					color.Red("  ✗ Invalid test definition: %v", err)
-					failCount++
+					color.Red("  %s Invalid test definition: %v", symbolFail, err)
+					failures[FailureConfig]++
 					continue
 				}
 
-				// Load or create target config
-				var targetConfig *config.TargetConfig
-				if targetConfigFileGen != "" {
-					log.Info("Loading target configuration", "file", targetConfigFileGen)
-					targetConfig, err = config.LoadTargetConfig(targetConfigFileGen)
-					if err != nil {
-						color.Red("  ✗ Failed to load target config: %v", err)
-						failCount++
+				// Convert an output produced elsewhere instead of executing the target
+				if generateFromOutput != "" {
+					if dryRun {
+						color.Cyan("  %s Would convert: %s", symbolDryRun, generateFromOutput)
+						successCount++
 						continue
 					}
-				} else if targetTypeGen != "" {
-					// Try to auto-discover config file for the specified target type
-					discoveredPath := fmt.Sprintf(".koncur/config/target-%s.yaml", targetTypeGen)
-					if _, err := os.Stat(discoveredPath); err == nil {
-						log.Info("Auto-discovered target configuration", "file", discoveredPath)
-						targetConfig, err = config.LoadTargetConfig(discoveredPath)
-						if err != nil {
-							color.Red("  ✗ Failed to load auto-discovered target config: %v", err)
-							failCount++
-							continue
-						}
-					} else {
-						// Create default config for specified type
-						targetConfig = &config.TargetConfig{Type: targetTypeGen}
+					if len(test.Analysis.Applications) > 0 {
+						color.Red("  %s A test with several applications cannot be generated from one output", symbolFail)
+						failures[FailureConfig]++
+						continue
 					}
-				} else {
-					// Default to kantra, try to auto-discover first
-					discoveredPath := ".koncur/config/target-kantra.yaml"
-					if _, err := os.Stat(discoveredPath); err == nil {
-						log.Info("Auto-discovered target configuration", "file", discoveredPath)
-						targetConfig, err = config.LoadTargetConfig(discoveredPath)
-						if err != nil {
-							color.Red("  ✗ Failed to load auto-discovered target config: %v", err)
-							failCount++
-							continue
-						}
-					} else {
-						// Create default kantra config
-						targetConfig = &config.TargetConfig{Type: "kantra"}
+					actualOutput, err := readAnalysisOutput(generateFromOutput)
+					if err != nil {
+						color.Red("  %s Failed to parse output: %v", symbolFail, err)
+						failures[FailureConfig]++
+						continue
+					}
+					// The exit code of the analysis is unknown, the expected one is kept
+					filteredOutput, err := writeGeneratedOutput(testFile, test, actualOutput, test.Expect.ExitCode, targetConfig, toolVersion, review)
+					if err != nil {
+						color.Red("  %s %v", symbolFail, err)
+						failures[FailureExecution]++
+						continue
 					}
+					color.Green("  %s Converted %s into expected output (%d rulesets, %d filtered)", symbolPass, generateFromOutput, len(filteredOutput), len(actualOutput)-len(filteredOutput))
+					successCount++
+					continue
 				}
 
 				// Check if test requires maven settings but target doesn't have it
 				if test.RequireMavenSettings {
 					hasSettings := false
-					if targetConfig.Kantra != nil && targetConfig.Kantra.MavenSettings != "" {
+					if test.MavenSettings != "" {
+						hasSettings = true
+						if _, err := os.Stat(test.MavenSettingsPath()); err != nil {
+							color.Red("  %s Failed to stat maven settings: %v", symbolFail, err)
+							failures[FailureConfig]++
+							continue
+						}
+					} else if targetConfig.Kantra != nil && targetConfig.Kantra.MavenSettings != "" {
 						hasSettings = true
 						if _, err := os.Stat(targetConfig.Kantra.MavenSettings); err != nil {
-							color.Red("  ✗ Failed to stat maven settings: %v", err)
-							failCount++
+							color.Red("  %s Failed to stat maven settings: %v", symbolFail, err)
+							failures[FailureConfig]++
 							continue
 						}
 					} else if targetConfig.TackleHub != nil && targetConfig.TackleHub.MavenSettings != "" {
 						hasSettings = true
 						if _, err := os.Stat(targetConfig.TackleHub.MavenSettings); err != nil {
-							color.Red("  ✗ Failed to stat maven settings: %v", err)
-							failCount++
+							color.Red("  %s Failed to stat maven settings: %v", symbolFail, err)
+							failures[FailureConfig]++
 							continue
 						}
 					}
 
 					if !hasSettings {
-						color.Red("  ✗ Test requires maven settings but none configured in target config")
-						failCount++
+						color.Red("  %s Test requires maven settings but none configured in target config", symbolFail)
+						failures[FailureConfig]++
 						continue
 					}
 				}
@@ -177,13 +231,13 @@ This is useful when:
 				// Create target
 				target, err := targets.NewTarget(targetConfig)
 				if err != nil {
-					color.Red("  ✗ Failed to create target: %v", err)
-					failCount++
+					color.Red("  %s Failed to create target: %v", symbolFail, err)
+					failures[FailureConfig]++
 					continue
 				}
 
 				if dryRun {
-					color.Cyan("  ⇢ Would execute: %s", target.Name())
+					color.Cyan("  %s Would execute: %s", symbolDryRun, target.Name())
 					successCount++
 					continue
 				}
@@ -192,52 +246,54 @@ This is useful when:
 				log.Info("Executing analysis", "test", testName, "target", target.Name())
 				result, err := target.Execute(context.Background(), test)
 				if err != nil {
-					color.Red("  ✗ Execution failed: %v", err)
-					failCount++
+					color.Red("  %s Execution failed: %v", symbolFail, err)
+					failures[FailureExecution]++
 					continue
 				}
 
-				color.Blue("  ⟳ Analysis completed (exit code: %d, duration: %s)", result.ExitCode, result.Duration)
+				color.Blue("  %s Analysis completed (exit code: %d, duration: %s)", symbolRun, result.ExitCode, result.Duration)
+
+				// Each application of a test analyzing several gets its own expected output
+				if len(result.Applications) > 0 {
+					if err := writeGeneratedApplicationOutputs(testFile, test, result, targetConfig, toolVersion); err != nil {
+						color.Red("  %s %v", symbolFail, err)
+						failures[FailureExecution]++
+						continue
+					}
+					successCount++
+					continue
+				}
 
 				// Parse the output
-				actualOutput, err := parser.ParseOutput(result.OutputFile)
+				actualOutput, _, err := parser.ParseOutputVersion(result.OutputFile)
 				if err != nil {
-					color.Red("  ✗ Failed to parse output: %v", err)
-					failCount++
+					color.Red("  %s Failed to parse output: %v", symbolFail, err)
+					failures[FailureExecution]++
 					continue
 				}
 
 				log.Info("Output parsed", "rulesets", len(actualOutput))
 
-				// Filter rulesets to only include those with violations, insights, or tags
-				filteredOutput := parser.FilterRuleSets(actualOutput)
-				log.Info("Filtered output", "original", len(actualOutput), "filtered", len(filteredOutput))
-
-				// Update test to use file-based expectation
-				test.Expect.ExitCode = result.ExitCode
-				test.Expect.Output.Result = nil // Clear inline expectation
-
-				// Save the filtered output.yaml file to the test directory
-				testDirPath := test.GetTestDir() // Use the absolute path stored in test
-				expectedOutputFile := filepath.Join(testDirPath, "expected-output.yaml")
-
-				// Save the filtered output as YAML with path normalization
-				if err := saveFilteredOutput(filteredOutput, expectedOutputFile, testDirPath); err != nil {
-					color.Red("  ✗ Failed to save filtered output: %v", err)
-					failCount++
+				filteredOutput, err := writeGeneratedOutput(testFile, test, actualOutput, test.Expect.ExitCode.Accepting(result.ExitCode), targetConfig, toolVersion, review)
+				if err != nil {
+					color.Red("  %s %v", symbolFail, err)
+					failures[FailureExecution]++
 					continue
 				}
 
-				test.Expect.Output.File = "expected-output.yaml"
+				color.Green("  %s Generated and saved expected output (%d rulesets, %d filtered)", symbolPass, len(filteredOutput), len(actualOutput)-len(filteredOutput))
 
-				// Save updated test definition
-				if err := saveSimpleTestDefinition(testFile, test); err != nil {
-					color.Red("  ✗ Failed to save: %v", err)
-					failCount++
-					continue
+				// Dependencies get a baseline next to the expected output, when the target lists them
+				if result.DependenciesFile != "" {
+					depsFile := test.VariantFile(expectedDependenciesFile)
+					count, err := saveDependencies(result.DependenciesFile, filepath.Join(test.GetTestDir(), depsFile), test.GetTestDir())
+					if err != nil {
+						color.Red("  %s %v", symbolFail, err)
+						failures[FailureExecution]++
+						continue
+					}
+					color.Green("  %s Saved %d dependencies to %s", symbolPass, count, depsFile)
 				}
-
-				color.Green("  ✓ Generated and saved expected output (%d rulesets, %d filtered)", len(filteredOutput), len(actualOutput)-len(filteredOutput))
 				successCount++
 			}
 
@@ -245,14 +301,14 @@ This is useful when:
 			fmt.Println("\n" + strings.Repeat("=", 60))
 			fmt.Printf("Summary: %d total\n", len(testFiles))
 			if successCount > 0 {
-				color.Green("  ✓ Success: %d", successCount)
+				color.Green("  %s Success: %d", symbolPass, successCount)
 			}
 			if skippedCount > 0 {
-				color.Yellow("  ⊘ Skipped: %d", skippedCount)
+				color.Yellow("  %s Skipped: %d", symbolSkip, skippedCount)
 			}
-			if failCount > 0 {
-				color.Red("  ✗ Failed: %d", failCount)
-				return fmt.Errorf("failed to generate outputs for %d tests", failCount)
+			if failCount := failures[FailureConfig] + failures[FailureExecution]; failCount > 0 {
+				color.Red("  %s Failed: %d", symbolFail, failCount)
+				return withExitCode(failureExitCode(failures), "failed to generate outputs for %d tests", failCount)
 			}
 
 			return nil
@@ -261,15 +317,121 @@ This is useful when:
 
 	// Flags
 	generateCmd.Flags().StringVarP(&testDir, "test-dir", "d", "./tests", "Directory containing test definitions")
-	generateCmd.Flags().StringVarP(&generateFilter, "filter", "f", "", "Filter tests by name pattern")
+	generateCmd.Flags().StringArrayVarP(&generateFilters, "filter", "f", nil, "Only generate tests whose directory name matches this regular expression (repeatable, any may match)")
+	generateCmd.Flags().StringArrayVar(&generateExcludes, "exclude", nil, "Skip tests whose directory name matches this regular expression (repeatable)")
+	generateCmd.Flags().StringSliceVar(&generateTags, "tags", nil, "Only generate tests with any of these tags (comma-separated or repeatable)")
+	generateCmd.Flags().StringSliceVar(&generateExcludeTags, "exclude-tags", nil, "Skip tests with any of these tags (comma-separated or repeatable)")
+	generateCmd.Flags().BoolVar(&generateMissingOnly, "missing-only", false, "Only generate tests without an expected output")
+	generateCmd.Flags().StringVar(&generateChanged, "changed-since", "", "Only generate tests whose directory, local application or rules changed since this git ref")
+	generateCmd.Flags().StringVar(&generateFromOutput, "from-output", "", "Convert this analysis output (output.yaml or a Hub issues export) instead of executing the target; selects a single test")
+	generateCmd.Flags().BoolVar(&generateReview, "review", false, "Show the changes to existing expected outputs and ask which rulesets to accept")
+	generateCmd.Flags().StringVar(&generateToolVersion, "tool-version", os.Getenv("KONCUR_TOOL_VERSION"), "Version of the tool under test, recorded in expected outputs (default: $KONCUR_TOOL_VERSION, or the version kantra reports)")
 	generateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be done without executing")
 	generateCmd.Flags().StringVarP(&targetTypeGen, "target", "t", "kantra", "Target type to use (kantra, tackle-hub, tackle-ui, kai-rpc, vscode)")
-	generateCmd.Flags().StringVarP(&targetConfigFileGen, "target-config", "c", "", "Path to target configuration file")
+	generateCmd.Flags().StringVarP(&targetConfigFileGen, "target-config", "c", "", "Path to target configuration file (default: .koncur/config/target-<type>.yaml if present)")
 
 	return generateCmd
 }
 
-// findTestFiles recursively finds all test.yaml files in the given directory
+// readAnalysisOutput parses an analysis output file, or a Hub issues export
+func readAnalysisOutput(file string) ([]konveyor.RuleSet, error) {
+	if parser.IsHubIssuesExport(file) {
+		return parser.ParseHubIssuesExport(file)
+	}
+	rulesets, _, err := parser.ParseOutputVersion(file)
+	return rulesets, err
+}
+
+// writeGeneratedOutput saves the filtered analysis output as the test's
+// expected-output.yaml, with a header recording how it was generated, and points
+// the test definition at it, expecting exitCodes.
+// With review, only the accepted changes to the previous expected output are kept.
+// It returns the rulesets that were kept.
+func writeGeneratedOutput(testFile string, test *config.TestDefinition, actualOutput []konveyor.RuleSet, exitCodes config.ExitCodes, targetConfig *config.TargetConfig, toolVersion string, review *reviewer) ([]konveyor.RuleSet, error) {
+	log := util.GetLogger()
+
+	// Filter rulesets to only include those with violations, insights, or tags
+	// (or the sections the test's validation.filter keeps)
+	filteredOutput := targetConfig.Validation.Merge(test.Validation).Filter.Apply(actualOutput)
+	log.Info("Filtered output", "original", len(actualOutput), "filtered", len(filteredOutput))
+
+	// Keep the previous version of the rulesets whose changes are rejected
+	if review != nil {
+		previous, err := previousOutput(test)
+		if err != nil {
+			return nil, err
+		}
+		generated, err := parser.NormalizeRuleSets(filteredOutput, test.GetTestDir())
+		if err != nil {
+			return nil, fmt.Errorf("failed to normalize output: %w", err)
+		}
+		if filteredOutput, err = review.review(previous, generated); err != nil {
+			return nil, err
+		}
+	}
+
+	// Update test to use file-based expectation
+	test.Expect.ExitCode = exitCodes
+	test.Expect.Output.Result = nil // Clear inline expectation
+
+	// Save the filtered output.yaml file to the test directory, one per matrix variant
+	testDirPath := test.GetTestDir() // Use the absolute path stored in test
+	expectedOutputFile := filepath.Join(testDirPath, test.VariantFile("expected-output.yaml"))
+
+	// Save the filtered output as YAML with path normalization
+	metadata := generationMetadata(test, targetConfig.Type, toolVersion)
+	if err := saveFilteredOutput(filteredOutput, expectedOutputFile, testDirPath, metadata); err != nil {
+		return nil, fmt.Errorf("failed to save filtered output: %w", err)
+	}
+
+	test.Expect.Output.File = test.VariantFilePattern("expected-output.yaml")
+
+	// Save updated test definition
+	if err := config.SaveExpectations(testFile, test.Expect.ExitCode, test.Expect.Output.File); err != nil {
+		return nil, fmt.Errorf("failed to save: %w", err)
+	}
+	return filteredOutput, nil
+}
+
+// writeGeneratedApplicationOutputs saves the filtered output of each application of a
+// test analyzing several as its expected-output-<application>.yaml, and points the
+// test definition at them. Outputs of several applications are not reviewed.
+func writeGeneratedApplicationOutputs(testFile string, test *config.TestDefinition, result *targets.ExecutionResult, targetConfig *config.TargetConfig, toolVersion string) error {
+	if len(result.Applications) != len(test.Analysis.Applications) {
+		return fmt.Errorf("target wrote outputs for %d of %d applications", len(result.Applications), len(test.Analysis.Applications))
+	}
+	filter := targetConfig.Validation.Merge(test.Validation).Filter
+	files := map[string]string{}
+	for i, app := range result.Applications {
+		metadata := generationMetadata(test.ForApplication(test.Analysis.Applications[i]), targetConfig.Type, toolVersion)
+		actualOutput, _, err := parser.ParseOutputVersion(app.OutputFile)
+		if err != nil {
+			return fmt.Errorf("failed to parse output of application %s: %w", app.Name, err)
+		}
+		filteredOutput := filter.Apply(actualOutput)
+		name := fmt.Sprintf("expected-output-%s.yaml", app.Name)
+		if err := saveFilteredOutput(filteredOutput, filepath.Join(test.GetTestDir(), test.VariantFile(name)), test.GetTestDir(), metadata); err != nil {
+			return fmt.Errorf("failed to save filtered output of application %s: %w", app.Name, err)
+		}
+		files[app.Name] = test.VariantFilePattern(name)
+		color.Green("  %s Generated and saved expected output of %s (%d rulesets, %d filtered)", symbolPass, app.Name, len(filteredOutput), len(actualOutput)-len(filteredOutput))
+		if app.DependenciesFile != "" {
+			depsFile := test.VariantFile(applicationDependenciesFile(app.Name))
+			count, err := saveDependencies(app.DependenciesFile, filepath.Join(test.GetTestDir(), depsFile), test.GetTestDir())
+			if err != nil {
+				return fmt.Errorf("application %s: %w", app.Name, err)
+			}
+			color.Green("  %s Saved %d dependencies of %s to %s", symbolPass, count, app.Name, depsFile)
+		}
+	}
+	if err := config.SaveApplicationExpectations(testFile, test.Expect.ExitCode.Accepting(result.ExitCode), files); err != nil {
+		return fmt.Errorf("failed to save: %w", err)
+	}
+	return nil
+}
+
+// findTestFiles recursively finds all test.yaml files in the given directory, with a
+// reference to each variant of tests with a matrix
 func findTestFiles(dir string) ([]string, error) {
 	var testFiles []string
 
@@ -278,7 +440,7 @@ func findTestFiles(dir string) ([]string, error) {
 			return err
 		}
 		if !info.IsDir() && filepath.Base(path) == "test.yaml" {
-			testFiles = append(testFiles, path)
+			testFiles = append(testFiles, testVariants(path)...)
 		}
 		return nil
 	})
@@ -288,7 +450,8 @@ func findTestFiles(dir string) ([]string, error) {
 
 // isTestSkipped checks if the test file contains a SKIPPED marker in the first few lines
 func isTestSkipped(testFile string) bool {
-	content, err := os.ReadFile(testFile)
+	path, _ := config.SplitTestRef(testFile)
+	content, err := os.ReadFile(path)
 	if err != nil {
 		return false
 	}
@@ -302,6 +465,60 @@ func isTestSkipped(testFile string) bool {
 	return strings.Contains(searchContent, "SKIPPED:") || strings.Contains(searchContent, "# SKIPPED")
 }
 
+// testSkip reports whether a test is skipped in an environment and why, by its skip
+// field or a legacy "# SKIPPED: reason" comment. Any skip applies without a target type.
+func testSkip(testFile string, env config.SkipEnvironment) (string, bool) {
+	if reason, ok := skipMarker(testFile); ok {
+		return reason, true
+	}
+	// Tests that fail to load run, to report the error
+	if skip, err := config.LoadSkip(testFile); err == nil && skip.Applies(env) {
+		return skip.String(), true
+	}
+	requires, err := config.LoadRequirements(testFile)
+	if err != nil {
+		return "", false
+	}
+	if missing := requires.Missing(env); len(missing) > 0 {
+		return fmt.Sprintf("requires providers %s, which %s does not have", strings.Join(missing, ", "), env.TargetType), true
+	}
+	return "", false
+}
+
+// requireProviders reports whether any test requires providers
+func requireProviders(testFiles []string) bool {
+	for _, testFile := range testFiles {
+		if requires, err := config.LoadRequirements(testFile); err == nil && requires != nil && len(requires.Providers) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// targetProviders asks a target for its analysis providers, nil when it cannot tell
+func targetProviders(target targets.Target) []string {
+	log := util.GetLogger()
+	lister, ok := target.(targets.ProviderLister)
+	if !ok {
+		return nil
+	}
+	providers, err := lister.Providers(context.Background())
+	if err != nil {
+		log.Info("Failed to list the providers of the target, provider requirements are ignored", "error", err.Error())
+		return nil
+	}
+	log.Info("Target providers", "providers", providers)
+	return providers
+}
+
+// skipSuffix follows "Skipped" in console output with the skip reason
+func skipSuffix(reason string) string {
+	if reason == "" {
+		return " (marked as skipped)"
+	}
+	return ": " + reason
+}
+
 // validateTestForGeneration validates a test but skips expected output validation
 // since we're about to generate the expected output
 func validateTestForGeneration(test *config.TestDefinition) error {
@@ -309,7 +526,7 @@ func validateTestForGeneration(test *config.TestDefinition) error {
 	if test.Name == "" {
 		return fmt.Errorf("test name is required")
 	}
-	if test.Analysis.Application == "" {
+	if test.Analysis.Application == "" && len(test.Analysis.Applications) == 0 {
 		return fmt.Errorf("analysis application is required")
 	}
 	if test.Analysis.AnalysisMode == "" {
@@ -318,61 +535,43 @@ func validateTestForGeneration(test *config.TestDefinition) error {
 	return nil
 }
 
-// saveSimpleTestDefinition saves a simplified test definition
-// This avoids the circular reference issue in RuleSet.MarshalYAML
-func saveSimpleTestDefinition(testFile string, test *config.TestDefinition) error {
-	// Create a simplified structure without the Result field
-	type SimpleExpectedOutput struct {
-		File string `yaml:"file,omitempty"`
-	}
-
-	type SimpleExpectConfig struct {
-		ExitCode int                  `yaml:"exitCode"`
-		Output   SimpleExpectedOutput `yaml:"output"`
-	}
+// expectedDependenciesFile is the dependencies baseline generated next to the expected output
+const expectedDependenciesFile = "expected-dependencies.yaml"
 
-	type SimpleTestDefinition struct {
-		Name                 string                `yaml:"name"`
-		Description          string                `yaml:"description,omitempty"`
-		Analysis             config.AnalysisConfig `yaml:"analysis"`
-		Timeout              *config.Duration      `yaml:"timeout,omitempty"`
-		WorkDir              string                `yaml:"workDir,omitempty"`
-		RequireMavenSettings bool                  `yaml:"requireMavenSettings,omitempty"`
-		Expect               SimpleExpectConfig    `yaml:"expect"`
-	}
+// applicationDependenciesFile is the dependencies baseline of one application of a
+// test analyzing several
+func applicationDependenciesFile(name string) string {
+	return fmt.Sprintf("expected-dependencies-%s.yaml", name)
+}
 
-	simpleTest := SimpleTestDefinition{
-		Name:                 test.Name,
-		Description:          test.Description,
-		Analysis:             test.Analysis,
-		Timeout:              test.Timeout,
-		WorkDir:              test.WorkDir,
-		RequireMavenSettings: test.RequireMavenSettings,
-		Expect: SimpleExpectConfig{
-			ExitCode: test.Expect.ExitCode,
-			Output: SimpleExpectedOutput{
-				File: test.Expect.Output.File,
-			},
-		},
+// saveDependencies saves a target's dependencies output, normalized, as the test's
+// dependencies baseline in path. It returns the number of dependencies saved.
+func saveDependencies(depsFile, path, testDir string) (int, error) {
+	items, err := parser.ParseDependencies(depsFile)
+	if err != nil {
+		return 0, err
 	}
+	items = parser.NormalizeDependencies(items, testDir)
 
-	// Marshal the simplified test
-	updatedContent, err := yaml.Marshal(simpleTest)
+	// Use yaml.v2 like the expected output, konveyor types were designed for it
+	data, err := yaml2.Marshal(items)
 	if err != nil {
-		return fmt.Errorf("failed to marshal test: %w", err)
+		return 0, fmt.Errorf("failed to marshal dependencies: %w", err)
 	}
-
-	// Write to file
-	if err := os.WriteFile(testFile, updatedContent, 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return 0, fmt.Errorf("failed to write dependencies: %w", err)
 	}
 
-	return nil
+	count := 0
+	for _, item := range items {
+		count += len(item.Dependencies)
+	}
+	return count, nil
 }
 
 // saveFilteredOutput saves the filtered rulesets to a YAML file with path normalization
 // Uses yaml.v2 to match analyzer-lsp's marshalling behavior and avoid circular reference issues
-func saveFilteredOutput(rulesets []konveyor.RuleSet, path string, testDir string) error {
+func saveFilteredOutput(rulesets []konveyor.RuleSet, path string, testDir string, metadata *config.GenerationMetadata) error {
 	rulesets, err := parser.NormalizeRuleSets(rulesets, testDir)
 	if err != nil {
 		return err
@@ -388,6 +587,15 @@ func saveFilteredOutput(rulesets []konveyor.RuleSet, path string, testDir string
 	// Normalize paths by removing the test directory path
 	yamlStr := string(data)
 
+	// Record how the output was generated in a comment header
+	if metadata != nil {
+		header, err := metadata.Header()
+		if err != nil {
+			return err
+		}
+		yamlStr = header + yamlStr
+	}
+
 	err = os.WriteFile(path, []byte(yamlStr), 0644)
 	if err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/targets"
+)
+
+func TestGenerateFromOutput(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"first", "second"} {
+		testDir := filepath.Join(dir, "tests", name)
+		if err := os.MkdirAll(testDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		test := "name: " + name + "\nanalysis:\n  application: https://github.com/example/app.git\n  analysisMode: source-only\nexpect:\n  exitCode: 0\n  output:\n    result: []\n"
+		if err := os.WriteFile(filepath.Join(testDir, "test.yaml"), []byte(test), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	output := filepath.Join(dir, "output.yaml")
+	content := `- name: ruleset
+  violations:
+    rule-001:
+      description: Rule
+      category: mandatory
+      effort: 1
+      incidents:
+      - uri: file:///opt/input/source/src/Main.java
+        message: Found
+        lineNumber: 3
+- name: empty
+`
+	if err := os.WriteFile(output, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	generate := func(args ...string) error {
+		cmd := NewGenerateCmd()
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		cmd.SetArgs(args)
+		return cmd.Execute()
+	}
+
+	if err := generate(filepath.Join(dir, "tests"), "--from-output", output); err == nil || exitCodeFor(err) != ExitCodeConfigError {
+		t.Errorf("expected a config error when several tests are selected, got %v", err)
+	}
+
+	if err := generate(filepath.Join(dir, "tests", "first"), "--from-output", output); err != nil {
+		t.Fatal(err)
+	}
+	test, err := config.Load(filepath.Join(dir, "tests", "first", "test.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if test.Expect.Output.File != "expected-output.yaml" {
+		t.Errorf("expected the test to use expected-output.yaml, got %+v", test.Expect.Output)
+	}
+	metadata, err := config.ReadGenerationMetadata(filepath.Join(dir, "tests", "first", "expected-output.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata == nil || metadata.Target != "kantra" || metadata.Application != "https://github.com/example/app.git" || metadata.ToolVersion != "" {
+		t.Errorf("unexpected generation metadata %+v", metadata)
+	}
+	if len(test.Expect.Output.Result) != 1 || test.Expect.Output.Result[0].Name != "ruleset" {
+		t.Fatalf("expected the empty ruleset to be filtered, got %+v", test.Expect.Output.Result)
+	}
+	uri := string(test.Expect.Output.Result[0].Violations["rule-001"].Incidents[0].URI)
+	if !strings.HasSuffix(uri, "/source/src/Main.java") || strings.Contains(uri, "/opt/input") {
+		t.Errorf("expected a normalized incident URI, got %s", uri)
+	}
+}
+
+func TestSaveDependencies(t *testing.T) {
+	dir := t.TempDir()
+	depsFile := filepath.Join(dir, "dependencies.yaml")
+	content := `- fileURI: file:///opt/input/source/pom.xml
+  provider: java
+  dependencies:
+  - name: junit.junit
+    version: "4.12"
+  - name: commons-io.commons-io
+    version: "2.6"
+`
+	if err := os.WriteFile(depsFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := saveDependencies(depsFile, filepath.Join(dir, expectedDependenciesFile), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 dependencies, got %d", count)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, expectedDependenciesFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := string(data)
+	if !strings.Contains(saved, "file:///source/pom.xml") || strings.Index(saved, "commons-io") > strings.Index(saved, "junit") {
+		t.Errorf("expected normalized and sorted dependencies, got:\n%s", saved)
+	}
+}
+
+func TestTestSkip_Requirements(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "test.yaml")
+	test := "name: dotnet\nrequires:\n  providers: [dotnet]\nanalysis:\n  application: ./app\n"
+	if err := os.WriteFile(testFile, []byte(test), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !requireProviders([]string{testFile}) {
+		t.Error("expected the test to require providers")
+	}
+
+	tests := []struct {
+		name      string
+		providers []string
+		want      string
+		skipped   bool
+	}{
+		{name: "unknown providers"},
+		{name: "provider available", providers: []string{"java", "dotnet"}},
+		{name: "provider missing", providers: []string{"java"}, want: "requires providers dotnet, which kantra does not have", skipped: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, skipped := testSkip(testFile, config.SkipEnvironment{TargetType: "kantra", Providers: tt.providers})
+			if reason != tt.want || skipped != tt.skipped {
+				t.Errorf("testSkip() = %q, %v, want %q, %v", reason, skipped, tt.want, tt.skipped)
+			}
+		})
+	}
+}
+
+func TestWriteGeneratedApplicationOutputs(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "test.yaml")
+	content := "name: apps\nanalysis:\n  applications:\n  - name: coolstore\n    application: ./coolstore\n  - name: daytrader\n    application: ./daytrader\n  analysisMode: full\n"
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	test, err := config.Load(testFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only coolstore's analysis listed dependencies
+	result := &targets.ExecutionResult{}
+	for _, name := range []string{"coolstore", "daytrader"} {
+		outputDir := filepath.Join(dir, "work", name)
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		output := targets.ApplicationOutput{Name: name, OutputFile: filepath.Join(outputDir, "output.yaml")}
+		if err := os.WriteFile(output.OutputFile, []byte("- name: "+name+"\n  tags: [Java]\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if name == "coolstore" {
+			output.DependenciesFile = filepath.Join(outputDir, "dependencies.yaml")
+			deps := "- provider: java\n  dependencies:\n  - name: junit.junit\n    version: \"4.12\"\n"
+			if err := os.WriteFile(output.DependenciesFile, []byte(deps), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		result.Applications = append(result.Applications, output)
+	}
+
+	if err := writeGeneratedApplicationOutputs(testFile, test, result, &config.TargetConfig{Type: "kantra"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	deps, err := os.ReadFile(filepath.Join(dir, applicationDependenciesFile("coolstore")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(deps), "junit.junit") {
+		t.Errorf("expected the dependencies of coolstore, got:\n%s", deps)
+	}
+	if _, err := os.Stat(filepath.Join(dir, applicationDependenciesFile("daytrader"))); !os.IsNotExist(err) {
+		t.Errorf("expected no dependencies baseline of daytrader, got %v", err)
+	}
+	for _, name := range []string{"coolstore", "daytrader"} {
+		if _, err := os.Stat(filepath.Join(dir, "expected-output-"+name+".yaml")); err != nil {
+			t.Errorf("expected the output of %s: %v", name, err)
+		}
+	}
+}
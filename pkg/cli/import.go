@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/importer"
+	"github.com/konveyor/test-harness/pkg/ruletest"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	importOutputDir string
+	importForce     bool
+)
+
+// NewImportCmd creates the import command
+func NewImportCmd() *cobra.Command {
+	importCmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import tests from other test suites",
+	}
+
+	goKonveyorCmd := &cobra.Command{
+		Use:   "go-konveyor-tests <path>...",
+		Short: "Import the test cases of go-konveyor-tests",
+		Long: `Convert the TC test cases of go-konveyor-tests into koncur tests. Give the
+directories (or files) with the test cases and the data they reference, usually
+the analysis and data packages of a go-konveyor-tests checkout:
+
+  koncur import go-konveyor-tests ../go-konveyor-tests/analysis ../go-konveyor-tests/data
+
+Every test case becomes <output>/<name>/test.yaml with an expected-output.yaml built
+from its expected issues and tags. The Go sources are read, not compiled: test cases
+built by function calls are not found. Test cases listed in slices such as
+Tier0TestCases are tagged "tier0", and parts that cannot be converted (uploaded
+custom rules, identities, binaries) are reported and noted in the test file.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, path := range args {
+				if _, err := os.Stat(path); err != nil {
+					return configError("%w", err)
+				}
+			}
+			cases, err := importer.ImportGoKonveyorTests(args...)
+			if err != nil {
+				return configError("failed to read go-konveyor-tests: %w", err)
+			}
+			if len(cases) == 0 {
+				return configError("no TC test cases found in %s", strings.Join(args, ", "))
+			}
+
+			written := 0
+			for _, tc := range cases {
+				ok, err := writeImportedTest(importOutputDir, goKonveyorTest(tc), importForce)
+				if err != nil {
+					return err
+				}
+				if ok {
+					written++
+				}
+			}
+			fmt.Printf("\nImported %d of %d test case(s) into %s\n", written, len(cases), importOutputDir)
+			return nil
+		},
+	}
+	goKonveyorCmd.Flags().StringVarP(&importOutputDir, "output", "o", "tests", "Directory to write the tests to")
+	goKonveyorCmd.Flags().BoolVar(&importForce, "force", false, "Overwrite existing tests")
+
+	ruleTestCmd := &cobra.Command{
+		Use:   "rule-test <tests-file>...",
+		Short: "Import kantra rule tests (*.test.yaml)",
+		Long: `Convert the rule tests run by 'kantra test' into koncur tests, one per tests
+file and set of analysis parameters:
+
+  koncur import rule-test rules/java/*.test.yaml
+
+The test analyzes the provider's dataPath with the rules under test (rulesPath, or
+the rules file next to the tests file) and the default rulesets disabled. Incident
+checks become incidentCount expectations described from the rules, hasTags become
+expected tags and isUnmatched test cases become absent rules. The application and
+rules are referenced by absolute path; checks that cannot be converted (message and
+code snippet patterns, incident locations) are reported and noted in the test file.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var tests []ruletest.ImportedTest
+			for _, path := range args {
+				imported, err := ruletest.Import(path)
+				if err != nil {
+					return configError("%w", err)
+				}
+				tests = append(tests, imported...)
+			}
+
+			written := 0
+			for _, test := range tests {
+				ok, err := writeImportedTest(importOutputDir, ruleTest(test), importForce)
+				if err != nil {
+					return err
+				}
+				if ok {
+					written++
+				}
+			}
+			fmt.Printf("\nImported %d of %d test(s) into %s\n", written, len(tests), importOutputDir)
+			return nil
+		},
+	}
+	ruleTestCmd.Flags().StringVarP(&importOutputDir, "output", "o", "tests", "Directory to write the tests to")
+	ruleTestCmd.Flags().BoolVar(&importForce, "force", false, "Overwrite existing tests")
+
+	importCmd.AddCommand(goKonveyorCmd)
+	importCmd.AddCommand(ruleTestCmd)
+	return importCmd
+}
+
+// importedTest is a test converted from another test suite
+type importedTest struct {
+	// dir is the directory of the test under the output directory
+	dir  string
+	test *config.TestDefinition
+	// comments are follow-ups written at the top of test.yaml
+	comments []string
+	warnings []string
+	// writeExpected writes the expected output file
+	writeExpected func(path string) error
+}
+
+// goKonveyorTest adapts a go-konveyor-tests test case
+func goKonveyorTest(tc importer.TestCase) importedTest {
+	imported := importedTest{dir: tc.Dir, test: tc.Test, warnings: tc.Warnings}
+	if tc.Skip {
+		imported.comments = append(imported.comments, "SKIPPED: skipped in go-konveyor-tests")
+	}
+	for _, warning := range tc.Warnings {
+		imported.comments = append(imported.comments, "TODO: "+warning)
+	}
+	imported.writeExpected = func(path string) error {
+		return saveFilteredOutput(tc.Expected, path, filepath.Dir(path), nil)
+	}
+	return imported
+}
+
+// ruleTest adapts a test imported from rule tests
+func ruleTest(test ruletest.ImportedTest) importedTest {
+	imported := importedTest{dir: test.Dir, test: test.Test, warnings: test.Warnings}
+	for _, warning := range test.Warnings {
+		imported.comments = append(imported.comments, "TODO: "+warning)
+	}
+	imported.writeExpected = func(path string) error {
+		data, err := yaml.Marshal(test.Expected)
+		if err != nil {
+			return fmt.Errorf("failed to marshal rulesets: %w", err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+		return nil
+	}
+	return imported
+}
+
+// writeImportedTest writes the test definition and expected output of an imported
+// test, and reports whether it was written
+func writeImportedTest(outputDir string, imported importedTest, force bool) (bool, error) {
+	dir := filepath.Join(outputDir, imported.dir)
+	testFile := filepath.Join(dir, "test.yaml")
+	if _, err := os.Stat(testFile); err == nil && !force {
+		color.Yellow("%s Kept existing %s (use --force to overwrite)", symbolWarn, testFile)
+		return false, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return false, fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	data, err := yaml.Marshal(imported.test)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal test %s: %w", imported.dir, err)
+	}
+	// Follow-ups are kept as comments so they are not lost after the import
+	var header strings.Builder
+	for _, comment := range imported.comments {
+		fmt.Fprintf(&header, "# %s\n", comment)
+	}
+	if err := os.WriteFile(testFile, append([]byte(header.String()), data...), 0644); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", testFile, err)
+	}
+	if err := imported.writeExpected(filepath.Join(dir, imported.test.Expect.Output.File)); err != nil {
+		return false, fmt.Errorf("failed to write expected output of %s: %w", imported.dir, err)
+	}
+
+	color.Green("%s Imported %s (%s)", symbolPass, testFile, imported.test.Description)
+	for _, warning := range imported.warnings {
+		color.Yellow("    %s %s", symbolWarn, warning)
+	}
+	return true, nil
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/importer"
+)
+
+func TestWriteImportedTest(t *testing.T) {
+	dir := t.TempDir()
+	tc := importer.TestCase{
+		Dir: "daytrader",
+		Test: &config.TestDefinition{
+			Name:        "daytrader",
+			Description: "Daytrader",
+			Analysis:    config.AnalysisConfig{Application: "https://github.com/example/daytrader"},
+			Expect:      config.ExpectConfig{Output: config.ExpectedOutput{File: "expected-output.yaml"}},
+		},
+		Expected: []konveyor.RuleSet{{Name: "technology-usage", Tags: []string{"EJB"}}},
+		Skip:     true,
+		Warnings: []string{"custom rules were not imported"},
+	}
+
+	written, err := writeImportedTest(dir, goKonveyorTest(tc), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !written {
+		t.Fatal("expected the test to be written")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "daytrader", "test.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# SKIPPED: skipped in go-konveyor-tests\n", "# TODO: custom rules were not imported\n", "name: daytrader"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("test.yaml does not contain %q:\n%s", want, data)
+		}
+	}
+	if _, err := config.Load(filepath.Join(dir, "daytrader", "test.yaml")); err != nil {
+		t.Errorf("imported test does not load: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "daytrader", "expected-output.yaml")); err != nil {
+		t.Errorf("expected output not written: %v", err)
+	}
+
+	// Existing tests are kept unless forced
+	written, err = writeImportedTest(dir, goKonveyorTest(tc), false)
+	if err != nil || written {
+		t.Errorf("expected existing test to be kept, written=%v err=%v", written, err)
+	}
+	written, err = writeImportedTest(dir, goKonveyorTest(tc), true)
+	if err != nil || !written {
+		t.Errorf("expected forced overwrite, written=%v err=%v", written, err)
+	}
+}
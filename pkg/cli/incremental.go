@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/konveyor/test-harness/pkg/config"
+)
+
+// incrementalSelection keeps the tests to regenerate: with missingOnly, tests without
+// an expected output; with changedSince, tests whose inputs changed since that git ref.
+// A test is kept when either applies. Without both, all tests are kept.
+func incrementalSelection(testFiles []string, missingOnly bool, changedSince string) ([]string, error) {
+	if !missingOnly && changedSince == "" {
+		return testFiles, nil
+	}
+
+	var changed []string
+	if changedSince != "" {
+		var err error
+		if changed, err = changedFiles(changedSince); err != nil {
+			return nil, configError("%w", err)
+		}
+	}
+
+	var selected []string
+	for _, testFile := range testFiles {
+		test, err := config.LoadWithOptions(testFile, true)
+		if err != nil {
+			// Broken tests are kept so generate reports them
+			selected = append(selected, testFile)
+			continue
+		}
+		if missingOnly && !hasExpectedOutput(test) {
+			selected = append(selected, testFile)
+			continue
+		}
+		if changedSince != "" && inputsChanged(test, changed) {
+			selected = append(selected, testFile)
+		}
+	}
+	return selected, nil
+}
+
+// expectedOutputFiles returns the absolute paths of a test's expected output files,
+// those of each application for a test analyzing several
+func expectedOutputFiles(test *config.TestDefinition) []string {
+	if len(test.Analysis.Applications) == 0 {
+		return outputFiles(test, test.Expect.Output, "expected-output.yaml")
+	}
+	var paths []string
+	for _, app := range test.Analysis.Applications {
+		output := test.Expect.Applications[app.Name]
+		if len(output.Result) > 0 && output.File == "" && len(output.Files) == 0 {
+			continue
+		}
+		paths = append(paths, outputFiles(test, output, fmt.Sprintf("expected-output-%s.yaml", app.Name))...)
+	}
+	return paths
+}
+
+// outputFiles returns the absolute paths of the files of an expected output, or of
+// the file generate writes it into
+func outputFiles(test *config.TestDefinition, output config.ExpectedOutput, generated string) []string {
+	files := output.Files
+	if output.File != "" {
+		files = append([]string{output.File}, files...)
+	}
+	if len(files) == 0 {
+		files = []string{test.VariantFile(generated)}
+	}
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(test.GetTestDir(), file)
+		}
+		paths = append(paths, file)
+	}
+	return paths
+}
+
+// hasExpectedOutput reports whether a test has inline expectations or all of its
+// expected output files exist
+func hasExpectedOutput(test *config.TestDefinition) bool {
+	if len(test.Expect.Output.Result) > 0 {
+		return true
+	}
+	for _, file := range expectedOutputFiles(test) {
+		if _, err := os.Stat(file); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// inputsChanged reports whether a changed file is an input of the test: a file of its
+// directory other than the expected outputs and dependencies, a base file it extends,
+// or its local application or rules
+func inputsChanged(test *config.TestDefinition, changed []string) bool {
+	inputs := append([]string{test.GetTestDir()}, test.BaseFiles()...)
+	if settings := test.MavenSettingsPath(); settings != "" {
+		inputs = append(inputs, settings)
+	}
+	local := append([]string{test.Analysis.Application}, test.Analysis.Rules...)
+	for _, app := range test.Analysis.Applications {
+		local = append(local, app.Application)
+	}
+	for _, path := range local {
+		if path == "" || strings.Contains(path, "://") || strings.HasPrefix(path, "binary:") {
+			continue
+		}
+		// Local paths are relative to the current directory, binaries to the test
+		inputs = append(inputs, absPath(path), filepath.Join(test.GetTestDir(), path))
+	}
+	outputs := append(expectedOutputFiles(test), filepath.Join(test.GetTestDir(), test.VariantFile(expectedDependenciesFile)))
+	for _, app := range test.Analysis.Applications {
+		outputs = append(outputs, filepath.Join(test.GetTestDir(), test.VariantFile(applicationDependenciesFile(app.Name))))
+	}
+
+	for _, file := range changed {
+		if containsPath(outputs, file) {
+			continue
+		}
+		for _, input := range inputs {
+			if file == input || strings.HasPrefix(file, input+string(filepath.Separator)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsPath(paths []string, path string) bool {
+	for _, p := range paths {
+		if absPath(p) == path {
+			return true
+		}
+	}
+	return false
+}
+
+// changedFiles returns the absolute paths of the files changed since a git ref:
+// committed and uncommitted changes, and untracked files
+func changedFiles(ref string) ([]string, error) {
+	root, err := gitOutput("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("--changed-since needs a git repository: %w", err)
+	}
+	root = strings.TrimSpace(root)
+
+	diff, err := gitOutput("diff", "--name-only", ref, "--")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files changed since %s: %w", ref, err)
+	}
+	untracked, err := gitOutput("ls-files", "--others", "--exclude-standard", "--full-name", root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+
+	var files []string
+	for _, line := range strings.Split(diff+"\n"+untracked, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, filepath.Join(root, filepath.FromSlash(line)))
+		}
+	}
+	return files, nil
+}
+
+func gitOutput(args ...string) (string, error) {
+	return gitOutputIn("", args...)
+}
+
+// gitOutputIn runs git in a directory, the current one when dir is empty
+func gitOutputIn(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return string(out), nil
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestIncrementalSelection(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	write := func(file, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	testYAML := func(app string) string {
+		return "name: t\nanalysis:\n  application: " + app + "\n  analysisMode: source-only\nexpect:\n  output:\n    file: expected-output.yaml\n"
+	}
+
+	write("apps/local/Main.java", "class Main {}\n")
+	write("tests/unchanged/test.yaml", testYAML("https://github.com/example/app.git"))
+	write("tests/unchanged/expected-output.yaml", "[]\n")
+	write("tests/edited/test.yaml", testYAML("https://github.com/example/app.git"))
+	write("tests/edited/expected-output.yaml", "[]\n")
+	write("tests/app-changed/test.yaml", testYAML("apps/local"))
+	write("tests/app-changed/expected-output.yaml", "[]\n")
+	write("tests/output-only/test.yaml", testYAML("https://github.com/example/app.git"))
+	write("tests/output-only/expected-output.yaml", "[]\n")
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "baseline")
+
+	write("tests/edited/test.yaml", testYAML("https://github.com/example/app.git#main"))
+	write("apps/local/Main.java", "class Main { int x; }\n")
+	write("tests/output-only/expected-output.yaml", "- name: changed\n")
+	write("tests/missing/test.yaml", testYAML("https://github.com/example/app.git"))
+
+	testFiles, err := findTestFiles("tests")
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := func(files []string) []string {
+		var names []string
+		for _, f := range files {
+			names = append(names, filepath.Base(filepath.Dir(f)))
+		}
+		return names
+	}
+
+	tests := []struct {
+		name         string
+		missingOnly  bool
+		changedSince string
+		want         []string
+	}{
+		{name: "all", want: []string{"app-changed", "edited", "missing", "output-only", "unchanged"}},
+		{name: "missing only", missingOnly: true, want: []string{"missing"}},
+		{name: "changed since", changedSince: "HEAD", want: []string{"app-changed", "edited", "missing"}},
+		{name: "both", missingOnly: true, changedSince: "HEAD", want: []string{"app-changed", "edited", "missing"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := incrementalSelection(testFiles, tt.missingOnly, tt.changedSince)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := names(selected); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selected %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := incrementalSelection(testFiles, false, "no-such-ref"); err == nil || exitCodeFor(err) != ExitCodeConfigError {
+		t.Errorf("expected a config error for an unknown ref, got %v", err)
+	}
+}
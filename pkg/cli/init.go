@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var initForce bool
+
+// scaffoldFile is a file written by 'koncur init'
+type scaffoldFile struct {
+	Path    string
+	Content string
+	Mode    os.FileMode
+}
+
+// scaffoldFiles is the layout created by 'koncur init', relative to its directory
+var scaffoldFiles = []scaffoldFile{
+	{
+		Path: "tests/example/test.yaml",
+		Mode: 0644,
+		Content: `name: example
+description: Source analysis of the tackle-testapp-public application
+
+analysis:
+  # A local path, or a git URL with an optional #branch/path
+  application: https://github.com/konveyor/tackle-testapp-public#ci-2024
+  labelSelector: "(konveyor.io/target=cloud-readiness) || (discovery)"
+  analysisMode: source-only
+
+timeout: 30m
+
+expect:
+  exitCode: 0
+  output:
+    # Capture it with: koncur generate --filter example
+    file: expected-output.yaml
+`,
+	},
+	{
+		Path: "tests/example/expected-output.yaml",
+		Mode: 0644,
+		Content: `# Expected RuleSets of tests/example, in the analyzer output.yaml format.
+# Replace this placeholder with the real output of the application:
+#   koncur generate --filter example
+[]
+`,
+	},
+	{
+		Path: ".koncur/config/target-kantra.yaml",
+		Mode: 0600,
+		Content: `type: kantra
+kantra:
+  # Optional, kantra is looked up in PATH when not set
+  # binaryPath: /usr/local/bin/kantra
+  # Optional, only needed for Maven projects requiring custom settings
+  # mavenSettings: /path/to/settings.xml
+`,
+	},
+	{
+		Path: ".koncur/config/target-tackle-hub.yaml",
+		Mode: 0600,
+		Content: `type: tackle-hub
+tackleHub:
+  url: http://localhost:8080/hub
+  # Credentials are read from the environment (or file:<path>) to keep them out of this file
+  username: admin
+  passwordFrom: env:HUB_PASSWORD
+  # tokenFrom: env:HUB_TOKEN
+`,
+	},
+	{
+		Path: ".koncur/.gitignore",
+		Mode: 0644,
+		Content: `# Run history written by 'koncur run'
+results/
+# kantra releases downloaded by 'koncur bisect' and tests fetched from git
+cache/
+`,
+	},
+}
+
+// NewInitCmd creates the init command
+func NewInitCmd() *cobra.Command {
+	initCmd := &cobra.Command{
+		Use:   "init [directory]",
+		Short: "Scaffold a tests directory and koncur configuration",
+		Long: `Lay out a ready-to-use koncur project in a directory (default: current directory):
+
+  tests/example/test.yaml              a sample test definition
+  tests/example/expected-output.yaml   a placeholder expected output to generate
+  .koncur/config/target-kantra.yaml    kantra target configuration (auto-discovered)
+  .koncur/config/target-tackle-hub.yaml  Hub target configuration
+  .koncur/.gitignore                   keeps the results store out of git
+
+Existing files are kept unless --force is given.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := "."
+			if len(args) == 1 {
+				dir = args[0]
+			}
+
+			created, err := scaffold(dir, initForce)
+			if err != nil {
+				return err
+			}
+			if created == 0 {
+				color.Yellow("%s Nothing to do, every file already exists (use --force to overwrite)", symbolWarn)
+				return nil
+			}
+
+			// Target configurations are auto-discovered relative to the working directory
+			fmt.Printf("\nNext steps:\n")
+			if filepath.Clean(dir) != "." {
+				fmt.Printf("  cd %s\n", dir)
+			}
+			fmt.Println("  koncur generate --filter example   # capture the expected output")
+			fmt.Println("  koncur run tests                   # run the tests")
+			return nil
+		},
+	}
+
+	initCmd.Flags().BoolVar(&initForce, "force", false, "Overwrite existing files")
+
+	return initCmd
+}
+
+// scaffold writes the scaffold files under dir and returns how many were written
+func scaffold(dir string, force bool) (int, error) {
+	created := 0
+	for _, file := range scaffoldFiles {
+		path := filepath.Join(dir, file.Path)
+		if _, err := os.Stat(path); err == nil && !force {
+			color.Yellow("%s Kept existing %s", symbolWarn, path)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return created, fmt.Errorf("failed to create directory %s: %w", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(file.Content), file.Mode); err != nil {
+			return created, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		color.Green("%s Created %s", symbolPass, path)
+		created++
+	}
+	return created, nil
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/konveyor/test-harness/pkg/config"
+)
+
+func TestScaffold(t *testing.T) {
+	dir := t.TempDir()
+
+	created, err := scaffold(dir, false)
+	if err != nil {
+		t.Fatalf("scaffold() error = %v", err)
+	}
+	if created != len(scaffoldFiles) {
+		t.Errorf("scaffold() created %d files, want %d", created, len(scaffoldFiles))
+	}
+
+	test, err := config.Load(filepath.Join(dir, "tests", "example", "test.yaml"))
+	if err != nil {
+		t.Fatalf("scaffolded test does not load: %v", err)
+	}
+	if err := config.Validate(test); err != nil {
+		t.Errorf("scaffolded test is invalid: %v", err)
+	}
+	t.Setenv("HUB_PASSWORD", "secret")
+	for _, target := range []string{"kantra", "tackle-hub"} {
+		path := filepath.Join(dir, ".koncur", "config", "target-"+target+".yaml")
+		if _, err := config.LoadTargetConfig(path); err != nil {
+			t.Errorf("scaffolded %s target config does not load: %v", target, err)
+		}
+	}
+
+	// Existing files are kept unless forced
+	testFile := filepath.Join(dir, "tests", "example", "test.yaml")
+	if err := os.WriteFile(testFile, []byte("name: edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if created, err := scaffold(dir, false); err != nil || created != 0 {
+		t.Errorf("scaffold() over an existing layout = %d, %v, want 0, nil", created, err)
+	}
+	if data, _ := os.ReadFile(testFile); string(data) != "name: edited\n" {
+		t.Error("scaffold() overwrote an existing file without force")
+	}
+	if created, err := scaffold(dir, true); err != nil || created != len(scaffoldFiles) {
+		t.Errorf("scaffold() with force = %d, %v, want %d, nil", created, err, len(scaffoldFiles))
+	}
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/targets"
+	"github.com/konveyor/test-harness/pkg/validator"
+)
+
+// runListLabelsTest lists the source and target labels of a list-labels test's
+// rulesets and validates them against its expected labels
+func runListLabelsTest(testResult *TestResult, test *config.TestDefinition, target targets.Target) (*TestResult, error) {
+	lister, ok := target.(targets.LabelLister)
+	if !ok {
+		testResult.Status = "failed"
+		testResult.ErrorMessage = fmt.Sprintf("target %s cannot list labels", target.Name())
+		testResult.FailureKind = FailureConfig
+		return testResult, fmt.Errorf("target %s cannot list labels", target.Name())
+	}
+
+	start := time.Now()
+	labels, err := lister.ListLabels(context.Background(), test)
+	if err != nil {
+		testResult.Status = "failed"
+		testResult.ErrorMessage = fmt.Sprintf("execution failed: %v", err)
+		testResult.FailureKind = FailureExecution
+		return testResult, fmt.Errorf("execution failed: %w", err)
+	}
+	testResult.WorkDir = labels.WorkDir
+
+	validation := validator.ValidateLabels(test.Expect.Labels, labels.Sources, labels.Targets)
+	testResult.ValidationErrors = validation.Errors
+	if validation.Passed {
+		testResult.Status = "passed"
+		if showProgress() {
+			green := color.New(color.FgGreen, color.Bold)
+			green.Printf("  %s PASSED", symbolPass)
+			fmt.Printf(" - Duration: %s, Sources: %d, Targets: %d\n", time.Since(start), len(labels.Sources), len(labels.Targets))
+		}
+		return testResult, nil
+	}
+
+	testResult.Status = "failed"
+	testResult.FailureKind = FailureValidation
+	if showProgress() {
+		red := color.New(color.FgRed, color.Bold)
+		red.Printf("  %s FAILED\n", symbolFail)
+		fmt.Printf("\n    Found %d validation error(s):\n\n", len(validation.Errors))
+		for i, err := range validation.Errors {
+			err.Print(i + 1)
+		}
+		fmt.Println()
+	}
+	return testResult, nil
+}
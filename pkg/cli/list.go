@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listJSON        bool
+	listTags        []string
+	listExcludeTags []string
+)
+
+// Where the expected output of a listed test comes from
+const (
+	expectedFile    = "file"
+	expectedInline  = "inline"
+	expectedMissing = "missing"
+	expectedNone    = "none"
+)
+
+// testListing describes a discovered test
+type testListing struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	File        string   `json:"file"`
+	Tags        []string `json:"tags,omitempty"`
+	Application string   `json:"application"`
+	Mode        string   `json:"mode"`
+	Skipped     bool     `json:"skipped"`
+	SkipReason  string   `json:"skipReason,omitempty"`
+	SkipWhen    []string `json:"skipWhen,omitempty"`
+	Expected    string   `json:"expected"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// NewListCmd creates the list command
+func NewListCmd() *cobra.Command {
+	listCmd := &cobra.Command{
+		Use:   "list [test-directory]",
+		Short: "List the tests in a directory",
+		Long: `List every test.yaml found in a directory (default: tests) with its name,
+description, application, analysis mode, skip status and whether its expected
+output exists: "file" (an expected output file that exists), "inline", "missing"
+(a referenced file that does not exist) or "none". --tags and --exclude-tags
+list the tests of a group, e.g. the tier0 tests except the slow ones.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			testDir := defaultTestDir()
+			if len(args) == 1 {
+				testDir = args[0]
+			}
+			if _, err := os.Stat(testDir); err != nil {
+				return configError("%w", err)
+			}
+
+			testFiles, err := findTestFiles(testDir)
+			if err != nil {
+				return fmt.Errorf("failed to find test files: %w", err)
+			}
+			selector, err := newTestSelector(nil, nil, listTags, listExcludeTags)
+			if err != nil {
+				return err
+			}
+			if testFiles, err = selector.selected(testFiles); err != nil {
+				return err
+			}
+
+			listings := make([]testListing, 0, len(testFiles))
+			for _, testFile := range testFiles {
+				listings = append(listings, listTest(testFile))
+			}
+
+			if listJSON {
+				data, err := json.MarshalIndent(listings, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal tests: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+			printTestListings(listings)
+			return nil
+		},
+	}
+
+	listCmd.Flags().BoolVar(&listJSON, "json", false, "Print tests as JSON")
+	listCmd.Flags().StringSliceVar(&listTags, "tags", nil, "Only list tests with any of these tags (comma-separated or repeatable)")
+	listCmd.Flags().StringSliceVar(&listExcludeTags, "exclude-tags", nil, "Skip tests with any of these tags (comma-separated or repeatable)")
+
+	return listCmd
+}
+
+// listTest describes a test file; tests that fail to load are listed with the error
+func listTest(testFile string) testListing {
+	listing := testListing{File: testFile, Name: resultName(testFile)}
+	listing.SkipReason, listing.Skipped = testSkip(testFile, config.SkipEnvironment{})
+
+	test, err := config.LoadWithOptions(testFile, true)
+	if err != nil {
+		listing.Error = err.Error()
+		listing.Expected = expectedNone
+		return listing
+	}
+	listing.Name = test.Name
+	listing.Description = test.Description
+	listing.Tags = test.Tags
+	if test.Skip != nil && listing.Skipped {
+		listing.SkipWhen = test.Skip.Conditions()
+	}
+	listing.Application = test.Analysis.Application
+	if test.Analysis.ApplicationAlias != "" {
+		listing.Application = config.ApplicationAliasPrefix + test.Analysis.ApplicationAlias
+	}
+	if len(test.Analysis.Applications) > 0 {
+		names := make([]string, len(test.Analysis.Applications))
+		for i, app := range test.Analysis.Applications {
+			names[i] = app.Name
+		}
+		listing.Application = strings.Join(names, ",")
+	}
+	listing.Mode = string(test.Analysis.AnalysisMode)
+	listing.Expected = expectedOutputStatus(test)
+	return listing
+}
+
+// expectedOutputStatus reports whether a test's expected output exists. A test
+// analyzing several applications reports the worst status of their outputs.
+func expectedOutputStatus(test *config.TestDefinition) string {
+	if len(test.Analysis.Applications) == 0 {
+		return outputStatus(test, test.Expect.Output)
+	}
+	status := expectedFile
+	for _, app := range test.Analysis.Applications {
+		switch appStatus := outputStatus(test, test.Expect.Applications[app.Name]); {
+		case appStatus == expectedMissing || appStatus == expectedNone:
+			return appStatus
+		case appStatus == expectedInline:
+			status = expectedInline
+		}
+	}
+	return status
+}
+
+// outputStatus reports whether an expected output exists
+func outputStatus(test *config.TestDefinition, output config.ExpectedOutput) string {
+	files := output.Files
+	if output.File != "" {
+		files = append([]string{output.File}, files...)
+	}
+	if len(files) == 0 {
+		if len(output.Result) > 0 {
+			return expectedInline
+		}
+		return expectedNone
+	}
+	for _, file := range files {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(test.GetTestDir(), file)
+		}
+		if _, err := os.Stat(file); err != nil {
+			return expectedMissing
+		}
+	}
+	return expectedFile
+}
+
+// skipMarker returns the reason of a "# SKIPPED: reason" marker, and whether
+// the test is marked as skipped (see isTestSkipped)
+func skipMarker(testFile string) (string, bool) {
+	if !isTestSkipped(testFile) {
+		return "", false
+	}
+	path, _ := config.SplitTestRef(testFile)
+	f, err := os.Open(path)
+	if err != nil {
+		return "", true
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if _, reason, ok := strings.Cut(scanner.Text(), "SKIPPED:"); ok {
+			return strings.TrimSpace(reason), true
+		}
+	}
+	return "", true
+}
+
+func printTestListings(listings []testListing) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tAPPLICATION\tMODE\tTAGS\tEXPECTED\tSKIPPED\tDESCRIPTION")
+	for _, l := range listings {
+		skipped := "-"
+		if l.Skipped {
+			skipped = "yes"
+			if l.SkipReason != "" {
+				skipped = l.SkipReason
+			}
+			if len(l.SkipWhen) > 0 {
+				skipped += " (" + strings.Join(l.SkipWhen, ", ") + ")"
+			}
+		}
+		description := l.Description
+		if l.Error != "" {
+			description = "invalid: " + l.Error
+		}
+		tags := "-"
+		if len(l.Tags) > 0 {
+			tags = strings.Join(l.Tags, ",")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", l.Name, l.Application, l.Mode, tags, l.Expected, skipped, firstLine(description))
+	}
+	w.Flush()
+	fmt.Printf("\n%d test(s)\n", len(listings))
+}
+
+// firstLine returns the first line of a possibly multi-line text
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return line
+}
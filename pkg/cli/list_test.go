@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestListTest(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		files    map[string]string
+		want     testListing
+		wantLoad bool
+	}{
+		{
+			name: "expected output file",
+			content: `name: with-file
+description: |
+  First line
+  Second line
+analysis:
+  application: app.war
+  analysisMode: full
+expect:
+  output:
+    file: expected-output.yaml
+`,
+			files:    map[string]string{"expected-output.yaml": "[]\n"},
+			want:     testListing{Name: "with-file", Description: "First line\nSecond line\n", Application: "app.war", Mode: "full", Expected: expectedFile},
+			wantLoad: true,
+		},
+		{
+			name: "missing expected output file",
+			content: `name: missing
+analysis:
+  application: app.war
+  analysisMode: source-only
+expect:
+  output:
+    file: expected-output.yaml
+`,
+			want:     testListing{Name: "missing", Application: "app.war", Mode: "source-only", Expected: expectedMissing},
+			wantLoad: true,
+		},
+		{
+			name: "skipped inline",
+			content: `# SKIPPED: Flaky, see issue 12
+name: skipped
+tags: [tier0, slow]
+analysis:
+  application: app.war
+  analysisMode: full
+expect:
+  output:
+    result:
+      - name: rs
+        tags: [Java]
+`,
+			want:     testListing{Name: "skipped", Tags: []string{"tier0", "slow"}, Application: "app.war", Mode: "full", Expected: expectedInline, Skipped: true, SkipReason: "Flaky, see issue 12"},
+			wantLoad: true,
+		},
+		{
+			name: "skipped on a target",
+			content: `name: skip-hub
+skip:
+  reason: Hub drops binary incidents
+  issue: MTA-5588
+  targets: [tackle-hub]
+analysis:
+  application: app.war
+  analysisMode: full
+`,
+			want:     testListing{Name: "skip-hub", Application: "app.war", Mode: "full", Expected: expectedNone, Skipped: true, SkipReason: "Hub drops binary incidents (MTA-5588)", SkipWhen: []string{"target tackle-hub"}},
+			wantLoad: true,
+		},
+		{
+			name:    "invalid",
+			content: "name: [",
+			want:    testListing{Expected: expectedNone},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "case")
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			testFile := filepath.Join(dir, "test.yaml")
+			if err := os.WriteFile(testFile, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got := listTest(testFile)
+			if (got.Error == "") != tt.wantLoad {
+				t.Fatalf("listTest() error = %q, want loaded %v", got.Error, tt.wantLoad)
+			}
+			if !tt.wantLoad {
+				if got.Name != "case" {
+					t.Errorf("expected the directory name for a test that failed to load, got %q", got.Name)
+				}
+				return
+			}
+			got.File = ""
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("listTest() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
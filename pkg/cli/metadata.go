@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/util"
+	"github.com/konveyor/test-harness/pkg/validator"
+)
+
+// koncurVersion returns the version koncur was built from, "(devel)" for local builds
+func koncurVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
+
+// detectToolVersion returns the version of the tool a target runs, or "" when it is
+// unknown. Only kantra reports its version; other targets rely on --tool-version.
+func detectToolVersion(targetConfig *config.TargetConfig) string {
+	if targetConfig.Type != "kantra" {
+		return ""
+	}
+	binary := "kantra"
+	if targetConfig.Kantra != nil && targetConfig.Kantra.BinaryPath != "" {
+		binary = targetConfig.Kantra.BinaryPath
+	}
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return ""
+	}
+	out, err := runDoctorCommand(context.Background(), path, "version")
+	if err != nil {
+		util.GetLogger().Info("Failed to detect kantra version", "error", err)
+		return ""
+	}
+	return parseToolVersion(out)
+}
+
+// parseToolVersion extracts the version from version command output, e.g. "version: v0.7.0"
+func parseToolVersion(out string) string {
+	for _, line := range strings.Split(out, "\n") {
+		if version, ok := strings.CutPrefix(strings.TrimSpace(line), "version:"); ok {
+			return strings.TrimSpace(version)
+		}
+	}
+	return firstLine(out)
+}
+
+// generationMetadata describes the generation of a test's expected output
+func generationMetadata(test *config.TestDefinition, targetType, toolVersion string) *config.GenerationMetadata {
+	return &config.GenerationMetadata{
+		Koncur:      koncurVersion(),
+		Target:      targetType,
+		ToolVersion: toolVersion,
+		Date:        time.Now().UTC().Format(time.RFC3339),
+		Application: test.Analysis.Application,
+	}
+}
+
+// baselineWarnings warns when a test's expected output files were generated by a
+// different target or tool version than the one that produced the actual output
+func baselineWarnings(test *config.TestDefinition, targetType, toolVersion string) []validator.ValidationError {
+	var warnings []validator.ValidationError
+	for _, file := range test.Expect.Output.ResolvedFilePaths {
+		metadata, err := config.ReadGenerationMetadata(file)
+		if err != nil || metadata == nil {
+			continue
+		}
+		for _, difference := range metadata.Differences(targetType, toolVersion) {
+			warnings = append(warnings, validator.ValidationError{
+				Code:    validator.CodeBaselineMismatch,
+				Path:    file,
+				Message: fmt.Sprintf("Expected output %s, regenerate it if the differences are expected", difference),
+			})
+		}
+	}
+	return warnings
+}
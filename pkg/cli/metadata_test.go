@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/validator"
+)
+
+func TestParseToolVersion(t *testing.T) {
+	tests := map[string]string{
+		"version: v0.7.0\nSHA: abc123\nimage: quay.io/konveyor/kantra:v0.7.0": "v0.7.0",
+		"kantra 0.6.1\n": "kantra 0.6.1",
+		"":               "",
+	}
+	for out, want := range tests {
+		if got := parseToolVersion(out); got != want {
+			t.Errorf("parseToolVersion(%q) = %q, want %q", out, got, want)
+		}
+	}
+}
+
+func TestBaselineWarnings(t *testing.T) {
+	dir := t.TempDir()
+	test := &config.TestDefinition{Analysis: config.AnalysisConfig{Application: "app"}}
+	header, err := generationMetadata(test, "kantra", "v0.7.0").Header()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "expected-output.yaml")
+	if err := os.WriteFile(path, []byte(header+"[]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	test.Expect.Output.ResolvedFilePaths = []string{path}
+
+	if warnings := baselineWarnings(test, "kantra", "v0.7.0"); len(warnings) != 0 {
+		t.Errorf("expected no warnings for the same version, got %+v", warnings)
+	}
+	warnings := baselineWarnings(test, "kantra", "v0.8.0")
+	if len(warnings) != 1 || warnings[0].Code != validator.CodeBaselineMismatch || !warnings[0].Code.IsWarning() {
+		t.Errorf("expected a BASELINE_MISMATCH warning, got %+v", warnings)
+	}
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// metricsPushTimeout bounds how long a run waits on the Pushgateway
+const metricsPushTimeout = 30 * time.Second
+
+// FormatPrometheusMetrics renders a run in the Prometheus text exposition format.
+// Extra labels (e.g. tool version, CI job) are attached to every sample.
+func FormatPrometheusMetrics(run *RunRecord, extraLabels map[string]string) string {
+	base := map[string]string{"target": run.Target}
+	if run.ToolVersion != "" {
+		base["tool_version"] = run.ToolVersion
+	}
+	for k, v := range extraLabels {
+		base[k] = v
+	}
+
+	var sb strings.Builder
+	summary := run.Summary
+
+	sb.WriteString("# HELP koncur_tests Number of tests in the run by status.\n")
+	sb.WriteString("# TYPE koncur_tests gauge\n")
+	for _, status := range []struct {
+		name  string
+		count int
+	}{{"passed", summary.Passed}, {"failed", summary.Failed}, {"flaky", summary.Flaky}, {"skipped", summary.Skipped}} {
+		fmt.Fprintf(&sb, "koncur_tests%s %d\n", promLabels(base, "status", status.name), status.count)
+	}
+
+	sb.WriteString("# HELP koncur_run_duration_seconds Wall-clock duration of the run.\n")
+	sb.WriteString("# TYPE koncur_run_duration_seconds gauge\n")
+	fmt.Fprintf(&sb, "koncur_run_duration_seconds%s %s\n", promLabels(base), promSeconds(summary.Duration))
+
+	sb.WriteString("# HELP koncur_run_timestamp_seconds Unix time the run started.\n")
+	sb.WriteString("# TYPE koncur_run_timestamp_seconds gauge\n")
+	fmt.Fprintf(&sb, "koncur_run_timestamp_seconds%s %d\n", promLabels(base), run.StartedAt.Unix())
+
+	sb.WriteString("# HELP koncur_test_passed Whether a test passed (1), failed (0) or was skipped (-1).\n")
+	sb.WriteString("# TYPE koncur_test_passed gauge\n")
+	for _, result := range summary.Tests {
+		value := 0
+		switch result.Status {
+		case "passed":
+			value = 1
+		case "skipped":
+			value = -1
+		}
+		fmt.Fprintf(&sb, "koncur_test_passed%s %d\n", promLabels(base, "test", result.Name), value)
+	}
+
+	sb.WriteString("# HELP koncur_test_duration_seconds Duration of each test.\n")
+	sb.WriteString("# TYPE koncur_test_duration_seconds gauge\n")
+	for _, result := range summary.Tests {
+		fmt.Fprintf(&sb, "koncur_test_duration_seconds%s %s\n", promLabels(base, "test", result.Name), promSeconds(result.Duration))
+	}
+
+	sb.WriteString("# HELP koncur_test_validation_errors Number of validation errors per test.\n")
+	sb.WriteString("# TYPE koncur_test_validation_errors gauge\n")
+	for _, result := range summary.Tests {
+		fmt.Fprintf(&sb, "koncur_test_validation_errors%s %d\n", promLabels(base, "test", result.Name), len(result.ValidationErrors))
+	}
+
+	byCode := map[string]int{}
+	for _, result := range summary.Tests {
+		for _, verr := range result.ValidationErrors {
+			if verr.Code != "" {
+				byCode[string(verr.Code)]++
+			}
+		}
+	}
+	if len(byCode) > 0 {
+		sb.WriteString("# HELP koncur_validation_errors_by_code Number of validation errors in the run by error code.\n")
+		sb.WriteString("# TYPE koncur_validation_errors_by_code gauge\n")
+		codes := make([]string, 0, len(byCode))
+		for code := range byCode {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		for _, code := range codes {
+			fmt.Fprintf(&sb, "koncur_validation_errors_by_code%s %d\n", promLabels(base, "code", code), byCode[code])
+		}
+	}
+
+	return sb.String()
+}
+
+// PushMetrics replaces the metrics of the given job on a Prometheus Pushgateway
+func PushMetrics(gatewayURL, job, body string) error {
+	endpoint := fmt.Sprintf("%s/metrics/job/%s", strings.TrimSuffix(gatewayURL, "/"), url.PathEscape(job))
+	req, err := http.NewRequest(http.MethodPut, endpoint, bytes.NewBufferString(body))
+	if err != nil {
+		return fmt.Errorf("failed to create metrics request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+
+	client := &http.Client{Timeout: metricsPushTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push metrics: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("pushgateway returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// promLabels renders base labels plus extra key/value pairs as a sorted Prometheus label set
+func promLabels(base map[string]string, extra ...string) string {
+	labels := make(map[string]string, len(base)+len(extra)/2)
+	for k, v := range base {
+		labels[k] = v
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		labels[extra[i]] = extra[i+1]
+	}
+	if len(labels) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(labels[k])
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, k, v))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// promSeconds converts a duration string to seconds, reporting 0 when it cannot be parsed
+func promSeconds(duration string) string {
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return "0"
+	}
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatPrometheusMetrics(t *testing.T) {
+	run := NewRunRecord(time.Unix(1700000000, 0), "kantra", "v0.8.0", sampleSummary())
+	out := FormatPrometheusMetrics(run, map[string]string{"job_name": "nightly"})
+
+	for _, want := range []string{
+		`koncur_tests{job_name="nightly",status="passed",target="kantra",tool_version="v0.8.0"} 1`,
+		`koncur_tests{job_name="nightly",status="failed",target="kantra",tool_version="v0.8.0"} 1`,
+		`koncur_run_duration_seconds{job_name="nightly",target="kantra",tool_version="v0.8.0"} 90.000`,
+		`koncur_test_duration_seconds{job_name="nightly",target="kantra",test="passing",tool_version="v0.8.0"} 45.000`,
+		`koncur_test_passed{job_name="nightly",target="kantra",test="skipped",tool_version="v0.8.0"} -1`,
+		`koncur_test_validation_errors{job_name="nightly",target="kantra",test="failing",tool_version="v0.8.0"} 3`,
+		`koncur_validation_errors_by_code{code="MISSING_INCIDENT",job_name="nightly",target="kantra",tool_version="v0.8.0"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected metrics to contain %q\n%s", want, out)
+		}
+	}
+}
+
+func TestPushMetrics(t *testing.T) {
+	var gotPath, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	if err := PushMetrics(server.URL+"/", "koncur", "koncur_tests 1\n"); err != nil {
+		t.Fatalf("PushMetrics returned error: %v", err)
+	}
+	if gotPath != "/metrics/job/koncur" {
+		t.Errorf("Unexpected push path: %s", gotPath)
+	}
+	if gotBody != "koncur_tests 1\n" {
+		t.Errorf("Unexpected push body: %q", gotBody)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad metrics", http.StatusBadRequest)
+	}))
+	defer failing.Close()
+	if err := PushMetrics(failing.URL, "koncur", "x"); err == nil {
+		t.Error("Expected error for non-2xx response")
+	}
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/konveyor/test-harness/pkg/util"
+)
+
+// progressBarWidth is the number of cells of the progress bar
+const progressBarWidth = 20
+
+// progress tracks a multi-test run and estimates its remaining time from the
+// durations recorded in the results store, or the tests run so far
+type progress struct {
+	tests   []string
+	history map[string]time.Duration
+	// scale multiplies estimates, e.g. for --repeat
+	scale int
+
+	started   time.Time
+	current   int
+	lastStart time.Time
+	observed  []time.Duration
+}
+
+func newProgress(testFiles []string, history map[string]time.Duration, scale int) *progress {
+	if scale < 1 {
+		scale = 1
+	}
+	return &progress{tests: testFiles, history: history, scale: scale, current: -1}
+}
+
+// start records that test i starts and returns its progress line
+func (p *progress) start(i int, now time.Time) string {
+	if p.started.IsZero() {
+		p.started = now
+	}
+	if p.current >= 0 {
+		p.observed = append(p.observed, now.Sub(p.lastStart))
+	}
+	p.current, p.lastStart = i, now
+
+	total := len(p.tests)
+	filled := i * progressBarWidth / total
+	bar := strings.Repeat(progressDone, filled) + strings.Repeat(progressTodo, progressBarWidth-filled)
+	line := fmt.Sprintf("[%d/%d] %s %3d%% Running: %s, elapsed %s", i+1, total, bar, i*100/total, resultName(p.tests[i]), now.Sub(p.started).Round(time.Second))
+	if eta, ok := p.eta(); ok {
+		line += fmt.Sprintf(", ETA %s", eta.Round(time.Second))
+	}
+	return line
+}
+
+// eta estimates the time the current and remaining tests take. Tests without a
+// recorded duration are estimated by the average duration, which is unknown until
+// a test ran or was recorded.
+func (p *progress) eta() (time.Duration, bool) {
+	average, ok := p.averageDuration()
+	var eta time.Duration
+	for _, testFile := range p.tests[p.current:] {
+		if d, known := p.history[resultName(testFile)]; known {
+			eta += d * time.Duration(p.scale)
+			continue
+		}
+		if !ok {
+			return 0, false
+		}
+		eta += average
+	}
+	return eta, true
+}
+
+// averageDuration is the average duration of the tests run so far, or else of the
+// recorded ones
+func (p *progress) averageDuration() (time.Duration, bool) {
+	if len(p.observed) > 0 {
+		var sum time.Duration
+		for _, d := range p.observed {
+			sum += d
+		}
+		return sum / time.Duration(len(p.observed)), true
+	}
+	if len(p.history) > 0 {
+		var sum time.Duration
+		for _, d := range p.history {
+			sum += d
+		}
+		return sum / time.Duration(len(p.history)) * time.Duration(p.scale), true
+	}
+	return 0, false
+}
+
+// progressHistory returns the recorded test durations for the ETA. The ETA is
+// optional, so a results store that cannot be read only leaves it without history.
+func progressHistory(location, target string) map[string]time.Duration {
+	durations, err := recordedDurations(location, target)
+	if err != nil {
+		util.GetLogger().V(1).Info("No recorded durations for the ETA", "store", location, "error", err)
+		return nil
+	}
+	return durations
+}
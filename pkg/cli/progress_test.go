@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgress(t *testing.T) {
+	testFiles := []string{"tests/a/test.yaml", "tests/b/test.yaml", "tests/c/test.yaml", "tests/d/test.yaml"}
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("without history", func(t *testing.T) {
+		p := newProgress(testFiles, nil, 1)
+		line := p.start(0, start)
+		if !strings.HasPrefix(line, "[1/4] ") || !strings.Contains(line, "Running: a") {
+			t.Errorf("unexpected line %q", line)
+		}
+		if strings.Contains(line, "ETA") {
+			t.Errorf("expected no ETA before any test ran, got %q", line)
+		}
+		// The average of the tests run so far estimates the remaining ones
+		line = p.start(1, start.Add(2*time.Minute))
+		if !strings.Contains(line, "elapsed 2m0s, ETA 6m0s") {
+			t.Errorf("unexpected line %q", line)
+		}
+	})
+
+	t.Run("with history", func(t *testing.T) {
+		history := map[string]time.Duration{"a": time.Minute, "b": 2 * time.Minute, "c": 3 * time.Minute}
+		p := newProgress(testFiles, history, 2)
+		// d is estimated by the recorded average, and every test runs twice
+		if line := p.start(0, start); !strings.Contains(line, "ETA 16m0s") {
+			t.Errorf("unexpected line %q", line)
+		}
+		// Durations of this run replace the recorded average: c is recorded, d takes 10m like a
+		if line := p.start(1, start.Add(10*time.Minute)); !strings.Contains(line, "ETA 20m0s") {
+			t.Errorf("unexpected line %q", line)
+		}
+	})
+
+	t.Run("bar", func(t *testing.T) {
+		p := newProgress(testFiles, nil, 1)
+		line := p.start(2, start)
+		want := strings.Repeat(progressDone, progressBarWidth/2) + strings.Repeat(progressTodo, progressBarWidth/2) + "  50%"
+		if !strings.Contains(line, want) {
+			t.Errorf("expected %q in %q", want, line)
+		}
+	})
+}
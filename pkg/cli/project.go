@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"sort"
+
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rcFile string
+	noRC   bool
+
+	// project holds the defaults of the project's .koncurrc, if any
+	project *config.ProjectConfig
+)
+
+// loadProjectConfig loads the --rc file, or the nearest .koncurrc, and applies
+// its defaults to the flags of a command that were not given
+func loadProjectConfig(cmd *cobra.Command) error {
+	project = nil
+	if noRC {
+		return nil
+	}
+	var err error
+	if rcFile != "" {
+		project, err = config.LoadProjectConfig(rcFile)
+	} else {
+		project, err = config.FindProjectConfig(".")
+	}
+	if err != nil {
+		return configError("%w", err)
+	}
+	if project == nil {
+		return nil
+	}
+	util.GetLogger().Info("Using project defaults", "file", project.Path())
+	return applyProjectDefaults(cmd, project)
+}
+
+// applyProjectDefaults sets the flags of a command that were not given from the
+// project defaults
+func applyProjectDefaults(cmd *cobra.Command, project *config.ProjectConfig) error {
+	defaults := map[string]string{
+		"target":        project.Target,
+		"target-config": project.TargetConfig,
+		"test-dir":      project.TestDir,
+		"output-format": project.OutputFormat,
+		"output-file":   project.OutputFile,
+		"results-store": project.ResultsStore,
+	}
+	flags := project.Flags[cmd.Name()]
+	for name, value := range flags {
+		if cmd.Flags().Lookup(name) == nil {
+			return configError("%s sets unknown flag --%s of %s", project.Path(), name, cmd.Name())
+		}
+		defaults[name] = value
+	}
+
+	names := make([]string, 0, len(defaults))
+	for name := range defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed || defaults[name] == "" {
+			continue
+		}
+		if err := flag.Value.Set(defaults[name]); err != nil {
+			return configError("invalid --%s in %s: %w", name, project.Path(), err)
+		}
+	}
+	return nil
+}
+
+// defaultTestDir is the test directory of commands given no path
+func defaultTestDir() string {
+	if project != nil && project.TestDir != "" {
+		return project.TestDir
+	}
+	return "tests"
+}
+
+// applyProjectValidation puts the project's validation defaults below a test's
+// target and test settings
+func applyProjectValidation(targetConfig *config.TargetConfig) {
+	if project == nil {
+		return
+	}
+	targetConfig.Validation = project.Validation.Merge(targetConfig.Validation)
+}
+
+// applyProjectTolerance gives a test the project's allowed mismatches unless it
+// sets its own
+func applyProjectTolerance(test *config.TestDefinition) {
+	if project != nil && test.Expect.AllowedMismatches == nil {
+		test.Expect.AllowedMismatches = project.AllowedMismatches
+	}
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+func TestApplyProjectDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), config.ProjectFileName)
+	content := `target: tackle-hub
+outputFormat: junit
+testDir: suite
+flags:
+  run:
+    repeat: "3"
+    tags: tier0,java
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	project, err := config.LoadProjectConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var target, format string
+	var repeat int
+	var tags []string
+	cmd := &cobra.Command{Use: "run"}
+	cmd.Flags().StringVarP(&target, "target", "t", "", "")
+	cmd.Flags().StringVar(&format, "output-format", "console", "")
+	cmd.Flags().IntVar(&repeat, "repeat", 1, "")
+	cmd.Flags().StringSliceVar(&tags, "tags", nil, "")
+	if err := cmd.ParseFlags([]string{"--target", "kantra"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := applyProjectDefaults(cmd, project); err != nil {
+		t.Fatalf("applyProjectDefaults() error = %v", err)
+	}
+	if target != "kantra" {
+		t.Errorf("expected the --target given to win, got %q", target)
+	}
+	if format != "junit" || repeat != 3 || len(tags) != 2 {
+		t.Errorf("expected project defaults, got format %q, repeat %d, tags %v", format, repeat, tags)
+	}
+
+	other := &cobra.Command{Use: "list"}
+	if err := applyProjectDefaults(other, project); err != nil {
+		t.Errorf("expected flags of other commands to be ignored, got %v", err)
+	}
+
+	project.Flags["run"]["nope"] = "1"
+	if err := applyProjectDefaults(cmd, project); err == nil {
+		t.Error("expected an unknown flag to be rejected")
+	}
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/targets"
+	"github.com/konveyor/test-harness/pkg/util"
+)
+
+// remoteTestsDir keeps the test trees fetched from git repositories
+var remoteTestsDir = ".koncur/cache/tests"
+
+// fetchedRemotes maps the repositories fetched by this command to their clones, so
+// several paths of a repository share one fresh clone
+var fetchedRemotes = map[string]string{}
+
+var unsafeCacheChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// isRemoteTestRef reports whether tests are given as a git URL, such as
+// https://github.com/konveyor/ci#main/koncur-tests. Unlike config.IsGitURL, a local
+// test.yaml#variant is not one.
+func isRemoteTestRef(path string) bool {
+	for _, prefix := range []string{"http://", "https://", "git@", "ssh://", "file://"} {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchRemoteTests clones the repository of a git URL into the remote tests cache,
+// replacing an earlier clone, and returns the directory of the tests in it
+func fetchRemoteTests(ref string) (string, error) {
+	components := config.ParseGitURLWithPath(ref)
+	repo := components.URL + "@" + components.Ref
+	clone, ok := fetchedRemotes[repo]
+	if !ok {
+		name := strings.Trim(unsafeCacheChars.ReplaceAllString(strings.TrimSuffix(repo, "@"), "_"), "_")
+		clone = filepath.Join(remoteTestsDir, name)
+		// Clones are kept between runs at a stable path for the results store, but
+		// fetched again so branches are up to date
+		if err := os.RemoveAll(clone); err != nil {
+			return "", fmt.Errorf("failed to remove previous clone of %s: %w", components.URL, err)
+		}
+		util.GetLogger().Info("Fetching remote tests", "url", components.URL, "ref", components.Ref, "dest", clone)
+		cloned, err := targets.CloneGitRepository(context.Background(), &config.GitURLComponents{URL: components.URL, Ref: components.Ref}, remoteTestsDir, name)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch remote tests %s: %w", ref, err)
+		}
+		clone = cloned
+		fetchedRemotes[repo] = clone
+	}
+
+	dir := filepath.Join(clone, components.Path)
+	if _, err := os.Stat(dir); err != nil {
+		return "", configError("%s does not exist in %s", components.Path, components.URL)
+	}
+	return dir, nil
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestIsRemoteTestRef(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"https://github.com/konveyor/ci#main/koncur-tests", true},
+		{"git@github.com:konveyor/ci.git", true},
+		{"file:///srv/tests.git", true},
+		{"tests/basic/test.yaml#quarkus", false},
+		{"tests", false},
+	}
+	for _, tt := range tests {
+		if got := isRemoteTestRef(tt.path); got != tt.want {
+			t.Errorf("isRemoteTestRef(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestDiscoverRemoteTestFiles(t *testing.T) {
+	repo := t.TempDir()
+	for _, name := range []string{"basic", "hub"} {
+		dir := filepath.Join(repo, "koncur-tests", name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "test.yaml"), []byte("name: "+name+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "tests"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	oldDir, oldFetched := remoteTestsDir, fetchedRemotes
+	remoteTestsDir, fetchedRemotes = t.TempDir(), map[string]string{}
+	defer func() { remoteTestsDir, fetchedRemotes = oldDir, oldFetched }()
+
+	url := "file://" + repo + "#main/koncur-tests"
+	got, err := discoverTestFiles([]string{url, url + "/hub"}, &testSelector{})
+	if err != nil {
+		t.Fatalf("discoverTestFiles() error = %v", err)
+	}
+	clone := fetchedRemotes["file://"+repo+"@main"]
+	want := []string{
+		filepath.Join(clone, "koncur-tests", "basic", "test.yaml"),
+		filepath.Join(clone, "koncur-tests", "hub", "test.yaml"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("discoverTestFiles() = %v, want %v", got, want)
+	}
+
+	if _, err := discoverTestFiles([]string{"file://" + repo + "#main/missing"}, &testSelector{}); exitCodeFor(err) != ExitCodeConfigError {
+		t.Errorf("expected a config error for a missing path, got %v", err)
+	}
+}
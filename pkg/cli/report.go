@@ -0,0 +1,395 @@
+package cli
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/diff"
+	"github.com/konveyor/test-harness/pkg/parser"
+	"github.com/konveyor/test-harness/pkg/util"
+	"github.com/spf13/cobra"
+	yaml2 "gopkg.in/yaml.v2"
+)
+
+var (
+	reportOutputFile string
+)
+
+// reportDiffContext is the number of unchanged lines kept around each change in ruleset diffs
+const reportDiffContext = 3
+
+// NewReportCmd creates the report command
+func NewReportCmd() *cobra.Command {
+	reportCmd := &cobra.Command{
+		Use:   "report <results-file>",
+		Short: "Render an HTML report for a test run",
+		Long: `Render a self-contained HTML report from a results file written by
+'koncur run --output-format json|yaml --output-file <file>'.
+
+The report lists every test with its status, shows side-by-side expected vs actual
+diffs of each mismatching ruleset for failed tests, and links to the run's log files.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log := util.GetLogger()
+
+			summary, err := LoadResults(args[0])
+			if err != nil {
+				return err
+			}
+
+			html, err := RenderHTMLReport(summary)
+			if err != nil {
+				return err
+			}
+
+			if err := os.WriteFile(reportOutputFile, []byte(html), 0644); err != nil {
+				return fmt.Errorf("failed to write report: %w", err)
+			}
+
+			log.Info("Report generated", "file", reportOutputFile, "tests", len(summary.Tests))
+			fmt.Printf("%s Report written to: %s\n", symbolPass, reportOutputFile)
+			return nil
+		},
+	}
+
+	reportCmd.Flags().StringVarP(&reportOutputFile, "output", "o", "koncur-report.html", "Path to write the HTML report")
+
+	return reportCmd
+}
+
+// reportTest is a single test as rendered in the HTML report
+type reportTest struct {
+	TestResult
+	Diffs     []rulesetDiff
+	DiffError string
+	Logs      []string
+}
+
+// rulesetDiff is a side-by-side diff of one expected vs actual ruleset
+type rulesetDiff struct {
+	Name   string
+	Status string
+	Rows   []reportRow
+}
+
+// reportRow is a diff row; Gap is set for rows standing in for collapsed unchanged lines
+type reportRow struct {
+	diff.Row
+	Gap int
+}
+
+// reportOptions customize the HTML report when it is served by 'koncur serve'
+type reportOptions struct {
+	// FileURL links to output and log files, file:// URLs by default
+	FileURL func(path string) template.URL
+	// RerunURL, when set, adds forms posting the tests to rerun to this URL
+	RerunURL string
+	// BackURL, when set, links back to the dashboard
+	BackURL string
+}
+
+// RenderHTMLReport renders a self-contained HTML report for the given results
+func RenderHTMLReport(summary *TestSummary) (string, error) {
+	return renderHTMLReport(summary, reportOptions{})
+}
+
+func renderHTMLReport(summary *TestSummary, opts reportOptions) (string, error) {
+	data := struct {
+		Summary   *TestSummary
+		Tests     []reportTest
+		Generated string
+		RerunURL  string
+		BackURL   string
+	}{
+		Summary:   summary,
+		Generated: time.Now().Format(time.RFC3339),
+		RerunURL:  opts.RerunURL,
+		BackURL:   opts.BackURL,
+	}
+
+	for _, result := range summary.Tests {
+		rt := reportTest{TestResult: result, Logs: findLogFiles(result.WorkDir)}
+		if (result.Status == "failed" || result.Status == "flaky") && result.OutputFile != "" {
+			diffs, err := buildRulesetDiffs(result)
+			if err != nil {
+				rt.DiffError = err.Error()
+			}
+			rt.Diffs = diffs
+		}
+		data.Tests = append(data.Tests, rt)
+	}
+
+	tmpl := reportTemplate
+	if opts.FileURL != nil {
+		tmpl = newReportTemplate(opts.FileURL)
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render report: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// buildRulesetDiffs reloads the expected and actual outputs of a test and diffs them per ruleset
+func buildRulesetDiffs(result TestResult) ([]rulesetDiff, error) {
+	test, err := config.Load(result.TestFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load test: %w", err)
+	}
+
+	actual, err := loadNormalizedOutput(result.OutputFile, test.GetTestDir(), test.Validation.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedYAML, err := rulesetsByName(test.Expect.Output.Result)
+	if err != nil {
+		return nil, err
+	}
+	actualYAML, err := rulesetsByName(actual)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for name := range expectedYAML {
+		names[name] = true
+	}
+	for name := range actualYAML {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var diffs []rulesetDiff
+	for _, name := range sorted {
+		exp, hasExp := expectedYAML[name]
+		act, hasAct := actualYAML[name]
+		if exp == act {
+			continue
+		}
+		status := "changed"
+		if !hasExp {
+			status = "unexpected"
+		} else if !hasAct {
+			status = "missing"
+		}
+		rows := diff.SideBySide(diff.Lines(diff.SplitLines(exp), diff.SplitLines(act)))
+		diffs = append(diffs, rulesetDiff{
+			Name:   name,
+			Status: status,
+			Rows:   collapseRows(rows, reportDiffContext),
+		})
+	}
+	return diffs, nil
+}
+
+// loadNormalizedOutput parses an output file and applies the same filtering and
+// path normalization that run applies before validation
+func loadNormalizedOutput(outputFile, testDir string, filter *parser.RuleSetFilter) ([]konveyor.RuleSet, error) {
+	actual, _, err := parser.ParseOutputVersion(outputFile)
+	if err != nil {
+		return nil, err
+	}
+	normalized, err := parser.NormalizeRuleSets(filter.Apply(actual), testDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to normalize paths: %w", err)
+	}
+	return normalized, nil
+}
+
+// rulesetsByName marshals each ruleset to YAML keyed by ruleset name
+// Uses yaml.v2 to match analyzer-lsp's marshalling behavior
+func rulesetsByName(rulesets []konveyor.RuleSet) (map[string]string, error) {
+	byName := make(map[string]string, len(rulesets))
+	for _, rs := range rulesets {
+		data, err := yaml2.Marshal(rs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal ruleset %s: %w", rs.Name, err)
+		}
+		byName[rs.Name] = string(data)
+	}
+	return byName, nil
+}
+
+// collapseRows replaces long runs of unchanged rows with a single gap row
+func collapseRows(rows []diff.Row, context int) []reportRow {
+	var result []reportRow
+	for i := 0; i < len(rows); {
+		if rows[i].Changed {
+			result = append(result, reportRow{Row: rows[i]})
+			i++
+			continue
+		}
+		j := i
+		for j < len(rows) && !rows[j].Changed {
+			j++
+		}
+		keepHead := context
+		if i == 0 {
+			keepHead = 0
+		}
+		keepTail := context
+		if j == len(rows) {
+			keepTail = 0
+		}
+		if j-i <= keepHead+keepTail {
+			for _, r := range rows[i:j] {
+				result = append(result, reportRow{Row: r})
+			}
+		} else {
+			for _, r := range rows[i : i+keepHead] {
+				result = append(result, reportRow{Row: r})
+			}
+			result = append(result, reportRow{Gap: j - i - keepHead - keepTail})
+			for _, r := range rows[j-keepTail : j] {
+				result = append(result, reportRow{Row: r})
+			}
+		}
+		i = j
+	}
+	return result
+}
+
+// findLogFiles returns the log files written under a test's work directory
+// fileURL links to a local file from a report opened in a browser
+func fileURL(path string) template.URL {
+	return template.URL("file://" + filepath.ToSlash(path))
+}
+
+func findLogFiles(workDir string) []string {
+	if workDir == "" {
+		return nil
+	}
+	var logs []string
+	_ = filepath.Walk(workDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		// Cloned sources can be huge and never contain run logs
+		if info.IsDir() && (info.Name() == "source" || strings.HasPrefix(info.Name(), "rules-")) {
+			return filepath.SkipDir
+		}
+		if !info.IsDir() && strings.HasSuffix(info.Name(), ".log") {
+			if abs, err := filepath.Abs(path); err == nil {
+				logs = append(logs, abs)
+			}
+		}
+		return nil
+	})
+	return logs
+}
+
+var reportTemplate = newReportTemplate(fileURL)
+
+// newReportTemplate parses the report template with the function linking to files
+func newReportTemplate(fileURL func(path string) template.URL) *template.Template {
+	return template.Must(template.New("report").Funcs(template.FuncMap{
+		"fileURL": fileURL,
+		"diffLineClass": func(line string) string {
+			switch {
+			case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+				return "hdr"
+			case strings.HasPrefix(line, "@@"):
+				return "hunk"
+			case strings.HasPrefix(line, "-"):
+				return "del"
+			case strings.HasPrefix(line, "+"):
+				return "ins"
+			}
+			return ""
+		},
+		"splitLines": diff.SplitLines,
+	}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Koncur Test Report</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+h1 { margin-bottom: 0.2em; }
+.meta { color: #57606a; margin-bottom: 1.5em; }
+table.results { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+table.results th, table.results td { border: 1px solid #d0d7de; padding: 4px 8px; text-align: left; }
+.passed { color: #1a7f37; font-weight: bold; }
+.failed { color: #cf222e; font-weight: bold; }
+.skipped, .flaky { color: #9a6700; font-weight: bold; }
+details { border: 1px solid #d0d7de; border-radius: 6px; padding: 0.5em 1em; margin-bottom: 1em; }
+summary { cursor: pointer; font-weight: bold; }
+ol.errors li { font-family: monospace; margin-bottom: 0.2em; }
+table.diff { border-collapse: collapse; width: 100%; font-family: monospace; font-size: 12px; table-layout: fixed; margin-bottom: 1em; }
+table.diff td { padding: 0 6px; white-space: pre-wrap; word-break: break-all; vertical-align: top; }
+table.diff th { text-align: left; background: #f6f8fa; padding: 2px 6px; }
+td.del { background: #ffebe9; }
+td.ins { background: #e6ffec; }
+td.empty { background: #f6f8fa; }
+td.gap { text-align: center; color: #57606a; background: #f6f8fa; }
+pre.udiff { font-size: 12px; background: #f6f8fa; padding: 6px; overflow-x: auto; }
+pre.udiff span { display: block; }
+pre.udiff .del { background: #ffebe9; }
+pre.udiff .ins { background: #e6ffec; }
+pre.udiff .hunk { color: #0969da; }
+pre.udiff .hdr { font-weight: bold; }
+</style>
+</head>
+<body>
+{{if .BackURL}}<p><a href="{{.BackURL}}">&larr; All runs</a></p>{{end}}
+<h1>Koncur Test Report</h1>
+<div class="meta">Generated {{.Generated}} &mdash; {{.Summary.Total}} total,
+<span class="passed">{{.Summary.Passed}} passed</span>,
+<span class="failed">{{.Summary.Failed}} failed</span>,{{if .Summary.Flaky}}
+<span class="flaky">{{.Summary.Flaky}} flaky</span>,{{end}}
+<span class="skipped">{{.Summary.Skipped}} skipped</span> in {{.Summary.Duration}}</div>
+{{if and .RerunURL .Summary.Failed}}<form method="post" action="{{.RerunURL}}"><input type="hidden" name="failed" value="true"><button>Rerun failed tests</button></form>{{end}}
+
+<table class="results">
+<tr><th>Test</th><th>Target</th><th>Status</th><th>Duration</th><th>Errors</th></tr>
+{{range $i, $t := .Tests}}<tr>
+<td>{{if or (eq $t.Status "failed") (eq $t.Status "flaky")}}<a href="#test-{{$i}}">{{$t.Name}}</a>{{else}}{{$t.Name}}{{end}}</td>
+<td>{{$t.Target}}</td>
+<td class="{{$t.Status}}">{{$t.Status}}</td>
+<td>{{$t.Duration}}</td>
+<td>{{len $t.ValidationErrors}}</td>
+</tr>
+{{end}}</table>
+
+{{range $i, $t := .Tests}}{{if or (eq $t.Status "failed") (eq $t.Status "flaky")}}
+<details id="test-{{$i}}" open>
+<summary>{{$t.Name}} <span class="{{$t.Status}}">{{$t.Status}}</span>{{if $t.Attempts}} ({{$t.PassedAttempts}}/{{$t.Attempts}} attempts passed){{end}}</summary>
+<p>Test file: <code>{{$t.TestFile}}</code>{{if $t.OutputFile}} &mdash; <a href="{{fileURL $t.OutputFile}}">actual output</a>{{end}}{{if $t.ExpectedFile}} &mdash; <a href="{{fileURL $t.ExpectedFile}}">expected output</a>{{end}}</p>
+{{if $.RerunURL}}<form method="post" action="{{$.RerunURL}}"><input type="hidden" name="test" value="{{$t.TestFile}}"><button>Rerun</button></form>{{end}}
+{{if $t.ErrorMessage}}<p class="failed">{{$t.ErrorMessage}}</p>{{end}}
+{{if $t.Logs}}<p>Logs:{{range $t.Logs}} <a href="{{fileURL .}}">{{.}}</a>{{end}}</p>{{end}}
+{{if $t.ValidationErrors}}<details><summary>{{len $t.ValidationErrors}} validation error(s)</summary>
+<ol class="errors">{{range $t.ValidationErrors}}<li{{if .Pointer}} title="{{.Pointer}}"{{end}}>{{.Path}}: {{.Message}}</li>{{end}}</ol>
+</details>{{end}}
+{{if $t.ValidationDiffs}}<details open><summary>{{len $t.ValidationDiffs}} validation diff(s)</summary>
+{{range $t.ValidationDiffs}}<pre class="udiff">{{range splitLines .Diff}}<span class="{{diffLineClass .}}">{{.}}</span>{{end}}</pre>
+{{end}}</details>{{end}}
+{{if $t.DiffError}}<p>Could not compute diffs: {{$t.DiffError}}</p>{{end}}
+{{range $t.Diffs}}<h4>{{.Name}} ({{.Status}})</h4>
+<table class="diff">
+<tr><th>Expected</th><th>Actual</th></tr>
+{{range .Rows}}{{if .Gap}}<tr><td class="gap" colspan="2">&hellip; {{.Gap}} unchanged line(s) &hellip;</td></tr>{{else}}<tr>
+<td class="{{if not .HasLeft}}empty{{else if .Changed}}del{{end}}">{{.Left}}</td>
+<td class="{{if not .HasRight}}empty{{else if .Changed}}ins{{end}}">{{.Right}}</td>
+</tr>{{end}}
+{{end}}</table>
+{{end}}
+</details>
+{{end}}{{end}}
+</body>
+</html>
+`))
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/konveyor/test-harness/pkg/diff"
+)
+
+func TestRenderHTMLReport(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "test.yaml")
+	outputFile := filepath.Join(dir, "output.yaml")
+
+	writeFile(t, testFile, `name: report-test
+analysis:
+  application: /tmp/app
+  analysisMode: source-only
+expect:
+  exitCode: 0
+  output:
+    result:
+      - name: cloud-readiness
+        tags:
+          - Java
+          - Spring
+`)
+	writeFile(t, outputFile, `- name: cloud-readiness
+  tags:
+    - Java
+    - Quarkus
+`)
+
+	summary := &TestSummary{
+		Total:    1,
+		Failed:   1,
+		Duration: "1s",
+		Tests: []TestResult{{
+			Name:       "report-test",
+			TestFile:   testFile,
+			Target:     "kantra",
+			Status:     "failed",
+			Duration:   "1s",
+			OutputFile: outputFile,
+		}},
+	}
+
+	html, err := RenderHTMLReport(summary)
+	if err != nil {
+		t.Fatalf("RenderHTMLReport returned error: %v", err)
+	}
+	for _, want := range []string{"report-test", "cloud-readiness (changed)", `<td class="del">- Spring</td>`, `<td class="ins">- Quarkus</td>`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected report to contain %q", want)
+		}
+	}
+}
+
+func TestCollapseRows(t *testing.T) {
+	a := diff.SplitLines("1\n2\n3\n4\n5\n6\n7\n8\n9\n10")
+	b := diff.SplitLines("1\n2\n3\n4\n5\nX\n7\n8\n9\n10")
+	rows := collapseRows(diff.SideBySide(diff.Lines(a, b)), 1)
+
+	// gap(4) + context + change + context + gap(3)
+	if len(rows) != 5 {
+		t.Fatalf("Expected 5 rows, got %d: %+v", len(rows), rows)
+	}
+	if rows[0].Gap != 4 || rows[4].Gap != 3 {
+		t.Errorf("Expected leading gap of 4 and trailing gap of 3, got %d and %d", rows[0].Gap, rows[4].Gap)
+	}
+	if !rows[2].Changed {
+		t.Errorf("Expected changed row in the middle, got %+v", rows[2])
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
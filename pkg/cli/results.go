@@ -4,8 +4,15 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/parser"
 	"github.com/konveyor/test-harness/pkg/validator"
 	yaml "gopkg.in/yaml.v2"
 )
@@ -15,14 +22,45 @@ import (
 type TestResult struct {
 	Name             string                      `json:"name" yaml:"name" xml:"name,attr"`
 	TestFile         string                      `json:"testFile" yaml:"testFile" xml:"testFile,attr"`
+	Target           string                      `json:"target,omitempty" yaml:"target,omitempty" xml:"target,attr,omitempty"`
 	Status           string                      `json:"status" yaml:"status" xml:"status,attr"`
 	Duration         string                      `json:"duration" yaml:"duration" xml:"time,attr"`
 	ExitCode         int                         `json:"exitCode,omitempty" yaml:"exitCode,omitempty" xml:"exitCode,omitempty"`
-	ExpectedExitCode int                         `json:"expectedExitCode,omitempty" yaml:"expectedExitCode,omitempty" xml:"expectedExitCode,omitempty"`
+	ExpectedExitCode config.ExitCodes            `json:"expectedExitCode,omitempty" yaml:"expectedExitCode,omitempty" xml:"expectedExitCode,omitempty"`
 	ValidationErrors []validator.ValidationError `json:"validationErrors,omitempty" yaml:"validationErrors,omitempty" xml:"validationErrors>error,omitempty"`
 	ErrorMessage     string                      `json:"errorMessage,omitempty" yaml:"errorMessage,omitempty" xml:"errorMessage,omitempty"`
 	RuleSetsCount    int                         `json:"ruleSetsCount,omitempty" yaml:"ruleSetsCount,omitempty" xml:"ruleSetsCount,omitempty"`
 	FilteredFrom     int                         `json:"filteredFrom,omitempty" yaml:"filteredFrom,omitempty" xml:"filteredFrom,omitempty"`
+	OutputFile       string                      `json:"outputFile,omitempty" yaml:"outputFile,omitempty" xml:"outputFile,omitempty"`
+	ExpectedFile     string                      `json:"expectedFile,omitempty" yaml:"expectedFile,omitempty" xml:"expectedFile,omitempty"`
+	WorkDir          string                      `json:"workDir,omitempty" yaml:"workDir,omitempty" xml:"workDir,omitempty"`
+	// LogFile holds the koncur logs written while the test ran, in its work directory
+	LogFile string `json:"logFile,omitempty" yaml:"logFile,omitempty" xml:"logFile,omitempty"`
+	// Attempts and PassedAttempts are set when the test was run repeatedly (--repeat)
+	Attempts       int `json:"attempts,omitempty" yaml:"attempts,omitempty" xml:"attempts,omitempty"`
+	PassedAttempts int `json:"passedAttempts,omitempty" yaml:"passedAttempts,omitempty" xml:"passedAttempts,omitempty"`
+	// FailureKind classifies failed tests as validation, execution or config failures
+	FailureKind string `json:"failureKind,omitempty" yaml:"failureKind,omitempty" xml:"failureKind,omitempty"`
+	// ValidationDiffs are unified YAML diffs of the mismatching parts of the output
+	ValidationDiffs []validator.ValidationDiff `json:"validationDiffs,omitempty" yaml:"validationDiffs,omitempty" xml:"validationDiffs>diff,omitempty"`
+	// BaselineRun is the ID of the run the output was compared with, for tests
+	// validated against their previous run
+	BaselineRun string `json:"baselineRun,omitempty" yaml:"baselineRun,omitempty" xml:"baselineRun,omitempty"`
+	// ValidationWarnings are differences that did not fail the test, e.g. renamed rules
+	ValidationWarnings []validator.ValidationError `json:"validationWarnings,omitempty" yaml:"validationWarnings,omitempty" xml:"validationWarnings>warning,omitempty"`
+	// ProviderErrors are provider initialization errors and rule evaluation failures
+	// found in the analysis and provider logs of the run
+	ProviderErrors []parser.LogIssue `json:"providerErrors,omitempty" yaml:"providerErrors,omitempty" xml:"providerErrors>issue,omitempty"`
+	// OutputSchema is the schema version of an actual output that was converted
+	// from an older kantra release
+	OutputSchema parser.SchemaVersion `json:"outputSchema,omitempty" yaml:"outputSchema,omitempty" xml:"outputSchema,omitempty"`
+	// SkipReason says why a skipped test did not run
+	SkipReason string `json:"skipReason,omitempty" yaml:"skipReason,omitempty" xml:"skipReason,omitempty"`
+	// UpdatedExpected is set when --update-expected rewrote the expected output
+	UpdatedExpected bool `json:"updatedExpected,omitempty" yaml:"updatedExpected,omitempty" xml:"updatedExpected,omitempty"`
+
+	// actual is the normalized output, stored with the run for later comparisons
+	actual []konveyor.RuleSet
 }
 
 // TestSummary contains results for all tests in a run
@@ -30,6 +68,7 @@ type TestSummary struct {
 	Total    int          `json:"total" yaml:"total" xml:"total,attr"`
 	Passed   int          `json:"passed" yaml:"passed" xml:"passed,attr"`
 	Failed   int          `json:"failed" yaml:"failed" xml:"failed,attr"`
+	Flaky    int          `json:"flaky,omitempty" yaml:"flaky,omitempty" xml:"flaky,attr,omitempty"`
 	Skipped  int          `json:"skipped" yaml:"skipped" xml:"skipped,attr"`
 	Duration string       `json:"duration" yaml:"duration" xml:"time,attr"`
 	Tests    []TestResult `json:"tests" yaml:"tests" xml:"testcase"`
@@ -70,6 +109,15 @@ type JUnitSkipped struct {
 // OutputFormat represents the output format for test results
 type OutputFormat string
 
+// ParseOutputFormat validates a format name given on the command line
+func ParseOutputFormat(format string) (OutputFormat, error) {
+	switch f := OutputFormat(strings.ToLower(format)); f {
+	case OutputFormatConsole, OutputFormatJSON, OutputFormatYAML, OutputFormatJUnit:
+		return f, nil
+	}
+	return "", fmt.Errorf("unsupported output format: %s (must be one of console, json, yaml, junit)", format)
+}
+
 const (
 	OutputFormatConsole OutputFormat = "console"
 	OutputFormatJSON    OutputFormat = "json"
@@ -91,6 +139,26 @@ func FormatResults(summary *TestSummary, format OutputFormat) (string, error) {
 	}
 }
 
+// LoadResults reads a results file previously written with --output-format json or yaml
+func LoadResults(path string) (*TestSummary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results file %s: %w", path, err)
+	}
+
+	var summary TestSummary
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &summary)
+	default:
+		err = json.Unmarshal(data, &summary)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse results file %s: %w", path, err)
+	}
+	return &summary, nil
+}
+
 // formatJSON formats the test results as JSON
 func formatJSON(summary *TestSummary) (string, error) {
 	data, err := json.MarshalIndent(summary, "", "  ")
@@ -118,46 +186,55 @@ func formatJUnit(summary *TestSummary) (string, error) {
 	suite := JUnitTestSuite{
 		Name:      "koncur-tests",
 		Tests:     summary.Total,
-		Failures:  summary.Failed,
+		Failures:  summary.Failed + summary.Flaky,
 		Skipped:   summary.Skipped,
 		Time:      parseDuration(junitTime),
 		TestCases: make([]JUnitTestCase, 0, len(summary.Tests)),
 	}
 
 	for _, result := range summary.Tests {
+		className := "koncur"
+		if result.Target != "" {
+			className = fmt.Sprintf("koncur.%s", result.Target)
+		}
 		testCase := JUnitTestCase{
 			Name:      result.Name,
-			ClassName: "koncur",
-			Time:      result.Duration,
+			ClassName: className,
+			Time:      junitSeconds(result.Duration),
 		}
 
 		switch result.Status {
+		case "flaky":
+			testCase.Failure = &JUnitFailure{
+				Message: fmt.Sprintf("flaky: passed %d of %d attempts", result.PassedAttempts, result.Attempts),
+				Type:    "FlakyTest",
+				Content: result.ErrorMessage,
+			}
 		case "failed":
 			failureMessage := result.ErrorMessage
 			if failureMessage == "" && len(result.ValidationErrors) > 0 {
 				failureMessage = fmt.Sprintf("%d validation error(s)", len(result.ValidationErrors))
 			}
 
-			// Build detailed failure content with validation errors grouped under this test
-			content := ""
-			if result.ExitCode != result.ExpectedExitCode {
-				content += fmt.Sprintf("Exit code mismatch: expected %d, got %d\n", result.ExpectedExitCode, result.ExitCode)
+			failureType := "ValidationError"
+			switch result.FailureKind {
+			case FailureExecution:
+				failureType = "ExecutionError"
+			case FailureConfig:
+				failureType = "ConfigError"
 			}
-			if len(result.ValidationErrors) > 0 {
-				content += fmt.Sprintf("\nValidation Errors (%d):\n", len(result.ValidationErrors))
-				for i, verr := range result.ValidationErrors {
-					content += fmt.Sprintf("[%d] %s: %s\n", i+1, verr.Path, verr.Message)
-				}
-			}
-
 			testCase.Failure = &JUnitFailure{
 				Message: failureMessage,
-				Type:    "ValidationError",
-				Content: content,
+				Type:    failureType,
+				Content: failureDetails(result),
 			}
 		case "skipped":
+			message := "Test marked as skipped"
+			if result.SkipReason != "" {
+				message = result.SkipReason
+			}
 			testCase.Skipped = &JUnitSkipped{
-				Message: "Test marked as skipped",
+				Message: message,
 			}
 		}
 
@@ -172,6 +249,151 @@ func formatJUnit(summary *TestSummary) (string, error) {
 	return xml.Header + string(data), nil
 }
 
+// failureDetails describes why a test failed: the exit code mismatch, error message,
+// validation errors grouped by ruleset and the diffs of mismatching output
+func failureDetails(result TestResult) string {
+	content := ""
+	if !result.ExpectedExitCode.Matches(result.ExitCode) {
+		content += fmt.Sprintf("Exit code mismatch: expected %s, got %d\n", result.ExpectedExitCode, result.ExitCode)
+	}
+	if result.ErrorMessage != "" {
+		content += result.ErrorMessage + "\n"
+	}
+	if len(result.ValidationErrors) > 0 {
+		content += fmt.Sprintf("\nValidation Errors (%d):\n", len(result.ValidationErrors))
+		for _, group := range GroupValidationErrors(result.ValidationErrors) {
+			content += fmt.Sprintf("\n%s (%d):\n", group.RuleSet, len(group.Errors))
+			for i, verr := range collapseUnlessVerbose(group.Errors) {
+				content += fmt.Sprintf("  [%d] %s%s: %s%s\n", i+1, codePrefix(verr.Code), verr.Path, verr.Message, verr.similarSuffix())
+			}
+		}
+	}
+	if len(result.ValidationDiffs) > 0 {
+		content += "\nDiffs:\n"
+		for _, d := range result.ValidationDiffs {
+			content += "\n" + d.Diff
+		}
+	}
+	return content
+}
+
+// junitSeconds converts a duration string as stored on TestResult into seconds.
+// Unparseable values are reported as zero rather than failing the whole report.
+func junitSeconds(duration string) string {
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return parseDuration(0)
+	}
+	return parseDuration(d)
+}
+
+// CollapsedError is a validation error standing for Count errors of the same kind
+// reported for the same violation, so one rule drift doesn't flood the output
+type CollapsedError struct {
+	validator.ValidationError
+	Count int
+}
+
+// CollapseValidationErrors merges errors with the same code on the same violation into
+// their first error and a count. Errors outside a violation are kept as they are.
+func CollapseValidationErrors(errs []validator.ValidationError) []CollapsedError {
+	index := map[string]int{}
+	var collapsed []CollapsedError
+	for _, verr := range errs {
+		loc := verr.Location
+		if loc == nil || loc.RuleID == "" || verr.Code == "" {
+			collapsed = append(collapsed, CollapsedError{ValidationError: verr, Count: 1})
+			continue
+		}
+		key := strings.Join([]string{loc.RuleSet, loc.Section, loc.RuleID, string(verr.Code)}, "\x00")
+		if i, ok := index[key]; ok {
+			collapsed[i].Count++
+			continue
+		}
+		index[key] = len(collapsed)
+		collapsed = append(collapsed, CollapsedError{ValidationError: verr, Count: 1})
+	}
+	return collapsed
+}
+
+// collapseUnlessVerbose collapses errors unless --verbose-validation asks for all of them
+func collapseUnlessVerbose(errs []validator.ValidationError) []CollapsedError {
+	if verboseValidation {
+		collapsed := make([]CollapsedError, len(errs))
+		for i, verr := range errs {
+			collapsed[i] = CollapsedError{ValidationError: verr, Count: 1}
+		}
+		return collapsed
+	}
+	return CollapseValidationErrors(errs)
+}
+
+// similarSuffix describes the errors a collapsed error stands for
+func (c CollapsedError) similarSuffix() string {
+	if c.Count <= 1 {
+		return ""
+	}
+	return fmt.Sprintf(" (+%d similar)", c.Count-1)
+}
+
+// codePrefix renders a validation error code for plain-text listings
+func codePrefix(code validator.ErrorCode) string {
+	if code == "" {
+		return ""
+	}
+	return fmt.Sprintf("[%s] ", code)
+}
+
+// ValidationErrorGroup holds the validation errors reported for a single ruleset
+type ValidationErrorGroup struct {
+	RuleSet string
+	Errors  []validator.ValidationError
+}
+
+// GroupValidationErrors groups validation errors by ruleset, taken from their location
+// or, for results written before locations existed, from the start of their path.
+// Groups are sorted by ruleset name; errors keep their original order within a group.
+func GroupValidationErrors(errs []validator.ValidationError) []ValidationErrorGroup {
+	index := map[string]int{}
+	var groups []ValidationErrorGroup
+	for _, verr := range errs {
+		name := rulesetFromPath(verr.Path)
+		if verr.Location != nil && verr.Location.RuleSet != "" {
+			name = verr.Location.RuleSet
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, ValidationErrorGroup{RuleSet: name})
+		}
+		groups[i].Errors = append(groups[i].Errors, verr)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].RuleSet < groups[j].RuleSet
+	})
+	return groups
+}
+
+// rulesetFromPath extracts the ruleset name from a validation error path.
+// Paths look like "<ruleset>/violations/<rule>" or "ruleset/<ruleset>" for missing rulesets,
+// and ruleset names may themselves contain slashes (e.g. "azure/springboot").
+func rulesetFromPath(path string) string {
+	if strings.HasPrefix(path, "ruleset/") {
+		return strings.TrimPrefix(path, "ruleset/")
+	}
+	end := len(path)
+	for _, section := range []string{"/error/", "/tags/", "/insights/", "/violations/", "/unmatched/", "/skipped/"} {
+		if i := strings.Index(path, section); i >= 0 && i < end {
+			end = i
+		}
+	}
+	if end == 0 {
+		return "(none)"
+	}
+	return path[:end]
+}
+
 // parseDuration converts a time.Duration to a string in seconds (for JUnit compatibility)
 func parseDuration(d time.Duration) string {
 	return fmt.Sprintf("%.3f", d.Seconds())
@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/konveyor/test-harness/pkg/validator"
+)
+
+func sampleSummary() *TestSummary {
+	return &TestSummary{
+		Total:    3,
+		Passed:   1,
+		Failed:   1,
+		Skipped:  1,
+		Duration: "1m30s",
+		Tests: []TestResult{
+			{Name: "passing", TestFile: "tests/passing/test.yaml", Target: "kantra", Status: "passed", Duration: "45s"},
+			{
+				Name:     "failing",
+				TestFile: "tests/failing/test.yaml",
+				Target:   "kantra",
+				Status:   "failed",
+				Duration: "1.5s",
+				ValidationErrors: []validator.ValidationError{
+					{Code: validator.CodeMissingIncident, Path: "azure/springboot/violations/rule-1", Message: "Did not find expected incident"},
+					{Code: validator.CodeMissingRuleSet, Path: "ruleset/missing", Message: "Did not find a matching ruleset"},
+					{Code: validator.CodeMissingTag, Path: "azure/springboot/tags/Java", Message: "Did not find expected tag: Java"},
+				},
+				ValidationDiffs: []validator.ValidationDiff{
+					{Path: "azure/springboot/tags", Diff: "--- expected/azure/springboot/tags\n+++ actual/azure/springboot/tags\n@@ -1 +0,0 @@\n-- Java\n"},
+				},
+			},
+			{Name: "skipped", TestFile: "tests/skipped/test.yaml", Target: "kantra", Status: "skipped", Duration: "0s"},
+		},
+	}
+}
+
+func TestParseOutputFormat(t *testing.T) {
+	for _, f := range []string{"console", "json", "yaml", "junit", "JUnit"} {
+		if _, err := ParseOutputFormat(f); err != nil {
+			t.Errorf("ParseOutputFormat(%q) returned error: %v", f, err)
+		}
+	}
+	if _, err := ParseOutputFormat("xml"); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
+
+func TestFormatJUnit(t *testing.T) {
+	out, err := FormatResults(sampleSummary(), OutputFormatJUnit)
+	if err != nil {
+		t.Fatalf("FormatResults returned error: %v", err)
+	}
+
+	var suite JUnitTestSuite
+	if err := xml.Unmarshal([]byte(out), &suite); err != nil {
+		t.Fatalf("Failed to parse JUnit output: %v", err)
+	}
+
+	if suite.Time != "90.000" {
+		t.Errorf("Expected suite time 90.000, got %s", suite.Time)
+	}
+	if len(suite.TestCases) != 3 {
+		t.Fatalf("Expected 3 test cases, got %d", len(suite.TestCases))
+	}
+	if suite.TestCases[0].Time != "45.000" {
+		t.Errorf("Expected per-test time in seconds, got %s", suite.TestCases[0].Time)
+	}
+	if suite.TestCases[0].ClassName != "koncur.kantra" {
+		t.Errorf("Expected classname koncur.kantra, got %s", suite.TestCases[0].ClassName)
+	}
+	failure := suite.TestCases[1].Failure
+	if failure == nil {
+		t.Fatal("Expected failure for failing test")
+	}
+	if !strings.Contains(failure.Content, "azure/springboot (2):") {
+		t.Errorf("Expected errors grouped by ruleset, got:\n%s", failure.Content)
+	}
+	if !strings.Contains(failure.Content, "[MISSING_TAG] azure/springboot/tags/Java") {
+		t.Errorf("Expected error codes in failure content, got:\n%s", failure.Content)
+	}
+	if !strings.Contains(failure.Content, "-- Java") {
+		t.Errorf("Expected validation diff in failure content, got:\n%s", failure.Content)
+	}
+	if suite.TestCases[2].Skipped == nil {
+		t.Error("Expected skipped element for skipped test")
+	}
+}
+
+func TestFormatJUnit_SkipReason(t *testing.T) {
+	summary := sampleSummary()
+	summary.Tests[2].SkipReason = "not run, failing failed with --fail-fast"
+	out, err := FormatResults(summary, OutputFormatJUnit)
+	if err != nil {
+		t.Fatalf("FormatResults returned error: %v", err)
+	}
+
+	var suite JUnitTestSuite
+	if err := xml.Unmarshal([]byte(out), &suite); err != nil {
+		t.Fatalf("Failed to parse JUnit output: %v", err)
+	}
+	if skipped := suite.TestCases[2].Skipped; skipped == nil || skipped.Message != summary.Tests[2].SkipReason {
+		t.Errorf("Expected skip reason as skipped message, got %+v", skipped)
+	}
+}
+
+func TestFormatJSON(t *testing.T) {
+	out, err := FormatResults(sampleSummary(), OutputFormatJSON)
+	if err != nil {
+		t.Fatalf("FormatResults returned error: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+	if !strings.Contains(out, `"path": "ruleset/missing"`) {
+		t.Errorf("Expected lower-case validation error fields in JSON output:\n%s", out)
+	}
+	if !strings.Contains(out, `"code": "MISSING_RULESET"`) {
+		t.Errorf("Expected validation error codes in JSON output:\n%s", out)
+	}
+}
+
+func TestGroupValidationErrors(t *testing.T) {
+	groups := GroupValidationErrors(sampleSummary().Tests[1].ValidationErrors)
+	if len(groups) != 2 {
+		t.Fatalf("Expected 2 groups, got %d", len(groups))
+	}
+	if groups[0].RuleSet != "azure/springboot" || len(groups[0].Errors) != 2 {
+		t.Errorf("Unexpected first group: %+v", groups[0])
+	}
+	if groups[1].RuleSet != "missing" {
+		t.Errorf("Unexpected second group: %+v", groups[1])
+	}
+}
+
+func TestCollapseValidationErrors(t *testing.T) {
+	incident := func(rule string, code validator.ErrorCode) validator.ValidationError {
+		return validator.ValidationError{
+			Code:     code,
+			Path:     "rs/violations/" + rule,
+			Location: &validator.Location{RuleSet: "rs", Section: "violations", RuleID: rule},
+		}
+	}
+	errs := []validator.ValidationError{
+		incident("rule-1", validator.CodeMissingIncident),
+		incident("rule-1", validator.CodeMissingIncident),
+		incident("rule-2", validator.CodeMissingIncident),
+		incident("rule-1", validator.CodeUnexpectedIncident),
+		incident("rule-1", validator.CodeMissingIncident),
+		{Code: validator.CodeMissingRuleSet, Path: "ruleset/a"},
+		{Code: validator.CodeMissingRuleSet, Path: "ruleset/b"},
+	}
+
+	collapsed := CollapseValidationErrors(errs)
+	counts := []int{}
+	for _, c := range collapsed {
+		counts = append(counts, c.Count)
+	}
+	if want := []int{3, 1, 1, 1, 1}; !slices.Equal(counts, want) {
+		t.Fatalf("Expected counts %v, got %v", want, counts)
+	}
+	if collapsed[0].similarSuffix() != " (+2 similar)" || collapsed[1].similarSuffix() != "" {
+		t.Errorf("Unexpected suffixes: %q %q", collapsed[0].similarSuffix(), collapsed[1].similarSuffix())
+	}
+
+	old := verboseValidation
+	defer func() { verboseValidation = old }()
+	verboseValidation = true
+	if got := collapseUnlessVerbose(errs); len(got) != len(errs) {
+		t.Errorf("Expected --verbose-validation to keep all %d errors, got %d", len(errs), len(got))
+	}
+}
+
+func TestFormatMarkdownSummary(t *testing.T) {
+	out := FormatMarkdownSummary(sampleSummary())
+	if !strings.Contains(out, "| Test | Target | Status | Duration | Top validation errors |") {
+		t.Errorf("Expected table header in summary:\n%s", out)
+	}
+	if !strings.Contains(out, "| failing | kantra | ❌ failed | 1.5s |") {
+		t.Errorf("Expected failing row in summary:\n%s", out)
+	}
+	if !strings.Contains(out, "`ruleset/missing`: Did not find a matching ruleset") {
+		t.Errorf("Expected validation errors in summary:\n%s", out)
+	}
+	if got := markdownCell("a|b\nc"); got != "a\\|b c" {
+		t.Errorf("markdownCell() = %q", got)
+	}
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+
+	"github.com/fatih/color"
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/diff"
+	"github.com/konveyor/test-harness/pkg/parser"
+)
+
+// reviewDiffContext is the number of unchanged lines shown around each change
+const reviewDiffContext = 3
+
+// reviewer asks which changed rulesets of a generated output to accept
+type reviewer struct {
+	in *bufio.Reader
+	// acceptAll and rejectAll answer every remaining question, for all tests
+	acceptAll bool
+	rejectAll bool
+}
+
+func newReviewer(in io.Reader) *reviewer {
+	return &reviewer{in: bufio.NewReader(in)}
+}
+
+// review shows the diff of every ruleset that differs between the previous expected
+// output and the generated one, and returns the generated output with the rejected
+// rulesets reverted to their previous version. Both outputs must be normalized.
+func (r *reviewer) review(previous, generated []konveyor.RuleSet) ([]konveyor.RuleSet, error) {
+	previousYAML, err := rulesetsByName(previous)
+	if err != nil {
+		return nil, err
+	}
+	generatedYAML, err := rulesetsByName(generated)
+	if err != nil {
+		return nil, err
+	}
+	previousByName := map[string]konveyor.RuleSet{}
+	for _, rs := range previous {
+		previousByName[rs.Name] = rs
+	}
+
+	var names []string
+	for name := range previousYAML {
+		names = append(names, name)
+	}
+	for name := range generatedYAML {
+		if _, ok := previousYAML[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	rejected := map[string]bool{}
+	changes := 0
+	for _, name := range names {
+		before, after := previousYAML[name], generatedYAML[name]
+		if before == after {
+			continue
+		}
+		changes++
+		fmt.Println()
+		printUnifiedDiff(diff.Unified("expected/"+name, "generated/"+name, diff.Lines(diff.SplitLines(before), diff.SplitLines(after)), reviewDiffContext))
+
+		accept, err := r.ask(name)
+		if err != nil {
+			return nil, err
+		}
+		if !accept {
+			rejected[name] = true
+		}
+	}
+	if changes == 0 {
+		color.Green("  %s No changes to the expected output", symbolPass)
+		return generated, nil
+	}
+
+	var result []konveyor.RuleSet
+	for _, rs := range generated {
+		if !rejected[rs.Name] {
+			result = append(result, rs)
+		}
+	}
+	// Rejected rulesets keep their previous version, or stay absent
+	for _, name := range names {
+		if rs, ok := previousByName[name]; ok && rejected[name] {
+			result = append(result, rs)
+		}
+	}
+	fmt.Printf("  Accepted %d of %d changed ruleset(s)\n", changes-len(rejected), changes)
+	return result, nil
+}
+
+// ask asks whether to accept the changes to a ruleset. "a" and "q" accept or
+// reject all remaining changes; an empty answer or end of input rejects.
+func (r *reviewer) ask(name string) (bool, error) {
+	switch {
+	case r.acceptAll:
+		return true, nil
+	case r.rejectAll:
+		return false, nil
+	}
+	for {
+		fmt.Printf("  Accept changes to ruleset %s? [y]es, [N]o, [a]ll, [q]uit: ", name)
+		answer, err := r.in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return false, fmt.Errorf("failed to read answer: %w", err)
+		}
+		if err == io.EOF && answer == "" {
+			fmt.Println()
+			r.rejectAll = true
+			return false, nil
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			return true, nil
+		case "", "n", "no":
+			return false, nil
+		case "a", "all":
+			r.acceptAll = true
+			return true, nil
+		case "q", "quit":
+			r.rejectAll = true
+			return false, nil
+		}
+	}
+}
+
+// previousOutput returns a test's current expected output, normalized like a
+// generated one; it is empty when the test has no expected output yet
+func previousOutput(test *config.TestDefinition) ([]konveyor.RuleSet, error) {
+	rulesets := test.Expect.Output.Result
+	if len(rulesets) == 0 {
+		for _, file := range expectedOutputFiles(test) {
+			loaded, err := config.LoadExpectedOutput(file)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to load expected output: %w", err)
+			}
+			rulesets = append(rulesets, loaded...)
+		}
+	}
+	return parser.NormalizeRuleSets(rulesets, test.GetTestDir())
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+func TestReview(t *testing.T) {
+	ruleset := func(name string, incidents int) konveyor.RuleSet {
+		return konveyor.RuleSet{
+			Name:       name,
+			Violations: map[string]konveyor.Violation{"rule-001": {Description: "Rule", Incidents: make([]konveyor.Incident, incidents)}},
+		}
+	}
+	// "changed" has one more incident, "removed" is gone and "added" is new
+	previous := []konveyor.RuleSet{ruleset("changed", 1), ruleset("removed", 1), ruleset("same", 1)}
+	generated := []konveyor.RuleSet{ruleset("added", 1), ruleset("changed", 2), ruleset("same", 1)}
+
+	tests := []struct {
+		name      string
+		answers   string
+		generated []konveyor.RuleSet
+		want      map[string]int
+	}{
+		{
+			name:    "accept each",
+			answers: "y\ny\nyes\n",
+			want:    map[string]int{"added": 1, "changed": 2, "same": 1},
+		},
+		{
+			name:    "reject each",
+			answers: "n\n\nno\n",
+			want:    map[string]int{"changed": 1, "removed": 1, "same": 1},
+		},
+		{
+			name:    "accept all after rejecting the first",
+			answers: "n\na\n",
+			want:    map[string]int{"changed": 2, "same": 1},
+		},
+		{
+			name:    "quit after accepting the first",
+			answers: "y\nq\n",
+			want:    map[string]int{"added": 1, "changed": 1, "removed": 1, "same": 1},
+		},
+		{
+			name:    "invalid answers are asked again",
+			answers: "maybe\ny\n",
+			want:    map[string]int{"added": 1, "changed": 1, "removed": 1, "same": 1},
+		},
+		{
+			name:    "end of input rejects",
+			answers: "",
+			want:    map[string]int{"changed": 1, "removed": 1, "same": 1},
+		},
+		{
+			name:      "no changes",
+			generated: previous,
+			want:      map[string]int{"changed": 1, "removed": 1, "same": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.generated == nil {
+				tt.generated = generated
+			}
+			result, err := newReviewer(strings.NewReader(tt.answers)).review(previous, tt.generated)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := map[string]int{}
+			for _, rs := range result {
+				got[rs.Name] = len(rs.Violations["rule-001"].Incidents)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got rulesets %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
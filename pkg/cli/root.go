@@ -9,7 +9,10 @@ import (
 )
 
 var (
-	verbose bool
+	verbose   bool
+	noColor   bool
+	logFile   string
+	logFormat string
 )
 
 // NewRootCmd creates the root command
@@ -21,20 +24,54 @@ func NewRootCmd() *cobra.Command {
 for Konveyor tools (Kantra, Tackle, Kai).
 
 Koncur concurs with your expected results!`,
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := util.SetLogOutput(logFile, logFormat); err != nil {
+				return configError("%w", err)
+			}
 			util.InitLogger(verbose)
+			configureConsole(noColor)
+			if err := loadProjectConfig(cmd); err != nil {
+				return err
+			}
+			// Flags parsed fine - failures from here on are not usage errors
+			cmd.SilenceUsage = true
+			return nil
 		},
 	}
 
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return configError("%w", err)
+	})
+
 	// Global flags
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging")
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "Write logs to this file instead of stderr (appended)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", util.LogFormatText, "Log format: text or json")
+	rootCmd.PersistentFlags().StringVar(&rcFile, "rc", "", "Project defaults file (default: the nearest .koncurrc up to the repository root)")
+	rootCmd.PersistentFlags().BoolVar(&noRC, "no-rc", false, "Ignore the project defaults file")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colors and symbols in console output (also set by NO_COLOR or a non-TTY stdout)")
 
 	// Add subcommands
 	rootCmd.AddCommand(NewRunCmd())
+	rootCmd.AddCommand(NewRerunFailedCmd())
 	rootCmd.AddCommand(NewValidateCmd())
+	rootCmd.AddCommand(NewVerifyExpectedCmd())
+	rootCmd.AddCommand(NewListCmd())
 	rootCmd.AddCommand(NewGenerateCmd())
 	rootCmd.AddCommand(NewCleanCmd())
+	rootCmd.AddCommand(NewArchiveCmd())
+	rootCmd.AddCommand(NewInitCmd())
+	rootCmd.AddCommand(NewImportCmd())
+	rootCmd.AddCommand(NewExportCmd())
+	rootCmd.AddCommand(NewDoctorCmd())
 	rootCmd.AddCommand(NewConfigCmd())
+	rootCmd.AddCommand(NewReportCmd())
+	rootCmd.AddCommand(NewServeCmd())
+	rootCmd.AddCommand(NewStatsCmd())
+	rootCmd.AddCommand(NewCoverageCmd())
+	rootCmd.AddCommand(NewCommentCmd())
+	rootCmd.AddCommand(NewBisectCmd())
+	rootCmd.AddCommand(NewSchemaCmd())
 
 	return rootCmd
 }
@@ -44,6 +81,6 @@ func Execute() {
 	rootCmd := NewRootCmd()
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(exitCodeFor(err))
 	}
 }
@@ -3,12 +3,16 @@ package cli
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/fatih/color"
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
 	"github.com/konveyor/test-harness/pkg/config"
 	"github.com/konveyor/test-harness/pkg/parser"
 	"github.com/konveyor/test-harness/pkg/targets"
@@ -20,162 +24,261 @@ import (
 var (
 	targetConfigFile string
 	targetType       string
-	runFilter        string
+	runFilters       []string
+	runExcludes      []string
+	runTags          []string
+	runExcludeTags   []string
 	outputFormat     string
 	outputFile       string
+	summaryFile      string
+	resultsStore     string
+	noStore          bool
+	toolVersion      string
+	// runToolVersion is --tool-version, or the version the target reports
+	runToolVersion   string
+	metricsPushURL   string
+	metricsJob       string
+	metricsLabels    map[string]string
+	repeatCount      int
+	untilFailure     bool
+	quietOutput      bool
+	strictValidation bool
+	comparePrevious  bool
+	updateExpected   bool
+	// failOnProviderErrors fails tests whose logs report provider errors
+	failOnProviderErrors bool
+	// verboseValidation lists every validation error instead of collapsing them per rule
+	verboseValidation bool
+	// failedOnly runs only the tests that failed in the previous recorded run
+	failedOnly bool
+	// failFast stops the run after the first failed test
+	failFast bool
+	// shardSpec selects a slice of the suite ("index/count") for parallel CI jobs
+	shardSpec string
+	// shardBalance balances shards by the test durations recorded in the results store
+	shardBalance bool
 )
 
+// untilFailureLimit caps --until-failure when --repeat is not given
+const untilFailureLimit = 100
+
 // NewRunCmd creates the run command
 func NewRunCmd() *cobra.Command {
 	runCmd := &cobra.Command{
-		Use:   "run <test-file-or-directory>",
+		Use:   "run [test-file-or-directory]...",
 		Short: "Run test definition(s)",
 		Long: `Execute one or more tests and validate their output against expected results.
 
-You can provide either:
+You can provide one or more of:
   - A specific test file (test.yaml)
-  - A directory containing test files (will search recursively)`,
-		Args: cobra.ExactArgs(1),
+  - A directory containing test files (will search recursively)
+  - A git URL of a test tree, such as https://github.com/konveyor/ci#main/koncur-tests,
+    fetched into .koncur/cache/tests
+
+Tests are selected with --filter and --exclude, regular expressions matched against
+the name of the test directory, and with --tags.
+
+With --failed-only, only the tests that failed in the previous recorded run on the
+same target are run, and the paths are optional.`,
+		Args: cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			path := args[0]
+			if len(args) == 0 && !failedOnly {
+				if project == nil || project.TestDir == "" {
+					return configError("requires a test file or directory (or --failed-only)")
+				}
+				args = []string{project.TestDir}
+			}
+			if quietOutput && !verbose {
+				util.InitLoggerWithLevel(slog.LevelWarn)
+			}
 			log := util.GetLogger()
 
-			// Check if path is a file or directory
-			info, err := os.Stat(path)
+			format, err := ParseOutputFormat(outputFormat)
 			if err != nil {
-				return fmt.Errorf("failed to stat path: %w", err)
+				return configError("%w", err)
+			}
+			outputFormat = string(format)
+			if outputFile != "" && format == OutputFormatConsole {
+				return configError("--output-file requires --output-format json, yaml, or junit")
+			}
+			if repeatCount < 1 {
+				return configError("--repeat must be at least 1")
 			}
 
+			selector, err := newTestSelector(runFilters, runExcludes, runTags, runExcludeTags)
+			if err != nil {
+				return err
+			}
+			var testShard *shard
+			if shardSpec != "" {
+				if testShard, err = parseShard(shardSpec); err != nil {
+					return configError("%w", err)
+				}
+			} else if shardBalance {
+				return configError("--shard-balance requires --shard")
+			}
 			var testFiles []string
-			if info.IsDir() {
-				// Find all test.yaml files in directory
-				log.Info("Searching for test files", "directory", path)
-				testFiles, err = findTestFiles(path)
+			if len(args) > 0 {
+				testFiles, err = discoverTestFiles(args, selector)
 				if err != nil {
-					return fmt.Errorf("failed to find test files: %w", err)
-				}
-
-				if len(testFiles) == 0 {
-					return fmt.Errorf("no test files found in %s", path)
+					return err
 				}
+			}
 
-				log.Info("Found test files", "count", len(testFiles))
+			// Load or create target config once for all tests
+			targetConfig, err := loadTargetConfig(targetConfigFile, targetType)
+			if err != nil {
+				return err
+			}
 
-				// Filter tests if pattern provided
-				if runFilter != "" {
-					filtered := []string{}
-					for _, tf := range testFiles {
-						testName := filepath.Base(filepath.Dir(tf))
-						if strings.Contains(testName, runFilter) {
-							filtered = append(filtered, tf)
-						}
-					}
-					testFiles = filtered
-					log.Info("Filtered test files", "count", len(testFiles), "pattern", runFilter)
-				}
+			log.Info("Using target", "type", targetConfig.Type)
+			skipEnv := config.NewSkipEnvironment(targetConfig)
 
-				if len(testFiles) == 0 {
-					return fmt.Errorf("no test files matched filter: %s", runFilter)
-				}
-			} else {
-				// Single test file
-				testFiles = []string{path}
+			// Expected outputs generated by another tool version are warned about
+			runToolVersion = toolVersion
+			if runToolVersion == "" {
+				runToolVersion = detectToolVersion(targetConfig)
 			}
 
-			// Load or create target config once for all tests
-			var targetConfig *config.TargetConfig
-			if targetConfigFile != "" {
-				log.Info("Loading target configuration", "file", targetConfigFile)
-				targetConfig, err = config.LoadTargetConfig(targetConfigFile)
+			// Only rerun the tests that failed in the previous run on this target
+			if failedOnly {
+				previousRun, failed, err := previousFailedTests(resultsStore, targetConfig.Type, testFiles)
 				if err != nil {
-					return fmt.Errorf("failed to load target config: %w", err)
+					return err
 				}
-			} else if targetType != "" {
-				// Try to auto-discover config file for the specified target type
-				discoveredPath := fmt.Sprintf(".koncur/config/target-%s.yaml", targetType)
-				if _, err := os.Stat(discoveredPath); err == nil {
-					log.Info("Auto-discovered target configuration", "file", discoveredPath)
-					targetConfig, err = config.LoadTargetConfig(discoveredPath)
-					if err != nil {
-						return fmt.Errorf("failed to load auto-discovered target config: %w", err)
-					}
-				} else {
-					// Create default config for specified type
-					targetConfig = &config.TargetConfig{Type: targetType}
+				if len(failed) == 0 {
+					color.Green("%s No failed tests to rerun from run %s", symbolPass, previousRun)
+					return nil
 				}
-			} else {
-				// Default to kantra, try to auto-discover first
-				discoveredPath := ".koncur/config/target-kantra.yaml"
-				if _, err := os.Stat(discoveredPath); err == nil {
-					log.Info("Auto-discovered target configuration", "file", discoveredPath)
-					targetConfig, err = config.LoadTargetConfig(discoveredPath)
-					if err != nil {
-						return fmt.Errorf("failed to load auto-discovered target config: %w", err)
+				if len(args) == 0 {
+					if failed, err = selector.selected(failed); err != nil {
+						return err
+					}
+					if len(failed) == 0 {
+						color.Green("%s No failed tests from run %s matched %s", symbolPass, previousRun, selector)
+						return nil
 					}
-				} else {
-					// Create default kantra config
-					targetConfig = &config.TargetConfig{Type: "kantra"}
 				}
+				log.Info("Rerunning failed tests", "run", previousRun, "count", len(failed))
+				testFiles = failed
 			}
 
-			log.Info("Using target", "type", targetConfig.Type)
+			// Only run this CI job's slice of the suite
+			if testShard != nil {
+				var durations map[string]time.Duration
+				if shardBalance {
+					if durations, err = recordedDurations(resultsStore, targetConfig.Type); err != nil {
+						return err
+					}
+				}
+				total := len(testFiles)
+				testFiles = testShard.selectTests(testFiles, durations)
+				log.Info("Selected shard", "shard", testShard.String(), "count", len(testFiles), "of", total, "balanced", len(durations) > 0)
+				if len(testFiles) == 0 {
+					color.Yellow("%s No tests in shard %s (%d test(s) in total)", symbolWarn, testShard, total)
+					return nil
+				}
+			}
 
 			// Create target from config
 			target, err := targets.NewTarget(targetConfig)
 			if err != nil {
-				return fmt.Errorf("failed to create target: %w", err)
+				return configError("failed to create target: %w", err)
+			}
+			// Tests requiring providers the target does not have are skipped
+			if skipEnv.Providers == nil && requireProviders(testFiles) {
+				skipEnv.Providers = targetProviders(target)
 			}
 
 			// Run all tests
 			startTime := time.Now()
 			successCount := 0
 			failCount := 0
+			flakyCount := 0
 			skippedCount := 0
 			var allResults []TestResult
+			var bar *progress
+			if len(testFiles) > 1 && showProgress() {
+				bar = newProgress(testFiles, progressHistory(resultsStore, targetConfig.Type), repeatCount)
+			}
+			// abortedBy is the test whose failure stopped the run with --fail-fast
+			abortedBy := ""
+			notRun := 0
 
 			for i, testFile := range testFiles {
-				testName := filepath.Base(filepath.Dir(testFile))
-				if len(testFiles) > 1 && outputFormat == "console" {
-					fmt.Printf("\n[%d/%d] Running: %s\n", i+1, len(testFiles), testName)
+				testName := resultName(testFile)
+
+				// The remaining tests are reported as skipped, so the results still list the whole suite
+				if abortedBy != "" {
+					allResults = append(allResults, TestResult{
+						Name:       testName,
+						TestFile:   testFile,
+						Target:     targetConfig.Type,
+						Status:     "skipped",
+						Duration:   "0s",
+						SkipReason: fmt.Sprintf("not run, %s failed with --fail-fast", abortedBy),
+					})
+					skippedCount++
+					notRun++
+					continue
+				}
+
+				if bar != nil {
+					fmt.Printf("\n%s\n", bar.start(i, time.Now()))
 				}
 
 				// Check if test is marked as skipped
-				if isTestSkipped(testFile) {
+				if reason, skipped := testSkip(testFile, skipEnv); skipped {
 					skippedResult := TestResult{
-						Name:     testName,
-						TestFile: testFile,
-						Status:   "skipped",
-						Duration: "0s",
+						Name:       testName,
+						TestFile:   testFile,
+						Target:     targetConfig.Type,
+						Status:     "skipped",
+						Duration:   "0s",
+						SkipReason: reason,
 					}
 					allResults = append(allResults, skippedResult)
-					if outputFormat == "console" {
-						color.Yellow("  ⊘ Skipped (marked as SKIPPED in file)")
+					if showProgress() {
+						color.Yellow("  %s Skipped%s", symbolSkip, skipSuffix(reason))
 					}
 					skippedCount++
 					continue
 				}
 
-				// Run single test
-				testResult, err := runSingleTest(testFile, target, targetConfig)
+				// Run the test, repeatedly when --repeat or --until-failure is set
+				testResult, err := runRepeatedTest(testFile, target, targetConfig)
 				if err != nil {
-					if outputFormat == "console" {
-						color.Red("  ✗ Error: %v", err)
+					if showProgress() {
+						color.Red("  %s Error: %v", symbolFail, err)
 					}
 					failCount++
 					if testResult != nil {
 						allResults = append(allResults, *testResult)
 					}
+					if failFast {
+						abortedBy = testName
+					}
 					continue
 				}
 
 				allResults = append(allResults, *testResult)
-				if testResult.Status == "passed" {
+				switch testResult.Status {
+				case "passed":
 					successCount++
-				} else {
+				case "flaky":
+					flakyCount++
+				default:
 					failCount++
+					if failFast {
+						abortedBy = testName
+					}
 				}
 			}
 
+			if notRun > 0 {
+				color.Yellow("\n%s Stopped after %s failed (--fail-fast), %d test(s) not run", symbolWarn, abortedBy, notRun)
+			}
+
 			totalDuration := time.Since(startTime)
 
 			// Create summary
@@ -183,11 +286,49 @@ You can provide either:
 				Total:    len(testFiles),
 				Passed:   successCount,
 				Failed:   failCount,
+				Flaky:    flakyCount,
 				Skipped:  skippedCount,
 				Duration: totalDuration.String(),
 				Tests:    allResults,
 			}
 
+			run := NewRunRecord(startTime, targetConfig.Type, runToolVersion, summary)
+			if !noStore {
+				store, err := OpenResultsStore(resultsStore)
+				if err != nil {
+					return err
+				}
+				if err := store.SaveRun(run); err != nil {
+					return fmt.Errorf("failed to record run: %w", err)
+				}
+				// Outputs are kept so later runs can be compared with this one
+				for _, result := range allResults {
+					if result.actual == nil {
+						continue
+					}
+					if err := store.SaveOutput(run.ID, run.Target, result.Name, result.actual); err != nil {
+						return fmt.Errorf("failed to record output of %s: %w", result.Name, err)
+					}
+				}
+				log.Info("Recorded run in results store", "store", resultsStore, "id", run.ID)
+			}
+
+			// Metrics are best effort - an unreachable Pushgateway must not fail the run
+			if metricsPushURL != "" {
+				if err := PushMetrics(metricsPushURL, metricsJob, FormatPrometheusMetrics(run, metricsLabels)); err != nil {
+					log.Error(err, "Failed to push metrics", "url", metricsPushURL)
+				} else {
+					log.Info("Pushed metrics", "url", metricsPushURL, "job", metricsJob)
+				}
+			}
+
+			if summaryFile != "" {
+				if err := WriteMarkdownSummary(summary, summaryFile); err != nil {
+					return err
+				}
+				log.Info("Wrote Markdown summary", "file", summaryFile)
+			}
+
 			// Output based on format
 			if outputFormat != "console" {
 				formatted, err := FormatResults(summary, OutputFormat(outputFormat))
@@ -204,116 +345,500 @@ You can provide either:
 				} else {
 					fmt.Println(formatted)
 				}
+			}
 
-				// Print summary to console even when writing to file
-				fmt.Println("\n" + strings.Repeat("=", 60))
-				fmt.Printf("Summary: %d total\n", len(testFiles))
-				if successCount > 0 {
-					color.Green("  ✓ Passed: %d", successCount)
-				}
-				if skippedCount > 0 {
-					color.Yellow("  ⊘ Skipped: %d", skippedCount)
-				}
-				if failCount > 0 {
-					color.Red("  ✗ Failed: %d", failCount)
-				}
-			} else {
-				// Console format - print summary if multiple tests
-				if len(testFiles) > 1 {
-					fmt.Println("\n" + strings.Repeat("=", 60))
-					fmt.Printf("Summary: %d total\n", len(testFiles))
-					if successCount > 0 {
-						color.Green("  ✓ Passed: %d", successCount)
-					}
-					if skippedCount > 0 {
-						color.Yellow("  ⊘ Skipped: %d", skippedCount)
-					}
-					if failCount > 0 {
-						color.Red("  ✗ Failed: %d", failCount)
-						return nil
-					}
-				} else if failCount > 0 {
-					return nil
-				}
+			// Structured output always gets a console summary; plain console output
+			// only needs one when several tests ran or per-test output was suppressed
+			if outputFormat != "console" || len(testFiles) > 1 || quietOutput {
+				printRunSummary(summary)
 			}
 
-			return nil
+			switch code := runExitCode(allResults); code {
+			case ExitCodeSuccess:
+				return nil
+			case ExitCodeFlaky:
+				return withExitCode(code, "%d flaky test(s) detected", flakyCount)
+			default:
+				return withExitCode(code, "%d of %d test(s) failed", failCount, len(testFiles))
+			}
 		},
 	}
 
 	// Flags
-	runCmd.Flags().StringVarP(&targetConfigFile, "target-config", "c", "", "Path to target configuration file")
+	runCmd.Flags().StringVarP(&targetConfigFile, "target-config", "c", "", "Path to target configuration file (default: .koncur/config/target-<type>.yaml if present)")
 	runCmd.Flags().StringVarP(&targetType, "target", "t", "", "Target type (kantra, tackle-hub, tackle-ui, kai-rpc, vscode)")
-	runCmd.Flags().StringVarP(&runFilter, "filter", "f", "", "Filter tests by name pattern (only applies when running a directory)")
+	runCmd.Flags().StringArrayVarP(&runFilters, "filter", "f", nil, "Only run tests whose directory name matches this regular expression (repeatable, any may match)")
+	runCmd.Flags().StringArrayVar(&runExcludes, "exclude", nil, "Skip tests whose directory name matches this regular expression (repeatable)")
+	runCmd.Flags().StringSliceVar(&runTags, "tags", nil, "Only run tests with any of these tags (comma-separated or repeatable)")
+	runCmd.Flags().StringSliceVar(&runExcludeTags, "exclude-tags", nil, "Skip tests with any of these tags (comma-separated or repeatable)")
 	runCmd.Flags().StringVarP(&outputFormat, "output-format", "o", "console", "Output format: console, json, yaml, junit")
 	runCmd.Flags().StringVar(&outputFile, "output-file", "", "File path to write test results (only for json, yaml, junit formats)")
+	runCmd.Flags().StringVar(&resultsStore, "results-store", defaultResultsStore, "Results store location used for history and 'koncur stats'")
+	runCmd.Flags().BoolVar(&noStore, "no-store", false, "Do not record this run in the results store")
+	runCmd.Flags().StringVar(&toolVersion, "tool-version", os.Getenv("KONCUR_TOOL_VERSION"), "Version of the tool under test, recorded with the run (default: $KONCUR_TOOL_VERSION)")
+	runCmd.Flags().StringVar(&metricsPushURL, "metrics-push-url", os.Getenv("KONCUR_PUSHGATEWAY_URL"), "Prometheus Pushgateway URL to push run metrics to (default: $KONCUR_PUSHGATEWAY_URL)")
+	runCmd.Flags().StringVar(&metricsJob, "metrics-job", "koncur", "Pushgateway job name for pushed metrics")
+	runCmd.Flags().StringToStringVar(&metricsLabels, "metrics-label", nil, "Extra label added to pushed metrics (key=value, repeatable)")
+	runCmd.Flags().StringVar(&summaryFile, "summary-file", "", "Append a Markdown results table to this file (e.g. $GITHUB_STEP_SUMMARY)")
+	runCmd.Flags().BoolVar(&strictValidation, "strict", false, "Report unexpected findings that targets tolerate by default (same as 'validation.strict' in every test)")
+	runCmd.Flags().BoolVar(&comparePrevious, "compare-previous", false, "Compare each test's output with its previous recorded run instead of the expected output (same as 'expect.baseline: previous-run')")
+	runCmd.Flags().BoolVar(&updateExpected, "update-expected", false, "Rewrite the expected output of tests that fail validation from their actual output")
+	runCmd.Flags().BoolVar(&failOnProviderErrors, "fail-on-provider-errors", false, "Fail tests whose analysis or provider logs report provider errors (same as 'validation.failOnProviderErrors' in every test)")
+	runCmd.Flags().BoolVar(&verboseValidation, "verbose-validation", false, "List every validation error instead of grouping similar errors per rule")
+	runCmd.Flags().BoolVar(&failedOnly, "failed-only", false, "Only run the tests that failed in the previous recorded run on the same target (the path, if given, narrows them down)")
+	runCmd.Flags().StringVar(&shardSpec, "shard", "", "Only run shard i of n of the selected tests (i/n, e.g. 2/5), for splitting a suite across parallel CI jobs")
+	runCmd.Flags().BoolVar(&shardBalance, "shard-balance", false, "Balance shards by the test durations recorded in the results store instead of hashing test names")
+	runCmd.Flags().BoolVar(&failFast, "fail-fast", false, "Stop after the first failed test; the remaining tests are reported as skipped")
+	runCmd.Flags().BoolVarP(&quietOutput, "quiet", "q", false, "Only print the run summary, not per-test progress")
+	runCmd.Flags().IntVar(&repeatCount, "repeat", 1, "Run each test N times and mark tests with mixed outcomes as flaky")
+	runCmd.Flags().BoolVar(&untilFailure, "until-failure", false, fmt.Sprintf("Repeat each test until it fails (at most --repeat times, or %d if --repeat is not set)", untilFailureLimit))
 
 	return runCmd
 }
 
-// runSingleTest executes a single test and returns the test result
-func runSingleTest(testFile string, target targets.Target, targetConfig *config.TargetConfig) (*TestResult, error) {
-	testName := filepath.Base(filepath.Dir(testFile))
+// NewRerunFailedCmd creates the rerun-failed command, a shortcut for 'run --failed-only'
+func NewRerunFailedCmd() *cobra.Command {
+	rerunCmd := NewRunCmd()
+	rerunCmd.Use = "rerun-failed [test-file-or-directory]..."
+	rerunCmd.Short = "Run the tests that failed in the previous run"
+	rerunCmd.Long = `Run only the tests that failed in the previous run recorded in the results store
+on the same target, the same as 'koncur run --failed-only'. Paths and test selection
+flags narrow the rerun down to the failed tests they select.`
+	_ = rerunCmd.Flags().MarkHidden("failed-only")
+	runE := rerunCmd.RunE
+	rerunCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		failedOnly = true
+		return runE(cmd, args)
+	}
+	return rerunCmd
+}
 
-	// Initialize test result
-	testResult := &TestResult{
-		Name:     testName,
-		TestFile: testFile,
-		Status:   "unknown",
+// loadTargetConfig loads the given target configuration file. Without one, it
+// auto-discovers .koncur/config/target-<type>.yaml (type defaulting to kantra),
+// falling back to a default configuration of the target type. The project's
+// validation defaults apply below the target's.
+func loadTargetConfig(file, targetType string) (*config.TargetConfig, error) {
+	targetConfig, err := findTargetConfig(file, targetType)
+	if err != nil {
+		return nil, err
 	}
+	applyProjectValidation(targetConfig)
+	return targetConfig, nil
+}
 
+func findTargetConfig(file, targetType string) (*config.TargetConfig, error) {
+	log := util.GetLogger()
+	if file != "" {
+		log.Info("Loading target configuration", "file", file)
+		targetConfig, err := config.LoadTargetConfig(file)
+		if err != nil {
+			return nil, configError("failed to load target config: %w", err)
+		}
+		return targetConfig, nil
+	}
+
+	if targetType == "" {
+		targetType = "kantra"
+	}
+	discoveredPath := fmt.Sprintf(".koncur/config/target-%s.yaml", targetType)
+	if _, err := os.Stat(discoveredPath); err != nil {
+		// Create default config for the target type
+		return &config.TargetConfig{Type: targetType}, nil
+	}
+	log.Info("Auto-discovered target configuration", "file", discoveredPath)
+	targetConfig, err := config.LoadTargetConfig(discoveredPath)
+	if err != nil {
+		return nil, configError("failed to load auto-discovered target config: %w", err)
+	}
+	return targetConfig, nil
+}
+
+// discoverTestFiles returns the test files given as paths, and the test files found
+// in the directories given as paths, that the selector selects
+func discoverTestFiles(paths []string, selector *testSelector) ([]string, error) {
+	log := util.GetLogger()
+
+	var testFiles []string
+	seen := map[string]bool{}
+	for _, path := range paths {
+		found, err := testFilesAt(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, testFile := range found {
+			// Overlapping paths must not run a test twice
+			if abs := absPath(testFile); !seen[abs] {
+				seen[abs] = true
+				testFiles = append(testFiles, testFile)
+			}
+		}
+	}
+
+	log.Info("Found test files", "count", len(testFiles))
+
+	if selector.empty() {
+		return testFiles, nil
+	}
+	testFiles, err := selector.selected(testFiles)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Selected test files", "count", len(testFiles), "selection", selector.String())
+
+	if len(testFiles) == 0 {
+		return nil, configError("no test files matched %s", selector)
+	}
+	return testFiles, nil
+}
+
+// testFilesAt returns the test file at a path, the test files found in a directory,
+// or the test files of a suite file
+func testFilesAt(path string) ([]string, error) {
+	log := util.GetLogger()
+
+	// Tests shared in a git repository are fetched first
+	if isRemoteTestRef(path) {
+		dir, err := fetchRemoteTests(path)
+		if err != nil {
+			return nil, err
+		}
+		return testFilesAt(dir)
+	}
+
+	// A variant of a matrix test is given as test.yaml#variant
+	file, variant := config.SplitTestRef(path)
+	if variant != "" {
+		if _, err := os.Stat(file); err != nil {
+			return nil, configError("failed to stat path: %w", err)
+		}
+		return []string{path}, nil
+	}
+
+	// Check if path is a file or directory
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, configError("failed to stat path: %w", err)
+	}
+	if !info.IsDir() {
+		if !config.IsSuiteFile(path) {
+			return testVariants(path), nil
+		}
+		suite, err := config.LoadSuite(path)
+		if err != nil {
+			return nil, configError("%w", err)
+		}
+		log.Info("Loaded suite", "name", suite.Name, "file", path)
+		var found []string
+		for _, testPath := range suite.TestPaths() {
+			testFiles, err := testFilesAt(testPath)
+			if err != nil {
+				return nil, err
+			}
+			found = append(found, testFiles...)
+		}
+		return found, nil
+	}
+
+	// Find all test.yaml files in directory
+	log.Info("Searching for test files", "directory", path)
+	found, err := findTestFiles(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find test files: %w", err)
+	}
+	if len(found) == 0 {
+		return nil, configError("no test files found in %s", path)
+	}
+	return found, nil
+}
+
+// testVariants returns a reference to each variant of a test with a matrix, or the
+// test file. Tests that fail to load are returned as they are, to report the error.
+func testVariants(testFile string) []string {
+	variants, err := config.TestVariants(testFile)
+	if err != nil {
+		return []string{testFile}
+	}
+	return variants
+}
+
+// previousFailedTests returns the ID of the latest recorded run on a target and the
+// files of its tests that failed. When candidates are given, only failed tests
+// among them are returned.
+func previousFailedTests(location, target string, candidates []string) (string, []string, error) {
+	store, err := OpenResultsStore(location)
+	if err != nil {
+		return "", nil, err
+	}
+	runs, err := store.ListRuns()
+	if err != nil {
+		return "", nil, err
+	}
+	var previous *RunRecord
+	for _, run := range runs {
+		if run.Target == target && run.Summary != nil {
+			previous = run
+		}
+	}
+	if previous == nil {
+		return "", nil, configError("no previous run on target %s in results store %s", target, location)
+	}
+
+	allowed := map[string]bool{}
+	for _, file := range candidates {
+		allowed[absPath(file)] = true
+	}
+	var failed []string
+	for _, result := range previous.Summary.Tests {
+		if result.Status != "failed" || result.TestFile == "" {
+			continue
+		}
+		if candidates != nil && !allowed[absPath(result.TestFile)] {
+			continue
+		}
+		if path, _ := config.SplitTestRef(result.TestFile); !pathExists(path) {
+			util.GetLogger().Info("Skipping failed test that no longer exists", "file", result.TestFile)
+			continue
+		}
+		failed = append(failed, result.TestFile)
+	}
+	return previous.ID, failed, nil
+}
+
+// pathExists reports whether a file or directory exists
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// absPath returns the absolute form of a path, or the path itself if it cannot be resolved
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// showProgress reports whether per-test progress is printed to the console
+func showProgress() bool {
+	return outputFormat == "console" && !quietOutput
+}
+
+// printRunSummary prints the pass/fail counts of a run
+func printRunSummary(summary *TestSummary) {
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	fmt.Printf("Summary: %d total\n", summary.Total)
+	if summary.Passed > 0 {
+		color.Green("  %s Passed: %d", symbolPass, summary.Passed)
+	}
+	if summary.Skipped > 0 {
+		color.Yellow("  %s Skipped: %d", symbolSkip, summary.Skipped)
+	}
+	if summary.Flaky > 0 {
+		color.Yellow("  %s Flaky: %d", symbolWarn, summary.Flaky)
+	}
+	if summary.Failed > 0 {
+		color.Red("  %s Failed: %d", symbolFail, summary.Failed)
+	}
+}
+
+// runRepeatedTest runs a test as many times as --repeat/--until-failure ask for and
+// folds the attempts into a single result. A test that both passed and failed is flaky.
+func runRepeatedTest(testFile string, target targets.Target, targetConfig *config.TargetConfig) (*TestResult, error) {
+	attempts := repeatCount
+	if untilFailure && attempts == 1 {
+		attempts = untilFailureLimit
+	}
+	if attempts == 1 {
+		return runSingleTest(testFile, target, targetConfig)
+	}
+
+	var result *TestResult
+	var resultErr error
+	var total time.Duration
+	passed, run := 0, 0
+	for run < attempts {
+		if showProgress() {
+			fmt.Printf("  Attempt %d/%d\n", run+1, attempts)
+		}
+		attempt, err := runSingleTest(testFile, target, targetConfig)
+		run++
+		if attempt == nil {
+			return nil, err
+		}
+		if d, perr := time.ParseDuration(attempt.Duration); perr == nil {
+			total += d
+		}
+		// Report the first failing attempt, later ones usually repeat it
+		if result == nil || (result.Status == "passed" && attempt.Status != "passed") {
+			result, resultErr = attempt, err
+		}
+		if attempt.Status == "passed" {
+			passed++
+		} else if untilFailure {
+			break
+		}
+	}
+
+	result.Attempts = run
+	result.PassedAttempts = passed
+	result.Duration = total.String()
+	if passed > 0 && passed < run {
+		result.Status = "flaky"
+		if showProgress() {
+			color.Yellow("  %s FLAKY - passed %d of %d attempts", symbolWarn, passed, run)
+		}
+		return result, nil
+	}
+	return result, resultErr
+}
+
+// runSingleTest executes a single test and returns the test result
+func runSingleTest(testFile string, target targets.Target, targetConfig *config.TargetConfig) (*TestResult, error) {
 	startTime := time.Now()
 
 	// Load test definition
 	test, err := config.Load(testFile)
 	if err != nil {
+		testResult := newTestResult(testFile, targetConfig)
 		testResult.Status = "failed"
 		testResult.ErrorMessage = fmt.Sprintf("failed to load test: %v", err)
+		testResult.FailureKind = FailureConfig
 		testResult.Duration = time.Since(startTime).String()
 		return testResult, fmt.Errorf("failed to load test: %w", err)
 	}
+	applyProjectTolerance(test)
+	return runLoadedTest(testFile, test, target, targetConfig)
+}
+
+// testLogFile is the file of a test's work directory its logs are saved to
+const testLogFile = "koncur.log"
+
+// saveTestLogs writes the logs captured while a test ran into its work directory
+func saveTestLogs(testResult *TestResult, logs []byte) {
+	if testResult.WorkDir == "" || len(logs) == 0 {
+		return
+	}
+	path := filepath.Join(testResult.WorkDir, testLogFile)
+	if err := os.WriteFile(path, logs, 0644); err != nil {
+		util.GetLogger().Error(err, "Failed to save test logs", "file", path)
+		return
+	}
+	testResult.LogFile = path
+}
+
+// newTestResult initializes the result of a test
+func newTestResult(testFile string, targetConfig *config.TargetConfig) *TestResult {
+	testResult := &TestResult{
+		Name:     resultName(testFile),
+		TestFile: testFile,
+		Status:   "unknown",
+	}
+	if targetConfig != nil {
+		testResult.Target = targetConfig.Type
+	}
+	return testResult
+}
+
+// runLoadedTest executes a loaded test definition and validates its output
+func runLoadedTest(testFile string, test *config.TestDefinition, target targets.Target, targetConfig *config.TargetConfig) (*TestResult, error) {
+	testResult := newTestResult(testFile, targetConfig)
+
+	startTime := time.Now()
+	stopCapture := util.CaptureLogs()
+	// Every exit path reports a duration, including parse failures, and keeps
+	// the test's logs in its work directory
+	defer func() {
+		if testResult.Duration == "" {
+			testResult.Duration = time.Since(startTime).String()
+		}
+		saveTestLogs(testResult, stopCapture())
+	}()
 
 	// Validate test definition
 	if err := config.Validate(test); err != nil {
 		testResult.Status = "failed"
 		testResult.ErrorMessage = fmt.Sprintf("invalid test definition: %v", err)
-		testResult.Duration = time.Since(startTime).String()
+		testResult.FailureKind = FailureConfig
 		return testResult, fmt.Errorf("invalid test definition: %w", err)
 	}
 
+	switch test.Kind {
+	case config.KindListLabels:
+		return runListLabelsTest(testResult, test, target)
+	case config.KindTransform:
+		return runTransformTest(testResult, test, target)
+	}
+
+	// Fix suggestions need a target that makes them, fail before analyzing
+	suggester, canSuggest := target.(targets.FixSuggester)
+	if test.Fixes != nil && !canSuggest {
+		testResult.Status = "failed"
+		testResult.ErrorMessage = fmt.Sprintf("target %s cannot suggest fixes", target.Name())
+		testResult.FailureKind = FailureConfig
+		return testResult, fmt.Errorf("target %s cannot suggest fixes", target.Name())
+	}
+
 	// Execute the test
 	result, err := target.Execute(context.Background(), test)
 	if err != nil {
 		testResult.Status = "failed"
 		testResult.ErrorMessage = fmt.Sprintf("execution failed: %v", err)
-		testResult.Duration = time.Since(startTime).String()
+		testResult.FailureKind = FailureExecution
 		return testResult, fmt.Errorf("execution failed: %w", err)
 	}
 
 	testResult.ExitCode = result.ExitCode
 	testResult.ExpectedExitCode = test.Expect.ExitCode
-	testResult.Duration = result.Duration.String()
+	testResult.OutputFile = result.OutputFile
+	testResult.WorkDir = result.WorkDir
+	testResult.ExpectedFile = test.Expect.Output.ResolvedFilePath
+	testResult.ProviderErrors = providerLogIssues(result.WorkDir)
 
-	// Check exit code
-	if result.ExitCode != test.Expect.ExitCode {
+	// Check exit code, or the exit status the target maps it to
+	got := strconv.Itoa(result.ExitCode)
+	matches := test.Expect.ExitCode.Matches(result.ExitCode)
+	if test.Expect.ExitCode.HasStatus() {
+		var mapping *config.ExitCodeMapping
+		if targetConfig != nil {
+			mapping = targetConfig.ExitCodes
+		}
+		status := mapping.Status(result.ExitCode, outputHasFindings(result.OutputFile))
+		got = fmt.Sprintf("%d (%s)", result.ExitCode, status)
+		matches = test.Expect.ExitCode.MatchesStatus(result.ExitCode, status)
+	}
+	if !matches {
 		testResult.Status = "failed"
-		testResult.ErrorMessage = fmt.Sprintf("Exit code mismatch: expected %d, got %d", test.Expect.ExitCode, result.ExitCode)
-		if outputFormat == "console" {
-			color.Red("  ✗ Exit code mismatch: expected %d, got %d", test.Expect.ExitCode, result.ExitCode)
+		testResult.ErrorMessage = fmt.Sprintf("Exit code mismatch: expected %s, got %s", test.Expect.ExitCode, got)
+		testResult.FailureKind = FailureValidation
+		if showProgress() {
+			color.Red("  %s Exit code mismatch: expected %s, got %s", symbolFail, test.Expect.ExitCode, got)
 		}
 		return testResult, nil
 	}
 
-	// Parse the output
-	actualOutput, err := parser.ParseOutput(result.OutputFile)
+	// Parse the output, converting output of older kantra releases
+	actualOutput, schemaVersion, err := parser.ParseOutputVersion(result.OutputFile)
 	if err != nil {
 		testResult.Status = "failed"
 		testResult.ErrorMessage = fmt.Sprintf("failed to parse output: %v", err)
+		testResult.FailureKind = FailureExecution
 		return testResult, fmt.Errorf("failed to parse output: %w", err)
 	}
+	if schemaVersion != parser.SchemaCurrent {
+		testResult.OutputSchema = schemaVersion
+		util.GetLogger().Info("Converted output of an older schema", "schema", schemaVersion, "file", result.OutputFile)
+	}
+
+	// Get target type and validation policy; the test's settings override the target's
+	tgtType := ""
+	validationConfig := test.Validation
+	if targetConfig != nil {
+		tgtType = targetConfig.Type
+		validationConfig = targetConfig.Validation.Merge(test.Validation)
+	}
 
 	// Filter actual output to match how expected output is filtered during generation
-	filteredActual := parser.FilterRuleSets(actualOutput)
+	filteredActual := validationConfig.Filter.Apply(actualOutput)
 	testResult.RuleSetsCount = len(filteredActual)
 	testResult.FilteredFrom = len(actualOutput)
 
@@ -322,58 +847,351 @@ func runSingleTest(testFile string, target targets.Target, targetConfig *config.
 	if err != nil {
 		testResult.Status = "failed"
 		testResult.ErrorMessage = fmt.Sprintf("failed to normalize paths: %v", err)
+		testResult.FailureKind = FailureExecution
 		return testResult, fmt.Errorf("failed to normalize paths: %w", err)
 	}
 
-	// Get target type for validation
-	tgtType := ""
-	if targetConfig != nil {
-		tgtType = targetConfig.Type
+	testResult.actual = normalizedActual
+
+	// --strict applies on top of the test's own validation settings
+	if strictValidation {
+		validationConfig.Strict = true
 	}
+	validationConfig.IncidentLimit = test.Analysis.IncidentLimit
+	validationConfig.CodeSnipLimit = test.Analysis.CodeSnipLimit
 
-	// Validate against expected output using the filtered file
-	validation, err := validator.ValidateFiles(test.GetTestDir(), tgtType, normalizedActual, test.Expect.Output.Result)
+	// Silent provider failures show up as missing violations, fail them as what they are
+	if (failOnProviderErrors || validationConfig.FailOnProviderErrors) && len(testResult.ProviderErrors) > 0 {
+		testResult.Status = "failed"
+		testResult.ErrorMessage = fmt.Sprintf("%d provider error(s) in analysis logs, first: %s", len(testResult.ProviderErrors), testResult.ProviderErrors[0])
+		testResult.FailureKind = FailureExecution
+		if showProgress() {
+			color.Red("  %s FAILED - provider errors in analysis logs", symbolFail)
+			printProviderErrors(testResult.ProviderErrors)
+		}
+		return testResult, nil
+	}
+
+	// Validate against expected output using the filtered file, or against the
+	// test's previous run when it is regression-gated
+	var validation *validator.ValidationResult
+	if comparePrevious || test.Expect.Baseline == config.BaselinePreviousRun {
+		validation, err = validatePreviousRun(testResult, normalizedActual)
+	} else if len(test.Analysis.Applications) > 0 {
+		validation, err = validateApplications(test, result, tgtType, validationConfig)
+	} else {
+		// Variables such as ${APP_DIR} resolve to this machine's locations
+		expect := test.Expect
+		expect.Output.Result = config.ExpandExpectedOutput(expect.Output.Result, test.ExpectedOutputVariables())
+		validation, err = validator.ValidateExpectations(test.GetTestDir(), tgtType, normalizedActual, expect, validationConfig)
+	}
 	if err != nil {
 		testResult.Status = "failed"
 		testResult.ErrorMessage = fmt.Sprintf("validation error: %v", err)
+		testResult.FailureKind = FailureExecution
 		return testResult, fmt.Errorf("validation error: %w", err)
 	}
 
+	// expect.maxDuration makes analysis performance regressions visible; its errors,
+	// those of expect.providers, issues tables, editor diagnostics and fixes fail
+	// tests regardless of allowed mismatches
+	var gateErrors []validator.ValidationError
+	if exceeded := durationExceeded(test, result.Duration); exceeded != nil {
+		if validationConfig.DurationBudget == config.DurationBudgetWarn {
+			validation.Warnings = append(validation.Warnings, *exceeded)
+		} else {
+			gateErrors = append(gateErrors, *exceeded)
+		}
+	}
+
+	// expect.providers catches providers that were silently skipped
+	providers := validator.ValidateProviders(test.Expect.Providers, result.Providers, normalizedActual)
+	validation.Warnings = append(validation.Warnings, providers.Warnings...)
+	gateErrors = append(gateErrors, providers.Errors...)
+
+	// Issues tables, e.g. the Tackle UI's, are checked for aggregation bugs
+	if lister, ok := target.(targets.IssueLister); ok && !comparePrevious && test.Expect.Baseline == "" && len(test.Analysis.Applications) == 0 {
+		shown, err := lister.Issues(context.Background(), test, result)
+		if err != nil {
+			testResult.Status = "failed"
+			testResult.ErrorMessage = fmt.Sprintf("failed to list issues: %v", err)
+			testResult.FailureKind = FailureExecution
+			return testResult, fmt.Errorf("failed to list issues: %w", err)
+		}
+		gateErrors = append(gateErrors, validator.ValidateIssues(test.Expect.Output, shown).Errors...)
+	}
+
+	// Editor diagnostics, e.g. VSCode's, are checked for filtering and rendering bugs
+	if lister, ok := target.(targets.DiagnosticLister); ok && !comparePrevious && test.Expect.Baseline == "" && len(test.Analysis.Applications) == 0 {
+		diagnostics, err := lister.Diagnostics(context.Background(), test, result)
+		if err != nil {
+			testResult.Status = "failed"
+			testResult.ErrorMessage = fmt.Sprintf("failed to list diagnostics: %v", err)
+			testResult.FailureKind = FailureExecution
+			return testResult, fmt.Errorf("failed to list diagnostics: %w", err)
+		}
+		expected := test.Expect.Output
+		expected.Result = config.ExpandExpectedOutput(expected.Result, test.ExpectedOutputVariables())
+		gateErrors = append(gateErrors, validator.ValidateDiagnostics(expected, diagnostics, test.GetTestDir()).Errors...)
+	}
+
+	// Fixes suggested for selected incidents, e.g. by Kai, are checked for their structure
+	if test.Fixes != nil {
+		suggest := func(ruleset, rule string, incident konveyor.Incident) (*parser.FixSuggestion, error) {
+			return suggester.SuggestFix(context.Background(), test, ruleset, rule, incident)
+		}
+		fixes, err := validator.ValidateFixes(test.Fixes, filteredActual, suggest)
+		if err != nil {
+			testResult.Status = "failed"
+			testResult.ErrorMessage = fmt.Sprintf("fix suggestion failed: %v", err)
+			testResult.FailureKind = FailureExecution
+			return testResult, fmt.Errorf("fix suggestion failed: %w", err)
+		}
+		gateErrors = append(gateErrors, fixes.Errors...)
+	}
+
 	// Report results
-	if validation.Passed {
+	testResult.ValidationWarnings = validation.Warnings
+	if !comparePrevious && test.Expect.Baseline == "" {
+		testResult.ValidationWarnings = append(testResult.ValidationWarnings, baselineWarnings(test, tgtType, runToolVersion)...)
+	}
+	if showProgress() {
+		for _, warning := range testResult.ValidationWarnings {
+			color.Yellow("  %s %s", symbolWarn, warning.Message)
+		}
+	}
+	if validation.Passed && len(gateErrors) == 0 {
 		testResult.Status = "passed"
-		if outputFormat == "console" {
+		// Mismatches within expect.allowedMismatches are kept for visibility
+		testResult.ValidationErrors = validation.Errors
+		if showProgress() {
 			green := color.New(color.FgGreen, color.Bold)
-			green.Printf("  ✓ PASSED")
+			green.Printf("  %s PASSED", symbolPass)
 			fmt.Printf(" - Duration: %s, RuleSets: %d (filtered from %d)\n", result.Duration, len(filteredActual), len(actualOutput))
+			if len(validation.Errors) > 0 {
+				color.Yellow("  %s %d mismatch(es) within allowedMismatches (%d)", symbolWarn, len(validation.Errors), validation.AllowedMismatches)
+			}
 		}
 		return testResult, nil
 	}
 
+	// Accept the actual output as the new expected output, like golden file updates
+	if updateExpected && !comparePrevious && test.Expect.Baseline == "" {
+		updated, err := updateExpectedOutput(testFile, test, tgtType, filteredActual, normalizedActual, len(validation.Errors))
+		if err != nil {
+			testResult.Status = "failed"
+			testResult.ErrorMessage = fmt.Sprintf("failed to update expected output: %v", err)
+			testResult.FailureKind = FailureExecution
+			return testResult, fmt.Errorf("failed to update expected output: %w", err)
+		}
+		if updated {
+			testResult.UpdatedExpected = true
+			testResult.ExpectedFile = test.Expect.Output.ResolvedFilePath
+			if len(gateErrors) == 0 {
+				testResult.Status = "passed"
+				testResult.ValidationErrors = validation.Errors
+				return testResult, nil
+			}
+			// The output is accepted, the test still fails its other checks
+			validation.Errors = nil
+			validation.Diffs = nil
+		}
+	}
+	validation.Errors = append(validation.Errors, gateErrors...)
+
 	// Test failed - populate validation errors
 	testResult.Status = "failed"
+	testResult.FailureKind = FailureValidation
 	testResult.ValidationErrors = validation.Errors
+	testResult.ValidationDiffs = validation.Diffs
 
-	if outputFormat == "console" {
+	if showProgress() {
 		// Test failed
 		red := color.New(color.FgRed, color.Bold)
-		red.Println("  ✗ FAILED")
+		red.Printf("  %s FAILED\n", symbolFail)
 
 		// Print validation errors in a pretty format
 		if len(validation.Errors) > 0 {
 			fmt.Printf("\n    Found %d validation error(s):\n\n", len(validation.Errors))
 
-			for i, err := range validation.Errors {
+			collapsed := collapseUnlessVerbose(validation.Errors)
+			for i, err := range collapsed {
 				err.Print(i + 1)
+				if err.Count > 1 {
+					color.Yellow("... and %d more %s error(s) for this rule (--verbose-validation lists them)", err.Count-1, err.Code)
+				}
 
 				// Add spacing between errors
-				if i < len(validation.Errors)-1 {
+				if i < len(collapsed)-1 {
 					fmt.Println()
 				}
 			}
 			fmt.Println()
 		}
+		printValidationDiffs(validation.Diffs)
+		if len(testResult.ProviderErrors) > 0 {
+			color.Yellow("    Provider errors in the analysis logs may explain these differences:")
+			printProviderErrors(testResult.ProviderErrors)
+		}
 	}
 
 	return testResult, nil
 }
+
+// durationExceeded returns the error of an analysis slower than the test's
+// expect.maxDuration, or nil
+func durationExceeded(test *config.TestDefinition, duration time.Duration) *validator.ValidationError {
+	if test.Expect.MaxDuration == nil || duration <= test.Expect.MaxDuration.Duration {
+		return nil
+	}
+	return &validator.ValidationError{
+		Code:     validator.CodeDurationExceeded,
+		Path:     "expect.maxDuration",
+		Message:  fmt.Sprintf("Analysis took %s, over the maxDuration budget of %s", duration.Round(time.Millisecond), test.Expect.MaxDuration.Duration),
+		Expected: test.Expect.MaxDuration.Duration.String(),
+		Actual:   duration.Round(time.Millisecond).String(),
+	}
+}
+
+// maxPrintedProviderErrors caps the provider errors printed per test
+const maxPrintedProviderErrors = 5
+
+// providerLogIssues parses the logs written under a test's work directory for provider errors
+func providerLogIssues(workDir string) []parser.LogIssue {
+	log := util.GetLogger()
+	var issues []parser.LogIssue
+	for _, logFile := range findLogFiles(workDir) {
+		fileIssues, err := parser.ParseLogFile(logFile)
+		if err != nil {
+			log.V(1).Info("Skipping unreadable log", "file", logFile, "error", err.Error())
+			continue
+		}
+		issues = append(issues, fileIssues...)
+	}
+	return issues
+}
+
+// printProviderErrors prints the first provider errors of a test with their log locations
+func printProviderErrors(issues []parser.LogIssue) {
+	for i, issue := range issues {
+		if i == maxPrintedProviderErrors {
+			fmt.Printf("    ... and %d more\n", len(issues)-maxPrintedProviderErrors)
+			break
+		}
+		fmt.Printf("    %s %s (%s:%d)\n", symbolWarn, issue, filepath.Base(issue.File), issue.Line)
+	}
+}
+
+// validatePreviousRun compares a test's output with its output in the most recent
+// recorded run on the same target. Without a previous run the test passes and its
+// output becomes the baseline of the next run.
+func validatePreviousRun(testResult *TestResult, actual []konveyor.RuleSet) (*validator.ValidationResult, error) {
+	store, err := OpenResultsStore(resultsStore)
+	if err != nil {
+		return nil, err
+	}
+	runID, previous, err := PreviousOutput(store, testResult.Target, testResult.Name)
+	if err != nil {
+		return nil, err
+	}
+	if runID == "" {
+		if showProgress() {
+			color.Yellow("  %s No previous run of this test on %s, recording its output as the baseline", symbolWarn, testResult.Target)
+		}
+		return &validator.ValidationResult{Passed: true}, nil
+	}
+	testResult.BaselineRun = runID
+	util.GetLogger().Info("Comparing with previous run", "run", runID)
+	return validator.CompareRuns(previous, actual), nil
+}
+
+// outputHasFindings reports whether an analysis output has violations, telling the
+// exit statuses of successful analyses apart
+func outputHasFindings(outputFile string) bool {
+	rulesets, _, err := parser.ParseOutputVersion(outputFile)
+	if err != nil {
+		return false
+	}
+	return slices.ContainsFunc(rulesets, func(rs konveyor.RuleSet) bool { return len(rs.Violations) > 0 })
+}
+
+// validateApplications validates the output of each application of a test analyzing
+// several against the application's expected output
+func validateApplications(test *config.TestDefinition, result *targets.ExecutionResult, targetType string, cfg config.ValidationConfig) (*validator.ValidationResult, error) {
+	if len(result.Applications) != len(test.Analysis.Applications) {
+		return nil, fmt.Errorf("target wrote outputs for %d of %d applications", len(result.Applications), len(test.Analysis.Applications))
+	}
+	var names []string
+	var results []*validator.ValidationResult
+	for i, app := range test.Analysis.Applications {
+		output := result.Applications[i]
+		actual, _, err := parser.ParseOutputVersion(output.OutputFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse output of application %s: %w", app.Name, err)
+		}
+		normalized, err := parser.NormalizeRuleSets(cfg.Filter.Apply(actual), test.GetTestDir())
+		if err != nil {
+			return nil, fmt.Errorf("failed to normalize paths of application %s: %w", app.Name, err)
+		}
+		appTest := test.ForApplication(app)
+		expect := appTest.Expect
+		expect.Output.Result = config.ExpandExpectedOutput(expect.Output.Result, appTest.ExpectedOutputVariables())
+		validation, err := validator.ValidateExpectations(test.GetTestDir(), targetType, normalized, expect, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("application %s: %w", app.Name, err)
+		}
+		names = append(names, app.Name)
+		results = append(results, validation)
+	}
+	return validator.MergeApplicationResults(names, results), nil
+}
+
+// updateExpectedOutput rewrites a test's expected output file from its actual output and
+// prints the violations that changed. Inline expectations are moved to expected-output.yaml.
+// Tests with count-only expectations are left alone, rewriting would drop them.
+func updateExpectedOutput(testFile string, test *config.TestDefinition, targetType string, filtered, normalized []konveyor.RuleSet, mismatches int) (bool, error) {
+	if len(test.Expect.Output.IncidentCounts) > 0 {
+		if showProgress() {
+			color.Yellow("  %s Not updating expected output with incidentCount entries, update it manually", symbolWarn)
+		}
+		return false, nil
+	}
+	if len(test.Analysis.Applications) > 0 {
+		if showProgress() {
+			color.Yellow("  %s Not updating expected outputs of several applications, regenerate them with 'koncur generate'", symbolWarn)
+		}
+		return false, nil
+	}
+	if len(test.Expect.Output.Files) > 0 || (test.Expect.Providers != nil && len(test.Expect.Providers.Output) > 0) {
+		if showProgress() {
+			color.Yellow("  %s Not updating expected output merged from several files, update them manually", symbolWarn)
+		}
+		return false, nil
+	}
+
+	previous := config.ExpandExpectedOutput(test.Expect.Output.Result, test.ExpectedOutputVariables())
+	path := test.Expect.Output.ResolvedFilePath
+	if path == "" {
+		path = filepath.Join(test.GetTestDir(), test.VariantFile("expected-output.yaml"))
+	}
+	if err := saveFilteredOutput(filtered, path, test.GetTestDir(), generationMetadata(test, targetType, runToolVersion)); err != nil {
+		return false, err
+	}
+	if test.Expect.Output.File == "" {
+		test.Expect.Output.Result = nil
+		test.Expect.Output.File = test.VariantFilePattern("expected-output.yaml")
+		if err := config.SaveExpectations(testFile, test.Expect.ExitCode, test.Expect.Output.File); err != nil {
+			return false, err
+		}
+	}
+	test.Expect.Output.ResolvedFilePath = path
+
+	if showProgress() {
+		changes := validator.CompareRuns(previous, normalized)
+		color.Yellow("  %s UPDATED %s (%d mismatch(es) accepted, %d violation(s) added or removed)", symbolWarn, path, mismatches, len(changes.Errors))
+		for _, change := range changes.Errors {
+			fmt.Printf("    %s\n", change.Message)
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,751 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/parser"
+	"github.com/konveyor/test-harness/pkg/targets"
+	"github.com/konveyor/test-harness/pkg/validator"
+)
+
+// scriptedTarget returns the next exit code from a script on every execution
+type scriptedTarget struct {
+	exitCodes    []int
+	output       string
+	applications []targets.ApplicationOutput
+	workDir      string
+	duration     time.Duration
+	providers    []string
+	calls        int
+}
+
+func (s *scriptedTarget) Name() string { return "scripted" }
+
+func (s *scriptedTarget) Execute(ctx context.Context, test *config.TestDefinition) (*targets.ExecutionResult, error) {
+	code := s.exitCodes[s.calls%len(s.exitCodes)]
+	s.calls++
+	return &targets.ExecutionResult{ExitCode: code, OutputFile: s.output, Applications: s.applications, WorkDir: s.workDir, Duration: s.duration, Providers: s.providers}, nil
+}
+
+func TestRunRepeatedTest(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "sample", "test.yaml")
+	writeFile(t, testFile, `name: sample
+analysis:
+  application: app
+  analysisMode: source-only
+expect:
+  exitCode: 0
+  output:
+    result:
+    - name: rs
+      tags:
+      - Java
+`)
+	output := filepath.Join(dir, "output.yaml")
+	writeFile(t, output, "- name: rs\n  tags:\n  - Java\n")
+
+	tests := []struct {
+		name         string
+		exitCodes    []int
+		repeat       int
+		untilFailure bool
+		wantStatus   string
+		wantAttempts int
+		wantPassed   int
+	}{
+		{name: "single run", exitCodes: []int{0}, repeat: 1, wantStatus: "passed"},
+		{name: "stable pass", exitCodes: []int{0}, repeat: 3, wantStatus: "passed", wantAttempts: 3, wantPassed: 3},
+		{name: "stable failure", exitCodes: []int{1}, repeat: 3, wantStatus: "failed", wantAttempts: 3},
+		{name: "mixed outcomes are flaky", exitCodes: []int{0, 1}, repeat: 4, wantStatus: "flaky", wantAttempts: 4, wantPassed: 2},
+		{name: "until failure stops at first failure", exitCodes: []int{0, 0, 1}, repeat: 10, untilFailure: true, wantStatus: "flaky", wantAttempts: 3, wantPassed: 2},
+		{name: "until failure without repeat", exitCodes: []int{0}, repeat: 1, untilFailure: true, wantStatus: "passed", wantAttempts: untilFailureLimit, wantPassed: untilFailureLimit},
+	}
+
+	oldRepeat, oldUntil, oldFormat := repeatCount, untilFailure, outputFormat
+	defer func() { repeatCount, untilFailure, outputFormat = oldRepeat, oldUntil, oldFormat }()
+	outputFormat = "json"
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repeatCount, untilFailure = tt.repeat, tt.untilFailure
+			target := &scriptedTarget{exitCodes: tt.exitCodes, output: output}
+
+			result, _ := runRepeatedTest(testFile, target, &config.TargetConfig{Type: "kantra"})
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.wantStatus, result.ErrorMessage)
+			}
+			if result.Attempts != tt.wantAttempts || result.PassedAttempts != tt.wantPassed {
+				t.Errorf("attempts = %d/%d, want %d/%d", result.PassedAttempts, result.Attempts, tt.wantPassed, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRunSingleTest_UpdateExpected(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "sample", "test.yaml")
+	writeFile(t, testFile, `name: sample
+analysis:
+  application: app
+  analysisMode: source-only
+expect:
+  exitCode: 0
+  output:
+    result:
+    - name: rs
+      tags:
+      - Java
+`)
+	output := filepath.Join(dir, "output.yaml")
+	writeFile(t, output, "- name: rs\n  tags:\n  - Java\n  - Spring\n- name: other\n  tags:\n  - EJB\n")
+	target := &scriptedTarget{exitCodes: []int{0}, output: output}
+
+	oldUpdate, oldFormat := updateExpected, outputFormat
+	defer func() { updateExpected, outputFormat = oldUpdate, oldFormat }()
+	outputFormat = "json"
+
+	result, _ := runSingleTest(testFile, target, &config.TargetConfig{Type: "kantra"})
+	if result.Status != "failed" {
+		t.Fatalf("Expected failure without --update-expected, got %s", result.Status)
+	}
+
+	updateExpected = true
+	result, _ = runSingleTest(testFile, target, &config.TargetConfig{Type: "kantra"})
+	if result.Status != "passed" || !result.UpdatedExpected {
+		t.Fatalf("Expected the test to be updated, got %s (%s)", result.Status, result.ErrorMessage)
+	}
+	if want := filepath.Join(dir, "sample", "expected-output.yaml"); result.ExpectedFile != want {
+		t.Errorf("Expected output written to %s, got %s", want, result.ExpectedFile)
+	}
+
+	// The rewritten test now references the file and passes as is
+	updateExpected = false
+	result, _ = runSingleTest(testFile, target, &config.TargetConfig{Type: "kantra"})
+	if result.Status != "passed" || result.UpdatedExpected {
+		t.Errorf("Expected the updated test to pass, got %s: %+v", result.Status, result.ValidationErrors)
+	}
+}
+
+func TestRunSingleTest_ProviderErrors(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "sample", "test.yaml")
+	writeFile(t, testFile, `name: sample
+analysis:
+  application: app
+  analysisMode: source-only
+expect:
+  exitCode: 0
+  output:
+    result:
+    - name: rs
+      tags:
+      - Java
+`)
+	workDir := filepath.Join(dir, "work")
+	output := filepath.Join(workDir, "output", "output.yaml")
+	writeFile(t, output, "- name: rs\n  tags:\n  - Java\n")
+	writeFile(t, filepath.Join(workDir, "output", "analysis.log"),
+		`time="2024-01-01T00:00:00Z" level=info msg="starting provider" provider=java
+time="2024-01-01T00:00:01Z" level=error msg="unable to init the providers" provider=java error="java provider not ready"
+`)
+	target := &scriptedTarget{exitCodes: []int{0}, output: output, workDir: workDir}
+
+	oldFail, oldFormat := failOnProviderErrors, outputFormat
+	defer func() { failOnProviderErrors, outputFormat = oldFail, oldFormat }()
+	outputFormat = "json"
+
+	result, _ := runSingleTest(testFile, target, &config.TargetConfig{Type: "kantra"})
+	if result.Status != "passed" || len(result.ProviderErrors) != 1 {
+		t.Fatalf("Expected a passing test with one recorded provider error, got %s: %+v", result.Status, result.ProviderErrors)
+	}
+
+	failOnProviderErrors = true
+	result, _ = runSingleTest(testFile, target, &config.TargetConfig{Type: "kantra"})
+	if result.Status != "failed" || result.FailureKind != FailureExecution {
+		t.Errorf("Expected provider errors to fail the test, got %s (%s)", result.Status, result.FailureKind)
+	}
+}
+
+func TestRunSingleTest_MaxDuration(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "sample", "test.yaml")
+	writeFile(t, testFile, `name: sample
+analysis:
+  application: app
+  analysisMode: source-only
+expect:
+  maxDuration: 15m
+  output:
+    result:
+    - name: rs
+      tags:
+      - Java
+`)
+	output := filepath.Join(dir, "output.yaml")
+	writeFile(t, output, "- name: rs\n  tags:\n  - Java\n")
+
+	oldFormat := outputFormat
+	defer func() { outputFormat = oldFormat }()
+	outputFormat = "json"
+
+	tests := []struct {
+		name         string
+		duration     time.Duration
+		budget       string
+		wantStatus   string
+		wantErrors   int
+		wantWarnings int
+	}{
+		{name: "within budget", duration: 10 * time.Minute, wantStatus: "passed"},
+		{name: "over budget", duration: 20 * time.Minute, wantStatus: "failed", wantErrors: 1},
+		{name: "over budget, warn", duration: 20 * time.Minute, budget: config.DurationBudgetWarn, wantStatus: "passed", wantWarnings: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &scriptedTarget{exitCodes: []int{0}, output: output, duration: tt.duration}
+			targetConfig := &config.TargetConfig{Type: "kantra", Validation: config.ValidationConfig{DurationBudget: tt.budget}}
+			result, _ := runSingleTest(testFile, target, targetConfig)
+			if result.Status != tt.wantStatus || len(result.ValidationErrors) != tt.wantErrors || len(result.ValidationWarnings) != tt.wantWarnings {
+				t.Fatalf("got %s with %d error(s) and %d warning(s), want %s with %d and %d",
+					result.Status, len(result.ValidationErrors), len(result.ValidationWarnings), tt.wantStatus, tt.wantErrors, tt.wantWarnings)
+			}
+			if tt.wantErrors > 0 && (result.FailureKind != FailureValidation || result.ValidationErrors[0].Code != validator.CodeDurationExceeded) {
+				t.Errorf("expected a %s validation failure, got %s: %+v", validator.CodeDurationExceeded, result.FailureKind, result.ValidationErrors)
+			}
+		})
+	}
+}
+
+func TestRunSingleTest_ExitStatus(t *testing.T) {
+	dir := t.TempDir()
+	clean := filepath.Join(dir, "clean.yaml")
+	writeFile(t, clean, "- name: rs\n  tags:\n  - Java\n")
+	findings := filepath.Join(dir, "findings.yaml")
+	writeFile(t, findings, "- name: rs\n  violations:\n    rule-001:\n      category: mandatory\n      effort: 1\n")
+
+	tests := []struct {
+		name         string
+		expect       string
+		exitCode     int
+		output       string
+		mapping      *config.ExitCodeMapping
+		wantMismatch bool
+	}{
+		{name: "clean", expect: config.StatusSuccessClean, output: clean},
+		{name: "findings read from output", expect: config.StatusSuccessWithFindings, output: findings},
+		{name: "findings exit code", expect: config.StatusSuccessWithFindings, exitCode: 3, output: clean,
+			mapping: &config.ExitCodeMapping{Findings: config.ExitCode(3)}},
+		{name: "unmapped exit code", expect: config.StatusSuccessWithFindings, exitCode: 3, output: findings, wantMismatch: true},
+		{name: "execution error", expect: config.StatusExecutionError, exitCode: 1, output: clean},
+		{name: "clean expected", expect: config.StatusSuccessClean, output: findings, wantMismatch: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "-"), "test.yaml")
+			writeFile(t, testFile, fmt.Sprintf(`name: sample
+analysis:
+  application: app
+  analysisMode: source-only
+expect:
+  exitCode: %s
+  output:
+    result:
+    - name: rs
+      tags:
+      - Java
+`, tt.expect))
+			target := &scriptedTarget{exitCodes: []int{tt.exitCode}, output: tt.output}
+			result, _ := runSingleTest(testFile, target, &config.TargetConfig{Type: "kantra", ExitCodes: tt.mapping})
+			if mismatch := strings.HasPrefix(result.ErrorMessage, "Exit code mismatch"); mismatch != tt.wantMismatch {
+				t.Errorf("exit code mismatch = %v, want %v: %s", mismatch, tt.wantMismatch, result.ErrorMessage)
+			}
+		})
+	}
+}
+
+func TestRunSingleTest_Providers(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "multi-language", "test.yaml")
+	writeFile(t, testFile, `name: multi-language
+analysis:
+  application: app
+  analysisMode: source-only
+expect:
+  output:
+    result:
+    - name: rs
+      tags:
+      - Java
+  providers:
+    ran: [java, nodejs]
+  allowedMismatches: 5
+`)
+	output := filepath.Join(dir, "output.yaml")
+	writeFile(t, output, "- name: rs\n  tags:\n  - Java\n")
+
+	tests := []struct {
+		name       string
+		providers  []string
+		wantStatus string
+	}{
+		{"all ran", []string{"builtin", "java", "nodejs"}, "passed"},
+		{"skipped despite allowed mismatches", []string{"builtin", "java"}, "failed"},
+		{"unknown", nil, "passed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &scriptedTarget{exitCodes: []int{0}, output: output, providers: tt.providers}
+			result, err := runSingleTest(testFile, target, &config.TargetConfig{Type: "kantra"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s: %+v", result.Status, tt.wantStatus, result.ValidationErrors)
+			}
+			if tt.providers == nil && (len(result.ValidationWarnings) == 0 || result.ValidationWarnings[0].Code != validator.CodeProvidersUnknown) {
+				t.Errorf("expected a warning that providers were not checked, got %+v", result.ValidationWarnings)
+			}
+		})
+	}
+}
+
+// issueTarget shows the issues of its analyses in an issues table
+type issueTarget struct {
+	scriptedTarget
+	issues []parser.Issue
+}
+
+func (i *issueTarget) Issues(ctx context.Context, test *config.TestDefinition, result *targets.ExecutionResult) ([]parser.Issue, error) {
+	return i.issues, nil
+}
+
+func TestRunSingleTest_Issues(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "issues", "test.yaml")
+	writeFile(t, testFile, `name: issues
+analysis:
+  application: app
+  analysisMode: source-only
+expect:
+  output:
+    result:
+    - name: rs
+      violations:
+        rule-001:
+          category: mandatory
+          effort: 1
+          incidents:
+          - uri: file:///app/A.java
+`)
+	output := filepath.Join(dir, "output.yaml")
+	writeFile(t, output, "- name: rs\n  violations:\n    rule-001:\n      category: mandatory\n      effort: 1\n      incidents:\n      - uri: file:///app/A.java\n")
+
+	tests := []struct {
+		name       string
+		incidents  int
+		wantStatus string
+	}{
+		{"shown as analyzed", 1, "passed"},
+		{"aggregated wrong", 2, "failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &issueTarget{
+				scriptedTarget: scriptedTarget{exitCodes: []int{0}, output: output},
+				issues:         []parser.Issue{{RuleSet: "rs", Rule: "rule-001", Category: "mandatory", Effort: 1, Incidents: tt.incidents}},
+			}
+			result, err := runSingleTest(testFile, target, &config.TargetConfig{Type: "tackle-ui"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s: %+v", result.Status, tt.wantStatus, result.ValidationErrors)
+			}
+			if tt.wantStatus == "failed" && (len(result.ValidationErrors) != 1 || result.ValidationErrors[0].Code != validator.CodeIssueMismatch) {
+				t.Errorf("expected an ISSUE_MISMATCH error, got %+v", result.ValidationErrors)
+			}
+		})
+	}
+}
+
+// diagnosticTarget shows the incidents of its analyses as editor diagnostics
+type diagnosticTarget struct {
+	scriptedTarget
+	diagnostics []parser.Diagnostic
+}
+
+func (d *diagnosticTarget) Diagnostics(ctx context.Context, test *config.TestDefinition, result *targets.ExecutionResult) ([]parser.Diagnostic, error) {
+	return d.diagnostics, nil
+}
+
+func TestRunSingleTest_Diagnostics(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "diagnostics", "test.yaml")
+	writeFile(t, testFile, `name: diagnostics
+analysis:
+  application: app
+  analysisMode: source-only
+expect:
+  output:
+    result:
+    - name: rs
+      violations:
+        rule-001:
+          effort: 1
+          incidents:
+          - uri: file://${TEST_DIR}/app/A.java
+            lineNumber: 3
+            message: Replace javax
+`)
+	output := filepath.Join(dir, "output.yaml")
+	writeFile(t, output, fmt.Sprintf("- name: rs\n  violations:\n    rule-001:\n      effort: 1\n      incidents:\n      - uri: file://%s/diagnostics/app/A.java\n        lineNumber: 3\n        message: Replace javax\n", dir))
+
+	tests := []struct {
+		name        string
+		diagnostics []parser.Diagnostic
+		wantStatus  string
+	}{
+		{"shown as analyzed", []parser.Diagnostic{{URI: "file://" + dir + "/diagnostics/app/A.java", Line: 3, Rule: "rule-001", Message: "Replace javax"}}, "passed"},
+		{"filtered by the extension", nil, "failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &diagnosticTarget{scriptedTarget: scriptedTarget{exitCodes: []int{0}, output: output}, diagnostics: tt.diagnostics}
+			result, err := runSingleTest(testFile, target, &config.TargetConfig{Type: "vscode"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s: %+v", result.Status, tt.wantStatus, result.ValidationErrors)
+			}
+			if tt.wantStatus == "failed" && (len(result.ValidationErrors) != 1 || result.ValidationErrors[0].Code != validator.CodeMissingDiagnostic) {
+				t.Errorf("expected a MISSING_DIAGNOSTIC error, got %+v", result.ValidationErrors)
+			}
+		})
+	}
+}
+
+// fixTarget suggests the same fix for every incident
+type fixTarget struct {
+	scriptedTarget
+	fix parser.FixSuggestion
+}
+
+func (f *fixTarget) SuggestFix(ctx context.Context, test *config.TestDefinition, ruleset, rule string, incident konveyor.Incident) (*parser.FixSuggestion, error) {
+	return &f.fix, nil
+}
+
+func TestRunSingleTest_Fixes(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "fixes", "test.yaml")
+	writeFile(t, testFile, `name: fixes
+analysis:
+  application: app
+  analysisMode: source-only
+fixes:
+  incidents:
+  - ruleset: rs
+    rule: rule-001
+expect:
+  output:
+    result:
+    - name: rs
+      violations:
+        rule-001:
+          effort: 1
+          incidents:
+          - uri: file:///app/A.java
+`)
+	output := filepath.Join(dir, "output.yaml")
+	writeFile(t, output, "- name: rs\n  violations:\n    rule-001:\n      effort: 1\n      incidents:\n      - uri: file:///app/A.java\n")
+
+	tests := []struct {
+		name       string
+		target     targets.Target
+		wantStatus string
+		wantCode   validator.ErrorCode
+	}{
+		{"valid fix", &fixTarget{scriptedTarget{exitCodes: []int{0}, output: output}, parser.FixSuggestion{File: "file:///app/A.java", Diff: "+fixed\n"}}, "passed", ""},
+		{"empty fix", &fixTarget{scriptedTarget{exitCodes: []int{0}, output: output}, parser.FixSuggestion{File: "file:///app/A.java"}}, "failed", validator.CodeEmptyFix},
+		{"target without fixes", &scriptedTarget{exitCodes: []int{0}, output: output}, "failed", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _ := runSingleTest(testFile, tt.target, &config.TargetConfig{Type: "kai-rpc"})
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s: %s %+v", result.Status, tt.wantStatus, result.ErrorMessage, result.ValidationErrors)
+			}
+			if tt.wantCode != "" && (len(result.ValidationErrors) != 1 || result.ValidationErrors[0].Code != tt.wantCode) {
+				t.Errorf("expected a %s error, got %+v", tt.wantCode, result.ValidationErrors)
+			}
+		})
+	}
+}
+
+func TestRunSingleTest_Applications(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "bulk", "test.yaml")
+	writeFile(t, testFile, `name: bulk
+analysis:
+  applications:
+  - name: coolstore
+    application: ./coolstore
+  - name: daytrader
+    application: ./daytrader
+  analysisMode: source-only
+expect:
+  applications:
+    coolstore:
+      result:
+      - name: rs
+        tags:
+        - Java
+    daytrader:
+      file: expected-output-daytrader.yaml
+`)
+	writeFile(t, filepath.Join(dir, "bulk", "expected-output-daytrader.yaml"), "- name: rs\n  tags:\n  - EJB\n")
+	coolstore := filepath.Join(dir, "coolstore.yaml")
+	writeFile(t, coolstore, "- name: rs\n  tags:\n  - Java\n")
+	daytrader := filepath.Join(dir, "daytrader.yaml")
+	merged := filepath.Join(dir, "output.yaml")
+	writeFile(t, merged, "- name: rs\n  tags:\n  - EJB\n  - Java\n")
+
+	oldFormat := outputFormat
+	defer func() { outputFormat = oldFormat }()
+	outputFormat = "json"
+
+	tests := []struct {
+		name       string
+		daytrader  string
+		wantStatus string
+	}{
+		{name: "every application matches", daytrader: "- name: rs\n  tags:\n  - EJB\n", wantStatus: "passed"},
+		{name: "one application differs", daytrader: "- name: rs\n  tags:\n  - EJB\n  - Servlet\n", wantStatus: "failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeFile(t, daytrader, tt.daytrader)
+			target := &scriptedTarget{exitCodes: []int{0}, output: merged, applications: []targets.ApplicationOutput{
+				{Name: "coolstore", OutputFile: coolstore},
+				{Name: "daytrader", OutputFile: daytrader},
+			}}
+			result, _ := runSingleTest(testFile, target, &config.TargetConfig{Type: "kantra", Validation: config.ValidationConfig{Strict: true}})
+			if result.Status != tt.wantStatus {
+				t.Fatalf("status = %s, want %s (%s %+v)", result.Status, tt.wantStatus, result.ErrorMessage, result.ValidationErrors)
+			}
+			for _, e := range result.ValidationErrors {
+				if e.Location == nil || e.Location.Application != "daytrader" || !strings.HasPrefix(e.Message, "[daytrader] ") {
+					t.Errorf("expected an error of the daytrader application, got %+v", e)
+				}
+			}
+		})
+	}
+}
+
+func TestPreviousFailedTests(t *testing.T) {
+	dir := t.TempDir()
+	testFile := func(name string) string {
+		path := filepath.Join(dir, "tests", name, "test.yaml")
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("name: "+name+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	a, b, c := testFile("a"), testFile("b"), testFile("c")
+	storeDir := filepath.Join(dir, "results")
+
+	if _, _, err := previousFailedTests(storeDir, "kantra", nil); err == nil {
+		t.Fatal("expected an error without a previous run")
+	}
+
+	store, err := OpenResultsStore(storeDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	runs := []*RunRecord{
+		newRun("v1", base, TestResult{Name: "a", TestFile: a, Status: "failed"}),
+		newRun("v2", base.Add(time.Hour),
+			TestResult{Name: "a", TestFile: a, Status: "passed"},
+			TestResult{Name: "b", TestFile: b, Status: "failed"},
+			TestResult{Name: "c", TestFile: c, Status: "failed"},
+			TestResult{Name: "gone", TestFile: filepath.Join(dir, "tests", "gone", "test.yaml"), Status: "failed"},
+		),
+		NewRunRecord(base.Add(2*time.Hour), "tackle-hub", "v3", &TestSummary{Tests: []TestResult{{Name: "a", TestFile: a, Status: "failed"}}}),
+	}
+	for _, run := range runs {
+		if err := store.SaveRun(run); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runID, failed, err := previousFailedTests(storeDir, "kantra", nil)
+	if err != nil {
+		t.Fatalf("previousFailedTests() error = %v", err)
+	}
+	if runID != runs[1].ID || !reflect.DeepEqual(failed, []string{b, c}) {
+		t.Errorf("previousFailedTests() = %s, %v, want %s, [%s %s]", runID, failed, runs[1].ID, b, c)
+	}
+
+	_, failed, err = previousFailedTests(storeDir, "kantra", []string{a, c})
+	if err != nil {
+		t.Fatalf("previousFailedTests() error = %v", err)
+	}
+	if !reflect.DeepEqual(failed, []string{c}) {
+		t.Errorf("expected only the failed candidate %s, got %v", c, failed)
+	}
+}
+
+func TestLoadTargetConfig(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if err := os.MkdirAll(filepath.Join(".koncur", "config"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		".koncur/config/target-tackle-hub.yaml": "type: tackle-hub\ntackleHub:\n  url: http://discovered\n",
+		"hub.yaml":                              "type: tackle-hub\ntackleHub:\n  url: http://explicit\n",
+		"invalid.yaml":                          "type: [\n",
+	}
+	for file, content := range files {
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		file       string
+		targetType string
+		wantType   string
+		wantURL    string
+	}{
+		{name: "explicit file wins over type", file: "hub.yaml", targetType: "kantra", wantType: "tackle-hub", wantURL: "http://explicit"},
+		{name: "auto-discovered", targetType: "tackle-hub", wantType: "tackle-hub", wantURL: "http://discovered"},
+		{name: "default for type", targetType: "kai-rpc", wantType: "kai-rpc"},
+		{name: "default kantra", wantType: "kantra"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := loadTargetConfig(tt.file, tt.targetType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Type != tt.wantType {
+				t.Errorf("type = %q, want %q", cfg.Type, tt.wantType)
+			}
+			if tt.wantURL != "" && (cfg.TackleHub == nil || cfg.TackleHub.URL != tt.wantURL) {
+				t.Errorf("hub config = %+v, want URL %q", cfg.TackleHub, tt.wantURL)
+			}
+		})
+	}
+
+	if _, err := loadTargetConfig("invalid.yaml", ""); err == nil || exitCodeFor(err) != ExitCodeConfigError {
+		t.Errorf("expected a config error for an invalid file, got %v", err)
+	}
+}
+
+// labelTarget lists fixed labels
+type labelTarget struct {
+	scriptedTarget
+	sources, targets []string
+}
+
+func (l *labelTarget) ListLabels(ctx context.Context, test *config.TestDefinition) (*targets.RuleSetLabels, error) {
+	return &targets.RuleSetLabels{Sources: l.sources, Targets: l.targets}, nil
+}
+
+func TestRunSingleTest_ListLabels(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "labels", "test.yaml")
+	writeFile(t, testFile, `name: labels
+kind: list-labels
+expect:
+  labels:
+    sources: [springboot]
+    targets: [quarkus, cloud-readiness]
+`)
+
+	tests := []struct {
+		name       string
+		target     targets.Target
+		wantStatus string
+		wantKind   string
+	}{
+		{"listed", &labelTarget{sources: []string{"springboot"}, targets: []string{"cloud-readiness", "quarkus"}}, "passed", ""},
+		{"missing target", &labelTarget{sources: []string{"springboot"}, targets: []string{"quarkus"}}, "failed", FailureValidation},
+		{"target cannot list", &scriptedTarget{}, "failed", FailureConfig},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _ := runSingleTest(testFile, tt.target, &config.TargetConfig{Type: "kantra"})
+			if result.Status != tt.wantStatus || result.FailureKind != tt.wantKind {
+				t.Errorf("got %s (%s), want %s (%s): %s", result.Status, result.FailureKind, tt.wantStatus, tt.wantKind, result.ErrorMessage)
+			}
+		})
+	}
+}
+
+// transformTarget writes a fixed file as its transform's output
+type transformTarget struct {
+	scriptedTarget
+	content string
+}
+
+func (tt *transformTarget) Transform(ctx context.Context, test *config.TestDefinition) (*targets.ExecutionResult, error) {
+	dir := filepath.Join(test.GetTestDir(), "produced")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "rules.yaml"), []byte(tt.content), 0644); err != nil {
+		return nil, err
+	}
+	return &targets.ExecutionResult{OutputDir: dir}, nil
+}
+
+func TestRunSingleTest_Transform(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "transform", "test.yaml")
+	writeFile(t, testFile, `name: transform
+kind: transform
+transform:
+  command: rules
+  input: windup
+expect:
+  files:
+    dir: expected
+`)
+	writeFile(t, filepath.Join(dir, "transform", "expected", "rules.yaml"), "- ruleID: converted\n")
+
+	tests := []struct {
+		name       string
+		target     targets.Target
+		wantStatus string
+		wantKind   string
+	}{
+		{"same files", &transformTarget{content: "- ruleID: converted\n"}, "passed", ""},
+		{"different file", &transformTarget{content: "- ruleID: other\n"}, "failed", FailureValidation},
+		{"target cannot transform", &scriptedTarget{}, "failed", FailureConfig},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _ := runSingleTest(testFile, tt.target, &config.TargetConfig{Type: "kantra"})
+			if result.Status != tt.wantStatus || result.FailureKind != tt.wantKind {
+				t.Errorf("got %s (%s), want %s (%s): %s", result.Status, result.FailureKind, tt.wantStatus, tt.wantKind, result.ErrorMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var schemaOutput string
+
+// Kinds of configuration 'koncur schema' describes
+const (
+	schemaKindTest   = "test"
+	schemaKindTarget = "target"
+)
+
+// NewSchemaCmd creates the schema command
+func NewSchemaCmd() *cobra.Command {
+	schemaCmd := &cobra.Command{
+		Use:   "schema <test|target>",
+		Short: "Print the JSON Schema of test definitions or target configurations",
+		Long: `Print the JSON Schema of test definitions (test) or target configurations
+(target), for editor autocomplete and validation. The schemas of this version are
+also published in the schemas directory of the repository.`,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{schemaKindTest, schemaKindTarget},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := schemaJSON(args[0])
+			if err != nil {
+				return err
+			}
+			if schemaOutput == "" {
+				fmt.Print(string(data))
+				return nil
+			}
+			if err := os.WriteFile(schemaOutput, data, 0644); err != nil {
+				return fmt.Errorf("failed to write schema: %w", err)
+			}
+			return nil
+		},
+	}
+
+	schemaCmd.Flags().StringVarP(&schemaOutput, "output", "o", "", "Write the schema to this file instead of stdout")
+
+	return schemaCmd
+}
+
+// schemaJSON returns the indented JSON Schema of a kind of configuration
+func schemaJSON(kind string) ([]byte, error) {
+	var schema *config.Schema
+	switch kind {
+	case schemaKindTest:
+		schema = config.TestDefinitionSchema()
+	case schemaKindTarget:
+		schema = config.TargetConfigSchema()
+	default:
+		return nil, configError("unknown schema %q (must be %s or %s)", kind, schemaKindTest, schemaKindTarget)
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPublishedSchemas(t *testing.T) {
+	for _, kind := range []string{schemaKindTest, schemaKindTarget} {
+		t.Run(kind, func(t *testing.T) {
+			want, err := schemaJSON(kind)
+			if err != nil {
+				t.Fatal(err)
+			}
+			published, err := os.ReadFile(filepath.Join("..", "..", "schemas", kind+".schema.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(published) != string(want) {
+				t.Errorf("schemas/%s.schema.json is out of date, run 'make schemas'", kind)
+			}
+		})
+	}
+
+	if _, err := schemaJSON("suite"); err == nil {
+		t.Error("expected an unknown schema to be rejected")
+	}
+}
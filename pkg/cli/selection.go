@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/konveyor/test-harness/pkg/config"
+)
+
+// testSelector selects tests by name pattern, exclusion pattern and tag
+type testSelector struct {
+	filters     []*regexp.Regexp
+	excludes    []*regexp.Regexp
+	tags        []string
+	excludeTags []string
+}
+
+// newTestSelector compiles the --filter and --exclude regular expressions
+func newTestSelector(filters, excludes, tags, excludeTags []string) (*testSelector, error) {
+	selector := &testSelector{tags: tags, excludeTags: excludeTags}
+	for _, pattern := range filters {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, configError("invalid --filter pattern %q: %w", pattern, err)
+		}
+		selector.filters = append(selector.filters, re)
+	}
+	for _, pattern := range excludes {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, configError("invalid --exclude pattern %q: %w", pattern, err)
+		}
+		selector.excludes = append(selector.excludes, re)
+	}
+	return selector, nil
+}
+
+// empty reports whether the selector selects every test
+func (s *testSelector) empty() bool {
+	return len(s.filters) == 0 && len(s.excludes) == 0 && len(s.tags) == 0 && len(s.excludeTags) == 0
+}
+
+// String describes the selection for log and error messages
+func (s *testSelector) String() string {
+	desc := ""
+	for _, re := range s.filters {
+		desc += fmt.Sprintf(" --filter %s", re)
+	}
+	for _, re := range s.excludes {
+		desc += fmt.Sprintf(" --exclude %s", re)
+	}
+	for _, tag := range s.tags {
+		desc += fmt.Sprintf(" --tags %s", tag)
+	}
+	for _, tag := range s.excludeTags {
+		desc += fmt.Sprintf(" --exclude-tags %s", tag)
+	}
+	if desc == "" {
+		return "all tests"
+	}
+	return desc[1:]
+}
+
+// matches reports whether a test is selected. Patterns are matched against the
+// name of the test directory, with the variant of a matrix test: a test is selected
+// when it matches any filter (or there is none), matches no exclude, has any of the
+// tags (or none are given) and none of the excluded tags.
+func (s *testSelector) matches(testFile string) (bool, error) {
+	name := resultName(testFile)
+	if len(s.filters) > 0 && !slices.ContainsFunc(s.filters, func(re *regexp.Regexp) bool { return re.MatchString(name) }) {
+		return false, nil
+	}
+	if slices.ContainsFunc(s.excludes, func(re *regexp.Regexp) bool { return re.MatchString(name) }) {
+		return false, nil
+	}
+	if len(s.tags) == 0 && len(s.excludeTags) == 0 {
+		return true, nil
+	}
+	tags, err := config.LoadTags(testFile)
+	if err != nil {
+		return false, configError("%w", err)
+	}
+	if slices.ContainsFunc(s.excludeTags, func(tag string) bool { return slices.Contains(tags, tag) }) {
+		return false, nil
+	}
+	return len(s.tags) == 0 || slices.ContainsFunc(s.tags, func(tag string) bool { return slices.Contains(tags, tag) }), nil
+}
+
+// selected returns the selected tests, in order
+func (s *testSelector) selected(testFiles []string) ([]string, error) {
+	if s.empty() {
+		return testFiles, nil
+	}
+	selected := []string{}
+	for _, testFile := range testFiles {
+		ok, err := s.matches(testFile)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			selected = append(selected, testFile)
+		}
+	}
+	return selected, nil
+}
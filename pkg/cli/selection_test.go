@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDiscoverTestFiles(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		"hub-basic":    "tags: [tier0]\n",
+		"java-slow":    "tags: [java, nightly]\n",
+		"java-tier0":   "tags: [java, tier0]\n",
+		"dotnet-tier0": "tags: [dotnet]\n",
+	}
+	for name, content := range tests {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name, "test.yaml"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	testFile := func(name string) string { return filepath.Join(dir, name, "test.yaml") }
+
+	cases := []struct {
+		name        string
+		paths       []string
+		filters     []string
+		excludes    []string
+		tags        []string
+		excludeTags []string
+		want        []string
+		wantErr     bool
+	}{
+		{
+			name:  "directory",
+			paths: []string{dir},
+			want:  []string{testFile("dotnet-tier0"), testFile("hub-basic"), testFile("java-slow"), testFile("java-tier0")},
+		},
+		{
+			name:    "substring filter",
+			paths:   []string{dir},
+			filters: []string{"java"},
+			want:    []string{testFile("java-slow"), testFile("java-tier0")},
+		},
+		{
+			name:    "regex filters",
+			paths:   []string{dir},
+			filters: []string{"^hub-", "tier0$"},
+			want:    []string{testFile("dotnet-tier0"), testFile("hub-basic"), testFile("java-tier0")},
+		},
+		{
+			name:     "exclude",
+			paths:    []string{dir},
+			excludes: []string{"slow", "^dotnet"},
+			want:     []string{testFile("hub-basic"), testFile("java-tier0")},
+		},
+		{
+			name:  "tags",
+			paths: []string{dir},
+			tags:  []string{"tier0", "nightly"},
+			want:  []string{testFile("hub-basic"), testFile("java-slow"), testFile("java-tier0")},
+		},
+		{
+			name:        "exclude tags",
+			paths:       []string{dir},
+			excludeTags: []string{"nightly", "dotnet"},
+			want:        []string{testFile("hub-basic"), testFile("java-tier0")},
+		},
+		{
+			name:        "tags and exclude tags",
+			paths:       []string{dir},
+			tags:        []string{"java"},
+			excludeTags: []string{"nightly"},
+			want:        []string{testFile("java-tier0")},
+		},
+		{
+			name:  "multiple paths without duplicates",
+			paths: []string{testFile("java-slow"), filepath.Join(dir, "java-slow"), testFile("hub-basic")},
+			want:  []string{testFile("java-slow"), testFile("hub-basic")},
+		},
+		{
+			name:    "nothing selected",
+			paths:   []string{dir},
+			filters: []string{"python"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid pattern",
+			paths:   []string{dir},
+			filters: []string{"java("},
+			wantErr: true,
+		},
+		{
+			name:    "missing path",
+			paths:   []string{filepath.Join(dir, "missing")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			selector, err := newTestSelector(tt.filters, tt.excludes, tt.tags, tt.excludeTags)
+			var got []string
+			if err == nil {
+				got, err = discoverTestFiles(tt.paths, selector)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("discoverTestFiles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("discoverTestFiles() = %v, want %v", got, tt.want)
+			}
+			if err != nil && exitCodeFor(err) != ExitCodeConfigError {
+				t.Errorf("expected a config error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDiscoverTestFiles_Suite(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"suite.yaml":         "name: java\ntests: [tier0, slow/test.yaml]\ndefaults:\n  tags: [java]\n",
+		"tier0/a/test.yaml":  "tags: [tier0]\n",
+		"tier0/b/test.yaml":  "tags: [tier0]\n",
+		"slow/test.yaml":     "tags: [nightly]\n",
+		"ignored/test.yaml":  "tags: [tier0]\n",
+		"nested/suite.yaml":  "defaults:\n  tags: [dotnet]\n",
+		"nested/d/test.yaml": "{}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	got, err := discoverTestFiles([]string{path("suite.yaml")}, &testSelector{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{path("tier0/a/test.yaml"), path("tier0/b/test.yaml"), path("slow/test.yaml")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("discoverTestFiles() = %v, want %v", got, want)
+	}
+
+	// Tags of the nearest suite select tests
+	selector, err := newTestSelector(nil, nil, []string{"java"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = discoverTestFiles([]string{dir}, selector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{path("ignored/test.yaml"), path("slow/test.yaml"), path("tier0/a/test.yaml"), path("tier0/b/test.yaml")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("discoverTestFiles() = %v, want %v", got, want)
+	}
+
+	invalid := filepath.Join(t.TempDir(), "suite.yaml")
+	if err := os.WriteFile(invalid, []byte("tests: [../slow]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := discoverTestFiles([]string{invalid}, &testSelector{}); exitCodeFor(err) != ExitCodeConfigError {
+		t.Errorf("expected a config error for a suite listing tests outside its directory, got %v", err)
+	}
+}
+
+func TestDiscoverTestFiles_Matrix(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "daytrader", "test.yaml")
+	if err := os.MkdirAll(filepath.Dir(testFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(testFile, []byte("name: daytrader\nmatrix:\n  analysisMode: [source-only, full]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := discoverTestFiles([]string{dir}, &testSelector{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{testFile + "#source-only", testFile + "#full"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("discoverTestFiles() = %v, want %v", got, want)
+	}
+	if name := resultName(got[0]); name != "daytrader[source-only]" {
+		t.Errorf("resultName() = %q", name)
+	}
+
+	// Variants are selected by name, or given directly
+	selector, err := newTestSelector([]string{`\[full\]`}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, paths := range [][]string{{dir}, {testFile + "#full"}} {
+		got, err = discoverTestFiles(paths, selector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{testFile + "#full"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("discoverTestFiles(%v) = %v, want %v", paths, got, want)
+		}
+	}
+}
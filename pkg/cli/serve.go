@@ -0,0 +1,398 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/konveyor/test-harness/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveAddr         string
+	serveStore        string
+	serveTargetConfig string
+)
+
+// NewServeCmd creates the serve command
+func NewServeCmd() *cobra.Command {
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve a local web dashboard over the results store",
+		Long: `Serve an HTML dashboard over the runs recorded in the results store:
+
+  /                 every run, newest first, with its pass/fail counts
+  /runs/<id>        the report of a run, with per-test diffs and links to logs
+  /jobs             reruns triggered from the dashboard and their output
+
+Failed tests can be rerun from a run's page, one at a time or all at once; reruns
+execute 'koncur run' on the run's target and are recorded in the same results store.
+Only one rerun executes at a time. The dashboard is meant for local use and listens
+on localhost by default.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log := util.GetLogger()
+
+			if _, err := OpenResultsStore(serveStore); err != nil {
+				return configError("%w", err)
+			}
+			executable, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("failed to find the koncur executable for reruns: %w", err)
+			}
+			dashboard := &dashboard{store: serveStore, executable: executable, targetConfig: serveTargetConfig}
+
+			fmt.Printf("%s Serving dashboard on http://%s (Ctrl+C to stop)\n", symbolRun, serveAddr)
+			log.Info("Serving dashboard", "addr", serveAddr, "store", serveStore)
+			server := &http.Server{Addr: serveAddr, Handler: dashboard.handler(), ReadHeaderTimeout: 10 * time.Second}
+			if err := server.ListenAndServe(); err != nil {
+				return fmt.Errorf("failed to serve dashboard: %w", err)
+			}
+			return nil
+		},
+	}
+
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "localhost:8090", "Address to listen on")
+	serveCmd.Flags().StringVar(&serveStore, "results-store", defaultResultsStore, "Results store location to browse")
+	serveCmd.Flags().StringVarP(&serveTargetConfig, "target-config", "c", "", "Target configuration file used for reruns (default: auto-discovered per target)")
+
+	return serveCmd
+}
+
+// dashboard serves the runs of a results store and reruns their tests
+type dashboard struct {
+	store        string
+	executable   string
+	targetConfig string
+
+	mu   sync.Mutex
+	jobs []*rerunJob
+}
+
+// rerunJob is a 'koncur run' started from the dashboard; its fields are guarded by
+// the dashboard's mutex, its output by its own
+type rerunJob struct {
+	ID       int
+	RunID    string
+	Args     []string
+	Started  time.Time
+	Finished time.Time
+	Running  bool
+	Err      string
+	output   lockedBuffer
+}
+
+// jobView is a snapshot of a rerun job rendered by the jobs page
+type jobView struct {
+	ID       int
+	RunID    string
+	Args     []string
+	Started  time.Time
+	Finished time.Time
+	Running  bool
+	Err      string
+	// Output is the redacted output of the job so far
+	Output string
+}
+
+// view returns a snapshot of the job; d.mu must be held
+func (j *rerunJob) view() jobView {
+	return jobView{
+		ID:       j.ID,
+		RunID:    j.RunID,
+		Args:     j.Args,
+		Started:  j.Started,
+		Finished: j.Finished,
+		Running:  j.Running,
+		Err:      j.Err,
+		Output:   util.RedactText(j.output.String()),
+	}
+}
+
+func (d *dashboard) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", d.handleIndex)
+	mux.HandleFunc("GET /runs/{id}", d.handleRun)
+	mux.HandleFunc("GET /runs/{id}/file", d.handleFile)
+	mux.HandleFunc("POST /runs/{id}/rerun", d.handleRerun)
+	mux.HandleFunc("GET /jobs", d.handleJobs)
+	// Other sites open in the browser must not start reruns
+	return http.NewCrossOriginProtection().Handler(mux)
+}
+
+// run returns a recorded run, writing a 404 when it does not exist
+func (d *dashboard) run(w http.ResponseWriter, id string) *RunRecord {
+	store, err := OpenResultsStore(d.store)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil
+	}
+	runs, err := store.ListRuns()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil
+	}
+	run := findRun(runs, id, "")
+	if run == nil || run.Summary == nil {
+		http.NotFound(w, nil)
+		return nil
+	}
+	return run
+}
+
+func (d *dashboard) handleIndex(w http.ResponseWriter, r *http.Request) {
+	store, err := OpenResultsStore(d.store)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	runs, err := store.ListRuns()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	slices.Reverse(runs)
+
+	d.mu.Lock()
+	running := d.runningJob()
+	d.mu.Unlock()
+
+	data := struct {
+		Store   string
+		Runs    []*RunRecord
+		Running *rerunJob
+	}{Store: d.store, Runs: runs, Running: running}
+	if err := dashboardIndexTemplate.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (d *dashboard) handleRun(w http.ResponseWriter, r *http.Request) {
+	run := d.run(w, r.PathValue("id"))
+	if run == nil {
+		return
+	}
+	html, err := renderHTMLReport(run.Summary, reportOptions{
+		FileURL: func(path string) template.URL {
+			return template.URL(fmt.Sprintf("/runs/%s/file?path=%s", url.PathEscape(run.ID), url.QueryEscape(path)))
+		},
+		RerunURL: fmt.Sprintf("/runs/%s/rerun", url.PathEscape(run.ID)),
+		BackURL:  "/",
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, html)
+}
+
+// handleFile serves the outputs and logs of a run's tests as redacted text.
+// Only files the run references can be read.
+func (d *dashboard) handleFile(w http.ResponseWriter, r *http.Request) {
+	run := d.run(w, r.PathValue("id"))
+	if run == nil {
+		return
+	}
+	path := r.URL.Query().Get("path")
+	if !slices.Contains(runFiles(run), path) {
+		http.Error(w, "file is not part of this run", http.StatusForbidden)
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprint(w, util.RedactText(string(data)))
+}
+
+// runFiles lists the output, expected output and log files of a run's tests
+func runFiles(run *RunRecord) []string {
+	var files []string
+	for _, result := range run.Summary.Tests {
+		for _, file := range []string{result.OutputFile, result.ExpectedFile} {
+			if file != "" {
+				files = append(files, file)
+			}
+		}
+		files = append(files, findLogFiles(result.WorkDir)...)
+	}
+	return files
+}
+
+// handleRerun starts 'koncur run' for one test of a run ("test") or its failed tests ("failed")
+func (d *dashboard) handleRerun(w http.ResponseWriter, r *http.Request) {
+	run := d.run(w, r.PathValue("id"))
+	if run == nil {
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var testFiles []string
+	for _, result := range run.Summary.Tests {
+		switch {
+		case r.PostForm.Get("test") != "" && result.TestFile == r.PostForm.Get("test"):
+			testFiles = append(testFiles, result.TestFile)
+		case r.PostForm.Get("failed") == "true" && result.Status == "failed":
+			testFiles = append(testFiles, result.TestFile)
+		}
+	}
+	if len(testFiles) == 0 {
+		http.Error(w, "no tests of this run to rerun", http.StatusBadRequest)
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	// Tests share work directories, so reruns must not overlap
+	if d.runningJob() != nil {
+		http.Error(w, "a rerun is already in progress, see /jobs", http.StatusConflict)
+		return
+	}
+	args := append([]string{"run"}, testFiles...)
+	args = append(args, "--target", run.Target, "--results-store", d.store)
+	if d.targetConfig != "" {
+		args = append(args, "--target-config", d.targetConfig)
+	}
+	if run.ToolVersion != "" {
+		args = append(args, "--tool-version", run.ToolVersion)
+	}
+	job := &rerunJob{ID: len(d.jobs) + 1, RunID: run.ID, Args: args, Started: time.Now(), Running: true}
+	d.jobs = append(d.jobs, job)
+	go d.execute(job)
+
+	http.Redirect(w, r, "/jobs#job-"+strconv.Itoa(job.ID), http.StatusSeeOther)
+}
+
+// execute runs a rerun job to completion
+func (d *dashboard) execute(job *rerunJob) {
+	cmd := exec.Command(d.executable, job.Args...)
+	cmd.Stdout = &job.output
+	cmd.Stderr = &job.output
+	err := cmd.Run()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	job.Running = false
+	job.Finished = time.Now()
+	if err != nil {
+		job.Err = err.Error()
+	}
+}
+
+// runningJob returns the rerun in progress, if any; d.mu must be held
+func (d *dashboard) runningJob() *rerunJob {
+	for _, job := range d.jobs {
+		if job.Running {
+			return job
+		}
+	}
+	return nil
+}
+
+func (d *dashboard) handleJobs(w http.ResponseWriter, r *http.Request) {
+	// Render a snapshot, so slow clients do not hold up reruns
+	d.mu.Lock()
+	jobs := make([]jobView, 0, len(d.jobs))
+	for i := len(d.jobs) - 1; i >= 0; i-- {
+		jobs = append(jobs, d.jobs[i].view())
+	}
+	d.mu.Unlock()
+
+	if err := dashboardJobsTemplate.Execute(w, jobs); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// lockedBuffer is the output of a rerun, written by the job while the jobs page reads it
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+const dashboardStyle = `<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+.meta { color: #57606a; margin-bottom: 1.5em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #d0d7de; padding: 4px 8px; text-align: left; }
+.passed { color: #1a7f37; font-weight: bold; }
+.failed { color: #cf222e; font-weight: bold; }
+.skipped, .flaky { color: #9a6700; font-weight: bold; }
+pre { font-size: 12px; background: #f6f8fa; padding: 6px; overflow-x: auto; }
+</style>`
+
+var dashboardIndexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Koncur Dashboard</title>
+` + dashboardStyle + `
+</head>
+<body>
+<h1>Koncur Dashboard</h1>
+<div class="meta">{{len .Runs}} run(s) in <code>{{.Store}}</code> &mdash; <a href="/jobs">reruns</a>{{if .Running}} (one in progress){{end}}</div>
+<table>
+<tr><th>Run</th><th>Started</th><th>Target</th><th>Tool version</th><th>Tests</th><th>Passed</th><th>Failed</th><th>Flaky</th><th>Skipped</th><th>Duration</th></tr>
+{{range .Runs}}{{if .Summary}}<tr>
+<td><a href="/runs/{{.ID}}">{{.ID}}</a></td>
+<td>{{.StartedAt.Format "2006-01-02 15:04:05"}}</td>
+<td>{{.Target}}</td>
+<td>{{.ToolVersion}}</td>
+<td>{{.Summary.Total}}</td>
+<td class="passed">{{.Summary.Passed}}</td>
+<td{{if .Summary.Failed}} class="failed"{{end}}>{{.Summary.Failed}}</td>
+<td{{if .Summary.Flaky}} class="flaky"{{end}}>{{.Summary.Flaky}}</td>
+<td>{{.Summary.Skipped}}</td>
+<td>{{.Summary.Duration}}</td>
+</tr>{{end}}
+{{end}}</table>
+</body>
+</html>
+`))
+
+var dashboardJobsTemplate = template.Must(template.New("jobs").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Koncur Reruns</title>
+{{range .}}{{if .Running}}<meta http-equiv="refresh" content="5">{{end}}{{end}}
+` + dashboardStyle + `
+</head>
+<body>
+<p><a href="/">&larr; All runs</a></p>
+<h1>Reruns</h1>
+{{if not .}}<p>No reruns yet. Rerun failed tests from the page of a run.</p>{{end}}
+{{range .}}<h3 id="job-{{.ID}}">#{{.ID}} rerun of <a href="/runs/{{.RunID}}">{{.RunID}}</a>
+{{if .Running}}<span class="skipped">running</span>{{else if .Err}}<span class="failed">{{.Err}}</span>{{else}}<span class="passed">passed</span>{{end}}</h3>
+<div class="meta">Started {{.Started.Format "15:04:05"}}{{if not .Running}}, finished {{.Finished.Format "15:04:05"}}{{end}} &mdash; <code>koncur{{range .Args}} {{.}}{{end}}</code></div>
+<pre>{{.Output}}</pre>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDashboard(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "work", "failing", "analysis.log")
+	if err := os.MkdirAll(filepath.Dir(logFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(logFile, []byte("token: abc123\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	secretFile := filepath.Join(dir, "secret.txt")
+	if err := os.WriteFile(secretFile, []byte("not served"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	location := filepath.Join(dir, "results")
+	store, err := OpenResultsStore(location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := NewRunRecord(time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC), "kantra", "", &TestSummary{
+		Total: 2, Passed: 1, Failed: 1,
+		Tests: []TestResult{
+			{Name: "passing", TestFile: "tests/passing/test.yaml", Target: "kantra", Status: "passed", Duration: "1s"},
+			{Name: "failing", TestFile: "tests/failing/test.yaml", Target: "kantra", Status: "failed", Duration: "2s", WorkDir: filepath.Dir(logFile), ErrorMessage: "exit code mismatch"},
+		},
+	})
+	if err := store.SaveRun(run); err != nil {
+		t.Fatal(err)
+	}
+
+	echo, err := exec.LookPath("echo")
+	if err != nil {
+		t.Skip("echo is not available")
+	}
+	d := &dashboard{store: location, executable: echo}
+	server := httptest.NewServer(d.handler())
+	defer server.Close()
+
+	get := func(path string) (int, string) {
+		t.Helper()
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	if code, body := get("/"); code != http.StatusOK || !strings.Contains(body, "/runs/"+run.ID) {
+		t.Errorf("expected the run in the index, got %d: %s", code, body)
+	}
+	code, body := get("/runs/" + run.ID)
+	if code != http.StatusOK || !strings.Contains(body, "Rerun failed tests") || !strings.Contains(body, "exit code mismatch") {
+		t.Errorf("expected the run report with rerun forms, got %d: %s", code, body)
+	}
+	if code, _ := get("/runs/unknown"); code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown run, got %d", code)
+	}
+	if code, body := get("/runs/" + run.ID + "/file?path=" + url.QueryEscape(logFile)); code != http.StatusOK || body != "token: [REDACTED]\n" {
+		t.Errorf("expected the redacted log, got %d: %q", code, body)
+	}
+	if code, _ := get("/runs/" + run.ID + "/file?path=" + url.QueryEscape(secretFile)); code != http.StatusForbidden {
+		t.Errorf("expected files outside the run to be forbidden, got %d", code)
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	for header, value := range map[string]string{"Origin": "https://example.com", "Sec-Fetch-Site": "cross-site"} {
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/runs/"+run.ID+"/rerun", strings.NewReader("failed=true"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set(header, value)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("expected a cross-origin rerun with %s to be forbidden, got %d", header, resp.StatusCode)
+		}
+	}
+
+	resp, err := client.PostForm(server.URL+"/runs/"+run.ID+"/rerun", url.Values{"failed": {"true"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSeeOther {
+		t.Fatalf("expected a redirect to the jobs page, got %d", resp.StatusCode)
+	}
+
+	// The fake koncur echoes its arguments
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, body = get("/jobs")
+		if strings.Contains(body, "run tests/failing/test.yaml --target kantra") || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if !strings.Contains(body, "run tests/failing/test.yaml --target kantra") || strings.Contains(body, "tests/passing") {
+		t.Errorf("expected a rerun of the failed test only, got %s", body)
+	}
+}
+
+func TestRerunOutputDoesNotWaitForDashboard(t *testing.T) {
+	d := &dashboard{}
+	job := &rerunJob{ID: 1, Running: true}
+	d.jobs = append(d.jobs, job)
+
+	// The jobs page holds the dashboard's mutex only while taking a snapshot
+	d.mu.Lock()
+	written := make(chan struct{})
+	go func() {
+		job.output.Write([]byte("running tests\n"))
+		close(written)
+	}()
+	select {
+	case <-written:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the rerun output not to wait for the dashboard's mutex")
+	}
+	view := job.view()
+	d.mu.Unlock()
+
+	if view.Output != "running tests\n" || !view.Running {
+		t.Errorf("unexpected snapshot %+v", view)
+	}
+}
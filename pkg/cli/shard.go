@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"fmt"
+	"hash/fnv"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konveyor/test-harness/pkg/config"
+)
+
+// shard is one of several slices a suite is split into for parallel CI jobs
+type shard struct {
+	// Index is the 1-based shard number
+	Index int
+	Count int
+}
+
+// parseShard parses a shard given as "index/count", e.g. "2/5"
+func parseShard(value string) (*shard, error) {
+	index, count, ok := strings.Cut(value, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid shard %q, expected index/count (e.g. 2/5)", value)
+	}
+	s := &shard{}
+	var err error
+	if s.Index, err = strconv.Atoi(strings.TrimSpace(index)); err != nil {
+		return nil, fmt.Errorf("invalid shard index %q: %w", index, err)
+	}
+	if s.Count, err = strconv.Atoi(strings.TrimSpace(count)); err != nil {
+		return nil, fmt.Errorf("invalid shard count %q: %w", count, err)
+	}
+	if s.Count < 1 || s.Index < 1 || s.Index > s.Count {
+		return nil, fmt.Errorf("invalid shard %q, index must be between 1 and the count", value)
+	}
+	return s, nil
+}
+
+func (s *shard) String() string {
+	return fmt.Sprintf("%d/%d", s.Index, s.Count)
+}
+
+// selectTests returns the tests of this shard, in their original order. Tests are
+// assigned by a hash of their name, or, when durations are given, balanced so
+// every shard gets about the same total duration.
+func (s *shard) selectTests(testFiles []string, durations map[string]time.Duration) []string {
+	assigned := s.assign(testFiles, durations)
+	var selected []string
+	for _, testFile := range testFiles {
+		if assigned[testFile] == s.Index-1 {
+			selected = append(selected, testFile)
+		}
+	}
+	return selected
+}
+
+// assign maps every test file to a zero-based shard
+func (s *shard) assign(testFiles []string, durations map[string]time.Duration) map[string]int {
+	assigned := make(map[string]int, len(testFiles))
+
+	// Tests without history are assumed to take the average known duration
+	var known time.Duration
+	var count int
+	for _, testFile := range testFiles {
+		if d, ok := durations[resultName(testFile)]; ok {
+			known += d
+			count++
+		}
+	}
+	if count == 0 {
+		for _, testFile := range testFiles {
+			h := fnv.New32a()
+			h.Write([]byte(resultName(testFile)))
+			assigned[testFile] = int(h.Sum32() % uint32(s.Count))
+		}
+		return assigned
+	}
+	average := known / time.Duration(count)
+
+	// Longest tests first, each to the least loaded shard. Ties are broken by
+	// name so every CI job computes the same assignment.
+	sorted := append([]string{}, testFiles...)
+	duration := func(testFile string) time.Duration {
+		if d, ok := durations[resultName(testFile)]; ok {
+			return d
+		}
+		return average
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		di, dj := duration(sorted[i]), duration(sorted[j])
+		if di != dj {
+			return di > dj
+		}
+		return filepath.ToSlash(sorted[i]) < filepath.ToSlash(sorted[j])
+	})
+	loads := make([]time.Duration, s.Count)
+	for _, testFile := range sorted {
+		least := 0
+		for i := range loads {
+			if loads[i] < loads[least] {
+				least = i
+			}
+		}
+		assigned[testFile] = least
+		loads[least] += duration(testFile)
+	}
+	return assigned
+}
+
+// resultName is the name of a test in results, which tests are also sharded and
+// selected by: the name of its directory, with the variant of a matrix test
+func resultName(testFile string) string {
+	path, variant := config.SplitTestRef(testFile)
+	name := filepath.Base(filepath.Dir(path))
+	if variant != "" {
+		name += "[" + variant + "]"
+	}
+	return name
+}
+
+// recordedDurations returns the duration of every test in its latest recorded run
+// on a target, skipping tests that did not run
+func recordedDurations(location, target string) (map[string]time.Duration, error) {
+	store, err := OpenResultsStore(location)
+	if err != nil {
+		return nil, err
+	}
+	runs, err := store.ListRuns()
+	if err != nil {
+		return nil, err
+	}
+	durations := map[string]time.Duration{}
+	// Runs are ordered from oldest to newest, so later runs overwrite earlier durations
+	for _, run := range runs {
+		if run.Target != target || run.Summary == nil {
+			continue
+		}
+		for _, result := range run.Summary.Tests {
+			if result.Status == "skipped" {
+				continue
+			}
+			if d, err := time.ParseDuration(result.Duration); err == nil && d > 0 {
+				durations[result.Name] = d
+			}
+		}
+	}
+	return durations, nil
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseShard(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    *shard
+		wantErr bool
+	}{
+		{value: "2/5", want: &shard{Index: 2, Count: 5}},
+		{value: "1/1", want: &shard{Index: 1, Count: 1}},
+		{value: "0/5", wantErr: true},
+		{value: "6/5", wantErr: true},
+		{value: "2", wantErr: true},
+		{value: "a/b", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseShard(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseShard(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseShard(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestShardSelectTests(t *testing.T) {
+	var testFiles []string
+	for i := range 20 {
+		testFiles = append(testFiles, filepath.Join("tests", fmt.Sprintf("test-%02d", i), "test.yaml"))
+	}
+
+	// Every test is in exactly one shard, whether hashed or balanced
+	balanced := map[string]time.Duration{"test-00": 10 * time.Minute, "test-01": 8 * time.Minute, "test-02": time.Minute}
+	for _, durations := range []map[string]time.Duration{nil, balanced} {
+		seen := map[string]int{}
+		for i := 1; i <= 3; i++ {
+			s := &shard{Index: i, Count: 3}
+			selected := s.selectTests(testFiles, durations)
+			if !reflect.DeepEqual(selected, s.selectTests(testFiles, durations)) {
+				t.Errorf("shard %s is not deterministic", s)
+			}
+			for _, testFile := range selected {
+				seen[testFile]++
+			}
+		}
+		for _, testFile := range testFiles {
+			if seen[testFile] != 1 {
+				t.Errorf("%s is in %d shards, want 1", testFile, seen[testFile])
+			}
+		}
+	}
+
+	// The two longest tests are balanced into different shards
+	s := &shard{Index: 1, Count: 2}
+	assigned := s.assign(testFiles, balanced)
+	if assigned[testFiles[0]] == assigned[testFiles[1]] {
+		t.Errorf("expected the two longest tests in different shards, got %v", assigned)
+	}
+
+	// Hashing ignores the order and the other tests
+	hashed := s.assign(testFiles, nil)
+	reversed := append([]string{}, testFiles[10:]...)
+	if got := s.assign(reversed, nil); got[testFiles[15]] != hashed[testFiles[15]] {
+		t.Errorf("expected hashed shard to only depend on the test name")
+	}
+}
+
+func TestRecordedDurations(t *testing.T) {
+	location := t.TempDir()
+	store, err := OpenResultsStore(location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	runs := []*RunRecord{
+		NewRunRecord(start, "kantra", "", &TestSummary{Tests: []TestResult{
+			{Name: "a", Status: "passed", Duration: "1m"},
+			{Name: "b", Status: "failed", Duration: "2m"},
+		}}),
+		NewRunRecord(start.Add(time.Hour), "kantra", "", &TestSummary{Tests: []TestResult{
+			{Name: "a", Status: "passed", Duration: "3m"},
+			{Name: "b", Status: "skipped", Duration: "0s"},
+		}}),
+		NewRunRecord(start.Add(2*time.Hour), "tackle-hub", "", &TestSummary{Tests: []TestResult{
+			{Name: "a", Status: "passed", Duration: "9m"},
+		}}),
+	}
+	for _, run := range runs {
+		if err := store.SaveRun(run); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := recordedDurations(location, "kantra")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]time.Duration{"a": 3 * time.Minute, "b": 2 * time.Minute}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("recordedDurations() = %v, want %v", got, want)
+	}
+}
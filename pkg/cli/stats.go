@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsStore  string
+	statsFilter string
+	statsLast   int
+	statsJSON   bool
+)
+
+// TestStats aggregates the history of a single test on a single target
+type TestStats struct {
+	Name     string  `json:"name"`
+	Target   string  `json:"target"`
+	Runs     int     `json:"runs"`
+	Passed   int     `json:"passed"`
+	Failed   int     `json:"failed"`
+	Skipped  int     `json:"skipped"`
+	PassRate float64 `json:"passRate"`
+	// AvgDuration is the mean duration of non-skipped runs
+	AvgDuration time.Duration `json:"avgDuration"`
+	// LastDuration is the duration of the most recent non-skipped run
+	LastDuration time.Duration `json:"lastDuration"`
+	// DurationChange is the relative change of the last duration against the average of earlier runs
+	DurationChange float64 `json:"durationChange"`
+	// LastStatus is the status of the most recent run
+	LastStatus string `json:"lastStatus"`
+	// FirstFailedIn is the tool version (or run ID) where the current failure streak started
+	FirstFailedIn string `json:"firstFailedIn,omitempty"`
+}
+
+// NewStatsCmd creates the stats command
+func NewStatsCmd() *cobra.Command {
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show historical pass rates and duration trends",
+		Long: `Summarize the runs recorded in the results store: per-test pass rates,
+duration trends, and the tool version in which a currently failing test first failed.
+
+Runs are recorded automatically by 'koncur run' (see --results-store and --tool-version).`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := OpenResultsStore(statsStore)
+			if err != nil {
+				return err
+			}
+			runs, err := store.ListRuns()
+			if err != nil {
+				return err
+			}
+			if len(runs) == 0 {
+				fmt.Printf("No runs recorded in %s\n", statsStore)
+				return nil
+			}
+			if statsLast > 0 && len(runs) > statsLast {
+				runs = runs[len(runs)-statsLast:]
+			}
+
+			stats := ComputeTestStats(runs)
+			if statsFilter != "" {
+				filtered := []TestStats{}
+				for _, s := range stats {
+					if strings.Contains(s.Name, statsFilter) {
+						filtered = append(filtered, s)
+					}
+				}
+				stats = filtered
+			}
+
+			if statsJSON {
+				data, err := json.MarshalIndent(stats, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal stats: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			fmt.Printf("%d run(s) from %s to %s\n\n", len(runs),
+				runs[0].StartedAt.Format(time.RFC3339), runs[len(runs)-1].StartedAt.Format(time.RFC3339))
+			printTestStats(stats)
+			return nil
+		},
+	}
+
+	statsCmd.Flags().StringVar(&statsStore, "results-store", defaultResultsStore, "Results store location")
+	statsCmd.Flags().StringVarP(&statsFilter, "filter", "f", "", "Only show tests whose name contains this pattern")
+	statsCmd.Flags().IntVar(&statsLast, "last", 0, "Only consider the most recent N runs (0 for all)")
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "Print statistics as JSON")
+
+	return statsCmd
+}
+
+// ComputeTestStats aggregates runs (ordered oldest to newest) into per-test statistics
+func ComputeTestStats(runs []*RunRecord) []TestStats {
+	type history struct {
+		stats     TestStats
+		durations []time.Duration
+		// failingSince is the version of the first run in the current failure streak
+		failingSince string
+	}
+	byKey := map[string]*history{}
+
+	for _, run := range runs {
+		if run.Summary == nil {
+			continue
+		}
+		version := run.ToolVersion
+		if version == "" {
+			version = run.ID
+		}
+		for _, result := range run.Summary.Tests {
+			target := result.Target
+			if target == "" {
+				target = run.Target
+			}
+			key := target + "/" + result.Name
+			h, ok := byKey[key]
+			if !ok {
+				h = &history{stats: TestStats{Name: result.Name, Target: target}}
+				byKey[key] = h
+			}
+
+			h.stats.Runs++
+			h.stats.LastStatus = result.Status
+			switch result.Status {
+			case "passed":
+				h.stats.Passed++
+				h.failingSince = ""
+			case "failed", "flaky":
+				h.stats.Failed++
+				if h.failingSince == "" {
+					h.failingSince = version
+				}
+			case "skipped":
+				h.stats.Skipped++
+				continue
+			}
+			if d, err := time.ParseDuration(result.Duration); err == nil {
+				h.durations = append(h.durations, d)
+			}
+		}
+	}
+
+	stats := make([]TestStats, 0, len(byKey))
+	for _, h := range byKey {
+		s := h.stats
+		if executed := s.Passed + s.Failed; executed > 0 {
+			s.PassRate = float64(s.Passed) / float64(executed)
+		}
+		if n := len(h.durations); n > 0 {
+			var total time.Duration
+			for _, d := range h.durations {
+				total += d
+			}
+			s.AvgDuration = total / time.Duration(n)
+			s.LastDuration = h.durations[n-1]
+			if n > 1 {
+				previous := (total - s.LastDuration) / time.Duration(n-1)
+				if previous > 0 {
+					s.DurationChange = float64(s.LastDuration-previous) / float64(previous)
+				}
+			}
+		}
+		if s.LastStatus == "failed" || s.LastStatus == "flaky" {
+			s.FirstFailedIn = h.failingSince
+		}
+		stats = append(stats, s)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Name != stats[j].Name {
+			return stats[i].Name < stats[j].Name
+		}
+		return stats[i].Target < stats[j].Target
+	})
+	return stats
+}
+
+// printTestStats prints statistics as an aligned table
+func printTestStats(stats []TestStats) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TEST\tTARGET\tRUNS\tPASS RATE\tAVG DURATION\tLAST DURATION\tTREND\tLAST STATUS\tFIRST FAILED IN")
+	for _, s := range stats {
+		trend := "-"
+		if s.DurationChange != 0 {
+			trend = fmt.Sprintf("%+.0f%%", s.DurationChange*100)
+		}
+		firstFailed := s.FirstFailedIn
+		if firstFailed == "" {
+			firstFailed = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%.0f%%\t%s\t%s\t%s\t%s\t%s\n",
+			s.Name, s.Target, s.Runs, s.PassRate*100,
+			s.AvgDuration.Round(time.Second), s.LastDuration.Round(time.Second),
+			trend, s.LastStatus, firstFailed)
+	}
+	w.Flush()
+}
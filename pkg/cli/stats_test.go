@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+func newRun(version string, at time.Time, results ...TestResult) *RunRecord {
+	return NewRunRecord(at, "kantra", version, &TestSummary{Tests: results})
+}
+
+func TestResultsStoreRoundTrip(t *testing.T) {
+	store, err := OpenResultsStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("OpenResultsStore returned error: %v", err)
+	}
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	second := newRun("v2", base.Add(time.Hour), TestResult{Name: "a", Status: "failed", Duration: "2s"})
+	first := newRun("v1", base, TestResult{Name: "a", Status: "passed", Duration: "1s"})
+	for _, r := range []*RunRecord{second, first} {
+		if err := store.SaveRun(r); err != nil {
+			t.Fatalf("SaveRun returned error: %v", err)
+		}
+	}
+
+	runs, err := store.ListRuns()
+	if err != nil {
+		t.Fatalf("ListRuns returned error: %v", err)
+	}
+	if len(runs) != 2 || runs[0].ToolVersion != "v1" || runs[1].ToolVersion != "v2" {
+		t.Fatalf("Expected runs ordered oldest first, got %+v", runs)
+	}
+}
+
+func TestPreviousOutput(t *testing.T) {
+	store, err := OpenResultsStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("OpenResultsStore returned error: %v", err)
+	}
+
+	if runID, _, err := PreviousOutput(store, "kantra", "a"); err != nil || runID != "" {
+		t.Fatalf("Expected no previous output in an empty store, got %q, %v", runID, err)
+	}
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	output := func(rule string) []konveyor.RuleSet {
+		return []konveyor.RuleSet{{Name: "rs", Violations: map[string]konveyor.Violation{rule: {Description: rule}}}}
+	}
+	first := newRun("v1", base, TestResult{Name: "a"})
+	second := newRun("v2", base.Add(time.Hour), TestResult{Name: "b"})
+	hub := NewRunRecord(base.Add(2*time.Hour), "tackle-hub", "v3", &TestSummary{})
+	for _, r := range []*RunRecord{first, second, hub} {
+		if err := store.SaveRun(r); err != nil {
+			t.Fatalf("SaveRun returned error: %v", err)
+		}
+	}
+	for _, o := range []struct {
+		run    *RunRecord
+		test   string
+		ruleID string
+	}{{first, "a", "rule-1"}, {second, "b", "rule-2"}, {hub, "a", "rule-3"}} {
+		if err := store.SaveOutput(o.run.ID, o.run.Target, o.test, output(o.ruleID)); err != nil {
+			t.Fatalf("SaveOutput returned error: %v", err)
+		}
+	}
+
+	// Test a did not run in the latest kantra run, so the first run is its baseline
+	runID, previous, err := PreviousOutput(store, "kantra", "a")
+	if err != nil {
+		t.Fatalf("PreviousOutput returned error: %v", err)
+	}
+	if runID != first.ID || len(previous) != 1 || previous[0].Violations["rule-1"].Description != "rule-1" {
+		t.Errorf("Expected output of run %s, got run %s: %+v", first.ID, runID, previous)
+	}
+}
+
+func TestOpenResultsStore_UnknownScheme(t *testing.T) {
+	if _, err := OpenResultsStore("sqlite:///tmp/results.db"); err == nil {
+		t.Error("Expected error for unsupported store scheme")
+	}
+}
+
+func TestComputeTestStats(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	runs := []*RunRecord{
+		newRun("v1", base, TestResult{Name: "a", Status: "passed", Duration: "10s"}, TestResult{Name: "b", Status: "passed", Duration: "1s"}, TestResult{Name: "c", Status: "passed", Duration: "1s"}),
+		newRun("v2", base.Add(time.Hour), TestResult{Name: "a", Status: "failed", Duration: "10s"}, TestResult{Name: "b", Status: "skipped", Duration: "0s"}, TestResult{Name: "c", Status: "flaky", Duration: "1s"}),
+		newRun("v3", base.Add(2*time.Hour), TestResult{Name: "a", Status: "failed", Duration: "20s"}, TestResult{Name: "b", Status: "passed", Duration: "1s"}, TestResult{Name: "c", Status: "flaky", Duration: "1s"}),
+	}
+
+	stats := ComputeTestStats(runs)
+	if len(stats) != 3 {
+		t.Fatalf("Expected stats for 3 tests, got %d", len(stats))
+	}
+
+	a := stats[0]
+	if a.Name != "a" || a.Runs != 3 || a.Passed != 1 || a.Failed != 2 {
+		t.Errorf("Unexpected stats for a: %+v", a)
+	}
+	if a.FirstFailedIn != "v2" {
+		t.Errorf("Expected a to have first failed in v2, got %q", a.FirstFailedIn)
+	}
+	if a.LastDuration != 20*time.Second || a.DurationChange != 1.0 {
+		t.Errorf("Expected last duration 20s (+100%%), got %s (%v)", a.LastDuration, a.DurationChange)
+	}
+
+	b := stats[1]
+	if b.PassRate != 1.0 || b.Skipped != 1 || b.FirstFailedIn != "" {
+		t.Errorf("Unexpected stats for b: %+v", b)
+	}
+
+	if c := stats[2]; c.Failed != 2 || c.FirstFailedIn != "v2" {
+		t.Errorf("Expected flaky c to have first failed in v2, got %+v", c)
+	}
+}
@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/parser"
+	yaml2 "gopkg.in/yaml.v2"
+)
+
+// defaultResultsStore is where run results are persisted unless --results-store says otherwise
+const defaultResultsStore = ".koncur/results"
+
+// RunRecord is a single persisted koncur run
+type RunRecord struct {
+	ID          string       `json:"id"`
+	StartedAt   time.Time    `json:"startedAt"`
+	Target      string       `json:"target"`
+	ToolVersion string       `json:"toolVersion,omitempty"`
+	Summary     *TestSummary `json:"summary"`
+}
+
+// ResultsStore persists run results so they can be analyzed over time
+type ResultsStore interface {
+	// SaveRun persists a run record
+	SaveRun(run *RunRecord) error
+	// ListRuns returns all stored runs ordered from oldest to newest
+	ListRuns() ([]*RunRecord, error)
+	// SaveOutput persists the normalized output of a test in a run
+	SaveOutput(runID, target, test string, output []konveyor.RuleSet) error
+	// LoadOutput returns the output of a test stored with a run, or nil if there is none
+	LoadOutput(runID, target, test string) ([]konveyor.RuleSet, error)
+}
+
+// storeBackends maps a store location scheme to its constructor.
+// Locations without a scheme use the file backend.
+var storeBackends = map[string]func(location string) (ResultsStore, error){
+	"file": func(location string) (ResultsStore, error) {
+		return &fileResultsStore{dir: location}, nil
+	},
+}
+
+// OpenResultsStore opens the results store at the given location, e.g. ".koncur/results" or "file:///var/koncur"
+func OpenResultsStore(location string) (ResultsStore, error) {
+	scheme, rest := "file", location
+	if i := strings.Index(location, "://"); i > 0 {
+		scheme, rest = location[:i], location[i+3:]
+	}
+	backend, ok := storeBackends[scheme]
+	if !ok {
+		return nil, fmt.Errorf("unsupported results store: %s", location)
+	}
+	return backend(rest)
+}
+
+// NewRunRecord creates a run record with an ID derived from the start time
+func NewRunRecord(startedAt time.Time, target, toolVersion string, summary *TestSummary) *RunRecord {
+	return &RunRecord{
+		ID:          startedAt.UTC().Format("20060102-150405.000"),
+		StartedAt:   startedAt,
+		Target:      target,
+		ToolVersion: toolVersion,
+		Summary:     summary,
+	}
+}
+
+// fileResultsStore stores one JSON document per run in a directory
+type fileResultsStore struct {
+	dir string
+}
+
+func (f *fileResultsStore) SaveRun(run *RunRecord) error {
+	if err := os.MkdirAll(f.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create results store %s: %w", f.dir, err)
+	}
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run: %w", err)
+	}
+	path := filepath.Join(f.dir, fmt.Sprintf("run-%s.json", run.ID))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write run %s: %w", path, err)
+	}
+	return nil
+}
+
+func (f *fileResultsStore) ListRuns() ([]*RunRecord, error) {
+	entries, err := os.ReadDir(f.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results store %s: %w", f.dir, err)
+	}
+
+	var runs []*RunRecord
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), "run-") || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(f.dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read run %s: %w", entry.Name(), err)
+		}
+		var run RunRecord
+		if err := json.Unmarshal(data, &run); err != nil {
+			return nil, fmt.Errorf("failed to parse run %s: %w", entry.Name(), err)
+		}
+		runs = append(runs, &run)
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].StartedAt.Before(runs[j].StartedAt)
+	})
+	return runs, nil
+}
+
+// outputPath is where the output of a test in a run is stored
+func (f *fileResultsStore) outputPath(runID, target, test string) string {
+	return filepath.Join(f.dir, "outputs", runID, target, test+".yaml")
+}
+
+func (f *fileResultsStore) SaveOutput(runID, target, test string, output []konveyor.RuleSet) error {
+	path := f.outputPath(runID, target, test)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	// yaml.v2 matches analyzer-lsp's marshalling of konveyor types
+	data, err := yaml2.Marshal(output)
+	if err != nil {
+		return fmt.Errorf("failed to marshal output: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write output %s: %w", path, err)
+	}
+	return nil
+}
+
+func (f *fileResultsStore) LoadOutput(runID, target, test string) ([]konveyor.RuleSet, error) {
+	path := f.outputPath(runID, target, test)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+	return parser.ParseOutput(path)
+}
+
+// PreviousOutput returns the most recently stored output of a test on a target,
+// with the ID of the run it belongs to. The run ID is empty when there is none.
+func PreviousOutput(store ResultsStore, target, test string) (string, []konveyor.RuleSet, error) {
+	runs, err := store.ListRuns()
+	if err != nil {
+		return "", nil, err
+	}
+	for i := len(runs) - 1; i >= 0; i-- {
+		if runs[i].Target != target {
+			continue
+		}
+		output, err := store.LoadOutput(runs[i].ID, target, test)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to load output of run %s: %w", runs[i].ID, err)
+		}
+		if output != nil {
+			return runs[i].ID, output, nil
+		}
+	}
+	return "", nil, nil
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// maxSummaryErrors is the number of validation errors listed per test in the Markdown summary
+const maxSummaryErrors = 3
+
+// FormatMarkdownSummary renders the test results as a Markdown table suitable for $GITHUB_STEP_SUMMARY
+func FormatMarkdownSummary(summary *TestSummary) string {
+	var sb strings.Builder
+
+	sb.WriteString("## Koncur Test Results\n\n")
+	fmt.Fprintf(&sb, "**%d total** — ✅ %d passed, ❌ %d failed, ", summary.Total, summary.Passed, summary.Failed)
+	if summary.Flaky > 0 {
+		fmt.Fprintf(&sb, "⚠️ %d flaky, ", summary.Flaky)
+	}
+	fmt.Fprintf(&sb, "⏭️ %d skipped in %s\n\n", summary.Skipped, summary.Duration)
+
+	sb.WriteString("| Test | Target | Status | Duration | Top validation errors |\n")
+	sb.WriteString("|------|--------|--------|----------|-----------------------|\n")
+	for _, result := range summary.Tests {
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s |\n",
+			markdownCell(result.Name),
+			markdownCell(result.Target),
+			markdownStatus(result.Status),
+			markdownCell(result.Duration),
+			markdownTopErrors(result),
+		)
+	}
+
+	return sb.String()
+}
+
+// WriteMarkdownSummary appends the Markdown summary to the given file.
+// Appending matches how GitHub Actions expects steps to write $GITHUB_STEP_SUMMARY.
+func WriteMarkdownSummary(summary *TestSummary, path string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open summary file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(FormatMarkdownSummary(summary)); err != nil {
+		return fmt.Errorf("failed to write summary file: %w", err)
+	}
+	return nil
+}
+
+func markdownStatus(status string) string {
+	switch status {
+	case "passed":
+		return "✅ passed"
+	case "failed":
+		return "❌ failed"
+	case "flaky":
+		return "⚠️ flaky"
+	case "skipped":
+		return "⏭️ skipped"
+	}
+	return status
+}
+
+// markdownTopErrors lists the first few validation errors (or the error message) for a test
+func markdownTopErrors(result TestResult) string {
+	var lines []string
+	if result.Status == "flaky" {
+		lines = append(lines, fmt.Sprintf("passed %d of %d attempts", result.PassedAttempts, result.Attempts))
+	}
+	if result.SkipReason != "" {
+		lines = append(lines, result.SkipReason)
+	}
+	if result.ErrorMessage != "" {
+		lines = append(lines, result.ErrorMessage)
+	}
+	for i, verr := range result.ValidationErrors {
+		if i == maxSummaryErrors {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(result.ValidationErrors)-maxSummaryErrors))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("`%s`: %s", verr.Path, verr.Message))
+	}
+	for i := range lines {
+		lines[i] = markdownCell(lines[i])
+	}
+	return strings.Join(lines, "<br>")
+}
+
+// markdownCell escapes content so it stays inside a single table cell
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	s = strings.ReplaceAll(s, "\r\n", " ")
+	s = strings.ReplaceAll(s, "\n", " ")
+	return strings.TrimSpace(s)
+}
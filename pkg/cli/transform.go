@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/targets"
+	"github.com/konveyor/test-harness/pkg/validator"
+)
+
+// runTransformTest runs a transform test's kantra transform command and validates
+// the files it produced against the expected files
+func runTransformTest(testResult *TestResult, test *config.TestDefinition, target targets.Target) (*TestResult, error) {
+	transformer, ok := target.(targets.Transformer)
+	if !ok {
+		testResult.Status = "failed"
+		testResult.ErrorMessage = fmt.Sprintf("target %s cannot run transforms", target.Name())
+		testResult.FailureKind = FailureConfig
+		return testResult, fmt.Errorf("target %s cannot run transforms", target.Name())
+	}
+
+	result, err := transformer.Transform(context.Background(), test)
+	if err != nil {
+		testResult.Status = "failed"
+		testResult.ErrorMessage = fmt.Sprintf("execution failed: %v", err)
+		testResult.FailureKind = FailureExecution
+		return testResult, fmt.Errorf("execution failed: %w", err)
+	}
+	testResult.ExitCode = result.ExitCode
+	testResult.ExpectedExitCode = test.Expect.ExitCode
+	testResult.WorkDir = result.WorkDir
+
+	if !test.Expect.ExitCode.Matches(result.ExitCode) {
+		testResult.Status = "failed"
+		testResult.ErrorMessage = fmt.Sprintf("Exit code mismatch: expected %s, got %d", test.Expect.ExitCode, result.ExitCode)
+		testResult.FailureKind = FailureValidation
+		if showProgress() {
+			color.Red("  %s Exit code mismatch: expected %s, got %d", symbolFail, test.Expect.ExitCode, result.ExitCode)
+		}
+		return testResult, nil
+	}
+
+	expectedDir := test.Expect.Files.Dir
+	if !filepath.IsAbs(expectedDir) {
+		expectedDir = filepath.Join(test.GetTestDir(), expectedDir)
+	}
+	validation, err := validator.ValidateTransformedFiles(expectedDir, result.OutputDir, test.Expect.Files.Exact)
+	if err != nil {
+		testResult.Status = "failed"
+		testResult.ErrorMessage = fmt.Sprintf("validation error: %v", err)
+		testResult.FailureKind = FailureExecution
+		return testResult, fmt.Errorf("validation error: %w", err)
+	}
+	testResult.ValidationErrors = validation.Errors
+	if validation.Passed {
+		testResult.Status = "passed"
+		if showProgress() {
+			green := color.New(color.FgGreen, color.Bold)
+			green.Printf("  %s PASSED", symbolPass)
+			fmt.Printf(" - Duration: %s\n", result.Duration)
+		}
+		return testResult, nil
+	}
+
+	testResult.Status = "failed"
+	testResult.FailureKind = FailureValidation
+	testResult.ValidationDiffs = validation.Diffs
+	if showProgress() {
+		red := color.New(color.FgRed, color.Bold)
+		red.Printf("  %s FAILED\n", symbolFail)
+		fmt.Printf("\n    Found %d validation error(s):\n\n", len(validation.Errors))
+		for i, err := range validation.Errors {
+			err.Print(i + 1)
+		}
+		fmt.Println()
+		printValidationDiffs(validation.Diffs)
+	}
+	return testResult, nil
+}
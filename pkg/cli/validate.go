@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 
+	"github.com/fatih/color"
 	"github.com/konveyor/test-harness/pkg/config"
 	"github.com/konveyor/test-harness/pkg/util"
 	"github.com/spf13/cobra"
@@ -11,31 +12,79 @@ import (
 // NewValidateCmd creates the validate command
 func NewValidateCmd() *cobra.Command {
 	validateCmd := &cobra.Command{
-		Use:   "validate <test-file>",
-		Short: "Validate a test definition",
-		Long:  `Check if a test definition is valid without running it.`,
-		Args:  cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			testFile := args[0]
-			log := util.GetLogger()
+		Use:   "validate <test-file-or-directory>...",
+		Short: "Validate test definitions",
+		Long: `Check if test definitions are valid without running them.
 
-			log.Info("Validating test definition", "file", testFile)
+Directories are searched for test.yaml files. Every test definition is loaded with
+its expected output files and validated, fields that are not in the test definition
+schema (see 'koncur schema') are reported, and a summary lists the invalid tests,
+which makes a fast pre-check for pull requests:
 
-			// Load test definition
-			test, err := config.Load(testFile)
+  koncur validate tests`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			testFiles, err := discoverTestFiles(args, &testSelector{})
 			if err != nil {
 				return err
 			}
 
-			// Validate test definition
-			if err := config.Validate(test); err != nil {
-				return err
+			// A single test reports its error like before directories were supported
+			if len(testFiles) == 1 {
+				name, err := validateTestFile(testFiles[0])
+				if err != nil {
+					return configError("%w", err)
+				}
+				fmt.Printf("%s Test definition is valid: %s\n", symbolPass, name)
+				return nil
 			}
 
-			fmt.Printf("✓ Test definition is valid: %s\n", test.Name)
+			var invalid []string
+			for _, testFile := range testFiles {
+				name, err := validateTestFile(testFile)
+				if err != nil {
+					color.Red("%s %s: %v", symbolFail, testFile, err)
+					invalid = append(invalid, testFile)
+					continue
+				}
+				fmt.Printf("%s Test definition is valid: %s\n", symbolPass, name)
+			}
+
+			fmt.Printf("\nSummary: %d test(s) checked, %d valid, %d invalid\n", len(testFiles), len(testFiles)-len(invalid), len(invalid))
+			for _, testFile := range invalid {
+				color.Red("  %s %s", symbolFail, testFile)
+			}
+			if len(invalid) > 0 {
+				return configError("%d of %d test definition(s) are invalid", len(invalid), len(testFiles))
+			}
 			return nil
 		},
 	}
 
 	return validateCmd
 }
+
+// validateTestFile loads a test definition with its expected output files and
+// validates it. It returns the test's name.
+func validateTestFile(testFile string) (string, error) {
+	log := util.GetLogger()
+	log.Info("Validating test definition", "file", testFile)
+
+	test, err := config.Load(testFile)
+	if err != nil {
+		return "", err
+	}
+	if err := config.Validate(test); err != nil {
+		return "", err
+	}
+
+	// Unknown fields are ignored when loading, they are usually typos
+	path, _ := config.SplitTestRef(testFile)
+	schema := config.TestDefinitionSchema()
+	for _, file := range append([]string{path}, test.BaseFiles()...) {
+		if err := config.CheckUnknownFields(file, schema); err != nil {
+			return "", err
+		}
+	}
+	return test.Name, nil
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateDirectory(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		"valid":            "name: valid\nanalysis:\n  application: https://github.com/example/app.git\n  analysisMode: source-only\nexpect:\n  exitCode: 0\n  output:\n    result:\n    - name: ruleset\n      tags: [Java]\n",
+		"unknown-field":    "name: unknown-field\nanalysis:\n  application: https://github.com/example/app.git\n  analysisMode: source-only\n  labelSelectr: konveyor.io/target=quarkus\nexpect:\n  exitCode: 0\n  output:\n    result:\n    - name: ruleset\n      tags: [Java]\n",
+		"missing-expected": "name: missing-expected\nanalysis:\n  application: https://github.com/example/app.git\n  analysisMode: source-only\nexpect:\n  exitCode: 0\n  output:\n    file: expected-output.yaml\n",
+	}
+	for name, content := range tests {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name, "test.yaml"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	validate := func(args ...string) error {
+		cmd := NewValidateCmd()
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		cmd.SetArgs(args)
+		return cmd.Execute()
+	}
+
+	if err := validate(filepath.Join(dir, "valid")); err != nil {
+		t.Errorf("expected the valid test to pass, got %v", err)
+	}
+	if err := validate(filepath.Join(dir, "unknown-field")); err == nil || !strings.Contains(err.Error(), "line 5: unknown field analysis.labelSelectr") {
+		t.Errorf("expected the unknown field to be reported, got %v", err)
+	}
+	if err := validate(filepath.Join(dir, "missing-expected", "test.yaml")); err == nil || exitCodeFor(err) != ExitCodeConfigError {
+		t.Errorf("expected a config error for a missing expected output, got %v", err)
+	}
+	err := validate(dir)
+	if err == nil || exitCodeFor(err) != ExitCodeConfigError || err.Error() != "2 of 3 test definition(s) are invalid" {
+		t.Errorf("expected 2 of 3 tests to be invalid, got %v", err)
+	}
+}
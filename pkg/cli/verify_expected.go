@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var verifyExpectedJSON bool
+
+// NewVerifyExpectedCmd creates the verify-expected command
+func NewVerifyExpectedCmd() *cobra.Command {
+	verifyExpectedCmd := &cobra.Command{
+		Use:   "verify-expected [test-file-or-directory]...",
+		Short: "Lint expected outputs for authoring mistakes",
+		Long: `Check expected-output files and inline expected results for mistakes that would
+otherwise only show up after an expensive run: unknown fields, duplicate keys and rule
+IDs, rulesets without violations, insights or tags (they are dropped before comparison),
+invalid violation categories and incident URIs that are not file:// URIs.
+
+Arguments may be test definitions, directories of tests (default: tests) or expected
+output files. Exits with the configuration error code when issues are found.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				args = []string{defaultTestDir()}
+			}
+
+			var issues []config.LintIssue
+			checked := 0
+			for _, arg := range args {
+				files, err := lintTargets(arg)
+				if err != nil {
+					return err
+				}
+				for _, file := range files {
+					fileIssues, err := lintFile(file)
+					if err != nil {
+						return configError("%w", err)
+					}
+					issues = append(issues, fileIssues...)
+					checked++
+				}
+			}
+
+			if verifyExpectedJSON {
+				if issues == nil {
+					issues = []config.LintIssue{}
+				}
+				data, err := json.MarshalIndent(issues, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal issues: %w", err)
+				}
+				fmt.Println(string(data))
+			} else {
+				for _, issue := range issues {
+					color.Red("%s %s", symbolFail, issue)
+				}
+			}
+
+			if len(issues) > 0 {
+				return configError("found %d issue(s) in expected outputs", len(issues))
+			}
+			if !verifyExpectedJSON {
+				fmt.Printf("%s %d file(s) checked, no issues found\n", symbolPass, checked)
+			}
+			return nil
+		},
+	}
+
+	verifyExpectedCmd.Flags().BoolVar(&verifyExpectedJSON, "json", false, "Print issues as JSON")
+
+	return verifyExpectedCmd
+}
+
+// lintTargets expands a directory into its test definitions
+func lintTargets(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, configError("%w", err)
+	}
+	if !info.IsDir() {
+		if filepath.Base(path) == "test.yaml" {
+			return testVariants(path), nil
+		}
+		return []string{path}, nil
+	}
+	files, err := findTestFiles(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find test files: %w", err)
+	}
+	return files, nil
+}
+
+// lintFile lints a test definition, or an expected output file given directly
+func lintFile(path string) ([]config.LintIssue, error) {
+	if testFile, _ := config.SplitTestRef(path); filepath.Base(testFile) == "test.yaml" {
+		return config.LintTestFile(path)
+	}
+	return config.LintExpectedOutput(path)
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ApplicationRegistryFileName is the name of application registry files
+const ApplicationRegistryFileName = "applications.yaml"
+
+// ApplicationAliasPrefix marks an application of a test definition as a registry
+// alias, e.g. "alias:tackle-testapp"
+const ApplicationAliasPrefix = "alias:"
+
+// ApplicationRegistry maps short names to the applications tests analyze, so their
+// URLs and branches are maintained in one place
+type ApplicationRegistry struct {
+	Applications map[string]RegisteredApplication `yaml:"applications" validate:"dive"`
+
+	path string `yaml:"-"`
+}
+
+// RegisteredApplication is an application of the registry
+type RegisteredApplication struct {
+	// URL is a git repository URL, or a path relative to the registry file
+	URL string `yaml:"url" validate:"required"`
+	// Ref is the branch or tag to analyze (default: the default branch)
+	Ref string `yaml:"ref,omitempty"`
+	// Path is the directory of the application in the repository
+	Path string `yaml:"path,omitempty"`
+	// Requires lists the credentials analyzing the application needs
+	Requires *ApplicationCredentials `yaml:"requires,omitempty"`
+}
+
+// ApplicationCredentials are the credentials an application needs
+type ApplicationCredentials struct {
+	// MavenSettings needs a maven settings file in the target configuration, as
+	// requireMavenSettings does
+	MavenSettings bool `yaml:"mavenSettings,omitempty"`
+	// Env needs these environment variables to be set, e.g. tokens the URL uses
+	Env []string `yaml:"env,omitempty" validate:"dive,required"`
+}
+
+// LoadApplicationRegistry reads and checks an application registry file
+func LoadApplicationRegistry(path string) (*ApplicationRegistry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read application registry %s: %w", path, err)
+	}
+	var registry ApplicationRegistry
+	if err := yaml.Unmarshal(data, &registry); err != nil {
+		return nil, fmt.Errorf("failed to parse application registry %s: %w", path, err)
+	}
+	if err := validate.Struct(&registry); err != nil {
+		return nil, fmt.Errorf("invalid application registry %s: %w", path, err)
+	}
+	if registry.path, err = filepath.Abs(path); err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	return &registry, nil
+}
+
+// FindApplicationRegistry returns the nearest application registry in the directory
+// of a test file or its parents up to the repository root, or nil
+func FindApplicationRegistry(testFile string) (*ApplicationRegistry, error) {
+	dir, err := filepath.Abs(filepath.Dir(testFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	for {
+		path := filepath.Join(dir, ApplicationRegistryFileName)
+		if _, err := os.Stat(path); err == nil {
+			return LoadApplicationRegistry(path)
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return nil, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// Path returns the path of the registry file
+func (r *ApplicationRegistry) Path() string {
+	return r.path
+}
+
+// Resolve returns the application location of an alias, as written in a test
+// definition: a git URL with its ref and path, or an absolute local path
+func (r *ApplicationRegistry) Resolve(alias string) (string, *RegisteredApplication, error) {
+	app, ok := r.Applications[alias]
+	if !ok {
+		names := make([]string, 0, len(r.Applications))
+		for name := range r.Applications {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", nil, fmt.Errorf("unknown application alias %q in %s (known: %s)", alias, r.path, strings.Join(names, ", "))
+	}
+	if app.Requires != nil {
+		for _, name := range app.Requires.Env {
+			if os.Getenv(name) == "" {
+				return "", nil, fmt.Errorf("application %s requires environment variable %s", alias, name)
+			}
+		}
+	}
+	url, err := ExpandEnv(app.URL)
+	if err != nil {
+		return "", nil, fmt.Errorf("application %s: %w", alias, err)
+	}
+
+	if !IsGitURL(url) {
+		if !filepath.IsAbs(url) {
+			url = filepath.Join(filepath.Dir(r.path), url)
+		}
+		return filepath.Join(url, app.Path), &app, nil
+	}
+	if app.Ref == "" && app.Path == "" {
+		return url, &app, nil
+	}
+	location := url + "#" + app.Ref
+	if app.Path != "" {
+		location += "/" + app.Path
+	}
+	return location, &app, nil
+}
+
+// resolveApplicationAlias replaces the application aliases of a test definition
+// with the applications of the registry found from the test file
+func (t *TestDefinition) resolveApplicationAlias(testFile string) error {
+	var registry *ApplicationRegistry
+	resolve := func(application string) (string, string, error) {
+		alias, ok := strings.CutPrefix(application, ApplicationAliasPrefix)
+		if !ok {
+			return application, "", nil
+		}
+		if registry == nil {
+			var err error
+			if registry, err = FindApplicationRegistry(testFile); err != nil {
+				return "", "", err
+			}
+			if registry == nil {
+				return "", "", fmt.Errorf("application %s needs an %s registry in the test directory or a parent", application, ApplicationRegistryFileName)
+			}
+		}
+		location, app, err := registry.Resolve(alias)
+		if err != nil {
+			return "", "", err
+		}
+		if app.Requires != nil && app.Requires.MavenSettings {
+			t.RequireMavenSettings = true
+		}
+		return location, alias, nil
+	}
+
+	var err error
+	if t.Analysis.Application, t.Analysis.ApplicationAlias, err = resolve(t.Analysis.Application); err != nil {
+		return err
+	}
+	for i, app := range t.Analysis.Applications {
+		if t.Analysis.Applications[i].Application, t.Analysis.Applications[i].Alias, err = resolve(app.Application); err != nil {
+			return err
+		}
+	}
+	return nil
+}
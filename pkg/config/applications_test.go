@@ -0,0 +1,102 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplicationAliases(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"applications.yaml": `applications:
+  tackle-testapp:
+    url: https://github.com/konveyor/tackle-testapp
+    ref: main
+    requires:
+      mavenSettings: true
+  book-server:
+    url: https://github.com/konveyor/book-server.git
+  coolstore:
+    url: https://github.com/konveyor-ecosystem/coolstore
+    ref: v1.0
+    path: monolith
+  local-app:
+    url: apps/local
+  private-app:
+    url: https://github.com/konveyor/private
+    requires:
+      env: [KONCUR_TEST_PRIVATE_APP_TOKEN]
+`,
+		"java/test.yaml": "name: t\nanalysis:\n  application: alias:tackle-testapp\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	registry, err := FindApplicationRegistry(filepath.Join(dir, "java", "test.yaml"))
+	if err != nil || registry == nil {
+		t.Fatalf("FindApplicationRegistry() = %v, %v", registry, err)
+	}
+	tests := []struct {
+		alias   string
+		want    string
+		wantErr string
+	}{
+		{alias: "tackle-testapp", want: "https://github.com/konveyor/tackle-testapp#main"},
+		{alias: "book-server", want: "https://github.com/konveyor/book-server.git"},
+		{alias: "coolstore", want: "https://github.com/konveyor-ecosystem/coolstore#v1.0/monolith"},
+		{alias: "local-app", want: filepath.Join(dir, "apps", "local")},
+		{alias: "private-app", wantErr: "requires environment variable KONCUR_TEST_PRIVATE_APP_TOKEN"},
+		{alias: "missing", wantErr: "known: book-server, coolstore, local-app, private-app, tackle-testapp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			got, _, err := registry.Resolve(tt.alias)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Resolve() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	test, err := LoadWithOptions(filepath.Join(dir, "java", "test.yaml"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if test.Analysis.ApplicationAlias != "tackle-testapp" || !test.RequireMavenSettings {
+		t.Errorf("expected the alias and its maven settings requirement, got %q and %v", test.Analysis.ApplicationAlias, test.RequireMavenSettings)
+	}
+	if c := test.Analysis.ApplicationGitComponents; c == nil || c.URL != "https://github.com/konveyor/tackle-testapp" || c.Ref != "main" {
+		t.Errorf("expected the registry URL and ref, got %+v", c)
+	}
+}
+
+func TestApplicationAliasWithoutRegistry(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	testFile := filepath.Join(dir, "test.yaml")
+	if err := os.WriteFile(testFile, []byte("name: t\nanalysis:\n  application: alias:book-server\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadWithOptions(testFile, true); err == nil || !strings.Contains(err.Error(), "needs an applications.yaml registry") {
+		t.Errorf("expected a missing registry error, got %v", err)
+	}
+}
@@ -0,0 +1,283 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ExitCodes are the exit codes a test accepts, written as a code, a range ("1-3"),
+// a symbolic name (see ExitCodeNames) or a list of them, e.g. [0, violations].
+// Without exit codes, 0 is expected.
+type ExitCodes []ExitCodeRange
+
+// ExitCodeRange is a range of exit codes, with its name when written as one.
+// Exit statuses have their name only.
+type ExitCodeRange struct {
+	Name   string
+	Min    int
+	Max    int
+	Status bool
+}
+
+// ExitCodeNames are the symbolic exit codes
+var ExitCodeNames = map[string]ExitCodeRange{
+	"success":    {Min: 0, Max: 0},
+	"error":      {Min: 1, Max: 1},
+	"violations": {Min: 3, Max: 3},
+	"nonzero":    {Min: 1, Max: 255},
+}
+
+// Exit statuses are outcomes of analyses that tests can expect instead of exit codes,
+// portable across tools and their versions. Targets map their exit codes onto them.
+const (
+	StatusSuccessClean        = "success-clean"
+	StatusSuccessWithFindings = "success-with-findings"
+	StatusExecutionError      = "execution-error"
+)
+
+// ExitStatuses are the exit statuses
+var ExitStatuses = []string{StatusSuccessClean, StatusSuccessWithFindings, StatusExecutionError}
+
+// ExitCodeMapping translates the exit codes of a target into exit statuses.
+// Exit codes that are neither successes nor findings are execution errors.
+type ExitCodeMapping struct {
+	// Success are the exit codes of successful analyses (default: 0); whether
+	// they found violations is read from their output
+	Success ExitCodes `yaml:"success,omitempty"`
+	// Findings are the exit codes of successful analyses that found violations,
+	// for tools signaling them with their exit code
+	Findings ExitCodes `yaml:"findings,omitempty"`
+}
+
+// Status returns the exit status of an exit code, and whether the output of the
+// analysis has violations. A nil mapping only treats 0 as success.
+func (m *ExitCodeMapping) Status(code int, findings bool) string {
+	var success ExitCodes
+	if m != nil {
+		if len(m.Findings) > 0 && m.Findings.matchesCode(code) {
+			return StatusSuccessWithFindings
+		}
+		success = m.Success
+	}
+	switch {
+	case !success.matchesCode(code):
+		return StatusExecutionError
+	case findings:
+		return StatusSuccessWithFindings
+	}
+	return StatusSuccessClean
+}
+
+// ExitCode accepts a single exit code
+func ExitCode(code int) ExitCodes {
+	return ExitCodes{{Min: code, Max: code}}
+}
+
+// ParseExitCodes parses exit codes written as in a test definition, separated by
+// commas or "or"
+func ParseExitCodes(s string) (ExitCodes, error) {
+	var codes ExitCodes
+	for _, field := range strings.FieldsFunc(strings.ReplaceAll(s, " or ", ","), func(r rune) bool { return r == ',' }) {
+		code, err := parseExitCodeRange(strings.TrimSpace(field))
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+func parseExitCodeRange(s string) (ExitCodeRange, error) {
+	if slices.Contains(ExitStatuses, s) {
+		return ExitCodeRange{Name: s, Status: true}, nil
+	}
+	if named, ok := ExitCodeNames[s]; ok {
+		named.Name = s
+		return named, nil
+	}
+	if code, err := strconv.Atoi(s); err == nil {
+		return ExitCodeRange{Min: code, Max: code}, nil
+	}
+	if lo, hi, ok := strings.Cut(s, "-"); ok {
+		min, minErr := strconv.Atoi(strings.TrimSpace(lo))
+		max, maxErr := strconv.Atoi(strings.TrimSpace(hi))
+		if minErr == nil && maxErr == nil && min <= max {
+			return ExitCodeRange{Min: min, Max: max}, nil
+		}
+	}
+	return ExitCodeRange{}, fmt.Errorf("invalid exit code %q: must be a code, a range (1-3) or one of %s", s, strings.Join(exitCodeNames(), ", "))
+}
+
+func exitCodeNames() []string {
+	names := make([]string, 0, len(ExitCodeNames)+len(ExitStatuses))
+	for name := range ExitCodeNames {
+		names = append(names, name)
+	}
+	names = append(names, ExitStatuses...)
+	sort.Strings(names)
+	return names
+}
+
+// Matches reports whether an exit code is accepted. Exit statuses are matched
+// as if 0 was the only success, with or without findings.
+func (c ExitCodes) Matches(code int) bool {
+	var defaults *ExitCodeMapping
+	return c.MatchesStatus(code, defaults.Status(code, false)) || c.MatchesStatus(code, defaults.Status(code, true))
+}
+
+// MatchesStatus reports whether an exit code, or the exit status it maps to, is accepted
+func (c ExitCodes) MatchesStatus(code int, status string) bool {
+	for _, r := range c {
+		if r.Status && r.Name == status {
+			return true
+		}
+	}
+	return c.matchesCode(code)
+}
+
+// matchesCode reports whether an exit code is accepted, ignoring exit statuses
+func (c ExitCodes) matchesCode(code int) bool {
+	if len(c) == 0 {
+		return code == 0
+	}
+	for _, r := range c {
+		if !r.Status && code >= r.Min && code <= r.Max {
+			return true
+		}
+	}
+	return false
+}
+
+// HasStatus reports whether an exit status is accepted, which requires mapping
+// exit codes to compare them
+func (c ExitCodes) HasStatus() bool {
+	return slices.ContainsFunc(c, func(r ExitCodeRange) bool { return r.Status })
+}
+
+// Accepting returns the exit codes if they accept code, or code alone
+func (c ExitCodes) Accepting(code int) ExitCodes {
+	if c.Matches(code) {
+		return c
+	}
+	return ExitCode(code)
+}
+
+// single returns the exit code when exactly one unnamed code is accepted
+func (c ExitCodes) single() (int, bool) {
+	if len(c) == 0 {
+		return 0, true
+	}
+	if len(c) == 1 && c[0].Name == "" && c[0].Min == c[0].Max {
+		return c[0].Min, true
+	}
+	return 0, false
+}
+
+func (r ExitCodeRange) String() string {
+	switch {
+	case r.Name != "":
+		return r.Name
+	case r.Min == r.Max:
+		return strconv.Itoa(r.Min)
+	}
+	return fmt.Sprintf("%d-%d", r.Min, r.Max)
+}
+
+// String describes the exit codes, e.g. "0 or violations"
+func (c ExitCodes) String() string {
+	if len(c) == 0 {
+		return "0"
+	}
+	parts := make([]string, len(c))
+	for i, r := range c {
+		parts[i] = r.String()
+	}
+	return strings.Join(parts, " or ")
+}
+
+// UnmarshalYAML reads a code, range or name, or a list of them. It has the legacy
+// signature, results are also read with yaml.v2.
+func (c *ExitCodes) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var values []string
+	if err := unmarshal(&values); err != nil {
+		var value string
+		if err := unmarshal(&value); err != nil {
+			return fmt.Errorf("exit code must be a code, a range, a name or a list of them")
+		}
+		values = []string{value}
+	}
+	var codes ExitCodes
+	for _, value := range values {
+		code, err := parseExitCodeRange(value)
+		if err != nil {
+			return err
+		}
+		codes = append(codes, code)
+	}
+	*c = codes
+	return nil
+}
+
+// MarshalYAML writes a single code as a number, and other exit codes as a list
+func (c ExitCodes) MarshalYAML() (interface{}, error) {
+	if code, ok := c.single(); ok {
+		return code, nil
+	}
+	// Plain values, results are also written with yaml.v2
+	list := make([]interface{}, len(c))
+	for i, r := range c {
+		if r.Name == "" && r.Min == r.Max {
+			list[i] = r.Min
+		} else {
+			list[i] = r.String()
+		}
+	}
+	return list, nil
+}
+
+// MarshalJSON writes a single code as a number, like results of earlier versions,
+// and other exit codes as their description
+func (c ExitCodes) MarshalJSON() ([]byte, error) {
+	if code, ok := c.single(); ok {
+		return json.Marshal(code)
+	}
+	return json.Marshal(c.String())
+}
+
+// UnmarshalJSON reads a code or a description of exit codes
+func (c *ExitCodes) UnmarshalJSON(data []byte) error {
+	var code int
+	if err := json.Unmarshal(data, &code); err == nil {
+		*c = ExitCode(code)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	codes, err := ParseExitCodes(s)
+	if err != nil {
+		return err
+	}
+	*c = codes
+	return nil
+}
+
+// MarshalText describes the exit codes, in XML reports
+func (c ExitCodes) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// JSONSchema describes exit codes, ranges and names, alone or in a list
+func (ExitCodes) JSONSchema() *Schema {
+	code := &Schema{AnyOf: []*Schema{
+		{Type: "integer"},
+		{Type: "string", Pattern: `^[0-9]+-[0-9]+$`},
+		{Type: "string", Enum: exitCodeNames()},
+	}}
+	return &Schema{AnyOf: []*Schema{code, {Type: "array", Items: code}}}
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestExitCodes(t *testing.T) {
+	tests := []struct {
+		yaml    string
+		want    string
+		matches []int
+		rejects []int
+		wantErr bool
+	}{
+		{yaml: "", want: "0", matches: []int{0}, rejects: []int{1}},
+		{yaml: "exitCode: 3", want: "3", matches: []int{3}, rejects: []int{0}},
+		{yaml: "exitCode: [0, 3]", want: "0 or 3", matches: []int{0, 3}, rejects: []int{1, 2}},
+		{yaml: "exitCode: 1-3", want: "1-3", matches: []int{1, 2, 3}, rejects: []int{0, 4}},
+		{yaml: "exitCode: [success, violations]", want: "success or violations", matches: []int{0, 3}, rejects: []int{1}},
+		{yaml: "exitCode: nonzero", want: "nonzero", matches: []int{1, 255}, rejects: []int{0}},
+		{yaml: "exitCode: sometimes", wantErr: true},
+		{yaml: "exitCode: 3-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.yaml, func(t *testing.T) {
+			var expect struct {
+				ExitCode ExitCodes `yaml:"exitCode"`
+			}
+			err := yaml.Unmarshal([]byte(tt.yaml), &expect)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := expect.ExitCode.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			for _, code := range tt.matches {
+				if !expect.ExitCode.Matches(code) {
+					t.Errorf("expected %d to match", code)
+				}
+			}
+			for _, code := range tt.rejects {
+				if expect.ExitCode.Matches(code) {
+					t.Errorf("expected %d not to match", code)
+				}
+			}
+
+			// Results round-trip through JSON
+			data, err := json.Marshal(expect.ExitCode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded ExitCodes
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("UnmarshalJSON(%s) error = %v", data, err)
+			}
+			if !decoded.Matches(tt.matches[0]) || decoded.String() != tt.want {
+				t.Errorf("JSON round trip = %v, want %s", decoded, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitCodes_Accepting(t *testing.T) {
+	codes := ExitCodes{{Min: 0, Max: 0}, {Name: "violations", Min: 3, Max: 3}}
+	if got := codes.Accepting(3); !reflect.DeepEqual(got, codes) {
+		t.Errorf("expected accepted codes to be kept, got %v", got)
+	}
+	if got := codes.Accepting(1); !reflect.DeepEqual(got, ExitCode(1)) {
+		t.Errorf("expected the actual code, got %v", got)
+	}
+
+	out, err := yaml.Marshal(map[string]ExitCodes{"single": ExitCode(0), "list": codes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "list:\n    - 0\n    - violations\nsingle: 0\n"; string(out) != want {
+		t.Errorf("Marshal() = %q, want %q", out, want)
+	}
+}
+
+func TestExitCodeMapping_Status(t *testing.T) {
+	tests := []struct {
+		name     string
+		mapping  *ExitCodeMapping
+		code     int
+		findings bool
+		want     string
+	}{
+		{name: "default, clean", code: 0, want: StatusSuccessClean},
+		{name: "default, findings", code: 0, findings: true, want: StatusSuccessWithFindings},
+		{name: "default, failure", code: 1, findings: true, want: StatusExecutionError},
+		{name: "findings code", mapping: &ExitCodeMapping{Findings: ExitCode(3)}, code: 3, want: StatusSuccessWithFindings},
+		{name: "findings code, clean", mapping: &ExitCodeMapping{Findings: ExitCode(3)}, code: 0, want: StatusSuccessClean},
+		{name: "success codes", mapping: &ExitCodeMapping{Success: ExitCodes{{Min: 0, Max: 1}}}, code: 1, want: StatusSuccessClean},
+		{name: "unmapped code", mapping: &ExitCodeMapping{Findings: ExitCode(3)}, code: 2, want: StatusExecutionError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.mapping.Status(tt.code, tt.findings); got != tt.want {
+				t.Errorf("Status(%d, %v) = %s, want %s", tt.code, tt.findings, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitCodes_Statuses(t *testing.T) {
+	var expect struct {
+		ExitCode ExitCodes `yaml:"exitCode"`
+	}
+	if err := yaml.Unmarshal([]byte("exitCode: [success-with-findings, 2]"), &expect); err != nil {
+		t.Fatal(err)
+	}
+	codes := expect.ExitCode
+	if !codes.HasStatus() || ExitCode(0).HasStatus() {
+		t.Errorf("HasStatus() did not tell exit statuses from exit codes")
+	}
+	if got := codes.String(); got != "success-with-findings or 2" {
+		t.Errorf("String() = %q", got)
+	}
+	if !codes.MatchesStatus(3, StatusSuccessWithFindings) || !codes.MatchesStatus(2, StatusExecutionError) {
+		t.Errorf("expected the status or exit code to match")
+	}
+	if codes.MatchesStatus(0, StatusSuccessClean) {
+		t.Errorf("expected success-clean not to match")
+	}
+	// Without a mapping, only 0 is a success
+	if !codes.Matches(0) || codes.Matches(3) {
+		t.Errorf("expected 0 to match and 3 not to")
+	}
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"go.lsp.dev/uri"
+)
+
+// expectedOutputVariable matches the ${NAME} variables of expected incident URIs and
+// messages. Only the names of ExpectedOutputVariables are replaced, so placeholders
+// of the analyzed code, such as Spring's ${server.port}, are kept.
+var expectedOutputVariable = regexp.MustCompile(`\$\{([A-Z_]+)\}`)
+
+// ExpectedOutputVariables returns the variables expected output can use, so
+// baselines hold no machine-specific paths:
+//   - APP_DIR: the directory the application is analyzed from (its clone for git URLs)
+//   - TEST_DIR: the directory of the test file
+//   - TEST_NAME: the name of the test
+//   - HOME: the home directory of the user
+func (t *TestDefinition) ExpectedOutputVariables() map[string]string {
+	testDir := t.GetTestDir()
+	if abs, err := filepath.Abs(testDir); err == nil && testDir != "" {
+		testDir = abs
+	}
+	vars := map[string]string{
+		"TEST_DIR":  testDir,
+		"TEST_NAME": t.Name,
+	}
+	switch app := t.Analysis.Application; {
+	case t.Analysis.ApplicationGitComponents != nil:
+		// Targets clone git applications into the test directory
+		vars["APP_DIR"] = filepath.Join(testDir, t.Analysis.GetSourceDir(), t.Analysis.ApplicationGitComponents.Path)
+	case app != "" && !filepath.IsAbs(app):
+		vars["APP_DIR"] = filepath.Join(testDir, app)
+	default:
+		vars["APP_DIR"] = app
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		vars["HOME"] = home
+	}
+	return vars
+}
+
+// ExpandExpectedOutput returns a copy of expected rulesets with the variables in
+// incident URIs and messages replaced. The rulesets themselves are not changed, so
+// updated baselines keep their variables.
+func ExpandExpectedOutput(rulesets []konveyor.RuleSet, vars map[string]string) []konveyor.RuleSet {
+	expanded := make([]konveyor.RuleSet, len(rulesets))
+	for i, rs := range rulesets {
+		rs.Violations = expandViolations(rs.Violations, vars)
+		rs.Insights = expandViolations(rs.Insights, vars)
+		expanded[i] = rs
+	}
+	return expanded
+}
+
+func expandViolations(violations map[string]konveyor.Violation, vars map[string]string) map[string]konveyor.Violation {
+	if violations == nil {
+		return nil
+	}
+	expanded := make(map[string]konveyor.Violation, len(violations))
+	for id, v := range violations {
+		incidents := make([]konveyor.Incident, len(v.Incidents))
+		for i, incident := range v.Incidents {
+			incident.URI = uri.URI(expandExpectedVariables(string(incident.URI), vars))
+			incident.Message = expandExpectedVariables(incident.Message, vars)
+			incidents[i] = incident
+		}
+		if v.Incidents == nil {
+			incidents = nil
+		}
+		v.Incidents = incidents
+		expanded[id] = v
+	}
+	return expanded
+}
+
+func expandExpectedVariables(s string, vars map[string]string) string {
+	return expectedOutputVariable.ReplaceAllStringFunc(s, func(ref string) string {
+		if value, ok := vars[ref[2:len(ref)-1]]; ok {
+			return value
+		}
+		return ref
+	})
+}
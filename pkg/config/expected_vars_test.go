@@ -0,0 +1,68 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+func TestExpectedOutputVariables(t *testing.T) {
+	testFile := filepath.Join("/tests", "basic", "test.yaml")
+	tests := []struct {
+		name     string
+		analysis AnalysisConfig
+		want     string
+	}{
+		{"local application", AnalysisConfig{Application: "./app"}, "/tests/basic/app"},
+		{"absolute application", AnalysisConfig{Application: "/srv/app"}, "/srv/app"},
+		{"git application", AnalysisConfig{
+			Application:              "https://github.com/konveyor/example#main/web",
+			ApplicationGitComponents: &GitURLComponents{URL: "https://github.com/konveyor/example", Ref: "main", Path: "web"},
+		}, "/tests/basic/source/web"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := &TestDefinition{Name: "basic", Analysis: tt.analysis}
+			test.SetTestFilePath(testFile)
+			vars := test.ExpectedOutputVariables()
+			if vars["APP_DIR"] != tt.want {
+				t.Errorf("APP_DIR = %q, want %q", vars["APP_DIR"], tt.want)
+			}
+			if vars["TEST_DIR"] != "/tests/basic" || vars["TEST_NAME"] != "basic" {
+				t.Errorf("unexpected TEST_DIR %q or TEST_NAME %q", vars["TEST_DIR"], vars["TEST_NAME"])
+			}
+		})
+	}
+}
+
+func TestExpandExpectedOutput(t *testing.T) {
+	vars := map[string]string{"APP_DIR": "/work/source", "TEST_NAME": "basic", "HOME": "/home/ci"}
+	expected := []konveyor.RuleSet{{
+		Name: "rs",
+		Violations: map[string]konveyor.Violation{
+			"rule-001": {Incidents: []konveyor.Incident{{
+				URI:     "file://${APP_DIR}/src/App.java",
+				Message: "${TEST_NAME} reads ${HOME}/.m2 and ${server.port}, not ${UNKNOWN}",
+			}}},
+		},
+		Insights: map[string]konveyor.Violation{
+			"info-001": {Incidents: []konveyor.Incident{{URI: "file://${APP_DIR}/pom.xml"}}},
+		},
+	}}
+
+	got := ExpandExpectedOutput(expected, vars)
+	incident := got[0].Violations["rule-001"].Incidents[0]
+	if incident.URI != "file:///work/source/src/App.java" {
+		t.Errorf("URI = %q", incident.URI)
+	}
+	if want := "basic reads /home/ci/.m2 and ${server.port}, not ${UNKNOWN}"; incident.Message != want {
+		t.Errorf("Message = %q, want %q", incident.Message, want)
+	}
+	if uri := got[0].Insights["info-001"].Incidents[0].URI; uri != "file:///work/source/pom.xml" {
+		t.Errorf("insight URI = %q", uri)
+	}
+	if uri := expected[0].Violations["rule-001"].Incidents[0].URI; uri != "file://${APP_DIR}/src/App.java" {
+		t.Errorf("expected output was changed: %q", uri)
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// applyExtends merges the base file a YAML mapping extends into it, and the files
+// that one extends in turn. Values set by the mapping win, except top-level tags,
+// which are combined. It returns the absolute paths of the base files.
+func applyExtends(node *yaml.Node, path string) ([]string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	return extend(node, []string{abs})
+}
+
+func extend(node *yaml.Node, chain []string) ([]string, error) {
+	ref := mappingValue(node, "extends")
+	if ref == nil {
+		return nil, nil
+	}
+	if ref.Kind != yaml.ScalarNode || ref.Value == "" {
+		return nil, fmt.Errorf("line %d: extends must be the path of a base file", ref.Line)
+	}
+	path := chain[len(chain)-1]
+	base := ref.Value
+	if !filepath.IsAbs(base) {
+		base = filepath.Join(filepath.Dir(path), base)
+	}
+	if slices.Contains(chain, base) {
+		return nil, fmt.Errorf("%s extends itself: %s", path, strings.Join(append(chain, base), " -> "))
+	}
+
+	data, err := os.ReadFile(base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read base file %s: %w", ref.Value, err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse base file %s: %w", base, err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("base file %s must be a mapping", base)
+	}
+	bases, err := extend(doc.Content[0], append(chain, base))
+	if err != nil {
+		return nil, err
+	}
+	mergeDefaults(doc.Content[0], node, true)
+	return append([]string{base}, bases...), nil
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExtends(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"bases/common.yaml": `tags: [java]
+timeout: 20m
+analysis:
+  application: https://github.com/example/app.git
+  analysisMode: source-only
+`,
+		"bases/app.yaml": `extends: common.yaml
+tags: [app]
+analysis:
+  analysisMode: full
+  labelSelector: konveyor.io/target=quarkus
+`,
+		"quarkus/test.yaml": `name: quarkus
+extends: ../bases/app.yaml
+tags: [tier0]
+analysis:
+  labelSelector: konveyor.io/target=eap8
+expect:
+  exitCode: 0
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	test, err := LoadWithOptions(filepath.Join(dir, "quarkus", "test.yaml"), true)
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if test.Analysis.Application != "https://github.com/example/app.git" || test.Analysis.AnalysisMode != "full" {
+		t.Errorf("expected the application of common.yaml and the mode of app.yaml, got %+v", test.Analysis)
+	}
+	if test.Analysis.LabelSelector != "konveyor.io/target=eap8" {
+		t.Errorf("expected the test's label selector to win, got %q", test.Analysis.LabelSelector)
+	}
+	if want := []string{"java", "app", "tier0"}; !reflect.DeepEqual(test.Tags, want) {
+		t.Errorf("tags = %v, want %v", test.Tags, want)
+	}
+	if want := []string{filepath.Join(dir, "bases", "app.yaml"), filepath.Join(dir, "bases", "common.yaml")}; !reflect.DeepEqual(test.BaseFiles(), want) {
+		t.Errorf("BaseFiles() = %v, want %v", test.BaseFiles(), want)
+	}
+}
+
+func TestExtends_Invalid(t *testing.T) {
+	tests := map[string]map[string]string{
+		"missing base": {"test.yaml": "name: t\nextends: base.yaml\n"},
+		"cycle": {
+			"test.yaml": "name: t\nextends: a.yaml\n",
+			"a.yaml":    "extends: b.yaml\n",
+			"b.yaml":    "extends: a.yaml\n",
+		},
+		"not a path": {"test.yaml": "name: t\nextends: [a.yaml]\n"},
+		"base list":  {"test.yaml": "name: t\nextends: a.yaml\n", "a.yaml": "- x\n"},
+	}
+	for name, files := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			for file, content := range files {
+				if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, err := LoadWithOptions(filepath.Join(dir, "test.yaml"), true); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestLoadTargetConfig_Extends(t *testing.T) {
+	dir := t.TempDir()
+	base := "type: tackle-hub\ntackleHub:\n  url: https://hub.example.com\n  mavenSettings: settings.xml\n"
+	config := "extends: base.yaml\ntackleHub:\n  url: https://staging.example.com\n"
+	if err := os.WriteFile(filepath.Join(dir, "base.yaml"), []byte(base), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "staging.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	targetConfig, err := LoadTargetConfig(filepath.Join(dir, "staging.yaml"))
+	if err != nil {
+		t.Fatalf("LoadTargetConfig() error = %v", err)
+	}
+	if targetConfig.Type != "tackle-hub" || targetConfig.TackleHub.URL != "https://staging.example.com" || targetConfig.TackleHub.MavenSettings != "settings.xml" {
+		t.Errorf("unexpected target config %+v %+v", targetConfig, targetConfig.TackleHub)
+	}
+}
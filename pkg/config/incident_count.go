@@ -0,0 +1,133 @@
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IncidentCount is a count-only expectation for a violation or insight.
+// It is written next to the violation in the expected output:
+//
+//	violations:
+//	  rule-001:
+//	    description: ...
+//	    incidentCount: 12
+//	    incidentCountTolerance: 10%
+//
+// When set, the violation's incidents are not compared one by one.
+type IncidentCount struct {
+	Count     int
+	Tolerance Tolerance
+}
+
+// Allows reports whether an actual number of incidents satisfies the expectation
+func (c IncidentCount) Allows(actual int) bool {
+	return c.Tolerance.Allows(c.Count, actual)
+}
+
+// IncidentCounts maps ruleset name to rule ID to a count-only expectation
+type IncidentCounts map[string]map[string]IncidentCount
+
+// Get returns the count expectation for a rule of a ruleset, if any
+func (c IncidentCounts) Get(ruleset, ruleID string) (IncidentCount, bool) {
+	count, ok := c[ruleset][ruleID]
+	return count, ok
+}
+
+// incidentCountRuleSet picks the count-only fields out of an expected ruleset,
+// which konveyor.RuleSet does not know about
+type incidentCountRuleSet struct {
+	Name       string                        `yaml:"name"`
+	Violations map[string]incidentCountEntry `yaml:"violations"`
+	Insights   map[string]incidentCountEntry `yaml:"insights"`
+}
+
+type incidentCountEntry struct {
+	IncidentCount          *int      `yaml:"incidentCount"`
+	IncidentCountTolerance Tolerance `yaml:"incidentCountTolerance"`
+}
+
+// collectIncidentCounts extracts count-only expectations from expected rulesets
+func collectIncidentCounts(rulesets []incidentCountRuleSet) (IncidentCounts, error) {
+	counts := IncidentCounts{}
+	for _, rs := range rulesets {
+		for _, entries := range []map[string]incidentCountEntry{rs.Violations, rs.Insights} {
+			for ruleID, entry := range entries {
+				if entry.IncidentCount == nil {
+					continue
+				}
+				if *entry.IncidentCount < 0 {
+					return nil, fmt.Errorf("%s/%s: incidentCount must not be negative", rs.Name, ruleID)
+				}
+				if counts[rs.Name] == nil {
+					counts[rs.Name] = map[string]IncidentCount{}
+				}
+				counts[rs.Name][ruleID] = IncidentCount{Count: *entry.IncidentCount, Tolerance: entry.IncidentCountTolerance}
+			}
+		}
+	}
+	if len(counts) == 0 {
+		return nil, nil
+	}
+	return counts, nil
+}
+
+// parseIncidentCounts reads count-only expectations from an expected output file
+func parseIncidentCounts(data []byte) (IncidentCounts, error) {
+	var rulesets []incidentCountRuleSet
+	if err := yaml.Unmarshal(data, &rulesets); err != nil {
+		return nil, fmt.Errorf("failed to parse incident counts: %w", err)
+	}
+	return collectIncidentCounts(rulesets)
+}
+
+// inlineIncidentCounts sets the count-only expectations of a test's inline expected
+// results, read from its merged definition (with extends, suite and variant applied)
+func inlineIncidentCounts(node *yaml.Node, expect *ExpectConfig) error {
+	type inlineOutput struct {
+		Result []incidentCountRuleSet `yaml:"result"`
+	}
+	var test struct {
+		Expect struct {
+			Output       inlineOutput            `yaml:"output"`
+			Applications map[string]inlineOutput `yaml:"applications"`
+			Providers    struct {
+				Output map[string]inlineOutput `yaml:"output"`
+			} `yaml:"providers"`
+		} `yaml:"expect"`
+	}
+	if err := node.Decode(&test); err != nil {
+		return fmt.Errorf("failed to parse incident counts: %w", err)
+	}
+
+	counts, err := collectIncidentCounts(test.Expect.Output.Result)
+	if err != nil {
+		return err
+	}
+	expect.Output.IncidentCounts = counts
+	for name, inline := range test.Expect.Applications {
+		output, ok := expect.Applications[name]
+		if !ok {
+			continue
+		}
+		if output.IncidentCounts, err = collectIncidentCounts(inline.Result); err != nil {
+			return fmt.Errorf("application %s: %w", name, err)
+		}
+		expect.Applications[name] = output
+	}
+	for name, inline := range test.Expect.Providers.Output {
+		if expect.Providers == nil {
+			break
+		}
+		output, ok := expect.Providers.Output[name]
+		if !ok {
+			continue
+		}
+		if output.IncidentCounts, err = collectIncidentCounts(inline.Result); err != nil {
+			return fmt.Errorf("provider %s: %w", name, err)
+		}
+		expect.Providers.Output[name] = output
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTolerance(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected int
+		actual   int
+		allows   bool
+		wantErr  bool
+	}{
+		{in: "0", expected: 10, actual: 10, allows: true},
+		{in: "0", expected: 10, actual: 11, allows: false},
+		{in: "2", expected: 10, actual: 8, allows: true},
+		{in: "2", expected: 10, actual: 13, allows: false},
+		{in: "10%", expected: 50, actual: 55, allows: true},
+		{in: "10%", expected: 50, actual: 56, allows: false},
+		{in: "-1", wantErr: true},
+		{in: "lots", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			tol, err := ParseTolerance(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTolerance(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := tol.Allows(tt.expected, tt.actual); got != tt.allows {
+				t.Errorf("Allows(%d, %d) = %v, want %v", tt.expected, tt.actual, got, tt.allows)
+			}
+		})
+	}
+}
+
+func TestLoadIncidentCounts(t *testing.T) {
+	dir := t.TempDir()
+	expected := `- name: ruleset-a
+  violations:
+    rule-001:
+      description: counted
+      incidentCount: 12
+      incidentCountTolerance: 10%
+    rule-002:
+      description: listed
+      incidents:
+        - uri: file:///source/a.java
+          message: m
+`
+	if err := os.WriteFile(filepath.Join(dir, "expected.yaml"), []byte(expected), 0644); err != nil {
+		t.Fatal(err)
+	}
+	testFile := filepath.Join(dir, "test.yaml")
+	content := `name: counts
+analysis:
+  application: app
+  analysisMode: source-only
+expect:
+  exitCode: 0
+  output:
+    file: expected.yaml
+`
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	test, err := Load(testFile)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	counts := test.Expect.Output.IncidentCounts
+	count, ok := counts.Get("ruleset-a", "rule-001")
+	if !ok {
+		t.Fatalf("expected incident count for rule-001, got %v", counts)
+	}
+	if count.Count != 12 || count.Tolerance.String() != "10%" {
+		t.Errorf("count = %+v, want 12 with 10%% tolerance", count)
+	}
+	if _, ok := counts.Get("ruleset-a", "rule-002"); ok {
+		t.Error("rule-002 lists incidents and should not have a count expectation")
+	}
+}
+
+func TestLoadInlineIncidentCounts(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "test.yaml")
+	content := `name: counts
+analysis:
+  application: app
+  analysisMode: source-only
+expect:
+  exitCode: 0
+  allowedMismatches: 5%
+  output:
+    result:
+      - name: ruleset-a
+        insights:
+          rule-003:
+            description: counted insight
+            incidentCount: 3
+            incidentCountTolerance: 1
+`
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	test, err := Load(testFile)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if test.Expect.AllowedMismatches == nil || test.Expect.AllowedMismatches.String() != "5%" {
+		t.Errorf("AllowedMismatches = %v, want 5%%", test.Expect.AllowedMismatches)
+	}
+	count, ok := test.Expect.Output.IncidentCounts.Get("ruleset-a", "rule-003")
+	if !ok || count.Count != 3 || !count.Allows(4) || count.Allows(5) {
+		t.Errorf("count = %+v (found %v), want 3 with tolerance 1", count, ok)
+	}
+}
+
+func TestLoadInlineIncidentCounts_Merged(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"base.yaml": `expect:
+  output:
+    result:
+      - name: ruleset-a
+        violations:
+          rule-001:
+            description: counted in the base
+            incidentCount: 4
+`,
+		"extends/test.yaml": `name: extends
+extends: ../base.yaml
+analysis:
+  application: app
+  analysisMode: source-only
+`,
+		"applications/test.yaml": `name: applications
+analysis:
+  applications:
+    - name: app
+      application: ./app
+  analysisMode: source-only
+expect:
+  applications:
+    app:
+      result:
+        - name: ruleset-a
+          violations:
+            rule-002:
+              description: counted per application
+              incidentCount: 2
+  providers:
+    output:
+      java:
+        result:
+          - name: ruleset-java
+            violations:
+              rule-003:
+                description: counted per provider
+                incidentCount: 5
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	test, err := Load(filepath.Join(dir, "extends", "test.yaml"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if count, ok := test.Expect.Output.IncidentCounts.Get("ruleset-a", "rule-001"); !ok || count.Count != 4 {
+		t.Errorf("expected the base's incidentCount 4, got %+v (found %v)", count, ok)
+	}
+
+	test, err = Load(filepath.Join(dir, "applications", "test.yaml"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if count, ok := test.Expect.Applications["app"].IncidentCounts.Get("ruleset-a", "rule-002"); !ok || count.Count != 2 {
+		t.Errorf("expected the application's incidentCount 2, got %+v (found %v)", count, ok)
+	}
+	if count, ok := test.Expect.Output.IncidentCounts.Get("ruleset-java", "rule-003"); !ok || count.Count != 5 {
+		t.Errorf("expected the provider's incidentCount 5 in the merged output, got %+v (found %v)", count, ok)
+	}
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envReference matches ${NAME} and ${NAME:-default}, and $${ escaping a literal ${
+var envReference = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// ExpandEnv replaces ${NAME} with the value of an environment variable, and
+// ${NAME:-default} with the default when the variable is unset or empty.
+// $${ is a literal ${. A variable without a default must be set.
+func ExpandEnv(value string) (string, error) {
+	var missing []string
+	expanded := envReference.ReplaceAllStringFunc(value, func(ref string) string {
+		if ref == "$${" {
+			return "${"
+		}
+		match := envReference.FindStringSubmatch(ref)
+		name, hasDefault, def := match[1], match[2] != "", match[3]
+		if v, ok := os.LookupEnv(name); ok && (v != "" || !hasDefault) {
+			return v
+		}
+		if hasDefault {
+			return def
+		}
+		missing = append(missing, name)
+		return ref
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable %s is not set", strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
+
+// expandEnvNode expands environment variables in the scalar values of a YAML
+// tree. Values below the skipped paths (dotted key patterns, e.g.
+// "expect.applications.*.result") are kept as they are.
+func expandEnvNode(node *yaml.Node, keyPath string, skip ...string) error {
+	for _, pattern := range skip {
+		if ok, _ := path.Match(pattern, keyPath); ok {
+			return nil
+		}
+	}
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			if err := expandEnvNode(child, keyPath, skip...); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if keyPath != "" {
+				key = keyPath + "." + key
+			}
+			if err := expandEnvNode(node.Content[i+1], key, skip...); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		if !strings.Contains(node.Value, "${") {
+			return nil
+		}
+		value, err := ExpandEnv(node.Value)
+		if err != nil {
+			return fmt.Errorf("failed to expand %s: %w", keyPath, err)
+		}
+		node.Value = value
+		// Plain values are typed by their expanded value, e.g. a port number
+		if node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+			node.Tag = ""
+		}
+	}
+	return nil
+}
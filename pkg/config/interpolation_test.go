@@ -0,0 +1,146 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("KONCUR_TEST_HUB_URL", "https://hub.example.com")
+	t.Setenv("KONCUR_TEST_EMPTY", "")
+
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "${KONCUR_TEST_HUB_URL}/hub", want: "https://hub.example.com/hub"},
+		{value: "${KONCUR_TEST_UNSET:-http://localhost:8080}", want: "http://localhost:8080"},
+		{value: "${KONCUR_TEST_EMPTY:-fallback}", want: "fallback"},
+		{value: "${KONCUR_TEST_EMPTY}", want: ""},
+		{value: "${KONCUR_TEST_UNSET:-}", want: ""},
+		{value: "$${KONCUR_TEST_HUB_URL} costs $5", want: "${KONCUR_TEST_HUB_URL} costs $5"},
+		{value: "${project.version}", want: "${project.version}"},
+		{value: "${KONCUR_TEST_UNSET}", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ExpandEnv(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExpandEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ExpandEnv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_ExpandsEnv(t *testing.T) {
+	t.Setenv("KONCUR_TEST_APP", "https://github.com/example/app.git")
+	t.Setenv("KONCUR_TEST_PORT", "9000")
+	t.Setenv("KONCUR_TEST_PASSWORD", "interpolated-password")
+	dir := t.TempDir()
+	files := map[string]string{
+		"test.yaml": `name: env
+analysis:
+  application: ${KONCUR_TEST_APP}
+  analysisMode: ${KONCUR_TEST_MODE:-source-only}
+expect:
+  exitCode: 0
+  output:
+    result:
+      - name: rs
+        violations:
+          rule-001:
+            description: Replace ${KONCUR_TEST_UNSET}
+`,
+		"target.yaml": `type: kai-rpc
+kaiRPC:
+  host: ${KONCUR_TEST_HOST:-localhost}
+  port: ${KONCUR_TEST_PORT}
+tackleUI:
+  url: http://localhost
+  username: admin
+  password: "${KONCUR_TEST_PASSWORD}"
+`,
+		"broken.yaml": "type: tackle-hub\ntackleHub:\n  url: ${KONCUR_TEST_UNSET}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	test, err := Load(filepath.Join(dir, "test.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if test.Analysis.Application != "https://github.com/example/app.git" || test.Analysis.AnalysisMode != "source-only" {
+		t.Errorf("unexpected analysis %+v", test.Analysis)
+	}
+	if got := test.Expect.Output.Result[0].Violations["rule-001"].Description; got != "Replace ${KONCUR_TEST_UNSET}" {
+		t.Errorf("expected the inline result to be kept as is, got %q", got)
+	}
+
+	targetConfig, err := LoadTargetConfig(filepath.Join(dir, "target.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if targetConfig.KaiRPC.Host != "localhost" || targetConfig.KaiRPC.Port != 9000 {
+		t.Errorf("unexpected kai config %+v", targetConfig.KaiRPC)
+	}
+	if targetConfig.TackleUI.Password != "interpolated-password" {
+		t.Errorf("unexpected password %q", targetConfig.TackleUI.Password)
+	}
+
+	if _, err := LoadTargetConfig(filepath.Join(dir, "broken.yaml")); err == nil {
+		t.Error("expected an error for an unset variable")
+	}
+}
+
+func TestLoad_KeepsInlineResults(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "test.yaml")
+	content := `name: inline
+analysis:
+  applications:
+    - name: app
+      application: ./app
+  analysisMode: source-only
+expect:
+  applications:
+    app:
+      result:
+        - name: rs
+          violations:
+            rule-001:
+              description: Replace ${KONCUR_TEST_UNSET}
+              incidents:
+                - uri: file://${APP_DIR}/A.java
+  providers:
+    output:
+      java:
+        result:
+          - name: java
+            violations:
+              java-001:
+                description: Java ${KONCUR_TEST_UNSET}
+`
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	test, err := Load(testFile)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	v := test.Expect.Applications["app"].Result[0].Violations["rule-001"]
+	if v.Description != "Replace ${KONCUR_TEST_UNSET}" || string(v.Incidents[0].URI) != "file://${APP_DIR}/A.java" {
+		t.Errorf("expected the application result to be kept as is, got %+v", v)
+	}
+	if got := test.Expect.Providers.Output["java"].Result[0].Violations["java-001"].Description; got != "Java ${KONCUR_TEST_UNSET}" {
+		t.Errorf("expected the provider result to be kept as is, got %q", got)
+	}
+}
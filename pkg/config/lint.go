@@ -0,0 +1,293 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// LintIssue is an authoring mistake found in an expected output
+type LintIssue struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s", i.File, i.Line, i.Path, i.Message)
+}
+
+// Fields accepted at each level of an expected output. incidentCount and
+// incidentCountTolerance are koncur's count-only expectations.
+var (
+	lintRuleSetFields   = fieldSet("name", "description", "tags", "violations", "insights", "errors", "unmatched", "skipped")
+	lintViolationFields = fieldSet("description", "category", "labels", "incidents", "links", "extras", "effort", "incidentCount", "incidentCountTolerance")
+	lintIncidentFields  = fieldSet("uri", "message", "codeSnip", "lineNumber", "variables")
+	lintLinkFields      = fieldSet("url", "title")
+	lintCategories      = fieldSet(string(konveyor.Mandatory), string(konveyor.Optional), string(konveyor.Potential))
+)
+
+func fieldSet(fields ...string) map[string]bool {
+	set := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		set[f] = true
+	}
+	return set
+}
+
+// LintTestFile lints the inline expected result of a test definition, or of a
+// variant of a test with a matrix, and the expected output files it references
+func LintTestFile(ref string) ([]LintIssue, error) {
+	path, variant := SplitTestRef(ref)
+	root, err := readYAMLNode(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rulesets the test's filter keeps are not reported as dropped
+	var filter *parser.RuleSetFilter
+	if node := mappingValue(mappingValue(root, "validation"), "filter"); node != nil {
+		if err := node.Decode(&filter); err != nil {
+			return nil, fmt.Errorf("failed to parse validation.filter of %s: %w", path, err)
+		}
+	}
+
+	output := mappingValue(mappingValue(root, "expect"), "output")
+	var issues []LintIssue
+	if result := mappingValue(output, "result"); result != nil {
+		issues = append(issues, lintRuleSets(path, result, filter)...)
+	}
+	var files []string
+	if file := mappingValue(output, "file"); file != nil && file.Value != "" {
+		files = append(files, file.Value)
+	}
+	if list := mappingValue(output, "files"); list != nil {
+		for _, file := range list.Content {
+			files = append(files, file.Value)
+		}
+	}
+	for _, expectedPath := range files {
+		expectedPath = strings.ReplaceAll(expectedPath, VariantPlaceholder, variant)
+		if !filepath.IsAbs(expectedPath) {
+			expectedPath = filepath.Join(filepath.Dir(path), expectedPath)
+		}
+		// Hub exports and legacy Windup reports are not YAML and are converted as they are
+		if parser.IsHubIssuesExport(expectedPath) || parser.IsWindupReport(expectedPath) {
+			continue
+		}
+		fileIssues, err := lintExpectedOutput(expectedPath, filter)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, fileIssues...)
+	}
+	return issues, nil
+}
+
+// LintExpectedOutput lints an expected output file
+func LintExpectedOutput(path string) ([]LintIssue, error) {
+	return lintExpectedOutput(path, nil)
+}
+
+func lintExpectedOutput(path string, filter *parser.RuleSetFilter) ([]LintIssue, error) {
+	root, err := readYAMLNode(path)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, nil
+	}
+	return lintRuleSets(path, root, filter), nil
+}
+
+// readYAMLNode parses a file into its top-level YAML node, or nil for an empty file
+func readYAMLNode(path string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	return doc.Content[0], nil
+}
+
+// mappingValue returns the value of a key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// rulesetLinter collects issues found in the rulesets of one file
+type rulesetLinter struct {
+	file   string
+	filter *parser.RuleSetFilter
+	issues []LintIssue
+}
+
+func (l *rulesetLinter) report(node *yaml.Node, path, format string, args ...any) {
+	l.issues = append(l.issues, LintIssue{File: l.file, Line: node.Line, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// lintRuleSets checks a sequence of expected rulesets
+func lintRuleSets(file string, node *yaml.Node, filter *parser.RuleSetFilter) []LintIssue {
+	l := &rulesetLinter{file: file, filter: filter}
+	if node.Kind != yaml.SequenceNode {
+		l.report(node, "", "expected a list of rulesets")
+		return l.issues
+	}
+
+	names := map[string]int{}
+	for i, rs := range node.Content {
+		name := fmt.Sprintf("[%d]", i)
+		if n := mappingValue(rs, "name"); n != nil && n.Value != "" {
+			name = n.Value
+		}
+		path := "ruleset/" + name
+		if line, ok := names[name]; ok {
+			l.report(rs, path, "duplicate ruleset name (first defined on line %d)", line)
+		} else {
+			names[name] = rs.Line
+		}
+		l.lintRuleSet(rs, path)
+	}
+	return l.issues
+}
+
+func (l *rulesetLinter) lintRuleSet(node *yaml.Node, path string) {
+	if !l.checkFields(node, path, lintRuleSetFields) {
+		return
+	}
+
+	if mappingValue(node, "name") == nil {
+		l.report(node, path, "ruleset has no name")
+	}
+	if l.dropped(node) {
+		l.report(node, path, "ruleset has no %s and is dropped before comparison", strings.Join(l.keep(), ", "))
+	}
+
+	ruleIDs := map[string]string{}
+	for _, kind := range []string{"violations", "insights"} {
+		rules := mappingValue(node, kind)
+		if rules == nil {
+			continue
+		}
+		kindPath := path + "/" + kind
+		if !l.checkMapping(rules, kindPath) {
+			continue
+		}
+		for i := 0; i+1 < len(rules.Content); i += 2 {
+			key, value := rules.Content[i], rules.Content[i+1]
+			if other, ok := ruleIDs[key.Value]; ok && other != kind {
+				l.report(key, kindPath+"/"+key.Value, "rule is listed in both %s and %s", other, kind)
+			}
+			ruleIDs[key.Value] = kind
+			l.lintViolation(value, kindPath+"/"+key.Value)
+		}
+	}
+}
+
+// keep returns the sections that keep a ruleset in the filtered output
+func (l *rulesetLinter) keep() []string {
+	if l.filter != nil && len(l.filter.Keep) > 0 {
+		return l.filter.Keep
+	}
+	return parser.DefaultKeep
+}
+
+// dropped reports whether the filter drops a ruleset before comparison
+func (l *rulesetLinter) dropped(node *yaml.Node) bool {
+	if l.filter != nil && l.filter.KeepAll {
+		return false
+	}
+	for _, section := range l.keep() {
+		if !isEmpty(mappingValue(node, section)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (l *rulesetLinter) lintViolation(node *yaml.Node, path string) {
+	if !l.checkFields(node, path, lintViolationFields) {
+		return
+	}
+
+	if category := mappingValue(node, "category"); category != nil && !lintCategories[category.Value] {
+		l.report(category, path, "invalid category %q (must be one of mandatory, optional, potential)", category.Value)
+	}
+
+	if incidents := mappingValue(node, "incidents"); incidents != nil && incidents.Kind == yaml.SequenceNode {
+		for i, incident := range incidents.Content {
+			l.lintIncident(incident, fmt.Sprintf("%s/incidents[%d]", path, i))
+		}
+	}
+	if links := mappingValue(node, "links"); links != nil && links.Kind == yaml.SequenceNode {
+		for i, link := range links.Content {
+			l.checkFields(link, fmt.Sprintf("%s/links[%d]", path, i), lintLinkFields)
+		}
+	}
+}
+
+func (l *rulesetLinter) lintIncident(node *yaml.Node, path string) {
+	if !l.checkFields(node, path, lintIncidentFields) {
+		return
+	}
+	// Incidents without a location (e.g. from dependency conditions) have an empty URI
+	if uri := mappingValue(node, "uri"); uri != nil && uri.Value != "" && !strings.HasPrefix(uri.Value, "file://") {
+		l.report(uri, path, "incident URI %q is not a file:// URI", uri.Value)
+	}
+}
+
+// checkMapping reports a node that is not a mapping, or has duplicate keys
+func (l *rulesetLinter) checkMapping(node *yaml.Node, path string) bool {
+	if node.Kind != yaml.MappingNode {
+		l.report(node, path, "expected a mapping")
+		return false
+	}
+	seen := map[string]int{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if line, ok := seen[key.Value]; ok {
+			l.report(key, path, "duplicate key %q (first defined on line %d)", key.Value, line)
+			continue
+		}
+		seen[key.Value] = key.Line
+	}
+	return true
+}
+
+// checkFields reports duplicate and unknown keys of a mapping node
+func (l *rulesetLinter) checkFields(node *yaml.Node, path string, known map[string]bool) bool {
+	if !l.checkMapping(node, path) {
+		return false
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if key := node.Content[i]; !known[key.Value] {
+			l.report(key, path, "unknown field %q", key.Value)
+		}
+	}
+	return true
+}
+
+// isEmpty reports whether a node is missing or an empty collection
+func isEmpty(node *yaml.Node) bool {
+	return node == nil || len(node.Content) == 0
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLintExpectedOutput(t *testing.T) {
+	tests := []struct {
+		name  string
+		yaml  string
+		wants []string
+	}{
+		{
+			name: "clean",
+			yaml: `- name: ruleset-a
+  violations:
+    rule-001:
+      description: d
+      category: mandatory
+      incidentCount: 3
+      incidents:
+        - uri: file:///source/src/Main.java
+          message: m
+        - uri: ""
+          message: dependency
+      links:
+        - url: https://example.com
+          title: t
+`,
+		},
+		{
+			name: "unknown fields",
+			yaml: `- name: ruleset-a
+  violation: {}
+  tags: [a]
+  insights:
+    rule-001:
+      descripton: d
+      incidents:
+        - uri: file:///source/a
+          line: 3
+`,
+			wants: []string{`unknown field "violation"`, `unknown field "descripton"`, `unknown field "line"`},
+		},
+		{
+			name: "duplicate keys",
+			yaml: `- name: ruleset-a
+  violations:
+    rule-001:
+      description: a
+    rule-001:
+      description: b
+  insights:
+    rule-001:
+      description: c
+- name: ruleset-a
+  tags: [a]
+`,
+			wants: []string{`duplicate key "rule-001" (first defined on line 3)`, "listed in both violations and insights", "duplicate ruleset name"},
+		},
+		{
+			name: "dropped ruleset",
+			yaml: `- name: empty
+  unmatched: [rule-001]
+`,
+			wants: []string{"dropped before comparison"},
+		},
+		{
+			name: "invalid category and uri",
+			yaml: `- name: ruleset-a
+  violations:
+    rule-001:
+      category: required
+      incidents:
+        - uri: /source/src/Main.java
+`,
+			wants: []string{`invalid category "required"`, "is not a file:// URI"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "expected-output.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0644); err != nil {
+				t.Fatal(err)
+			}
+			issues, err := LintExpectedOutput(path)
+			if err != nil {
+				t.Fatalf("LintExpectedOutput() error = %v", err)
+			}
+			if len(issues) != len(tt.wants) {
+				t.Fatalf("got %d issues, want %d: %v", len(issues), len(tt.wants), issues)
+			}
+			for i, want := range tt.wants {
+				if !strings.Contains(issues[i].Message, want) {
+					t.Errorf("issue %d = %q, want it to contain %q", i, issues[i].Message, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLintTestFile(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "test.yaml")
+	content := `name: t
+expect:
+  output:
+    result:
+      - name: inline
+        violations:
+          rule-001:
+            category: potential
+            incidents:
+              - uri: C:\source\Main.java
+`
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := LintTestFile(testFile)
+	if err != nil {
+		t.Fatalf("LintTestFile() error = %v", err)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("got %d issues, want 1: %v", len(issues), issues)
+	}
+	if issues[0].Line != 10 || issues[0].Path != "ruleset/inline/violations/rule-001/incidents[0]" {
+		t.Errorf("issue = %+v, want line 10 at the incident", issues[0])
+	}
+}
+
+func TestLintTestFile_Filter(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "test.yaml")
+	content := `name: t
+validation:
+  filter:
+    keep: [violations, errors]
+expect:
+  output:
+    result:
+      - name: errors-only
+        errors:
+          rule-001: provider failed
+      - name: unmatched-only
+        unmatched: [rule-002]
+`
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := LintTestFile(testFile)
+	if err != nil {
+		t.Fatalf("LintTestFile() error = %v", err)
+	}
+	if len(issues) != 1 || issues[0].Path != "ruleset/unmatched-only" {
+		t.Fatalf("got %v, want only the unmatched-only ruleset reported as dropped", issues)
+	}
+}
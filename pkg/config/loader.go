@@ -1,11 +1,16 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/parser"
 	"gopkg.in/yaml.v3"
 )
 
@@ -14,19 +19,225 @@ func Load(path string) (*TestDefinition, error) {
 	return LoadWithOptions(path, false)
 }
 
-// LoadWithOptions reads and parses a test definition with options
+// LoadTags reads only the tags of a test definition, including the tags of its
+// suite, without loading or validating the rest
+func LoadTags(ref string) ([]string, error) {
+	path, _ := SplitTestRef(ref)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read test file %s: %w", path, err)
+	}
+	var test struct {
+		Tags []string `yaml:"tags"`
+	}
+	if err := decodeTestDefinition(path, "", data, &test); err != nil {
+		return nil, fmt.Errorf("failed to parse test YAML %s: %w", path, err)
+	}
+	return test.Tags, nil
+}
+
+// LoadSkip reads only the skip settings of a test definition, including those of
+// its suite, without loading or validating the rest
+func LoadSkip(ref string) (*SkipConfig, error) {
+	path, variant := SplitTestRef(ref)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read test file %s: %w", path, err)
+	}
+	var test struct {
+		Skip *SkipConfig `yaml:"skip"`
+	}
+	if err := decodeTestDefinition(path, variant, data, &test); err != nil {
+		return nil, fmt.Errorf("failed to parse test YAML %s: %w", path, err)
+	}
+	return test.Skip, nil
+}
+
+// LoadRequirements reads only the requirements of a test definition, including
+// those of its suite, without loading or validating the rest
+func LoadRequirements(ref string) (*Requirements, error) {
+	path, variant := SplitTestRef(ref)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read test file %s: %w", path, err)
+	}
+	var test struct {
+		Requires *Requirements `yaml:"requires"`
+	}
+	if err := decodeTestDefinition(path, variant, data, &test); err != nil {
+		return nil, fmt.Errorf("failed to parse test YAML %s: %w", path, err)
+	}
+	return test.Requires, nil
+}
+
+// SaveExpectations sets the exit code and expected output file of a test file and
+// drops its inline expected result, keeping the rest of the file as written: the
+// settings of its suite and base file, and environment variable references, are
+// not written into the test
+func SaveExpectations(ref string, exitCodes ExitCodes, outputFile string) error {
+	return saveExpectations(ref, exitCodes, func(expect *yaml.Node) {
+		setMappingValue(expect, "output", outputFileNode(outputFile))
+	})
+}
+
+// SaveApplicationExpectations is SaveExpectations for a test analyzing several
+// applications, setting the expected output file of each application by name
+func SaveApplicationExpectations(ref string, exitCodes ExitCodes, outputFiles map[string]string) error {
+	return saveExpectations(ref, exitCodes, func(expect *yaml.Node) {
+		applications := &yaml.Node{Kind: yaml.MappingNode}
+		for _, name := range slices.Sorted(maps.Keys(outputFiles)) {
+			setMappingValue(applications, name, outputFileNode(outputFiles[name]))
+		}
+		setMappingValue(expect, "applications", applications)
+	})
+}
+
+// outputFileNode is an expected output referencing a file, or an empty one
+func outputFileNode(outputFile string) *yaml.Node {
+	output := &yaml.Node{Kind: yaml.MappingNode}
+	if outputFile != "" {
+		output.Content = append(output.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "file"},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: outputFile})
+	}
+	return output
+}
+
+// saveExpectations sets the exit code of a test file and lets setOutput set its
+// expected outputs
+func saveExpectations(ref string, exitCodes ExitCodes, setOutput func(expect *yaml.Node)) error {
+	path, _ := SplitTestRef(ref)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read test file %s: %w", path, err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse test YAML: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("test file %s must be a mapping", path)
+	}
+
+	expect := mappingValue(doc.Content[0], "expect")
+	if expect == nil || expect.Kind != yaml.MappingNode {
+		expect = &yaml.Node{Kind: yaml.MappingNode}
+		setMappingValue(doc.Content[0], "expect", expect)
+	}
+	var exitCode yaml.Node
+	if err := exitCode.Encode(exitCodes); err != nil {
+		return fmt.Errorf("failed to marshal exit code: %w", err)
+	}
+	exitCode.Style = yaml.FlowStyle
+	setMappingValue(expect, "exitCode", &exitCode)
+	setOutput(expect)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to marshal test: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to marshal test: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// inlineResults are the inline expected results of a test definition, which keep
+// their ${...} expected output variables instead of expanding environment variables
+var inlineResults = []string{
+	"expect.output.result",
+	"expect.applications.*.result",
+	"expect.providers.output.*.result",
+}
+
+// decodeTestDefinition decodes a test definition on top of the base file it extends
+// and the defaults of its suite, with the settings of a matrix variant, expanding
+// environment variables except in the inline expected results
+func decodeTestDefinition(path, variant string, data []byte, out any) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	bases, err := applyExtends(doc.Content[0], path)
+	if err != nil {
+		return err
+	}
+	suite, err := FindSuite(path)
+	if err != nil {
+		return err
+	}
+	suite.apply(doc.Content[0])
+	if variant != "" {
+		if err := applyVariant(doc.Content[0], variant); err != nil {
+			return err
+		}
+	}
+	if err := expandEnvNode(doc.Content[0], "", inlineResults...); err != nil {
+		return err
+	}
+	if err := doc.Content[0].Decode(out); err != nil {
+		return err
+	}
+	if test, ok := out.(*TestDefinition); ok {
+		test.bases = bases
+		// Inline expected violations may carry count-only expectations
+		if err := inlineIncidentCounts(doc.Content[0], &test.Expect); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadWithOptions reads and parses a test definition, or a variant of a test with a
+// matrix ("test.yaml#variant"), with options
 // skipExpectedOutput: if true, don't try to load the expected output file (useful for generation)
-func LoadWithOptions(path string, skipExpectedOutput bool) (*TestDefinition, error) {
+func LoadWithOptions(ref string, skipExpectedOutput bool) (*TestDefinition, error) {
+	path, variant := SplitTestRef(ref)
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read test file %s: %w", path, err)
 	}
 
+	// Suite defaults apply to values the test doesn't set
 	var test TestDefinition
-	if err := yaml.Unmarshal(data, &test); err != nil {
+	if err := decodeTestDefinition(path, variant, data, &test); err != nil {
 		return nil, fmt.Errorf("failed to parse test YAML: %w", err)
 	}
 
+	// Every variant is a test of its own, with its own expected output files
+	if variant != "" {
+		test.variant = variant
+		test.Name = fmt.Sprintf("%s [%s]", test.Name, variant)
+		test.Expect.Output.File = strings.ReplaceAll(test.Expect.Output.File, VariantPlaceholder, variant)
+		for i, file := range test.Expect.Output.Files {
+			test.Expect.Output.Files[i] = strings.ReplaceAll(file, VariantPlaceholder, variant)
+		}
+		for name, output := range test.Expect.Applications {
+			output.File = strings.ReplaceAll(output.File, VariantPlaceholder, variant)
+			test.Expect.Applications[name] = output
+		}
+		if test.Expect.Providers != nil {
+			for name, output := range test.Expect.Providers.Output {
+				output.File = strings.ReplaceAll(output.File, VariantPlaceholder, variant)
+				test.Expect.Providers.Output[name] = output
+			}
+		}
+	} else if len(test.Matrix) > 0 {
+		var refs []string
+		for _, v := range test.Matrix.Variants() {
+			refs = append(refs, TestRef(path, v.ID))
+		}
+		return nil, fmt.Errorf("test %s has a matrix, run one of its variants: %s", path, strings.Join(refs, ", "))
+	}
+
 	// Store the absolute path to the test file
 	absPath, err := filepath.Abs(path)
 	if err != nil {
@@ -34,47 +245,158 @@ func LoadWithOptions(path string, skipExpectedOutput bool) (*TestDefinition, err
 	}
 	test.SetTestFilePath(absPath)
 
+	// Applications may be aliases of the application registry
+	if err := test.resolveApplicationAlias(path); err != nil {
+		return nil, err
+	}
+
 	// Parse Git URLs in the analysis configuration
 	test.Analysis.ParseGitURLs()
 
-	// If the expected output specifies a file, load it (unless skipped)
-	if test.Expect.Output.File != "" && !skipExpectedOutput {
+	// Discovery analyses are compared and generated by their tags
+	if test.Analysis.AnalysisMode == DiscoveryAnalysisMode {
+		filter := parser.RuleSetFilter{}
+		if test.Validation.Filter != nil {
+			filter = *test.Validation.Filter
+		}
+		filter.TagsOnly = true
+		test.Validation.Filter = &filter
+	}
+
+	// If the expected output specifies files, load and merge them (unless skipped)
+	if !skipExpectedOutput {
+		if err := loadExpectedOutputFiles(&test.Expect.Output, filepath.Dir(path)); err != nil {
+			return nil, err
+		}
+		for name, output := range test.Expect.Applications {
+			if err := loadExpectedOutputFiles(&output, filepath.Dir(path)); err != nil {
+				return nil, fmt.Errorf("application %s: %w", name, err)
+			}
+			test.Expect.Applications[name] = output
+		}
+		if err := mergeProviderOutputs(&test.Expect, filepath.Dir(path)); err != nil {
+			return nil, err
+		}
+	}
+
+	return &test, nil
+}
+
+// mergeProviderOutputs loads the expected output of each provider and merges it
+// into the test's expected output, which is compared as a whole
+func mergeProviderOutputs(expect *ExpectConfig, testDir string) error {
+	if expect.Providers == nil || len(expect.Providers.Output) == 0 {
+		return nil
+	}
+	outputs := [][]konveyor.RuleSet{expect.Output.Result}
+	for _, name := range slices.Sorted(maps.Keys(expect.Providers.Output)) {
+		output := expect.Providers.Output[name]
+		if err := loadExpectedOutputFiles(&output, testDir); err != nil {
+			return fmt.Errorf("provider %s: %w", name, err)
+		}
+		expect.Providers.Output[name] = output
+		outputs = append(outputs, output.Result)
+		for ruleset, rules := range output.IncidentCounts {
+			if expect.Output.IncidentCounts == nil {
+				expect.Output.IncidentCounts = IncidentCounts{}
+			}
+			if expect.Output.IncidentCounts[ruleset] == nil {
+				expect.Output.IncidentCounts[ruleset] = map[string]IncidentCount{}
+			}
+			maps.Copy(expect.Output.IncidentCounts[ruleset], rules)
+		}
+	}
+	expect.Output.Result = parser.MergeRuleSets(outputs...)
+	return nil
+}
+
+// loadExpectedOutputFiles loads expect.output.file and expect.output.files, resolved
+// relative to the test directory. Several files are merged into one ruleset list.
+func loadExpectedOutputFiles(output *ExpectedOutput, testDir string) error {
+	var files []string
+	if output.File != "" {
+		files = append(files, output.File)
+	}
+	files = append(files, output.Files...)
+	if len(files) == 0 {
+		return nil
+	}
+
+	var outputs [][]konveyor.RuleSet
+	counts := IncidentCounts{}
+	for _, file := range files {
 		// Resolve the expected output file path relative to the test file's directory
-		expectedOutputPath := test.Expect.Output.File
+		expectedOutputPath := file
 		if !filepath.IsAbs(expectedOutputPath) {
-			testDir := filepath.Dir(path)
 			expectedOutputPath = filepath.Join(testDir, expectedOutputPath)
 		}
 
 		// Store the resolved absolute path
 		absExpectedPath, err := filepath.Abs(expectedOutputPath)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get absolute path for expected output: %w", err)
+			return fmt.Errorf("failed to get absolute path for expected output: %w", err)
 		}
-		test.Expect.Output.ResolvedFilePath = absExpectedPath
+		output.ResolvedFilePaths = append(output.ResolvedFilePaths, absExpectedPath)
 
-		rulesets, err := LoadExpectedOutput(expectedOutputPath)
+		rulesets, fileCounts, err := readExpectedOutput(absExpectedPath)
 		if err != nil {
-			return nil, fmt.Errorf("failed to load expected output from %s: %w", test.Expect.Output.File, err)
+			return fmt.Errorf("failed to load expected output from %s: %w", file, err)
+		}
+		outputs = append(outputs, rulesets)
+		for ruleset, rules := range fileCounts {
+			if counts[ruleset] == nil {
+				counts[ruleset] = map[string]IncidentCount{}
+			}
+			for ruleID, count := range rules {
+				counts[ruleset][ruleID] = count
+			}
 		}
-
-		test.Expect.Output.Result = rulesets
 	}
 
-	return &test, nil
+	if len(outputs) == 1 {
+		output.ResolvedFilePath = output.ResolvedFilePaths[0]
+		output.Result = outputs[0]
+	} else {
+		output.Result = parser.MergeRuleSets(outputs...)
+	}
+	if len(counts) == 0 {
+		counts = nil
+	}
+	output.IncidentCounts = counts
+	return nil
 }
 
 // LoadExpectedOutput reads and parses expected RuleSets from a YAML file
 func LoadExpectedOutput(path string) ([]konveyor.RuleSet, error) {
+	rulesets, _, err := readExpectedOutput(path)
+	return rulesets, err
+}
+
+// readExpectedOutput reads expected RuleSets and their count-only expectations.
+// Hub issues exports and legacy Windup/MTA CSV and XML reports are converted into RuleSets.
+func readExpectedOutput(path string) ([]konveyor.RuleSet, IncidentCounts, error) {
+	if parser.IsHubIssuesExport(path) {
+		rulesets, err := parser.ParseHubIssuesExport(path)
+		return rulesets, nil, err
+	}
+	if parser.IsWindupReport(path) {
+		rulesets, err := parser.ParseWindupReport(path)
+		return rulesets, nil, err
+	}
+
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read expected output file: %w", err)
+		return nil, nil, fmt.Errorf("failed to read expected output file: %w", err)
 	}
 
 	var rulesets []konveyor.RuleSet
 	if err := yaml.Unmarshal(data, &rulesets); err != nil {
-		return nil, fmt.Errorf("failed to parse expected output YAML: %w", err)
+		return nil, nil, fmt.Errorf("failed to parse expected output YAML: %w", err)
 	}
 
-	return rulesets, nil
+	counts, err := parseIncidentCounts(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rulesets, counts, nil
 }
@@ -0,0 +1,287 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+func TestLoad_ExpectedOutputFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"test.yaml": `name: multi-module
+analysis:
+  application: ./app
+expect:
+  exitCode: 0
+  output:
+    files:
+      - module-a.yaml
+      - module-b.yaml
+`,
+		"module-a.yaml": "- name: rs\n  violations:\n    rule-001:\n      description: first\n      incidentCount: 3\n",
+		"module-b.yaml": "- name: rs\n  unmatched: [rule-001]\n  violations:\n    rule-002:\n      description: second\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	test, err := Load(filepath.Join(dir, "test.yaml"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	output := test.Expect.Output
+	if len(output.ResolvedFilePaths) != 2 || output.ResolvedFilePath != "" {
+		t.Errorf("expected two resolved files and no single file, got %v and %q", output.ResolvedFilePaths, output.ResolvedFilePath)
+	}
+	if len(output.Result) != 1 || len(output.Result[0].Violations) != 2 {
+		t.Fatalf("expected one merged ruleset with two violations, got %+v", output.Result)
+	}
+	if len(output.Result[0].Unmatched) != 0 {
+		t.Errorf("expected rule-001 to be matched by module-a, got unmatched %v", output.Result[0].Unmatched)
+	}
+	if count, ok := output.IncidentCounts.Get("rs", "rule-001"); !ok || count.Count != 3 {
+		t.Errorf("expected incidentCount 3 for rule-001, got %+v", count)
+	}
+	if err := validateExpectedOutput(&output); err != nil {
+		t.Errorf("validateExpectedOutput() error = %v", err)
+	}
+}
+
+func TestLoad_ProviderOutputs(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"test.yaml": `name: multi-language
+analysis:
+  application: ./app
+  analysisMode: source-only
+expect:
+  exitCode: 0
+  output:
+    result:
+    - name: discovery
+      tags: [Java, JavaScript]
+  providers:
+    ran: [builtin]
+    output:
+      java:
+        file: expected-java.yaml
+      nodejs:
+        file: expected-nodejs.yaml
+`,
+		"expected-java.yaml":   "- name: rs\n  violations:\n    java-001:\n      description: java\n      incidentCount: 2\n",
+		"expected-nodejs.yaml": "- name: rs\n  violations:\n    nodejs-001:\n      description: nodejs\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	test, err := Load(filepath.Join(dir, "test.yaml"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := Validate(test); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	output := test.Expect.Output
+	if len(output.Result) != 2 || output.Result[0].Name != "discovery" || len(output.Result[1].Violations) != 2 {
+		t.Fatalf("expected the discovery ruleset and a ruleset merged from both providers, got %+v", output.Result)
+	}
+	if count, ok := output.IncidentCounts.Get("rs", "java-001"); !ok || count.Count != 2 {
+		t.Errorf("expected incidentCount 2 for java-001, got %+v", count)
+	}
+	if len(test.Expect.Providers.Output["java"].Result) != 1 {
+		t.Errorf("expected the java output to be loaded, got %+v", test.Expect.Providers.Output["java"])
+	}
+	if required := test.Expect.Providers.Required(); !slices.Equal(required, []string{"builtin", "java", "nodejs"}) {
+		t.Errorf("Required() = %v", required)
+	}
+}
+
+func TestLoad_Applications(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "test.yaml")
+	files := map[string]string{
+		"test.yaml": `name: bulk
+analysis:
+  applications:
+    - name: coolstore
+      application: https://github.com/konveyor-ecosystem/coolstore#main
+    - name: local
+      application: ./local
+  analysisMode: source-only
+expect:
+  applications:
+    coolstore:
+      file: expected-output-coolstore.yaml
+    local:
+      result:
+        - name: rs
+          tags: [Java]
+`,
+		"expected-output-coolstore.yaml": "- name: rs\n  tags: [EJB]\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	test, err := Load(testFile)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := Validate(test); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	coolstore := test.ForApplication(test.Analysis.Applications[0])
+	if coolstore.Name != "bulk [coolstore]" || coolstore.Analysis.ApplicationGitComponents == nil || coolstore.Analysis.GetSourceDir() != "source-coolstore" {
+		t.Errorf("unexpected application test %q: %+v", coolstore.Name, coolstore.Analysis)
+	}
+	if result := coolstore.Expect.Output.Result; len(result) != 1 || result[0].Tags[0] != "EJB" {
+		t.Errorf("expected the output of expected-output-coolstore.yaml, got %+v", result)
+	}
+
+	outputFiles := map[string]string{"coolstore": "expected-output-coolstore.yaml", "local": "expected-output-local.yaml"}
+	if err := SaveApplicationExpectations(testFile, ExitCode(0), outputFiles); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := LoadWithOptions(testFile, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, file := range outputFiles {
+		if output := saved.Expect.Applications[name]; output.File != file || len(output.Result) != 0 {
+			t.Errorf("expected %s to expect %s, got %+v", name, file, output)
+		}
+	}
+}
+
+func TestLoadSkip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.yaml")
+	content := `name: skipped
+skip:
+  reason: binary analysis is broken
+  issue: MTA-5588
+  targets: [tackle-hub]
+analysis:
+  application: ./app
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	skip, err := LoadSkip(path)
+	if err != nil {
+		t.Fatalf("LoadSkip() error = %v", err)
+	}
+	if got := skip.String(); got != "binary analysis is broken (MTA-5588)" {
+		t.Errorf("String() = %q", got)
+	}
+	if !skip.Applies(SkipEnvironment{}) || !skip.Applies(SkipEnvironment{TargetType: "tackle-hub"}) {
+		t.Error("Expected the skip to apply when listing and on tackle-hub")
+	}
+	if skip.Applies(SkipEnvironment{TargetType: "kantra"}) {
+		t.Error("Expected the skip not to apply on kantra")
+	}
+}
+
+func TestSkipApplies(t *testing.T) {
+	t.Setenv("KONCUR_TEST_SKIP_SET", "1")
+	hubArm := SkipEnvironment{TargetType: "tackle-hub", OS: "linux", Arch: "arm64"}
+	kantraAmd := SkipEnvironment{TargetType: "kantra", OS: "linux", Arch: "amd64", MavenSettings: true}
+	tests := []struct {
+		name string
+		skip *SkipConfig
+		env  SkipEnvironment
+		want bool
+	}{
+		{"no skip", nil, hubArm, false},
+		{"unconditional", &SkipConfig{}, kantraAmd, true},
+		{"any skip when listing", &SkipConfig{Arch: []string{"s390x"}}, SkipEnvironment{}, true},
+		{"arch", &SkipConfig{Arch: []string{"arm64"}}, hubArm, true},
+		{"other arch", &SkipConfig{Arch: []string{"arm64"}}, kantraAmd, false},
+		{"os", &SkipConfig{OS: []string{"darwin"}}, hubArm, false},
+		{"target and arch", &SkipConfig{Targets: []string{"tackle-hub"}, Arch: []string{"amd64"}}, hubArm, false},
+		{"unless maven settings, without", &SkipConfig{Unless: &SkipUnless{MavenSettings: true}}, hubArm, true},
+		{"unless maven settings, with", &SkipConfig{Unless: &SkipUnless{MavenSettings: true}}, kantraAmd, false},
+		{"unless env, set", &SkipConfig{Unless: &SkipUnless{Env: []string{"KONCUR_TEST_SKIP_SET"}}}, hubArm, false},
+		{"unless env, unset", &SkipConfig{Unless: &SkipUnless{Env: []string{"KONCUR_TEST_SKIP_UNSET"}}}, hubArm, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.skip.Applies(tt.env); got != tt.want {
+				t.Errorf("Applies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequirementsMissing(t *testing.T) {
+	requires := &Requirements{Providers: []string{"java", "dotnet"}}
+	tests := []struct {
+		name     string
+		requires *Requirements
+		env      SkipEnvironment
+		want     []string
+	}{
+		{"no requirements", nil, SkipEnvironment{Providers: []string{"java"}}, nil},
+		{"unknown providers", requires, SkipEnvironment{TargetType: "kantra"}, nil},
+		{"all providers", requires, SkipEnvironment{Providers: []string{"dotnet", "go", "java"}}, nil},
+		{"missing provider", requires, SkipEnvironment{Providers: []string{"java", "nodejs"}}, []string{"dotnet"}},
+		{"no providers", requires, SkipEnvironment{Providers: []string{}}, []string{"java", "dotnet"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.requires.Missing(tt.env); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Missing() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_Discovery(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "test.yaml")
+	content := `name: discovery
+analysis:
+  application: ./app
+  analysisMode: discovery
+validation:
+  filter:
+    keep: [tags, errors]
+expect:
+  output:
+    result:
+      - name: discovery-rules
+        tags: [Language=Java]
+`
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	test, err := Load(testFile)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := Validate(test); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if filter := test.Validation.Filter; filter == nil || !filter.TagsOnly || len(filter.Keep) != 2 {
+		t.Errorf("expected a tags-only filter keeping the test's sections, got %+v", filter)
+	}
+
+	test.Expect.Output.Result[0].Insights = map[string]konveyor.Violation{"tech-001": {}}
+	if err := Validate(test); err == nil {
+		t.Error("expected insights of a discovery test to be rejected")
+	}
+}
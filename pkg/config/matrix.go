@@ -0,0 +1,271 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// VariantPlaceholder is replaced with the variant in expected output file names
+const VariantPlaceholder = "{variant}"
+
+// Matrix expands a test into one variant per combination of analysis settings,
+// e.g. every analysis mode with every target
+type Matrix []MatrixAxis
+
+// MatrixAxis is an analysis setting and the values the test runs with. A value is
+// a list for list settings such as target; a single value is a list of one.
+type MatrixAxis struct {
+	Setting string
+	Values  [][]string
+}
+
+// MatrixVariant is one combination of matrix values
+type MatrixVariant struct {
+	// ID names the variant in test references, test names and expected output files
+	ID       string
+	Settings []MatrixSetting
+}
+
+// MatrixSetting is the value of an analysis setting in a variant
+type MatrixSetting struct {
+	Setting string
+	Value   []string
+}
+
+// UnmarshalYAML reads the matrix as a mapping from analysis settings to values,
+// keeping the order of the settings
+func (m *Matrix) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: matrix must map analysis settings to lists of values", node.Line)
+	}
+	var matrix Matrix
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, values := node.Content[i], node.Content[i+1]
+		if _, ok := analysisSettingKind(key.Value); !ok {
+			return fmt.Errorf("line %d: unknown analysis setting %q in matrix", key.Line, key.Value)
+		}
+		for _, axis := range matrix {
+			if axis.Setting == key.Value {
+				return fmt.Errorf("line %d: duplicate matrix setting %q", key.Line, key.Value)
+			}
+		}
+		if values.Kind != yaml.SequenceNode || len(values.Content) == 0 {
+			return fmt.Errorf("line %d: matrix setting %q must list its values", values.Line, key.Value)
+		}
+		axis := MatrixAxis{Setting: key.Value}
+		seen := map[string]bool{}
+		for _, value := range values.Content {
+			var v []string
+			if value.Kind == yaml.ScalarNode {
+				v = []string{value.Value}
+			} else if err := value.Decode(&v); err != nil {
+				return fmt.Errorf("line %d: invalid value of matrix setting %q: %w", value.Line, key.Value, err)
+			}
+			id := variantValueID(v)
+			if seen[id] {
+				return fmt.Errorf("line %d: duplicate value %q of matrix setting %q", value.Line, id, key.Value)
+			}
+			seen[id] = true
+			axis.Values = append(axis.Values, v)
+		}
+		matrix = append(matrix, axis)
+	}
+	*m = matrix
+	return nil
+}
+
+// MarshalYAML writes the matrix back as a mapping
+func (m Matrix) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, axis := range m {
+		values := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for _, value := range axis.Values {
+			var v yaml.Node
+			if len(value) == 1 {
+				if err := v.Encode(value[0]); err != nil {
+					return nil, err
+				}
+			} else if err := v.Encode(value); err != nil {
+				return nil, err
+			}
+			values.Content = append(values.Content, &v)
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: axis.Setting}, values)
+	}
+	return node, nil
+}
+
+// Variants returns every combination of the matrix values, in order
+func (m Matrix) Variants() []MatrixVariant {
+	if len(m) == 0 {
+		return nil
+	}
+	variants := []MatrixVariant{{}}
+	for _, axis := range m {
+		var expanded []MatrixVariant
+		for _, variant := range variants {
+			for _, value := range axis.Values {
+				settings := append(variant.Settings[:len(variant.Settings):len(variant.Settings)], MatrixSetting{Setting: axis.Setting, Value: value})
+				id := variantValueID(value)
+				if variant.ID != "" {
+					id = variant.ID + "_" + id
+				}
+				expanded = append(expanded, MatrixVariant{ID: id, Settings: settings})
+			}
+		}
+		variants = expanded
+	}
+	return variants
+}
+
+// Variant returns the variant with an ID
+func (m Matrix) Variant(id string) (MatrixVariant, error) {
+	var ids []string
+	for _, variant := range m.Variants() {
+		if variant.ID == id {
+			return variant, nil
+		}
+		ids = append(ids, variant.ID)
+	}
+	if len(ids) == 0 {
+		return MatrixVariant{}, fmt.Errorf("test has no matrix, it has no variant %q", id)
+	}
+	return MatrixVariant{}, fmt.Errorf("unknown variant %q (variants: %s)", id, strings.Join(ids, ", "))
+}
+
+// variantValueID names a matrix value in variant IDs, keeping characters that are
+// safe in file names
+func variantValueID(value []string) string {
+	id := []rune(strings.Join(value, "+"))
+	for i, ch := range id {
+		if !(ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9' || strings.ContainsRune("-+.", ch)) {
+			id[i] = '-'
+		}
+	}
+	return string(id)
+}
+
+// analysisSettingKind returns the kind of the analysis setting with a YAML name
+func analysisSettingKind(name string) (reflect.Kind, bool) {
+	t := reflect.TypeOf(AnalysisConfig{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if tag, _, _ := strings.Cut(field.Tag.Get("yaml"), ","); tag == name && tag != "-" {
+			return field.Type.Kind(), true
+		}
+	}
+	return reflect.Invalid, false
+}
+
+// applyVariant sets the analysis settings of a variant in a test definition node
+func applyVariant(test *yaml.Node, id string) error {
+	var matrix Matrix
+	if node := mappingValue(test, "matrix"); node != nil {
+		if err := node.Decode(&matrix); err != nil {
+			return err
+		}
+	}
+	variant, err := matrix.Variant(id)
+	if err != nil {
+		return err
+	}
+
+	analysis := mappingValue(test, "analysis")
+	if analysis == nil || analysis.Kind != yaml.MappingNode {
+		analysis = &yaml.Node{Kind: yaml.MappingNode}
+		setMappingValue(test, "analysis", analysis)
+	}
+	for _, setting := range variant.Settings {
+		var value yaml.Node
+		if kind, _ := analysisSettingKind(setting.Setting); kind == reflect.Slice {
+			err = value.Encode(setting.Value)
+		} else if len(setting.Value) == 1 {
+			value = yaml.Node{Kind: yaml.ScalarNode, Value: setting.Value[0]}
+		} else {
+			err = fmt.Errorf("matrix setting %s takes a single value, not %v", setting.Setting, setting.Value)
+		}
+		if err != nil {
+			return err
+		}
+		setMappingValue(analysis, setting.Setting, &value)
+	}
+	return nil
+}
+
+// setMappingValue sets the value of a key in a mapping node
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+}
+
+// TestRef references a variant of a test file, or the test file without a variant
+func TestRef(path, variant string) string {
+	if variant == "" {
+		return path
+	}
+	return path + "#" + variant
+}
+
+// SplitTestRef splits a test reference into the test file and the variant
+func SplitTestRef(ref string) (path, variant string) {
+	i := strings.LastIndex(ref, "#")
+	if i < 0 || i < strings.LastIndexAny(ref, `/\`) {
+		return ref, ""
+	}
+	return ref[:i], ref[i+1:]
+}
+
+// TestVariants returns a reference to every variant of a test file, or the test
+// file itself when it has no matrix
+func TestVariants(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read test file %s: %w", path, err)
+	}
+	var test struct {
+		Matrix Matrix `yaml:"matrix"`
+	}
+	if err := decodeTestDefinition(path, "", data, &test); err != nil {
+		return nil, fmt.Errorf("failed to parse test YAML %s: %w", path, err)
+	}
+	variants := test.Matrix.Variants()
+	if len(variants) == 0 {
+		return []string{path}, nil
+	}
+	refs := make([]string, 0, len(variants))
+	for _, variant := range variants {
+		refs = append(refs, TestRef(path, variant.ID))
+	}
+	return refs, nil
+}
+
+// Variant returns the matrix variant the test was loaded as, or ""
+func (t *TestDefinition) Variant() string {
+	return t.variant
+}
+
+// VariantFile returns the name of a file of the test's variant, e.g.
+// expected-output-full_quarkus.yaml for expected-output.yaml
+func (t *TestDefinition) VariantFile(name string) string {
+	return strings.ReplaceAll(t.VariantFilePattern(name), VariantPlaceholder, t.variant)
+}
+
+// VariantFilePattern returns the name of a file per variant with the variant
+// placeholder, e.g. expected-output-{variant}.yaml, or name without a variant
+func (t *TestDefinition) VariantFilePattern(name string) string {
+	if t.variant == "" {
+		return name
+	}
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + "-" + VariantPlaceholder + ext
+}
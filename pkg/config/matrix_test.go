@@ -0,0 +1,121 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMatrix(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "test.yaml")
+	content := `name: daytrader
+analysis:
+  application: ./app
+  analysisMode: full
+  target: [cloud-readiness]
+matrix:
+  analysisMode: [source-only, full]
+  target: [quarkus, [eap8, jakarta-ee]]
+expect:
+  exitCode: 0
+  output:
+    file: expected-output-{variant}.yaml
+`
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	refs, err := TestVariants(testFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		testFile + "#source-only_quarkus",
+		testFile + "#source-only_eap8+jakarta-ee",
+		testFile + "#full_quarkus",
+		testFile + "#full_eap8+jakarta-ee",
+	}
+	if !reflect.DeepEqual(refs, want) {
+		t.Fatalf("TestVariants() = %v, want %v", refs, want)
+	}
+
+	test, err := LoadWithOptions(refs[1], true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if test.Name != "daytrader [source-only_eap8+jakarta-ee]" || test.Variant() != "source-only_eap8+jakarta-ee" {
+		t.Errorf("unexpected name %q and variant %q", test.Name, test.Variant())
+	}
+	if test.Analysis.AnalysisMode != "source-only" || !reflect.DeepEqual(test.Analysis.Target, []string{"eap8", "jakarta-ee"}) {
+		t.Errorf("expected the variant's settings, got %+v", test.Analysis)
+	}
+	if test.Expect.Output.File != "expected-output-source-only_eap8+jakarta-ee.yaml" {
+		t.Errorf("unexpected expected output file %q", test.Expect.Output.File)
+	}
+	if got := test.VariantFile("expected-dependencies.yaml"); got != "expected-dependencies-source-only_eap8+jakarta-ee.yaml" {
+		t.Errorf("VariantFile() = %q", got)
+	}
+	if got := test.VariantFilePattern("expected-output.yaml"); got != "expected-output-{variant}.yaml" {
+		t.Errorf("VariantFilePattern() = %q", got)
+	}
+
+	if _, err := LoadWithOptions(testFile, true); err == nil || !strings.Contains(err.Error(), "#full_quarkus") {
+		t.Errorf("expected loading a matrix test without a variant to list the variants, got %v", err)
+	}
+	if _, err := LoadWithOptions(testFile+"#full_eap7", true); err == nil {
+		t.Error("expected an error for an unknown variant")
+	}
+
+	// The matrix is written back as it was read
+	data, err := yaml.Marshal(test.Matrix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "analysisMode: [source-only, full]\ntarget: [quarkus, [eap8, jakarta-ee]]\n"; string(data) != want {
+		t.Errorf("Marshal() = %q, want %q", data, want)
+	}
+}
+
+func TestMatrix_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown setting":    "matrix:\n  mode: [a, b]\n",
+		"duplicate value":    "matrix:\n  analysisMode: [full, full]\n",
+		"no values":          "matrix:\n  analysisMode: []\n",
+		"not a list":         "matrix:\n  analysisMode: full\n",
+		"not a mapping":      "matrix: [full]\n",
+		"list of scalar key": "matrix:\n  analysisMode: [[full, source-only]]\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			testFile := filepath.Join(t.TempDir(), "test.yaml")
+			if err := os.WriteFile(testFile, []byte("name: test\n"+content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			refs, err := TestVariants(testFile)
+			if err == nil && len(refs) == 1 {
+				_, err = LoadWithOptions(refs[0], true)
+			}
+			if err == nil {
+				t.Errorf("expected an error, got variants %v", refs)
+			}
+		})
+	}
+}
+
+func TestSplitTestRef(t *testing.T) {
+	tests := []struct{ ref, path, variant string }{
+		{"tests/a/test.yaml", "tests/a/test.yaml", ""},
+		{"tests/a/test.yaml#full_quarkus", "tests/a/test.yaml", "full_quarkus"},
+		{"tests/#a/test.yaml", "tests/#a/test.yaml", ""},
+	}
+	for _, tt := range tests {
+		if path, variant := SplitTestRef(tt.ref); path != tt.path || variant != tt.variant {
+			t.Errorf("SplitTestRef(%q) = %q, %q", tt.ref, path, variant)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// RenderedMavenSettingsFile is the name of the rendered maven settings of a test
+// in its work directory
+const RenderedMavenSettingsFile = "maven-settings.xml"
+
+// mavenSecretReference matches the ${env:NAME} and ${file:path} secret references of
+// maven settings templates. Maven's own properties, such as ${env.HOME}, are kept.
+var mavenSecretReference = regexp.MustCompile(`\$\{((?:env|file):[^}]+)\}`)
+
+// MavenSettingsPath returns the absolute path of the test's own maven settings, or
+// "" when it uses those of the target
+func (t *TestDefinition) MavenSettingsPath() string {
+	if t.MavenSettings == "" || filepath.IsAbs(t.MavenSettings) {
+		return t.MavenSettings
+	}
+	return filepath.Join(t.GetTestDir(), t.MavenSettings)
+}
+
+// RenderMavenSettings writes the test's own maven settings into dir, with the secret
+// references in them resolved, and returns the path of the written file. Resolved
+// secrets are registered for redaction.
+func (t *TestDefinition) RenderMavenSettings(dir string) (string, error) {
+	src := t.MavenSettingsPath()
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return "", fmt.Errorf("failed to read maven settings %s: %w", src, err)
+	}
+	var resolveErr error
+	rendered := mavenSecretReference.ReplaceAllFunc(data, func(ref []byte) []byte {
+		value, err := ResolveSecret(string(mavenSecretReference.FindSubmatch(ref)[1]), filepath.Dir(src))
+		if err != nil && resolveErr == nil {
+			resolveErr = err
+		}
+		return []byte(value)
+	})
+	if resolveErr != nil {
+		return "", fmt.Errorf("failed to render maven settings %s: %w", src, resolveErr)
+	}
+
+	dst, err := filepath.Abs(filepath.Join(dir, RenderedMavenSettingsFile))
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	// The rendered settings hold credentials
+	if err := os.WriteFile(dst, rendered, 0600); err != nil {
+		return "", fmt.Errorf("failed to write maven settings: %w", err)
+	}
+	return dst, nil
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderMavenSettings(t *testing.T) {
+	dir := t.TempDir()
+	settings := `<settings>
+  <localRepository>${env.HOME}/.m2/repository</localRepository>
+  <servers>
+    <server>
+      <username>${env:KONCUR_TEST_MAVEN_USER}</username>
+      <password>${file:secrets/maven-password}</password>
+    </server>
+  </servers>
+</settings>
+`
+	if err := os.MkdirAll(filepath.Join(dir, "secrets"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "settings.xml"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "secrets", "maven-password"), []byte("maven-pass-4649\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KONCUR_TEST_MAVEN_USER", "maven-user-4649")
+
+	test := &TestDefinition{MavenSettings: "settings.xml"}
+	test.SetTestFilePath(filepath.Join(dir, "test.yaml"))
+	if got, want := test.MavenSettingsPath(), filepath.Join(dir, "settings.xml"); got != want {
+		t.Errorf("MavenSettingsPath() = %q, want %q", got, want)
+	}
+
+	workDir := t.TempDir()
+	path, err := test.RenderMavenSettings(workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(workDir, RenderedMavenSettingsFile) {
+		t.Errorf("RenderMavenSettings() = %q", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"<localRepository>${env.HOME}/.m2/repository</localRepository>",
+		"<username>maven-user-4649</username>",
+		"<password>maven-pass-4649</password>",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("rendered settings miss %q:\n%s", want, data)
+		}
+	}
+
+	test.MavenSettings = filepath.Join(dir, "settings.xml")
+	os.Unsetenv("KONCUR_TEST_MAVEN_USER")
+	if _, err := test.RenderMavenSettings(workDir); err == nil || !strings.Contains(err.Error(), "KONCUR_TEST_MAVEN_USER is not set") {
+		t.Errorf("expected a missing variable error, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GenerationMetadata records how an expected output was generated. It is written as
+// a comment header of the expected output file, so it doesn't change its contents:
+//
+//	# generated:
+//	#   koncur: v0.3.0
+//	#   target: kantra
+//	#   toolVersion: v0.7.0
+//	#   date: "2026-10-17T09:30:00Z"
+//	#   application: https://github.com/example/app.git#main
+type GenerationMetadata struct {
+	Koncur      string `yaml:"koncur,omitempty"`
+	Target      string `yaml:"target,omitempty"`
+	ToolVersion string `yaml:"toolVersion,omitempty"`
+	Date        string `yaml:"date,omitempty"`
+	Application string `yaml:"application,omitempty"`
+}
+
+type metadataHeader struct {
+	Generated *GenerationMetadata `yaml:"generated"`
+}
+
+// Header returns the metadata as YAML comment lines
+func (m *GenerationMetadata) Header() (string, error) {
+	var data strings.Builder
+	encoder := yaml.NewEncoder(&data)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(metadataHeader{Generated: m}); err != nil {
+		return "", fmt.Errorf("failed to marshal generation metadata: %w", err)
+	}
+	var header strings.Builder
+	for _, line := range strings.Split(strings.TrimRight(data.String(), "\n"), "\n") {
+		header.WriteString("# " + line + "\n")
+	}
+	return header.String(), nil
+}
+
+// ReadGenerationMetadata reads the metadata header of an expected output file.
+// It returns nil when the file has no header, e.g. it was written by hand.
+func ReadGenerationMetadata(path string) (*GenerationMetadata, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read expected output file: %w", err)
+	}
+	defer f.Close()
+
+	// The header is the leading block of comment lines
+	var header strings.Builder
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, ok := strings.CutPrefix(scanner.Text(), "# ")
+		if !ok {
+			break
+		}
+		header.WriteString(line + "\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read expected output file: %w", err)
+	}
+
+	var parsed metadataHeader
+	if err := yaml.Unmarshal([]byte(header.String()), &parsed); err != nil {
+		// Other leading comments are not a metadata header
+		return nil, nil
+	}
+	return parsed.Generated, nil
+}
+
+// Differences describes how the tool that produced an output differs from the one that
+// generated the expected output. Versions are only compared when both are known.
+func (m *GenerationMetadata) Differences(target, toolVersion string) []string {
+	var differences []string
+	if m.Target != "" && target != "" && m.Target != target {
+		differences = append(differences, fmt.Sprintf("generated with target %s, running %s", m.Target, target))
+	}
+	if m.ToolVersion != "" && toolVersion != "" && m.ToolVersion != toolVersion {
+		differences = append(differences, fmt.Sprintf("generated with %s version %s, running %s", m.Target, m.ToolVersion, toolVersion))
+	}
+	return differences
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGenerationMetadata(t *testing.T) {
+	dir := t.TempDir()
+	metadata := &GenerationMetadata{
+		Koncur:      "v0.3.0",
+		Target:      "kantra",
+		ToolVersion: "v0.7.0",
+		Date:        "2026-10-17T09:30:00Z",
+		Application: "https://github.com/example/app.git#main",
+	}
+	header, err := metadata.Header()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    *GenerationMetadata
+	}{
+		{
+			name:    "header",
+			content: header + "- name: ruleset\n",
+			want:    metadata,
+		},
+		{
+			name:    "no header",
+			content: "- name: ruleset\n",
+		},
+		{
+			name:    "other comments",
+			content: "# Expected output of the daytrader test: violations only\n- name: ruleset\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "expected-output.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReadGenerationMetadata(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			rulesets, err := LoadExpectedOutput(path)
+			if err != nil || len(rulesets) != 1 {
+				t.Errorf("expected the header not to change the output, got %v (%v)", rulesets, err)
+			}
+		})
+	}
+
+	if got := metadata.Differences("kantra", ""); len(got) != 0 {
+		t.Errorf("unknown versions should not differ, got %q", got)
+	}
+	if got := metadata.Differences("tackle-hub", "v0.8.0"); len(got) != 2 {
+		t.Errorf("expected target and version differences, got %q", got)
+	}
+}
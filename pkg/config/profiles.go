@@ -0,0 +1,118 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Tolerance profile names
+const (
+	// ProfileDefault adds no tolerances beyond the built-in field defaults
+	ProfileDefault = "default"
+	// ProfileHubVsKantra covers the known differences between Tackle Hub and kantra output
+	ProfileHubVsKantra = "hub-vs-kantra"
+	// ProfileDiagnostics covers output reported as per-file diagnostics, e.g. by Kai
+	ProfileDiagnostics = "diagnostics"
+)
+
+// ToleranceProfile is a named set of known, acceptable differences between a
+// target's output and expected outputs generated with kantra
+type ToleranceProfile struct {
+	Name        string
+	Description string
+	// Tolerances are applied beneath the validation settings of targets and tests
+	Tolerances ValidationConfig
+	// StrictTolerances replace Tolerances when strict validation is enabled
+	StrictTolerances *ValidationConfig
+}
+
+var (
+	profileTrue   = true
+	ignoreRuleIDs = &RuleListComparison{Ignore: true}
+)
+
+// toleranceProfiles are the profiles tests and targets can select with validation.profile
+var toleranceProfiles = map[string]ToleranceProfile{
+	ProfileDefault: {
+		Name:        ProfileDefault,
+		Description: "No tolerances beyond the defaults: code snips and variables are ignored, Maven and container paths are normalized",
+	},
+	ProfileHubVsKantra: {
+		Name: ProfileHubVsKantra,
+		Description: "The hub API has no unmatched or skipped rules and code snips can't be configured; " +
+			"applications are cloned below /source; tags are only compared in strict mode",
+		Tolerances: ValidationConfig{
+			IgnoreCodeSnips:  &profileTrue,
+			SkipTags:         &profileTrue,
+			Unmatched:        ignoreRuleIDs,
+			Skipped:          ignoreRuleIDs,
+			StripURIPrefixes: []string{"/source"},
+		},
+		StrictTolerances: &ValidationConfig{
+			IgnoreCodeSnips:  &profileTrue,
+			Unmatched:        ignoreRuleIDs,
+			Skipped:          ignoreRuleIDs,
+			StripURIPrefixes: []string{"/source"},
+		},
+	},
+	ProfileDiagnostics: {
+		Name:        ProfileDiagnostics,
+		Description: "Diagnostics carry no code snips or variables, and there are no unmatched or skipped rules",
+		Tolerances: ValidationConfig{
+			IgnoreCodeSnips: &profileTrue,
+			IgnoreVariables: &profileTrue,
+			Unmatched:       ignoreRuleIDs,
+			Skipped:         ignoreRuleIDs,
+		},
+	},
+}
+
+// defaultProfiles are the profiles used by targets when none is selected
+var defaultProfiles = map[string]string{
+	"tackle-hub": ProfileHubVsKantra,
+	"kai-rpc":    ProfileDiagnostics,
+}
+
+// GetToleranceProfile returns a profile by name
+func GetToleranceProfile(name string) (ToleranceProfile, bool) {
+	profile, ok := toleranceProfiles[name]
+	return profile, ok
+}
+
+// ToleranceProfiles returns all profiles sorted by name
+func ToleranceProfiles() []ToleranceProfile {
+	profiles := make([]ToleranceProfile, 0, len(toleranceProfiles))
+	for _, profile := range toleranceProfiles {
+		profiles = append(profiles, profile)
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	return profiles
+}
+
+// DefaultProfile returns the profile a target uses when none is selected
+func DefaultProfile(targetType string) string {
+	if name, ok := defaultProfiles[targetType]; ok {
+		return name
+	}
+	return ProfileDefault
+}
+
+// WithProfile returns v on top of the tolerances of its selected profile,
+// or of the target's default profile when none is selected
+func (v ValidationConfig) WithProfile(targetType string) (ValidationConfig, error) {
+	name := v.Profile
+	if name == "" {
+		name = DefaultProfile(targetType)
+	}
+	profile, ok := toleranceProfiles[name]
+	if !ok {
+		return v, fmt.Errorf("unknown tolerance profile %q", name)
+	}
+	tolerances := profile.Tolerances
+	if v.Strict && profile.StrictTolerances != nil {
+		tolerances = *profile.StrictTolerances
+	}
+	merged := tolerances.Merge(v)
+	merged.Profile = name
+	return merged, nil
+}
@@ -0,0 +1,43 @@
+package config
+
+import "testing"
+
+func TestWithProfile(t *testing.T) {
+	no := false
+	tests := []struct {
+		name        string
+		targetType  string
+		cfg         ValidationConfig
+		wantProfile string
+		wantSkip    bool
+		wantTags    bool
+		wantErr     bool
+	}{
+		{name: "kantra default", targetType: "kantra", wantProfile: ProfileDefault},
+		{name: "hub default", targetType: "tackle-hub", wantProfile: ProfileHubVsKantra, wantSkip: true, wantTags: true},
+		{name: "hub strict compares tags", targetType: "tackle-hub", cfg: ValidationConfig{Strict: true}, wantProfile: ProfileHubVsKantra, wantSkip: true},
+		{name: "test selects profile", targetType: "kantra", cfg: ValidationConfig{Profile: ProfileHubVsKantra}, wantProfile: ProfileHubVsKantra, wantSkip: true, wantTags: true},
+		{name: "test overrides profile setting", targetType: "tackle-hub", cfg: ValidationConfig{SkipTags: &no}, wantProfile: ProfileHubVsKantra, wantSkip: true},
+		{name: "unknown profile", targetType: "kantra", cfg: ValidationConfig{Profile: "nope"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cfg.WithProfile(tt.targetType)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WithProfile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Profile != tt.wantProfile {
+				t.Errorf("Profile = %q, want %q", got.Profile, tt.wantProfile)
+			}
+			if skip := got.Skipped != nil && got.Skipped.Ignore; skip != tt.wantSkip {
+				t.Errorf("Skipped.Ignore = %v, want %v", skip, tt.wantSkip)
+			}
+			if tags := got.SkipTags != nil && *got.SkipTags; tags != tt.wantTags {
+				t.Errorf("SkipTags = %v, want %v", tags, tt.wantTags)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectFileName is the name of the project defaults file
+const ProjectFileName = ".koncurrc"
+
+// ProjectConfig holds project-wide defaults, so CI invocations do not need to
+// repeat the same flags. Flags given on the command line win.
+type ProjectConfig struct {
+	// Target and TargetConfig default --target and --target-config
+	Target       string `yaml:"target,omitempty" validate:"omitempty,oneof=kantra tackle-hub tackle-ui kai-rpc vscode"`
+	TargetConfig string `yaml:"targetConfig,omitempty"`
+
+	// TestDir is where commands look for tests when no path is given (default: tests)
+	TestDir string `yaml:"testDir,omitempty"`
+
+	// OutputFormat and OutputFile default the results format and file of run
+	OutputFormat string `yaml:"outputFormat,omitempty" validate:"omitempty,oneof=console json yaml junit"`
+	OutputFile   string `yaml:"outputFile,omitempty"`
+
+	// ResultsStore defaults --results-store
+	ResultsStore string `yaml:"resultsStore,omitempty"`
+
+	// Validation applies to every test, below the target's and the test's settings
+	Validation ValidationConfig `yaml:"validation,omitempty"`
+
+	// AllowedMismatches applies to tests that do not set expect.allowedMismatches
+	AllowedMismatches *Tolerance `yaml:"allowedMismatches,omitempty"`
+
+	// Flags defaults any other flag, per command, e.g. run: {repeat: "3"}
+	Flags map[string]map[string]string `yaml:"flags,omitempty"`
+
+	path string `yaml:"-"`
+}
+
+// LoadProjectConfig reads a project defaults file. Relative paths in it are
+// relative to the file.
+func LoadProjectConfig(path string) (*ProjectConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project config %s: %w", path, err)
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse project config %s: %w", path, err)
+	}
+	if err := expandEnvNode(&root, ""); err != nil {
+		return nil, fmt.Errorf("failed to parse project config %s: %w", path, err)
+	}
+	var project ProjectConfig
+	if len(root.Content) > 0 {
+		if err := root.Decode(&project); err != nil {
+			return nil, fmt.Errorf("failed to parse project config %s: %w", path, err)
+		}
+	}
+	if err := validate.Struct(&project); err != nil {
+		return nil, fmt.Errorf("invalid project config %s: %w", path, err)
+	}
+	if project.path, err = filepath.Abs(path); err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	for _, p := range []*string{&project.TargetConfig, &project.TestDir, &project.OutputFile, &project.ResultsStore} {
+		if *p != "" && !filepath.IsAbs(*p) {
+			*p = filepath.Join(project.Dir(), *p)
+		}
+	}
+	return &project, nil
+}
+
+// Dir returns the directory of the project defaults file
+func (p *ProjectConfig) Dir() string {
+	return filepath.Dir(p.path)
+}
+
+// Path returns the project defaults file
+func (p *ProjectConfig) Path() string {
+	return p.path
+}
+
+// FindProjectConfig returns the nearest project defaults file in a directory or
+// its parents up to the repository root, or nil
+func FindProjectConfig(dir string) (*ProjectConfig, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+		if _, err := os.Stat(path); err == nil {
+			return LoadProjectConfig(path)
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return nil, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
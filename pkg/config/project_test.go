@@ -0,0 +1,78 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindProjectConfig(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	nested := filepath.Join(root, "tests", "java")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	project, err := FindProjectConfig(nested)
+	if err != nil || project != nil {
+		t.Fatalf("FindProjectConfig() = %v, %v, want no project config", project, err)
+	}
+
+	t.Setenv("KONCUR_TEST_PROJECT_FORMAT", "junit")
+	content := `target: tackle-hub
+targetConfig: .koncur/config/hub.yaml
+testDir: tests
+outputFormat: ${KONCUR_TEST_PROJECT_FORMAT}
+outputFile: /tmp/results.xml
+allowedMismatches: 2%
+validation:
+  strict: true
+flags:
+  run:
+    repeat: "3"
+`
+	if err := os.WriteFile(filepath.Join(root, ProjectFileName), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	project, err = FindProjectConfig(nested)
+	if err != nil {
+		t.Fatalf("FindProjectConfig() error = %v", err)
+	}
+	if project == nil {
+		t.Fatal("expected the project config of the repository root")
+	}
+	if project.TargetConfig != filepath.Join(root, ".koncur/config/hub.yaml") || project.TestDir != filepath.Join(root, "tests") {
+		t.Errorf("expected paths relative to the project config, got %q and %q", project.TargetConfig, project.TestDir)
+	}
+	if project.OutputFile != "/tmp/results.xml" {
+		t.Errorf("expected absolute paths to be kept, got %q", project.OutputFile)
+	}
+	if project.OutputFormat != "junit" || !project.Validation.Strict || project.Flags["run"]["repeat"] != "3" {
+		t.Errorf("unexpected project config %+v", project)
+	}
+	if project.AllowedMismatches == nil {
+		t.Error("expected allowedMismatches to be read")
+	}
+}
+
+func TestLoadProjectConfig_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown target": "target: hub\n",
+		"unknown format": "outputFormat: xml\n",
+		"not a mapping":  "- tests\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ProjectFileName)
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadProjectConfig(path); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaDraft is the JSON Schema version of the generated schemas
+const SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the subset of JSON Schema the test definition and target config schemas
+// use. Fields that suites and base files can supply are not listed as required.
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	ID          string             `json:"$id,omitempty"`
+	Ref         string             `json:"$ref,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        any                `json:"type,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Pattern     string             `json:"pattern,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	// AdditionalProperties is false for structs, or the schema of map values
+	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// schemaProvider is implemented by types decoded from YAML in their own way
+type schemaProvider interface {
+	JSONSchema() *Schema
+}
+
+// legacyUnmarshaler is the unmarshaler of yaml.v2 and the legacy one of yaml.v3
+type legacyUnmarshaler interface {
+	UnmarshalYAML(unmarshal func(interface{}) error) error
+}
+
+var (
+	schemaProviderType    = reflect.TypeOf((*schemaProvider)(nil)).Elem()
+	unmarshalerType       = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+	legacyUnmarshalerType = reflect.TypeOf((*legacyUnmarshaler)(nil)).Elem()
+)
+
+// TestDefinitionSchema returns the JSON Schema of test definitions
+func TestDefinitionSchema() *Schema {
+	s := rootSchema(reflect.TypeOf(TestDefinition{}), "koncur test definition")
+	// Expected violations and insights may count incidents instead of listing them
+	if violation := s.Defs["Violation"]; violation != nil {
+		g := &schemaGenerator{defs: s.Defs, names: map[reflect.Type]string{}}
+		for key, value := range g.structSchema(reflect.TypeOf(incidentCountEntry{})).Properties {
+			violation.Properties[key] = value
+		}
+	}
+	return s
+}
+
+// TargetConfigSchema returns the JSON Schema of target configurations
+func TargetConfigSchema() *Schema {
+	return rootSchema(reflect.TypeOf(TargetConfig{}), "koncur target configuration")
+}
+
+func rootSchema(t reflect.Type, title string) *Schema {
+	g := &schemaGenerator{defs: map[string]*Schema{}, names: map[reflect.Type]string{}}
+	root := g.structSchema(t)
+	root.Schema = SchemaDraft
+	root.Title = title
+	root.Defs = g.defs
+	return root
+}
+
+// schemaGenerator builds schemas from Go types, by their YAML names. Named structs
+// other than the root are shared definitions, so recursive types terminate.
+type schemaGenerator struct {
+	defs  map[string]*Schema
+	names map[reflect.Type]string
+}
+
+func (g *schemaGenerator) schema(t reflect.Type, validateTag string) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Implements(schemaProviderType) {
+		return reflect.Zero(t).Interface().(schemaProvider).JSONSchema()
+	}
+	// Types decoded in their own way accept anything the schema cannot know about
+	ptr := reflect.PointerTo(t)
+	if t == reflect.TypeOf(yaml.Node{}) || ptr.Implements(unmarshalerType) || ptr.Implements(legacyUnmarshalerType) {
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string", Enum: oneOf(validateTag)}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		_, dive, _ := strings.Cut(validateTag, "dive,")
+		return &Schema{Type: "array", Items: g.schema(t.Elem(), dive)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem(), "")}
+	case reflect.Struct:
+		return g.ref(t)
+	}
+	return &Schema{}
+}
+
+// ref returns a reference to the definition of a named struct, adding it first
+func (g *schemaGenerator) ref(t reflect.Type) *Schema {
+	name, ok := g.names[t]
+	if !ok {
+		name = t.Name()
+		if _, taken := g.defs[name]; taken || name == "" {
+			name = strings.ReplaceAll(t.String(), ".", "_")
+		}
+		g.names[t] = name
+		g.defs[name] = &Schema{}
+		*g.defs[name] = *g.structSchema(t)
+	}
+	return &Schema{Ref: "#/$defs/" + name}
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: false}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("yaml")
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(options, "inline") || field.Anonymous && tag == "" {
+			inline := field.Type
+			for inline.Kind() == reflect.Pointer {
+				inline = inline.Elem()
+			}
+			if inline.Kind() == reflect.Struct && !reflect.PointerTo(inline).Implements(legacyUnmarshalerType) {
+				for key, value := range g.structSchema(inline).Properties {
+					s.Properties[key] = value
+				}
+				continue
+			}
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		s.Properties[name] = g.schema(field.Type, field.Tag.Get("validate"))
+	}
+	return s
+}
+
+// oneOf returns the values of a oneof validation, as an enum
+func oneOf(validateTag string) []string {
+	for _, rule := range strings.Split(validateTag, ",") {
+		if values, ok := strings.CutPrefix(rule, "oneof="); ok {
+			return strings.Fields(values)
+		}
+	}
+	return nil
+}
+
+// JSONSchema describes durations such as "5m" or "1h30m"
+func (Duration) JSONSchema() *Schema {
+	return &Schema{Type: "string", Pattern: `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`}
+}
+
+// JSONSchema describes absolute or percentage tolerances
+func (Tolerance) JSONSchema() *Schema {
+	return &Schema{AnyOf: []*Schema{{Type: "number"}, {Type: "string", Pattern: `^[0-9]+(\.[0-9]+)?%?$`}}}
+}
+
+// JSONSchema describes a matrix: analysis settings mapped to lists of values
+func (Matrix) JSONSchema() *Schema {
+	value := &Schema{AnyOf: []*Schema{{Type: "string"}, {Type: "array", Items: &Schema{Type: "string"}}}}
+	return &Schema{Type: "object", AdditionalProperties: &Schema{Type: "array", Items: value}}
+}
+
+// UnknownFields returns the keys of a YAML document that its schema does not
+// define, as "line N: unknown field analysis.foo"
+func UnknownFields(data []byte, schema *Schema) ([]string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	var unknown []string
+	for _, node := range doc.Content {
+		unknownFields(node, schema, schema.Defs, "", &unknown)
+	}
+	return unknown, nil
+}
+
+// CheckUnknownFields returns an error listing the fields of a YAML file that its
+// schema does not define
+func CheckUnknownFields(path string, schema *Schema) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	unknown, err := UnknownFields(data, schema)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("%s has unknown fields:\n  %s", path, strings.Join(unknown, "\n  "))
+	}
+	return nil
+}
+
+func unknownFields(node *yaml.Node, schema *Schema, defs map[string]*Schema, path string, unknown *[]string) {
+	if schema.Ref != "" {
+		schema = defs[strings.TrimPrefix(schema.Ref, "#/$defs/")]
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		if schema.Type != "object" {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			keyPath := key.Value
+			if path != "" {
+				keyPath = path + "." + key.Value
+			}
+			if property, ok := schema.Properties[key.Value]; ok {
+				unknownFields(value, property, defs, keyPath, unknown)
+			} else if values, ok := schema.AdditionalProperties.(*Schema); ok {
+				unknownFields(value, values, defs, keyPath, unknown)
+			} else if schema.AdditionalProperties == false {
+				*unknown = append(*unknown, fmt.Sprintf("line %d: unknown field %s", key.Line, keyPath))
+			}
+		}
+	case yaml.SequenceNode:
+		if schema.Items == nil {
+			return
+		}
+		for i, item := range node.Content {
+			unknownFields(item, schema.Items, defs, fmt.Sprintf("%s[%d]", path, i), unknown)
+		}
+	}
+}
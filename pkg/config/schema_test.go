@@ -0,0 +1,63 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnknownFields(t *testing.T) {
+	content := `name: typo
+extends: base.yaml
+analysis:
+  application: app.war
+  labelSelectr: konveyor.io/target=quarkus
+matrix:
+  target: [quarkus, [eap8, jakarta-ee]]
+skip:
+  reason: flaky
+  unless:
+    mavenSettings: true
+expect:
+  output:
+    result:
+      - name: rs
+        violations:
+          rule-001:
+            incidentCount: 3
+            incidentCountTolerance: 10%
+            incidnets: []
+validation:
+  rulesets:
+    rs:
+      strict: true
+      ignoreLineNumber: true
+timeOut: 5m
+`
+	unknown, err := UnknownFields([]byte(content), TestDefinitionSchema())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"line 5: unknown field analysis.labelSelectr",
+		"line 20: unknown field expect.output.result[0].violations.rule-001.incidnets",
+		"line 25: unknown field validation.rulesets.rs.ignoreLineNumber",
+		"line 26: unknown field timeOut",
+	}
+	if !reflect.DeepEqual(unknown, want) {
+		t.Errorf("UnknownFields() = %v, want %v", unknown, want)
+	}
+}
+
+func TestTargetConfigSchema(t *testing.T) {
+	schema := TargetConfigSchema()
+	if got := schema.Properties["type"].Enum; !reflect.DeepEqual(got, []string{"kantra", "tackle-hub", "tackle-ui", "kai-rpc", "vscode"}) {
+		t.Errorf("type enum = %v", got)
+	}
+	unknown, err := UnknownFields([]byte("type: kantra\nkantra:\n  binaryPath: kantra\n  mavenSetings: settings.xml\n"), schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"line 4: unknown field kantra.mavenSetings"}; !reflect.DeepEqual(unknown, want) {
+		t.Errorf("UnknownFields() = %v, want %v", unknown, want)
+	}
+}
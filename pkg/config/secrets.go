@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/konveyor/test-harness/pkg/util"
+)
+
+// IsSecretRef reports whether a value references a secret ("env:NAME" or "file:path")
+func IsSecretRef(value string) bool {
+	return strings.HasPrefix(value, "env:") || strings.HasPrefix(value, "file:")
+}
+
+// ResolveSecret reads the secret referenced by ref. "env:NAME" reads an environment
+// variable and "file:path" reads a file, relative paths resolving against baseDir.
+// The resolved value is registered for redaction in logs.
+func ResolveSecret(ref, baseDir string) (string, error) {
+	var value string
+	switch {
+	case strings.HasPrefix(ref, "env:"):
+		name := strings.TrimPrefix(ref, "env:")
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		value = v
+	case strings.HasPrefix(ref, "file:"):
+		path := strings.TrimPrefix(ref, "file:")
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(baseDir, path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file: %w", err)
+		}
+		value = strings.TrimRight(string(data), "\r\n")
+	default:
+		return "", fmt.Errorf("invalid secret reference %q: must start with env: or file:", ref)
+	}
+	util.RegisterSecret(value)
+	return value, nil
+}
+
+// resolveSecrets fills in credentials referenced by *From fields and registers
+// inline credentials for redaction
+func (tc *TargetConfig) resolveSecrets(baseDir string) error {
+	resolve := func(field string, value *string, ref string) error {
+		if ref == "" {
+			util.RegisterSecret(*value)
+			return nil
+		}
+		v, err := ResolveSecret(ref, baseDir)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", field, err)
+		}
+		*value = v
+		return nil
+	}
+
+	if hub := tc.TackleHub; hub != nil {
+		if err := resolve("tackleHub.tokenFrom", &hub.Token, hub.TokenFrom); err != nil {
+			return err
+		}
+		if err := resolve("tackleHub.passwordFrom", &hub.Password, hub.PasswordFrom); err != nil {
+			return err
+		}
+	}
+	if ui := tc.TackleUI; ui != nil {
+		if err := resolve("tackleUI.passwordFrom", &ui.Password, ui.PasswordFrom); err != nil {
+			return err
+		}
+	}
+	return nil
+}
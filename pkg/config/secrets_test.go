@@ -0,0 +1,56 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadTargetConfigResolvesSecrets(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "hub-password"), []byte("s3cr3t-pass\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KONCUR_TEST_HUB_TOKEN", "token-from-env")
+
+	path := filepath.Join(dir, "target.yaml")
+	content := `type: tackle-hub
+tackleHub:
+  url: http://localhost:8081
+  username: admin
+  tokenFrom: env:KONCUR_TEST_HUB_TOKEN
+  passwordFrom: file:hub-password
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadTargetConfig(path)
+	if err != nil {
+		t.Fatalf("LoadTargetConfig() error = %v", err)
+	}
+	if cfg.TackleHub.Token != "token-from-env" {
+		t.Errorf("Token = %q, want value from environment", cfg.TackleHub.Token)
+	}
+	if cfg.TackleHub.Password != "s3cr3t-pass" {
+		t.Errorf("Password = %q, want value from file without trailing newline", cfg.TackleHub.Password)
+	}
+}
+
+func TestResolveSecretErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		ref  string
+	}{
+		{"unset env", "env:KONCUR_TEST_DOES_NOT_EXIST"},
+		{"missing file", "file:/does/not/exist"},
+		{"unknown scheme", "vault:secret/hub"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ResolveSecret(tt.ref, t.TempDir()); err == nil {
+				t.Errorf("ResolveSecret(%q) expected error", tt.ref)
+			}
+		})
+	}
+}
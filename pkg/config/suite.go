@@ -0,0 +1,161 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SuiteFileName is the name of suite files
+const SuiteFileName = "suite.yaml"
+
+// Suite groups the tests below its directory and supplies shared defaults for them
+type Suite struct {
+	Name        string `yaml:"name,omitempty"`
+	Description string `yaml:"description,omitempty"`
+
+	// Tests are the test files and directories the suite runs, relative to the suite
+	// file and inside its directory. Empty runs every test below the directory.
+	Tests []string `yaml:"tests,omitempty"`
+
+	// Defaults has the shape of a test definition. Values set by a test win, except
+	// tags, which are combined.
+	Defaults yaml.Node `yaml:"defaults,omitempty"`
+
+	path string `yaml:"-"`
+}
+
+// IsSuiteFile reports whether a path names a suite file
+func IsSuiteFile(path string) bool {
+	return filepath.Base(path) == SuiteFileName
+}
+
+// LoadSuite reads and checks a suite file
+func LoadSuite(path string) (*Suite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read suite file %s: %w", path, err)
+	}
+	var suite Suite
+	if err := yaml.Unmarshal(data, &suite); err != nil {
+		return nil, fmt.Errorf("failed to parse suite YAML %s: %w", path, err)
+	}
+	if suite.path, err = filepath.Abs(path); err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	if !suite.Defaults.IsZero() {
+		if suite.Defaults.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("defaults of suite %s must be a mapping", path)
+		}
+		var defaults TestDefinition
+		if err := suite.Defaults.Decode(&defaults); err != nil {
+			return nil, fmt.Errorf("invalid defaults in suite %s: %w", path, err)
+		}
+		if defaults.Name != "" {
+			return nil, fmt.Errorf("defaults of suite %s cannot set a test name", path)
+		}
+	}
+
+	dir := suite.Dir()
+	for _, test := range suite.Tests {
+		resolved := suite.resolve(test)
+		if resolved == suite.path {
+			return nil, fmt.Errorf("suite %s cannot list itself", path)
+		}
+		if rel, err := filepath.Rel(dir, resolved); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("test %s of suite %s is outside the suite directory", test, path)
+		}
+	}
+	return &suite, nil
+}
+
+// Dir returns the directory of the suite file
+func (s *Suite) Dir() string {
+	return filepath.Dir(s.path)
+}
+
+// TestPaths returns the test files and directories the suite runs
+func (s *Suite) TestPaths() []string {
+	if len(s.Tests) == 0 {
+		return []string{s.Dir()}
+	}
+	paths := make([]string, 0, len(s.Tests))
+	for _, test := range s.Tests {
+		paths = append(paths, s.resolve(test))
+	}
+	return paths
+}
+
+func (s *Suite) resolve(path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(s.Dir(), path)
+}
+
+// FindSuite returns the suite a test file belongs to, the nearest suite file in the
+// test's directory or its parents up to the repository root, or nil
+func FindSuite(testFile string) (*Suite, error) {
+	dir, err := filepath.Abs(filepath.Dir(testFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	for {
+		path := filepath.Join(dir, SuiteFileName)
+		if _, err := os.Stat(path); err == nil {
+			return LoadSuite(path)
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return nil, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// apply merges the suite defaults into the top-level mapping of a test definition
+func (s *Suite) apply(test *yaml.Node) {
+	if s == nil || s.Defaults.IsZero() || test.Kind != yaml.MappingNode {
+		return
+	}
+	mergeDefaults(&s.Defaults, test, true)
+}
+
+// mergeDefaults adds the keys of defaults missing from node, recursing into
+// mappings both have. Top-level tags are combined.
+func mergeDefaults(defaults, node *yaml.Node, topLevel bool) {
+	for i := 0; i+1 < len(defaults.Content); i += 2 {
+		key, value := defaults.Content[i], defaults.Content[i+1]
+		existing := mappingValue(node, key.Value)
+		switch {
+		case existing == nil:
+			node.Content = append(node.Content, key, value)
+		case existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			mergeDefaults(value, existing, false)
+		case topLevel && key.Value == "tags" && existing.Kind == yaml.SequenceNode && value.Kind == yaml.SequenceNode:
+			var tags []*yaml.Node
+			for _, tag := range value.Content {
+				if !containsScalar(existing, tag.Value) {
+					tags = append(tags, tag)
+				}
+			}
+			existing.Content = append(tags, existing.Content...)
+		}
+	}
+}
+
+func containsScalar(sequence *yaml.Node, value string) bool {
+	for _, item := range sequence.Content {
+		if item.Value == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSuiteDefaults(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"suite.yaml": `name: java
+tests:
+  - daytrader
+defaults:
+  tags: [java, tier0]
+  timeout: 30m
+  requireMavenSettings: true
+  analysis:
+    labelSelector: konveyor.io/target=quarkus
+    analysisMode: source-only
+`,
+		"daytrader/test.yaml": `name: daytrader
+tags: [tier0, slow]
+analysis:
+  application: ./app
+  analysisMode: full
+expect:
+  exitCode: 0
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	testFile := filepath.Join(dir, "daytrader", "test.yaml")
+
+	test, err := LoadWithOptions(testFile, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"java", "tier0", "slow"}; !reflect.DeepEqual(test.Tags, want) {
+		t.Errorf("tags = %v, want %v", test.Tags, want)
+	}
+	if test.GetTimeout() != 30*time.Minute || !test.RequireMavenSettings {
+		t.Errorf("expected the suite timeout and maven settings requirement, got %v and %v", test.GetTimeout(), test.RequireMavenSettings)
+	}
+	if test.Analysis.LabelSelector != "konveyor.io/target=quarkus" {
+		t.Errorf("expected the suite label selector, got %q", test.Analysis.LabelSelector)
+	}
+	if test.Analysis.AnalysisMode != "full" {
+		t.Errorf("expected the test's analysis mode to win, got %q", test.Analysis.AnalysisMode)
+	}
+
+	tags, err := LoadTags(testFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tags, test.Tags) {
+		t.Errorf("LoadTags() = %v, want %v", tags, test.Tags)
+	}
+
+	if err := SaveExpectations(testFile, ExitCode(1), "expected-output.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	var own TestDefinition
+	data, err := os.ReadFile(testFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := yaml.Unmarshal(data, &own); err != nil {
+		t.Fatal(err)
+	}
+	if own.Timeout != nil || len(own.Tags) != 2 || own.Analysis.LabelSelector != "" {
+		t.Errorf("expected the test as written, got timeout %v, tags %v and label selector %q", own.Timeout, own.Tags, own.Analysis.LabelSelector)
+	}
+	if !reflect.DeepEqual(own.Expect.ExitCode, ExitCode(1)) || own.Expect.Output.File != "expected-output.yaml" {
+		t.Errorf("expected the new expectations, got %+v", own.Expect)
+	}
+
+	suite, err := LoadSuite(filepath.Join(dir, "suite.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paths := suite.TestPaths(); len(paths) != 1 || paths[0] != filepath.Join(dir, "daytrader") {
+		t.Errorf("TestPaths() = %v", paths)
+	}
+}
+
+func TestLoadSuite_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"outside directory": "tests: [../other]\n",
+		"lists itself":      "tests: [suite.yaml]\n",
+		"test name":         "defaults:\n  name: shared\n",
+		"invalid default":   "defaults:\n  timeout: soon\n",
+		"defaults list":     "defaults: [a]\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), SuiteFileName)
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadSuite(path); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
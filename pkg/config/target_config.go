@@ -2,13 +2,20 @@ package config
 
 import (
 	"fmt"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
 
 	"gopkg.in/yaml.v3"
 )
 
 // TargetConfig defines how to execute tests (separate from test definitions)
 type TargetConfig struct {
+	// Extends names a base target config, relative to this one, whose settings
+	// apply unless this one sets them
+	Extends string `yaml:"extends,omitempty"`
+
 	// Type specifies the target: kantra, tackle-hub, tackle-ui, kai-rpc, vscode
 	Type string `yaml:"type" validate:"required,oneof=kantra tackle-hub tackle-ui kai-rpc vscode"`
 
@@ -26,20 +33,78 @@ type TargetConfig struct {
 
 	// VSCode extension configuration
 	VSCode *VSCodeConfig `yaml:"vscode,omitempty"`
+
+	// Validation defaults for every test run against this target
+	Validation ValidationConfig `yaml:"validation,omitempty"`
+
+	// Providers lists the analysis providers the target has, e.g. [java, nodejs],
+	// for tests that require providers. Empty asks the target, when it can tell.
+	Providers []string `yaml:"providers,omitempty" validate:"dive,required"`
+
+	// ExitCodes maps the target's exit codes onto the exit statuses tests can
+	// expect (success-clean, success-with-findings, execution-error)
+	ExitCodes *ExitCodeMapping `yaml:"exitCodes,omitempty"`
 }
 
 // KantraConfig for Kantra CLI execution
 type KantraConfig struct {
 	BinaryPath    string `yaml:"binaryPath,omitempty"`
 	MavenSettings string `yaml:"mavenSettings,omitempty"`
+	KantraImages  `yaml:",inline"`
+}
+
+// KantraImages select the container images kantra analyzes with, e.g. candidate
+// analyzer images to verify before a release
+type KantraImages struct {
+	// Image is the runner image analyses run in (kantra's RUNNER_IMG)
+	Image string `yaml:"image,omitempty"`
+	// Settings are further kantra settings, passed as environment variables,
+	// e.g. JAVA_PROVIDER_IMG or CONTAINER_TOOL
+	Settings map[string]string `yaml:"settings,omitempty"`
+}
+
+// Merge returns k with the image and settings of override applied on top
+func (k KantraImages) Merge(override *KantraImages) KantraImages {
+	if override == nil {
+		return k
+	}
+	merged := KantraImages{Image: k.Image, Settings: maps.Clone(k.Settings)}
+	if override.Image != "" {
+		merged.Image = override.Image
+	}
+	if len(override.Settings) > 0 && merged.Settings == nil {
+		merged.Settings = map[string]string{}
+	}
+	maps.Copy(merged.Settings, override.Settings)
+	return merged
+}
+
+// Env returns the images and settings as the environment variables kantra reads,
+// sorted by name
+func (k KantraImages) Env() []string {
+	settings := maps.Clone(k.Settings)
+	if k.Image != "" {
+		if settings == nil {
+			settings = map[string]string{}
+		}
+		settings["RUNNER_IMG"] = k.Image
+	}
+	var env []string
+	for _, name := range slices.Sorted(maps.Keys(settings)) {
+		env = append(env, name+"="+settings[name])
+	}
+	return env
 }
 
 // TackleHubConfig for Tackle Hub API execution
 type TackleHubConfig struct {
-	URL           string `yaml:"url" validate:"required"`
-	Username      string `yaml:"username,omitempty"`
-	Password      string `yaml:"password,omitempty"`
-	Token         string `yaml:"token,omitempty"`
+	URL      string `yaml:"url" validate:"required"`
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	Token    string `yaml:"token,omitempty"`
+	// PasswordFrom and TokenFrom reference secrets instead of inlining them (env:NAME or file:path)
+	PasswordFrom  string `yaml:"passwordFrom,omitempty"`
+	TokenFrom     string `yaml:"tokenFrom,omitempty"`
 	MavenSettings string `yaml:"mavenSettings,omitempty"`
 }
 
@@ -47,9 +112,11 @@ type TackleHubConfig struct {
 type TackleUIConfig struct {
 	URL      string `yaml:"url" validate:"required"`
 	Username string `yaml:"username" validate:"required"`
-	Password string `yaml:"password" validate:"required"`
-	Browser  string `yaml:"browser,omitempty"` // chrome, firefox
-	Headless bool   `yaml:"headless,omitempty"`
+	Password string `yaml:"password,omitempty" validate:"required_without=PasswordFrom"`
+	// PasswordFrom references the password instead of inlining it (env:NAME or file:path)
+	PasswordFrom string `yaml:"passwordFrom,omitempty"`
+	Browser      string `yaml:"browser,omitempty"` // chrome, firefox
+	Headless     bool   `yaml:"headless,omitempty"`
 }
 
 // KaiRPCConfig for Kai analyzer RPC
@@ -72,10 +139,26 @@ func LoadTargetConfig(path string) (*TargetConfig, error) {
 		return nil, fmt.Errorf("failed to read target config file %s: %w", path, err)
 	}
 
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse target config YAML: %w", err)
+	}
+	if len(doc.Content) > 0 {
+		if _, err := applyExtends(doc.Content[0], path); err != nil {
+			return nil, fmt.Errorf("failed to load target config %s: %w", path, err)
+		}
+	}
+	if err := expandEnvNode(&doc, ""); err != nil {
+		return nil, fmt.Errorf("failed to load target config %s: %w", path, err)
+	}
 	var targetConfig TargetConfig
-	if err := yaml.Unmarshal(data, &targetConfig); err != nil {
+	if err := doc.Decode(&targetConfig); err != nil {
 		return nil, fmt.Errorf("failed to parse target config YAML: %w", err)
 	}
 
+	if err := targetConfig.resolveSecrets(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+
 	return &targetConfig, nil
 }
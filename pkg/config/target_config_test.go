@@ -0,0 +1,42 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKantraImages(t *testing.T) {
+	target := KantraImages{
+		Image:    "quay.io/konveyor/kantra:latest",
+		Settings: map[string]string{"JAVA_PROVIDER_IMG": "quay.io/konveyor/java-external-provider:latest", "CONTAINER_TOOL": "podman"},
+	}
+	tests := []struct {
+		name     string
+		override *KantraImages
+		want     []string
+	}{
+		{"target images", nil, []string{
+			"CONTAINER_TOOL=podman",
+			"JAVA_PROVIDER_IMG=quay.io/konveyor/java-external-provider:latest",
+			"RUNNER_IMG=quay.io/konveyor/kantra:latest",
+		}},
+		{"test overrides", &KantraImages{Image: "quay.io/konveyor/kantra:v0.8.0-rc.1", Settings: map[string]string{"JAVA_PROVIDER_IMG": "localhost/java-provider:dev"}}, []string{
+			"CONTAINER_TOOL=podman",
+			"JAVA_PROVIDER_IMG=localhost/java-provider:dev",
+			"RUNNER_IMG=quay.io/konveyor/kantra:v0.8.0-rc.1",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := target.Merge(tt.override).Env(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Env() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if target.Settings["JAVA_PROVIDER_IMG"] != "quay.io/konveyor/java-external-provider:latest" {
+		t.Error("Merge() changed the target's settings")
+	}
+	if env := (KantraImages{}).Env(); env != nil {
+		t.Errorf("expected no environment without images, got %v", env)
+	}
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Tolerance is an allowed deviation given either as an absolute number ("2")
+// or as a percentage of the expected value ("10%")
+type Tolerance struct {
+	Value   float64
+	Percent bool
+}
+
+// ParseTolerance parses an absolute or percentage tolerance
+func ParseTolerance(s string) (Tolerance, error) {
+	s = strings.TrimSpace(s)
+	percent := strings.HasSuffix(s, "%")
+	value, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil {
+		return Tolerance{}, fmt.Errorf("invalid tolerance %q: must be a number or a percentage", s)
+	}
+	if value < 0 {
+		return Tolerance{}, fmt.Errorf("invalid tolerance %q: must not be negative", s)
+	}
+	return Tolerance{Value: value, Percent: percent}, nil
+}
+
+// Allowed returns the absolute deviation allowed around the expected value
+func (t Tolerance) Allowed(expected int) float64 {
+	if t.Percent {
+		return float64(expected) * t.Value / 100
+	}
+	return t.Value
+}
+
+// Allows reports whether actual is within the tolerance of expected
+func (t Tolerance) Allows(expected, actual int) bool {
+	diff := float64(actual - expected)
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= t.Allowed(expected)
+}
+
+// String formats the tolerance as it is written in YAML
+func (t Tolerance) String() string {
+	s := strconv.FormatFloat(t.Value, 'f', -1, 64)
+	if t.Percent {
+		s += "%"
+	}
+	return s
+}
+
+// UnmarshalYAML implements custom unmarshaling for numeric or percentage tolerances
+func (t *Tolerance) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	parsed, err := ParseTolerance(s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// MarshalYAML implements custom marshaling for Tolerance
+func (t Tolerance) MarshalYAML() (interface{}, error) {
+	if t.Percent {
+		return t.String(), nil
+	}
+	return t.Value, nil
+}
@@ -1,7 +1,11 @@
 package config
 
 import (
+	"fmt"
+	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 	"time"
 
@@ -14,19 +18,68 @@ type TestDefinition struct {
 	Name        string `yaml:"name" validate:"required"`
 	Description string `yaml:"description,omitempty"`
 
+	// Extends names a base file, relative to the test, whose settings the test
+	// inherits. Values set by the test win; tags are combined.
+	Extends string `yaml:"extends,omitempty"`
+
+	// Tags group tests for selection, e.g. 'koncur run tests --tags tier0'
+	Tags []string `yaml:"tags,omitempty"`
+
+	// Kind is what the test runs: an analysis (default), list-labels, which lists
+	// the source and target labels of the rulesets instead of analyzing anything,
+	// or transform, which runs kantra transform
+	Kind string `yaml:"kind,omitempty" validate:"omitempty,oneof=analysis list-labels transform"`
+
 	// Analysis configuration - what to analyze
 	Analysis AnalysisConfig `yaml:"analysis" validate:"required"`
 
+	// Transform is what a transform test runs kantra transform on
+	Transform *TransformConfig `yaml:"transform,omitempty"`
+
+	// Fixes requests fix suggestions for incidents of the analysis, e.g. from Kai,
+	// and validates their structure
+	Fixes *FixConfig `yaml:"fixes,omitempty"`
+
+	// Matrix runs the test once per combination of analysis settings
+	Matrix Matrix `yaml:"matrix,omitempty"`
+
+	// Skip marks the test as skipped, on every target or the listed ones
+	Skip *SkipConfig `yaml:"skip,omitempty"`
+
+	// Requires lists what the target must have to run the test; tests whose
+	// requirements are not met are skipped
+	Requires *Requirements `yaml:"requires,omitempty"`
+
 	// Optional execution settings
 	Timeout              *Duration `yaml:"timeout,omitempty"`
 	WorkDir              string    `yaml:"workDir,omitempty"`
 	RequireMavenSettings bool      `yaml:"requireMavenSettings,omitempty"`
+	// MavenSettings is a maven settings file for this test, relative to it, used
+	// instead of the target's. ${env:NAME} and ${file:path} references in it are
+	// resolved at run time.
+	MavenSettings string `yaml:"mavenSettings,omitempty"`
+	// Kantra overrides the kantra target's image and settings for this test
+	Kantra *KantraImages `yaml:"kantra,omitempty"`
 
 	// Validation configuration
 	Expect ExpectConfig `yaml:"expect" validate:"required"`
 
+	// Optional tuning of how output is compared against expectations
+	Validation ValidationConfig `yaml:"validation,omitempty"`
+
 	// Internal field - path to the test file (not in YAML)
 	testFilePath string `yaml:"-"`
+
+	// Internal field - the matrix variant the test was loaded as (not in YAML)
+	variant string `yaml:"-"`
+
+	// Internal field - the base files the test extends (not in YAML)
+	bases []string `yaml:"-"`
+}
+
+// BaseFiles returns the absolute paths of the base files the test extends
+func (t *TestDefinition) BaseFiles() []string {
+	return t.bases
 }
 
 // SetTestFilePath sets the test file path
@@ -42,12 +95,147 @@ func (t *TestDefinition) GetTestDir() string {
 	return filepath.Dir(t.testFilePath)
 }
 
+// SkipConfig says why a test is skipped and where. The skip applies when every
+// condition set holds; a skip without conditions always applies.
+type SkipConfig struct {
+	Reason string `yaml:"reason,omitempty"`
+	// Issue links the issue tracking the skip
+	Issue string `yaml:"issue,omitempty"`
+	// Targets limits the skip to these target types; empty skips on every target
+	Targets []string `yaml:"targets,omitempty" validate:"dive,oneof=kantra tackle-hub tackle-ui kai-rpc vscode"`
+	// Arch and OS limit the skip to these architectures and operating systems, as
+	// named by Go (e.g. arm64, darwin)
+	Arch []string `yaml:"arch,omitempty" validate:"dive,required"`
+	OS   []string `yaml:"os,omitempty" validate:"dive,required"`
+	// Unless runs the test anyway when the environment provides what it needs
+	Unless *SkipUnless `yaml:"unless,omitempty"`
+}
+
+// SkipUnless lists what a test needs to run
+type SkipUnless struct {
+	// MavenSettings needs a maven settings file in the target configuration
+	MavenSettings bool `yaml:"mavenSettings,omitempty"`
+	// Env needs these environment variables to be set
+	Env []string `yaml:"env,omitempty" validate:"dive,required"`
+}
+
+// Requirements are what a test needs from the target
+type Requirements struct {
+	// Providers are the analysis providers the test needs, e.g. [java, dotnet]
+	Providers []string `yaml:"providers,omitempty" validate:"dive,required"`
+}
+
+// Missing returns the required providers the environment does not have. Nothing
+// is missing when the providers of the target are unknown.
+func (r *Requirements) Missing(env SkipEnvironment) []string {
+	if r == nil || env.Providers == nil {
+		return nil
+	}
+	var missing []string
+	for _, p := range r.Providers {
+		if !slices.Contains(env.Providers, p) {
+			missing = append(missing, p)
+		}
+	}
+	return missing
+}
+
+// SkipEnvironment is what skip conditions are evaluated against at run time
+type SkipEnvironment struct {
+	TargetType    string
+	MavenSettings bool
+	OS            string
+	Arch          string
+	// Providers are the analysis providers of the target, nil when unknown
+	Providers []string
+}
+
+// NewSkipEnvironment describes running against a target on this machine
+func NewSkipEnvironment(target *TargetConfig) SkipEnvironment {
+	env := SkipEnvironment{TargetType: target.Type, OS: runtime.GOOS, Arch: runtime.GOARCH, Providers: target.Providers}
+	if target.Kantra != nil && target.Kantra.MavenSettings != "" ||
+		target.TackleHub != nil && target.TackleHub.MavenSettings != "" {
+		env.MavenSettings = true
+	}
+	return env
+}
+
+// Applies reports whether the test is skipped in an environment. Without a target
+// type, e.g. when listing tests, any skip applies.
+func (s *SkipConfig) Applies(env SkipEnvironment) bool {
+	if s == nil {
+		return false
+	}
+	if env.TargetType == "" {
+		return true
+	}
+	if len(s.Targets) > 0 && !slices.Contains(s.Targets, env.TargetType) ||
+		len(s.Arch) > 0 && !slices.Contains(s.Arch, env.Arch) ||
+		len(s.OS) > 0 && !slices.Contains(s.OS, env.OS) {
+		return false
+	}
+	return s.Unless == nil || !s.Unless.met(env)
+}
+
+func (u *SkipUnless) met(env SkipEnvironment) bool {
+	if u.MavenSettings && !env.MavenSettings {
+		return false
+	}
+	for _, name := range u.Env {
+		if os.Getenv(name) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Conditions describes when the skip applies, e.g. "target tackle-hub" and
+// "unless maven settings"
+func (s *SkipConfig) Conditions() []string {
+	var conditions []string
+	if len(s.Targets) > 0 {
+		conditions = append(conditions, "target "+strings.Join(s.Targets, " or "))
+	}
+	if len(s.Arch) > 0 {
+		conditions = append(conditions, "arch "+strings.Join(s.Arch, " or "))
+	}
+	if len(s.OS) > 0 {
+		conditions = append(conditions, "os "+strings.Join(s.OS, " or "))
+	}
+	if s.Unless != nil {
+		if s.Unless.MavenSettings {
+			conditions = append(conditions, "unless maven settings")
+		}
+		for _, name := range s.Unless.Env {
+			conditions = append(conditions, "unless $"+name)
+		}
+	}
+	return conditions
+}
+
+// String describes the skip with its reason and issue
+func (s *SkipConfig) String() string {
+	switch {
+	case s.Reason != "" && s.Issue != "":
+		return fmt.Sprintf("%s (%s)", s.Reason, s.Issue)
+	case s.Reason != "":
+		return s.Reason
+	}
+	return s.Issue
+}
+
 // AnalysisConfig defines what to analyze
 type AnalysisConfig struct {
-	// Application is either a file path or git repository URL
-	Application         string                `json:"application" yaml:"application" validate:"required" `
-	LabelSelector       string                `json:"label_selector" yaml:"labelSelector,omitempty" `
-	KnownLibs           bool                  `json:"known_libs" yaml:"knownLibs,omitempty"`
+	// Application is either a file path, a git repository URL, or an alias of the
+	// application registry ("alias:name")
+	Application   string `json:"application" yaml:"application,omitempty" validate:"required_without=Applications,excluded_with=Applications" `
+	LabelSelector string `json:"label_selector" yaml:"labelSelector,omitempty" `
+	// KnownLibs also analyzes the dependencies labeled konveyor.io/dep-source=open-source,
+	// which kantra and the Hub exclude by default
+	KnownLibs bool `json:"known_libs" yaml:"knownLibs,omitempty"`
+	// DepLabelSelector selects the dependencies analyzed by their labels, such as
+	// konveyor.io/dep-source=internal
+	DepLabelSelector    string                `json:"dep_label_selector,omitempty" yaml:"depLabelSelector,omitempty"`
 	ContextLines        int                   `json:"context_lines" yaml:"context_lines"`
 	IncidentSelector    string                `json:"incident_selector" yaml:"incident_selector"`
 	Source              []string              `json:"source" yaml:"source"`
@@ -56,11 +244,93 @@ type AnalysisConfig struct {
 	DisableDefaultRules bool                  `json:"disableDefaultRules" yaml:"disableDefaultRules"`
 	AnalysisMode        provider.AnalysisMode `json:"analysis_mode" yaml:"analysisMode" validate:"required" `
 
+	// EnableDefaultRulesets set to false analyzes with the test's rules only, without the
+	// default ruleset catalog of kantra and the Hub. It supersedes disableDefaultRules.
+	EnableDefaultRulesets *bool `json:"enableDefaultRulesets,omitempty" yaml:"enableDefaultRulesets,omitempty"`
+
+	// Applications are analyzed together in one operation, as users run bulk
+	// analyses, instead of Application. Each has its own expected output in
+	// expect.applications.
+	Applications []ApplicationInput `json:"applications,omitempty" yaml:"applications,omitempty" validate:"omitempty,unique=Name,dive"`
+
+	// StaticReport has kantra generate its HTML static report, which tests skip for
+	// speed by default, and fails the analysis when no report was written
+	StaticReport bool `json:"staticReport,omitempty" yaml:"staticReport,omitempty"`
+
+	// Bulk analyzes the applications with kantra's bulk mode into one output
+	// directory, split per application from the combined static report, instead
+	// of one analysis each. The Hub always analyzes them in one task group.
+	Bulk bool `json:"bulk,omitempty" yaml:"bulk,omitempty" validate:"excluded_without=Applications"`
+
+	// IncidentLimit caps the incidents of each rule, and CodeSnipLimit the incidents
+	// of each rule with a code snip (default: kantra's). Outputs are validated as
+	// capped: violations at the limit only compare the incidents the analyzer kept.
+	IncidentLimit int `json:"incidentLimit,omitempty" yaml:"incidentLimit,omitempty" validate:"gte=0"`
+	CodeSnipLimit int `json:"codeSnipLimit,omitempty" yaml:"codeSnipLimit,omitempty" validate:"gte=0"`
+
+	// ProviderOverrides override the settings of analysis providers, e.g. java's
+	// bundles or dotnet's framework versions. Kantra gets them as a settings file
+	// (--override-provider-settings); the Hub cannot run them.
+	ProviderOverrides []ProviderOverride `json:"providerOverrides,omitempty" yaml:"providerOverrides,omitempty" validate:"omitempty,unique=Name,dive"`
+
+	// ApplicationAlias is the registry alias the application was given as (not in YAML)
+	ApplicationAlias string `yaml:"-" json:"-"`
+
+	// SourceDir is the directory of the test git applications are cloned into (not
+	// in YAML); each application of a test analyzing several has its own
+	SourceDir string `yaml:"-" json:"-"`
+
+	// ProviderOverridesFile is the settings file ProviderOverrides were written to
+	// for the target (not in YAML)
+	ProviderOverridesFile string `yaml:"-" json:"-"`
+
 	// Parsed Git components (not in YAML)
 	ApplicationGitComponents *GitURLComponents   `yaml:"-" json:"-"`
 	RulesGitComponents       []*GitURLComponents `yaml:"-" json:"-"`
 }
 
+// ApplicationInput is an application of a test analyzing several
+type ApplicationInput struct {
+	// Name identifies the application in expect.applications and reports
+	Name string `json:"name" yaml:"name" validate:"required"`
+	// Application is a file path, a git repository URL or an alias of the
+	// application registry, as the application of a test
+	Application string `json:"application" yaml:"application" validate:"required"`
+
+	// Alias and GitComponents are set when loading (not in YAML)
+	Alias         string            `yaml:"-" json:"-"`
+	GitComponents *GitURLComponents `yaml:"-" json:"-"`
+}
+
+// ProviderOverride overrides the settings of one analysis provider
+type ProviderOverride struct {
+	// Name is the provider, e.g. java or dotnet
+	Name string `json:"name" yaml:"name" validate:"required"`
+	// ProviderSpecificConfig is set in the provider's init config, e.g. java's
+	// bundles or lspServerPath
+	ProviderSpecificConfig map[string]any `json:"providerSpecificConfig" yaml:"providerSpecificConfig" validate:"required"`
+}
+
+// DiscoveryAnalysisMode only runs language and technology discovery, whose
+// output consists of tags; expectations hold no violations or insights
+const DiscoveryAnalysisMode provider.AnalysisMode = "discovery"
+
+// DefaultRulesetsEnabled reports whether the default rulesets are analyzed
+func (ac *AnalysisConfig) DefaultRulesetsEnabled() bool {
+	if ac.EnableDefaultRulesets != nil {
+		return *ac.EnableDefaultRulesets
+	}
+	return !ac.DisableDefaultRules
+}
+
+// GetSourceDir returns the directory of the test git applications are cloned into
+func (ac *AnalysisConfig) GetSourceDir() string {
+	if ac.SourceDir != "" {
+		return ac.SourceDir
+	}
+	return "source"
+}
+
 type ApplicationGitRef struct {
 	Repo   string
 	Branch string
@@ -93,8 +363,154 @@ func (a *AnalysisConfig) GetApplication() (ApplicationGitRef, error) {
 
 // ExpectConfig defines expected outcomes
 type ExpectConfig struct {
-	ExitCode int            `yaml:"exitCode"`
+	// ExitCode is the exit code the tool must return: a code, a range, a name or a
+	// list of them, e.g. [0, violations] (default: 0)
+	ExitCode ExitCodes      `yaml:"exitCode"`
 	Output   ExpectedOutput `yaml:"output" validate:"required"`
+
+	// Applications holds the expected output of each application of a test with
+	// analysis.applications, by name
+	Applications map[string]ExpectedOutput `yaml:"applications,omitempty"`
+
+	// AllowedMismatches lets a test pass with up to N validation errors, or a
+	// percentage of the expected incidents (e.g. "2%")
+	AllowedMismatches *Tolerance `yaml:"allowedMismatches,omitempty"`
+
+	// Absent lists rulesets, rules and tags that must not appear in the output
+	Absent *AbsentExpectations `yaml:"absent,omitempty"`
+
+	// Effort checks aggregated effort (effort × incidents) as shown by the Hub and UI
+	Effort *EffortExpectations `yaml:"effort,omitempty"`
+
+	// MaxDuration is the analysis time budget of the test; slower analyses fail the
+	// test, or warn with validation.durationBudget: warn
+	MaxDuration *Duration `yaml:"maxDuration,omitempty"`
+
+	// Baseline set to "previous-run" compares the output with the test's output in the
+	// previous recorded run on the same target instead of an expected output, failing
+	// on violations that appeared or disappeared since. Output is optional then.
+	Baseline string `yaml:"baseline,omitempty" validate:"omitempty,oneof=previous-run"`
+
+	// Labels are the source and target labels a list-labels test expects, in place
+	// of an output
+	Labels *LabelExpectations `yaml:"labels,omitempty"`
+
+	// Files are the files a transform test expects, in place of an output
+	Files *FileExpectations `yaml:"files,omitempty"`
+
+	// Providers are the analysis providers a multi-language application must run,
+	// and the output expected of each
+	Providers *ProviderExpectations `yaml:"providers,omitempty"`
+}
+
+// EffortExpectations are expected effort totals, the sum of each violation's effort
+// multiplied by its number of incidents
+type EffortExpectations struct {
+	// Total is the effort of the whole application
+	Total *int `yaml:"total,omitempty"`
+	// RuleSets is the effort per ruleset name
+	RuleSets map[string]int `yaml:"rulesets,omitempty"`
+	// Tolerance is the allowed deviation of each total, e.g. "5" or "2%"
+	Tolerance Tolerance `yaml:"tolerance,omitempty"`
+}
+
+// BaselinePreviousRun compares a test's output with its previous recorded run
+const BaselinePreviousRun = "previous-run"
+
+// Kinds of tests
+const (
+	KindAnalysis   = "analysis"
+	KindListLabels = "list-labels"
+	KindTransform  = "transform"
+)
+
+// Transform commands of kantra
+const (
+	TransformRules       = "rules"
+	TransformOpenRewrite = "openrewrite"
+)
+
+// TransformConfig is a kantra transform command and its input
+type TransformConfig struct {
+	// Command is rules, converting Windup XML rules to YAML rules, or openrewrite,
+	// rewriting the sources of an application with a recipe
+	Command string `yaml:"command" validate:"required,oneof=rules openrewrite"`
+	// Input is the rules or application source directory, relative to the test.
+	// Applications are copied to the work directory, as openrewrite rewrites them.
+	Input string `yaml:"input" validate:"required"`
+	// Target is the openrewrite recipe to run, e.g. jakarta-imports
+	Target string `yaml:"target,omitempty" validate:"required_if=Command openrewrite"`
+}
+
+// FixConfig selects the incidents of an analysis to request fix suggestions for.
+// Suggestions must have a diff of the incident's file.
+type FixConfig struct {
+	Incidents []FixIncident `yaml:"incidents" validate:"required,min=1,dive"`
+	// RequireCompiles fails suggestions the target does not report as compiling
+	RequireCompiles bool `yaml:"requireCompiles,omitempty"`
+}
+
+// FixIncident selects an incident of the actual output by its rule
+type FixIncident struct {
+	RuleSet string `yaml:"ruleset" validate:"required"`
+	Rule    string `yaml:"rule" validate:"required"`
+	// Incident is the index of the incident among the rule's incidents, ordered by
+	// URI and line (default: 0)
+	Incident int `yaml:"incident,omitempty" validate:"gte=0"`
+}
+
+// FileExpectations are the files a transform test must produce
+type FileExpectations struct {
+	// Dir holds the expected files, relative to the test; produced files with the
+	// same path must have the same content
+	Dir string `yaml:"dir" validate:"required"`
+	// Exact fails on produced files that are not expected
+	Exact bool `yaml:"exact,omitempty"`
+}
+
+// LabelExpectations are the source and target labels the rulesets of a list-labels
+// test must offer, as listed by kantra analyze --list-sources and --list-targets
+type LabelExpectations struct {
+	Sources []string `yaml:"sources,omitempty"`
+	Targets []string `yaml:"targets,omitempty"`
+	// Exact fails on listed labels that are not expected
+	Exact bool `yaml:"exact,omitempty"`
+}
+
+// ProviderExpectations are the analysis providers an analysis must run, catching
+// providers that are silently skipped, e.g. nodejs in a java and javascript application
+type ProviderExpectations struct {
+	// Ran are providers that must run, e.g. [java, nodejs, builtin]
+	Ran []string `yaml:"ran,omitempty" validate:"dive,required"`
+	// Exact fails on providers that ran without being expected
+	Exact bool `yaml:"exact,omitempty"`
+	// Output holds the rulesets expected of each provider, by provider name. They
+	// are merged into the expected output, and their providers must run too.
+	Output map[string]ExpectedOutput `yaml:"output,omitempty"`
+}
+
+// Required returns the providers that must run: those of Ran and those with
+// expected output
+func (p *ProviderExpectations) Required() []string {
+	if p == nil {
+		return nil
+	}
+	required := slices.Clone(p.Ran)
+	for name := range p.Output {
+		if !slices.Contains(required, name) {
+			required = append(required, name)
+		}
+	}
+	slices.Sort(required)
+	return required
+}
+
+// AbsentExpectations are negative expectations, e.g. that a removed rule no longer fires
+type AbsentExpectations struct {
+	RuleSets []string `yaml:"rulesets,omitempty"`
+	// Rules are rule IDs that must not produce a violation or insight in any ruleset
+	Rules []string `yaml:"rules,omitempty"`
+	Tags  []string `yaml:"tags,omitempty"`
 }
 
 // ExpectedOutput is a union type for expected output
@@ -106,8 +522,18 @@ type ExpectedOutput struct {
 	// File path to YAML file containing expected RuleSets (as specified in YAML)
 	File string `yaml:"file,omitempty"`
 
+	// Files are expected output files, e.g. one per module of a multi-module
+	// application, merged into a single ruleset list
+	Files []string `yaml:"files,omitempty"`
+
 	// ResolvedFilePath is the absolute path to the expected output file (not in YAML)
 	ResolvedFilePath string `yaml:"-"`
+
+	// ResolvedFilePaths are the absolute paths of all expected output files (not in YAML)
+	ResolvedFilePaths []string `yaml:"-"`
+
+	// IncidentCounts holds the incidentCount expectations of Result (not in YAML)
+	IncidentCounts IncidentCounts `yaml:"-"`
 }
 
 // Duration is a wrapper around time.Duration that supports YAML unmarshaling
@@ -151,6 +577,22 @@ func (td *TestDefinition) GetWorkDir() string {
 	return ".koncur/output"
 }
 
+// ForApplication returns the test definition of one application of a test analyzing
+// several: analyzing only that application, named after both, and expecting the
+// application's output
+func (td *TestDefinition) ForApplication(app ApplicationInput) *TestDefinition {
+	appTest := *td
+	appTest.Name = fmt.Sprintf("%s [%s]", td.Name, app.Name)
+	appTest.Analysis.Application = app.Application
+	appTest.Analysis.ApplicationAlias = app.Alias
+	appTest.Analysis.ApplicationGitComponents = app.GitComponents
+	appTest.Analysis.Applications = nil
+	appTest.Analysis.SourceDir = "source-" + app.Name
+	appTest.Expect.Output = td.Expect.Applications[app.Name]
+	appTest.Expect.Applications = nil
+	return &appTest
+}
+
 // ParseGitURLs parses Git URLs in the analysis configuration
 // This should be called after loading the configuration
 func (ac *AnalysisConfig) ParseGitURLs() {
@@ -158,6 +600,11 @@ func (ac *AnalysisConfig) ParseGitURLs() {
 	if IsGitURL(ac.Application) {
 		ac.ApplicationGitComponents = ParseGitURLWithPath(ac.Application)
 	}
+	for i, app := range ac.Applications {
+		if IsGitURL(app.Application) {
+			ac.Applications[i].GitComponents = ParseGitURLWithPath(app.Application)
+		}
+	}
 
 	// Parse rules Git URLs
 	if len(ac.Rules) > 0 {
@@ -0,0 +1,261 @@
+package config
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/konveyor/test-harness/pkg/parser"
+)
+
+// ValidationConfig tunes how actual output is compared with the expected output.
+// It can be set in a target config and overridden per test.
+type ValidationConfig struct {
+	// Profile selects a named set of tolerances for known cross-target differences
+	// (see 'koncur config profiles'). Targets use their own default profile when unset.
+	Profile string `yaml:"profile,omitempty" validate:"omitempty,toleranceprofile"`
+
+	// Strict reports unexpected findings (tags, labels, links, insight incidents)
+	// that a target's comparer tolerates by default
+	Strict bool `yaml:"strict,omitempty"`
+
+	// FailOnProviderErrors fails a test when its analysis or provider logs report
+	// provider initialization errors or rule evaluation failures, even if an
+	// output was written
+	FailOnProviderErrors bool `yaml:"failOnProviderErrors,omitempty"`
+
+	// DurationBudget is what an analysis slower than expect.maxDuration does to its
+	// test: fail (default) or warn
+	DurationBudget string `yaml:"durationBudget,omitempty" validate:"omitempty,oneof=fail warn"`
+
+	// Field tolerances; unset fields keep the target's default
+	IgnoreLineNumbers *bool `yaml:"ignoreLineNumbers,omitempty"`
+	IgnoreCodeSnips   *bool `yaml:"ignoreCodeSnips,omitempty"`
+	IgnoreVariables   *bool `yaml:"ignoreVariables,omitempty"`
+	IgnoreLinks       *bool `yaml:"ignoreLinks,omitempty"`
+	SkipTags          *bool `yaml:"skipTags,omitempty"`
+
+	// CodeSnips selects how code snips are compared when they are not ignored:
+	// exact (default, surrounding whitespace trimmed), whitespace (tabs and spaces
+	// normalized) or flaggedLine (only the incident's line, ignoring context lines)
+	CodeSnips string `yaml:"codeSnips,omitempty" validate:"omitempty,oneof=exact whitespace flaggedLine"`
+
+	// Variables tunes how incident variables are compared when they are not ignored
+	Variables *VariableComparison `yaml:"variables,omitempty"`
+
+	// Links tunes how link URLs are compared
+	Links *LinkComparison `yaml:"links,omitempty"`
+
+	// StripURIPrefixes are path prefixes removed from both expected and actual
+	// incident URIs before comparing them, e.g. the directory a target clones
+	// applications into. Known tool locations (Maven caches, container source
+	// directories, file:// URIs, Windows drives) are always normalized.
+	StripURIPrefixes []string `yaml:"stripURIPrefixes,omitempty"`
+
+	// DependencyIncidents selects whether incidents in dependency sources, which are
+	// normalized under /m2/, are compared (default) or ignored. Targets often differ
+	// in which dependencies they download, decompile and analyze.
+	DependencyIncidents string `yaml:"dependencyIncidents,omitempty" validate:"omitempty,oneof=compare ignore"`
+
+	// MessageIgnorePatterns are regular expressions whose matches are removed
+	// from incident messages before comparing them (e.g. versions, timestamps)
+	MessageIgnorePatterns []string `yaml:"messageIgnorePatterns,omitempty"`
+
+	// Errors tunes how the provider errors of rulesets are compared
+	Errors *ErrorComparison `yaml:"errors,omitempty"`
+
+	// Unmatched and Skipped tune how the unmatched and skipped rule lists are compared
+	Unmatched *RuleListComparison `yaml:"unmatched,omitempty"`
+	Skipped   *RuleListComparison `yaml:"skipped,omitempty"`
+
+	// Filter selects which rulesets of the actual output are compared (and saved
+	// by generate); by default rulesets without violations, insights or tags are dropped
+	Filter *parser.RuleSetFilter `yaml:"filter,omitempty"`
+
+	// MatchRenamedRules pairs expected violations missing from the actual output with
+	// unexpected ones of the same description and category, so renamed rules are
+	// compared and reported as a warning instead of a missing and an unexpected violation
+	MatchRenamedRules *bool `yaml:"matchRenamedRules,omitempty"`
+
+	// Validators are external programs run after the built-in comparison
+	Validators []ExternalValidator `yaml:"validators,omitempty" validate:"dive"`
+
+	// IncidentLimit and CodeSnipLimit are the caps of the test's analysis (not in
+	// YAML), which outputs are validated against
+	IncidentLimit int `yaml:"-"`
+	CodeSnipLimit int `yaml:"-"`
+
+	// RuleSets overrides these settings for individual rulesets by name,
+	// so noisy rulesets can be relaxed without loosening everything else
+	RuleSets map[string]ValidationConfig `yaml:"rulesets,omitempty"`
+}
+
+// Merge returns v with the settings of override applied on top
+func (v ValidationConfig) Merge(override ValidationConfig) ValidationConfig {
+	merged := v
+	merged.Strict = v.Strict || override.Strict
+	merged.FailOnProviderErrors = v.FailOnProviderErrors || override.FailOnProviderErrors
+	if override.Profile != "" {
+		merged.Profile = override.Profile
+	}
+	if override.DurationBudget != "" {
+		merged.DurationBudget = override.DurationBudget
+	}
+	if override.IgnoreLineNumbers != nil {
+		merged.IgnoreLineNumbers = override.IgnoreLineNumbers
+	}
+	if override.IgnoreCodeSnips != nil {
+		merged.IgnoreCodeSnips = override.IgnoreCodeSnips
+	}
+	if override.IgnoreVariables != nil {
+		merged.IgnoreVariables = override.IgnoreVariables
+	}
+	if override.IgnoreLinks != nil {
+		merged.IgnoreLinks = override.IgnoreLinks
+	}
+	if override.SkipTags != nil {
+		merged.SkipTags = override.SkipTags
+	}
+	if override.DependencyIncidents != "" {
+		merged.DependencyIncidents = override.DependencyIncidents
+	}
+	if override.CodeSnips != "" {
+		merged.CodeSnips = override.CodeSnips
+	}
+	if override.Variables != nil {
+		merged.Variables = override.Variables
+	}
+	if override.Links != nil {
+		merged.Links = override.Links
+	}
+	if override.Filter != nil {
+		merged.Filter = override.Filter
+	}
+	if override.MatchRenamedRules != nil {
+		merged.MatchRenamedRules = override.MatchRenamedRules
+	}
+	if override.Errors != nil {
+		merged.Errors = override.Errors
+	}
+	if override.Unmatched != nil {
+		merged.Unmatched = override.Unmatched
+	}
+	if override.Skipped != nil {
+		merged.Skipped = override.Skipped
+	}
+	merged.StripURIPrefixes = append(append([]string{}, v.StripURIPrefixes...), override.StripURIPrefixes...)
+	merged.MessageIgnorePatterns = append(append([]string{}, v.MessageIgnorePatterns...), override.MessageIgnorePatterns...)
+	merged.Validators = append(append([]ExternalValidator{}, v.Validators...), override.Validators...)
+	if len(v.RuleSets) > 0 || len(override.RuleSets) > 0 {
+		merged.RuleSets = make(map[string]ValidationConfig, len(v.RuleSets)+len(override.RuleSets))
+		for name, rs := range v.RuleSets {
+			merged.RuleSets[name] = rs
+		}
+		for name, rs := range override.RuleSets {
+			merged.RuleSets[name] = merged.RuleSets[name].Merge(rs)
+		}
+	}
+	return merged
+}
+
+// ForRuleSet returns the settings that apply to the named ruleset
+func (v ValidationConfig) ForRuleSet(name string) ValidationConfig {
+	merged := v.Merge(v.RuleSets[name])
+	merged.RuleSets = nil
+	return merged
+}
+
+// Duration budget modes
+const (
+	DurationBudgetFail = "fail"
+	DurationBudgetWarn = "warn"
+)
+
+// Dependency incident comparison modes
+const (
+	DependencyIncidentsCompare = "compare"
+	DependencyIncidentsIgnore  = "ignore"
+)
+
+// Code snip comparison modes
+const (
+	CodeSnipExact       = "exact"
+	CodeSnipWhitespace  = "whitespace"
+	CodeSnipFlaggedLine = "flaggedLine"
+)
+
+// Error matching modes
+const (
+	ErrorMatchExact     = "exact"
+	ErrorMatchSubstring = "substring"
+	ErrorMatchRegex     = "regex"
+)
+
+// ErrorComparison configures the comparison of ruleset errors, whose messages
+// often contain absolute paths and timestamps
+type ErrorComparison struct {
+	// Match selects how expected error messages match the actual ones: exact
+	// (default), substring (the actual message contains the expected one) or
+	// regex (the expected message is a regular expression)
+	Match string `yaml:"match,omitempty" validate:"omitempty,oneof=exact substring regex"`
+	// PresenceOnly only checks that a ruleset has errors when errors are expected,
+	// and none otherwise, regardless of which rules failed
+	PresenceOnly bool `yaml:"presenceOnly,omitempty"`
+}
+
+// RuleListComparison configures the comparison of rule ID lists such as unmatched
+// and skipped rules. Expected entries may always be glob patterns (e.g. "eap8/websphere-*").
+type RuleListComparison struct {
+	// Ignore skips the comparison, e.g. for targets that don't report these rules
+	Ignore bool `yaml:"ignore,omitempty"`
+	// CountOnly compares only the number of entries, which churns less than the lists
+	CountOnly bool `yaml:"countOnly,omitempty"`
+	// CountTolerance is the allowed deviation of the count, e.g. "5" or "10%"
+	CountTolerance Tolerance `yaml:"countTolerance,omitempty"`
+}
+
+// VariableComparison configures the comparison of incident variables, which providers
+// tend to extend with new keys. Without keys or patterns all variables must be equal.
+type VariableComparison struct {
+	// Keys limits the comparison to these variables, other keys are ignored
+	Keys []string `yaml:"keys,omitempty"`
+	// Patterns are regular expressions the values of these variables must match,
+	// instead of being equal to the expected value. Their keys are always compared.
+	Patterns map[string]string `yaml:"patterns,omitempty"`
+}
+
+// LinkComparison configures the comparison of violation links. By default URLs are
+// normalized so http/https, host case, default ports and trailing slashes don't matter.
+type LinkComparison struct {
+	// ExactURLs compares URLs as written, without normalization
+	ExactURLs bool `yaml:"exactURLs,omitempty"`
+	// FollowRedirects resolves both URLs through their HTTP redirects before comparing,
+	// so moved documentation pages still match. This requires network access.
+	FollowRedirects bool `yaml:"followRedirects,omitempty"`
+}
+
+// ExternalValidator is a program implementing the exec validation protocol.
+// It receives the target, test directory, expected and actual rulesets as YAML on
+// stdin and writes `errors: [{path, message}]` as YAML or JSON to stdout.
+type ExternalValidator struct {
+	Name string `yaml:"name,omitempty"`
+	// Command is the program and its arguments; relative programs are resolved
+	// against the test directory, which is also the working directory
+	Command []string  `yaml:"command" validate:"required,min=1"`
+	Timeout *Duration `yaml:"timeout,omitempty"`
+}
+
+// GetName returns the validator name, defaulting to the program name
+func (e ExternalValidator) GetName() string {
+	if e.Name != "" || len(e.Command) == 0 {
+		return e.Name
+	}
+	return filepath.Base(e.Command[0])
+}
+
+// GetTimeout returns the validator timeout with a default
+func (e ExternalValidator) GetTimeout() time.Duration {
+	if e.Timeout != nil {
+		return e.Timeout.Duration
+	}
+	return time.Minute
+}
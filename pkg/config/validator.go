@@ -2,35 +2,116 @@ package config
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/go-playground/validator/v10"
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
 )
 
 var validate *validator.Validate
 
 func init() {
 	validate = validator.New()
+	validate.RegisterValidation("toleranceprofile", func(fl validator.FieldLevel) bool {
+		_, ok := GetToleranceProfile(fl.Field().String())
+		return ok
+	})
 }
 
 // Validate checks if a test definition is valid
 func Validate(test *TestDefinition) error {
+	switch test.Kind {
+	case KindListLabels:
+		return validateListLabels(test)
+	case KindTransform:
+		return validateTransform(test)
+	}
+
 	// Run struct validation
 	if err := validate.Struct(test); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
-	// Custom validation: ExpectedOutput must have exactly one of Result or File
-	if err := validateExpectedOutput(&test.Expect.Output); err != nil {
+	if err := validateProviders(test); err != nil {
+		return err
+	}
+
+	// Custom validation: ExpectedOutput must have exactly one of Result or File,
+	// unless the test is compared with its previous run
+	if len(test.Analysis.Applications) > 0 {
+		if err := validateApplicationOutputs(test); err != nil {
+			return err
+		}
+	} else if err := validateExpectedOutput(&test.Expect.Output); err != nil && test.Expect.Baseline != BaselinePreviousRun {
+		return err
+	}
+
+	if err := validateAbsent(&test.Expect); err != nil {
+		return err
+	}
+
+	if err := validateDefaultRulesets(&test.Analysis); err != nil {
 		return err
 	}
 
+	if test.Analysis.AnalysisMode == DiscoveryAnalysisMode {
+		if err := validateDiscoveryOutputs(test); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateListLabels validates a list-labels test, which analyzes no application
+// and expects labels instead of an output
+func validateListLabels(test *TestDefinition) error {
+	if err := validate.StructExcept(test, "Analysis.Application", "Analysis.AnalysisMode"); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	if test.Expect.Labels == nil {
+		return fmt.Errorf("list-labels tests must specify expect.labels")
+	}
+	if test.Fixes != nil {
+		return fmt.Errorf("list-labels tests analyze nothing to request fixes for")
+	}
+	return validateDefaultRulesets(&test.Analysis)
+}
+
+// validateTransform validates a transform test, which analyzes no application and
+// expects files instead of an output
+func validateTransform(test *TestDefinition) error {
+	if err := validate.StructExcept(test, "Analysis.Application", "Analysis.AnalysisMode"); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	if test.Transform == nil {
+		return fmt.Errorf("transform tests must specify transform")
+	}
+	if test.Expect.Files == nil {
+		return fmt.Errorf("transform tests must specify expect.files")
+	}
+	if test.Fixes != nil {
+		return fmt.Errorf("transform tests analyze nothing to request fixes for")
+	}
+	return nil
+}
+
+// validateDefaultRulesets ensures an analysis without default rulesets has rules to run
+func validateDefaultRulesets(analysis *AnalysisConfig) error {
+	if analysis.EnableDefaultRulesets != nil && *analysis.EnableDefaultRulesets && analysis.DisableDefaultRules {
+		return fmt.Errorf("enableDefaultRulesets and disableDefaultRules contradict each other")
+	}
+	if !analysis.DefaultRulesetsEnabled() && len(analysis.Rules) == 0 {
+		return fmt.Errorf("default rulesets are disabled, but the analysis has no rules")
+	}
 	return nil
 }
 
-// validateExpectedOutput ensures exactly one of Result or File is set
+// validateExpectedOutput ensures exactly one of Result or File (or Files) is set
 func validateExpectedOutput(output *ExpectedOutput) error {
 	hasResult := len(output.Result) > 0
-	hasFile := output.File != ""
+	hasFile := output.File != "" || len(output.Files) > 0
 
 	if !hasResult && !hasFile {
 		return fmt.Errorf("expected output must specify either 'result' or 'file'")
@@ -38,9 +119,92 @@ func validateExpectedOutput(output *ExpectedOutput) error {
 
 	// If ResolvedFilePath is set, it means we loaded the file and populated Result
 	// In this case, both Result and File being set is expected and valid
-	if hasResult && hasFile && output.ResolvedFilePath == "" {
+	loaded := output.ResolvedFilePath != "" || len(output.ResolvedFilePaths) > 0
+	if hasResult && hasFile && !loaded {
 		return fmt.Errorf("expected output cannot specify both 'result' and 'file'")
 	}
 
 	return nil
 }
+
+// validateApplicationOutputs ensures a test analyzing several applications expects
+// an output per application, and no other
+func validateApplicationOutputs(test *TestDefinition) error {
+	expected := maps.Clone(test.Expect.Applications)
+	for _, app := range test.Analysis.Applications {
+		output, ok := expected[app.Name]
+		delete(expected, app.Name)
+		if test.Expect.Baseline == BaselinePreviousRun && !ok {
+			continue
+		}
+		if err := validateExpectedOutput(&output); err != nil {
+			return fmt.Errorf("application %s: %w", app.Name, err)
+		}
+	}
+	if extra := slices.Sorted(maps.Keys(expected)); len(extra) > 0 {
+		return fmt.Errorf("expect.applications has an output for %s, which is not an application of the analysis", extra[0])
+	}
+	if len(test.Expect.Output.Result) > 0 || test.Expect.Output.File != "" || len(test.Expect.Output.Files) > 0 {
+		return fmt.Errorf("a test with several applications expects their outputs in expect.applications, not expect.output")
+	}
+	return nil
+}
+
+// validateProviders ensures each provider's expected output is set, and only for
+// tests analyzing a single application, whose expected output it is merged into
+func validateProviders(test *TestDefinition) error {
+	if test.Expect.Providers == nil {
+		return nil
+	}
+	if len(test.Expect.Providers.Output) > 0 && len(test.Analysis.Applications) > 0 {
+		return fmt.Errorf("expect.providers.output cannot be used with several applications, expect their outputs in expect.applications")
+	}
+	for _, name := range slices.Sorted(maps.Keys(test.Expect.Providers.Output)) {
+		output := test.Expect.Providers.Output[name]
+		if err := validateExpectedOutput(&output); err != nil {
+			return fmt.Errorf("provider %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// validateAbsent ensures nothing is both expected and expected to be absent
+func validateAbsent(expect *ExpectConfig) error {
+	if expect.Absent == nil {
+		return nil
+	}
+	for _, rs := range expect.Output.Result {
+		if slices.Contains(expect.Absent.RuleSets, rs.Name) {
+			return fmt.Errorf("ruleset %s is both expected and listed in expect.absent", rs.Name)
+		}
+		for _, tag := range rs.Tags {
+			if slices.Contains(expect.Absent.Tags, tag) {
+				return fmt.Errorf("tag %s is both expected and listed in expect.absent", tag)
+			}
+		}
+		for _, violations := range []map[string]konveyor.Violation{rs.Violations, rs.Insights} {
+			for ruleID := range violations {
+				if slices.Contains(expect.Absent.Rules, ruleID) {
+					return fmt.Errorf("rule %s is both expected and listed in expect.absent", ruleID)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateDiscoveryOutputs ensures the expected outputs of a discovery analysis only hold tags
+func validateDiscoveryOutputs(test *TestDefinition) error {
+	outputs := []ExpectedOutput{test.Expect.Output}
+	for _, output := range test.Expect.Applications {
+		outputs = append(outputs, output)
+	}
+	for _, output := range outputs {
+		for _, rs := range output.Result {
+			if len(rs.Violations) > 0 || len(rs.Insights) > 0 {
+				return fmt.Errorf("ruleset %s expects violations or insights, but discovery analyses are compared by their tags only", rs.Name)
+			}
+		}
+	}
+	return nil
+}
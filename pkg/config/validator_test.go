@@ -0,0 +1,335 @@
+package config
+
+import (
+	"testing"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/parser"
+)
+
+func TestValidateAbsentConflicts(t *testing.T) {
+	expected := []konveyor.RuleSet{
+		{
+			Name:       "ruleset-a",
+			Tags:       []string{"Java"},
+			Violations: map[string]konveyor.Violation{"rule-001": {Description: "d"}},
+		},
+	}
+	tests := []struct {
+		name    string
+		absent  *AbsentExpectations
+		wantErr bool
+	}{
+		{"no absent", nil, false},
+		{"unrelated", &AbsentExpectations{RuleSets: []string{"b"}, Rules: []string{"rule-002"}, Tags: []string{"EJB"}}, false},
+		{"expected ruleset", &AbsentExpectations{RuleSets: []string{"ruleset-a"}}, true},
+		{"expected rule", &AbsentExpectations{Rules: []string{"rule-001"}}, true},
+		{"expected tag", &AbsentExpectations{Tags: []string{"Java"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := &TestDefinition{
+				Name:     "absent",
+				Analysis: AnalysisConfig{Application: "app", AnalysisMode: "source-only"},
+				Expect:   ExpectConfig{Output: ExpectedOutput{Result: expected}, Absent: tt.absent},
+			}
+			if err := Validate(test); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateBaselineWithoutOutput(t *testing.T) {
+	test := &TestDefinition{
+		Name:     "baseline",
+		Analysis: AnalysisConfig{Application: "app", AnalysisMode: "source-only"},
+		Expect:   ExpectConfig{Baseline: BaselinePreviousRun},
+	}
+	if err := Validate(test); err != nil {
+		t.Errorf("Expected previous-run baseline without output to be valid, got %v", err)
+	}
+
+	test.Expect.Baseline = "last-week"
+	if err := Validate(test); err == nil {
+		t.Error("Expected unknown baseline to be rejected")
+	}
+}
+
+func TestValidateProfile(t *testing.T) {
+	test := &TestDefinition{
+		Name:       "profile",
+		Analysis:   AnalysisConfig{Application: "app", AnalysisMode: "source-only"},
+		Expect:     ExpectConfig{Output: ExpectedOutput{File: "expected-output.yaml"}},
+		Validation: ValidationConfig{Profile: "nope"},
+	}
+	if err := Validate(test); err == nil {
+		t.Error("Expected an unknown profile to be rejected")
+	}
+	test.Validation.Profile = ProfileHubVsKantra
+	if err := Validate(test); err != nil {
+		t.Errorf("Expected a known profile to be accepted, got %v", err)
+	}
+}
+
+func TestValidateFilter(t *testing.T) {
+	test := &TestDefinition{
+		Name:       "filter",
+		Analysis:   AnalysisConfig{Application: "app", AnalysisMode: "source-only"},
+		Expect:     ExpectConfig{Output: ExpectedOutput{File: "expected-output.yaml"}},
+		Validation: ValidationConfig{Filter: &parser.RuleSetFilter{Keep: []string{"violations", "nope"}}},
+	}
+	if err := Validate(test); err == nil {
+		t.Error("Expected an unknown filter section to be rejected")
+	}
+	test.Validation.Filter.Keep = []string{parser.KeepErrors, parser.KeepUnmatched}
+	if err := Validate(test); err != nil {
+		t.Errorf("Expected known filter sections to be accepted, got %v", err)
+	}
+}
+
+func TestValidateSkip(t *testing.T) {
+	test := &TestDefinition{
+		Name:     "skip",
+		Analysis: AnalysisConfig{Application: "app", AnalysisMode: "source-only"},
+		Expect:   ExpectConfig{Output: ExpectedOutput{File: "expected-output.yaml"}},
+		Skip:     &SkipConfig{Reason: "broken", Targets: []string{"tackle-hub"}},
+	}
+	if err := Validate(test); err != nil {
+		t.Errorf("Expected a skip on a known target to be accepted, got %v", err)
+	}
+	test.Skip.Targets = []string{"hub"}
+	if err := Validate(test); err == nil {
+		t.Error("Expected a skip on an unknown target to be rejected")
+	}
+}
+
+func TestValidateApplications(t *testing.T) {
+	output := ExpectedOutput{Result: []konveyor.RuleSet{{Name: "rs", Tags: []string{"Java"}}}}
+	apps := []ApplicationInput{{Name: "coolstore", Application: "./coolstore"}, {Name: "daytrader", Application: "./daytrader"}}
+	tests := []struct {
+		name     string
+		analysis AnalysisConfig
+		expect   ExpectConfig
+		wantErr  bool
+	}{
+		{"output per application", AnalysisConfig{Applications: apps},
+			ExpectConfig{Applications: map[string]ExpectedOutput{"coolstore": output, "daytrader": output}}, false},
+		{"missing application output", AnalysisConfig{Applications: apps},
+			ExpectConfig{Applications: map[string]ExpectedOutput{"coolstore": output}}, true},
+		{"missing output with previous-run baseline", AnalysisConfig{Applications: apps},
+			ExpectConfig{Baseline: BaselinePreviousRun}, false},
+		{"output of an unknown application", AnalysisConfig{Applications: apps},
+			ExpectConfig{Applications: map[string]ExpectedOutput{"coolstore": output, "daytrader": output, "petclinic": output}}, true},
+		{"test output", AnalysisConfig{Applications: apps},
+			ExpectConfig{Output: output, Applications: map[string]ExpectedOutput{"coolstore": output, "daytrader": output}}, true},
+		{"application and applications", AnalysisConfig{Application: "app", Applications: apps},
+			ExpectConfig{Applications: map[string]ExpectedOutput{"coolstore": output, "daytrader": output}}, true},
+		{"duplicate names", AnalysisConfig{Applications: []ApplicationInput{apps[0], apps[0]}},
+			ExpectConfig{Applications: map[string]ExpectedOutput{"coolstore": output}}, true},
+		{"bulk analysis", AnalysisConfig{Applications: apps, Bulk: true},
+			ExpectConfig{Applications: map[string]ExpectedOutput{"coolstore": output, "daytrader": output}}, false},
+		{"bulk analysis of one application", AnalysisConfig{Application: "app", Bulk: true},
+			ExpectConfig{Output: output}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.analysis.AnalysisMode = "source-only"
+			test := &TestDefinition{Name: "bulk", Analysis: tt.analysis, Expect: tt.expect}
+			if err := Validate(test); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateDefaultRulesets(t *testing.T) {
+	enabled, disabled := true, false
+	tests := []struct {
+		name     string
+		analysis AnalysisConfig
+		wantErr  bool
+	}{
+		{"default", AnalysisConfig{}, false},
+		{"disabled with rules", AnalysisConfig{EnableDefaultRulesets: &disabled, Rules: []string{"rules"}}, false},
+		{"disabled without rules", AnalysisConfig{EnableDefaultRulesets: &disabled}, true},
+		{"legacy disabled without rules", AnalysisConfig{DisableDefaultRules: true}, true},
+		{"contradicting settings", AnalysisConfig{EnableDefaultRulesets: &enabled, DisableDefaultRules: true, Rules: []string{"rules"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.analysis.Application = "app"
+			tt.analysis.AnalysisMode = "source-only"
+			test := &TestDefinition{
+				Name:     "rulesets",
+				Analysis: tt.analysis,
+				Expect:   ExpectConfig{Baseline: BaselinePreviousRun},
+			}
+			if err := Validate(test); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateProviderOverrides(t *testing.T) {
+	java := ProviderOverride{Name: "java", ProviderSpecificConfig: map[string]any{"bundles": "/jdtls/bundles/custom.jar"}}
+	tests := []struct {
+		name      string
+		overrides []ProviderOverride
+		wantErr   bool
+	}{
+		{"none", nil, false},
+		{"java", []ProviderOverride{java}, false},
+		{"several providers", []ProviderOverride{java, {Name: "dotnet", ProviderSpecificConfig: map[string]any{"dotnetFramework": "4.5"}}}, false},
+		{"duplicate provider", []ProviderOverride{java, java}, true},
+		{"missing name", []ProviderOverride{{ProviderSpecificConfig: java.ProviderSpecificConfig}}, true},
+		{"missing config", []ProviderOverride{{Name: "java"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := &TestDefinition{
+				Name:     "overrides",
+				Analysis: AnalysisConfig{Application: "app", AnalysisMode: "source-only", ProviderOverrides: tt.overrides},
+				Expect:   ExpectConfig{Baseline: BaselinePreviousRun},
+			}
+			if err := Validate(test); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateProviders(t *testing.T) {
+	java := ExpectedOutput{Result: []konveyor.RuleSet{{Name: "rs", Violations: map[string]konveyor.Violation{"java-001": {Description: "d"}}}}}
+	tests := []struct {
+		name         string
+		providers    *ProviderExpectations
+		applications []ApplicationInput
+		wantErr      bool
+	}{
+		{"ran only", &ProviderExpectations{Ran: []string{"java", "nodejs"}, Exact: true}, nil, false},
+		{"provider output", &ProviderExpectations{Output: map[string]ExpectedOutput{"java": java}}, nil, false},
+		{"empty provider output", &ProviderExpectations{Output: map[string]ExpectedOutput{"java": {}}}, nil, true},
+		{"empty provider name", &ProviderExpectations{Ran: []string{""}}, nil, true},
+		{"applications with ran", &ProviderExpectations{Ran: []string{"java"}}, []ApplicationInput{{Name: "a", Application: "a"}}, false},
+		{"applications with provider output", &ProviderExpectations{Output: map[string]ExpectedOutput{"java": java}}, []ApplicationInput{{Name: "a", Application: "a"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := &TestDefinition{
+				Name:     "providers",
+				Analysis: AnalysisConfig{Application: "app", AnalysisMode: "source-only", Applications: tt.applications},
+				Expect:   ExpectConfig{Output: java, Providers: tt.providers},
+			}
+			if tt.applications != nil {
+				test.Analysis.Application = ""
+				test.Expect.Output = ExpectedOutput{}
+				test.Expect.Applications = map[string]ExpectedOutput{"a": java}
+			}
+			if err := Validate(test); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateFixes(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixes   *FixConfig
+		wantErr bool
+	}{
+		{"no fixes", nil, false},
+		{"incident", &FixConfig{Incidents: []FixIncident{{RuleSet: "rs", Rule: "rule-001", Incident: 2}}, RequireCompiles: true}, false},
+		{"no incidents", &FixConfig{}, true},
+		{"missing rule", &FixConfig{Incidents: []FixIncident{{RuleSet: "rs"}}}, true},
+		{"negative incident", &FixConfig{Incidents: []FixIncident{{RuleSet: "rs", Rule: "rule-001", Incident: -1}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := &TestDefinition{
+				Name:     "fixes",
+				Analysis: AnalysisConfig{Application: "app", AnalysisMode: "source-only"},
+				Fixes:    tt.fixes,
+				Expect:   ExpectConfig{Baseline: BaselinePreviousRun},
+			}
+			if err := Validate(test); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateListLabels(t *testing.T) {
+	tests := []struct {
+		name    string
+		test    TestDefinition
+		wantErr bool
+	}{
+		{"labels without application", TestDefinition{
+			Name: "labels", Kind: KindListLabels,
+			Expect: ExpectConfig{Labels: &LabelExpectations{Targets: []string{"quarkus"}}},
+		}, false},
+		{"labels missing", TestDefinition{Name: "labels", Kind: KindListLabels}, true},
+		{"analysis without application", TestDefinition{
+			Name:   "analysis",
+			Expect: ExpectConfig{Labels: &LabelExpectations{Targets: []string{"quarkus"}}},
+		}, true},
+		{"unknown kind", TestDefinition{
+			Name: "labels", Kind: "labels",
+			Analysis: AnalysisConfig{Application: "app", AnalysisMode: "source-only"},
+			Expect:   ExpectConfig{Baseline: BaselinePreviousRun},
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(&tt.test); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTransform(t *testing.T) {
+	files := &FileExpectations{Dir: "expected"}
+	tests := []struct {
+		name    string
+		test    TestDefinition
+		wantErr bool
+	}{
+		{"rules", TestDefinition{
+			Name: "transform", Kind: KindTransform,
+			Transform: &TransformConfig{Command: TransformRules, Input: "windup"},
+			Expect:    ExpectConfig{Files: files},
+		}, false},
+		{"openrewrite without recipe", TestDefinition{
+			Name: "transform", Kind: KindTransform,
+			Transform: &TransformConfig{Command: TransformOpenRewrite, Input: "app"},
+			Expect:    ExpectConfig{Files: files},
+		}, true},
+		{"unknown command", TestDefinition{
+			Name: "transform", Kind: KindTransform,
+			Transform: &TransformConfig{Command: "xslt", Input: "app"},
+			Expect:    ExpectConfig{Files: files},
+		}, true},
+		{"files missing", TestDefinition{
+			Name: "transform", Kind: KindTransform,
+			Transform: &TransformConfig{Command: TransformRules, Input: "windup"},
+		}, true},
+		{"transform missing", TestDefinition{Name: "transform", Kind: KindTransform, Expect: ExpectConfig{Files: files}}, true},
+		{"fixes requested", TestDefinition{
+			Name: "transform", Kind: KindTransform,
+			Transform: &TransformConfig{Command: TransformRules, Input: "windup"},
+			Fixes:     &FixConfig{Incidents: []FixIncident{{RuleSet: "rs", Rule: "rule-001"}}},
+			Expect:    ExpectConfig{Files: files},
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(&tt.test); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
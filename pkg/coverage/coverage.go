@@ -0,0 +1,267 @@
+package coverage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"gopkg.in/yaml.v3"
+)
+
+// Status describes how a rule was exercised by the tests
+type Status string
+
+const (
+	// Matched rules produced a violation or insight in at least one test
+	Matched Status = "matched"
+	// Unmatched rules ran but never matched in any test
+	Unmatched Status = "unmatched"
+	// Skipped rules were only ever skipped (e.g. filtered out by label selectors)
+	Skipped Status = "skipped"
+	// Uncovered rules do not appear in any test output
+	Uncovered Status = "uncovered"
+)
+
+// Rule is a rule declared in a ruleset on disk
+type Rule struct {
+	RuleSet string `json:"ruleset"`
+	RuleID  string `json:"ruleID"`
+	File    string `json:"file"`
+}
+
+// RuleCoverage is the coverage of a single rule
+type RuleCoverage struct {
+	Rule
+	Status Status `json:"status"`
+	// Tests lists the tests whose output mentions the rule
+	Tests []string `json:"tests,omitempty"`
+}
+
+// RuleSetCoverage aggregates coverage for all rules of one ruleset
+type RuleSetCoverage struct {
+	Name      string         `json:"name"`
+	Total     int            `json:"total"`
+	Matched   int            `json:"matched"`
+	Unmatched int            `json:"unmatched"`
+	Skipped   int            `json:"skipped"`
+	Uncovered int            `json:"uncovered"`
+	Rules     []RuleCoverage `json:"rules"`
+}
+
+// Percent returns the share of rules exercised by at least one test
+func (r RuleSetCoverage) Percent() float64 {
+	if r.Total == 0 {
+		return 0
+	}
+	return float64(r.Total-r.Uncovered) / float64(r.Total) * 100
+}
+
+// Report is the rule coverage of a set of rulesets
+type Report struct {
+	RuleSets []RuleSetCoverage `json:"rulesets"`
+	Total    int               `json:"total"`
+	Covered  int               `json:"covered"`
+}
+
+// Percent returns the share of all rules exercised by at least one test
+func (r Report) Percent() float64 {
+	if r.Total == 0 {
+		return 0
+	}
+	return float64(r.Covered) / float64(r.Total) * 100
+}
+
+// TestOutput is the ruleset output of one test
+type TestOutput struct {
+	Test     string
+	RuleSets []konveyor.RuleSet
+}
+
+// rulesetMetadata is the ruleset.yaml file that names a directory of rules
+type rulesetMetadata struct {
+	Name string `yaml:"name"`
+}
+
+// LoadRules reads all rules from rule files or directories.
+// Rules are attributed to the ruleset named by the nearest ruleset.yaml.
+func LoadRules(paths []string) ([]Rule, error) {
+	var rules []Rule
+	for _, root := range paths {
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || !isYAML(path) || filepath.Base(path) == "ruleset.yaml" {
+				return nil
+			}
+			fileRules, err := loadRuleFile(path)
+			if err != nil {
+				return err
+			}
+			rules = append(rules, fileRules...)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load rules from %s: %w", root, err)
+		}
+	}
+	return rules, nil
+}
+
+// loadRuleFile reads the rules declared in a single file.
+// Files that are not a list of rules are ignored.
+func loadRuleFile(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []struct {
+		RuleID string `yaml:"ruleID"`
+	}
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, nil
+	}
+
+	rulesetName := findRuleSetName(filepath.Dir(path))
+	var rules []Rule
+	for _, e := range entries {
+		if e.RuleID == "" {
+			continue
+		}
+		rules = append(rules, Rule{RuleSet: rulesetName, RuleID: e.RuleID, File: path})
+	}
+	return rules, nil
+}
+
+// findRuleSetName walks up from dir looking for a ruleset.yaml.
+// Directories without one are named after the directory, like the analyzer does.
+func findRuleSetName(dir string) string {
+	for d := dir; ; d = filepath.Dir(d) {
+		data, err := os.ReadFile(filepath.Join(d, "ruleset.yaml"))
+		if err == nil {
+			var meta rulesetMetadata
+			if yaml.Unmarshal(data, &meta) == nil && meta.Name != "" {
+				return meta.Name
+			}
+		}
+		if parent := filepath.Dir(d); parent == d {
+			break
+		}
+	}
+	return filepath.Base(dir)
+}
+
+func isYAML(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// Compute cross-references declared rules with the rules that appear in test outputs
+func Compute(rules []Rule, outputs []TestOutput) *Report {
+	type seen struct {
+		status Status
+		tests  map[string]bool
+	}
+	// Outputs are indexed both by ruleset/rule and by rule ID alone, so rules whose
+	// ruleset name could not be determined are still matched
+	byKey := map[string]*seen{}
+	byID := map[string]*seen{}
+	record := func(index map[string]*seen, key string, status Status, test string) {
+		s, ok := index[key]
+		if !ok {
+			s = &seen{status: status, tests: map[string]bool{}}
+			index[key] = s
+		}
+		if rank(status) > rank(s.status) {
+			s.status = status
+		}
+		s.tests[test] = true
+	}
+
+	for _, output := range outputs {
+		for _, rs := range output.RuleSets {
+			mark := func(ruleID string, status Status) {
+				record(byKey, rs.Name+"/"+ruleID, status, output.Test)
+				record(byID, ruleID, status, output.Test)
+			}
+			for ruleID := range rs.Violations {
+				mark(ruleID, Matched)
+			}
+			for ruleID := range rs.Insights {
+				mark(ruleID, Matched)
+			}
+			for _, ruleID := range rs.Unmatched {
+				mark(ruleID, Unmatched)
+			}
+			for _, ruleID := range rs.Skipped {
+				mark(ruleID, Skipped)
+			}
+		}
+	}
+
+	byRuleSet := map[string]*RuleSetCoverage{}
+	report := &Report{}
+	for _, rule := range rules {
+		rc, ok := byRuleSet[rule.RuleSet]
+		if !ok {
+			rc = &RuleSetCoverage{Name: rule.RuleSet}
+			byRuleSet[rule.RuleSet] = rc
+		}
+
+		cov := RuleCoverage{Rule: rule, Status: Uncovered}
+		s, ok := byKey[rule.RuleSet+"/"+rule.RuleID]
+		if !ok {
+			s, ok = byID[rule.RuleID]
+		}
+		if ok {
+			cov.Status = s.status
+			for test := range s.tests {
+				cov.Tests = append(cov.Tests, test)
+			}
+			sort.Strings(cov.Tests)
+		}
+
+		rc.Total++
+		switch cov.Status {
+		case Matched:
+			rc.Matched++
+		case Unmatched:
+			rc.Unmatched++
+		case Skipped:
+			rc.Skipped++
+		default:
+			rc.Uncovered++
+		}
+		rc.Rules = append(rc.Rules, cov)
+	}
+
+	for _, rc := range byRuleSet {
+		sort.Slice(rc.Rules, func(i, j int) bool {
+			return rc.Rules[i].RuleID < rc.Rules[j].RuleID
+		})
+		report.Total += rc.Total
+		report.Covered += rc.Total - rc.Uncovered
+		report.RuleSets = append(report.RuleSets, *rc)
+	}
+	sort.Slice(report.RuleSets, func(i, j int) bool {
+		return report.RuleSets[i].Name < report.RuleSets[j].Name
+	})
+	return report
+}
+
+// rank orders statuses so a rule takes the strongest status seen across tests
+func rank(s Status) int {
+	switch s {
+	case Matched:
+		return 3
+	case Unmatched:
+		return 2
+	case Skipped:
+		return 1
+	}
+	return 0
+}
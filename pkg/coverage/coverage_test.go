@@ -0,0 +1,108 @@
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "eap7", "ruleset.yaml"), "name: eap7/weblogic\n")
+	writeFile(t, filepath.Join(dir, "eap7", "rules.yaml"), "- ruleID: rule-1\n- ruleID: rule-2\n")
+	writeFile(t, filepath.Join(dir, "eap7", "nested", "more.yaml"), "- ruleID: rule-3\n")
+	writeFile(t, filepath.Join(dir, "loose", "rules.yml"), "- ruleID: loose-1\n")
+	writeFile(t, filepath.Join(dir, "loose", "notes.yaml"), "title: not a rule file\n")
+
+	rules, err := LoadRules([]string{dir})
+	if err != nil {
+		t.Fatalf("LoadRules() error = %v", err)
+	}
+
+	got := map[string]string{}
+	for _, r := range rules {
+		got[r.RuleID] = r.RuleSet
+	}
+	want := map[string]string{
+		"rule-1":  "eap7/weblogic",
+		"rule-2":  "eap7/weblogic",
+		"rule-3":  "eap7/weblogic",
+		"loose-1": "loose",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("LoadRules() = %v, want %v", got, want)
+	}
+	for id, rs := range want {
+		if got[id] != rs {
+			t.Errorf("rule %s in ruleset %q, want %q", id, got[id], rs)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	rules := []Rule{
+		{RuleSet: "rs", RuleID: "matched"},
+		{RuleSet: "rs", RuleID: "insight"},
+		{RuleSet: "rs", RuleID: "unmatched"},
+		{RuleSet: "rs", RuleID: "skipped"},
+		{RuleSet: "rs", RuleID: "uncovered"},
+		{RuleSet: "renamed", RuleID: "by-id"},
+	}
+	outputs := []TestOutput{
+		{Test: "a", RuleSets: []konveyor.RuleSet{{
+			Name:       "rs",
+			Violations: map[string]konveyor.Violation{"matched": {}},
+			Unmatched:  []string{"unmatched", "insight"},
+			Skipped:    []string{"skipped"},
+		}}},
+		{Test: "b", RuleSets: []konveyor.RuleSet{{
+			Name:     "rs",
+			Insights: map[string]konveyor.Violation{"insight": {}},
+		}, {
+			Name:      "other",
+			Unmatched: []string{"by-id"},
+		}}},
+	}
+
+	report := Compute(rules, outputs)
+	if report.Total != 6 || report.Covered != 5 {
+		t.Errorf("covered %d/%d, want 5/6", report.Covered, report.Total)
+	}
+
+	statuses := map[string]Status{}
+	tests := map[string]int{}
+	for _, rs := range report.RuleSets {
+		for _, r := range rs.Rules {
+			statuses[r.RuleID] = r.Status
+			tests[r.RuleID] = len(r.Tests)
+		}
+	}
+	want := map[string]Status{
+		"matched":   Matched,
+		"insight":   Matched,
+		"unmatched": Unmatched,
+		"skipped":   Skipped,
+		"uncovered": Uncovered,
+		"by-id":     Unmatched,
+	}
+	for id, status := range want {
+		if statuses[id] != status {
+			t.Errorf("rule %s status = %s, want %s", id, statuses[id], status)
+		}
+	}
+	if tests["insight"] != 2 {
+		t.Errorf("rule insight exercised by %d tests, want 2", tests["insight"])
+	}
+}
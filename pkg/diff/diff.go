@@ -0,0 +1,259 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind identifies how a line differs between the two inputs
+type Kind int
+
+const (
+	// Equal lines are present in both inputs
+	Equal Kind = iota
+	// Delete lines are only present in the first (expected) input
+	Delete
+	// Insert lines are only present in the second (actual) input
+	Insert
+)
+
+// Line is a single line of an edit script
+type Line struct {
+	Kind Kind
+	Text string
+}
+
+// SplitLines splits text into lines, dropping a single trailing newline
+func SplitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// Lines computes a minimal line-based edit script turning a into b.
+// It uses the Myers O((N+M)D) algorithm so large, mostly-equal inputs stay cheap.
+func Lines(a, b []string) []Line {
+	// Trim the common prefix and suffix, which is the bulk of most YAML diffs
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var result []Line
+	for _, l := range a[:prefix] {
+		result = append(result, Line{Kind: Equal, Text: l})
+	}
+	result = append(result, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, l := range a[len(a)-suffix:] {
+		result = append(result, Line{Kind: Equal, Text: l})
+	}
+	return result
+}
+
+func myers(a, b []string) []Line {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+	max := n + m
+	offset := max
+	v := make([]int, 2*max+2)
+	var trace [][]int
+
+	found := false
+	for d := 0; d <= max && !found; d++ {
+		snapshot := make([]int, len(v))
+		copy(snapshot, v)
+		trace = append(trace, snapshot)
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+
+	// Walk the trace backwards to recover the edit script
+	var reversed []Line
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		vd := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && vd[offset+k-1] < vd[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := vd[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, Line{Kind: Equal, Text: a[x]})
+		}
+		if d > 0 {
+			if x == prevX {
+				y--
+				reversed = append(reversed, Line{Kind: Insert, Text: b[y]})
+			} else {
+				x--
+				reversed = append(reversed, Line{Kind: Delete, Text: a[x]})
+			}
+		}
+	}
+
+	result := make([]Line, len(reversed))
+	for i, l := range reversed {
+		result[len(reversed)-1-i] = l
+	}
+	return result
+}
+
+// Row is one row of a side-by-side rendering; Left or Right is empty for pure inserts/deletes
+type Row struct {
+	Left     string
+	Right    string
+	HasLeft  bool
+	HasRight bool
+	Changed  bool
+}
+
+// SideBySide pairs deleted and inserted lines so changes can be shown in two columns
+func SideBySide(lines []Line) []Row {
+	var rows []Row
+	for i := 0; i < len(lines); {
+		if lines[i].Kind == Equal {
+			rows = append(rows, Row{Left: lines[i].Text, Right: lines[i].Text, HasLeft: true, HasRight: true})
+			i++
+			continue
+		}
+		var deletes, inserts []string
+		for i < len(lines) && lines[i].Kind != Equal {
+			if lines[i].Kind == Delete {
+				deletes = append(deletes, lines[i].Text)
+			} else {
+				inserts = append(inserts, lines[i].Text)
+			}
+			i++
+		}
+		for j := 0; j < len(deletes) || j < len(inserts); j++ {
+			row := Row{Changed: true}
+			if j < len(deletes) {
+				row.Left, row.HasLeft = deletes[j], true
+			}
+			if j < len(inserts) {
+				row.Right, row.HasRight = inserts[j], true
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// Unified renders an edit script as a unified diff with the given number of context lines.
+// It returns an empty string when the inputs are equal.
+func Unified(fromName, toName string, lines []Line, context int) string {
+	changed := false
+	for _, l := range lines {
+		if l.Kind != Equal {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+
+	// Line numbers (0-based) of each entry in both inputs
+	aLine, bLine := make([]int, len(lines)), make([]int, len(lines))
+	for i, a, b := 0, 0, 0; i < len(lines); i++ {
+		aLine[i], bLine[i] = a, b
+		if lines[i].Kind != Insert {
+			a++
+		}
+		if lines[i].Kind != Delete {
+			b++
+		}
+	}
+
+	for i := 0; i < len(lines); {
+		if lines[i].Kind == Equal {
+			i++
+			continue
+		}
+		// Grow the hunk while changes are closer than two contexts apart
+		start := max(i-context, 0)
+		end := i
+		for end < len(lines) {
+			if lines[end].Kind != Equal {
+				end++
+				continue
+			}
+			next := end
+			for next < len(lines) && lines[next].Kind == Equal {
+				next++
+			}
+			if next == len(lines) || next-end > 2*context {
+				end = min(end+context, len(lines))
+				break
+			}
+			end = next
+		}
+
+		aCount, bCount := 0, 0
+		for _, l := range lines[start:end] {
+			if l.Kind != Insert {
+				aCount++
+			}
+			if l.Kind != Delete {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aLine[start], aCount), hunkRange(bLine[start], bCount))
+		for _, l := range lines[start:end] {
+			switch l.Kind {
+			case Equal:
+				sb.WriteString(" ")
+			case Delete:
+				sb.WriteString("-")
+			case Insert:
+				sb.WriteString("+")
+			}
+			sb.WriteString(l.Text + "\n")
+		}
+		i = end
+	}
+	return sb.String()
+}
+
+// hunkRange formats a hunk range the way diff -u does: 1-based start, count omitted when 1
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
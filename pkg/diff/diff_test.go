@@ -0,0 +1,109 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+// apply rebuilds both inputs from an edit script
+func apply(lines []Line) (a, b []string) {
+	for _, l := range lines {
+		switch l.Kind {
+		case Equal:
+			a = append(a, l.Text)
+			b = append(b, l.Text)
+		case Delete:
+			a = append(a, l.Text)
+		case Insert:
+			b = append(b, l.Text)
+		}
+	}
+	return a, b
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		changes int
+	}{
+		{"identical", "a\nb\nc", "a\nb\nc", 0},
+		{"empty to text", "", "a\nb", 2},
+		{"text to empty", "a\nb", "", 2},
+		{"single change", "a\nb\nc", "a\nx\nc", 2},
+		{"insert in middle", "a\nc", "a\nb\nc", 1},
+		{"delete at end", "a\nb\nc", "a\nb", 1},
+		{"interleaved", "a\nb\nc\nd\ne", "b\nc\nx\ne\nf", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := SplitLines(tt.a), SplitLines(tt.b)
+			lines := Lines(a, b)
+
+			gotA, gotB := apply(lines)
+			if strings.Join(gotA, "\n") != strings.Join(a, "\n") || strings.Join(gotB, "\n") != strings.Join(b, "\n") {
+				t.Fatalf("edit script does not reproduce inputs: %+v", lines)
+			}
+
+			changes := 0
+			for _, l := range lines {
+				if l.Kind != Equal {
+					changes++
+				}
+			}
+			if changes != tt.changes {
+				t.Errorf("Expected %d changed lines, got %d: %+v", tt.changes, changes, lines)
+			}
+		})
+	}
+}
+
+func TestSideBySide(t *testing.T) {
+	rows := SideBySide(Lines(SplitLines("a\nb\nc"), SplitLines("a\nx\ny\nc")))
+	if len(rows) != 4 {
+		t.Fatalf("Expected 4 rows, got %d: %+v", len(rows), rows)
+	}
+	if !rows[1].Changed || rows[1].Left != "b" || rows[1].Right != "x" {
+		t.Errorf("Expected b/x pair, got %+v", rows[1])
+	}
+	if rows[2].HasLeft || rows[2].Right != "y" {
+		t.Errorf("Expected pure insert of y, got %+v", rows[2])
+	}
+}
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"identical", "a\nb", "a\nb", ""},
+		{
+			name: "single change with context",
+			a:    "1\n2\n3\n4\n5\n6\n7",
+			b:    "1\n2\n3\nx\n5\n6\n7",
+			want: "--- expected\n+++ actual\n@@ -2,5 +2,5 @@\n 2\n 3\n-4\n+x\n 5\n 6\n",
+		},
+		{
+			name: "separate hunks",
+			a:    "a\n1\n2\n3\n4\n5\nb",
+			b:    "A\n1\n2\n3\n4\n5\nB",
+			want: "--- expected\n+++ actual\n@@ -1,3 +1,3 @@\n-a\n+A\n 1\n 2\n@@ -5,3 +5,3 @@\n 4\n 5\n-b\n+B\n",
+		},
+		{
+			name: "all inserted",
+			a:    "",
+			b:    "a",
+			want: "--- expected\n+++ actual\n@@ -0,0 +1 @@\n+a\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified("expected", "actual", Lines(SplitLines(tt.a), SplitLines(tt.b)), 2)
+			if got != tt.want {
+				t.Errorf("Unified() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
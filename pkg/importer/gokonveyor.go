@@ -0,0 +1,331 @@
+// Package importer converts test suites of other harnesses into koncur tests.
+package importer
+
+import (
+	"fmt"
+	"go/ast"
+	"regexp"
+	"slices"
+	"strings"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/analyzer-lsp/provider"
+	"github.com/konveyor/test-harness/pkg/config"
+	"go.lsp.dev/uri"
+)
+
+// TestCase is a go-konveyor-tests test case converted into a koncur test
+type TestCase struct {
+	// Dir is the directory name of the test, derived from its name
+	Dir  string
+	Test *config.TestDefinition
+	// Expected is the expected output built from the expected issues and tags
+	Expected []konveyor.RuleSet
+	// Skip is set for test cases marked Skip in go-konveyor-tests
+	Skip bool
+	// Warnings are parts of the test case that could not be converted
+	Warnings []string
+}
+
+// Rulesets the Hub reports application tags under, by tag source
+const (
+	discoveryRuleSet  = "discovery-rules"
+	technologyRuleSet = "technology-usage"
+)
+
+// ImportGoKonveyorTests converts the TC test cases of go-konveyor-tests source files
+// (e.g. the analysis and data packages) into koncur tests. Test cases listed in
+// package-level slices such as Tier0TestCases are tagged after the slice ("tier0").
+func ImportGoKonveyorTests(paths ...string) ([]TestCase, error) {
+	src, err := loadGoSource(paths...)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	cases := map[string]*goStruct{}
+	tags := map[string][]string{}
+	addCase := func(name string, tc *goStruct) {
+		if _, exists := cases[name]; !exists {
+			names = append(names, name)
+			cases[name] = tc
+		}
+	}
+	for _, v := range src.vars {
+		switch value := src.lookup(v.Name).(type) {
+		case *goStruct:
+			if value.Type == "TC" {
+				addCase(v.Name, value)
+			}
+		case []any:
+			tag := tierTag(v.Name)
+			for _, member := range src.listMembers(v.Value, map[string]bool{}) {
+				tc, ok := src.eval(member, "TC").(*goStruct)
+				if !ok || tc.Type != "TC" {
+					continue
+				}
+				// Test cases defined inline in the list are named after their Name field
+				name := tc.str("Name")
+				switch elt := member.(type) {
+				case *ast.Ident:
+					name = elt.Name
+				case *ast.SelectorExpr:
+					name = elt.Sel.Name
+				}
+				addCase(name, tc)
+				if !slices.Contains(tags[name], tag) {
+					tags[name] = append(tags[name], tag)
+				}
+			}
+		}
+	}
+
+	var imported []TestCase
+	taken := map[string]bool{}
+	for _, name := range names {
+		tc := convertTC(name, cases[name])
+		tc.Test.Tags = tags[name]
+		// Directory and test names must be unique, tests share work directories by name
+		base := tc.Dir
+		for n := 2; taken[tc.Dir]; n++ {
+			tc.Dir = fmt.Sprintf("%s-%d", base, n)
+		}
+		taken[tc.Dir] = true
+		tc.Test.Name = tc.Dir
+		imported = append(imported, tc)
+	}
+	return imported, nil
+}
+
+var (
+	tierSuffix  = regexp.MustCompile(`(TestCases|Tests|TCs|Cases)$`)
+	nonSlugChar = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// tierTag derives a tag from the name of a test case list, e.g. Tier0TestCases -> tier0
+func tierTag(name string) string {
+	if trimmed := tierSuffix.ReplaceAllString(name, ""); trimmed != "" {
+		name = trimmed
+	}
+	return slug(name)
+}
+
+// slug turns a name into a lower-case directory name
+func slug(name string) string {
+	return strings.Trim(nonSlugChar.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// convertTC converts one TC test case
+func convertTC(varName string, tc *goStruct) TestCase {
+	name := tc.str("Name")
+	if name == "" {
+		name = varName
+	}
+	imported := TestCase{Dir: slug(name), Skip: tc.boolean("Skip")}
+	warn := func(format string, args ...any) {
+		imported.Warnings = append(imported.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	analysis := config.AnalysisConfig{
+		Application:   applicationURL(tc, warn),
+		LabelSelector: labelSelector(tc.object("Labels")),
+		Source:        tc.strings("Sources"),
+		Target:        tc.strings("Targets"),
+		AnalysisMode:  provider.SourceOnlyAnalysisMode,
+	}
+	if tc.boolean("WithDeps") {
+		analysis.AnalysisMode = provider.FullAnalysisMode
+	}
+
+	// Rules from a repository can be referenced; uploaded rule files cannot
+	if rules := tc.object("Rules"); rules != nil {
+		if repo := rules.object("Repository"); repo != nil && repo.str("URL") != "" {
+			analysis.Rules = append(analysis.Rules, gitURL(repo))
+		} else {
+			warn("custom rules (Rules) were not imported, add them to analysis.rules")
+		}
+	}
+	if len(tc.list("CustomRules")) > 0 {
+		warn("custom rule files (CustomRules) were not imported, add them to analysis.rules")
+	}
+	if len(tc.list("Identities")) > 0 {
+		warn("the test uses identities (e.g. Maven settings), configure them in the target config or with requireMavenSettings")
+	}
+
+	imported.Test = &config.TestDefinition{
+		Name:        imported.Dir,
+		Description: name,
+		Analysis:    analysis,
+		Expect: config.ExpectConfig{
+			Output: config.ExpectedOutput{File: "expected-output.yaml"},
+		},
+	}
+	imported.Expected = expectedRuleSets(tc)
+	return imported
+}
+
+// applicationURL returns the git URL (with #branch/path) or binary of the application
+func applicationURL(tc *goStruct, warn func(string, ...any)) string {
+	app := tc.object("Application")
+	if tc.boolean("Binary") || app.str("Binary") != "" {
+		binary := tc.str("Artifact")
+		if binary == "" {
+			binary = app.str("Binary")
+		}
+		warn("binary application %q must be copied next to the test and analysis.application updated", binary)
+		return binary
+	}
+	repo := app.object("Repository")
+	if repo == nil || repo.str("URL") == "" {
+		warn("application %q has no repository URL", app.str("Name"))
+		return ""
+	}
+	return gitURL(repo)
+}
+
+// gitURL formats a repository as a koncur git URL: url#branch/path
+func gitURL(repo *goStruct) string {
+	url := repo.str("URL")
+	ref := repo.str("Branch")
+	if ref == "" {
+		ref = repo.str("Tag")
+	}
+	if path := strings.Trim(repo.str("Path"), "/"); path != "" {
+		// The path can only be given after a ref
+		if ref == "" {
+			ref = "main"
+		}
+		return url + "#" + ref + "/" + path
+	}
+	if ref != "" {
+		return url + "#" + ref
+	}
+	return url
+}
+
+// labelSelector turns included and excluded labels into a label selector expression
+func labelSelector(labels *goStruct) string {
+	included := strings.Join(labels.strings("Included"), " || ")
+	excluded := strings.Join(labels.strings("Excluded"), " || ")
+	switch {
+	case included != "" && excluded != "":
+		return fmt.Sprintf("(%s) && !(%s)", included, excluded)
+	case excluded != "":
+		return fmt.Sprintf("!(%s)", excluded)
+	}
+	return included
+}
+
+// expectedRuleSets builds rulesets from the expected issues (or insights) and tags,
+// the way the Hub target reports them: issues without effort are insights, and
+// language tags go to discovery-rules while other tags go to technology-usage
+func expectedRuleSets(tc *goStruct) []konveyor.RuleSet {
+	analysis := tc.object("Analysis")
+	issues := analysis.list("Issues")
+	if len(issues) == 0 {
+		issues = analysis.list("Insights")
+	}
+
+	rulesets := map[string]*konveyor.RuleSet{}
+	ruleset := func(name string) *konveyor.RuleSet {
+		rs, ok := rulesets[name]
+		if !ok {
+			rs = &konveyor.RuleSet{Name: name}
+			rulesets[name] = rs
+		}
+		return rs
+	}
+
+	for _, item := range issues {
+		issue, ok := item.(*goStruct)
+		if !ok || issue.str("Rule") == "" {
+			continue
+		}
+		category := konveyor.Category(issue.str("Category"))
+		effort := issue.integer("Effort")
+		v := konveyor.Violation{
+			Description: issue.str("Description"),
+			Category:    &category,
+			Labels:      issue.strings("Labels"),
+			Effort:      &effort,
+		}
+		for _, item := range issue.list("Incidents") {
+			incident, ok := item.(*goStruct)
+			if !ok {
+				continue
+			}
+			line := incident.integer("Line")
+			v.Incidents = append(v.Incidents, konveyor.Incident{
+				URI:        uri.URI("file://" + containerPath(incident.str("File"))),
+				Message:    incident.str("Message"),
+				CodeSnip:   incident.str("CodeSnip"),
+				LineNumber: &line,
+			})
+		}
+		for _, item := range issue.list("Links") {
+			if link, ok := item.(*goStruct); ok {
+				v.Links = append(v.Links, konveyor.Link{URL: link.str("URL"), Title: link.str("Title")})
+			}
+		}
+
+		rs := ruleset(issue.str("RuleSet"))
+		if effort == 0 {
+			if rs.Insights == nil {
+				rs.Insights = map[string]konveyor.Violation{}
+			}
+			rs.Insights[issue.str("Rule")] = v
+		} else {
+			if rs.Violations == nil {
+				rs.Violations = map[string]konveyor.Violation{}
+			}
+			rs.Violations[issue.str("Rule")] = v
+		}
+	}
+
+	for _, item := range tc.list("AnalysisTags") {
+		tag, ok := item.(*goStruct)
+		if !ok || tag.str("Name") == "" {
+			continue
+		}
+		name := technologyRuleSet
+		if strings.EqualFold(tag.object("Category").str("Name"), "Language") {
+			name = discoveryRuleSet
+		}
+		rs := ruleset(name)
+		if !slices.Contains(rs.Tags, tag.str("Name")) {
+			rs.Tags = append(rs.Tags, tag.str("Name"))
+		}
+	}
+
+	names := make([]string, 0, len(rulesets))
+	for name := range rulesets {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	result := make([]konveyor.RuleSet, 0, len(names))
+	for _, name := range names {
+		result = append(result, *rulesets[name])
+	}
+	return result
+}
+
+// containerPath maps the paths of go-konveyor-tests incidents to the paths the Hub
+// target reports: sources under /source and the Maven repository under /m2
+func containerPath(path string) string {
+	switch {
+	case strings.HasPrefix(path, "/opt/input/source/"):
+		return "/source/" + strings.TrimPrefix(path, "/opt/input/source/")
+	case strings.HasPrefix(path, "/shared/source/"):
+		// Older releases cloned sources into /shared/source/<application>
+		rest := strings.TrimPrefix(path, "/shared/source/")
+		if _, after, ok := strings.Cut(rest, "/"); ok {
+			return "/source/" + after
+		}
+		return "/source/" + rest
+	case strings.HasPrefix(path, "/cache/m2/"):
+		return "/m2/" + strings.TrimPrefix(path, "/cache/m2/")
+	case strings.HasPrefix(path, "/shared/m2/"):
+		return "/m2/" + strings.TrimPrefix(path, "/shared/m2/")
+	}
+	return path
+}
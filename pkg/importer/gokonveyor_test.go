@@ -0,0 +1,220 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/analyzer-lsp/provider"
+)
+
+const dataSource = `package data
+
+import "github.com/konveyor/tackle2-hub/api"
+
+const testappURL = "https://github.com/konveyor/tackle-testapp-public"
+
+var TackleTestappPublic = api.Application{
+	Name: "Tackle Testapp public",
+	Repository: &api.Repository{
+		Kind:   "git",
+		URL:    testappURL,
+		Branch: "ci-" + "2024",
+	},
+}
+
+var Daytrader = api.Application{
+	Name:       "Daytrader",
+	Repository: &api.Repository{URL: "https://github.com/WASdev/sample.daytrader7", Path: "daytrader-ee7"},
+}
+`
+
+const analysisSource = `package analysis
+
+import (
+	"github.com/konveyor/go-konveyor-tests/data"
+	"github.com/konveyor/tackle2-hub/api"
+)
+
+var Tier0TestCases = []TC{
+	TackleTestappPublicWithDeps,
+}
+
+var Tier1TestCases = append(Tier0TestCases, DaytraderSource)
+
+var TackleTestappPublicWithDeps = TC{
+	Name:        "Tackle Testapp public with deps",
+	Application: data.TackleTestappPublic,
+	WithDeps:    true,
+	Labels: addon.Labels{
+		Included: []string{"konveyor.io/target=cloud-readiness", "konveyor.io/target=quarkus"},
+		Excluded: []string{"konveyor.io/target=jakarta-ee"},
+	},
+	Identities: []api.Identity{tc.MavenIdentity},
+	Analysis: api.Analysis{
+		Effort: 1,
+		Issues: []api.Issue{
+			{
+				Category:    "mandatory",
+				Description: "File system - Java IO",
+				Effort:      1,
+				RuleSet:     "cloud-readiness",
+				Rule:        "local-storage-00001",
+				Incidents: []api.Incident{
+					{
+						File:    "/shared/source/tackle-testapp-public/src/main/java/A.java",
+						Line:    12,
+						Message: "Local storage",
+					},
+				},
+			},
+			{
+				Category: "potential",
+				RuleSet:  "technology-usage",
+				Rule:     "embedded-framework-01",
+				Incidents: []api.Incident{{File: "/opt/input/source/pom.xml", Line: 1}},
+			},
+		},
+	},
+	AnalysisTags: []api.Tag{
+		{Name: "Java", Category: api.Ref{Name: "Language"}},
+		{Name: "EJB XML", Category: api.Ref{Name: "Bean"}},
+	},
+}
+
+var DaytraderSource = TC{
+	Name:        "Daytrader source",
+	Skip:        true,
+	Application: data.Daytrader,
+	Sources:     []string{"java-ee"},
+	Targets:     []string{"quarkus"},
+}
+`
+
+func TestImportGoKonveyorTests(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"data/data.go":              dataSource,
+		"analysis/tc_testapp.go":    analysisSource,
+		"analysis/analysis_test.go": "package analysis\n\nvar Ignored = TC{Name: \"ignored\"}\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases, err := ImportGoKonveyorTests(filepath.Join(dir, "analysis"), filepath.Join(dir, "data"))
+	if err != nil {
+		t.Fatalf("ImportGoKonveyorTests() error = %v", err)
+	}
+	if len(cases) != 2 {
+		t.Fatalf("expected 2 test cases, got %d: %+v", len(cases), cases)
+	}
+
+	testapp := cases[0]
+	if testapp.Dir != "tackle-testapp-public-with-deps" || testapp.Test.Name != testapp.Dir {
+		t.Errorf("unexpected test name %q (dir %q)", testapp.Test.Name, testapp.Dir)
+	}
+	analysis := testapp.Test.Analysis
+	if analysis.Application != "https://github.com/konveyor/tackle-testapp-public#ci-2024" {
+		t.Errorf("unexpected application %q", analysis.Application)
+	}
+	if analysis.LabelSelector != "(konveyor.io/target=cloud-readiness || konveyor.io/target=quarkus) && !(konveyor.io/target=jakarta-ee)" {
+		t.Errorf("unexpected label selector %q", analysis.LabelSelector)
+	}
+	if analysis.AnalysisMode != provider.FullAnalysisMode {
+		t.Errorf("expected full analysis with deps, got %q", analysis.AnalysisMode)
+	}
+	if !reflect.DeepEqual(testapp.Test.Tags, []string{"tier0", "tier1"}) {
+		t.Errorf("expected tier tags, got %v", testapp.Test.Tags)
+	}
+	if len(testapp.Warnings) != 1 {
+		t.Errorf("expected an identities warning, got %v", testapp.Warnings)
+	}
+
+	if len(testapp.Expected) != 3 {
+		t.Fatalf("expected cloud-readiness, discovery-rules and technology-usage rulesets, got %+v", testapp.Expected)
+	}
+	cloud := testapp.Expected[0]
+	v, ok := cloud.Violations["local-storage-00001"]
+	if cloud.Name != "cloud-readiness" || !ok {
+		t.Fatalf("expected violation local-storage-00001 in cloud-readiness, got %+v", cloud)
+	}
+	if len(v.Incidents) != 1 || string(v.Incidents[0].URI) != "file:///source/src/main/java/A.java" || *v.Incidents[0].LineNumber != 12 {
+		t.Errorf("unexpected incidents %+v", v.Incidents)
+	}
+	if discovery := testapp.Expected[1]; discovery.Name != discoveryRuleSet || !reflect.DeepEqual(discovery.Tags, []string{"Java"}) {
+		t.Errorf("expected the Java tag in discovery-rules, got %+v", discovery)
+	}
+	tech := testapp.Expected[2]
+	if !reflect.DeepEqual(tech.Tags, []string{"EJB XML"}) {
+		t.Errorf("expected the EJB XML tag in technology-usage, got %v", tech.Tags)
+	}
+	insight, ok := tech.Insights["embedded-framework-01"]
+	if !ok || string(insight.Incidents[0].URI) != "file:///source/pom.xml" {
+		t.Errorf("expected an insight for the issue without effort, got %+v", tech.Insights)
+	}
+
+	daytrader := cases[1]
+	if !daytrader.Skip || daytrader.Test.Analysis.Application != "https://github.com/WASdev/sample.daytrader7#main/daytrader-ee7" {
+		t.Errorf("unexpected daytrader test %+v", daytrader.Test.Analysis)
+	}
+	if !reflect.DeepEqual(daytrader.Test.Analysis.Source, []string{"java-ee"}) || daytrader.Test.Analysis.AnalysisMode != provider.SourceOnlyAnalysisMode {
+		t.Errorf("unexpected daytrader analysis %+v", daytrader.Test.Analysis)
+	}
+	if !reflect.DeepEqual(daytrader.Test.Tags, []string{"tier1"}) {
+		t.Errorf("expected tier1 tag, got %v", daytrader.Test.Tags)
+	}
+}
+
+func TestContainerPath(t *testing.T) {
+	tests := map[string]string{
+		"/shared/source/app/src/A.java":  "/source/src/A.java",
+		"/opt/input/source/pom.xml":      "/source/pom.xml",
+		"/cache/m2/org/lib/1.0/lib.jar":  "/m2/org/lib/1.0/lib.jar",
+		"/shared/m2/org/lib/1.0/lib.jar": "/m2/org/lib/1.0/lib.jar",
+		"/elsewhere/file.txt":            "/elsewhere/file.txt",
+	}
+	for in, want := range tests {
+		if got := containerPath(in); got != want {
+			t.Errorf("containerPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestImportGoKonveyorTests_DuplicateNames(t *testing.T) {
+	dir := t.TempDir()
+	source := `package analysis
+
+var Tier0TestCases = []TC{DaytraderA, DaytraderB, DaytraderC}
+
+var DaytraderA = TC{Name: "Daytrader source", Skip: true}
+
+var DaytraderB = TC{Name: "daytrader-source", Skip: true}
+
+var DaytraderC = TC{Name: "Daytrader source 2", Skip: true}
+`
+	if err := os.WriteFile(filepath.Join(dir, "tc.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cases, err := ImportGoKonveyorTests(dir, t.TempDir())
+	if err != nil {
+		t.Fatalf("ImportGoKonveyorTests() error = %v", err)
+	}
+	var dirs []string
+	for _, tc := range cases {
+		if tc.Test.Name != tc.Dir {
+			t.Errorf("expected test %q to be named after its directory %q", tc.Test.Name, tc.Dir)
+		}
+		dirs = append(dirs, tc.Dir)
+	}
+	if want := []string{"daytrader-source", "daytrader-source-2", "daytrader-source-2-2"}; !reflect.DeepEqual(dirs, want) {
+		t.Errorf("directories = %v, want %v", dirs, want)
+	}
+}
@@ -0,0 +1,337 @@
+package importer
+
+import (
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// goStruct is an evaluated struct (or map) composite literal
+type goStruct struct {
+	// Type is the type name without its package, e.g. "TC" for analysis.TC
+	Type   string
+	Fields map[string]any
+}
+
+// goSource evaluates the package-level variables and constants of Go source files.
+// Only literals, references to other declarations, & and string concatenation are
+// evaluated; anything else (function calls, positional struct fields) evaluates to nil.
+type goSource struct {
+	decls map[string]ast.Expr
+	// vars are the package-level variables in declaration order
+	vars []goVar
+
+	values     map[string]any
+	evaluating map[string]bool
+}
+
+// goVar is a package-level variable declaration
+type goVar struct {
+	Name  string
+	Value ast.Expr
+}
+
+// loadGoSource parses the Go files given as paths, and the Go files found in
+// directories given as paths. Test files and vendored code are skipped.
+func loadGoSource(paths ...string) (*goSource, error) {
+	src := &goSource{decls: map[string]ast.Expr{}, values: map[string]any{}, evaluating: map[string]bool{}}
+	fset := token.NewFileSet()
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != root && (d.Name() == "vendor" || d.Name() == "testdata" || strings.HasPrefix(d.Name(), ".")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if filepath.Ext(path) != ".go" || strings.HasSuffix(path, "_test.go") {
+				return nil
+			}
+			file, err := goparser.ParseFile(fset, path, nil, goparser.SkipObjectResolution)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+			src.add(file)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return src, nil
+}
+
+// add records the package-level declarations of a file. Declarations are looked up
+// by name regardless of their package; the first declaration of a name wins.
+func (s *goSource) add(file *ast.File) {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || (gen.Tok != token.VAR && gen.Tok != token.CONST) {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				if i >= len(vs.Values) || name.Name == "_" {
+					continue
+				}
+				if _, exists := s.decls[name.Name]; exists {
+					continue
+				}
+				s.decls[name.Name] = vs.Values[i]
+				if gen.Tok == token.VAR {
+					s.vars = append(s.vars, goVar{Name: name.Name, Value: vs.Values[i]})
+				}
+			}
+		}
+	}
+}
+
+// lookup evaluates a package-level declaration by name
+func (s *goSource) lookup(name string) any {
+	if value, ok := s.values[name]; ok {
+		return value
+	}
+	expr, ok := s.decls[name]
+	if !ok || s.evaluating[name] {
+		return nil
+	}
+	s.evaluating[name] = true
+	value := s.eval(expr, "")
+	delete(s.evaluating, name)
+	s.values[name] = value
+	return value
+}
+
+// eval evaluates an expression; elemType is the type of a composite literal
+// whose type is elided, e.g. the elements of []api.Issue{{...}}
+func (s *goSource) eval(expr ast.Expr, elemType string) any {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		switch e.Kind {
+		case token.STRING:
+			if v, err := strconv.Unquote(e.Value); err == nil {
+				return v
+			}
+		case token.INT:
+			if v, err := strconv.ParseInt(e.Value, 0, 64); err == nil {
+				return int(v)
+			}
+		case token.FLOAT:
+			if v, err := strconv.ParseFloat(e.Value, 64); err == nil {
+				return v
+			}
+		}
+	case *ast.Ident:
+		switch e.Name {
+		case "true":
+			return true
+		case "false":
+			return false
+		case "nil":
+			return nil
+		}
+		return s.lookup(e.Name)
+	case *ast.SelectorExpr:
+		return s.lookup(e.Sel.Name)
+	case *ast.ParenExpr:
+		return s.eval(e.X, elemType)
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			return s.eval(e.X, elemType)
+		}
+	case *ast.BinaryExpr:
+		if e.Op == token.ADD {
+			left, right := s.eval(e.X, ""), s.eval(e.Y, "")
+			if l, ok := left.(string); ok {
+				if r, ok := right.(string); ok {
+					return l + r
+				}
+			}
+			if l, ok := left.(int); ok {
+				if r, ok := right.(int); ok {
+					return l + r
+				}
+			}
+		}
+	case *ast.CallExpr:
+		// append(list, items...) is common to build tiers of test cases
+		if fn, ok := e.Fun.(*ast.Ident); ok && fn.Name == "append" {
+			var items []any
+			for i, arg := range e.Args {
+				value := s.eval(arg, "")
+				if list, ok := value.([]any); ok && (i == 0 || e.Ellipsis.IsValid()) {
+					items = append(items, list...)
+				} else {
+					items = append(items, value)
+				}
+			}
+			return items
+		}
+	case *ast.CompositeLit:
+		return s.evalComposite(e, elemType)
+	}
+	return nil
+}
+
+func (s *goSource) evalComposite(e *ast.CompositeLit, elemType string) any {
+	switch t := e.Type.(type) {
+	case *ast.ArrayType:
+		items := make([]any, 0, len(e.Elts))
+		for _, elt := range e.Elts {
+			items = append(items, s.eval(elt, typeName(t.Elt)))
+		}
+		return items
+	case *ast.MapType:
+		return s.evalFields(e, typeName(t.Value), true)
+	case nil:
+		if elemType == "" && len(e.Elts) > 0 {
+			if _, keyed := e.Elts[0].(*ast.KeyValueExpr); !keyed {
+				items := make([]any, 0, len(e.Elts))
+				for _, elt := range e.Elts {
+					items = append(items, s.eval(elt, ""))
+				}
+				return items
+			}
+		}
+		return s.evalFields(e, elemType, false)
+	default:
+		return s.evalFields(e, typeName(t), false)
+	}
+}
+
+// evalFields evaluates the keyed elements of a struct or map literal
+func (s *goSource) evalFields(e *ast.CompositeLit, name string, isMap bool) *goStruct {
+	value := &goStruct{Type: name, Fields: map[string]any{}}
+	if isMap {
+		value.Type = ""
+	}
+	for _, elt := range e.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key := ""
+		if ident, ok := kv.Key.(*ast.Ident); ok && !isMap {
+			key = ident.Name
+		} else {
+			key = fmt.Sprint(s.eval(kv.Key, ""))
+		}
+		elemType := ""
+		if isMap {
+			elemType = name
+		}
+		value.Fields[key] = s.eval(kv.Value, elemType)
+	}
+	return value
+}
+
+// listMembers returns the element expressions of a slice literal, following
+// references to other package-level slices and append calls
+func (s *goSource) listMembers(expr ast.Expr, visited map[string]bool) []ast.Expr {
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		if _, ok := e.Type.(*ast.ArrayType); ok {
+			return e.Elts
+		}
+	case *ast.Ident, *ast.SelectorExpr:
+		name := typeName(e)
+		if decl, ok := s.decls[name]; ok && !visited[name] {
+			visited[name] = true
+			return s.listMembers(decl, visited)
+		}
+	case *ast.CallExpr:
+		if fn, ok := e.Fun.(*ast.Ident); ok && fn.Name == "append" && len(e.Args) > 0 {
+			members := s.listMembers(e.Args[0], visited)
+			for i, arg := range e.Args[1:] {
+				if e.Ellipsis.IsValid() && i == len(e.Args)-2 {
+					members = append(members, s.listMembers(arg, visited)...)
+				} else {
+					members = append(members, arg)
+				}
+			}
+			return members
+		}
+	}
+	return nil
+}
+
+// typeName returns the name of a type expression without its package
+func typeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.StarExpr:
+		return typeName(t.X)
+	}
+	return ""
+}
+
+// str returns a string field, or "" when it is not a string
+func (g *goStruct) str(name string) string {
+	if g == nil {
+		return ""
+	}
+	v, _ := g.Fields[name].(string)
+	return v
+}
+
+func (g *goStruct) boolean(name string) bool {
+	if g == nil {
+		return false
+	}
+	v, _ := g.Fields[name].(bool)
+	return v
+}
+
+func (g *goStruct) integer(name string) int {
+	if g == nil {
+		return 0
+	}
+	v, _ := g.Fields[name].(int)
+	return v
+}
+
+func (g *goStruct) object(name string) *goStruct {
+	if g == nil {
+		return nil
+	}
+	v, _ := g.Fields[name].(*goStruct)
+	return v
+}
+
+func (g *goStruct) list(name string) []any {
+	if g == nil {
+		return nil
+	}
+	v, _ := g.Fields[name].([]any)
+	return v
+}
+
+func (g *goStruct) strings(name string) []string {
+	var values []string
+	for _, item := range g.list(name) {
+		if v, ok := item.(string); ok {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func (g *goStruct) has(name string) bool {
+	if g == nil {
+		return false
+	}
+	_, ok := g.Fields[name]
+	return ok
+}
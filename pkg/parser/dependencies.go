@@ -0,0 +1,54 @@
+package parser
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"slices"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"go.lsp.dev/uri"
+	"gopkg.in/yaml.v3"
+)
+
+// ParseDependencies reads a dependencies.yaml file written by kantra, or by a
+// target converting its dependencies to the same format
+func ParseDependencies(depsFile string) ([]konveyor.DepsFlatItem, error) {
+	data, err := os.ReadFile(depsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dependencies file %s: %w", depsFile, err)
+	}
+
+	var items []konveyor.DepsFlatItem
+	if err := yaml.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("failed to parse dependencies YAML: %w", err)
+	}
+	return items, nil
+}
+
+// NormalizeDependencies maps the file URIs of dependencies onto the canonical layout
+// of expected outputs and sorts the items by provider and file. Dependencies are
+// sorted when the items are marshaled.
+func NormalizeDependencies(items []konveyor.DepsFlatItem, testDir string) []konveyor.DepsFlatItem {
+	normalizer := URINormalizer{TestDir: testDir}
+	normalized := make([]konveyor.DepsFlatItem, 0, len(items))
+	for _, item := range items {
+		item.FileURI = string(normalizer.Normalize(uri.URI(item.FileURI)))
+		deps := make([]*konveyor.Dep, 0, len(item.Dependencies))
+		for _, dep := range item.Dependencies {
+			if dep == nil {
+				continue
+			}
+			d := *dep
+			d.FileURIPrefix = string(normalizer.Normalize(uri.URI(d.FileURIPrefix)))
+			d.Labels = sortedStrings(d.Labels)
+			deps = append(deps, &d)
+		}
+		item.Dependencies = deps
+		normalized = append(normalized, item)
+	}
+	slices.SortStableFunc(normalized, func(a, b konveyor.DepsFlatItem) int {
+		return cmp.Or(cmp.Compare(a.Provider, b.Provider), cmp.Compare(a.FileURI, b.FileURI))
+	})
+	return normalized
+}
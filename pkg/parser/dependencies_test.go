@@ -0,0 +1,42 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalizeDependencies(t *testing.T) {
+	depsFile := filepath.Join(t.TempDir(), "dependencies.yaml")
+	content := `- fileURI: file:///opt/input/source/pom.xml
+  provider: java
+  dependencies:
+  - name: org.springframework.spring-core
+    version: 5.3.0
+    labels: [konveyor.io/dep-source=open-source, konveyor.io/language=java]
+    prefix: file:///root/.m2/repository/org/springframework
+- fileURI: file:///opt/input/source/go.mod
+  provider: go
+  dependencies:
+  - name: golang.org/x/text
+    version: v0.3.0
+`
+	if err := os.WriteFile(depsFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := ParseDependencies(depsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items = NormalizeDependencies(items, "")
+	if len(items) != 2 || items[0].Provider != "go" || items[1].Provider != "java" {
+		t.Fatalf("expected items sorted by provider, got %+v", items)
+	}
+	if items[1].FileURI != "file:///source/pom.xml" {
+		t.Errorf("fileURI = %s, want file:///source/pom.xml", items[1].FileURI)
+	}
+	if prefix := items[1].Dependencies[0].FileURIPrefix; prefix != "file:///m2/org/springframework" {
+		t.Errorf("prefix = %s, want file:///m2/org/springframework", prefix)
+	}
+}
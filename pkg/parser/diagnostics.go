@@ -0,0 +1,12 @@
+package parser
+
+// Diagnostic is an incident as an editor shows it, e.g. in VSCode's problems panel
+type Diagnostic struct {
+	// URI is the file the diagnostic is shown in
+	URI string `json:"uri" yaml:"uri"`
+	// Line is the 1-based line of the diagnostic, like incident line numbers
+	Line int `json:"line" yaml:"line"`
+	// Rule is the diagnostic's code, the ID of the rule of the incident
+	Rule    string `json:"rule" yaml:"rule"`
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
@@ -0,0 +1,11 @@
+package parser
+
+// FixSuggestion is a fix suggested for an incident, e.g. by Kai
+type FixSuggestion struct {
+	// File is the path or URI of the file the fix changes
+	File string `json:"file" yaml:"file"`
+	// Diff is the unified diff of the change
+	Diff string `json:"diff" yaml:"diff"`
+	// Compiles reports whether the fixed code compiles, nil when it was not checked
+	Compiles *bool `json:"compiles,omitempty" yaml:"compiles,omitempty"`
+}
@@ -0,0 +1,241 @@
+package parser
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+// HubRuleSet is the ruleset Hub issues are grouped under when the export has no ruleset column
+const HubRuleSet = "hub"
+
+// IsHubIssuesExport reports whether a file is an issues export of the Hub UI:
+// an Excel workbook, or a CSV file with "Ruleset" and "Rule" columns
+func IsHubIssuesExport(file string) bool {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".xlsx":
+		return true
+	case ".csv":
+		f, err := os.Open(file)
+		if err != nil {
+			return false
+		}
+		defer f.Close()
+		reader := csv.NewReader(f)
+		reader.FieldsPerRecord = -1
+		header, err := reader.Read()
+		if err != nil {
+			return false
+		}
+		table := newReportTable([][]string{header})
+		return table.has("ruleset") && table.has("rule")
+	}
+	return false
+}
+
+// ParseHubIssuesExport converts an issues export of the Hub UI (CSV or Excel)
+// into rulesets, so the exported report can be validated against the
+// API-backed results of the same analysis
+func ParseHubIssuesExport(file string) ([]konveyor.RuleSet, error) {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".csv":
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open Hub issues export %s: %w", file, err)
+		}
+		defer f.Close()
+		return ParseHubIssuesCSV(f)
+	case ".xlsx":
+		r, err := zip.OpenReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open Hub issues export %s: %w", file, err)
+		}
+		defer r.Close()
+		records, err := readXLSXRecords(&r.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Hub issues export %s: %w", file, err)
+		}
+		return hubIssues(newReportTable(records))
+	}
+	return nil, fmt.Errorf("unsupported Hub issues export format: %s", file)
+}
+
+// ParseHubIssuesCSV reads the CSV issues export of the Hub UI. Columns are matched
+// by header: "Ruleset", "Rule", "Name" (or "Description"), "Category", "Effort",
+// "Labels", "Links", "File", "Line" and "Message". Every row is one incident.
+func ParseHubIssuesCSV(r io.Reader) ([]konveyor.RuleSet, error) {
+	table, err := readCSVTable(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Hub issues CSV: %w", err)
+	}
+	return hubIssues(table)
+}
+
+func hubIssues(table *reportTable) ([]konveyor.RuleSet, error) {
+	if table == nil {
+		return nil, nil
+	}
+	if !table.has("rule") && !table.has("ruleid") {
+		return nil, fmt.Errorf("failed to parse Hub issues export: no \"Rule\" column")
+	}
+
+	var findings []reportFinding
+	for _, row := range table.rows {
+		finding := reportFinding{
+			ruleSet:  table.get(row, "ruleset"),
+			ruleID:   table.get(row, "rule", "ruleid"),
+			category: table.get(row, "category"),
+			effort:   table.get(row, "effort"),
+			title:    table.get(row, "name", "description", "issue"),
+			message:  table.get(row, "message", "incidentmessage"),
+			file:     table.get(row, "file", "incidentfile", "filepath"),
+			line:     table.get(row, "line", "incidentline"),
+			labels:   hubLabels(table.get(row, "labels")),
+			links:    reportLinks(table.get(row, "links")),
+		}
+		// The Hub lists insights with neither category nor effort
+		if finding.category == "" && (finding.effort == "" || finding.effort == "0") {
+			finding.category = "information"
+		}
+		if finding.ruleID != "" {
+			findings = append(findings, finding)
+		}
+	}
+	return reportRuleSets(findings, HubRuleSet), nil
+}
+
+// hubLabels splits the labels cell of an export, separated by commas or new lines
+func hubLabels(cell string) []string {
+	var labels []string
+	for _, label := range strings.FieldsFunc(cell, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// xlsxSheet is the cell data of an Excel worksheet
+type xlsxSheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref       string `xml:"r,attr"`
+			Type      string `xml:"t,attr"`
+			Value     string `xml:"v"`
+			InlineStr string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// xlsxSharedStrings is the shared string table of an Excel workbook
+type xlsxSharedStrings struct {
+	Items []struct {
+		Text string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"si"`
+}
+
+// readXLSXRecords reads the rows of the first worksheet of an Excel workbook
+func readXLSXRecords(r *zip.Reader) ([][]string, error) {
+	var shared []string
+	var sheets []*zip.File
+	for _, f := range r.File {
+		switch {
+		case f.Name == "xl/sharedStrings.xml":
+			var sst xlsxSharedStrings
+			if err := decodeZipXML(f, &sst); err != nil {
+				return nil, err
+			}
+			for _, item := range sst.Items {
+				text := item.Text
+				for _, run := range item.Runs {
+					text += run.Text
+				}
+				shared = append(shared, text)
+			}
+		case path.Dir(f.Name) == "xl/worksheets" && strings.HasSuffix(f.Name, ".xml"):
+			sheets = append(sheets, f)
+		}
+	}
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("no worksheet found")
+	}
+	sort.Slice(sheets, func(i, j int) bool { return sheetNumber(sheets[i].Name) < sheetNumber(sheets[j].Name) })
+
+	var sheet xlsxSheet
+	if err := decodeZipXML(sheets[0], &sheet); err != nil {
+		return nil, err
+	}
+	records := make([][]string, 0, len(sheet.Rows))
+	for _, row := range sheet.Rows {
+		var record []string
+		for i, cell := range row.Cells {
+			col := i
+			if ref := columnIndex(cell.Ref); ref >= 0 {
+				col = ref
+			}
+			for len(record) <= col {
+				record = append(record, "")
+			}
+			switch cell.Type {
+			case "s":
+				idx, err := strconv.Atoi(cell.Value)
+				if err != nil || idx < 0 || idx >= len(shared) {
+					return nil, fmt.Errorf("invalid shared string %q in cell %s", cell.Value, cell.Ref)
+				}
+				record[col] = shared[idx]
+			case "inlineStr":
+				record[col] = cell.InlineStr
+			default:
+				record[col] = cell.Value
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func decodeZipXML(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// sheetNumber returns the number of a worksheet file such as xl/worksheets/sheet2.xml
+func sheetNumber(name string) int {
+	n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(path.Base(name), "sheet"), ".xml"))
+	if err != nil {
+		return int(^uint(0) >> 1)
+	}
+	return n
+}
+
+// columnIndex returns the zero-based column of a cell reference such as "AB12"
+func columnIndex(ref string) int {
+	col := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+	}
+	return col - 1
+}
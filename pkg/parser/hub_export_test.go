@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+const hubIssuesCSV = `Application,Ruleset,Rule,Name,Category,Effort,Labels,Links,File,Line,Message
+app,eap8,eap8-001,Replace javax,mandatory,1,"konveyor.io/target=eap8,konveyor.io/source",https://example.com/eap8,/shared/source/src/A.java,3,Replace javax.ejb
+app,eap8,eap8-001,Replace javax,mandatory,1,"konveyor.io/target=eap8,konveyor.io/source",https://example.com/eap8,/shared/source/src/B.java,9,Replace javax.ejb
+app,discovery,java-files,Java files,,0,,,/shared/source/src/A.java,,Java file
+`
+
+func TestParseHubIssuesCSV(t *testing.T) {
+	rulesets, err := ParseHubIssuesCSV(strings.NewReader(hubIssuesCSV))
+	if err != nil {
+		t.Fatalf("ParseHubIssuesCSV() error = %v", err)
+	}
+	if len(rulesets) != 2 || rulesets[0].Name != "discovery" || rulesets[1].Name != "eap8" {
+		t.Fatalf("expected rulesets discovery and eap8, got %+v", rulesets)
+	}
+	if _, ok := rulesets[0].Insights["java-files"]; !ok {
+		t.Errorf("expected java-files without category and effort as insight, got %+v", rulesets[0])
+	}
+	v, ok := rulesets[1].Violations["eap8-001"]
+	if !ok {
+		t.Fatalf("expected violation eap8-001, got %v", rulesets[1].Violations)
+	}
+	if v.Description != "Replace javax" || v.Category == nil || *v.Category != konveyor.Mandatory || v.Effort == nil || *v.Effort != 1 {
+		t.Errorf("unexpected violation %+v", v)
+	}
+	if len(v.Labels) != 2 || v.Labels[0] != "konveyor.io/source" {
+		t.Errorf("unexpected labels %v", v.Labels)
+	}
+	if len(v.Incidents) != 2 || *v.Incidents[1].LineNumber != 9 || v.Incidents[1].Message != "Replace javax.ejb" {
+		t.Errorf("unexpected incidents %+v", v.Incidents)
+	}
+
+	if _, err := ParseHubIssuesCSV(strings.NewReader("Name,File\nfoo,bar\n")); err == nil {
+		t.Error("expected an error for an export without a Rule column")
+	}
+}
+
+func TestParseHubIssuesExport_XLSX(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "issues.xlsx")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	files := map[string]string{
+		"xl/sharedStrings.xml": `<sst><si><t>Ruleset</t></si><si><t>Rule</t></si><si><t>Category</t></si>` +
+			`<si><r><t>eap</t></r><r><t>8</t></r></si><si><t>eap8-001</t></si><si><t>optional</t></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c><c r="E1" t="inlineStr"><is><t>Effort</t></is></c></row>` +
+			`<row r="2"><c r="A2" t="s"><v>3</v></c><c r="B2" t="s"><v>4</v></c><c r="C2" t="s"><v>5</v></c><c r="E2"><v>3</v></c></row>` +
+			`</sheetData></worksheet>`,
+	}
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if !IsHubIssuesExport(path) {
+		t.Fatal("expected an Excel workbook to be detected as Hub issues export")
+	}
+	rulesets, err := ParseHubIssuesExport(path)
+	if err != nil {
+		t.Fatalf("ParseHubIssuesExport() error = %v", err)
+	}
+	if len(rulesets) != 1 || rulesets[0].Name != "eap8" {
+		t.Fatalf("expected ruleset eap8, got %+v", rulesets)
+	}
+	v := rulesets[0].Violations["eap8-001"]
+	if v.Category == nil || *v.Category != konveyor.Optional || v.Effort == nil || *v.Effort != 3 {
+		t.Errorf("unexpected violation %+v", v)
+	}
+}
+
+func TestIsHubIssuesExport(t *testing.T) {
+	dir := t.TempDir()
+	hub := filepath.Join(dir, "hub.csv")
+	windup := filepath.Join(dir, "windup.csv")
+	if err := os.WriteFile(hub, []byte(hubIssuesCSV), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(windup, []byte("Rule Id,Title\njee-001,EJB\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !IsHubIssuesExport(hub) {
+		t.Error("expected a CSV with Ruleset and Rule columns to be a Hub issues export")
+	}
+	if IsHubIssuesExport(windup) {
+		t.Error("expected a Windup CSV not to be a Hub issues export")
+	}
+}
@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"cmp"
+	"slices"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+// Issue is a violation aggregated as issues tables show it, e.g. the Tackle UI's:
+// one row per rule with its number of incidents
+type Issue struct {
+	RuleSet     string `json:"ruleset" yaml:"ruleset"`
+	Rule        string `json:"rule" yaml:"rule"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Category    string `json:"category,omitempty" yaml:"category,omitempty"`
+	Effort      int    `json:"effort" yaml:"effort"`
+	Incidents   int    `json:"incidents" yaml:"incidents"`
+}
+
+// AggregateIssues returns the issues of rulesets, sorted by ruleset and rule.
+// Insights are not issues.
+func AggregateIssues(rulesets []konveyor.RuleSet) []Issue {
+	var issues []Issue
+	for _, rs := range rulesets {
+		for ruleID, v := range rs.Violations {
+			issue := Issue{
+				RuleSet:     rs.Name,
+				Rule:        ruleID,
+				Description: v.Description,
+				Incidents:   len(v.Incidents),
+			}
+			if v.Category != nil {
+				issue.Category = string(*v.Category)
+			}
+			if v.Effort != nil {
+				issue.Effort = *v.Effort
+			}
+			issues = append(issues, issue)
+		}
+	}
+	slices.SortFunc(issues, func(a, b Issue) int {
+		return cmp.Or(cmp.Compare(a.RuleSet, b.RuleSet), cmp.Compare(a.Rule, b.Rule))
+	})
+	return issues
+}
@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+func TestAggregateIssues(t *testing.T) {
+	mandatory := konveyor.Mandatory
+	effort := 3
+	rulesets := []konveyor.RuleSet{
+		{
+			Name: "rs-b",
+			Violations: map[string]konveyor.Violation{
+				"rule-002": {Description: "no category or effort", Incidents: []konveyor.Incident{{URI: "file:///a"}}},
+			},
+		},
+		{
+			Name: "rs-a",
+			Violations: map[string]konveyor.Violation{
+				"rule-001": {
+					Description: "mandatory",
+					Category:    &mandatory,
+					Effort:      &effort,
+					Incidents:   []konveyor.Incident{{URI: "file:///a"}, {URI: "file:///b"}},
+				},
+			},
+			Insights: map[string]konveyor.Violation{
+				"insight-001": {Description: "not an issue"},
+			},
+		},
+	}
+
+	want := []Issue{
+		{RuleSet: "rs-a", Rule: "rule-001", Description: "mandatory", Category: "mandatory", Effort: 3, Incidents: 2},
+		{RuleSet: "rs-b", Rule: "rule-002", Description: "no category or effort", Incidents: 1},
+	}
+	if got := AggregateIssues(rulesets); !reflect.DeepEqual(got, want) {
+		t.Errorf("AggregateIssues() = %+v, want %+v", got, want)
+	}
+	if got := AggregateIssues(nil); got != nil {
+		t.Errorf("AggregateIssues(nil) = %+v, want nil", got)
+	}
+}
@@ -0,0 +1,165 @@
+package parser
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Kinds of problems found in analysis logs
+const (
+	// LogProviderInit is a provider that failed to start or initialize
+	LogProviderInit = "provider-init"
+	// LogRuleError is a rule whose evaluation failed
+	LogRuleError = "rule-error"
+)
+
+// LogIssue is a provider problem reported in an analysis or provider log
+type LogIssue struct {
+	Kind     string `json:"kind" yaml:"kind"`
+	File     string `json:"file" yaml:"file"`
+	Line     int    `json:"line" yaml:"line"`
+	Provider string `json:"provider,omitempty" yaml:"provider,omitempty"`
+	RuleID   string `json:"ruleID,omitempty" yaml:"ruleID,omitempty"`
+	Message  string `json:"message" yaml:"message"`
+}
+
+func (i LogIssue) String() string {
+	subject := i.Provider
+	if i.RuleID != "" {
+		subject = i.RuleID
+	}
+	if subject != "" {
+		return fmt.Sprintf("%s (%s): %s", i.Kind, subject, i.Message)
+	}
+	return fmt.Sprintf("%s: %s", i.Kind, i.Message)
+}
+
+var (
+	// logfmtField matches key=value and key="quoted value" pairs of logrus text logs
+	logfmtField = regexp.MustCompile(`([A-Za-z_][\w.-]*)=("(?:[^"\\]|\\.)*"|\S+)`)
+	// plainErrorLevel matches the level of plain text logs, e.g. hub addon output
+	plainErrorLevel = regexp.MustCompile(`(^|[\s\[])(ERROR|FATAL)([\]\s:]|$)`)
+	ruleFailure     = regexp.MustCompile(`(?i)(evaluat|rule|condition|query)`)
+	providerFailure = regexp.MustCompile(`(?i)(provider|init|start|connect|language server|lsp|client)`)
+)
+
+// ParseLogFile extracts provider initialization errors and rule evaluation
+// failures from a kantra analysis.log, a provider log or a hub addon log
+func ParseLogFile(path string) ([]LogIssue, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log %s: %w", path, err)
+	}
+	defer f.Close()
+	return ParseLog(f, path)
+}
+
+// ParseLog extracts provider problems from log lines in logrus text, JSON or plain format
+func ParseLog(r io.Reader, file string) ([]LogIssue, error) {
+	var issues []LogIssue
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields, ok := parseLogLine(scanner.Text())
+		if !ok {
+			continue
+		}
+		issue, ok := classifyLogLine(fields)
+		if !ok {
+			continue
+		}
+		issue.File = file
+		issue.Line = line
+		issues = append(issues, issue)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read log %s: %w", file, err)
+	}
+	return issues, nil
+}
+
+// parseLogLine returns the fields of an error-level log line
+func parseLogLine(text string) (map[string]string, bool) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, false
+	}
+
+	fields := map[string]string{}
+	if strings.HasPrefix(text, "{") {
+		var raw map[string]any
+		if err := json.Unmarshal([]byte(text), &raw); err == nil {
+			for k, v := range raw {
+				fields[strings.ToLower(k)] = fmt.Sprint(v)
+			}
+			return fields, isErrorLevel(fields["level"])
+		}
+	}
+
+	if matches := logfmtField.FindAllStringSubmatch(text, -1); len(matches) > 0 {
+		for _, m := range matches {
+			value := m[2]
+			if strings.HasPrefix(value, `"`) {
+				value = strings.ReplaceAll(strings.Trim(value, `"`), `\"`, `"`)
+			}
+			fields[strings.ToLower(m[1])] = value
+		}
+		if level, ok := fields["level"]; ok {
+			return fields, isErrorLevel(level)
+		}
+	}
+
+	if plainErrorLevel.MatchString(text) {
+		return map[string]string{"msg": text}, true
+	}
+	return nil, false
+}
+
+func isErrorLevel(level string) bool {
+	switch strings.ToLower(level) {
+	case "error", "fatal", "panic":
+		return true
+	}
+	return false
+}
+
+// classifyLogLine decides whether an error line is a rule or provider failure
+func classifyLogLine(fields map[string]string) (LogIssue, bool) {
+	msg := fields["msg"]
+	if msg == "" {
+		msg = fields["message"]
+	}
+	if errText := fields["error"]; errText != "" {
+		msg = strings.TrimSpace(msg + ": " + errText)
+	} else if errText := fields["err"]; errText != "" {
+		msg = strings.TrimSpace(msg + ": " + errText)
+	}
+
+	issue := LogIssue{Message: msg, Provider: fields["provider"]}
+	if issue.Provider == "" {
+		issue.Provider = fields["provider-name"]
+	}
+	for _, key := range []string{"ruleid", "rule", "rule-id"} {
+		if fields[key] != "" {
+			issue.RuleID = fields[key]
+			break
+		}
+	}
+
+	switch {
+	case issue.RuleID != "" || ruleFailure.MatchString(msg):
+		issue.Kind = LogRuleError
+	case issue.Provider != "" || providerFailure.MatchString(msg):
+		issue.Kind = LogProviderInit
+	default:
+		return LogIssue{}, false
+	}
+	return issue, true
+}
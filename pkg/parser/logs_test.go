@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLog(t *testing.T) {
+	tests := []struct {
+		name     string
+		log      string
+		want     []LogIssue
+		wantNone bool
+	}{
+		{
+			name: "logrus provider init error",
+			log: `time="2024-01-01T00:00:00Z" level=info msg="starting providers"
+time="2024-01-01T00:00:01Z" level=error msg="unable to init the providers" provider=java error="java provider not ready: timeout"`,
+			want: []LogIssue{{Kind: LogProviderInit, Line: 2, Provider: "java", Message: "unable to init the providers: java provider not ready: timeout"}},
+		},
+		{
+			name: "logrus rule evaluation failure",
+			log:  `time="2024-01-01T00:00:02Z" level=error msg="failed to evaluate rule" ruleID=jakarta-00001 error="query failed"`,
+			want: []LogIssue{{Kind: LogRuleError, Line: 1, RuleID: "jakarta-00001", Message: "failed to evaluate rule: query failed"}},
+		},
+		{
+			name: "json log",
+			log:  `{"level":"error","msg":"failed to start language server","provider":"go"}`,
+			want: []LogIssue{{Kind: LogProviderInit, Line: 1, Provider: "go", Message: "failed to start language server"}},
+		},
+		{
+			name: "plain addon log",
+			log:  "[ERROR] Provider java failed to connect\nINFO done",
+			want: []LogIssue{{Kind: LogProviderInit, Line: 1, Message: "[ERROR] Provider java failed to connect"}},
+		},
+		{
+			name:     "warnings and unrelated errors are ignored",
+			log:      "level=warning msg=\"provider slow\"\nlevel=error msg=\"failed to write report\"\nno error here",
+			wantNone: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := ParseLog(strings.NewReader(tt.log), "analysis.log")
+			if err != nil {
+				t.Fatalf("ParseLog() error = %v", err)
+			}
+			if tt.wantNone {
+				if len(issues) != 0 {
+					t.Errorf("Expected no issues, got %+v", issues)
+				}
+				return
+			}
+			if len(issues) != len(tt.want) {
+				t.Fatalf("Expected %d issues, got %+v", len(tt.want), issues)
+			}
+			for i, want := range tt.want {
+				want.File = "analysis.log"
+				if issues[i] != want {
+					t.Errorf("issue %d = %+v, want %+v", i, issues[i], want)
+				}
+			}
+		})
+	}
+}
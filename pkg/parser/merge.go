@@ -0,0 +1,118 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+// ParseOutputs reads several output.yaml files, e.g. of per-module analyses,
+// and merges them into a single ruleset list
+func ParseOutputs(outputFiles ...string) ([]konveyor.RuleSet, error) {
+	outputs := make([][]konveyor.RuleSet, 0, len(outputFiles))
+	for _, file := range outputFiles {
+		rulesets, err := ParseOutput(file)
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, rulesets)
+	}
+	return MergeRuleSets(outputs...), nil
+}
+
+// MergeRuleSets merges the outputs of several analyses into one ruleset list,
+// de-duplicating rulesets by name. Tags, errors, violations and insights are combined,
+// with identical incidents kept once, and rules matched by any output are no longer
+// listed as unmatched or skipped. The result is sorted like SortRuleSets.
+func MergeRuleSets(outputs ...[]konveyor.RuleSet) []konveyor.RuleSet {
+	byName := map[string]*konveyor.RuleSet{}
+	var order []string
+	for _, rulesets := range outputs {
+		for _, rs := range rulesets {
+			merged, ok := byName[rs.Name]
+			if !ok {
+				merged = &konveyor.RuleSet{Name: rs.Name}
+				byName[rs.Name] = merged
+				order = append(order, rs.Name)
+			}
+			mergeRuleSet(merged, rs)
+		}
+	}
+	if order == nil {
+		return nil
+	}
+
+	merged := make([]konveyor.RuleSet, 0, len(order))
+	for _, name := range order {
+		rs := byName[name]
+		matched := func(ruleID string) bool {
+			_, violation := rs.Violations[ruleID]
+			_, insight := rs.Insights[ruleID]
+			return violation || insight
+		}
+		rs.Unmatched = slices.DeleteFunc(rs.Unmatched, matched)
+		rs.Skipped = slices.DeleteFunc(rs.Skipped, matched)
+		merged = append(merged, *rs)
+	}
+	return SortRuleSets(merged)
+}
+
+func mergeRuleSet(merged *konveyor.RuleSet, rs konveyor.RuleSet) {
+	if merged.Description == "" {
+		merged.Description = rs.Description
+	}
+	merged.Tags = appendUnique(merged.Tags, rs.Tags...)
+	merged.Unmatched = appendUnique(merged.Unmatched, rs.Unmatched...)
+	merged.Skipped = appendUnique(merged.Skipped, rs.Skipped...)
+	for ruleID, msg := range rs.Errors {
+		if merged.Errors == nil {
+			merged.Errors = map[string]string{}
+		}
+		if existing, ok := merged.Errors[ruleID]; ok && existing != msg {
+			msg = fmt.Sprintf("%s; %s", existing, msg)
+		}
+		merged.Errors[ruleID] = msg
+	}
+	merged.Violations = mergeViolations(merged.Violations, rs.Violations)
+	merged.Insights = mergeViolations(merged.Insights, rs.Insights)
+}
+
+func mergeViolations(merged, violations map[string]konveyor.Violation) map[string]konveyor.Violation {
+	for ruleID, v := range violations {
+		if merged == nil {
+			merged = map[string]konveyor.Violation{}
+		}
+		existing, ok := merged[ruleID]
+		if !ok {
+			v.Incidents = slices.Clone(v.Incidents)
+			v.Links = slices.Clone(v.Links)
+			v.Labels = slices.Clone(v.Labels)
+			merged[ruleID] = v
+			continue
+		}
+		existing.Labels = appendUnique(existing.Labels, v.Labels...)
+		for _, link := range v.Links {
+			if !slices.ContainsFunc(existing.Links, func(l konveyor.Link) bool { return l.URL == link.URL }) {
+				existing.Links = append(existing.Links, link)
+			}
+		}
+		for _, incident := range v.Incidents {
+			if !slices.ContainsFunc(existing.Incidents, func(i konveyor.Incident) bool { return reflect.DeepEqual(i, incident) }) {
+				existing.Incidents = append(existing.Incidents, incident)
+			}
+		}
+		merged[ruleID] = existing
+	}
+	return merged
+}
+
+func appendUnique(values []string, more ...string) []string {
+	for _, value := range more {
+		if !slices.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+func TestMergeRuleSets(t *testing.T) {
+	line := func(n int) *int { return &n }
+	mandatory := konveyor.Mandatory
+
+	tests := []struct {
+		name    string
+		outputs [][]konveyor.RuleSet
+		want    []konveyor.RuleSet
+	}{
+		{
+			name: "no outputs",
+			want: nil,
+		},
+		{
+			name: "rulesets de-duplicated by name",
+			outputs: [][]konveyor.RuleSet{
+				{
+					{
+						Name:      "rs",
+						Tags:      []string{"Java"},
+						Unmatched: []string{"rule-002", "rule-003"},
+						Violations: map[string]konveyor.Violation{
+							"rule-001": {
+								Category:  &mandatory,
+								Links:     []konveyor.Link{{URL: "https://a"}},
+								Incidents: []konveyor.Incident{{URI: "file:///a/A.java", LineNumber: line(1)}},
+							},
+						},
+					},
+				},
+				{
+					{
+						Name:      "rs",
+						Tags:      []string{"Java", "Spring"},
+						Unmatched: []string{"rule-001", "rule-003"},
+						Errors:    map[string]string{"rule-004": "failed"},
+						Violations: map[string]konveyor.Violation{
+							"rule-001": {
+								Category: &mandatory,
+								Links:    []konveyor.Link{{URL: "https://a"}, {URL: "https://b"}},
+								Incidents: []konveyor.Incident{
+									{URI: "file:///b/B.java", LineNumber: line(2)},
+									{URI: "file:///a/A.java", LineNumber: line(1)},
+								},
+							},
+						},
+						Insights: map[string]konveyor.Violation{
+							"rule-002": {Incidents: []konveyor.Incident{{URI: "file:///b/pom.xml"}}},
+						},
+					},
+					{Name: "other", Tags: []string{"Maven"}},
+				},
+			},
+			want: []konveyor.RuleSet{
+				{Name: "other", Tags: []string{"Maven"}},
+				{
+					Name:      "rs",
+					Tags:      []string{"Java", "Spring"},
+					Unmatched: []string{"rule-003"},
+					Errors:    map[string]string{"rule-004": "failed"},
+					Violations: map[string]konveyor.Violation{
+						"rule-001": {
+							Category: &mandatory,
+							Links:    []konveyor.Link{{URL: "https://a"}, {URL: "https://b"}},
+							Incidents: []konveyor.Incident{
+								{URI: "file:///a/A.java", LineNumber: line(1)},
+								{URI: "file:///b/B.java", LineNumber: line(2)},
+							},
+						},
+					},
+					Insights: map[string]konveyor.Violation{
+						"rule-002": {Incidents: []konveyor.Incident{{URI: "file:///b/pom.xml"}}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergeRuleSets(tt.outputs...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergeRuleSets() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOutputs(t *testing.T) {
+	dir := t.TempDir()
+	moduleA := filepath.Join(dir, "module-a.yaml")
+	moduleB := filepath.Join(dir, "module-b.yaml")
+	if err := os.WriteFile(moduleA, []byte("- name: rs\n  tags: [Java]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(moduleB, []byte("- name: rs\n  tags: [Quarkus]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rulesets, err := ParseOutputs(moduleA, moduleB)
+	if err != nil {
+		t.Fatalf("ParseOutputs() error = %v", err)
+	}
+	if len(rulesets) != 1 || !reflect.DeepEqual(rulesets[0].Tags, []string{"Java", "Quarkus"}) {
+		t.Errorf("ParseOutputs() = %+v, want one ruleset with both tags", rulesets)
+	}
+
+	if _, err := ParseOutputs(moduleA, filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("ParseOutputs() expected an error for a missing file")
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"regexp"
 	"strings"
 
 	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
@@ -27,20 +26,109 @@ func ParseOutput(outputFile string) ([]konveyor.RuleSet, error) {
 	return rulesets, nil
 }
 
-// FilterRuleSets filters out rulesets that don't have violations, insights, or tags
-// This is used to normalize output for comparison, removing empty rulesets
-func FilterRuleSets(rulesets []konveyor.RuleSet) []konveyor.RuleSet {
+// ParseOutputVersion parses an output.yaml file written by a target and converts
+// output of older kantra releases to the current konveyor.RuleSet shape. It returns
+// the schema version the output was detected as.
+func ParseOutputVersion(outputFile string) ([]konveyor.RuleSet, SchemaVersion, error) {
+	rulesets, err := ParseOutput(outputFile)
+	if err != nil {
+		return nil, "", err
+	}
+	version := DetectSchemaVersion(rulesets)
+	return ConvertOutput(rulesets, version), version, nil
+}
+
+// Ruleset sections that keep a ruleset when filtering
+const (
+	KeepViolations = "violations"
+	KeepInsights   = "insights"
+	KeepTags       = "tags"
+	KeepErrors     = "errors"
+	KeepUnmatched  = "unmatched"
+	KeepSkipped    = "skipped"
+)
+
+// DefaultKeep are the sections that keep a ruleset when a filter does not list any
+var DefaultKeep = []string{KeepViolations, KeepInsights, KeepTags}
+
+// RuleSetFilter selects the rulesets of an output that are compared and saved
+// as expected output. A ruleset is kept when one of the Keep sections is not empty.
+type RuleSetFilter struct {
+	// KeepAll keeps every ruleset, including empty ones
+	KeepAll bool `yaml:"keepAll,omitempty" json:"keepAll,omitempty"`
+	// Keep lists the sections that keep a ruleset: violations, insights, tags,
+	// errors, unmatched or skipped (default: violations, insights and tags)
+	Keep []string `yaml:"keep,omitempty" json:"keep,omitempty" validate:"omitempty,dive,oneof=violations insights tags errors unmatched skipped"`
+	// TagsOnly keeps only the tags of rulesets, as discovery analyses are compared
+	TagsOnly bool `yaml:"tagsOnly,omitempty" json:"tagsOnly,omitempty"`
+}
+
+// Apply returns the rulesets the filter keeps; a nil filter keeps rulesets
+// with violations, insights or tags
+func (f *RuleSetFilter) Apply(rulesets []konveyor.RuleSet) []konveyor.RuleSet {
+	keep := DefaultKeep
+	if f != nil {
+		if f.TagsOnly {
+			return tagsOnly(rulesets)
+		}
+		if f.KeepAll {
+			return rulesets
+		}
+		if len(f.Keep) > 0 {
+			keep = f.Keep
+		}
+	}
+
 	var filtered []konveyor.RuleSet
 	for _, rs := range rulesets {
-		// Keep rulesets that have violations, insights, or tags
-		if len(rs.Violations) > 0 || len(rs.Insights) > 0 || len(rs.Tags) > 0 {
-			filtered = append(filtered, rs)
+		for _, section := range keep {
+			if hasSection(rs, section) {
+				filtered = append(filtered, rs)
+				break
+			}
 		}
 	}
 	return filtered
 }
 
+// tagsOnly returns the tagged rulesets with their other sections dropped
+func tagsOnly(rulesets []konveyor.RuleSet) []konveyor.RuleSet {
+	var tagged []konveyor.RuleSet
+	for _, rs := range rulesets {
+		if len(rs.Tags) > 0 {
+			tagged = append(tagged, konveyor.RuleSet{Name: rs.Name, Description: rs.Description, Tags: rs.Tags})
+		}
+	}
+	return tagged
+}
+
+func hasSection(rs konveyor.RuleSet, section string) bool {
+	switch section {
+	case KeepViolations:
+		return len(rs.Violations) > 0
+	case KeepInsights:
+		return len(rs.Insights) > 0
+	case KeepTags:
+		return len(rs.Tags) > 0
+	case KeepErrors:
+		return len(rs.Errors) > 0
+	case KeepUnmatched:
+		return len(rs.Unmatched) > 0
+	case KeepSkipped:
+		return len(rs.Skipped) > 0
+	}
+	return false
+}
+
+// FilterRuleSets filters out rulesets that don't have violations, insights, or tags
+// This is used to normalize output for comparison, removing empty rulesets
+func FilterRuleSets(rulesets []konveyor.RuleSet) []konveyor.RuleSet {
+	var filter *RuleSetFilter
+	return filter.Apply(rulesets)
+}
+
 // NormalizeRuleSets normalizes rulesets for comparison by removing dynamic content
+// and sorting them into a stable order
 func NormalizeRuleSets(rulesets []konveyor.RuleSet, testDir string) ([]konveyor.RuleSet, error) {
 	normalizedRuleSets := []konveyor.RuleSet{}
 	var returnError error
@@ -74,7 +162,7 @@ func NormalizeRuleSets(rulesets []konveyor.RuleSet, testDir string) ([]konveyor.
 		}
 		normalizedRuleSets = append(normalizedRuleSets, newRuleSet)
 	}
-	return normalizedRuleSets, returnError
+	return SortRuleSets(normalizedRuleSets), returnError
 }
 
 func normalizeViolation(violation konveyor.Violation, testDir string) (konveyor.Violation, error) {
@@ -114,32 +202,7 @@ func normalizeIncident(incident konveyor.Incident, testDir string) (konveyor.Inc
 		LineNumber: incident.LineNumber,
 		Variables:  incident.Variables,
 	}
-	fileName := string(incident.URI)
-	if testDir != "" {
-		fileName = strings.ReplaceAll(fileName, testDir, "")
-	}
-
-	// Normalize Maven repository paths
-	if strings.Contains(fileName, "/root/.m2/repository") {
-		fileName = strings.ReplaceAll(fileName, "/root/.m2/repository/", "/m2/")
-	}
-	if strings.Contains(fileName, "/cache/m2/") {
-		fileName = strings.ReplaceAll(fileName, "/cache/m2/", "/m2/")
-	}
-
-	// Normalize Tackle Hub container paths
-	if strings.Contains(fileName, "/shared/source/") {
-		fileName = strings.ReplaceAll(fileName, "/shared/source", "/source")
-	}
-	if strings.Contains(fileName, "/opt/input/source") {
-		fileName = strings.ReplaceAll(fileName, "/opt/input/source", "/source")
-	}
-
-	// Normalize ephemeral java-bin paths (containers, temp dirs) to /source/
-	// This handles macOS (/var/folders/.../T/), Linux (/tmp/), and container storage
-	javaBinPattern := regexp.MustCompile(`.*/java-bin-\d+/`)
-	fileName = javaBinPattern.ReplaceAllString(fileName, "file:///source/")
-
+	fileName := string(URINormalizer{TestDir: testDir}.Normalize(incident.URI))
 	if fileName == "" {
 		return newIncident, fmt.Errorf("fileName went to empty: %s", incident.URI)
 	}
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"testing"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+func TestRuleSetFilter_Apply(t *testing.T) {
+	rulesets := []konveyor.RuleSet{
+		{Name: "violations", Violations: map[string]konveyor.Violation{"rule-001": {}}},
+		{Name: "tags", Tags: []string{"Java"}, Insights: map[string]konveyor.Violation{"tech-001": {}}},
+		{Name: "errors", Errors: map[string]string{"rule-002": "failed"}},
+		{Name: "unmatched", Unmatched: []string{"rule-003"}},
+		{Name: "empty"},
+	}
+
+	tests := []struct {
+		name   string
+		filter *RuleSetFilter
+		want   []string
+	}{
+		{
+			name: "default",
+			want: []string{"violations", "tags"},
+		},
+		{
+			name:   "keep errors and unmatched",
+			filter: &RuleSetFilter{Keep: []string{KeepErrors, KeepUnmatched}},
+			want:   []string{"errors", "unmatched"},
+		},
+		{
+			name:   "tags only",
+			filter: &RuleSetFilter{TagsOnly: true, KeepAll: true},
+			want:   []string{"tags"},
+		},
+		{
+			name:   "keep all",
+			filter: &RuleSetFilter{KeepAll: true},
+			want:   []string{"violations", "tags", "errors", "unmatched", "empty"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.filter.Apply(rulesets)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Apply() kept %d rulesets, want %v", len(got), tt.want)
+			}
+			for i, rs := range got {
+				if rs.Name != tt.want[i] {
+					t.Errorf("Apply()[%d] = %s, want %s", i, rs.Name, tt.want[i])
+				}
+				if tt.filter != nil && tt.filter.TagsOnly && len(rs.Insights) > 0 {
+					t.Errorf("Apply()[%d] kept insights of a tags-only filter", i)
+				}
+			}
+		})
+	}
+}
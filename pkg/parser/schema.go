@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"path/filepath"
+	"strings"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"go.lsp.dev/uri"
+)
+
+// SchemaVersion identifies the shape of an analyzer output file
+type SchemaVersion string
+
+const (
+	// SchemaCurrent is the current konveyor.RuleSet shape: findings without effort
+	// are reported as insights
+	SchemaCurrent SchemaVersion = "current"
+	// SchemaPreInsights is the output of kantra releases before insights existed:
+	// informational findings are violations without effort, and incident URIs may
+	// be plain paths
+	SchemaPreInsights SchemaVersion = "pre-insights"
+)
+
+// schemaConverters convert an output of an older schema version to the current shape
+var schemaConverters = map[SchemaVersion]func([]konveyor.RuleSet) []konveyor.RuleSet{
+	SchemaPreInsights: convertPreInsights,
+}
+
+// DetectSchemaVersion detects the schema version of analyzer output. An output
+// with insights, or whose violations all carry effort, has the current shape.
+func DetectSchemaVersion(rulesets []konveyor.RuleSet) SchemaVersion {
+	legacy := false
+	for _, rs := range rulesets {
+		if rs.Insights != nil {
+			return SchemaCurrent
+		}
+		for _, v := range rs.Violations {
+			if v.Effort == nil || *v.Effort == 0 {
+				legacy = true
+			}
+		}
+	}
+	if legacy {
+		return SchemaPreInsights
+	}
+	return SchemaCurrent
+}
+
+// ConvertOutput converts analyzer output of the given schema version to the
+// current konveyor.RuleSet shape
+func ConvertOutput(rulesets []konveyor.RuleSet, version SchemaVersion) []konveyor.RuleSet {
+	convert, ok := schemaConverters[version]
+	if !ok {
+		return rulesets
+	}
+	return convert(rulesets)
+}
+
+// convertPreInsights moves violations without effort to insights and turns
+// plain incident paths into file URIs
+func convertPreInsights(rulesets []konveyor.RuleSet) []konveyor.RuleSet {
+	converted := make([]konveyor.RuleSet, len(rulesets))
+	for i, rs := range rulesets {
+		violations := map[string]konveyor.Violation{}
+		insights := map[string]konveyor.Violation{}
+		for ruleID, v := range rs.Violations {
+			v.Incidents = convertPlainURIs(v.Incidents)
+			if v.Effort == nil || *v.Effort == 0 {
+				v.Effort = nil
+				v.Category = nil
+				insights[ruleID] = v
+				continue
+			}
+			violations[ruleID] = v
+		}
+		rs.Violations = violations
+		rs.Insights = insights
+		if len(rs.Violations) == 0 {
+			rs.Violations = nil
+		}
+		if len(rs.Insights) == 0 {
+			rs.Insights = nil
+		}
+		converted[i] = rs
+	}
+	return converted
+}
+
+func convertPlainURIs(incidents []konveyor.Incident) []konveyor.Incident {
+	if incidents == nil {
+		return nil
+	}
+	converted := make([]konveyor.Incident, len(incidents))
+	for i, inc := range incidents {
+		path := string(inc.URI)
+		if path != "" && !strings.Contains(path, "://") && (filepath.IsAbs(path) || strings.HasPrefix(path, "/")) {
+			inc.URI = uri.File(filepath.ToSlash(path))
+		}
+		converted[i] = inc
+	}
+	return converted
+}
@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+func TestDetectSchemaVersion(t *testing.T) {
+	effort := func(n int) *int { return &n }
+
+	tests := []struct {
+		name     string
+		rulesets []konveyor.RuleSet
+		want     SchemaVersion
+	}{
+		{
+			name: "empty output",
+			want: SchemaCurrent,
+		},
+		{
+			name: "insights",
+			rulesets: []konveyor.RuleSet{{
+				Name:       "rs",
+				Violations: map[string]konveyor.Violation{"rule-001": {}},
+				Insights:   map[string]konveyor.Violation{"rule-002": {}},
+			}},
+			want: SchemaCurrent,
+		},
+		{
+			name: "violations with effort",
+			rulesets: []konveyor.RuleSet{{
+				Name:       "rs",
+				Violations: map[string]konveyor.Violation{"rule-001": {Effort: effort(3)}},
+			}},
+			want: SchemaCurrent,
+		},
+		{
+			name: "violations without effort",
+			rulesets: []konveyor.RuleSet{{
+				Name:       "rs",
+				Violations: map[string]konveyor.Violation{"rule-001": {Effort: effort(3)}, "rule-002": {Effort: effort(0)}},
+			}},
+			want: SchemaPreInsights,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectSchemaVersion(tt.rulesets); got != tt.want {
+				t.Errorf("DetectSchemaVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOutputVersion_PreInsights(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output.yaml")
+	legacy := `- name: rs
+  violations:
+    rule-001:
+      description: Replace API
+      category: mandatory
+      effort: 3
+      incidents:
+        - uri: /opt/input/source/src/A.java
+          lineNumber: 4
+    rule-002:
+      description: Java technology
+      category: potential
+      incidents:
+        - uri: file:///opt/input/source/pom.xml
+`
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rulesets, version, err := ParseOutputVersion(path)
+	if err != nil {
+		t.Fatalf("ParseOutputVersion() error = %v", err)
+	}
+	if version != SchemaPreInsights {
+		t.Errorf("expected schema %s, got %s", SchemaPreInsights, version)
+	}
+	rs := rulesets[0]
+	if _, ok := rs.Violations["rule-001"]; !ok || len(rs.Violations) != 1 {
+		t.Fatalf("expected only rule-001 as violation, got %v", rs.Violations)
+	}
+	if got := rs.Violations["rule-001"].Incidents[0].URI; got != "file:///opt/input/source/src/A.java" {
+		t.Errorf("expected plain path converted to a file URI, got %s", got)
+	}
+	insight, ok := rs.Insights["rule-002"]
+	if !ok || insight.Category != nil {
+		t.Errorf("expected rule-002 as insight without category, got %+v", rs.Insights)
+	}
+	if !reflect.DeepEqual(rulesets, ConvertOutput(rulesets, SchemaCurrent)) {
+		t.Error("expected converting current output to be a no-op")
+	}
+}
@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"cmp"
+	"slices"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+// SortRuleSets returns a copy of rulesets in a stable order: rulesets by name, tags,
+// labels, unmatched and skipped rules alphabetically, and incidents by URI and line number.
+// Violations are maps and are already written in key order.
+// Saved expected outputs then produce minimal diffs, and comparisons don't depend
+// on the order a tool reported its findings in.
+func SortRuleSets(rulesets []konveyor.RuleSet) []konveyor.RuleSet {
+	if rulesets == nil {
+		return nil
+	}
+	sorted := make([]konveyor.RuleSet, len(rulesets))
+	for i, rs := range rulesets {
+		rs.Tags = sortedStrings(rs.Tags)
+		rs.Unmatched = sortedStrings(rs.Unmatched)
+		rs.Skipped = sortedStrings(rs.Skipped)
+		rs.Violations = sortViolations(rs.Violations)
+		rs.Insights = sortViolations(rs.Insights)
+		sorted[i] = rs
+	}
+	slices.SortStableFunc(sorted, func(a, b konveyor.RuleSet) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return sorted
+}
+
+func sortedStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return sorted
+}
+
+func sortViolations(violations map[string]konveyor.Violation) map[string]konveyor.Violation {
+	if violations == nil {
+		return nil
+	}
+	sorted := make(map[string]konveyor.Violation, len(violations))
+	for ruleID, v := range violations {
+		sorted[ruleID] = SortViolation(v)
+	}
+	return sorted
+}
+
+// SortViolation returns a copy of a violation with its labels and incidents in a stable order
+func SortViolation(v konveyor.Violation) konveyor.Violation {
+	v.Labels = sortedStrings(v.Labels)
+	if v.Incidents != nil {
+		v.Incidents = slices.Clone(v.Incidents)
+		slices.SortStableFunc(v.Incidents, compareIncidents)
+	}
+	return v
+}
+
+// compareIncidents orders incidents by URI, then line number, then message
+func compareIncidents(a, b konveyor.Incident) int {
+	if c := cmp.Compare(a.URI, b.URI); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(lineOrZero(a.LineNumber), lineOrZero(b.LineNumber)); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Message, b.Message)
+}
+
+func lineOrZero(line *int) int {
+	if line == nil {
+		return 0
+	}
+	return *line
+}
@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+func TestSortRuleSets(t *testing.T) {
+	line := func(n int) *int { return &n }
+	input := []konveyor.RuleSet{
+		{
+			Name:      "zeta",
+			Tags:      []string{"Spring", "Java"},
+			Unmatched: []string{"rule-2", "rule-1"},
+			Violations: map[string]konveyor.Violation{
+				"rule-001": {
+					Labels: []string{"konveyor.io/target=quarkus", "konveyor.io/source=java-ee"},
+					Incidents: []konveyor.Incident{
+						{URI: "file:///source/B.java", LineNumber: line(1)},
+						{URI: "file:///source/A.java", LineNumber: line(20)},
+						{URI: "file:///source/A.java", LineNumber: line(3)},
+					},
+				},
+			},
+		},
+		{Name: "alpha", Tags: []string{"b", "a"}},
+	}
+
+	sorted := SortRuleSets(input)
+
+	if sorted[0].Name != "alpha" || sorted[1].Name != "zeta" {
+		t.Errorf("Expected rulesets sorted by name, got %s, %s", sorted[0].Name, sorted[1].Name)
+	}
+	if want := []string{"Java", "Spring"}; !reflect.DeepEqual(sorted[1].Tags, want) {
+		t.Errorf("Tags = %v, want %v", sorted[1].Tags, want)
+	}
+	if want := []string{"rule-1", "rule-2"}; !reflect.DeepEqual(sorted[1].Unmatched, want) {
+		t.Errorf("Unmatched = %v, want %v", sorted[1].Unmatched, want)
+	}
+	incidents := sorted[1].Violations["rule-001"].Incidents
+	if incidents[0].URI != "file:///source/A.java" || *incidents[0].LineNumber != 3 ||
+		*incidents[1].LineNumber != 20 || incidents[2].URI != "file:///source/B.java" {
+		t.Errorf("Incidents not sorted by URI and line: %+v", incidents)
+	}
+
+	// The input is left untouched
+	if input[0].Name != "zeta" || input[0].Tags[0] != "Spring" || input[0].Violations["rule-001"].Incidents[0].URI != "file:///source/B.java" {
+		t.Error("SortRuleSets modified its input")
+	}
+}
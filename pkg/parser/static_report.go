@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+// staticReportApplication is an application of kantra's static report data
+type staticReportApplication struct {
+	Name     string             `json:"name"`
+	Rulesets []konveyor.RuleSet `json:"rulesets"`
+}
+
+// ParseStaticReport parses the output.js data of kantra's static report, which holds
+// the rulesets of every application of a bulk analysis, keyed by application name
+func ParseStaticReport(file string) (map[string][]konveyor.RuleSet, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read static report data: %w", err)
+	}
+	// The data is a script assigning the applications: window["apps"] = [...]
+	data = bytes.TrimSpace(data)
+	if !bytes.HasPrefix(data, []byte("[")) {
+		if eq := bytes.IndexByte(data, '='); eq >= 0 {
+			data = data[eq+1:]
+		}
+	}
+	data = bytes.TrimSuffix(bytes.TrimSpace(data), []byte(";"))
+
+	var apps []staticReportApplication
+	if err := json.Unmarshal(data, &apps); err != nil {
+		return nil, fmt.Errorf("failed to parse static report data %s: %w", file, err)
+	}
+	rulesets := make(map[string][]konveyor.RuleSet, len(apps))
+	for _, app := range apps {
+		if _, exists := rulesets[app.Name]; exists {
+			return nil, fmt.Errorf("static report data %s lists application %s twice", file, app.Name)
+		}
+		rulesets[app.Name] = app.Rulesets
+	}
+	return rulesets, nil
+}
@@ -0,0 +1,42 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseStaticReport(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "output.js")
+	data := `window["apps"] = [
+  {"id": "0000", "name": "coolstore", "rulesets": [{"name": "eap8", "violations": {"rule-001": {"description": "d", "incidents": [{"uri": "file:///opt/input/source/App.java", "message": "m"}]}}}], "depItems": []},
+  {"id": "0001", "name": "daytrader", "rulesets": [{"name": "discovery", "tags": ["Java"]}]}
+];
+`
+	if err := os.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	apps, err := ParseStaticReport(file)
+	if err != nil {
+		t.Fatalf("ParseStaticReport() error = %v", err)
+	}
+	if len(apps) != 2 {
+		t.Fatalf("expected 2 applications, got %d", len(apps))
+	}
+	if incidents := apps["coolstore"][0].Violations["rule-001"].Incidents; len(incidents) != 1 || incidents[0].Message != "m" {
+		t.Errorf("unexpected coolstore incidents %+v", incidents)
+	}
+	if tags := apps["daytrader"][0].Tags; len(tags) != 1 || tags[0] != "Java" {
+		t.Errorf("unexpected daytrader tags %v", tags)
+	}
+
+	duplicate := `window["apps"] = [{"name": "a"}, {"name": "a"}]`
+	if err := os.WriteFile(file, []byte(duplicate), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseStaticReport(file); err == nil {
+		t.Error("expected an application listed twice to be rejected")
+	}
+}
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"go.lsp.dev/uri"
+)
+
+// uriRewrite maps a tool-specific path onto the canonical layout of expected outputs
+type uriRewrite struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// uriRewrites normalize the locations different targets analyze from.
+// Dependencies end up under /m2/ and application sources under /source/.
+var uriRewrites = []uriRewrite{
+	// Maven repositories (local, kantra containers and hub caches)
+	{regexp.MustCompile(`/root/\.m2/repository/`), "/m2/"},
+	{regexp.MustCompile(`/cache/m2/`), "/m2/"},
+	// Tackle Hub and kantra container source directories
+	{regexp.MustCompile(`/shared/source/`), "/source/"},
+	{regexp.MustCompile(`/opt/input/source/`), "/source/"},
+	{regexp.MustCompile(`^/addon/source/`), "/source/"},
+	// Ephemeral java-bin directories of decompiled binaries
+	// (macOS /var/folders/.../T/, Linux /tmp/, container storage)
+	{regexp.MustCompile(`^.*/java-bin-\d+/`), "/source/"},
+}
+
+// windowsDrive matches the drive letter of a Windows path
+var windowsDrive = regexp.MustCompile(`^/?[A-Za-z]:/`)
+
+// URINormalizer maps incident URIs from any target onto one canonical path,
+// so outputs of kantra, the hub, local clones and Windows hosts compare equal
+type URINormalizer struct {
+	// TestDir is removed from paths, making local test files relative to the test
+	TestDir string
+	// StripPrefixes are removed from the start of canonical paths, e.g. the
+	// directory a target clones applications into
+	StripPrefixes []string
+}
+
+// NormalizePath returns the canonical path of a URI: without the file:// scheme,
+// with forward slashes, no drive letter, and the known tool locations rewritten
+func (n URINormalizer) NormalizePath(u uri.URI) string {
+	path := strings.TrimPrefix(string(u), "file://")
+	path = strings.ReplaceAll(path, `\`, "/")
+	if loc := windowsDrive.FindStringIndex(path); loc != nil {
+		path = "/" + path[loc[1]:]
+	}
+	if n.TestDir != "" {
+		testDir := strings.ReplaceAll(n.TestDir, `\`, "/")
+		if loc := windowsDrive.FindStringIndex(testDir); loc != nil {
+			testDir = "/" + testDir[loc[1]:]
+		}
+		path = strings.ReplaceAll(path, testDir, "")
+	}
+	for _, rewrite := range uriRewrites {
+		path = rewrite.pattern.ReplaceAllString(path, rewrite.replacement)
+	}
+	for _, prefix := range n.StripPrefixes {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if prefix != "" && (path == prefix || strings.HasPrefix(path, prefix+"/")) {
+			path = strings.TrimPrefix(path, prefix)
+			break
+		}
+	}
+	return path
+}
+
+// Normalize returns the canonical file URI of u. URIs that are not files are kept as is.
+func (n URINormalizer) Normalize(u uri.URI) uri.URI {
+	if !strings.HasPrefix(string(u), "file://") {
+		return u
+	}
+	path := n.NormalizePath(u)
+	if path == "" {
+		return ""
+	}
+	return uri.URI("file://" + path)
+}
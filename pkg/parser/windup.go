@@ -0,0 +1,356 @@
+package parser
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"go.lsp.dev/uri"
+)
+
+// WindupRuleSet is the ruleset legacy findings are grouped under when the report
+// does not name one; Windup reports only carry rule IDs
+const WindupRuleSet = "windup"
+
+// IsWindupReport reports whether a file is a legacy Windup/MTA report by its extension
+func IsWindupReport(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv", ".xml":
+		return true
+	}
+	return false
+}
+
+// ParseWindupReport converts a legacy Windup/MTA CSV or XML report into rulesets,
+// so analyzer-lsp output can be compared against historical Windup baselines
+func ParseWindupReport(path string) ([]konveyor.RuleSet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Windup report %s: %w", path, err)
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ParseWindupCSV(f)
+	case ".xml":
+		return ParseWindupXML(f)
+	}
+	return nil, fmt.Errorf("unsupported Windup report format: %s", path)
+}
+
+// reportFinding is one issue occurrence of an exported report
+type reportFinding struct {
+	ruleSet  string
+	ruleID   string
+	category string
+	effort   string
+	title    string
+	message  string
+	file     string
+	line     string
+	labels   []string
+	links    []konveyor.Link
+}
+
+// ParseWindupCSV reads the CSV export of Windup/MTA (--exportCSV). Columns are
+// matched by header, e.g. "Rule Id", "Issue Category", "Title", "Description",
+// "Links", "File Path", "Line" and "Story points".
+func ParseWindupCSV(r io.Reader) ([]konveyor.RuleSet, error) {
+	table, err := readCSVTable(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Windup CSV: %w", err)
+	}
+	if table == nil {
+		return nil, nil
+	}
+	if !table.has("ruleid") {
+		return nil, fmt.Errorf("failed to parse Windup CSV: no \"Rule Id\" column")
+	}
+
+	var findings []reportFinding
+	for _, row := range table.rows {
+		finding := reportFinding{
+			ruleSet:  table.get(row, "ruleset"),
+			ruleID:   table.get(row, "ruleid"),
+			category: table.get(row, "issuecategory", "category", "problemtype", "severity"),
+			effort:   table.get(row, "storypoints", "effort"),
+			title:    table.get(row, "title"),
+			message:  table.get(row, "description", "message"),
+			file:     table.get(row, "filepath", "path", "file"),
+			line:     table.get(row, "line", "linenumber"),
+			links:    reportLinks(table.get(row, "links")),
+		}
+		if finding.ruleID != "" {
+			findings = append(findings, finding)
+		}
+	}
+	return reportRuleSets(findings, WindupRuleSet), nil
+}
+
+// reportTable is a tabular report export with columns matched by normalized header
+type reportTable struct {
+	columns map[string]int
+	rows    [][]string
+}
+
+// newReportTable builds a table from its records; the first record is the header
+func newReportTable(records [][]string) *reportTable {
+	if len(records) == 0 {
+		return nil
+	}
+	table := &reportTable{columns: map[string]int{}, rows: records[1:]}
+	for i, name := range records[0] {
+		table.columns[reportKey(name)] = i
+	}
+	return table
+}
+
+// readCSVTable reads a CSV export, or returns nil when it is empty
+func readCSVTable(r io.Reader) (*reportTable, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	return newReportTable(records), nil
+}
+
+func (t *reportTable) has(name string) bool {
+	_, ok := t.columns[name]
+	return ok
+}
+
+// get returns the value of the first of the named columns the table has
+func (t *reportTable) get(row []string, names ...string) string {
+	for _, name := range names {
+		if i, ok := t.columns[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+	}
+	return ""
+}
+
+// reportLinks extracts link URLs from a report cell
+func reportLinks(cell string) []konveyor.Link {
+	var links []konveyor.Link
+	for _, u := range linkURL.FindAllString(cell, -1) {
+		links = append(links, konveyor.Link{URL: u})
+	}
+	return links
+}
+
+var linkURL = regexp.MustCompile(`https?://[^\s,;"'()\]]+`)
+
+// xmlElement is a generic XML element; legacy report layouts differ between
+// Windup and MTA versions, so elements are matched by name instead of a fixed schema
+type xmlElement struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr   `xml:",any,attr"`
+	Text     string       `xml:",chardata"`
+	Children []xmlElement `xml:",any"`
+}
+
+// ParseWindupXML reads a Windup/MTA XML issue report. Every <issue>, <hint> or
+// <classification> element is a finding; its rule ID, category, effort (story points),
+// title, message, file, line and links are read from attributes or child elements,
+// and <file>/<location> children each add an incident.
+func ParseWindupXML(r io.Reader) ([]konveyor.RuleSet, error) {
+	var root xmlElement
+	if err := xml.NewDecoder(r).Decode(&root); err != nil {
+		return nil, fmt.Errorf("failed to parse Windup XML: %w", err)
+	}
+
+	var findings []reportFinding
+	var walk func(e xmlElement, ruleSet string)
+	walk = func(e xmlElement, ruleSet string) {
+		switch reportKey(e.XMLName.Local) {
+		case "ruleset":
+			if name := e.field("name", "id"); name != "" {
+				ruleSet = name
+			}
+		case "issue", "hint", "classification":
+			findings = append(findings, e.findings(ruleSet)...)
+			return
+		}
+		for _, child := range e.Children {
+			walk(child, ruleSet)
+		}
+	}
+	walk(root, "")
+	return reportRuleSets(findings, WindupRuleSet), nil
+}
+
+// field returns the first attribute or child element text with one of the names
+func (e xmlElement) field(names ...string) string {
+	for _, name := range names {
+		for _, attr := range e.Attrs {
+			if reportKey(attr.Name.Local) == name {
+				return strings.TrimSpace(attr.Value)
+			}
+		}
+		for _, child := range e.Children {
+			if reportKey(child.XMLName.Local) == name && len(child.Children) == 0 {
+				return strings.TrimSpace(child.Text)
+			}
+		}
+	}
+	return ""
+}
+
+// findings returns one finding per file location of an issue element
+func (e xmlElement) findings(ruleSet string) []reportFinding {
+	base := reportFinding{
+		ruleSet:  ruleSet,
+		ruleID:   e.field("ruleid", "rule"),
+		category: e.field("issuecategory", "category", "severity"),
+		effort:   e.field("storypoints", "effort"),
+		title:    e.field("title", "name"),
+		message:  e.field("message", "description", "hint"),
+		file:     e.field("filepath", "path", "file"),
+		line:     e.field("line", "linenumber"),
+	}
+	if rs := e.field("ruleset"); rs != "" {
+		base.ruleSet = rs
+	}
+	if base.ruleID == "" {
+		return nil
+	}
+
+	var locations []xmlElement
+	for _, child := range e.Children {
+		switch reportKey(child.XMLName.Local) {
+		case "link":
+			u := child.field("href", "url")
+			if u == "" {
+				u = strings.TrimSpace(child.Text)
+			}
+			if u != "" {
+				base.links = append(base.links, konveyor.Link{URL: u, Title: child.field("title")})
+			}
+		case "file", "location", "incident":
+			if len(child.Attrs) > 0 || len(child.Children) > 0 {
+				locations = append(locations, child)
+			}
+		}
+	}
+	if len(locations) == 0 {
+		return []reportFinding{base}
+	}
+
+	findings := make([]reportFinding, 0, len(locations))
+	for _, loc := range locations {
+		finding := base
+		finding.file = loc.field("filepath", "path", "file", "name")
+		finding.line = loc.field("line", "linenumber")
+		if message := loc.field("message"); message != "" {
+			finding.message = message
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// reportKey normalizes a column, attribute or element name for matching
+func reportKey(name string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// reportRuleSets groups findings into rulesets by rule ID, under defaultRuleSet when
+// a finding names none. Findings without effort in the information category
+// become insights.
+func reportRuleSets(findings []reportFinding, defaultRuleSet string) []konveyor.RuleSet {
+	byName := map[string]*konveyor.RuleSet{}
+	var order []string
+	for _, f := range findings {
+		name := f.ruleSet
+		if name == "" {
+			name = defaultRuleSet
+		}
+		rs, ok := byName[name]
+		if !ok {
+			rs = &konveyor.RuleSet{Name: name, Violations: map[string]konveyor.Violation{}, Insights: map[string]konveyor.Violation{}}
+			byName[name] = rs
+			order = append(order, name)
+		}
+
+		category, insight := windupCategory(f.category)
+		effort, err := strconv.Atoi(f.effort)
+		hasEffort := err == nil
+		if hasEffort && effort > 0 {
+			insight = false
+		}
+		target := rs.Violations
+		if insight {
+			target = rs.Insights
+		}
+
+		v, ok := target[f.ruleID]
+		if !ok {
+			v = konveyor.Violation{Description: f.title, Category: category}
+			if hasEffort && !insight {
+				v.Effort = &effort
+			}
+		}
+		for _, label := range f.labels {
+			if !slices.Contains(v.Labels, label) {
+				v.Labels = append(v.Labels, label)
+			}
+		}
+		for _, link := range f.links {
+			if !slices.ContainsFunc(v.Links, func(l konveyor.Link) bool { return l.URL == link.URL }) {
+				v.Links = append(v.Links, link)
+			}
+		}
+		incident := konveyor.Incident{Message: f.message}
+		if f.file != "" {
+			incident.URI = uri.File(filepath.ToSlash(f.file))
+		}
+		if line, err := strconv.Atoi(f.line); err == nil {
+			incident.LineNumber = &line
+		}
+		v.Incidents = append(v.Incidents, incident)
+		target[f.ruleID] = v
+	}
+
+	rulesets := make([]konveyor.RuleSet, 0, len(order))
+	for _, name := range order {
+		rs := byName[name]
+		if len(rs.Insights) == 0 {
+			rs.Insights = nil
+		}
+		if len(rs.Violations) == 0 {
+			rs.Violations = nil
+		}
+		rulesets = append(rulesets, *rs)
+	}
+	return SortRuleSets(rulesets)
+}
+
+// windupCategory maps a Windup issue category onto analyzer-lsp categories.
+// Informational findings are reported as insights.
+func windupCategory(category string) (*konveyor.Category, bool) {
+	var c konveyor.Category
+	switch key := reportKey(category); {
+	case strings.Contains(key, "mandatory"):
+		c = konveyor.Mandatory
+	case strings.Contains(key, "optional"):
+		c = konveyor.Optional
+	case strings.Contains(key, "potential"):
+		c = konveyor.Potential
+	case key == "information" || key == "info":
+		return nil, true
+	default:
+		return nil, false
+	}
+	return &c, false
+}
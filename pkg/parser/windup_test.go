@@ -0,0 +1,149 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+func TestParseWindupCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		wantErr bool
+		check   func(t *testing.T, rulesets []konveyor.RuleSet)
+	}{
+		{
+			name: "violations and insights",
+			csv: `Rule Id,Issue Category,Title,Description,Links,Application,File Name,File Path,Line,Story points
+jee-001,mandatory,Replace EJB,Use CDI,"[Guide|https://example.com/guide]",app,A.java,/src/A.java,10,3
+jee-001,mandatory,Replace EJB,Use CDI,"[Guide|https://example.com/guide]",app,B.java,/src/B.java,4,3
+info-001,information,Java source,Found Java source,,app,A.java,/src/A.java,,0
+`,
+			check: func(t *testing.T, rulesets []konveyor.RuleSet) {
+				if len(rulesets) != 1 || rulesets[0].Name != WindupRuleSet {
+					t.Fatalf("expected one %q ruleset, got %+v", WindupRuleSet, rulesets)
+				}
+				v, ok := rulesets[0].Violations["jee-001"]
+				if !ok {
+					t.Fatalf("expected violation jee-001, got %v", rulesets[0].Violations)
+				}
+				if v.Description != "Replace EJB" || v.Category == nil || *v.Category != konveyor.Mandatory {
+					t.Errorf("unexpected violation %+v", v)
+				}
+				if v.Effort == nil || *v.Effort != 3 {
+					t.Errorf("expected effort 3, got %v", v.Effort)
+				}
+				if len(v.Links) != 1 || v.Links[0].URL != "https://example.com/guide" {
+					t.Errorf("expected one deduplicated link, got %+v", v.Links)
+				}
+				if len(v.Incidents) != 2 || string(v.Incidents[0].URI) != "file:///src/A.java" || *v.Incidents[0].LineNumber != 10 {
+					t.Errorf("unexpected incidents %+v", v.Incidents)
+				}
+				if _, ok := rulesets[0].Insights["info-001"]; !ok {
+					t.Errorf("expected insight info-001, got %v", rulesets[0].Insights)
+				}
+			},
+		},
+		{
+			name:    "missing rule id column",
+			csv:     "Title,Line\nfoo,1\n",
+			wantErr: true,
+		},
+		{
+			name: "empty report",
+			csv:  "",
+			check: func(t *testing.T, rulesets []konveyor.RuleSet) {
+				if len(rulesets) != 0 {
+					t.Errorf("expected no rulesets, got %+v", rulesets)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rulesets, err := ParseWindupCSV(strings.NewReader(tt.csv))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWindupCSV() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, rulesets)
+			}
+		})
+	}
+}
+
+func TestParseWindupXML(t *testing.T) {
+	tests := []struct {
+		name    string
+		xml     string
+		wantErr bool
+		check   func(t *testing.T, rulesets []konveyor.RuleSet)
+	}{
+		{
+			name: "issues grouped by ruleset",
+			xml: `<report>
+  <ruleset name="eap7">
+    <issue ruleId="eap7-001" category="optional" effort="1">
+      <title>Deprecated API</title>
+      <message>Replace the API</message>
+      <link href="https://example.com/api" title="API"/>
+      <file path="/src/A.java" line="7"/>
+      <file path="/src/B.java" line="2"/>
+    </issue>
+  </ruleset>
+  <issue>
+    <ruleId>cloud-001</ruleId>
+    <category>cloud-mandatory</category>
+    <storyPoints>5</storyPoints>
+    <title>Local storage</title>
+    <filePath>/src/C.java</filePath>
+    <line>12</line>
+  </issue>
+  <hint ruleId="no-location"/>
+</report>`,
+			check: func(t *testing.T, rulesets []konveyor.RuleSet) {
+				if len(rulesets) != 2 || rulesets[0].Name != "eap7" || rulesets[1].Name != WindupRuleSet {
+					t.Fatalf("expected rulesets eap7 and %s, got %+v", WindupRuleSet, rulesets)
+				}
+				v := rulesets[0].Violations["eap7-001"]
+				if v.Category == nil || *v.Category != konveyor.Optional || v.Effort == nil || *v.Effort != 1 {
+					t.Errorf("unexpected violation %+v", v)
+				}
+				if len(v.Links) != 1 || v.Links[0].Title != "API" {
+					t.Errorf("unexpected links %+v", v.Links)
+				}
+				if len(v.Incidents) != 2 || v.Incidents[1].Message != "Replace the API" || *v.Incidents[1].LineNumber != 2 {
+					t.Errorf("unexpected incidents %+v", v.Incidents)
+				}
+				cloud := rulesets[1].Violations["cloud-001"]
+				if cloud.Category == nil || *cloud.Category != konveyor.Mandatory || len(cloud.Incidents) != 1 ||
+					string(cloud.Incidents[0].URI) != "file:///src/C.java" {
+					t.Errorf("unexpected violation %+v", cloud)
+				}
+				if _, ok := rulesets[1].Violations["no-location"]; !ok {
+					t.Errorf("expected violation without location, got %v", rulesets[1].Violations)
+				}
+			},
+		},
+		{
+			name:    "invalid xml",
+			xml:     "<report><issue>",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rulesets, err := ParseWindupXML(strings.NewReader(tt.xml))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWindupXML() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, rulesets)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package ruletest
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/config"
+)
+
+// Export converts a koncur test into a rule tests file to be written at outputFile.
+// Every expected violation and insight becomes a test case on its number of
+// incidents, and rules expected absent become isUnmatched test cases. Local
+// application and rules paths are made relative to outputFile. The returned
+// warnings are the expectations that have no rule test equivalent.
+func Export(test *config.TestDefinition, outputFile, providerName string) (*File, []string) {
+	var warnings []string
+	warn := func(format string, args ...any) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+	outputDir := filepath.Dir(outputFile)
+
+	file := &File{}
+	provider := ProviderConfig{Name: providerName}
+	if isLocalPath(test.Analysis.Application) && test.Analysis.ApplicationGitComponents == nil {
+		provider.DataPath = relativePath(outputDir, test.Analysis.Application)
+	} else {
+		warn("application %s is not a local directory, check it out and set dataPath", test.Analysis.Application)
+	}
+	file.Providers = []ProviderConfig{provider}
+
+	for i, rules := range test.Analysis.Rules {
+		if i < len(test.Analysis.RulesGitComponents) && test.Analysis.RulesGitComponents[i] != nil || !isLocalPath(rules) {
+			warn("rules %s are not a local path, check them out and set rulesPath", rules)
+			continue
+		}
+		if file.RulesPath != "" {
+			warn("rules %s were not exported, a rule tests file tests a single rules path", rules)
+			continue
+		}
+		file.RulesPath = relativePath(outputDir, rules)
+	}
+	if len(test.Analysis.Rules) == 0 {
+		warn("the test runs the default rulesets, set rulesPath to the rules under test")
+	}
+
+	params := AnalysisParams{Mode: test.Analysis.AnalysisMode}
+	tests := map[string]*Test{}
+	addCase := func(ruleID string, tc TestCase) {
+		t, ok := tests[ruleID]
+		if !ok {
+			t = &Test{RuleID: ruleID}
+			tests[ruleID] = t
+		}
+		tc.Name = test.Name
+		tc.AnalysisParams = params
+		t.TestCases = append(t.TestCases, tc)
+	}
+
+	output := test.Expect.Output
+	for _, rs := range output.Result {
+		for _, violations := range []map[string]konveyor.Violation{rs.Violations, rs.Insights} {
+			for ruleID, v := range violations {
+				if _, exists := tests[ruleID]; exists {
+					warn("rule %s is expected in several rulesets, only its first expectation is exported", ruleID)
+					continue
+				}
+				verification := &IncidentVerification{}
+				if count, ok := output.IncidentCounts.Get(rs.Name, ruleID); ok {
+					allowed := count.Tolerance.Allowed(count.Count)
+					if allowed == 0 {
+						verification.Exactly = intPtr(count.Count)
+					} else {
+						verification.AtLeast = intPtr(max(0, int(math.Ceil(float64(count.Count)-allowed))))
+						verification.AtMost = intPtr(int(math.Floor(float64(count.Count) + allowed)))
+					}
+				} else {
+					verification.Exactly = intPtr(len(v.Incidents))
+				}
+				addCase(ruleID, TestCase{HasIncidents: verification})
+			}
+		}
+		for _, ruleID := range rs.Unmatched {
+			if _, exists := tests[ruleID]; !exists {
+				addCase(ruleID, TestCase{IsUnmatched: true})
+			}
+		}
+		if len(rs.Tags) > 0 {
+			warn("tags of ruleset %s were not exported, hasTags needs the ID of the rule that sets them: %s", rs.Name, strings.Join(rs.Tags, ", "))
+		}
+	}
+	if absent := test.Expect.Absent; absent != nil {
+		for _, ruleID := range absent.Rules {
+			if _, exists := tests[ruleID]; !exists {
+				addCase(ruleID, TestCase{IsUnmatched: true})
+			}
+		}
+		if len(absent.RuleSets) > 0 || len(absent.Tags) > 0 {
+			warn("absent rulesets and tags were not exported")
+		}
+	}
+
+	ruleIDs := make([]string, 0, len(tests))
+	for ruleID := range tests {
+		ruleIDs = append(ruleIDs, ruleID)
+	}
+	slices.Sort(ruleIDs)
+	for _, ruleID := range ruleIDs {
+		file.Tests = append(file.Tests, *tests[ruleID])
+	}
+	return file, warnings
+}
+
+// isLocalPath reports whether a path is not a URL or a binary: reference
+func isLocalPath(path string) bool {
+	return path != "" && !strings.Contains(path, "://") && !strings.HasPrefix(path, "binary:")
+}
+
+// relativePath returns path relative to dir, or its absolute path when there is none
+func relativePath(dir, path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return abs
+	}
+	rel, err := filepath.Rel(absDir, abs)
+	if err != nil {
+		return abs
+	}
+	return filepath.ToSlash(rel)
+}
+
+func intPtr(v int) *int {
+	return &v
+}
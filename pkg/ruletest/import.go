@@ -0,0 +1,293 @@
+package ruletest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/analyzer-lsp/provider"
+	"github.com/konveyor/test-harness/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// ImportedTest is a rule tests file converted into a koncur test
+type ImportedTest struct {
+	// Dir is the directory name of the test, derived from the tests file name
+	Dir  string
+	Test *config.TestDefinition
+	// Expected is written as the expected output of the test
+	Expected []ExpectedRuleSet
+	// Warnings are expectations that could not be converted
+	Warnings []string
+}
+
+// ExpectedRuleSet is an expected ruleset whose violations and insights are
+// checked by number of incidents (incidentCount)
+type ExpectedRuleSet struct {
+	Name       string                       `yaml:"name"`
+	Tags       []string                     `yaml:"tags,omitempty"`
+	Violations map[string]ExpectedViolation `yaml:"violations,omitempty"`
+	Insights   map[string]ExpectedViolation `yaml:"insights,omitempty"`
+}
+
+// ExpectedViolation is a violation or insight with a count-only expectation
+type ExpectedViolation struct {
+	Description            string             `yaml:"description"`
+	Category               *konveyor.Category `yaml:"category,omitempty"`
+	Labels                 []string           `yaml:"labels,omitempty"`
+	Links                  []konveyor.Link    `yaml:"links,omitempty"`
+	Effort                 *int               `yaml:"effort,omitempty"`
+	IncidentCount          int                `yaml:"incidentCount"`
+	IncidentCountTolerance *config.Tolerance  `yaml:"incidentCountTolerance,omitempty"`
+}
+
+// rule holds the fields of a rule that end up in its violations
+type rule struct {
+	RuleID      string             `yaml:"ruleID"`
+	Description string             `yaml:"description"`
+	Category    *konveyor.Category `yaml:"category"`
+	Effort      *int               `yaml:"effort"`
+	Labels      []string           `yaml:"labels"`
+	Links       []konveyor.Link    `yaml:"links"`
+}
+
+// Import converts a rule tests file into koncur tests, one per set of analysis
+// parameters of its test cases. The application and rules are referenced by absolute
+// path. Incident checks become incidentCount expectations, described from the rules
+// under test, and isUnmatched test cases become absent rules.
+func Import(path string) ([]ImportedTest, error) {
+	file, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(path)
+	name := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), ".test")
+
+	var warnings []string
+	warn := func(format string, args ...any) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+
+	// The rules file next to the tests file is tested by default
+	rulesPath := file.RulesPath
+	if rulesPath == "" {
+		rulesPath = name + filepath.Ext(path)
+	}
+	if !filepath.IsAbs(rulesPath) {
+		rulesPath = filepath.Join(dir, rulesPath)
+	}
+	rulesPath, err = filepath.Abs(rulesPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path of %s: %w", rulesPath, err)
+	}
+	rulesetName, rules, err := loadRules(rulesPath)
+	if err != nil {
+		return nil, err
+	}
+	if rulesetName == "" {
+		rulesetName = strings.TrimSuffix(filepath.Base(rulesPath), filepath.Ext(rulesPath))
+		warn("no ruleset.yaml found next to %s, assuming ruleset name %q", rulesPath, rulesetName)
+	}
+
+	dataPath := ""
+	for _, p := range file.Providers {
+		if p.DataPath == "" {
+			continue
+		}
+		if dataPath == "" {
+			dataPath = p.DataPath
+		} else if p.DataPath != dataPath {
+			warn("provider %s analyzes %s, only %s was imported", p.Name, p.DataPath, dataPath)
+		}
+	}
+	if dataPath == "" {
+		return nil, fmt.Errorf("rule tests %s have no provider with a dataPath", path)
+	}
+	if !filepath.IsAbs(dataPath) {
+		dataPath = filepath.Join(dir, dataPath)
+	}
+	if dataPath, err = filepath.Abs(dataPath); err != nil {
+		return nil, fmt.Errorf("failed to get absolute path of %s: %w", dataPath, err)
+	}
+
+	// kantra runs one analysis per set of analysis parameters
+	var groups []AnalysisParams
+	cases := map[AnalysisParams][]Test{}
+	for _, t := range file.Tests {
+		for _, tc := range t.TestCases {
+			if _, ok := cases[tc.AnalysisParams]; !ok {
+				groups = append(groups, tc.AnalysisParams)
+			}
+			cases[tc.AnalysisParams] = append(cases[tc.AnalysisParams], Test{RuleID: t.RuleID, TestCases: []TestCase{tc}})
+		}
+	}
+
+	var imported []ImportedTest
+	for i, params := range groups {
+		testName := name
+		if len(groups) > 1 {
+			testName = fmt.Sprintf("%s-%d", name, i+1)
+		}
+		test := importGroup(testName, params, cases[params], rulesetName, rules)
+		test.Test.Description = fmt.Sprintf("Rule tests of %s", filepath.Base(rulesPath))
+		test.Test.Analysis.Application = dataPath
+		test.Test.Analysis.Rules = []string{rulesPath}
+		test.Warnings = append(slices.Clone(warnings), test.Warnings...)
+		imported = append(imported, test)
+	}
+	return imported, nil
+}
+
+// importGroup converts the test cases sharing analysis parameters into one test
+func importGroup(name string, params AnalysisParams, tests []Test, rulesetName string, rules map[string]rule) ImportedTest {
+	imported := ImportedTest{Dir: name}
+	warn := func(format string, args ...any) {
+		imported.Warnings = append(imported.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	mode := params.Mode
+	if mode == "" {
+		mode = provider.FullAnalysisMode
+	}
+
+	rs := ExpectedRuleSet{Name: rulesetName}
+	var absent []string
+	for _, t := range tests {
+		tc := t.TestCases[0]
+		if tc.IsUnmatched && !slices.Contains(absent, t.RuleID) {
+			absent = append(absent, t.RuleID)
+		}
+		for _, tag := range tc.HasTags {
+			if !slices.Contains(rs.Tags, tag) {
+				rs.Tags = append(rs.Tags, tag)
+			}
+		}
+		if tc.HasIncidents == nil {
+			continue
+		}
+
+		v := ExpectedViolation{}
+		if r, ok := rules[t.RuleID]; ok {
+			v.Description, v.Category, v.Labels, v.Links, v.Effort = r.Description, r.Category, r.Labels, r.Links, r.Effort
+		} else {
+			warn("rule %s was not found in the rules under test, fill in its description", t.RuleID)
+		}
+		v.IncidentCount, v.IncidentCountTolerance = incidentCount(t.RuleID, tc.HasIncidents, warn)
+		if tc.HasIncidents.MessageMatches != "" || tc.HasIncidents.CodeSnipMatches != "" {
+			warn("%s: messageMatches and codeSnipMatches were not imported", tc.Name)
+		}
+
+		// Rules without effort produce insights
+		if v.Effort == nil || *v.Effort == 0 {
+			if rs.Insights == nil {
+				rs.Insights = map[string]ExpectedViolation{}
+			}
+			rs.Insights[t.RuleID] = v
+		} else {
+			if rs.Violations == nil {
+				rs.Violations = map[string]ExpectedViolation{}
+			}
+			rs.Violations[t.RuleID] = v
+		}
+	}
+
+	imported.Test = &config.TestDefinition{
+		Name: name,
+		Analysis: config.AnalysisConfig{
+			DisableDefaultRules: true,
+			AnalysisMode:        mode,
+			DepLabelSelector:    params.DepLabelSelector,
+		},
+		Expect: config.ExpectConfig{
+			Output: config.ExpectedOutput{File: "expected-output.yaml"},
+		},
+	}
+	if len(absent) > 0 {
+		imported.Test.Expect.Absent = &config.AbsentExpectations{Rules: absent}
+	}
+	if len(rs.Tags) > 0 || len(rs.Violations) > 0 || len(rs.Insights) > 0 {
+		imported.Expected = []ExpectedRuleSet{rs}
+	}
+	return imported
+}
+
+// incidentCount converts an incident check into an incidentCount expectation
+func incidentCount(ruleID string, check *IncidentVerification, warn func(string, ...any)) (int, *config.Tolerance) {
+	switch {
+	case check.Exactly != nil:
+		return *check.Exactly, nil
+	case check.AtLeast != nil && check.AtMost != nil:
+		// count±tolerance is symmetric, so an odd range is narrowed to stay within it
+		atLeast, atMost := *check.AtLeast, *check.AtMost
+		count, tolerance := (atLeast+atMost)/2, (atMost-atLeast)/2
+		if (atLeast+atMost)%2 != 0 {
+			warn("%s: atLeast %d atMost %d was imported as %d±%d incidents, which does not allow %d", ruleID, atLeast, atMost, count, tolerance, atMost)
+		}
+		if tolerance == 0 {
+			return count, nil
+		}
+		return count, &config.Tolerance{Value: float64(tolerance)}
+	case check.AtLeast != nil:
+		warn("%s: atLeast %d was imported as exactly %d incidents", ruleID, *check.AtLeast, *check.AtLeast)
+		return *check.AtLeast, nil
+	case check.AtMost != nil:
+		warn("%s: atMost %d was imported as exactly %d incidents", ruleID, *check.AtMost, *check.AtMost)
+		return *check.AtMost, nil
+	}
+	warn("%s: incident locations were imported as a count of %d incidents", ruleID, len(check.Locations))
+	return len(check.Locations), nil
+}
+
+// loadRules reads the rules of a rules file or directory, and the ruleset name
+// from the ruleset.yaml next to them
+func loadRules(path string) (string, map[string]rule, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read rules: %w", err)
+	}
+	dir := filepath.Dir(path)
+	files := []string{path}
+	if info.IsDir() {
+		dir = path
+		files = nil
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read rules: %w", err)
+		}
+		for _, e := range entries {
+			ext := filepath.Ext(e.Name())
+			if !e.IsDir() && (ext == ".yaml" || ext == ".yml") && e.Name() != "ruleset.yaml" && !strings.Contains(e.Name(), ".test.") {
+				files = append(files, filepath.Join(path, e.Name()))
+			}
+		}
+	}
+
+	rules := map[string]rule{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read rules: %w", err)
+		}
+		var parsed []rule
+		if err := yaml.Unmarshal(data, &parsed); err != nil {
+			return "", nil, fmt.Errorf("failed to parse rules %s: %w", file, err)
+		}
+		for _, r := range parsed {
+			rules[r.RuleID] = r
+		}
+	}
+
+	var ruleset struct {
+		Name string `yaml:"name"`
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "ruleset.yaml"))
+	if err == nil {
+		if err := yaml.Unmarshal(data, &ruleset); err != nil {
+			return "", nil, fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, "ruleset.yaml"), err)
+		}
+	}
+	return ruleset.Name, rules, nil
+}
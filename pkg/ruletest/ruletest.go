@@ -0,0 +1,94 @@
+// Package ruletest converts between koncur tests and the rule tests of
+// analyzer-lsp and kantra ('kantra test'), the <rules>.test.yaml files rule
+// authors keep next to their rules.
+package ruletest
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/konveyor/analyzer-lsp/provider"
+	"gopkg.in/yaml.v3"
+)
+
+// File is a rule tests file
+type File struct {
+	// RulesPath is the rules file or directory under test, relative to the tests file.
+	// When empty, the rules file next to the tests file is used (x.test.yaml tests x.yaml).
+	RulesPath string           `yaml:"rulesPath,omitempty"`
+	Providers []ProviderConfig `yaml:"providers,omitempty"`
+	Tests     []Test           `yaml:"tests,omitempty"`
+}
+
+// ProviderConfig is a provider and the test data it analyzes
+type ProviderConfig struct {
+	Name string `yaml:"name"`
+	// DataPath is the application to analyze, relative to the tests file
+	DataPath string `yaml:"dataPath,omitempty"`
+}
+
+// Test holds the test cases of one rule
+type Test struct {
+	RuleID    string     `yaml:"ruleID"`
+	TestCases []TestCase `yaml:"testCases"`
+}
+
+// TestCase is an expectation on the result of a rule
+type TestCase struct {
+	Name           string         `yaml:"name"`
+	AnalysisParams AnalysisParams `yaml:"analysisParams,omitempty"`
+	// IsUnmatched expects the rule not to match
+	IsUnmatched  bool                  `yaml:"isUnmatched,omitempty"`
+	HasIncidents *IncidentVerification `yaml:"hasIncidents,omitempty"`
+	HasTags      []string              `yaml:"hasTags,omitempty"`
+}
+
+// AnalysisParams are the analysis settings of a test case
+type AnalysisParams struct {
+	Mode             provider.AnalysisMode `yaml:"mode,omitempty"`
+	DepLabelSelector string                `yaml:"depLabelSelector,omitempty"`
+}
+
+// IncidentVerification checks the incidents of a rule, either by count
+// (exactly, atLeast, atMost) or by location
+type IncidentVerification struct {
+	Exactly         *int                   `yaml:"exactly,omitempty"`
+	AtLeast         *int                   `yaml:"atLeast,omitempty"`
+	AtMost          *int                   `yaml:"atMost,omitempty"`
+	MessageMatches  string                 `yaml:"messageMatches,omitempty"`
+	CodeSnipMatches string                 `yaml:"codeSnipMatches,omitempty"`
+	Locations       []LocationVerification `yaml:"locations,omitempty"`
+}
+
+// LocationVerification expects an incident at a line of a file
+type LocationVerification struct {
+	FileURI         string `yaml:"fileURI"`
+	LineNumber      int    `yaml:"lineNumber"`
+	MessageMatches  string `yaml:"messageMatches,omitempty"`
+	CodeSnipMatches string `yaml:"codeSnipMatches,omitempty"`
+}
+
+// Load reads a rule tests file
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule tests %s: %w", path, err)
+	}
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse rule tests %s: %w", path, err)
+	}
+	return &file, nil
+}
+
+// Save writes a rule tests file
+func Save(path string, file *File) error {
+	data, err := yaml.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rule tests: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write rule tests %s: %w", path, err)
+	}
+	return nil
+}
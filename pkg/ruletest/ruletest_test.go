@@ -0,0 +1,218 @@
+package ruletest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/analyzer-lsp/provider"
+	"github.com/konveyor/test-harness/pkg/config"
+)
+
+func TestExport(t *testing.T) {
+	dir := t.TempDir()
+	test := &config.TestDefinition{
+		Name: "jakarta",
+		Analysis: config.AnalysisConfig{
+			Application:  filepath.Join(dir, "data", "app"),
+			Rules:        []string{filepath.Join(dir, "rules", "jakarta.yaml"), "https://github.com/example/rules.git"},
+			AnalysisMode: provider.SourceOnlyAnalysisMode,
+		},
+		Expect: config.ExpectConfig{
+			Output: config.ExpectedOutput{
+				Result: []konveyor.RuleSet{{
+					Name:       "jakarta",
+					Tags:       []string{"Jakarta EE"},
+					Violations: map[string]konveyor.Violation{"jakarta-001": {Incidents: make([]konveyor.Incident, 3)}},
+					Insights:   map[string]konveyor.Violation{"jakarta-002": {}},
+					Unmatched:  []string{"jakarta-004"},
+				}},
+				IncidentCounts: config.IncidentCounts{"jakarta": {"jakarta-002": {Count: 100, Tolerance: config.Tolerance{Value: 10, Percent: true}}}},
+			},
+			Absent: &config.AbsentExpectations{Rules: []string{"jakarta-003"}},
+		},
+	}
+	test.Analysis.ParseGitURLs()
+
+	file, warnings := Export(test, filepath.Join(dir, "rules", "tests", "jakarta.test.yaml"), "java")
+	if file.RulesPath != "../jakarta.yaml" {
+		t.Errorf("rulesPath = %q, want ../jakarta.yaml", file.RulesPath)
+	}
+	if want := []ProviderConfig{{Name: "java", DataPath: "../../data/app"}}; !reflect.DeepEqual(file.Providers, want) {
+		t.Errorf("providers = %+v, want %+v", file.Providers, want)
+	}
+
+	var ruleIDs []string
+	for _, test := range file.Tests {
+		ruleIDs = append(ruleIDs, test.RuleID)
+		tc := test.TestCases[0]
+		if tc.Name != "jakarta" || tc.AnalysisParams.Mode != provider.SourceOnlyAnalysisMode {
+			t.Errorf("%s: unexpected test case %+v", test.RuleID, tc)
+		}
+		switch test.RuleID {
+		case "jakarta-001":
+			if tc.HasIncidents == nil || tc.HasIncidents.Exactly == nil || *tc.HasIncidents.Exactly != 3 {
+				t.Errorf("jakarta-001: expected exactly 3 incidents, got %+v", tc.HasIncidents)
+			}
+		case "jakarta-002":
+			if tc.HasIncidents == nil || *tc.HasIncidents.AtLeast != 90 || *tc.HasIncidents.AtMost != 110 {
+				t.Errorf("jakarta-002: expected 90 to 110 incidents, got %+v", tc.HasIncidents)
+			}
+		case "jakarta-003", "jakarta-004":
+			if !tc.IsUnmatched {
+				t.Errorf("%s: expected isUnmatched", test.RuleID)
+			}
+		}
+	}
+	if want := []string{"jakarta-001", "jakarta-002", "jakarta-003", "jakarta-004"}; !reflect.DeepEqual(ruleIDs, want) {
+		t.Errorf("rule IDs = %v, want %v", ruleIDs, want)
+	}
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "not a local path") || !strings.Contains(warnings[1], "Jakarta EE") {
+		t.Errorf("unexpected warnings %q", warnings)
+	}
+}
+
+func TestImport(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"ruleset.yaml": "name: jakarta/java\n",
+		"jakarta.yaml": `- ruleID: jakarta-001
+  description: Replace javax with jakarta
+  category: mandatory
+  effort: 1
+  labels: [konveyor.io/target=jakarta-ee]
+- ruleID: jakarta-002
+  description: Jakarta EE usage
+- ruleID: jakarta-003
+  description: Removed API
+  effort: 3
+`,
+		"jakarta.test.yaml": `providers:
+- name: java
+  dataPath: ./data/app
+tests:
+- ruleID: jakarta-001
+  testCases:
+  - name: tc-1
+    analysisParams:
+      mode: source-only
+    hasIncidents:
+      exactly: 2
+  - name: tc-2
+    analysisParams:
+      depLabelSelector: konveyor.io/dep-source=internal
+    hasIncidents:
+      atLeast: 4
+      atMost: 8
+- ruleID: jakarta-002
+  testCases:
+  - name: tc-1
+    analysisParams:
+      mode: source-only
+    hasIncidents:
+      locations:
+      - fileURI: file:///data/App.java
+        lineNumber: 3
+    hasTags: [Jakarta EE]
+- ruleID: jakarta-003
+  testCases:
+  - name: tc-1
+    analysisParams:
+      mode: source-only
+    isUnmatched: true
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	imported, err := Import(filepath.Join(dir, "jakarta.test.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(imported) != 2 {
+		t.Fatalf("expected a test per set of analysis parameters, got %d", len(imported))
+	}
+
+	source, full := imported[0], imported[1]
+	if source.Dir != "jakarta-1" || full.Dir != "jakarta-2" {
+		t.Errorf("unexpected test directories %q, %q", source.Dir, full.Dir)
+	}
+	if source.Test.Analysis.AnalysisMode != provider.SourceOnlyAnalysisMode || full.Test.Analysis.AnalysisMode != provider.FullAnalysisMode {
+		t.Errorf("unexpected analysis modes %q, %q", source.Test.Analysis.AnalysisMode, full.Test.Analysis.AnalysisMode)
+	}
+	if full.Test.Analysis.DepLabelSelector != "konveyor.io/dep-source=internal" {
+		t.Errorf("depLabelSelector = %q", full.Test.Analysis.DepLabelSelector)
+	}
+	if source.Test.Analysis.Application != filepath.Join(dir, "data", "app") {
+		t.Errorf("application = %q", source.Test.Analysis.Application)
+	}
+	if want := []string{filepath.Join(dir, "jakarta.yaml")}; !reflect.DeepEqual(source.Test.Analysis.Rules, want) || !source.Test.Analysis.DisableDefaultRules {
+		t.Errorf("rules = %v (default rules disabled: %v)", source.Test.Analysis.Rules, source.Test.Analysis.DisableDefaultRules)
+	}
+	if source.Test.Expect.Absent == nil || !reflect.DeepEqual(source.Test.Expect.Absent.Rules, []string{"jakarta-003"}) {
+		t.Errorf("expected jakarta-003 to be absent, got %+v", source.Test.Expect.Absent)
+	}
+
+	rs := source.Expected[0]
+	if rs.Name != "jakarta/java" || !reflect.DeepEqual(rs.Tags, []string{"Jakarta EE"}) {
+		t.Errorf("unexpected ruleset %q with tags %v", rs.Name, rs.Tags)
+	}
+	if v := rs.Violations["jakarta-001"]; v.Description != "Replace javax with jakarta" || v.IncidentCount != 2 || v.IncidentCountTolerance != nil {
+		t.Errorf("unexpected jakarta-001 violation %+v", v)
+	}
+	if v, ok := rs.Insights["jakarta-002"]; !ok || v.IncidentCount != 1 {
+		t.Errorf("expected jakarta-002 insight with 1 incident, got %+v", rs.Insights)
+	}
+	if !strings.Contains(strings.Join(source.Warnings, "\n"), "locations were imported as a count") {
+		t.Errorf("expected a warning about locations, got %q", source.Warnings)
+	}
+	if v := full.Expected[0].Violations["jakarta-001"]; v.IncidentCount != 6 || v.IncidentCountTolerance == nil || v.IncidentCountTolerance.Value != 2 {
+		t.Errorf("expected 6±2 incidents for atLeast 4 atMost 8, got %+v", v)
+	}
+}
+
+func TestIncidentCount(t *testing.T) {
+	n := func(v int) *int { return &v }
+	tests := []struct {
+		name     string
+		check    IncidentVerification
+		atLeast  int
+		atMost   int
+		wantWarn bool
+	}{
+		{name: "exactly", check: IncidentVerification{Exactly: n(3)}, atLeast: 3, atMost: 3},
+		{name: "even range", check: IncidentVerification{AtLeast: n(4), AtMost: n(8)}, atLeast: 4, atMost: 8},
+		{name: "odd range narrowed", check: IncidentVerification{AtLeast: n(2), AtMost: n(3)}, atLeast: 2, atMost: 3, wantWarn: true},
+		{name: "wider odd range narrowed", check: IncidentVerification{AtLeast: n(2), AtMost: n(5)}, atLeast: 2, atMost: 5, wantWarn: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var warnings []string
+			warn := func(format string, args ...any) { warnings = append(warnings, fmt.Sprintf(format, args...)) }
+			count, tolerance := incidentCount("rule-001", &tt.check, warn)
+			expectation := config.IncidentCount{Count: count}
+			if tolerance != nil {
+				expectation.Tolerance = *tolerance
+			}
+			// No count outside the checked range may be allowed
+			for actual := 0; actual <= tt.atMost+3; actual++ {
+				if expectation.Allows(actual) && (actual < tt.atLeast || actual > tt.atMost) {
+					t.Errorf("%d±%v allows %d, outside [%d, %d]", count, tolerance, actual, tt.atLeast, tt.atMost)
+				}
+			}
+			if !expectation.Allows(tt.atLeast) && !tt.wantWarn {
+				t.Errorf("%d±%v does not allow %d", count, tolerance, tt.atLeast)
+			}
+			if (len(warnings) > 0) != tt.wantWarn {
+				t.Errorf("warnings = %q, want warning %v", warnings, tt.wantWarn)
+			}
+		})
+	}
+}
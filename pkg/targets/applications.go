@@ -0,0 +1,51 @@
+package targets
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/parser"
+	"gopkg.in/yaml.v2"
+)
+
+// ApplicationOutput is the output of one application of a test analyzing several
+type ApplicationOutput struct {
+	// Name of the application in the test definition
+	Name string
+	// OutputFile path to the application's output.yaml
+	OutputFile string
+	// DependenciesFile path to the application's dependencies.yaml, when the target
+	// produced one
+	DependenciesFile string
+}
+
+// writeMergedOutput writes the outputs of several applications merged into one
+// output file, for the reports and tools that read a single output
+func writeMergedOutput(outputs []ApplicationOutput, outputFile string) error {
+	files := make([]string, len(outputs))
+	for i, output := range outputs {
+		files[i] = output.OutputFile
+	}
+	merged, err := parser.ParseOutputs(files...)
+	if err != nil {
+		return err
+	}
+	return writeOutput(merged, outputFile)
+}
+
+// writeOutput writes rulesets as an analysis output file
+func writeOutput(rulesets []konveyor.RuleSet, outputFile string) error {
+	data, err := yaml.Marshal(rulesets)
+	if err != nil {
+		return fmt.Errorf("failed to marshal output: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(outputFile), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(outputFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
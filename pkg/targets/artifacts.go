@@ -0,0 +1,98 @@
+package targets
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/konveyor/test-harness/pkg/util"
+)
+
+// ArtifactsDir returns the directory of a work directory keeping what a target ran:
+// its command lines, console output and generated settings, so failures on CI can
+// be diagnosed without reproducing them
+func ArtifactsDir(workDir string) string {
+	return filepath.Join(workDir, "artifacts")
+}
+
+// recordCommand appends a command line, with the environment it adds, to the
+// artifacts of a work directory and returns the console log its output is
+// appended to. Secrets are redacted from the recorded command line.
+func recordCommand(workDir, binary string, args, env []string) (*os.File, error) {
+	dir := ArtifactsDir(workDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create artifacts directory: %w", err)
+	}
+
+	words := slices.Concat(env, []string{binary}, args)
+	for i, word := range words {
+		if word == "" || strings.ContainsAny(word, " \t\n'\"$\\") {
+			words[i] = strconv.Quote(word)
+		}
+	}
+	line := util.RedactText(strings.Join(words, " "))
+	if err := appendFile(filepath.Join(dir, "command.txt"), line+"\n"); err != nil {
+		return nil, err
+	}
+
+	console, err := os.OpenFile(filepath.Join(dir, "console.txt"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open console log: %w", err)
+	}
+	if _, err := fmt.Fprintf(console, "$ %s\n", line); err != nil {
+		console.Close()
+		return nil, fmt.Errorf("failed to write console log: %w", err)
+	}
+	return console, nil
+}
+
+func appendFile(file, content string) error {
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", file, err)
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	return f.Close()
+}
+
+// collectSettings copies the provider settings an analysis generated into its
+// output directory to the artifacts of the work directory, under the output
+// directory's path relative to the work directory's output
+func collectSettings(workDir, outputDir string) error {
+	matches, err := filepath.Glob(filepath.Join(outputDir, "*settings*"))
+	if err != nil {
+		return fmt.Errorf("failed to list settings: %w", err)
+	}
+	dest := ArtifactsDir(workDir)
+	absWorkDir, err := filepath.Abs(workDir)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute work directory: %w", err)
+	}
+	if rel, err := filepath.Rel(filepath.Join(absWorkDir, "output"), outputDir); err == nil && !strings.HasPrefix(rel, "..") {
+		dest = filepath.Join(dest, rel)
+	}
+	for _, file := range matches {
+		switch filepath.Ext(file) {
+		case ".json", ".yaml", ".yml":
+		default:
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read settings %s: %w", file, err)
+		}
+		if err := os.MkdirAll(dest, 0755); err != nil {
+			return fmt.Errorf("failed to create artifacts directory: %w", err)
+		}
+		if err := os.WriteFile(filepath.Join(dest, filepath.Base(file)), data, 0644); err != nil {
+			return fmt.Errorf("failed to copy settings %s: %w", file, err)
+		}
+	}
+	return nil
+}
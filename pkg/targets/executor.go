@@ -3,9 +3,11 @@ package targets
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -14,8 +16,28 @@ import (
 
 // ExecuteCommand runs a command with timeout and captures output
 func ExecuteCommand(ctx context.Context, binary string, args []string, workDir string, timeout time.Duration) (*ExecutionResult, error) {
+	return ExecuteCommandEnv(ctx, binary, args, nil, workDir, timeout)
+}
+
+// ExecuteCommandEnv runs a command like ExecuteCommand, with env ("NAME=value")
+// added to the environment
+func ExecuteCommandEnv(ctx context.Context, binary string, args, env []string, workDir string, timeout time.Duration) (*ExecutionResult, error) {
+	result, err := RunCommand(ctx, binary, args, env, workDir, timeout, nil)
+	if err != nil {
+		return nil, err
+	}
+	if result.ExitCode != 0 {
+		return nil, fmt.Errorf("command failed with exit code: %d", result.ExitCode)
+	}
+	return result, nil
+}
+
+// RunCommand runs a command like ExecuteCommandEnv, but returns the result of
+// commands exiting with a nonzero code, for targets whose exit codes are expected.
+// If output is not nil, it receives a copy of the command's stdout and stderr.
+func RunCommand(ctx context.Context, binary string, args, env []string, workDir string, timeout time.Duration, output io.Writer) (*ExecutionResult, error) {
 	log := util.GetLogger()
-	log.Info("Executing command", "binary", binary, "args", args, "workDir", workDir)
+	log.Info("Executing command", "binary", binary, "args", args, "env", env, "workDir", workDir)
 
 	// Create context with timeout
 	execCtx, cancel := context.WithTimeout(ctx, timeout)
@@ -24,9 +46,17 @@ func ExecuteCommand(ctx context.Context, binary string, args []string, workDir s
 	// Create command
 	cmd := exec.CommandContext(execCtx, binary, args...)
 	cmd.Dir = workDir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
+	if output != nil {
+		captured := &lockedWriter{w: output}
+		cmd.Stdout = io.MultiWriter(os.Stdout, captured)
+		cmd.Stderr = io.MultiWriter(os.Stderr, captured)
+	}
 
 	// Execute
 	start := time.Now()
@@ -53,13 +83,21 @@ func ExecuteCommand(ctx context.Context, binary string, args []string, workDir s
 
 	log.Info("Command completed", "exitCode", exitCode, "duration", duration)
 
-	if exitCode != 0 {
-		return nil, fmt.Errorf("command failed with exit code: %d", exitCode)
-	}
-
 	return result, nil
 }
 
+// lockedWriter serializes the writes of a command's stdout and stderr
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
 // PrepareWorkDir creates a unique work directory for test execution
 func PrepareWorkDir(baseDir, testName string) (string, error) {
 	// Sanitize test name to avoid issues with special characters and spaces
@@ -0,0 +1,73 @@
+package targets
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// packageTerm matches the package terms of incident selectors, e.g. package=com.example
+var packageTerm = regexp.MustCompile(`^package\s*=\s*(\S+)$`)
+
+// ParseIncidentSelector maps an incident selector onto the package scope of the Hub's
+// analyzer addon, which builds kantra's incident selector from included and excluded
+// packages. Selectors the addon cannot express fail, rather than being dropped and
+// yielding more incidents than kantra does.
+// The selector format supports a conjunction ("&&") of:
+//   - package terms, or several combined with "||": "(package=a || package=b)"
+//   - negated package terms or groups: "!package=c", "!(package=c || package=d)"
+//
+// Examples:
+//   - "package=com.example" -> Included: ["com.example"]
+//   - "(package=com.a || package=com.b) && !package=com.a.test" -> Included: ["com.a", "com.b"], Excluded: ["com.a.test"]
+func ParseIncidentSelector(selector string) (included, excluded []string, err error) {
+	unsupported := func() error {
+		return fmt.Errorf("incident selector %q cannot be run on the Hub, its analyzer addon only selects incidents by package, e.g. (package=a || package=b) && !package=c", selector)
+	}
+	for _, clause := range splitTopLevel(selector, "&&") {
+		clause = strings.TrimSpace(clause)
+		negated := strings.HasPrefix(clause, "!")
+		clause = strings.TrimSpace(strings.TrimPrefix(clause, "!"))
+		if strings.HasPrefix(clause, "(") && strings.HasSuffix(clause, ")") {
+			clause = clause[1 : len(clause)-1]
+		}
+
+		var packages []string
+		for _, term := range strings.Split(clause, "||") {
+			match := packageTerm.FindStringSubmatch(strings.TrimSpace(term))
+			if match == nil {
+				return nil, nil, unsupported()
+			}
+			packages = append(packages, match[1])
+		}
+		switch {
+		case negated:
+			excluded = append(excluded, packages...)
+		case included != nil:
+			// The addon cannot intersect several groups of packages
+			return nil, nil, unsupported()
+		default:
+			included = packages
+		}
+	}
+	return included, excluded, nil
+}
+
+// splitTopLevel splits s on sep outside of parentheses
+func splitTopLevel(s, sep string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '(':
+			depth++
+		case s[i] == ')':
+			depth--
+		case depth == 0 && strings.HasPrefix(s[i:], sep):
+			parts = append(parts, s[start:i])
+			start = i + len(sep)
+			i += len(sep) - 1
+		}
+	}
+	return append(parts, s[start:])
+}
@@ -0,0 +1,40 @@
+package targets
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseIncidentSelector(t *testing.T) {
+	tests := []struct {
+		name         string
+		selector     string
+		wantIncluded []string
+		wantExcluded []string
+		wantErr      bool
+	}{
+		{name: "single package", selector: "package=com.example", wantIncluded: []string{"com.example"}},
+		{name: "several packages", selector: "(package=com.a || package=com.b)", wantIncluded: []string{"com.a", "com.b"}},
+		{name: "excluded package", selector: "!package=com.a.test", wantExcluded: []string{"com.a.test"}},
+		{
+			name:         "included and excluded",
+			selector:     "(package=com.a || package=com.b) && !(package=com.a.test || package=com.b.test)",
+			wantIncluded: []string{"com.a", "com.b"},
+			wantExcluded: []string{"com.a.test", "com.b.test"},
+		},
+		{name: "other variable", selector: "name=javax.ejb.Stateless", wantErr: true},
+		{name: "packages intersected", selector: "package=com.a && package=com.b", wantErr: true},
+		{name: "package mixed with another variable", selector: "(package=com.a || name=Foo)", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			included, excluded, err := ParseIncidentSelector(tt.selector)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseIncidentSelector() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(included, tt.wantIncluded) || !reflect.DeepEqual(excluded, tt.wantExcluded) {
+				t.Errorf("ParseIncidentSelector() = %v, %v, want %v, %v", included, excluded, tt.wantIncluded, tt.wantExcluded)
+			}
+		})
+	}
+}
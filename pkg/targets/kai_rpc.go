@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
 	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/parser"
 )
 
 // KaiRPCTarget implements Target for Kai analyzer RPC
@@ -39,3 +41,11 @@ func (k *KaiRPCTarget) Execute(ctx context.Context, test *config.TestDefinition)
 	// 4. Parse and return RuleSets
 	return nil, fmt.Errorf("kai-rpc target not yet implemented")
 }
+
+// SuggestFix requests a fix suggestion for an incident via Kai RPC
+func (k *KaiRPCTarget) SuggestFix(ctx context.Context, test *config.TestDefinition, ruleset, rule string, incident konveyor.Incident) (*parser.FixSuggestion, error) {
+	// TODO: Implement Kai fix suggestions
+	// 1. Send a solution request for the incident to the Kai RPC server
+	// 2. Return the changed file, its diff and whether the fixed code compiled
+	return nil, fmt.Errorf("kai-rpc fix suggestions not yet implemented")
+}
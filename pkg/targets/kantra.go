@@ -3,14 +3,17 @@ package targets
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/konveyor/analyzer-lsp/provider"
 	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/parser"
 	"github.com/konveyor/test-harness/pkg/util"
 )
 
@@ -18,12 +21,14 @@ import (
 type KantraTarget struct {
 	binaryPath    string
 	mavenSettings string
+	images        config.KantraImages
 }
 
 // NewKantraTarget creates a new Kantra target
 func NewKantraTarget(cfg *config.KantraConfig) (*KantraTarget, error) {
 	var binaryPath string
 	var mavenSettings string
+	var images config.KantraImages
 
 	// Use configured path if provided
 	if cfg != nil && cfg.BinaryPath != "" {
@@ -37,14 +42,16 @@ func NewKantraTarget(cfg *config.KantraConfig) (*KantraTarget, error) {
 		}
 	}
 
-	// Get maven settings from config
+	// Get maven settings and images from config
 	if cfg != nil {
 		mavenSettings = cfg.MavenSettings
+		images = cfg.KantraImages
 	}
 
 	return &KantraTarget{
 		binaryPath:    binaryPath,
 		mavenSettings: mavenSettings,
+		images:        images,
 	}, nil
 }
 
@@ -53,6 +60,42 @@ func (k *KantraTarget) Name() string {
 	return "kantra"
 }
 
+// Providers lists the providers of container analyses, as reported by kantra
+// analyze --list-providers
+func (k *KantraTarget) Providers(ctx context.Context) ([]string, error) {
+	out, err := exec.CommandContext(ctx, k.binaryPath, "analyze", "--list-providers").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list kantra providers: %w", err)
+	}
+	return parseKantraProviders(string(out)), nil
+}
+
+// parseKantraProviders reads the providers of the container analysis section of
+// --list-providers output, koncur runs kantra in containers, or every provider
+// listed when there is no such section
+func parseKantraProviders(out string) []string {
+	var all, container []string
+	inContainer, hasContainer := false, false
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasSuffix(line, ":"):
+			section := strings.ToLower(line)
+			inContainer = strings.Contains(section, "container") && !strings.Contains(section, "containerless")
+			hasContainer = hasContainer || inContainer
+		case line != "" && !strings.ContainsAny(line, " \t"):
+			all = append(all, line)
+			if inContainer {
+				container = append(container, line)
+			}
+		}
+	}
+	if hasContainer {
+		return container
+	}
+	return all
+}
+
 // Execute runs kantra analyze
 func (k *KantraTarget) Execute(ctx context.Context, test *config.TestDefinition) (*ExecutionResult, error) {
 	log := util.GetLogger()
@@ -60,7 +103,7 @@ func (k *KantraTarget) Execute(ctx context.Context, test *config.TestDefinition)
 	log.V(2).Info("Test config", "config", test.Analysis)
 
 	// Validate maven settings requirement
-	if test.RequireMavenSettings && k.mavenSettings == "" {
+	if test.RequireMavenSettings && k.mavenSettings == "" && test.MavenSettings == "" {
 		return nil, fmt.Errorf("test requires maven settings but none configured in target config")
 	}
 
@@ -76,10 +119,19 @@ func (k *KantraTarget) Execute(ctx context.Context, test *config.TestDefinition)
 		return nil, err
 	}
 
-	// Handle application input (clone git repo to test-dir/source if needed)
-	inputPath, err := k.prepareInput(ctx, &test.Analysis, testDir)
-	if err != nil {
-		return nil, fmt.Errorf("failed to prepare input: %w", err)
+	// The test's own maven settings replace the target's
+	mavenSettings := k.mavenSettings
+	if test.MavenSettings != "" {
+		if mavenSettings, err = test.RenderMavenSettings(workDir); err != nil {
+			return nil, err
+		}
+	}
+
+	// Provider overrides are passed as a settings file, kept with the artifacts
+	if len(test.Analysis.ProviderOverrides) > 0 {
+		if test.Analysis.ProviderOverridesFile, err = writeProviderOverrides(test.Analysis.ProviderOverrides, workDir); err != nil {
+			return nil, err
+		}
 	}
 
 	// Handle rules that may be Git URLs
@@ -88,6 +140,16 @@ func (k *KantraTarget) Execute(ctx context.Context, test *config.TestDefinition)
 		return nil, fmt.Errorf("failed to prepare rules: %w", err)
 	}
 
+	if len(test.Analysis.Applications) > 0 {
+		return k.executeApplications(ctx, test, workDir, mavenSettings, preparedRules)
+	}
+
+	// Handle application input (clone git repo to test-dir/source if needed)
+	inputPath, err := k.prepareInput(ctx, &test.Analysis, testDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare input: %w", err)
+	}
+
 	// Create output directory with absolute path
 	outputDir := filepath.Join(workDir, "output")
 	absOutputDir, err := filepath.Abs(outputDir)
@@ -99,22 +161,317 @@ func (k *KantraTarget) Execute(ctx context.Context, test *config.TestDefinition)
 	}
 
 	// Build kantra command arguments with prepared rules
-	args := k.buildArgs(test.Analysis, inputPath, absOutputDir, k.mavenSettings, preparedRules)
+	args := k.buildArgs(test.Analysis, inputPath, absOutputDir, mavenSettings, preparedRules)
 
 	// Execute kantra
-	result, err := ExecuteCommand(ctx, k.binaryPath, args, workDir, test.GetTimeout())
+	result, err := k.analyze(ctx, test, args, workDir, absOutputDir)
 	if err != nil {
 		return nil, err
 	}
 
+	if test.Analysis.StaticReport && result.ExitCode == 0 {
+		if err := checkStaticReport(absOutputDir); err != nil {
+			return nil, err
+		}
+	}
+
 	// Set the output file path (absOutputDir is already absolute)
 	result.OutputFile = filepath.Join(absOutputDir, "output.yaml")
+	// Dependencies are only listed by full analyses
+	depsFile := filepath.Join(absOutputDir, "dependencies.yaml")
+	if _, err := os.Stat(depsFile); err == nil {
+		result.DependenciesFile = depsFile
+	}
 
 	LogResult(log, result)
 
 	return result, nil
 }
 
+// analyze runs kantra with the images and settings of the target, or the test's.
+// Nonzero exit codes are returned for the test's exit code expectations. The
+// command line, console output and provider settings kantra generated into
+// outputDir are kept as artifacts of the work directory, the providers of the
+// settings are the providers that ran.
+func (k *KantraTarget) analyze(ctx context.Context, test *config.TestDefinition, args []string, workDir, outputDir string) (*ExecutionResult, error) {
+	result, err := k.run(ctx, test, args, workDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := collectSettings(workDir, outputDir); err != nil {
+		return nil, err
+	}
+	if result.Providers, err = ranProviders(outputDir); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// run runs a kantra command, recording its command line and console output
+func (k *KantraTarget) run(ctx context.Context, test *config.TestDefinition, args []string, workDir string) (*ExecutionResult, error) {
+	env := k.images.Merge(test.Kantra).Env()
+	console, err := recordCommand(workDir, k.binaryPath, args, env)
+	if err != nil {
+		return nil, err
+	}
+	defer console.Close()
+	return RunCommand(ctx, k.binaryPath, args, env, workDir, test.GetTimeout(), console)
+}
+
+// ListLabels lists the source and target labels of the test's rules, and of the
+// default rulesets unless they are disabled, with kantra analyze --list-sources
+// and --list-targets
+func (k *KantraTarget) ListLabels(ctx context.Context, test *config.TestDefinition) (*RuleSetLabels, error) {
+	workDir, err := PrepareWorkDir(test.GetWorkDir(), test.Name)
+	if err != nil {
+		return nil, err
+	}
+	preparedRules, err := k.prepareRules(ctx, &test.Analysis, workDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare rules: %w", err)
+	}
+
+	labels := &RuleSetLabels{WorkDir: workDir}
+	for _, list := range []struct {
+		flag   string
+		labels *[]string
+	}{{"--list-sources", &labels.Sources}, {"--list-targets", &labels.Targets}} {
+		args := []string{"analyze", list.flag}
+		for _, rule := range preparedRules {
+			args = append(args, "--rules", rule)
+		}
+		if !test.Analysis.DefaultRulesetsEnabled() {
+			args = append(args, "--enable-default-rulesets=false")
+		}
+		args = append(args, "--run-local=false")
+
+		out, err := k.list(ctx, test, args, workDir)
+		if err != nil {
+			return nil, err
+		}
+		*list.labels = parseKantraLabels(out)
+	}
+	return labels, nil
+}
+
+// list runs a kantra listing command and returns its stdout, recording the command
+// and its console output as artifacts like analyses
+func (k *KantraTarget) list(ctx context.Context, test *config.TestDefinition, args []string, workDir string) (string, error) {
+	env := k.images.Merge(test.Kantra).Env()
+	console, err := recordCommand(workDir, k.binaryPath, args, env)
+	if err != nil {
+		return "", err
+	}
+	defer console.Close()
+
+	execCtx, cancel := context.WithTimeout(ctx, test.GetTimeout())
+	defer cancel()
+	var stdout strings.Builder
+	captured := &lockedWriter{w: console}
+	cmd := exec.CommandContext(execCtx, k.binaryPath, args...)
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = io.MultiWriter(&stdout, captured)
+	cmd.Stderr = captured
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to run kantra %s: %w", strings.Join(args, " "), err)
+	}
+	return stdout.String(), nil
+}
+
+// parseKantraLabels reads the labels of --list-sources and --list-targets output,
+// one per line below a heading, e.g. "available target technologies:"
+func parseKantraLabels(out string) []string {
+	var labels []string
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasSuffix(line, ":") || strings.ContainsAny(line, " \t") {
+			continue
+		}
+		if !slices.Contains(labels, line) {
+			labels = append(labels, line)
+		}
+	}
+	slices.Sort(labels)
+	return labels
+}
+
+// executeApplications analyzes each application of a test analyzing several into its
+// own output directory, one after the other, and merges their outputs
+func (k *KantraTarget) executeApplications(ctx context.Context, test *config.TestDefinition, workDir, mavenSettings string, preparedRules []string) (*ExecutionResult, error) {
+	log := util.GetLogger()
+	// The analyses run one after the other, so they share the test's timeout
+	ctx, cancel := context.WithTimeout(ctx, test.GetTimeout())
+	defer cancel()
+
+	absOutputDir, err := filepath.Abs(filepath.Join(workDir, "output"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute output path: %w", err)
+	}
+
+	if test.Analysis.Bulk {
+		return k.executeBulk(ctx, test, workDir, absOutputDir, mavenSettings, preparedRules)
+	}
+
+	result := &ExecutionResult{WorkDir: workDir}
+	var ran [][]string
+	for _, app := range test.Analysis.Applications {
+		appTest := test.ForApplication(app)
+		inputPath, err := k.prepareInput(ctx, &appTest.Analysis, test.GetTestDir())
+		if err != nil {
+			return nil, fmt.Errorf("failed to prepare input of application %s: %w", app.Name, err)
+		}
+		appOutputDir := filepath.Join(absOutputDir, app.Name)
+		if err := os.MkdirAll(appOutputDir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create output directory: %w", err)
+		}
+
+		log.Info("Analyzing application", "test", test.Name, "application", app.Name)
+		args := k.buildArgs(appTest.Analysis, inputPath, appOutputDir, mavenSettings, preparedRules)
+		appResult, err := k.analyze(ctx, test, args, workDir, appOutputDir)
+		if err != nil {
+			return nil, fmt.Errorf("application %s: %w", app.Name, err)
+		}
+		result.Duration += appResult.Duration
+		result.ExitCode = firstNonzero(result.ExitCode, appResult.ExitCode)
+		ran = append(ran, appResult.Providers)
+		if test.Analysis.StaticReport && appResult.ExitCode == 0 {
+			if err := checkStaticReport(appOutputDir); err != nil {
+				return nil, fmt.Errorf("application %s: %w", app.Name, err)
+			}
+		}
+		output := ApplicationOutput{
+			Name:       app.Name,
+			OutputFile: filepath.Join(appOutputDir, "output.yaml"),
+		}
+		// Dependencies are only listed by full analyses
+		depsFile := filepath.Join(appOutputDir, "dependencies.yaml")
+		if _, err := os.Stat(depsFile); err == nil {
+			output.DependenciesFile = depsFile
+		}
+		result.Applications = append(result.Applications, output)
+	}
+
+	result.Providers = mergeProviders(ran...)
+	result.OutputFile = filepath.Join(absOutputDir, "output.yaml")
+	if err := writeMergedOutput(result.Applications, result.OutputFile); err != nil {
+		return nil, err
+	}
+	LogResult(log, result)
+	return result, nil
+}
+
+// executeBulk analyzes the applications of a test with kantra's bulk mode, which adds
+// each to one output directory and static report, then splits the report's combined
+// results per application
+func (k *KantraTarget) executeBulk(ctx context.Context, test *config.TestDefinition, workDir, absOutputDir, mavenSettings string, preparedRules []string) (*ExecutionResult, error) {
+	log := util.GetLogger()
+	// Bulk analyses don't overwrite, start from an empty output directory
+	if err := os.RemoveAll(absOutputDir); err != nil {
+		return nil, fmt.Errorf("failed to clean output directory: %w", err)
+	}
+	if err := os.MkdirAll(absOutputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	// The static report names applications after the base name of their input
+	reportNames := make([]string, len(test.Analysis.Applications))
+	analyzed := map[string]string{}
+	result := &ExecutionResult{WorkDir: workDir}
+	var ran [][]string
+	for i, app := range test.Analysis.Applications {
+		appTest := test.ForApplication(app)
+		inputPath, err := k.prepareInput(ctx, &appTest.Analysis, test.GetTestDir())
+		if err != nil {
+			return nil, fmt.Errorf("failed to prepare input of application %s: %w", app.Name, err)
+		}
+		reportNames[i] = filepath.Base(inputPath)
+		if other, exists := analyzed[reportNames[i]]; exists {
+			return nil, fmt.Errorf("applications %s and %s have the same input name %s, which bulk analyses cannot tell apart", other, app.Name, reportNames[i])
+		}
+		analyzed[reportNames[i]] = app.Name
+
+		log.Info("Analyzing application in bulk", "test", test.Name, "application", app.Name)
+		args := k.buildArgs(appTest.Analysis, inputPath, absOutputDir, mavenSettings, preparedRules)
+		appResult, err := k.analyze(ctx, test, args, workDir, absOutputDir)
+		if err != nil {
+			return nil, fmt.Errorf("application %s: %w", app.Name, err)
+		}
+		result.Duration += appResult.Duration
+		result.ExitCode = firstNonzero(result.ExitCode, appResult.ExitCode)
+		ran = append(ran, appResult.Providers)
+	}
+
+	if test.Analysis.StaticReport {
+		if err := checkStaticReport(absOutputDir); err != nil {
+			return nil, err
+		}
+	}
+	reported, err := parser.ParseStaticReport(filepath.Join(absOutputDir, "static-report", "output.js"))
+	if err != nil {
+		return nil, err
+	}
+	for i, app := range test.Analysis.Applications {
+		rulesets, found := reported[reportNames[i]]
+		if !found {
+			return nil, fmt.Errorf("the static report has no results of application %s (%s)", app.Name, reportNames[i])
+		}
+		output := ApplicationOutput{Name: app.Name, OutputFile: filepath.Join(absOutputDir, app.Name, "output.yaml")}
+		if err := writeOutput(rulesets, output.OutputFile); err != nil {
+			return nil, fmt.Errorf("application %s: %w", app.Name, err)
+		}
+		result.Applications = append(result.Applications, output)
+	}
+
+	result.Providers = mergeProviders(ran...)
+	// Replace the output of the last analysis with the combined results
+	result.OutputFile = filepath.Join(absOutputDir, "output.yaml")
+	if err := writeMergedOutput(result.Applications, result.OutputFile); err != nil {
+		return nil, err
+	}
+	LogResult(log, result)
+	return result, nil
+}
+
+// mergeProviders returns the providers that ran in several analyses, nil when any
+// of them cannot tell
+func mergeProviders(ran ...[]string) []string {
+	merged := []string{}
+	for _, providers := range ran {
+		if providers == nil {
+			return nil
+		}
+		for _, provider := range providers {
+			if !slices.Contains(merged, provider) {
+				merged = append(merged, provider)
+			}
+		}
+	}
+	slices.Sort(merged)
+	return merged
+}
+
+// firstNonzero returns the exit code of several analyses: the first nonzero one
+func firstNonzero(code, next int) int {
+	if code != 0 {
+		return code
+	}
+	return next
+}
+
+// checkStaticReport fails unless kantra wrote a static report into outputDir
+func checkStaticReport(outputDir string) error {
+	index := filepath.Join(outputDir, "static-report", "index.html")
+	if _, err := os.Stat(index); err != nil {
+		return fmt.Errorf("static report was requested, but kantra did not write %s", index)
+	}
+	return nil
+}
+
+// discoveryLabelSelector selects the language and technology discovery rules, which
+// are labeled to be included in every analysis
+const discoveryLabelSelector = "konveyor.io/include=always"
+
 // buildArgsWithPreparedRules constructs the kantra analyze command arguments with prepared rules
 func (k *KantraTarget) buildArgs(analysis config.AnalysisConfig, inputPath, outputDir, mavenSettings string, preparedRules []string) []string {
 	args := []string{"analyze", "--context-lines", strconv.Itoa(analysis.ContextLines)}
@@ -125,16 +482,36 @@ func (k *KantraTarget) buildArgs(analysis config.AnalysisConfig, inputPath, outp
 	// Output directory (now passed as parameter, already absolute)
 	args = append(args, "--output", outputDir)
 
-	args = append(args, "--skip-static-report")
+	// Bulk analyses combine their applications in the static report, which
+	// is skipped unless requested
+	switch {
+	case analysis.Bulk:
+		args = append(args, "--bulk")
+	case !analysis.StaticReport:
+		args = append(args, "--skip-static-report")
+	}
 
-	// Label selector (if specified)
-	if analysis.LabelSelector != "" {
+	// Label selector (if specified); discovery only runs the rules included always
+	switch {
+	case analysis.LabelSelector != "":
 		args = append(args, "--label-selector", analysis.LabelSelector)
+	case analysis.AnalysisMode == config.DiscoveryAnalysisMode:
+		args = append(args, "--label-selector", discoveryLabelSelector)
 	}
 
 	if analysis.IncidentSelector != "" {
 		args = append(args, "--incident-selector", analysis.IncidentSelector)
 	}
+	if analysis.IncidentLimit > 0 {
+		args = append(args, "--incident-limit", strconv.Itoa(analysis.IncidentLimit))
+	}
+	if analysis.CodeSnipLimit > 0 {
+		args = append(args, "--code-snip-limit", strconv.Itoa(analysis.CodeSnipLimit))
+	}
+
+	if analysis.ProviderOverridesFile != "" {
+		args = append(args, "--override-provider-settings", analysis.ProviderOverridesFile)
+	}
 
 	// Maven settings (from test-level configuration)
 	if mavenSettings != "" {
@@ -158,14 +535,20 @@ func (k *KantraTarget) buildArgs(analysis config.AnalysisConfig, inputPath, outp
 		}
 	}
 
-	if analysis.DisableDefaultRules {
-		fmt.Printf("disableDefaultRules")
+	if !analysis.DefaultRulesetsEnabled() {
 		args = append(args, "--enable-default-rulesets=false")
 	}
 
+	if analysis.KnownLibs {
+		args = append(args, "--analyze-known-libraries")
+	}
+	if analysis.DepLabelSelector != "" {
+		args = append(args, "--dep-label-selector", analysis.DepLabelSelector)
+	}
+
 	// Analysis mode
 	switch analysis.AnalysisMode {
-	case provider.SourceOnlyAnalysisMode:
+	case provider.SourceOnlyAnalysisMode, config.DiscoveryAnalysisMode:
 		args = append(args, "--mode", "source-only")
 	case provider.FullAnalysisMode:
 		// Full is the default, but we can be explicit
@@ -175,8 +558,10 @@ func (k *KantraTarget) buildArgs(analysis config.AnalysisConfig, inputPath, outp
 	// Use container mode instead of run-local to avoid dependency issues
 	args = append(args, "--run-local=false")
 
-	// Allow overwriting existing output
-	args = append(args, "--overwrite")
+	// Allow overwriting existing output; bulk analyses add to it
+	if !analysis.Bulk {
+		args = append(args, "--overwrite")
+	}
 
 	return args
 }
@@ -196,7 +581,7 @@ func (k *KantraTarget) prepareInput(ctx context.Context, analysis *config.Analys
 	// Check if we have parsed Git components
 	if analysis.ApplicationGitComponents != nil {
 		// Clone the repository using parsed components
-		return CloneGitRepository(ctx, analysis.ApplicationGitComponents, workDir, "source")
+		return CloneGitRepository(ctx, analysis.ApplicationGitComponents, workDir, analysis.GetSourceDir())
 	}
 
 	// It's a local path or binary reference
@@ -2,11 +2,14 @@ package targets
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/konveyor/analyzer-lsp/provider"
 	"github.com/konveyor/test-harness/pkg/config"
@@ -80,6 +83,37 @@ func TestNewKantraTarget(t *testing.T) {
 	}
 }
 
+func TestParseKantraProviders(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want []string
+	}{
+		{
+			name: "container and containerless sections",
+			out:  "container analysis supported providers:\n\tjava\n\tgo\n\tdotnet\ncontainerless analysis supported providers (default):\n\tjava\n",
+			want: []string{"java", "go", "dotnet"},
+		},
+		{
+			name: "no sections",
+			out:  "java\nnodejs\n",
+			want: []string{"java", "nodejs"},
+		},
+		{
+			name: "other sections",
+			out:  "supported providers:\n  java\n  python\n",
+			want: []string{"java", "python"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseKantraProviders(tt.out); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseKantraProviders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestKantraTarget_Name(t *testing.T) {
 	target := &KantraTarget{}
 	if target.Name() != "kantra" {
@@ -88,6 +122,7 @@ func TestKantraTarget_Name(t *testing.T) {
 }
 
 func TestKantraTarget_BuildArgs(t *testing.T) {
+	no := false
 	tests := []struct {
 		name             string
 		analysis         config.AnalysisConfig
@@ -148,6 +183,19 @@ func TestKantraTarget_BuildArgs(t *testing.T) {
 				"--label-selector", "konveyor.io/target=cloud-readiness",
 			},
 		},
+		{
+			name: "discovery analysis",
+			analysis: config.AnalysisConfig{
+				AnalysisMode: config.DiscoveryAnalysisMode,
+				ContextLines: 10,
+			},
+			inputPath: "/path/to/app",
+			outputDir: "/path/to/output",
+			expectContain: []string{
+				"--mode", "source-only",
+				"--label-selector", "konveyor.io/include=always",
+			},
+		},
 		{
 			name: "analysis with incident selector",
 			analysis: config.AnalysisConfig{
@@ -181,13 +229,137 @@ func TestKantraTarget_BuildArgs(t *testing.T) {
 				ContextLines: 10,
 				Rules:        []string{"/custom/rules1", "/custom/rules2"},
 			},
-			inputPath: "/path/to/app",
-			outputDir: "/path/to/output",
+			inputPath:     "/path/to/app",
+			outputDir:     "/path/to/output",
+			preparedRules: []string{"/custom/rules1", "/custom/rules2"},
 			expectContain: []string{
 				"--rules", "/custom/rules1",
 				"--rules", "/custom/rules2",
 			},
 		},
+		{
+			name: "analysis without default rulesets",
+			analysis: config.AnalysisConfig{
+				AnalysisMode:          provider.SourceOnlyAnalysisMode,
+				ContextLines:          10,
+				Rules:                 []string{"/custom/rules"},
+				EnableDefaultRulesets: &no,
+			},
+			inputPath:     "/path/to/app",
+			outputDir:     "/path/to/output",
+			preparedRules: []string{"/custom/rules"},
+			expectContain: []string{
+				"--enable-default-rulesets=false",
+			},
+		},
+		{
+			name: "analysis with default rulesets",
+			analysis: config.AnalysisConfig{
+				AnalysisMode: provider.SourceOnlyAnalysisMode,
+				ContextLines: 10,
+			},
+			inputPath: "/path/to/app",
+			outputDir: "/path/to/output",
+			expectNotContain: []string{
+				"--enable-default-rulesets=false",
+			},
+		},
+		{
+			name: "analysis skips the static report",
+			analysis: config.AnalysisConfig{
+				AnalysisMode: provider.SourceOnlyAnalysisMode,
+				ContextLines: 10,
+			},
+			inputPath:     "/path/to/app",
+			outputDir:     "/path/to/output",
+			expectContain: []string{"--skip-static-report"},
+		},
+		{
+			name: "analysis with static report",
+			analysis: config.AnalysisConfig{
+				AnalysisMode: provider.SourceOnlyAnalysisMode,
+				ContextLines: 10,
+				StaticReport: true,
+			},
+			inputPath:        "/path/to/app",
+			outputDir:        "/path/to/output",
+			expectNotContain: []string{"--skip-static-report"},
+		},
+		{
+			name: "analysis with known libraries",
+			analysis: config.AnalysisConfig{
+				AnalysisMode: provider.FullAnalysisMode,
+				ContextLines: 10,
+				KnownLibs:    true,
+			},
+			inputPath: "/path/to/app",
+			outputDir: "/path/to/output",
+			expectContain: []string{
+				"--analyze-known-libraries",
+			},
+		},
+		{
+			name: "analysis with dependency label selector",
+			analysis: config.AnalysisConfig{
+				AnalysisMode:     provider.FullAnalysisMode,
+				DepLabelSelector: "konveyor.io/dep-source=internal",
+			},
+			inputPath: "/path/to/app",
+			outputDir: "/path/to/output",
+			expectContain: []string{
+				"--dep-label-selector", "konveyor.io/dep-source=internal",
+			},
+		},
+		{
+			name: "analysis with incident limits",
+			analysis: config.AnalysisConfig{
+				AnalysisMode:  provider.SourceOnlyAnalysisMode,
+				IncidentLimit: 5,
+				CodeSnipLimit: 2,
+			},
+			inputPath: "/path/to/app",
+			outputDir: "/path/to/output",
+			expectContain: []string{
+				"--incident-limit", "5",
+				"--code-snip-limit", "2",
+			},
+		},
+		{
+			name: "analysis with provider overrides",
+			analysis: config.AnalysisConfig{
+				AnalysisMode:          provider.SourceOnlyAnalysisMode,
+				ProviderOverridesFile: "/path/to/work/artifacts/provider-overrides.json",
+			},
+			inputPath: "/path/to/app",
+			outputDir: "/path/to/output",
+			expectContain: []string{
+				"--override-provider-settings", "/path/to/work/artifacts/provider-overrides.json",
+			},
+		},
+		{
+			name: "analysis without incident limits",
+			analysis: config.AnalysisConfig{
+				AnalysisMode: provider.SourceOnlyAnalysisMode,
+			},
+			inputPath: "/path/to/app",
+			outputDir: "/path/to/output",
+			expectNotContain: []string{
+				"--incident-limit",
+				"--code-snip-limit",
+			},
+		},
+		{
+			name: "analysis without known libraries",
+			analysis: config.AnalysisConfig{
+				AnalysisMode: provider.FullAnalysisMode,
+				ContextLines: 10,
+			},
+			inputPath: "/path/to/app",
+			outputDir: "/path/to/output",
+			expectNotContain: []string{
+				"--analyze-known-libraries",
+			},
+		},
 		{
 			name: "analysis without maven settings",
 			analysis: config.AnalysisConfig{
@@ -404,6 +576,501 @@ func TestKantraTarget_ValidateMavenSettings(t *testing.T) {
 	}
 }
 
+func TestKantraTarget_TestMavenSettings(t *testing.T) {
+	dir := t.TempDir()
+	// A fake kantra recording its arguments
+	argsFile := filepath.Join(dir, "args")
+	binary := filepath.Join(dir, "kantra")
+	script := "#!/bin/sh\necho \"$@\" > " + argsFile + "\n"
+	if err := os.WriteFile(binary, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "settings.xml"), []byte("<password>${env:KONCUR_TEST_KANTRA_MAVEN}</password>\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KONCUR_TEST_KANTRA_MAVEN", "kantra-maven-4649")
+
+	test := &config.TestDefinition{
+		Name:                 "maven",
+		WorkDir:              filepath.Join(dir, "work"),
+		RequireMavenSettings: true,
+		MavenSettings:        "settings.xml",
+		Analysis:             config.AnalysisConfig{Application: dir},
+	}
+	test.SetTestFilePath(filepath.Join(dir, "test.yaml"))
+
+	target := &KantraTarget{binaryPath: binary, mavenSettings: "/target/settings.xml"}
+	result, err := target.Execute(context.Background(), test)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rendered := filepath.Join(result.WorkDir, config.RenderedMavenSettingsFile)
+	args, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(args), "--maven-settings "+rendered) {
+		t.Errorf("expected the rendered test settings %s, got args %s", rendered, args)
+	}
+	data, err := os.ReadFile(rendered)
+	if err != nil || string(data) != "<password>kantra-maven-4649</password>\n" {
+		t.Errorf("rendered settings = %q, %v", data, err)
+	}
+}
+
+func TestKantraTarget_ExecuteBulk(t *testing.T) {
+	dir := t.TempDir()
+	for _, app := range []string{"coolstore", "daytrader"} {
+		if err := os.MkdirAll(filepath.Join(dir, "apps", app), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A fake kantra recording its arguments and adding its input to the static report
+	argsFile := filepath.Join(dir, "args")
+	binary := filepath.Join(dir, "kantra")
+	script := `#!/bin/sh
+echo "$@" >> ` + argsFile + `
+while [ $# -gt 0 ]; do
+  case "$1" in
+    --input) input=$(basename "$2"); shift ;;
+    --output) out="$2"; shift ;;
+  esac
+  shift
+done
+mkdir -p "$out/static-report"
+apps="$out/static-report/apps"
+echo "{\"name\": \"$input\", \"rulesets\": [{\"name\": \"rs\", \"tags\": [\"$input\"]}]}" >> "$apps"
+printf 'window["apps"] = [%s]' "$(paste -sd, "$apps")" > "$out/static-report/output.js"
+echo "- name: last" > "$out/output.yaml"
+`
+	if err := os.WriteFile(binary, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	test := &config.TestDefinition{
+		Name:    "bulk",
+		WorkDir: filepath.Join(dir, "work"),
+		Analysis: config.AnalysisConfig{
+			Applications: []config.ApplicationInput{
+				{Name: "coolstore", Application: "./apps/coolstore"},
+				{Name: "daytrader", Application: "./apps/daytrader"},
+			},
+			Bulk: true,
+		},
+	}
+	test.SetTestFilePath(filepath.Join(dir, "test.yaml"))
+
+	target := &KantraTarget{binaryPath: binary}
+	result, err := target.Execute(context.Background(), test)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(string(args), "--bulk") != 2 || strings.Contains(string(args), "--overwrite") {
+		t.Errorf("expected two bulk analyses without --overwrite, got args %s", args)
+	}
+	if len(result.Applications) != 2 {
+		t.Fatalf("expected 2 application outputs, got %+v", result.Applications)
+	}
+	for _, app := range result.Applications {
+		data, err := os.ReadFile(app.OutputFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), "- "+app.Name) || strings.Count(string(data), "name: rs") != 1 {
+			t.Errorf("output of %s holds other results:\n%s", app.Name, data)
+		}
+	}
+	merged, err := os.ReadFile(result.OutputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(merged), "coolstore") || !strings.Contains(string(merged), "daytrader") {
+		t.Errorf("expected the combined output of both applications, got:\n%s", merged)
+	}
+}
+
+func TestKantraTarget_ExecuteApplications(t *testing.T) {
+	dir := t.TempDir()
+	for _, app := range []string{"coolstore", "daytrader"} {
+		if err := os.MkdirAll(filepath.Join(dir, "apps", app), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A fake kantra taking most of the test's timeout, listing its input as a dependency
+	binary := filepath.Join(dir, "kantra")
+	script := `#!/bin/sh
+while [ $# -gt 0 ]; do
+  case "$1" in
+    --input) input=$(basename "$2"); shift ;;
+    --output) out="$2"; shift ;;
+  esac
+  shift
+done
+echo "- name: $input" > "$out/output.yaml"
+printf -- '- provider: java\n  dependencies:\n  - name: %s\n' "$input" > "$out/dependencies.yaml"
+exec sleep 1
+`
+	if err := os.WriteFile(binary, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	test := &config.TestDefinition{
+		Name:    "applications",
+		WorkDir: filepath.Join(dir, "work"),
+		Timeout: &config.Duration{Duration: 1500 * time.Millisecond},
+		Analysis: config.AnalysisConfig{
+			Applications: []config.ApplicationInput{
+				{Name: "coolstore", Application: "./apps/coolstore"},
+				{Name: "daytrader", Application: "./apps/daytrader"},
+			},
+		},
+	}
+	test.SetTestFilePath(filepath.Join(dir, "test.yaml"))
+
+	target := &KantraTarget{binaryPath: binary}
+	result, err := target.Execute(context.Background(), test)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each analysis fits in the timeout, both together do not
+	if result.ExitCode == 0 {
+		t.Error("expected the second analysis to be killed at the test's timeout")
+	}
+	if len(result.Applications) != 2 {
+		t.Fatalf("expected 2 application outputs, got %+v", result.Applications)
+	}
+	for _, app := range result.Applications {
+		deps, err := os.ReadFile(app.DependenciesFile)
+		if err != nil {
+			t.Fatalf("expected the dependencies of %s: %v", app.Name, err)
+		}
+		if !strings.Contains(string(deps), "name: "+app.Name) {
+			t.Errorf("dependencies of %s hold other results:\n%s", app.Name, deps)
+		}
+	}
+}
+
+func TestKantraTarget_StaticReport(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		wantErr bool
+	}{
+		{"report written", "mkdir -p \"$out/static-report\" && touch \"$out/static-report/index.html\"\n", false},
+		{"report missing", "", true},
+		// Nonzero exits are returned for exit code expectations, without a report
+		{"analysis failed", "exit 3\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			// A fake kantra writing the static report into its output directory
+			binary := filepath.Join(dir, "kantra")
+			script := "#!/bin/sh\nwhile [ $# -gt 0 ]; do [ \"$1\" = --output ] && out=\"$2\"; shift; done\n" + tt.script
+			if err := os.WriteFile(binary, []byte(script), 0755); err != nil {
+				t.Fatal(err)
+			}
+			test := &config.TestDefinition{
+				Name:     "report",
+				WorkDir:  filepath.Join(dir, "work"),
+				Analysis: config.AnalysisConfig{Application: dir, StaticReport: true},
+			}
+			test.SetTestFilePath(filepath.Join(dir, "test.yaml"))
+
+			target := &KantraTarget{binaryPath: binary}
+			if _, err := target.Execute(context.Background(), test); (err != nil) != tt.wantErr {
+				t.Errorf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKantraTarget_Images(t *testing.T) {
+	dir := t.TempDir()
+	// A fake kantra recording the images it was given
+	envFile := filepath.Join(dir, "env")
+	binary := filepath.Join(dir, "kantra")
+	script := "#!/bin/sh\necho \"$RUNNER_IMG $JAVA_PROVIDER_IMG\" > " + envFile + "\n"
+	if err := os.WriteFile(binary, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	test := &config.TestDefinition{
+		Name:     "images",
+		WorkDir:  filepath.Join(dir, "work"),
+		Analysis: config.AnalysisConfig{Application: dir},
+		Kantra:   &config.KantraImages{Image: "localhost/kantra:candidate"},
+	}
+	test.SetTestFilePath(filepath.Join(dir, "test.yaml"))
+
+	target, err := NewKantraTarget(&config.KantraConfig{
+		BinaryPath: binary,
+		KantraImages: config.KantraImages{
+			Image:    "quay.io/konveyor/kantra:latest",
+			Settings: map[string]string{"JAVA_PROVIDER_IMG": "localhost/java-provider:candidate"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := target.Execute(context.Background(), test); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(envFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "localhost/kantra:candidate localhost/java-provider:candidate" {
+		t.Errorf("kantra ran with images %q", got)
+	}
+}
+
+func TestKantraTarget_Artifacts(t *testing.T) {
+	dir := t.TempDir()
+	// A fake kantra printing to its console and generating provider settings
+	binary := filepath.Join(dir, "kantra")
+	script := "#!/bin/sh\nwhile [ $# -gt 0 ]; do [ \"$1\" = --output ] && out=\"$2\"; shift; done\n" +
+		"echo analyzing\necho provider failed >&2\necho '[]' > \"$out/provider_settings.json\"\nexit 1\n"
+	if err := os.WriteFile(binary, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	test := &config.TestDefinition{
+		Name:     "artifacts",
+		WorkDir:  filepath.Join(dir, "work"),
+		Analysis: config.AnalysisConfig{Application: dir},
+		Kantra:   &config.KantraImages{Image: "localhost/kantra:candidate"},
+	}
+	test.SetTestFilePath(filepath.Join(dir, "test.yaml"))
+
+	target := &KantraTarget{binaryPath: binary}
+	result, err := target.Execute(context.Background(), test)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	artifacts := ArtifactsDir(result.WorkDir)
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(artifacts, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	if command := read("command.txt"); !strings.HasPrefix(command, "RUNNER_IMG=localhost/kantra:candidate "+binary+" analyze ") {
+		t.Errorf("unexpected command line %q", command)
+	}
+	if console := read("console.txt"); !strings.Contains(console, "analyzing\n") || !strings.Contains(console, "provider failed\n") {
+		t.Errorf("expected stdout and stderr in the console log, got %q", console)
+	}
+	if settings := read("provider_settings.json"); settings != "[]\n" {
+		t.Errorf("unexpected provider settings %q", settings)
+	}
+}
+
+func TestKantraTarget_ProviderOverrides(t *testing.T) {
+	dir := t.TempDir()
+	// A fake kantra repeating the provider settings it was given
+	binary := filepath.Join(dir, "kantra")
+	script := "#!/bin/sh\nwhile [ $# -gt 0 ]; do [ \"$1\" = --override-provider-settings ] && cat \"$2\"; shift; done\n"
+	if err := os.WriteFile(binary, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	test := &config.TestDefinition{
+		Name:    "provider-overrides",
+		WorkDir: filepath.Join(dir, "work"),
+		Analysis: config.AnalysisConfig{
+			Application: dir,
+			ProviderOverrides: []config.ProviderOverride{{
+				Name:                   "java",
+				ProviderSpecificConfig: map[string]any{"bundles": "/jdtls/bundles/custom.jar"},
+			}},
+		},
+	}
+	test.SetTestFilePath(filepath.Join(dir, "test.yaml"))
+
+	target := &KantraTarget{binaryPath: binary}
+	result, err := target.Execute(context.Background(), test)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	console, err := os.ReadFile(filepath.Join(ArtifactsDir(result.WorkDir), "console.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The console log starts with the command line
+	_, given, _ := strings.Cut(string(console), "\n")
+	var settings []struct {
+		Name       string `json:"name"`
+		InitConfig []struct {
+			ProviderSpecificConfig map[string]any `json:"providerSpecificConfig"`
+		} `json:"initConfig"`
+	}
+	if err := json.Unmarshal([]byte(given), &settings); err != nil {
+		t.Fatalf("expected kantra to be given provider settings, got %q: %v", given, err)
+	}
+	if len(settings) != 1 || settings[0].Name != "java" || len(settings[0].InitConfig) != 1 ||
+		settings[0].InitConfig[0].ProviderSpecificConfig["bundles"] != "/jdtls/bundles/custom.jar" {
+		t.Errorf("unexpected provider settings %+v", settings)
+	}
+}
+
+func TestRanProviders(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{"no settings", nil, nil},
+		{"provider settings", map[string]string{
+			"provider_settings.json": `[{"name":"nodejs"},{"name":"java"},{"name":"builtin"}]`,
+		}, []string{"builtin", "java", "nodejs"}},
+		{"other settings", map[string]string{"settings.json": `{"mode":"full"}`}, nil},
+		{"several settings files", map[string]string{
+			"java_settings.json":   `[{"name":"java"},{"name":"builtin"}]`,
+			"dotnet_settings.json": `[{"name":"dotnet"},{"name":"builtin"}]`,
+			"dotnet_settings.yaml": "- name: ignored\n",
+		}, []string{"builtin", "dotnet", "java"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := ranProviders(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ranProviders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if merged := mergeProviders([]string{"java", "builtin"}, []string{"builtin", "nodejs"}); !reflect.DeepEqual(merged, []string{"builtin", "java", "nodejs"}) {
+		t.Errorf("mergeProviders() = %v", merged)
+	}
+	if merged := mergeProviders([]string{"java"}, nil); merged != nil {
+		t.Errorf("expected providers of analyses that cannot tell to be unknown, got %v", merged)
+	}
+}
+
+func TestKantraTarget_ListLabels(t *testing.T) {
+	dir := t.TempDir()
+	// A fake kantra listing labels, and repeating its arguments on stderr
+	binary := filepath.Join(dir, "kantra")
+	script := `#!/bin/sh
+echo "$@" >&2
+case "$2" in
+--list-sources) printf 'available source technologies:\njava-ee\nspringboot\n' ;;
+--list-targets) printf 'available target technologies:\nquarkus\ncloud-readiness\nquarkus\n' ;;
+esac
+`
+	if err := os.WriteFile(binary, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	no := false
+	test := &config.TestDefinition{
+		Name:     "labels",
+		Kind:     config.KindListLabels,
+		WorkDir:  filepath.Join(dir, "work"),
+		Analysis: config.AnalysisConfig{Rules: []string{dir}, EnableDefaultRulesets: &no},
+	}
+	test.SetTestFilePath(filepath.Join(dir, "test.yaml"))
+
+	target := &KantraTarget{binaryPath: binary}
+	labels, err := target.ListLabels(context.Background(), test)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(labels.Sources, []string{"java-ee", "springboot"}) || !reflect.DeepEqual(labels.Targets, []string{"cloud-readiness", "quarkus"}) {
+		t.Errorf("ListLabels() = %v and %v", labels.Sources, labels.Targets)
+	}
+	console, err := os.ReadFile(filepath.Join(ArtifactsDir(labels.WorkDir), "console.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "analyze --list-targets --rules " + dir + " --enable-default-rulesets=false --run-local=false"; !strings.Contains(string(console), want) {
+		t.Errorf("expected kantra to run %q, console:\n%s", want, console)
+	}
+}
+
+func TestKantraTarget_Transform(t *testing.T) {
+	dir := t.TempDir()
+	// A fake kantra converting rules into its output, or rewriting its input
+	binary := filepath.Join(dir, "kantra")
+	script := `#!/bin/sh
+command=$2
+while [ $# -gt 0 ]; do
+  case "$1" in
+  --input) in="$2" ;;
+  --output) out="$2" ;;
+  esac
+  shift
+done
+case "$command" in
+rules) echo "- ruleID: converted" > "$out/01-rules.yaml" ;;
+openrewrite) sed -i 's/javax/jakarta/' "$in/App.java" ;;
+esac
+`
+	if err := os.WriteFile(binary, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	app := filepath.Join(dir, "app")
+	if err := os.MkdirAll(app, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(app, "App.java"), []byte("import javax.inject.Inject;\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		transform config.TransformConfig
+		file      string
+		want      string
+	}{
+		{"rules", config.TransformConfig{Command: config.TransformRules, Input: "windup"}, "01-rules.yaml", "- ruleID: converted\n"},
+		{"openrewrite", config.TransformConfig{Command: config.TransformOpenRewrite, Input: "app", Target: "jakarta-imports"}, "App.java", "import jakarta.inject.Inject;\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := &config.TestDefinition{
+				Name:      tt.name,
+				Kind:      config.KindTransform,
+				WorkDir:   filepath.Join(dir, "work"),
+				Transform: &tt.transform,
+			}
+			test.SetTestFilePath(filepath.Join(dir, "test.yaml"))
+
+			target := &KantraTarget{binaryPath: binary}
+			result, err := target.Transform(context.Background(), test)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(filepath.Join(result.OutputDir, tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("%s = %q, want %q", tt.file, data, tt.want)
+			}
+		})
+	}
+
+	// The application itself is not rewritten
+	if data, _ := os.ReadFile(filepath.Join(app, "App.java")); string(data) != "import javax.inject.Inject;\n" {
+		t.Errorf("the test's application was rewritten: %q", data)
+	}
+}
+
 func TestKantraTarget_AnalysisMode(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -420,6 +1087,11 @@ func TestKantraTarget_AnalysisMode(t *testing.T) {
 			analysisMode: provider.FullAnalysisMode,
 			expectFlag:   "full",
 		},
+		{
+			name:         "discovery mode",
+			analysisMode: config.DiscoveryAnalysisMode,
+			expectFlag:   "source-only",
+		},
 	}
 
 	for _, tt := range tests {
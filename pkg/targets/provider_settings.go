@@ -0,0 +1,85 @@
+package targets
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/konveyor/test-harness/pkg/config"
+)
+
+// providerOverridesFile is the artifact provider overrides are written to
+const providerOverridesFile = "provider-overrides.json"
+
+// providerSettings is the part of a provider's settings that overrides set, in
+// the format of analyzer provider settings files
+type providerSettings struct {
+	Name       string           `json:"name"`
+	InitConfig []providerConfig `json:"initConfig"`
+}
+
+type providerConfig struct {
+	ProviderSpecificConfig map[string]any `json:"providerSpecificConfig"`
+}
+
+// writeProviderOverrides writes provider overrides as a provider settings file into
+// the artifacts of a work directory and returns its absolute path
+func writeProviderOverrides(overrides []config.ProviderOverride, workDir string) (string, error) {
+	settings := make([]providerSettings, len(overrides))
+	for i, override := range overrides {
+		settings[i] = providerSettings{
+			Name:       override.Name,
+			InitConfig: []providerConfig{{ProviderSpecificConfig: override.ProviderSpecificConfig}},
+		}
+	}
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal provider overrides: %w", err)
+	}
+
+	dir, err := filepath.Abs(ArtifactsDir(workDir))
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute artifacts path: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create artifacts directory: %w", err)
+	}
+	file := filepath.Join(dir, providerOverridesFile)
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write provider overrides: %w", err)
+	}
+	return file, nil
+}
+
+// ranProviders reads the providers an analysis ran from the provider settings
+// kantra generated into its output directory, nil when it generated none
+func ranProviders(outputDir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(outputDir, "*settings*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list settings: %w", err)
+	}
+	var providers []string
+	for _, file := range matches {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read settings %s: %w", file, err)
+		}
+		var settings []providerSettings
+		if err := json.Unmarshal(data, &settings); err != nil {
+			// Not provider settings
+			continue
+		}
+		if providers == nil {
+			providers = []string{}
+		}
+		for _, provider := range settings {
+			if provider.Name != "" && !slices.Contains(providers, provider.Name) {
+				providers = append(providers, provider.Name)
+			}
+		}
+	}
+	slices.Sort(providers)
+	return providers, nil
+}
@@ -2,6 +2,7 @@ package targets
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"maps"
 	"os"
@@ -85,6 +86,25 @@ type Tagger struct {
 	Source  string `json:"source"`
 }
 
+// errNoRulesRepository is returned for analyses without default rulesets whose rules
+// the Hub cannot run, as it only analyzes custom rules from a git repository
+var errNoRulesRepository = errors.New("default rulesets are disabled, but tackle hub only analyzes rules from a git repository")
+
+// errIncidentLimits is returned for analyses with incident or code snip limits, which
+// the Hub's analyzer addon does not let tasks set
+var errIncidentLimits = errors.New("incidentLimit and codeSnipLimit cannot be run on tackle hub, its analyzer addon has no such settings")
+
+// errDepLabelSelector is returned for analyses with a dependency label selector the
+// Hub's analyzer addon cannot express, as it only excludes known libraries
+var errDepLabelSelector = errors.New("depLabelSelector cannot be run on tackle hub, its analyzer addon only excludes " + openSourceDepLabel + " dependencies unless knownLibs is set")
+
+// openSourceDepLabel labels the known libraries the addon excludes without withKnownLibs
+const openSourceDepLabel = "konveyor.io/dep-source=open-source"
+
+// errProviderOverrides is returned for analyses overriding provider settings, which
+// the Hub configures per addon extension rather than per task
+var errProviderOverrides = errors.New("providerOverrides cannot be run on tackle hub, its provider settings are configured by addon extensions")
+
 // TackleHubTarget implements Target for Tackle Hub API
 type TackleHubTarget struct {
 	url           string
@@ -121,27 +141,81 @@ func (t *TackleHubTarget) Name() string {
 	return "tackle-hub"
 }
 
+// CheckConnection verifies that the Hub answers API requests with the configured credentials
+func (t *TackleHubTarget) CheckConnection() error {
+	if _, err := t.client.Application.List(); err != nil {
+		return fmt.Errorf("failed to list applications on %s: %w", t.url, err)
+	}
+	return nil
+}
+
+// Providers lists the provider extensions of the Hub's addons
+func (t *TackleHubTarget) Providers(ctx context.Context) ([]string, error) {
+	addons, err := t.client.Addon.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list addons on %s: %w", t.url, err)
+	}
+	providers := []string{}
+	for _, addon := range addons {
+		for _, extension := range addon.Extensions {
+			providers = append(providers, extension.Name)
+		}
+	}
+	return providers, nil
+}
+
 // Execute runs analysis via Tackle Hub API
 func (t *TackleHubTarget) Execute(ctx context.Context, test *config.TestDefinition) (*ExecutionResult, error) {
 	log := util.GetLogger()
 	start := time.Now()
 
 	// Validate maven settings requirement
-	if test.RequireMavenSettings && t.mavenSettings == "" {
+	if test.RequireMavenSettings && t.mavenSettings == "" && test.MavenSettings == "" {
 		return nil, fmt.Errorf("test requires maven settings but none configured in target config")
 	}
 
+	// Fail before creating anything on the Hub when the incident selector or rules cannot be run
+	if test.Analysis.IncidentSelector != "" {
+		if _, _, err := ParseIncidentSelector(test.Analysis.IncidentSelector); err != nil {
+			return nil, err
+		}
+	}
+	if !test.Analysis.DefaultRulesetsEnabled() && len(test.Analysis.RulesGitComponents) == 0 {
+		return nil, errNoRulesRepository
+	}
+	if test.Analysis.IncidentLimit > 0 || test.Analysis.CodeSnipLimit > 0 {
+		return nil, errIncidentLimits
+	}
+	if len(test.Analysis.ProviderOverrides) > 0 {
+		return nil, errProviderOverrides
+	}
+	if !hubDepLabelSelector(test.Analysis) {
+		return nil, errDepLabelSelector
+	}
+
 	// Prepare work directory
 	workDir, err := PrepareWorkDir(test.GetWorkDir(), test.Name)
 	if err != nil {
 		return nil, err
 	}
 
+	// The test's own maven settings replace the target's
+	mavenSettings := t.mavenSettings
+	if test.MavenSettings != "" {
+		if mavenSettings, err = test.RenderMavenSettings(workDir); err != nil {
+			return nil, err
+		}
+	}
+
 	log.Info("Executing Tackle Hub analysis", "workDir", workDir)
 
+	if len(test.Analysis.Applications) > 0 {
+		return t.executeApplications(ctx, test, workDir, mavenSettings, start)
+	}
+
 	// Step 1: Create or find application
 	log.Info("Creating application", "name", test.Name)
-	app, err := t.createApplication(test)
+	app, err := t.createApplication(test, mavenSettings)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create application: %w", err)
 	}
@@ -171,9 +245,142 @@ func (t *TackleHubTarget) Execute(ctx context.Context, test *config.TestDefiniti
 	}
 	log.Info("Analysis task completed successfully", "taskID", task.ID)
 
+	// Addon logs are kept for provider error detection, they are not required
+	if err := t.downloadTaskLogs(task.ID, workDir); err != nil {
+		log.Info("Failed to download task logs", "taskID", task.ID, "error", err.Error())
+	}
+
+	// Convert the Hub's findings into an output file
+	outputDir := filepath.Join(workDir, "output")
+	outputFile, err := t.writeApplicationOutput(app.ID, outputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Successfully wrote analysis results", "file", outputFile)
+
+	duration := time.Since(start)
+	result := &ExecutionResult{
+		ExitCode:   0,
+		Duration:   duration,
+		OutputFile: outputFile,
+		WorkDir:    workDir,
+	}
+
+	// Dependencies are kept for dependency baselines, they are not required
+	depsFile, err := t.downloadDependencies(app.ID, outputDir)
+	if err != nil {
+		log.Info("Failed to download dependencies", "applicationID", app.ID, "error", err.Error())
+	}
+	result.DependenciesFile = depsFile
+
+	return result, nil
+}
+
+// executeApplications analyzes the applications of a test analyzing several in one
+// task group, as bulk analyses from the Hub UI do, and writes an output per
+// application. Binary applications are analyzed from their own task buckets, so
+// they cannot be part of a group.
+func (t *TackleHubTarget) executeApplications(ctx context.Context, test *config.TestDefinition, workDir, mavenSettings string, start time.Time) (*ExecutionResult, error) {
+	log := util.GetLogger()
+
+	apps := make([]*api.Application, len(test.Analysis.Applications))
+	for i, input := range test.Analysis.Applications {
+		if IsBinaryFile(input.Application) {
+			return nil, fmt.Errorf("application %s: binary applications cannot be analyzed in a task group", input.Name)
+		}
+		app, err := t.createApplication(test.ForApplication(input), mavenSettings)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create application %s: %w", input.Name, err)
+		}
+		log.Info("Application created", "id", app.ID, "name", app.Name)
+		apps[i] = app
+	}
+
+	taskData, err := t.analysisTaskData(ctx, test)
+	if err != nil {
+		return nil, err
+	}
+	group := &api.TaskGroup{
+		Name:  fmt.Sprintf("Analysis: %s", test.Name),
+		Kind:  "analyzer",
+		Addon: "analyzer",
+		Data:  taskData,
+		State: "Created",
+	}
+	for _, app := range apps {
+		group.Tasks = append(group.Tasks, api.Task{
+			Name:        fmt.Sprintf("Analysis: %s", app.Name),
+			Application: &api.Ref{ID: app.ID},
+		})
+	}
+	if err := t.client.Client.Post("/taskgroups", group); err != nil {
+		return nil, fmt.Errorf("failed to create task group: %w", err)
+	}
+	log.Info("Task group created", "taskGroupID", group.ID, "tasks", len(group.Tasks))
+
+	// Submitting the group creates its tasks, which the Hub then runs
+	err = t.client.Client.Put(fmt.Sprintf("/taskgroups/%d/submit", group.ID), group)
+	if err != nil && err.Error() != "json: Unmarshal(nil)" {
+		return nil, fmt.Errorf("failed to submit task group: %w", err)
+	}
+	if err := t.client.Client.Get(fmt.Sprintf("/taskgroups/%d", group.ID), group); err != nil {
+		return nil, fmt.Errorf("failed to get task group: %w", err)
+	}
+	taskIDs := map[uint]uint{}
+	for _, task := range group.Tasks {
+		if task.Application != nil {
+			taskIDs[task.Application.ID] = task.ID
+		}
+	}
+
+	absOutputDir, err := filepath.Abs(filepath.Join(workDir, "output"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute output path: %w", err)
+	}
+	result := &ExecutionResult{WorkDir: workDir}
+	// The tasks run concurrently, so the group shares the test's timeout
+	groupCtx, cancel := context.WithTimeout(ctx, test.GetTimeout())
+	defer cancel()
+	for i, input := range test.Analysis.Applications {
+		taskID, ok := taskIDs[apps[i].ID]
+		if !ok {
+			return nil, fmt.Errorf("task group %d has no task for application %s", group.ID, input.Name)
+		}
+		if err := t.pollTaskCompletion(groupCtx, taskID, test.GetTimeout()); err != nil {
+			return nil, fmt.Errorf("task of application %s failed or timed out: %w", input.Name, err)
+		}
+		if err := t.downloadTaskLogs(taskID, filepath.Join(workDir, input.Name)); err != nil {
+			log.Info("Failed to download task logs", "taskID", taskID, "error", err.Error())
+		}
+		appOutputDir := filepath.Join(absOutputDir, input.Name)
+		outputFile, err := t.writeApplicationOutput(apps[i].ID, appOutputDir)
+		if err != nil {
+			return nil, fmt.Errorf("application %s: %w", input.Name, err)
+		}
+		output := ApplicationOutput{Name: input.Name, OutputFile: outputFile}
+		// Dependencies are kept for dependency baselines, they are not required
+		if output.DependenciesFile, err = t.downloadDependencies(apps[i].ID, appOutputDir); err != nil {
+			log.Info("Failed to download dependencies", "applicationID", apps[i].ID, "error", err.Error())
+		}
+		result.Applications = append(result.Applications, output)
+	}
+
+	result.OutputFile = filepath.Join(absOutputDir, "output.yaml")
+	if err := writeMergedOutput(result.Applications, result.OutputFile); err != nil {
+		return nil, err
+	}
+	result.Duration = time.Since(start)
+	log.Info("Successfully wrote analysis results", "file", result.OutputFile, "applications", len(result.Applications))
+	return result, nil
+}
+
+// writeApplicationOutput converts the insights and tags the Hub has for an
+// application into a kantra output.yaml in outputDir, and returns its path
+func (t *TackleHubTarget) writeApplicationOutput(appID uint, outputDir string) (string, error) {
 	var insights []api.Insight
-	err = t.client.Client.Get(
-		fmt.Sprintf("applications/%v/analysis/insights", app.ID),
+	err := t.client.Client.Get(
+		fmt.Sprintf("applications/%v/analysis/insights", appID),
 		&insights,
 	)
 
@@ -229,10 +436,10 @@ func (t *TackleHubTarget) Execute(ctx context.Context, test *config.TestDefiniti
 		rulesetToInsightConverted[insight.RuleSet] = rs
 	}
 	// Get tags from application
-	appTag := t.client.Application.Tags(app.ID)
+	appTag := t.client.Application.Tags(appID)
 	tags, err := appTag.List()
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
 	// Ensure discovery-rules and technology-usage rulesets exist
@@ -264,36 +471,65 @@ func (t *TackleHubTarget) Execute(ctx context.Context, test *config.TestDefiniti
 	}
 	output, err := yaml.Marshal(slices.Collect(maps.Values(rulesetToInsightConverted)))
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	// Create output directory
-	outputDir := filepath.Join(workDir, "output")
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create output directory: %w", err)
+		return "", fmt.Errorf("failed to create output directory: %w", err)
 	}
 
 	// Write output to file
 	outputFile := filepath.Join(outputDir, "output.yaml")
 	if err := os.WriteFile(outputFile, output, 0644); err != nil {
-		return nil, fmt.Errorf("failed to write output file: %w", err)
+		return "", fmt.Errorf("failed to write output file: %w", err)
 	}
+	return outputFile, nil
+}
 
-	log.Info("Successfully wrote analysis results", "file", outputFile)
+// downloadDependencies writes the dependencies the Hub found for an application as a
+// kantra dependencies.yaml. It returns "" when the analysis found none.
+func (t *TackleHubTarget) downloadDependencies(appID uint, outputDir string) (string, error) {
+	var deps []api.TechDependency
+	if err := t.client.Client.Get(fmt.Sprintf("applications/%v/analysis/dependencies", appID), &deps); err != nil {
+		return "", err
+	}
+	if len(deps) == 0 {
+		return "", nil
+	}
 
-	duration := time.Since(start)
-	result := &ExecutionResult{
-		ExitCode:   0,
-		Duration:   duration,
-		OutputFile: outputFile,
-		WorkDir:    workDir,
+	// The Hub doesn't keep the file dependencies were found in, group them by provider
+	byProvider := map[string]*konveyor.DepsFlatItem{}
+	for _, dep := range deps {
+		item, ok := byProvider[dep.Provider]
+		if !ok {
+			item = &konveyor.DepsFlatItem{Provider: dep.Provider}
+			byProvider[dep.Provider] = item
+		}
+		item.Dependencies = append(item.Dependencies, &konveyor.Dep{
+			Name:     dep.Name,
+			Version:  dep.Version,
+			Indirect: dep.Indirect,
+			Labels:   dep.Labels,
+		})
+	}
+	var items []konveyor.DepsFlatItem
+	for _, provider := range slices.Sorted(maps.Keys(byProvider)) {
+		items = append(items, *byProvider[provider])
 	}
 
-	return result, nil
+	data, err := yaml.Marshal(items)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal dependencies: %w", err)
+	}
+	depsFile := filepath.Join(outputDir, "dependencies.yaml")
+	if err := os.WriteFile(depsFile, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write dependencies file: %w", err)
+	}
+	return depsFile, nil
 }
 
 // createApplication creates a new application in Tackle Hub or finds existing one
-func (t *TackleHubTarget) createApplication(test *config.TestDefinition) (*api.Application, error) {
+func (t *TackleHubTarget) createApplication(test *config.TestDefinition, mavenSettings string) (*api.Application, error) {
 	log := util.GetLogger()
 
 	// First, try to find an existing application with the same name
@@ -308,8 +544,8 @@ func (t *TackleHubTarget) createApplication(test *config.TestDefinition) (*api.A
 			log.Info("Found existing application", "id", existingApp.ID, "name", existingApp.Name)
 
 			// Update identities if maven settings configured
-			if t.mavenSettings != "" {
-				err = t.attachMavenIdentity(&existingApp)
+			if mavenSettings != "" {
+				err = t.attachMavenIdentity(&existingApp, mavenSettings)
 				if err != nil {
 					return nil, fmt.Errorf("failed to attach maven identity: %w", err)
 				}
@@ -355,8 +591,8 @@ func (t *TackleHubTarget) createApplication(test *config.TestDefinition) (*api.A
 	}
 
 	// Attach maven identity if configured
-	if t.mavenSettings != "" {
-		err = t.attachMavenIdentity(app)
+	if mavenSettings != "" {
+		err = t.attachMavenIdentity(app, mavenSettings)
 		if err != nil {
 			return nil, fmt.Errorf("failed to attach maven identity: %w", err)
 		}
@@ -403,6 +639,46 @@ func (t *TackleHubTarget) uploadBinary(task *api.Task, binaryPath string, testDi
 
 // createAnalysisTask creates an analysis task for the application
 func (t *TackleHubTarget) createAnalysisTask(ctx context.Context, test *config.TestDefinition, app *api.Application) (*api.Task, error) {
+	log := util.GetLogger()
+	taskData, err := t.analysisTaskData(ctx, test)
+	if err != nil {
+		return nil, err
+	}
+	isBinary := IsBinaryFile(test.Analysis.Application)
+
+	task := &api.Task{
+		Name:        fmt.Sprintf("Analysis: %s", test.Name),
+		Kind:        "analyzer", // analyzer task kind
+		Addon:       "analyzer",
+		Application: &api.Ref{ID: app.ID},
+		Data:        taskData,
+		State:       "Created",
+	}
+
+	// Debug: log the task before creating
+	log.V(1).Info("Creating task", "name", task.Name, "kind", task.Kind, "addon", task.Addon, "appID", app.ID)
+
+	err = t.client.Task.Create(task)
+	if err != nil {
+		return nil, err
+	}
+	if isBinary {
+		err = t.uploadBinary(task, test.Analysis.Application, test.GetTestDir())
+		if err != nil {
+			return nil, err
+		}
+	}
+	task.State = "Ready"
+	err = t.client.Task.Update(task)
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// analysisTaskData builds the analyzer addon data of a test's analysis
+func (t *TackleHubTarget) analysisTaskData(ctx context.Context, test *config.TestDefinition) (Data, error) {
 	log := util.GetLogger()
 	// Build task data with analysis configuration
 	taskData := Data{}
@@ -424,55 +700,58 @@ func (t *TackleHubTarget) createAnalysisTask(ctx context.Context, test *config.T
 		switch test.Analysis.AnalysisMode {
 		case "source-only":
 			taskData.Mode.WithDeps = false
+		case config.DiscoveryAnalysisMode:
+			taskData.Mode.Discovery = true
+			taskData.Mode.WithDeps = false
 		default:
 			taskData.Mode.WithDeps = true
 		}
 	}
 
+	// Open-source dependencies are excluded unless known libraries are analyzed
+	taskData.Scope.WithKnownLibs = test.Analysis.KnownLibs
+
 	// Add label selector
 	if test.Analysis.LabelSelector != "" {
 		taskData.Rules.Labels = ParseLabelSelector(test.Analysis.LabelSelector)
 	}
 
+	// The addon selects incidents by package scope
+	if test.Analysis.IncidentSelector != "" {
+		included, excluded, err := ParseIncidentSelector(test.Analysis.IncidentSelector)
+		if err != nil {
+			return Data{}, err
+		}
+		taskData.Scope.Packages.Included = included
+		taskData.Scope.Packages.Excluded = excluded
+	}
+
 	// Handle rules that may be Git URLs
 	// Tackle Hub uses repositories for rules, so we'll prepare them differently
 	err := t.prepareRulesForHub(ctx, test, &taskData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to prepare rules: %w", err)
+		return Data{}, fmt.Errorf("failed to prepare rules: %w", err)
 	}
 
-	taskData.Verbosity = 1
-	log.V(1).Info("Using task data", "data", taskData)
-
-	task := &api.Task{
-		Name:        fmt.Sprintf("Analysis: %s", test.Name),
-		Kind:        "analyzer", // analyzer task kind
-		Addon:       "analyzer",
-		Application: &api.Ref{ID: app.ID},
-		Data:        taskData,
-		State:       "Created",
+	// Tasks select no rulesets, the addon picks the Hub's; without default rulesets
+	// the test's rules must be a repository the addon can fetch
+	if !test.Analysis.DefaultRulesetsEnabled() && taskData.Rules.Repository == nil {
+		return Data{}, errNoRulesRepository
 	}
 
-	// Debug: log the task before creating
-	log.V(1).Info("Creating task", "name", task.Name, "kind", task.Kind, "addon", task.Addon, "appID", app.ID)
+	taskData.Verbosity = 1
+	log.V(1).Info("Using task data", "data", taskData)
+	return taskData, nil
+}
 
-	err = t.client.Task.Create(task)
-	if err != nil {
-		return nil, err
+// hubDepLabelSelector reports whether the analysis' dependency label selector is one
+// the addon applies, excluding known libraries when withKnownLibs is not set
+func hubDepLabelSelector(analysis config.AnalysisConfig) bool {
+	if analysis.DepLabelSelector == "" {
+		return true
 	}
-	if isBinary {
-		err = t.uploadBinary(task, test.Analysis.Application, test.GetTestDir())
-		if err != nil {
-			return nil, err
-		}
-	}
-	task.State = "Ready"
-	err = t.client.Task.Update(task)
-	if err != nil {
-		return nil, err
-	}
-
-	return task, nil
+	selector := strings.Trim(strings.ReplaceAll(analysis.DepLabelSelector, " ", ""), "()")
+	return !analysis.KnownLibs && selector == "!"+openSourceDepLabel
 }
 
 // prepareRulesForHub handles rules that may be Git URLs for Tackle Hub
@@ -500,12 +779,15 @@ func (t *TackleHubTarget) prepareRulesForHub(_ context.Context, test *config.Tes
 	return nil
 }
 
+// taskPollInterval is how often task states are fetched while waiting for tasks
+var taskPollInterval = 5 * time.Second
+
 // pollTaskCompletion polls the task until it completes or times out
 func (t *TackleHubTarget) pollTaskCompletion(ctx context.Context, taskID uint, timeout time.Duration) error {
 	log := util.GetLogger()
 
 	deadline := time.Now().Add(timeout)
-	ticker := time.NewTicker(5 * time.Second)
+	ticker := time.NewTicker(taskPollInterval)
 	defer ticker.Stop()
 
 	for {
@@ -581,6 +863,33 @@ func (t *TackleHubTarget) downloadTaskResults(taskID uint, workDir string) (stri
 	return outputFile, nil
 }
 
+// downloadTaskLogs downloads the log attachments of a task (e.g. the addon and
+// provider logs) into the work directory
+func (t *TackleHubTarget) downloadTaskLogs(taskID uint, workDir string) error {
+	log := util.GetLogger()
+
+	task, err := t.client.Task.Get(taskID)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+
+	logDir := filepath.Join(workDir, "logs")
+	for _, attachment := range task.Attached {
+		if !strings.HasSuffix(attachment.Name, ".log") {
+			continue
+		}
+		if err := os.MkdirAll(logDir, 0755); err != nil {
+			return fmt.Errorf("failed to create log directory: %w", err)
+		}
+		logFile := filepath.Join(logDir, filepath.Base(attachment.Name))
+		if err := t.client.Client.FileGet(fmt.Sprintf("/files/%d", attachment.ID), logFile); err != nil {
+			return fmt.Errorf("failed to download %s: %w", attachment.Name, err)
+		}
+		log.V(1).Info("Downloaded task log", "taskID", taskID, "file", logFile)
+	}
+	return nil
+}
+
 // downloadResults downloads the analysis results from the application bucket (deprecated)
 func (t *TackleHubTarget) downloadResults(appID uint, workDir string) (string, error) {
 	log := util.GetLogger()
@@ -625,13 +934,13 @@ func (t *TackleHubTarget) submitTask(taskID uint) error {
 }
 
 // attachMavenIdentity creates or finds a maven settings identity and attaches it to the application
-func (t *TackleHubTarget) attachMavenIdentity(app *api.Application) error {
+func (t *TackleHubTarget) attachMavenIdentity(app *api.Application, mavenSettings string) error {
 	log := util.GetLogger()
 
 	// Read maven settings file
-	settingsContent, err := os.ReadFile(t.mavenSettings)
+	settingsContent, err := os.ReadFile(mavenSettings)
 	if err != nil {
-		return fmt.Errorf("failed to read maven settings file %s: %w", t.mavenSettings, err)
+		return fmt.Errorf("failed to read maven settings file %s: %w", mavenSettings, err)
 	}
 
 	identityName := fmt.Sprintf("maven-settings-%s", app.Name)
@@ -0,0 +1,368 @@
+package targets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/shared/api"
+	"github.com/konveyor/test-harness/pkg/config"
+)
+
+func TestTackleHubTarget_AnalysisTaskData(t *testing.T) {
+	disabled := false
+	rulesRepo := []*config.GitURLComponents{{URL: "https://github.com/konveyor/rulesets", Ref: "main", Path: "default/generated"}}
+
+	tests := []struct {
+		name     string
+		analysis config.AnalysisConfig
+		wantErr  error
+		validate func(t *testing.T, data Data)
+	}{
+		{
+			name:     "full analysis",
+			analysis: config.AnalysisConfig{Application: "https://github.com/konveyor/test-app"},
+			validate: func(t *testing.T, data Data) {
+				if !data.Mode.WithDeps || data.Mode.Discovery {
+					t.Errorf("Expected a full analysis with dependencies, got %+v", data.Mode)
+				}
+				if data.Scope.WithKnownLibs {
+					t.Error("Expected known libraries to be excluded by default")
+				}
+				if data.Rules.RuleSets != nil || data.Rules.Repository != nil {
+					t.Errorf("Expected no ruleset selection, got %+v", data.Rules)
+				}
+			},
+		},
+		{
+			name: "discovery mode",
+			analysis: config.AnalysisConfig{
+				Application:  "https://github.com/konveyor/test-app",
+				AnalysisMode: config.DiscoveryAnalysisMode,
+			},
+			validate: func(t *testing.T, data Data) {
+				if !data.Mode.Discovery || data.Mode.WithDeps {
+					t.Errorf("Expected discovery without dependencies, got %+v", data.Mode)
+				}
+			},
+		},
+		{
+			name: "known libraries",
+			analysis: config.AnalysisConfig{
+				Application: "https://github.com/konveyor/test-app",
+				KnownLibs:   true,
+			},
+			validate: func(t *testing.T, data Data) {
+				if !data.Scope.WithKnownLibs {
+					t.Error("Expected withKnownLibs to be set")
+				}
+			},
+		},
+		{
+			name: "known libraries excluded by dependency label",
+			analysis: config.AnalysisConfig{
+				Application:      "https://github.com/konveyor/test-app",
+				DepLabelSelector: "(!konveyor.io/dep-source=open-source)",
+			},
+			validate: func(t *testing.T, data Data) {
+				if data.Scope.WithKnownLibs {
+					t.Error("Expected withKnownLibs to be unset")
+				}
+			},
+		},
+		{
+			name: "scope packages",
+			analysis: config.AnalysisConfig{
+				Application:      "https://github.com/konveyor/test-app",
+				IncidentSelector: `(package=com.example || package=org.acme) && !package=com.example.generated`,
+			},
+			validate: func(t *testing.T, data Data) {
+				if want := []string{"com.example", "org.acme"}; !reflect.DeepEqual(data.Scope.Packages.Included, want) {
+					t.Errorf("Included = %v, want %v", data.Scope.Packages.Included, want)
+				}
+				if want := []string{"com.example.generated"}; !reflect.DeepEqual(data.Scope.Packages.Excluded, want) {
+					t.Errorf("Excluded = %v, want %v", data.Scope.Packages.Excluded, want)
+				}
+			},
+		},
+		{
+			name: "default rulesets disabled with a rules repository",
+			analysis: config.AnalysisConfig{
+				Application:           "https://github.com/konveyor/test-app",
+				Rules:                 []string{"https://github.com/konveyor/rulesets#main/default/generated"},
+				RulesGitComponents:    rulesRepo,
+				EnableDefaultRulesets: &disabled,
+			},
+			validate: func(t *testing.T, data Data) {
+				if data.Rules.Repository == nil || data.Rules.Repository.URL != rulesRepo[0].URL {
+					t.Errorf("Expected the rules repository, got %+v", data.Rules.Repository)
+				}
+				if data.Rules.RuleSets != nil {
+					t.Errorf("Expected no ruleset selection, got %v", data.Rules.RuleSets)
+				}
+			},
+		},
+		{
+			name: "default rulesets disabled without a rules repository",
+			analysis: config.AnalysisConfig{
+				Application:           "https://github.com/konveyor/test-app",
+				Rules:                 []string{"/opt/rules"},
+				EnableDefaultRulesets: &disabled,
+			},
+			wantErr: errNoRulesRepository,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &TackleHubTarget{}
+			test := &config.TestDefinition{Name: "task-data", Analysis: tt.analysis}
+
+			data, err := target.analysisTaskData(context.Background(), test)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !data.Tagger.Enabled {
+				t.Error("Expected the tagger to be enabled")
+			}
+			tt.validate(t, data)
+		})
+	}
+}
+
+func TestTackleHubTarget_ExecuteRejectsLimits(t *testing.T) {
+	target := &TackleHubTarget{}
+	tests := map[string]config.AnalysisConfig{
+		"incident limit":  {Application: "https://github.com/konveyor/test-app", IncidentLimit: 10},
+		"code snip limit": {Application: "https://github.com/konveyor/test-app", CodeSnipLimit: 5},
+	}
+	for name, analysis := range tests {
+		t.Run(name, func(t *testing.T) {
+			test := &config.TestDefinition{Name: "limits", Analysis: analysis}
+			if _, err := target.Execute(context.Background(), test); !errors.Is(err, errIncidentLimits) {
+				t.Errorf("Expected %v, got %v", errIncidentLimits, err)
+			}
+		})
+	}
+}
+
+func TestTackleHubTarget_ExecuteRejectsDepLabelSelector(t *testing.T) {
+	target := &TackleHubTarget{}
+	tests := map[string]config.AnalysisConfig{
+		"other label":  {Application: "https://github.com/konveyor/test-app", DepLabelSelector: "konveyor.io/dep-source=internal"},
+		"known libs":   {Application: "https://github.com/konveyor/test-app", DepLabelSelector: "!" + openSourceDepLabel, KnownLibs: true},
+		"combined set": {Application: "https://github.com/konveyor/test-app", DepLabelSelector: "!" + openSourceDepLabel + " && konveyor.io/dep-source=internal"},
+	}
+	for name, analysis := range tests {
+		t.Run(name, func(t *testing.T) {
+			test := &config.TestDefinition{Name: "dep-labels", Analysis: analysis}
+			if _, err := target.Execute(context.Background(), test); !errors.Is(err, errDepLabelSelector) {
+				t.Errorf("Expected %v, got %v", errDepLabelSelector, err)
+			}
+		})
+	}
+}
+
+func TestHubDepLabelSelector(t *testing.T) {
+	tests := []struct {
+		analysis config.AnalysisConfig
+		want     bool
+	}{
+		{config.AnalysisConfig{}, true},
+		{config.AnalysisConfig{DepLabelSelector: "!konveyor.io/dep-source=open-source"}, true},
+		{config.AnalysisConfig{DepLabelSelector: "( !konveyor.io/dep-source=open-source )"}, true},
+		{config.AnalysisConfig{DepLabelSelector: "!konveyor.io/dep-source=open-source", KnownLibs: true}, false},
+		{config.AnalysisConfig{DepLabelSelector: "konveyor.io/dep-source=open-source"}, false},
+	}
+	for _, tt := range tests {
+		if got := hubDepLabelSelector(tt.analysis); got != tt.want {
+			t.Errorf("hubDepLabelSelector(%q, knownLibs %v) = %v, want %v", tt.analysis.DepLabelSelector, tt.analysis.KnownLibs, got, tt.want)
+		}
+	}
+}
+
+// fakeHub serves the Hub API used by task group analyses: each application gets a
+// task that is running when first polled, then succeeds with one insight and one
+// dependency named after the application
+type fakeHub struct {
+	mu    sync.Mutex
+	apps  []api.Application
+	group api.TaskGroup
+	polls map[string]int
+}
+
+func (h *fakeHub) handler() http.Handler {
+	mux := http.NewServeMux()
+	reply := func(w http.ResponseWriter, status int, body any) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(body)
+	}
+	app := func(r *http.Request) *api.Application {
+		id, _ := strconv.Atoi(r.PathValue("id"))
+		if id < 1 || id > len(h.apps) {
+			return nil
+		}
+		return &h.apps[id-1]
+	}
+	handle := func(pattern string, serve func(w http.ResponseWriter, r *http.Request)) {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			serve(w, r)
+		})
+	}
+
+	handle("GET /applications", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, http.StatusOK, h.apps)
+	})
+	handle("POST /applications", func(w http.ResponseWriter, r *http.Request) {
+		var created api.Application
+		_ = json.NewDecoder(r.Body).Decode(&created)
+		created.ID = uint(len(h.apps) + 1)
+		h.apps = append(h.apps, created)
+		reply(w, http.StatusCreated, created)
+	})
+	handle("POST /taskgroups", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&h.group)
+		h.group.ID = 7
+		reply(w, http.StatusCreated, h.group)
+	})
+	handle("PUT /taskgroups/7/submit", func(w http.ResponseWriter, r *http.Request) {
+		for i := range h.group.Tasks {
+			h.group.Tasks[i].ID = uint(100 + i)
+		}
+		h.group.State = "Ready"
+		w.WriteHeader(http.StatusNoContent)
+	})
+	handle("GET /taskgroups/7", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, http.StatusOK, h.group)
+	})
+	handle("GET /tasks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		h.polls[r.PathValue("id")]++
+		state := TaskStateRunning
+		if h.polls[r.PathValue("id")] > 1 {
+			state = TaskStateSucceeded
+		}
+		id, _ := strconv.Atoi(r.PathValue("id"))
+		reply(w, http.StatusOK, api.Task{Resource: api.Resource{ID: uint(id)}, State: state})
+	})
+	handle("GET /applications/{id}/analysis/insights", func(w http.ResponseWriter, r *http.Request) {
+		a := app(r)
+		if a == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		reply(w, http.StatusOK, []api.Insight{{
+			RuleSet:   "rs",
+			Rule:      "rule-" + a.Repository.URL,
+			Effort:    1,
+			Incidents: []api.Incident{{File: "/opt/input/source/src/App.java", Line: 3, Message: a.Name}},
+		}})
+	})
+	handle("GET /applications/{id}/tags", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, http.StatusOK, []api.TagRef{})
+	})
+	handle("GET /applications/{id}/analysis/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		a := app(r)
+		if a == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		reply(w, http.StatusOK, []api.TechDependency{{Provider: "java", Name: "dep-" + a.Repository.URL, Version: "1.0"}})
+	})
+	return mux
+}
+
+func TestTackleHubTarget_ExecuteApplications(t *testing.T) {
+	interval := taskPollInterval
+	taskPollInterval = 10 * time.Millisecond
+	defer func() { taskPollInterval = interval }()
+
+	hub := &fakeHub{polls: map[string]int{}}
+	// The Hub API is served under /hub, like in the Hub's deployments
+	server := httptest.NewServer(http.StripPrefix("/hub", hub.handler()))
+	defer server.Close()
+
+	target, err := NewTackleHubTarget(&config.TackleHubConfig{URL: server.URL + "/hub"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	test := &config.TestDefinition{
+		Name:    "group",
+		WorkDir: filepath.Join(dir, "work"),
+		Analysis: config.AnalysisConfig{
+			Applications: []config.ApplicationInput{
+				{Name: "coolstore", Application: "coolstore"},
+				{Name: "daytrader", Application: "daytrader"},
+			},
+			KnownLibs: true,
+		},
+	}
+	test.SetTestFilePath(filepath.Join(dir, "test.yaml"))
+
+	result, err := target.Execute(context.Background(), test)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(hub.group.Tasks) != 2 || hub.group.Addon != "analyzer" {
+		t.Fatalf("expected an analyzer task group with 2 tasks, got %+v", hub.group)
+	}
+	data, ok := hub.group.Data.(map[string]any)
+	if !ok || data["scope"].(map[string]any)["withKnownLibs"] != true {
+		t.Errorf("expected the group's task data to hold the analysis settings, got %v", hub.group.Data)
+	}
+	for id, polls := range hub.polls {
+		if polls < 2 {
+			t.Errorf("task %s was not polled until it succeeded", id)
+		}
+	}
+
+	if len(result.Applications) != 2 {
+		t.Fatalf("expected 2 application outputs, got %+v", result.Applications)
+	}
+	for _, app := range result.Applications {
+		output, err := os.ReadFile(app.OutputFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(output), "rule-"+app.Name) || strings.Count(string(output), "rule-") != 1 {
+			t.Errorf("output of %s holds other results:\n%s", app.Name, output)
+		}
+		if !strings.Contains(string(output), "/source/src/App.java") {
+			t.Errorf("expected normalized incident paths in the output of %s:\n%s", app.Name, output)
+		}
+		deps, err := os.ReadFile(app.DependenciesFile)
+		if err != nil {
+			t.Fatalf("expected the dependencies of %s: %v", app.Name, err)
+		}
+		if !strings.Contains(string(deps), "dep-"+app.Name) || strings.Count(string(deps), "dep-") != 1 {
+			t.Errorf("dependencies of %s hold other results:\n%s", app.Name, deps)
+		}
+	}
+	merged, err := os.ReadFile(result.OutputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(merged), "rule-coolstore") || !strings.Contains(string(merged), "rule-daytrader") {
+		t.Errorf("expected the merged output of both applications, got:\n%s", merged)
+	}
+}
@@ -49,5 +49,8 @@ func (t *TackleUITarget) Execute(ctx context.Context, test *config.TestDefinitio
 	// 3. Navigate and create application
 	// 4. Configure and trigger analysis
 	// 5. Wait for completion and download results
+	// 6. On any failure, save screenshots, the browser console log and a Playwright
+	//    trace into ArtifactsDir(workDir), which koncur archive includes, so UI
+	//    failures in CI can be debugged
 	return nil, fmt.Errorf("tackle-ui target not yet implemented")
 }
@@ -4,7 +4,9 @@ import (
 	"context"
 	"time"
 
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
 	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/parser"
 )
 
 // Target represents a tool that can be executed (kantra, tackle, kai)
@@ -16,6 +18,53 @@ type Target interface {
 	Execute(ctx context.Context, test *config.TestDefinition) (*ExecutionResult, error)
 }
 
+// ProviderLister is implemented by targets that can tell which analysis providers
+// they have, e.g. java or dotnet
+type ProviderLister interface {
+	Providers(ctx context.Context) ([]string, error)
+}
+
+// LabelLister is implemented by targets that can list the source and target labels
+// of the rulesets a test would run, without analyzing anything
+type LabelLister interface {
+	ListLabels(ctx context.Context, test *config.TestDefinition) (*RuleSetLabels, error)
+}
+
+// RuleSetLabels are the source and target labels offered by a test's rulesets
+type RuleSetLabels struct {
+	Sources []string
+	Targets []string
+
+	// WorkDir where the labels were listed
+	WorkDir string
+}
+
+// Transformer is implemented by targets that can run the transform of a transform
+// test; the result's OutputDir holds the files it produced
+type Transformer interface {
+	Transform(ctx context.Context, test *config.TestDefinition) (*ExecutionResult, error)
+}
+
+// IssueLister is implemented by targets that show the results of an analysis they
+// executed aggregated per issue, e.g. the issues table of the Tackle UI, so the
+// aggregation can be checked against the expected output
+type IssueLister interface {
+	Issues(ctx context.Context, test *config.TestDefinition, result *ExecutionResult) ([]parser.Issue, error)
+}
+
+// DiagnosticLister is implemented by targets that show the incidents of an analysis
+// they executed as editor diagnostics, e.g. the VSCode extension, so their filtering
+// and rendering can be checked against the expected output
+type DiagnosticLister interface {
+	Diagnostics(ctx context.Context, test *config.TestDefinition, result *ExecutionResult) ([]parser.Diagnostic, error)
+}
+
+// FixSuggester is implemented by targets that can suggest fixes for incidents of an
+// analysis they executed, e.g. Kai
+type FixSuggester interface {
+	SuggestFix(ctx context.Context, test *config.TestDefinition, ruleset, rule string, incident konveyor.Incident) (*parser.FixSuggestion, error)
+}
+
 // ExecutionResult contains the results of executing a target
 type ExecutionResult struct {
 	// ExitCode from the process
@@ -27,6 +76,19 @@ type ExecutionResult struct {
 	// OutputFile path to the generated output.yaml
 	OutputFile string
 
+	// OutputDir is the directory of the files a transform produced
+	OutputDir string
+
+	// DependenciesFile path to the dependencies.yaml, when the target produced one
+	DependenciesFile string
+
+	// Applications are the outputs of each application of a test analyzing several;
+	// OutputFile then holds their merged output
+	Applications []ApplicationOutput
+
+	// Providers are the analysis providers that ran, nil when the target cannot tell
+	Providers []string
+
 	// WorkDir where the execution happened
 	WorkDir string
 
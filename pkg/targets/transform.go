@@ -0,0 +1,87 @@
+package targets
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/util"
+)
+
+// Transform runs kantra transform for a transform test: rules converts the input's
+// Windup XML rules into the output directory, openrewrite rewrites a copy of the
+// input application in the output directory with the test's recipe
+func (k *KantraTarget) Transform(ctx context.Context, test *config.TestDefinition) (*ExecutionResult, error) {
+	log := util.GetLogger()
+	log.Info("Executing Kantra transform", "test", test.Name, "command", test.Transform.Command)
+
+	workDir, err := PrepareWorkDir(test.GetWorkDir(), test.Name)
+	if err != nil {
+		return nil, err
+	}
+	input := test.Transform.Input
+	if !filepath.IsAbs(input) {
+		input = filepath.Join(test.GetTestDir(), input)
+	}
+	outputDir, err := filepath.Abs(filepath.Join(workDir, "output"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute output path: %w", err)
+	}
+
+	var args []string
+	switch test.Transform.Command {
+	case config.TransformRules:
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create output directory: %w", err)
+		}
+		args = []string{"transform", "rules", "--input", input, "--output", outputDir}
+	case config.TransformOpenRewrite:
+		// openrewrite rewrites its input in place, leave the test's application alone
+		if err := copyDir(input, outputDir); err != nil {
+			return nil, fmt.Errorf("failed to copy application: %w", err)
+		}
+		args = []string{"transform", "openrewrite", "--input", outputDir, "--target", test.Transform.Target, "--goal", "run"}
+	default:
+		return nil, fmt.Errorf("unknown transform command: %s", test.Transform.Command)
+	}
+
+	result, err := k.run(ctx, test, args, workDir)
+	if err != nil {
+		return nil, err
+	}
+	result.OutputDir = outputDir
+	LogResult(log, result)
+	return result, nil
+}
+
+// copyDir copies the files of a directory tree into dst
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if entry.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, info.Mode().Perm())
+	})
+}
@@ -45,5 +45,7 @@ func (v *VSCodeTarget) Execute(ctx context.Context, test *config.TestDefinition)
 	// 3. Trigger analysis command via CLI or automation
 	// 4. Wait for analysis completion
 	// 5. Extract results from workspace/output
+	// 6. Collect the diagnostics the extension shows (DiagnosticLister), from its
+	//    output channel or the LSP trace, to compare with the expected incidents
 	return nil, fmt.Errorf("vscode target not yet implemented")
 }
@@ -1,33 +1,165 @@
 package util
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"sync"
 
 	"github.com/go-logr/logr"
 )
 
-var logger logr.Logger
+// Log formats
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+var (
+	logger logr.Logger
+
+	// logOutput and logFormat are where and how logs are written
+	logOutput io.Writer = os.Stderr
+	logFormat           = LogFormatText
+	logLevel            = slog.LevelInfo
+
+	// capture receives a copy of the logs while a test runs
+	captureMu sync.RWMutex
+	capture   slog.Handler
+)
 
 // InitLogger initializes the global logger with the specified log level
 func InitLogger(verbose bool) {
-	var level slog.Level
 	if verbose {
-		level = slog.LevelDebug
+		InitLoggerWithLevel(slog.LevelDebug)
 	} else {
-		level = slog.LevelInfo
-	}
-
-	opts := &slog.HandlerOptions{
-		Level: level,
+		InitLoggerWithLevel(slog.LevelInfo)
 	}
+}
 
-	handler := slog.NewTextHandler(os.Stderr, opts)
+// InitLoggerWithLevel initializes the global logger with an explicit slog level
+func InitLoggerWithLevel(level slog.Level) {
+	logLevel = level
+	handler := &logHandler{base: newHandler(logOutput, level)}
 	slogger := slog.New(handler)
 	logger = logr.FromSlogHandler(handler)
 	slog.SetDefault(slogger)
 }
 
+// SetLogOutput sets where logs are written, a file or stderr when file is empty,
+// and their format (text or json). It applies to the next InitLogger.
+func SetLogOutput(file, format string) error {
+	switch format {
+	case "", LogFormatText:
+		format = LogFormatText
+	case LogFormatJSON:
+	default:
+		return fmt.Errorf("invalid log format %q (valid: text, json)", format)
+	}
+
+	output := io.Writer(os.Stderr)
+	if file != "" {
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		output = f
+	}
+	if f, ok := logOutput.(*os.File); ok && f != os.Stderr {
+		f.Close()
+	}
+	logOutput = output
+	logFormat = format
+	return nil
+}
+
+// CaptureLogs copies the logs written until the returned function is called, in
+// the configured format, e.g. to keep each test's logs next to its results
+func CaptureLogs() func() []byte {
+	var buf bytes.Buffer
+	handler := newHandler(&lockedWriter{w: &buf}, logLevel)
+	captureMu.Lock()
+	capture = handler
+	captureMu.Unlock()
+
+	return func() []byte {
+		captureMu.Lock()
+		defer captureMu.Unlock()
+		if capture == handler {
+			capture = nil
+		}
+		return buf.Bytes()
+	}
+}
+
+func newHandler(w io.Writer, level slog.Level) slog.Handler {
+	opts := &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redactAttr,
+	}
+	if logFormat == LogFormatJSON {
+		return slog.NewJSONHandler(w, opts)
+	}
+	return slog.NewTextHandler(w, opts)
+}
+
+// logHandler writes to the configured output, and to the capture handler while
+// logs are captured. Attributes and groups are replayed on the capture handler,
+// which may start after loggers were derived.
+type logHandler struct {
+	base slog.Handler
+	with []func(slog.Handler) slog.Handler
+}
+
+func (h *logHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.base.Enabled(ctx, level)
+}
+
+func (h *logHandler) Handle(ctx context.Context, record slog.Record) error {
+	captureMu.RLock()
+	captured := capture
+	captureMu.RUnlock()
+	if captured != nil {
+		for _, with := range h.with {
+			captured = with(captured)
+		}
+		if err := captured.Handle(ctx, record.Clone()); err != nil {
+			return err
+		}
+	}
+	return h.base.Handle(ctx, record)
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.derive(func(handler slog.Handler) slog.Handler { return handler.WithAttrs(attrs) })
+}
+
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	return h.derive(func(handler slog.Handler) slog.Handler { return handler.WithGroup(name) })
+}
+
+func (h *logHandler) derive(with func(slog.Handler) slog.Handler) slog.Handler {
+	return &logHandler{
+		base: with(h.base),
+		with: append(h.with[:len(h.with):len(h.with)], with),
+	}
+}
+
+// lockedWriter serializes writes to a buffer
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
 // GetLogger returns the global logger instance
 func GetLogger() logr.Logger {
 	if logger.GetSink() == nil {
@@ -0,0 +1,51 @@
+package util
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogOutput(t *testing.T) {
+	t.Cleanup(func() {
+		if err := SetLogOutput("", LogFormatText); err != nil {
+			t.Fatal(err)
+		}
+		InitLogger(false)
+	})
+
+	if err := SetLogOutput("", "xml"); err == nil {
+		t.Error("expected an error for an invalid log format")
+	}
+
+	logFile := filepath.Join(t.TempDir(), "koncur.log")
+	if err := SetLogOutput(logFile, LogFormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	InitLogger(false)
+	// Loggers derived before a capture starts are captured too
+	log := GetLogger().WithValues("test", "daytrader")
+
+	stop := CaptureLogs()
+	log.Info("Executing analysis")
+	captured := stop()
+	log.Info("After the test")
+
+	var record map[string]any
+	if err := json.Unmarshal(captured, &record); err != nil {
+		t.Fatalf("expected one JSON record to be captured, got %q: %v", captured, err)
+	}
+	if record["msg"] != "Executing analysis" || record["test"] != "daytrader" {
+		t.Errorf("unexpected captured record %v", record)
+	}
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 2 {
+		t.Errorf("expected both records in the log file, got %q", data)
+	}
+}
@@ -0,0 +1,81 @@
+package util
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// redacted replaces secret values in logs
+const redacted = "[REDACTED]"
+
+// minSecretLength avoids redacting trivially short values that would mangle unrelated text
+const minSecretLength = 4
+
+var (
+	secretsMu sync.RWMutex
+	secrets   []string
+)
+
+// sensitiveKeys are log attribute keys whose values are always redacted
+var sensitiveKeys = []string{"password", "token", "secret", "authorization"}
+
+// RegisterSecret records a secret value so it is redacted wherever it appears in logs
+func RegisterSecret(value string) {
+	if len(value) < minSecretLength {
+		return
+	}
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	for _, s := range secrets {
+		if s == value {
+			return
+		}
+	}
+	secrets = append(secrets, value)
+}
+
+// Redact replaces every registered secret in s
+func Redact(s string) string {
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	return s
+}
+
+// sensitiveValue matches the value of a sensitive key in YAML, JSON, headers or
+// key=value text, e.g. "password: x", "\"token\": \"x\"" or "Authorization: Bearer x".
+// Keys such as passwordFrom, which reference secrets rather than hold them, do not match.
+var sensitiveValue = regexp.MustCompile(`(?i)((?:` + strings.Join(sensitiveKeys, "|") + `)["']?\s*[:=]\s*["']?(?:(?:bearer|basic)\s+)?)[^\s"',}]+`)
+
+// RedactText replaces registered secrets and the values of sensitive keys in text
+// such as logs and configuration files
+func RedactText(s string) string {
+	return sensitiveValue.ReplaceAllString(Redact(s), "${1}"+redacted)
+}
+
+// redactAttr is a slog ReplaceAttr hook that hides sensitive attributes and registered secrets
+func redactAttr(groups []string, a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return slog.String(a.Key, redacted)
+		}
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, Redact(a.Value.String()))
+	case slog.KindAny:
+		// Structs such as target configs are rendered with %+v, which would expose secrets
+		formatted := fmt.Sprintf("%+v", a.Value.Any())
+		if redactedValue := Redact(formatted); redactedValue != formatted {
+			return slog.String(a.Key, redactedValue)
+		}
+	}
+	return a
+}
@@ -0,0 +1,57 @@
+package util
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRedactingLogger(t *testing.T) {
+	RegisterSecret("hunter2-token")
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: redactAttr}))
+
+	type hubConfig struct {
+		URL   string
+		Token string
+	}
+	logger.Info("login with hunter2-token",
+		"password", "plain",
+		"config", hubConfig{URL: "http://hub", Token: "hunter2-token"},
+		"url", "http://hub",
+	)
+
+	out := buf.String()
+	if strings.Contains(out, "hunter2-token") || strings.Contains(out, "plain") {
+		t.Errorf("Expected secrets to be redacted, got: %s", out)
+	}
+	if !strings.Contains(out, "url=http://hub") {
+		t.Errorf("Expected non-secret attributes to be kept, got: %s", out)
+	}
+}
+
+func TestRedactText(t *testing.T) {
+	RegisterSecret("s3cr3t-value")
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "registered secret", text: "cloning with s3cr3t-value", want: "cloning with [REDACTED]"},
+		{name: "yaml", text: "username: admin\npassword: hunter2\n", want: "username: admin\npassword: [REDACTED]\n"},
+		{name: "json", text: `{"token": "abc123", "url": "http://hub"}`, want: `{"token": "[REDACTED]", "url": "http://hub"}`},
+		{name: "header", text: "Authorization: Bearer abc.def", want: "Authorization: Bearer [REDACTED]"},
+		{name: "key=value", text: "client_secret=xyz other=1", want: "client_secret=[REDACTED] other=1"},
+		{name: "secret reference", text: "passwordFrom: env:HUB_PASSWORD", want: "passwordFrom: env:HUB_PASSWORD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RedactText(tt.text); got != tt.want {
+				t.Errorf("RedactText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/config"
+)
+
+// compareAbsent reports rulesets, rules and tags in the actual output that must not be there
+func compareAbsent(absent *config.AbsentExpectations, actual []konveyor.RuleSet) []ValidationError {
+	if absent == nil {
+		return nil
+	}
+	var errors []ValidationError
+	for _, rs := range actual {
+		if slices.Contains(absent.RuleSets, rs.Name) {
+			errors = append(errors, ValidationError{
+				Path:     fmt.Sprintf("ruleset/%s", rs.Name),
+				Code:     CodeAbsentRuleSetFound,
+				Message:  fmt.Sprintf("Found ruleset expected to be absent: %s", rs.Name),
+				Actual:   rs.Name,
+				Location: &Location{RuleSet: rs.Name},
+			})
+		}
+		for idx, tag := range rs.Tags {
+			if slices.Contains(absent.Tags, tag) {
+				errors = append(errors, ValidationError{
+					Path:     fmt.Sprintf("%s/tags/%s", rs.Name, tag),
+					Code:     CodeAbsentTagFound,
+					Message:  fmt.Sprintf("Found tag expected to be absent: %s", tag),
+					Actual:   tag,
+					Location: &Location{RuleSet: rs.Name, Section: "tags", Index: intPtr(idx)},
+				})
+			}
+		}
+		for _, section := range []struct {
+			name       string
+			violations map[string]konveyor.Violation
+		}{{"violations", rs.Violations}, {"insights", rs.Insights}} {
+			for _, ruleID := range absent.Rules {
+				if v, ok := section.violations[ruleID]; ok {
+					errors = append(errors, ValidationError{
+						Path:     fmt.Sprintf("%s/%s/%s", rs.Name, section.name, ruleID),
+						Code:     CodeAbsentRuleFound,
+						Message:  fmt.Sprintf("Found rule expected to be absent: %s (%d incidents)", ruleID, len(v.Incidents)),
+						Actual:   v,
+						Location: &Location{RuleSet: rs.Name, Section: section.name, RuleID: ruleID},
+					})
+				}
+			}
+		}
+	}
+	return errors
+}
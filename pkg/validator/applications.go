@@ -0,0 +1,39 @@
+package validator
+
+import "fmt"
+
+// MergeApplicationResults combines the validation results of the applications of a
+// test analyzing several, named by names in the same order. Errors, warnings and
+// diffs are prefixed with their application; the test passes if every application does.
+func MergeApplicationResults(names []string, results []*ValidationResult) *ValidationResult {
+	merged := &ValidationResult{Passed: true, Errors: []ValidationError{}}
+	for i, result := range results {
+		name := names[i]
+		merged.Passed = merged.Passed && result.Passed
+		merged.Errors = append(merged.Errors, inApplication(name, result.Errors)...)
+		merged.Warnings = append(merged.Warnings, inApplication(name, result.Warnings)...)
+		for _, d := range result.Diffs {
+			d.Path = fmt.Sprintf("application/%s/%s", name, d.Path)
+			merged.Diffs = append(merged.Diffs, d)
+		}
+		merged.AllowedMismatches += result.AllowedMismatches
+	}
+	return merged
+}
+
+// inApplication returns errors with their path, message and location in an application
+func inApplication(name string, errs []ValidationError) []ValidationError {
+	prefixed := make([]ValidationError, 0, len(errs))
+	for _, e := range errs {
+		e.Path = fmt.Sprintf("application/%s/%s", name, e.Path)
+		e.Message = fmt.Sprintf("[%s] %s", name, e.Message)
+		location := Location{}
+		if e.Location != nil {
+			location = *e.Location
+		}
+		location.Application = name
+		e.Location = &location
+		prefixed = append(prefixed, e)
+	}
+	return prefixed
+}
@@ -3,9 +3,15 @@ package validator
 import (
 	"fmt"
 	"maps"
+	"path"
+	"reflect"
+	"regexp"
 	"slices"
+	"strings"
 
 	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/parser"
 	"github.com/konveyor/test-harness/pkg/util"
 )
 
@@ -16,6 +22,7 @@ const (
 	LINE_NUMBER
 	CODE_SNIP
 	MESSAGE
+	VARIABLES
 	NONE
 )
 
@@ -29,31 +36,140 @@ func (i incidentField) String() string {
 		return "code snip"
 	case MESSAGE:
 		return "message"
+	case VARIABLES:
+		return "variables"
+	}
+	return ""
+}
+
+// jsonName is the incident field name used in output files and locations
+func (i incidentField) jsonName() string {
+	switch i {
+	case URI:
+		return "uri"
+	case LINE_NUMBER:
+		return "lineNumber"
+	case CODE_SNIP:
+		return "codeSnip"
+	case MESSAGE:
+		return "message"
+	case VARIABLES:
+		return "variables"
 	}
 	return ""
 }
 
 type baseValidator struct {
 	testDir string
+	config  config.ValidationConfig
+	// messageIgnore holds the compiled config.MessageIgnorePatterns
+	messageIgnore []*regexp.Regexp
+	// variablePatterns holds the compiled config.VariableComparison patterns
+	variablePatterns map[string]*regexp.Regexp
+	// uris normalizes incident URIs before they are compared
+	uris parser.URINormalizer
+}
+
+// Field tolerance defaults shared by all targets; a test or target config can override them.
+// Code snips and variables depend on provider versions and context lines, so they are
+// ignored unless explicitly enabled.
+func (b *baseValidator) ignoreLineNumbers() bool {
+	return boolOr(b.config.IgnoreLineNumbers, false)
+}
+
+func (b *baseValidator) ignoreCodeSnips() bool {
+	return boolOr(b.config.IgnoreCodeSnips, true)
+}
+
+func (b *baseValidator) ignoreVariables() bool {
+	return boolOr(b.config.IgnoreVariables, true)
+}
+
+func (b *baseValidator) ignoreLinks() bool {
+	return boolOr(b.config.IgnoreLinks, false)
+}
+
+func (b *baseValidator) skipTags() bool {
+	return boolOr(b.config.SkipTags, false)
+}
+
+func boolOr(v *bool, def bool) bool {
+	if v == nil {
+		return def
+	}
+	return *v
+}
+
+// messagesMatch compares incident messages after stripping the configured ignore patterns
+func (b *baseValidator) messagesMatch(expected, actual string) bool {
+	if expected == actual {
+		return true
+	}
+	for _, re := range b.messageIgnore {
+		expected = re.ReplaceAllString(expected, "")
+		actual = re.ReplaceAllString(actual, "")
+	}
+	return expected == actual
+}
+
+// codeSnipsMatch compares incident code snips when enabled; an empty expected snip matches anything
+func (b *baseValidator) codeSnipsMatch(expected, actual konveyor.Incident) bool {
+	if b.ignoreCodeSnips() || strings.TrimSpace(expected.CodeSnip) == "" {
+		return true
+	}
+	return codeSnipsEqual(b.config.CodeSnips,
+		expected.CodeSnip, lineNumberOrZero(expected.LineNumber),
+		actual.CodeSnip, lineNumberOrZero(actual.LineNumber))
+}
+
+// variablesMatch compares incident variables when enabled; no expected variables match anything.
+// When keys or patterns are configured only those variables are compared.
+func (b *baseValidator) variablesMatch(expected, actual map[string]interface{}) bool {
+	if b.ignoreVariables() || len(expected) == 0 {
+		return true
+	}
+	if b.config.Variables == nil || (len(b.config.Variables.Keys) == 0 && len(b.variablePatterns) == 0) {
+		return reflect.DeepEqual(expected, actual)
+	}
+	for _, key := range b.config.Variables.Keys {
+		if _, ok := b.variablePatterns[key]; !ok && !reflect.DeepEqual(expected[key], actual[key]) {
+			return false
+		}
+	}
+	for key, re := range b.variablePatterns {
+		value, ok := actual[key]
+		if !ok || !re.MatchString(fmt.Sprint(value)) {
+			return false
+		}
+	}
+	return true
 }
 
 func (b *baseValidator) compareTags(expected, actual []string) []ValidationError {
+	if b.skipTags() {
+		return nil
+	}
 	var errors []ValidationError
-	for _, exp := range expected {
-		if !findExpectedString(exp, actual) {
+	expectedSet, actualSet := stringSet(expected), stringSet(actual)
+	for idx, exp := range expected {
+		if !actualSet[exp] {
 			errors = append(errors, ValidationError{
 				Path:     fmt.Sprintf("/%s", exp),
+				Code:     CodeMissingTag,
 				Message:  fmt.Sprintf("Did not find expected tag: %s", exp),
 				Expected: exp,
+				Location: &Location{Index: intPtr(idx)},
 			})
 		}
 	}
-	for _, act := range actual {
-		if !findExpectedString(act, expected) {
+	for idx, act := range actual {
+		if !expectedSet[act] {
 			errors = append(errors, ValidationError{
-				Path:    fmt.Sprintf("/%s", act),
-				Message: fmt.Sprintf("Unexpected tag found: %s", act),
-				Actual:  act,
+				Path:     fmt.Sprintf("/%s", act),
+				Code:     CodeUnexpectedTag,
+				Message:  fmt.Sprintf("Unexpected tag found: %s", act),
+				Actual:   act,
+				Location: &Location{Index: intPtr(idx)},
 			})
 		}
 	}
@@ -63,13 +179,20 @@ func (b *baseValidator) compareTags(expected, actual []string) []ValidationError
 
 func (b *baseValidator) compareViolations(expected, actual map[string]konveyor.Violation) []ValidationError {
 	var errors []ValidationError
+	renamed := b.renamedRules(expected, actual)
 	for k, exp := range expected {
 		act, exists := actual[k]
+		if newID, ok := renamed[k]; ok {
+			act, exists = actual[newID], true
+			errors = append(errors, renamedRuleWarning(k, newID))
+		}
 		if !exists {
 			errors = append(errors, ValidationError{
 				Path:     fmt.Sprintf("/%s", k),
+				Code:     CodeMissingViolation,
 				Message:  fmt.Sprintf("Did not find expected violation: %s", k),
 				Expected: exp,
+				Location: &Location{RuleID: k},
 			})
 			continue
 		}
@@ -77,15 +200,18 @@ func (b *baseValidator) compareViolations(expected, actual map[string]konveyor.V
 		detailErrors := b.compareViolationDetails(exp, act)
 		for i := range detailErrors {
 			detailErrors[i].Path = fmt.Sprintf("/%s%s", k, detailErrors[i].Path)
+			detailErrors[i].locate().RuleID = k
 		}
 		errors = append(errors, detailErrors...)
 	}
 	for k := range actual {
-		if _, exists := expected[k]; !exists {
+		if _, exists := expected[k]; !exists && !renamedTo(renamed, k) {
 			errors = append(errors, ValidationError{
-				Path:    fmt.Sprintf("/%s", k),
-				Message: fmt.Sprintf("Unexpected violation found: %s", k),
-				Actual:  actual[k],
+				Path:     fmt.Sprintf("/%s", k),
+				Code:     CodeUnexpectedViolation,
+				Message:  fmt.Sprintf("Unexpected violation found: %s", k),
+				Actual:   actual[k],
+				Location: &Location{RuleID: k},
 			})
 		}
 	}
@@ -98,42 +224,37 @@ func (b *baseValidator) compareViolationDetails(expected, actual konveyor.Violat
 
 	if actual.Category != nil && expected.Category != nil && *expected.Category != *actual.Category {
 		errors = append(errors, ValidationError{
-			Message: fmt.Sprintf("Did not find expected category: %v", expected.Category),
+			Code:     CodeCategoryMismatch,
+			Message:  fmt.Sprintf("Did not find expected category: %v", expected.Category),
+			Expected: *expected.Category,
+			Actual:   *actual.Category,
+			Location: &Location{Field: "category"},
 		})
 	}
 	if (expected.Effort != nil && actual.Effort != nil) && (*expected.Effort != *actual.Effort) {
 		errors = append(errors, ValidationError{
-			Message: fmt.Sprintf("Did not find expected effort: %v", expected.Effort),
+			Code:     CodeEffortMismatch,
+			Message:  fmt.Sprintf("Did not find expected effort: %v", expected.Effort),
+			Expected: *expected.Effort,
+			Actual:   *actual.Effort,
+			Location: &Location{Field: "effort"},
 		})
 	}
 	// Handle Links
-	for _, l := range expected.Links {
-		found := false
-		for _, al := range actual.Links {
-			if l.Title == al.Title && l.URL == al.URL {
-				found = true
-				break
-			}
-		}
-		if !found {
-			errors = append(errors, ValidationError{
-				Message: fmt.Sprintf("Did not find expected link: %v", l),
-			})
-		}
-	}
+	errors = append(errors, b.compareLinks(expected.Links, actual.Links)...)
 	// Handle Labels
-	for _, l := range expected.Labels {
-		if !findExpectedString(l, actual.Labels) {
-			errors = append(errors, ValidationError{
-				Message: fmt.Sprintf("Did not find expected label: %v", l),
-			})
-		}
-	}
-	// Handle Incidents - collect all missing incidents and report as one error
-	for _, i := range expected.Incidents {
+	errors = append(errors, compareLabels(expected.Labels, actual.Labels)...)
+	errors = append(errors, b.compareStrictDetails(expected, actual)...)
+	// Handle Incidents - collect all missing incidents and report as one error.
+	// Incidents only match on the same URI, so candidates are looked up by URI.
+	actualByURI := b.incidentsByURI(actual.Incidents)
+	for idx, i := range expected.Incidents {
 		found := false
 		faildFields := map[incidentField]*struct{}{}
-		for _, ai := range actual.Incidents {
+		if len(actual.Incidents) > len(actualByURI[b.uris.NormalizePath(i.URI)]) {
+			faildFields[URI] = nil
+		}
+		for _, ai := range actualByURI[b.uris.NormalizePath(i.URI)] {
 			if ok, fieldFaild := b.incidentsMatch(i, ai); ok {
 				found = true
 				break
@@ -142,17 +263,23 @@ func (b *baseValidator) compareViolationDetails(expected, actual konveyor.Violat
 			}
 		}
 		if !found {
-			fieldError := slices.Sorted(maps.Keys(faildFields))[len(faildFields)-1]
 			errors = append(errors, ValidationError{
-				Message: fmt.Sprintf("Did not find expected incident:  %s:%d failed to match on: %s", i.URI, lineNumberOrZero(i.LineNumber), fieldError.String()),
+				Code:     CodeMissingIncident,
+				Message:  fmt.Sprintf("Did not find expected incident:  %s:%d%s", i.URI, lineNumberOrZero(i.LineNumber), failedFieldSuffix(faildFields)),
+				Expected: i,
+				Location: &Location{Incident: intPtr(idx), Field: furthestField(faildFields).jsonName()},
 			})
 		}
 	}
 
-	for _, ai := range actual.Incidents {
+	expectedByURI := b.incidentsByURI(expected.Incidents)
+	for idx, ai := range actual.Incidents {
 		found := false
 		faildFields := map[incidentField]*struct{}{}
-		for _, i := range expected.Incidents {
+		if len(expected.Incidents) > len(expectedByURI[b.uris.NormalizePath(ai.URI)]) {
+			faildFields[URI] = nil
+		}
+		for _, i := range expectedByURI[b.uris.NormalizePath(ai.URI)] {
 			if ok, fieldFaild := b.incidentsMatch(i, ai); ok {
 				found = true
 				break
@@ -161,9 +288,11 @@ func (b *baseValidator) compareViolationDetails(expected, actual konveyor.Violat
 			}
 		}
 		if !found {
-			fieldError := slices.Sorted(maps.Keys(faildFields))[len(faildFields)-1]
 			errors = append(errors, ValidationError{
-				Message: fmt.Sprintf("Unexpected incident found: %s:%d failed to match on: %s", ai.URI, lineNumberOrZero(ai.LineNumber), fieldError),
+				Code:     CodeUnexpectedIncident,
+				Message:  fmt.Sprintf("Unexpected incident found: %s:%d%s", ai.URI, lineNumberOrZero(ai.LineNumber), failedFieldSuffix(faildFields)),
+				Actual:   ai,
+				Location: &Location{Incident: intPtr(idx), Field: furthestField(faildFields).jsonName()},
 			})
 		}
 	}
@@ -171,6 +300,98 @@ func (b *baseValidator) compareViolationDetails(expected, actual konveyor.Violat
 	return errors
 }
 
+// incidentsByURI indexes incidents by their normalized URI
+func (b *baseValidator) incidentsByURI(incidents []konveyor.Incident) map[string][]konveyor.Incident {
+	byURI := make(map[string][]konveyor.Incident, len(incidents))
+	for _, i := range incidents {
+		path := b.uris.NormalizePath(i.URI)
+		byURI[path] = append(byURI[path], i)
+	}
+	return byURI
+}
+
+// failedFieldSuffix names the furthest incident field that failed to match.
+// There is none when the other side has no incidents at all.
+func failedFieldSuffix(failedFields map[incidentField]*struct{}) string {
+	if len(failedFields) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" failed to match on: %s", furthestField(failedFields))
+}
+
+// furthestField returns the last incident field that failed to match, or NONE
+func furthestField(failedFields map[incidentField]*struct{}) incidentField {
+	if len(failedFields) == 0 {
+		return NONE
+	}
+	return slices.Sorted(maps.Keys(failedFields))[len(failedFields)-1]
+}
+
+// compareLabels reports expected labels missing from the actual violation
+func compareLabels(expected, actual []string) []ValidationError {
+	var errors []ValidationError
+	for idx, l := range expected {
+		if !findExpectedString(l, actual) {
+			errors = append(errors, ValidationError{
+				Code:     CodeMissingLabel,
+				Message:  fmt.Sprintf("Did not find expected label: %v", l),
+				Expected: l,
+				Location: &Location{Field: "labels", Index: intPtr(idx)},
+			})
+		}
+	}
+	return errors
+}
+
+// compareStrictDetails reports violation details present only in the actual output.
+// These are tolerated unless strict validation is enabled.
+func (b *baseValidator) compareStrictDetails(expected, actual konveyor.Violation) []ValidationError {
+	if !b.config.Strict {
+		return nil
+	}
+	var errors []ValidationError
+	if expected.Category == nil && actual.Category != nil {
+		errors = append(errors, ValidationError{
+			Code:     CodeUnexpectedCategory,
+			Message:  fmt.Sprintf("Unexpected category found: %v", *actual.Category),
+			Actual:   *actual.Category,
+			Location: &Location{Field: "category"},
+		})
+	}
+	if expected.Effort == nil && actual.Effort != nil {
+		errors = append(errors, ValidationError{
+			Code:     CodeUnexpectedEffort,
+			Message:  fmt.Sprintf("Unexpected effort found: %d", *actual.Effort),
+			Actual:   *actual.Effort,
+			Location: &Location{Field: "effort"},
+		})
+	}
+	for idx, l := range actual.Labels {
+		if !findExpectedString(l, expected.Labels) {
+			errors = append(errors, ValidationError{
+				Code:     CodeUnexpectedLabel,
+				Message:  fmt.Sprintf("Unexpected label found: %v", l),
+				Actual:   l,
+				Location: &Location{Field: "labels", Index: intPtr(idx)},
+			})
+		}
+	}
+	return errors
+}
+
+// compareLinks reports expected links missing from the actual violation and,
+// in strict mode, unexpected ones
+func (b *baseValidator) compareLinks(expected, actual []konveyor.Link) []ValidationError {
+	if b.ignoreLinks() {
+		return nil
+	}
+	comparer := linkComparer{}
+	if b.config.Links != nil {
+		comparer.config = *b.config.Links
+	}
+	return comparer.compare(expected, actual, b.config.Strict)
+}
+
 func lineNumberOrZero(ln *int) int {
 	if ln != nil {
 		return *ln
@@ -179,56 +400,63 @@ func lineNumberOrZero(ln *int) int {
 }
 
 func (b *baseValidator) incidentsMatch(expected, actual konveyor.Incident) (bool, incidentField) {
-	if string(expected.URI) != string(actual.URI) {
+	if b.uris.NormalizePath(expected.URI) != b.uris.NormalizePath(actual.URI) {
 		return false, URI
 	}
 	expectedLN := lineNumberOrZero(expected.LineNumber)
 	actualLN := lineNumberOrZero(actual.LineNumber)
-	if expectedLN != actualLN {
+	if !b.ignoreLineNumbers() && expectedLN != actualLN {
 		return false, LINE_NUMBER
 	}
 	logger := util.GetLogger()
-	if expected.Message != actual.Message {
+	if !b.messagesMatch(expected.Message, actual.Message) {
 		logger.Info("messages don't match", "expected", expected.Message, "actual", actual.Message)
 		return false, MESSAGE
 	}
-	// Here three is a problem where the variables may not be the exact same.
-	// To compare we would have to know what is being returned and parse it.
-	// Because of this, if the uri line number and code are the same
-	// Then we can reasonably be sure the incident is the same.
-
-	//	if strings.TrimSpace(expected.CodeSnip) != "" && strings.TrimSpace(expected.CodeSnip) != strings.TrimSpace(actual.CodeSnip) {
-	//		logger.Info("code snip's don't match", "expected", expected.CodeSnip, "actual", actual.CodeSnip)
-	//		return false, CODE_SNIP
-	//	}
-
-	//	if len(expected.Variables) > 0 && !reflect.DeepEqual(expected.Variables, actual.Variables) {
-	//		log.Info("here", "vars", actual.Variables)
-	//		return false
-	//		return false,
-	//	}
+	// Variables and code snips may legitimately differ between runs, so they are
+	// only compared when a test or target config enables them.
+	if !b.codeSnipsMatch(expected, actual) {
+		logger.Info("code snip's don't match", "expected", expected.CodeSnip, "actual", actual.CodeSnip)
+		return false, CODE_SNIP
+	}
+	if !b.variablesMatch(expected.Variables, actual.Variables) {
+		return false, VARIABLES
+	}
 
 	return true, NONE
 }
 
 func (b *baseValidator) compareErrors(expected, actual map[string]string) []ValidationError {
+	opts := config.ErrorComparison{}
+	if b.config.Errors != nil {
+		opts = *b.config.Errors
+	}
+	if opts.PresenceOnly {
+		return compareErrorPresence(expected, actual)
+	}
+
 	var errors []ValidationError
 	for k, exp := range expected {
 		act, exists := actual[k]
-		if !exists || exp != act {
+		if !exists || !errorMessageMatches(opts.Match, exp, act) {
 			errors = append(errors, ValidationError{
 				Path:     fmt.Sprintf("/%s", k),
+				Code:     CodeMissingError,
 				Message:  fmt.Sprintf("Did not find expected error: %s", exp),
 				Expected: exp,
+				Actual:   act,
+				Location: &Location{RuleID: k},
 			})
 		}
 	}
 	for k := range actual {
 		if _, exists := expected[k]; !exists {
 			errors = append(errors, ValidationError{
-				Path:    fmt.Sprintf("/%s", k),
-				Message: fmt.Sprintf("Unexpected error found: %s", k),
-				Actual:  actual[k],
+				Path:     fmt.Sprintf("/%s", k),
+				Code:     CodeUnexpectedError,
+				Message:  fmt.Sprintf("Unexpected error found: %s", k),
+				Actual:   actual[k],
+				Location: &Location{RuleID: k},
 			})
 		}
 	}
@@ -236,50 +464,121 @@ func (b *baseValidator) compareErrors(expected, actual map[string]string) []Vali
 	return errors
 }
 
-func (b *baseValidator) compareUnmatched(expected, actual []string) []ValidationError {
-	var errors []ValidationError
-	for _, exp := range expected {
-		if !findExpectedString(exp, actual) {
-			errors = append(errors, ValidationError{
-				Path:     fmt.Sprintf("/%s", exp),
-				Message:  fmt.Sprintf("Did not find expected unmatched rule: %s", exp),
-				Expected: exp,
-			})
+// errorMessageMatches compares an expected ruleset error message with the actual one
+func errorMessageMatches(mode, expected, actual string) bool {
+	switch mode {
+	case config.ErrorMatchSubstring:
+		return strings.Contains(actual, expected)
+	case config.ErrorMatchRegex:
+		re, err := regexp.Compile(expected)
+		if err != nil {
+			util.GetLogger().Info("Invalid expected error pattern", "pattern", expected, "error", err.Error())
+			return false
 		}
+		return re.MatchString(actual)
 	}
-	for _, act := range actual {
-		if !findExpectedString(act, expected) {
-			errors = append(errors, ValidationError{
-				Path:    fmt.Sprintf("/%s", act),
-				Message: fmt.Sprintf("Unexpected unmatched rule found: %s", act),
-				Actual:  act,
-			})
-		}
+	return expected == actual
+}
+
+// compareErrorPresence only checks whether a ruleset has errors at all
+func compareErrorPresence(expected, actual map[string]string) []ValidationError {
+	switch {
+	case len(expected) > 0 && len(actual) == 0:
+		return []ValidationError{{
+			Code:     CodeMissingError,
+			Message:  fmt.Sprintf("Expected ruleset errors (%d), found none", len(expected)),
+			Expected: expected,
+		}}
+	case len(expected) == 0 && len(actual) > 0:
+		return []ValidationError{{
+			Code:    CodeUnexpectedError,
+			Message: fmt.Sprintf("Expected no ruleset errors, found %d", len(actual)),
+			Actual:  actual,
+		}}
 	}
+	return nil
+}
 
-	return errors
+func (b *baseValidator) compareUnmatched(expected, actual []string) []ValidationError {
+	return compareRuleList("unmatched", expected, actual, b.config.Unmatched)
 }
 
 func (b *baseValidator) compareSkipped(expected, actual []string) []ValidationError {
+	return compareRuleList("skipped", expected, actual, b.config.Skipped)
+}
+
+// compareRuleList compares lists of rule IDs. Expected entries may be glob patterns,
+// and with CountOnly only the number of entries is compared.
+func compareRuleList(kind string, expected, actual []string, opts *config.RuleListComparison) []ValidationError {
 	var errors []ValidationError
+	if opts != nil && opts.Ignore {
+		return nil
+	}
+	if opts != nil && opts.CountOnly {
+		if !opts.CountTolerance.Allows(len(expected), len(actual)) {
+			errors = append(errors, ValidationError{
+				Code:     CodeRuleCountMismatch,
+				Message:  fmt.Sprintf("Expected %d %s rules, found %d", len(expected), kind, len(actual)),
+				Expected: len(expected),
+				Actual:   len(actual),
+			})
+		}
+		return errors
+	}
+	expectedSet, actualSet := stringSet(expected), stringSet(actual)
+	var patterns []string
 	for _, exp := range expected {
-		if !findExpectedString(exp, actual) {
+		if isGlob(exp) {
+			patterns = append(patterns, exp)
+		}
+	}
+	for idx, exp := range expected {
+		if !actualSet[exp] && !(isGlob(exp) && slices.ContainsFunc(actual, func(act string) bool { return ruleIDMatches(exp, act) })) {
 			errors = append(errors, ValidationError{
 				Path:     fmt.Sprintf("/%s", exp),
-				Message:  fmt.Sprintf("Did not find expected skipped rule: %s", exp),
+				Code:     CodeMissingRule,
+				Message:  fmt.Sprintf("Did not find expected %s rule: %s", kind, exp),
 				Expected: exp,
+				Location: &Location{Index: intPtr(idx)},
 			})
 		}
 	}
-	for _, act := range actual {
-		if !findExpectedString(act, expected) {
+	for idx, act := range actual {
+		if !expectedSet[act] && !slices.ContainsFunc(patterns, func(exp string) bool { return ruleIDMatches(exp, act) }) {
 			errors = append(errors, ValidationError{
-				Path:    fmt.Sprintf("/%s", act),
-				Message: fmt.Sprintf("Unexpected skipped rule found: %s", act),
-				Actual:  act,
+				Path:     fmt.Sprintf("/%s", act),
+				Code:     CodeUnexpectedRule,
+				Message:  fmt.Sprintf("Unexpected %s rule found: %s", kind, act),
+				Actual:   act,
+				Location: &Location{Index: intPtr(idx)},
 			})
 		}
 	}
 
 	return errors
 }
+
+// ruleIDMatches reports whether a rule ID matches an expected entry, which may be a glob pattern
+func ruleIDMatches(pattern, ruleID string) bool {
+	if pattern == ruleID {
+		return true
+	}
+	if !isGlob(pattern) {
+		return false
+	}
+	matched, err := path.Match(pattern, ruleID)
+	return err == nil && matched
+}
+
+func isGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// stringSet indexes strings for constant time membership checks
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
@@ -0,0 +1,91 @@
+package validator
+
+// ErrorCode is a stable, machine-readable kind of validation error that
+// dashboards can aggregate across runs
+type ErrorCode string
+
+const (
+	CodeMissingRuleSet        ErrorCode = "MISSING_RULESET"
+	CodeUnexpectedRuleSet     ErrorCode = "UNEXPECTED_RULESET"
+	CodeMissingTag            ErrorCode = "MISSING_TAG"
+	CodeUnexpectedTag         ErrorCode = "UNEXPECTED_TAG"
+	CodeMissingViolation      ErrorCode = "MISSING_VIOLATION"
+	CodeUnexpectedViolation   ErrorCode = "UNEXPECTED_VIOLATION"
+	CodeCategoryMismatch      ErrorCode = "CATEGORY_MISMATCH"
+	CodeUnexpectedCategory    ErrorCode = "UNEXPECTED_CATEGORY"
+	CodeEffortMismatch        ErrorCode = "EFFORT_MISMATCH"
+	CodeUnexpectedEffort      ErrorCode = "UNEXPECTED_EFFORT"
+	CodeMissingLabel          ErrorCode = "MISSING_LABEL"
+	CodeUnexpectedLabel       ErrorCode = "UNEXPECTED_LABEL"
+	CodeMissingLink           ErrorCode = "MISSING_LINK"
+	CodeUnexpectedLink        ErrorCode = "UNEXPECTED_LINK"
+	CodeMissingIncident       ErrorCode = "MISSING_INCIDENT"
+	CodeUnexpectedIncident    ErrorCode = "UNEXPECTED_INCIDENT"
+	CodeIncidentCountMismatch ErrorCode = "INCIDENT_COUNT_MISMATCH"
+	CodeMissingError          ErrorCode = "MISSING_ERROR"
+	CodeUnexpectedError       ErrorCode = "UNEXPECTED_ERROR"
+	CodeRuleCountMismatch     ErrorCode = "RULE_COUNT_MISMATCH"
+	CodeMissingRule           ErrorCode = "MISSING_RULE"
+	CodeUnexpectedRule        ErrorCode = "UNEXPECTED_RULE"
+	CodeAbsentRuleSetFound    ErrorCode = "ABSENT_RULESET_FOUND"
+	CodeAbsentTagFound        ErrorCode = "ABSENT_TAG_FOUND"
+	CodeAbsentRuleFound       ErrorCode = "ABSENT_RULE_FOUND"
+	CodeTotalEffortMismatch   ErrorCode = "TOTAL_EFFORT_MISMATCH"
+	CodeAddedViolation        ErrorCode = "ADDED_VIOLATION"
+	CodeRemovedViolation      ErrorCode = "REMOVED_VIOLATION"
+	CodeAddedInsight          ErrorCode = "ADDED_INSIGHT"
+	CodeRemovedInsight        ErrorCode = "REMOVED_INSIGHT"
+	CodeExternalValidator     ErrorCode = "EXTERNAL_VALIDATOR"
+	// CodeRenamedRule is reported when an expected rule only matched a differently
+	// named actual rule by description and category
+	CodeRenamedRule ErrorCode = "RENAMED_RULE"
+	// CodeBaselineMismatch is reported when the expected output was generated by a
+	// different target or tool version
+	CodeBaselineMismatch ErrorCode = "BASELINE_MISMATCH"
+	// CodeDurationExceeded is reported when the analysis took longer than the
+	// test's expect.maxDuration
+	CodeDurationExceeded ErrorCode = "DURATION_EXCEEDED"
+	// CodeIncidentLimitExceeded and CodeCodeSnipLimitExceeded are reported when a
+	// rule has more incidents, or code snips, than the analysis' limit
+	CodeIncidentLimitExceeded ErrorCode = "INCIDENT_LIMIT_EXCEEDED"
+	CodeCodeSnipLimitExceeded ErrorCode = "CODE_SNIP_LIMIT_EXCEEDED"
+	// CodeMissingFile, CodeUnexpectedFile and CodeFileMismatch compare the files a
+	// transform test produced with the expected ones
+	CodeMissingFile    ErrorCode = "MISSING_FILE"
+	CodeUnexpectedFile ErrorCode = "UNEXPECTED_FILE"
+	CodeFileMismatch   ErrorCode = "FILE_MISMATCH"
+	// CodeProviderNotRun and CodeUnexpectedProvider compare the analysis providers
+	// that ran with a test's expect.providers
+	CodeProviderNotRun     ErrorCode = "PROVIDER_NOT_RUN"
+	CodeUnexpectedProvider ErrorCode = "UNEXPECTED_PROVIDER"
+	// CodeMissingIssue, CodeUnexpectedIssue and CodeIssueMismatch compare the issues
+	// a target shows, e.g. in the Tackle UI's issues table, with the expected output
+	CodeMissingIssue    ErrorCode = "MISSING_ISSUE"
+	CodeUnexpectedIssue ErrorCode = "UNEXPECTED_ISSUE"
+	CodeIssueMismatch   ErrorCode = "ISSUE_MISMATCH"
+	// CodeFixIncidentNotFound, CodeEmptyFix, CodeFixFileMismatch and
+	// CodeFixNotCompiling check the fixes suggested for a test's fixes.incidents
+	CodeFixIncidentNotFound ErrorCode = "FIX_INCIDENT_NOT_FOUND"
+	CodeEmptyFix            ErrorCode = "EMPTY_FIX"
+	CodeFixFileMismatch     ErrorCode = "FIX_FILE_MISMATCH"
+	CodeFixNotCompiling     ErrorCode = "FIX_NOT_COMPILING"
+	// CodeMissingDiagnostic, CodeUnexpectedDiagnostic and CodeDiagnosticMismatch
+	// compare the diagnostics an editor shows with the expected incidents
+	CodeMissingDiagnostic    ErrorCode = "MISSING_DIAGNOSTIC"
+	CodeUnexpectedDiagnostic ErrorCode = "UNEXPECTED_DIAGNOSTIC"
+	CodeDiagnosticMismatch   ErrorCode = "DIAGNOSTIC_MISMATCH"
+	// CodeProvidersUnknown is reported when the target cannot tell which providers ran
+	CodeProvidersUnknown ErrorCode = "PROVIDERS_UNKNOWN"
+)
+
+// warningCodes are reported as warnings, they don't fail a test
+var warningCodes = map[ErrorCode]bool{
+	CodeRenamedRule:      true,
+	CodeBaselineMismatch: true,
+	CodeProvidersUnknown: true,
+}
+
+// IsWarning reports whether errors of this code are warnings
+func (c ErrorCode) IsWarning() bool {
+	return warningCodes[c]
+}
@@ -0,0 +1,56 @@
+package validator
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/konveyor/test-harness/pkg/config"
+)
+
+// codeSnipLine matches a line of a code snip: the source line number followed by the code
+var codeSnipLine = regexp.MustCompile(`^\s*(\d+)\s+(.*)$`)
+
+// normalizeWhitespace collapses tabs and runs of spaces and drops blank lines,
+// so snips rendered with different indentation compare equal
+func normalizeWhitespace(snip string) string {
+	var lines []string
+	for _, line := range strings.Split(snip, "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			lines = append(lines, strings.Join(fields, " "))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// flaggedLine returns the code of the snip line with the given line number
+func flaggedLine(snip string, lineNumber int) (string, bool) {
+	for _, line := range strings.Split(snip, "\n") {
+		m := codeSnipLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if n, err := strconv.Atoi(m[1]); err == nil && n == lineNumber {
+			return normalizeWhitespace(m[2]), true
+		}
+	}
+	return "", false
+}
+
+// codeSnipsEqual compares two snips in the given mode. In flaggedLine mode only the
+// incident's own line is compared, falling back to the whole snip when a snip
+// does not contain it.
+func codeSnipsEqual(mode string, expected string, expectedLine int, actual string, actualLine int) bool {
+	switch mode {
+	case config.CodeSnipWhitespace:
+		return normalizeWhitespace(expected) == normalizeWhitespace(actual)
+	case config.CodeSnipFlaggedLine:
+		exp, expOK := flaggedLine(expected, expectedLine)
+		act, actOK := flaggedLine(actual, actualLine)
+		if expOK && actOK {
+			return exp == act
+		}
+		return normalizeWhitespace(expected) == normalizeWhitespace(actual)
+	}
+	return strings.TrimSpace(expected) == strings.TrimSpace(actual)
+}
@@ -0,0 +1,55 @@
+package validator
+
+import (
+	"strings"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/parser"
+)
+
+// dependencyPathPrefix is where the URI normalizer puts dependency sources
+const dependencyPathPrefix = "/m2/"
+
+// withoutDependencyIncidents returns a copy of rulesets without the incidents in dependency
+// sources, for the rulesets whose validation settings ignore them. Violations and rulesets
+// only found in dependencies are dropped.
+func withoutDependencyIncidents(rulesets []konveyor.RuleSet, testDir string, cfg config.ValidationConfig) []konveyor.RuleSet {
+	uris := parser.URINormalizer{TestDir: testDir}
+	stripped := make([]konveyor.RuleSet, 0, len(rulesets))
+	for _, rs := range rulesets {
+		if cfg.ForRuleSet(rs.Name).DependencyIncidents != config.DependencyIncidentsIgnore {
+			stripped = append(stripped, rs)
+			continue
+		}
+		hadFindings := len(rs.Violations) > 0 || len(rs.Insights) > 0
+		rs.Violations = stripDependencyIncidents(rs.Violations, uris)
+		rs.Insights = stripDependencyIncidents(rs.Insights, uris)
+		if hadFindings && len(rs.Violations) == 0 && len(rs.Insights) == 0 && len(rs.Tags) == 0 {
+			continue
+		}
+		stripped = append(stripped, rs)
+	}
+	return stripped
+}
+
+func stripDependencyIncidents(violations map[string]konveyor.Violation, uris parser.URINormalizer) map[string]konveyor.Violation {
+	if violations == nil {
+		return nil
+	}
+	stripped := make(map[string]konveyor.Violation, len(violations))
+	for ruleID, v := range violations {
+		var incidents []konveyor.Incident
+		for _, incident := range v.Incidents {
+			if !strings.HasPrefix(uris.NormalizePath(incident.URI), dependencyPathPrefix) {
+				incidents = append(incidents, incident)
+			}
+		}
+		if len(v.Incidents) > 0 && len(incidents) == 0 {
+			continue
+		}
+		v.Incidents = incidents
+		stripped[ruleID] = v
+	}
+	return stripped
+}
@@ -0,0 +1,114 @@
+package validator
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/parser"
+	"go.lsp.dev/uri"
+)
+
+// ValidateDiagnostics compares the diagnostics an editor shows, e.g. VSCode's, with
+// the incidents of the expected violations: every incident must be shown once at
+// its file and line with its message, and nothing else. Incidents in dependencies
+// are not expected, editors only show the workspace's files, and incidents without
+// a line are expected on the first line. Count-only expectations are compared by
+// number of diagnostics.
+func ValidateDiagnostics(expected config.ExpectedOutput, diagnostics []parser.Diagnostic, testDir string) *ValidationResult {
+	result := &ValidationResult{}
+	uris := parser.URINormalizer{TestDir: testDir}
+	type key struct {
+		rule, path string
+		line       int
+	}
+	shown := map[key][]parser.Diagnostic{}
+	perRule := map[string]int{}
+	for _, d := range diagnostics {
+		k := key{d.Rule, uris.NormalizePath(uri.URI(d.URI)), d.Line}
+		shown[k] = append(shown[k], d)
+		perRule[d.Rule]++
+	}
+
+	counted := map[string]bool{}
+	for _, rs := range expected.Result {
+		for _, ruleID := range slices.Sorted(maps.Keys(rs.Violations)) {
+			if count, ok := expected.IncidentCounts.Get(rs.Name, ruleID); ok {
+				counted[ruleID] = true
+				if !count.Allows(perRule[ruleID]) {
+					result.Errors = append(result.Errors, ValidationError{
+						Path:     fmt.Sprintf("diagnostics/%s/%s", rs.Name, ruleID),
+						Code:     CodeIncidentCountMismatch,
+						Message:  fmt.Sprintf("Expected %d diagnostic(s) of %s/%s, %d shown", count.Count, rs.Name, ruleID, perRule[ruleID]),
+						Expected: count.Count,
+						Actual:   perRule[ruleID],
+						Location: &Location{RuleSet: rs.Name, Section: "violations", RuleID: ruleID},
+					})
+				}
+				continue
+			}
+			for idx, incident := range parser.SortViolation(rs.Violations[ruleID]).Incidents {
+				path := uris.NormalizePath(incident.URI)
+				if strings.HasPrefix(path, dependencyPathPrefix) {
+					continue
+				}
+				line := 1
+				if incident.LineNumber != nil && *incident.LineNumber > 0 {
+					line = *incident.LineNumber
+				}
+				k := key{ruleID, path, line}
+				location := &Location{RuleSet: rs.Name, Section: "violations", RuleID: ruleID, Incident: intPtr(idx)}
+				if len(shown[k]) == 0 {
+					result.Errors = append(result.Errors, ValidationError{
+						Path:     fmt.Sprintf("diagnostics/%s/%s", rs.Name, ruleID),
+						Code:     CodeMissingDiagnostic,
+						Message:  fmt.Sprintf("Expected diagnostic of %s not shown at %s:%d", ruleID, path, k.line),
+						Expected: fmt.Sprintf("%s:%d", path, k.line),
+						Location: location,
+					})
+					continue
+				}
+				d := shown[k][0]
+				shown[k] = shown[k][1:]
+				if strings.TrimSpace(d.Message) != strings.TrimSpace(incident.Message) {
+					location.Field = "message"
+					result.Errors = append(result.Errors, ValidationError{
+						Path:     fmt.Sprintf("diagnostics/%s/%s", rs.Name, ruleID),
+						Code:     CodeDiagnosticMismatch,
+						Message:  fmt.Sprintf("Diagnostic of %s at %s:%d shows another message", ruleID, path, k.line),
+						Expected: incident.Message,
+						Actual:   d.Message,
+						Location: location,
+					})
+				}
+			}
+		}
+	}
+
+	var unexpected []key
+	for k, remaining := range shown {
+		if counted[k.rule] {
+			continue
+		}
+		for range remaining {
+			unexpected = append(unexpected, k)
+		}
+	}
+	slices.SortFunc(unexpected, func(a, b key) int {
+		return cmp.Or(cmp.Compare(a.path, b.path), cmp.Compare(a.line, b.line), cmp.Compare(a.rule, b.rule))
+	})
+	for _, k := range unexpected {
+		result.Errors = append(result.Errors, ValidationError{
+			Path:    "diagnostics/" + k.rule,
+			Code:    CodeUnexpectedDiagnostic,
+			Message: fmt.Sprintf("Unexpected diagnostic of %s shown at %s:%d", k.rule, k.path, k.line),
+			Actual:  fmt.Sprintf("%s:%d", k.path, k.line),
+		})
+	}
+	setPointers(result.Errors)
+	result.Passed = len(result.Errors) == 0
+	return result
+}
@@ -0,0 +1,112 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/diff"
+	"github.com/konveyor/test-harness/pkg/parser"
+	yaml2 "gopkg.in/yaml.v2"
+)
+
+const (
+	// diffContext is the number of unchanged lines kept around each change
+	diffContext = 3
+	// maxDiffLines caps a single diff so a missing ruleset does not flood the output
+	maxDiffLines = 200
+)
+
+// ValidationDiff is a unified YAML diff of an expected vs actual subtree of the output
+type ValidationDiff struct {
+	Path string `json:"path" yaml:"path"`
+	Diff string `json:"diff" yaml:"diff"`
+}
+
+// subtreeDiff renders a unified diff of two values; a nil value is treated as absent.
+// Uses yaml.v2 to match analyzer-lsp's marshalling behavior.
+func subtreeDiff(path string, expected, actual any) (ValidationDiff, bool) {
+	exp, err := marshalSubtree(expected)
+	if err != nil {
+		return ValidationDiff{}, false
+	}
+	act, err := marshalSubtree(actual)
+	if err != nil {
+		return ValidationDiff{}, false
+	}
+	text := diff.Unified("expected/"+path, "actual/"+path, diff.Lines(diff.SplitLines(exp), diff.SplitLines(act)), diffContext)
+	if text == "" {
+		return ValidationDiff{}, false
+	}
+	if lines := diff.SplitLines(text); len(lines) > maxDiffLines {
+		text = strings.Join(lines[:maxDiffLines], "\n") + fmt.Sprintf("\n... %d more lines\n", len(lines)-maxDiffLines)
+	}
+	return ValidationDiff{Path: path, Diff: text}, true
+}
+
+func marshalSubtree(v any) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	if rv := reflect.ValueOf(v); (rv.Kind() == reflect.Map || rv.Kind() == reflect.Slice) && rv.Len() == 0 {
+		return "", nil
+	}
+	// Both sides are rendered in the same stable order, so diffs only show real differences
+	switch t := v.(type) {
+	case konveyor.RuleSet:
+		v = parser.SortRuleSets([]konveyor.RuleSet{t})[0]
+	case konveyor.Violation:
+		v = parser.SortViolation(t)
+	case []string:
+		v = slices.Sorted(slices.Values(t))
+	}
+	data, err := yaml2.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// violationDiffs diffs each violation of a section that has validation errors
+func violationDiffs[V any](section string, expected, actual map[string]V, errs []ValidationError) []ValidationDiff {
+	failed := map[string]bool{}
+	for _, e := range errs {
+		if !e.Code.IsWarning() {
+			failed[e.Path] = true
+		}
+	}
+	keys := map[string]bool{}
+	for k := range expected {
+		keys[k] = true
+	}
+	for k := range actual {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var diffs []ValidationDiff
+	for _, k := range sorted {
+		path := fmt.Sprintf("%s/%s", section, k)
+		if !failed[path] {
+			continue
+		}
+		var exp, act any
+		if v, ok := expected[k]; ok {
+			exp = v
+		}
+		if v, ok := actual[k]; ok {
+			act = v
+		}
+		if d, ok := subtreeDiff(path, exp, act); ok {
+			diffs = append(diffs, d)
+		}
+	}
+	return diffs
+}
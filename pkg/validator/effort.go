@@ -0,0 +1,62 @@
+package validator
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/config"
+)
+
+// compareEffort checks the aggregated effort of the application and of individual rulesets
+func compareEffort(expect *config.EffortExpectations, actual []konveyor.RuleSet) []ValidationError {
+	if expect == nil {
+		return nil
+	}
+	byRuleSet := make(map[string]int, len(actual))
+	total := 0
+	for _, rs := range actual {
+		effort := rulesetEffort(rs)
+		byRuleSet[rs.Name] = effort
+		total += effort
+	}
+
+	var errors []ValidationError
+	check := func(path string, location *Location, expected, actual int) {
+		if expect.Tolerance.Allows(expected, actual) {
+			return
+		}
+		message := fmt.Sprintf("Expected total effort %d, found %d", expected, actual)
+		if expect.Tolerance.Value > 0 {
+			message = fmt.Sprintf("Expected total effort %d (tolerance %s), found %d", expected, expect.Tolerance, actual)
+		}
+		errors = append(errors, ValidationError{
+			Path:     path,
+			Code:     CodeTotalEffortMismatch,
+			Message:  message,
+			Expected: expected,
+			Actual:   actual,
+			Location: location,
+		})
+	}
+	if expect.Total != nil {
+		check("effort", &Location{Field: "effort"}, *expect.Total, total)
+	}
+	for _, name := range slices.Sorted(maps.Keys(expect.RuleSets)) {
+		check(fmt.Sprintf("%s/effort", name), &Location{RuleSet: name, Field: "effort"}, expect.RuleSets[name], byRuleSet[name])
+	}
+	return errors
+}
+
+// rulesetEffort returns the effort of a ruleset as the Hub and UI compute it:
+// the effort of every violation multiplied by its number of incidents
+func rulesetEffort(rs konveyor.RuleSet) int {
+	total := 0
+	for _, v := range rs.Violations {
+		if v.Effort != nil {
+			total += *v.Effort * len(v.Incidents)
+		}
+	}
+	return total
+}
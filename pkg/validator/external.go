@@ -0,0 +1,86 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/util"
+	yaml2 "gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
+)
+
+// externalInput is written to an external validator's stdin
+type externalInput struct {
+	Target   string             `yaml:"target"`
+	TestDir  string             `yaml:"testDir"`
+	Expected []konveyor.RuleSet `yaml:"expected"`
+	Actual   []konveyor.RuleSet `yaml:"actual"`
+}
+
+// externalOutput is read from an external validator's stdout
+type externalOutput struct {
+	Errors []ValidationError `yaml:"errors"`
+}
+
+// runExternalValidator runs one external validator and returns the errors it reports.
+// A validator that exits non-zero without reporting errors is treated as broken.
+func runExternalValidator(v config.ExternalValidator, testDir, targetType string, actual, expected []konveyor.RuleSet) ([]ValidationError, error) {
+	log := util.GetLogger()
+	name := v.GetName()
+
+	// Uses yaml.v2 to match analyzer-lsp's marshalling behavior
+	input, err := yaml2.Marshal(externalInput{Target: targetType, TestDir: testDir, Expected: expected, Actual: actual})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal input for validator %s: %w", name, err)
+	}
+
+	program := v.Command[0]
+	if strings.Contains(program, "/") && !filepath.IsAbs(program) && testDir != "" {
+		program = filepath.Join(testDir, program)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), v.GetTimeout())
+	defer cancel()
+	cmd := exec.CommandContext(ctx, program, v.Command[1:]...)
+	cmd.Dir = testDir
+	cmd.Env = append(os.Environ(), "KONCUR_TEST_DIR="+testDir, "KONCUR_TARGET="+targetType)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	log.Info("Running external validator", "name", name, "command", v.Command)
+	runErr := cmd.Run()
+	var exitErr *exec.ExitError
+	if runErr != nil && !errors.As(runErr, &exitErr) {
+		return nil, fmt.Errorf("failed to run validator %s: %w", name, runErr)
+	}
+
+	var output externalOutput
+	if err := yaml.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return nil, fmt.Errorf("validator %s returned invalid output: %w", name, err)
+	}
+	if runErr != nil && len(output.Errors) == 0 {
+		return nil, fmt.Errorf("validator %s failed: %w: %s", name, runErr, strings.TrimSpace(stderr.String()))
+	}
+
+	for i := range output.Errors {
+		if output.Errors[i].Path == "" {
+			output.Errors[i].Path = fmt.Sprintf("validator/%s", name)
+		}
+		// Validators may report codes of their own
+		if output.Errors[i].Code == "" {
+			output.Errors[i].Code = CodeExternalValidator
+		}
+		output.Errors[i].Message = fmt.Sprintf("[%s] %s", name, output.Errors[i].Message)
+	}
+	return output.Errors, nil
+}
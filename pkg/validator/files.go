@@ -0,0 +1,93 @@
+package validator
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/konveyor/test-harness/pkg/diff"
+)
+
+// ValidateTransformedFiles compares the files a transform produced in actualDir with
+// the expected files in expectedDir, by relative path and content. With exact, every
+// produced file must be expected.
+func ValidateTransformedFiles(expectedDir, actualDir string, exact bool) (*ValidationResult, error) {
+	expected, err := listFiles(expectedDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expected files: %w", err)
+	}
+	actual, err := listFiles(actualDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list produced files: %w", err)
+	}
+
+	result := &ValidationResult{}
+	for _, name := range expected {
+		if !slices.Contains(actual, name) {
+			result.Errors = append(result.Errors, ValidationError{
+				Path:    name,
+				Code:    CodeMissingFile,
+				Message: fmt.Sprintf("Expected file not produced: %s", name),
+			})
+			continue
+		}
+		want, err := os.ReadFile(filepath.Join(expectedDir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read expected file: %w", err)
+		}
+		got, err := os.ReadFile(filepath.Join(actualDir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read produced file: %w", err)
+		}
+		if string(want) == string(got) {
+			continue
+		}
+		result.Errors = append(result.Errors, ValidationError{
+			Path:    name,
+			Code:    CodeFileMismatch,
+			Message: fmt.Sprintf("Produced file differs from the expected one: %s", name),
+		})
+		text := diff.Unified("expected/"+name, "actual/"+name, diff.Lines(diff.SplitLines(string(want)), diff.SplitLines(string(got))), diffContext)
+		if lines := diff.SplitLines(text); len(lines) > maxDiffLines {
+			text = strings.Join(lines[:maxDiffLines], "\n") + fmt.Sprintf("\n... %d more lines\n", len(lines)-maxDiffLines)
+		}
+		result.Diffs = append(result.Diffs, ValidationDiff{Path: name, Diff: text})
+	}
+	if exact {
+		for _, name := range actual {
+			if !slices.Contains(expected, name) {
+				result.Errors = append(result.Errors, ValidationError{
+					Path:    name,
+					Code:    CodeUnexpectedFile,
+					Message: fmt.Sprintf("Unexpected file produced: %s", name),
+				})
+			}
+		}
+	}
+	result.Passed = len(result.Errors) == 0
+	return result, nil
+}
+
+// listFiles returns the slash-separated paths of the files under dir, sorted
+func listFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	slices.Sort(files)
+	return files, err
+}
@@ -0,0 +1,113 @@
+package validator
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/parser"
+	"go.lsp.dev/uri"
+)
+
+// FixSuggestFunc requests a fix suggestion for an incident of a rule
+type FixSuggestFunc func(ruleset, rule string, incident konveyor.Incident) (*parser.FixSuggestion, error)
+
+// ValidateFixes requests a fix for each incident selected by a test's fixes from
+// the actual output, and checks the structure of the suggestions: a non-empty
+// diff of the incident's file that compiles, when required. Their content is not
+// compared, suggestions vary between runs.
+func ValidateFixes(fixes *config.FixConfig, actual []konveyor.RuleSet, suggest FixSuggestFunc) (*ValidationResult, error) {
+	result := &ValidationResult{}
+	for _, selected := range fixes.Incidents {
+		location := func(field string) *Location {
+			return &Location{RuleSet: selected.RuleSet, Section: "violations", RuleID: selected.Rule, Incident: intPtr(selected.Incident), Field: field}
+		}
+		path := fmt.Sprintf("fixes/%s/%s/%d", selected.RuleSet, selected.Rule, selected.Incident)
+
+		incident, found := selectIncident(actual, selected)
+		if !found {
+			result.Errors = append(result.Errors, ValidationError{
+				Path:     path,
+				Code:     CodeFixIncidentNotFound,
+				Message:  fmt.Sprintf("Incident %d of %s/%s to fix is not in the output", selected.Incident, selected.RuleSet, selected.Rule),
+				Location: location(""),
+			})
+			continue
+		}
+
+		suggestion, err := suggest(selected.RuleSet, selected.Rule, incident)
+		if err != nil {
+			return nil, fmt.Errorf("failed to suggest a fix for %s/%s: %w", selected.RuleSet, selected.Rule, err)
+		}
+		if suggestion == nil {
+			suggestion = &parser.FixSuggestion{}
+		}
+		if strings.TrimSpace(suggestion.Diff) == "" {
+			result.Errors = append(result.Errors, ValidationError{
+				Path:     path,
+				Code:     CodeEmptyFix,
+				Message:  fmt.Sprintf("Fix suggested for %s/%s has an empty diff", selected.RuleSet, selected.Rule),
+				Location: location("diff"),
+			})
+		}
+		if fixFile(suggestion.File) != fixFile(string(incident.URI)) {
+			result.Errors = append(result.Errors, ValidationError{
+				Path:     path,
+				Code:     CodeFixFileMismatch,
+				Message:  fmt.Sprintf("Fix suggested for %s/%s changes %s, not the incident's file", selected.RuleSet, selected.Rule, suggestion.File),
+				Expected: string(incident.URI),
+				Actual:   suggestion.File,
+				Location: location("file"),
+			})
+		}
+		if fixes.RequireCompiles && (suggestion.Compiles == nil || !*suggestion.Compiles) {
+			compiles := "unknown"
+			if suggestion.Compiles != nil {
+				compiles = "false"
+			}
+			result.Errors = append(result.Errors, ValidationError{
+				Path:     path,
+				Code:     CodeFixNotCompiling,
+				Message:  fmt.Sprintf("Fix suggested for %s/%s is not reported as compiling", selected.RuleSet, selected.Rule),
+				Expected: "true",
+				Actual:   compiles,
+				Location: location("compiles"),
+			})
+		}
+	}
+	setPointers(result.Errors)
+	result.Passed = len(result.Errors) == 0
+	return result, nil
+}
+
+// selectIncident returns the selected incident of a violation, its incidents
+// ordered by URI and line
+func selectIncident(rulesets []konveyor.RuleSet, selected config.FixIncident) (konveyor.Incident, bool) {
+	idx := slices.IndexFunc(rulesets, func(rs konveyor.RuleSet) bool { return rs.Name == selected.RuleSet })
+	if idx < 0 {
+		return konveyor.Incident{}, false
+	}
+	violation, ok := rulesets[idx].Violations[selected.Rule]
+	if !ok {
+		return konveyor.Incident{}, false
+	}
+	incidents := parser.SortViolation(violation).Incidents
+	if selected.Incident >= len(incidents) {
+		return konveyor.Incident{}, false
+	}
+	return incidents[selected.Incident], true
+}
+
+// fixFile returns the cleaned path of a file path or file URI
+func fixFile(file string) string {
+	if strings.HasPrefix(file, "file://") {
+		file = uri.URI(file).Filename()
+	}
+	if file == "" {
+		return ""
+	}
+	return filepath.Clean(file)
+}
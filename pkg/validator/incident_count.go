@@ -0,0 +1,71 @@
+package validator
+
+import (
+	"fmt"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/config"
+)
+
+// compareIncidentCounts checks the number of incidents of counted violations and insights.
+// Missing violations are reported by the regular comparison.
+func compareIncidentCounts(counts config.IncidentCounts, actual []konveyor.RuleSet) []ValidationError {
+	var errors []ValidationError
+	for _, rs := range actual {
+		for _, section := range []struct {
+			name       string
+			violations map[string]konveyor.Violation
+		}{{"violations", rs.Violations}, {"insights", rs.Insights}} {
+			for ruleID, violation := range section.violations {
+				count, ok := counts.Get(rs.Name, ruleID)
+				if !ok || count.Allows(len(violation.Incidents)) {
+					continue
+				}
+				message := fmt.Sprintf("Expected %d incidents, found %d", count.Count, len(violation.Incidents))
+				if count.Tolerance.Value > 0 {
+					message = fmt.Sprintf("Expected %d incidents (tolerance %s), found %d", count.Count, count.Tolerance, len(violation.Incidents))
+				}
+				errors = append(errors, ValidationError{
+					Path:     fmt.Sprintf("%s/%s/%s", rs.Name, section.name, ruleID),
+					Code:     CodeIncidentCountMismatch,
+					Message:  message,
+					Expected: count.Count,
+					Actual:   len(violation.Incidents),
+					Location: &Location{RuleSet: rs.Name, Section: section.name, RuleID: ruleID, Field: "incidents"},
+				})
+			}
+		}
+	}
+	return errors
+}
+
+// withoutCountedIncidents returns a copy of rulesets with the incidents of counted rules removed
+func withoutCountedIncidents(rulesets []konveyor.RuleSet, counts config.IncidentCounts) []konveyor.RuleSet {
+	if len(counts) == 0 {
+		return rulesets
+	}
+	stripped := make([]konveyor.RuleSet, len(rulesets))
+	for i, rs := range rulesets {
+		stripped[i] = rs
+		if counts[rs.Name] == nil {
+			continue
+		}
+		stripped[i].Violations = stripIncidents(rs.Violations, counts[rs.Name])
+		stripped[i].Insights = stripIncidents(rs.Insights, counts[rs.Name])
+	}
+	return stripped
+}
+
+func stripIncidents(violations map[string]konveyor.Violation, counts map[string]config.IncidentCount) map[string]konveyor.Violation {
+	if violations == nil {
+		return nil
+	}
+	stripped := make(map[string]konveyor.Violation, len(violations))
+	for ruleID, v := range violations {
+		if _, ok := counts[ruleID]; ok {
+			v.Incidents = nil
+		}
+		stripped[ruleID] = v
+	}
+	return stripped
+}
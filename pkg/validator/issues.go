@@ -0,0 +1,82 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/parser"
+)
+
+// ValidateIssues compares the issues a target shows, e.g. in the Tackle UI's issues
+// table, with the issues of the expected output: every expected violation must be
+// shown once with its category, effort and number of incidents, and nothing else.
+// Count-only expectations give the expected number of incidents, and its tolerance.
+func ValidateIssues(expected config.ExpectedOutput, shown []parser.Issue) *ValidationResult {
+	result := &ValidationResult{}
+	type key struct{ ruleset, rule string }
+	remaining := map[key]parser.Issue{}
+	for _, issue := range shown {
+		k := key{issue.RuleSet, issue.Rule}
+		if _, duplicate := remaining[k]; duplicate {
+			result.Errors = append(result.Errors, issueError(CodeUnexpectedIssue, issue, "", fmt.Sprintf("Issue shown more than once: %s/%s", issue.RuleSet, issue.Rule), nil, issue.Rule))
+			continue
+		}
+		remaining[k] = issue
+	}
+
+	for _, want := range parser.AggregateIssues(expected.Result) {
+		count, counted := expected.IncidentCounts.Get(want.RuleSet, want.Rule)
+		if counted {
+			want.Incidents = count.Count
+		}
+		k := key{want.RuleSet, want.Rule}
+		got, ok := remaining[k]
+		delete(remaining, k)
+		if !ok {
+			result.Errors = append(result.Errors, issueError(CodeMissingIssue, want, "", fmt.Sprintf("Expected issue not shown: %s/%s", want.RuleSet, want.Rule), want.Rule, nil))
+			continue
+		}
+		incidentsMatch := want.Incidents == got.Incidents
+		if counted {
+			incidentsMatch = count.Allows(got.Incidents)
+		}
+		for _, field := range []struct {
+			name     string
+			expected any
+			actual   any
+			matches  bool
+		}{
+			{"category", want.Category, got.Category, want.Category == got.Category},
+			{"effort", want.Effort, got.Effort, want.Effort == got.Effort},
+			{"incidents", want.Incidents, got.Incidents, incidentsMatch},
+		} {
+			if !field.matches {
+				result.Errors = append(result.Errors, issueError(CodeIssueMismatch, want, field.name,
+					fmt.Sprintf("Issue %s/%s shows %s %v, expected %v", want.RuleSet, want.Rule, field.name, field.actual, field.expected),
+					field.expected, field.actual))
+			}
+		}
+	}
+
+	for _, issue := range shown {
+		if _, ok := remaining[key{issue.RuleSet, issue.Rule}]; ok {
+			delete(remaining, key{issue.RuleSet, issue.Rule})
+			result.Errors = append(result.Errors, issueError(CodeUnexpectedIssue, issue, "", fmt.Sprintf("Unexpected issue shown: %s/%s", issue.RuleSet, issue.Rule), nil, issue.Rule))
+		}
+	}
+	setPointers(result.Errors)
+	result.Passed = len(result.Errors) == 0
+	return result
+}
+
+// issueError reports a difference of an issue, located at its violation
+func issueError(code ErrorCode, issue parser.Issue, field, message string, expected, actual any) ValidationError {
+	return ValidationError{
+		Path:     fmt.Sprintf("issues/%s/%s", issue.RuleSet, issue.Rule),
+		Code:     code,
+		Message:  message,
+		Expected: expected,
+		Actual:   actual,
+		Location: &Location{RuleSet: issue.RuleSet, Section: "violations", RuleID: issue.Rule, Field: field},
+	}
+}
@@ -0,0 +1,37 @@
+package validator
+
+import (
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+// kaiRPCValidator compares output received from the Kai analyzer RPC server.
+// Kai returns diagnostics per file: incidents carry a location and message but
+// no code snips or variables, and rulesets have no unmatched or skipped rules,
+// which the diagnostics profile tolerates.
+type kaiRPCValidator struct {
+	baseValidator
+}
+
+// compareViolations compares violations without the incident fields Kai diagnostics don't carry
+func (k *kaiRPCValidator) compareViolations(expected, actual map[string]konveyor.Violation) []ValidationError {
+	return k.baseValidator.compareViolations(withoutDiagnosticFields(expected), withoutDiagnosticFields(actual))
+}
+
+// withoutDiagnosticFields returns a copy of violations with incident code snips and variables cleared
+func withoutDiagnosticFields(violations map[string]konveyor.Violation) map[string]konveyor.Violation {
+	if violations == nil {
+		return nil
+	}
+	stripped := make(map[string]konveyor.Violation, len(violations))
+	for ruleID, v := range violations {
+		incidents := make([]konveyor.Incident, len(v.Incidents))
+		for i, incident := range v.Incidents {
+			incident.CodeSnip = ""
+			incident.Variables = nil
+			incidents[i] = incident
+		}
+		v.Incidents = incidents
+		stripped[ruleID] = v
+	}
+	return stripped
+}
@@ -0,0 +1,50 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/konveyor/test-harness/pkg/config"
+)
+
+// ValidateLabels compares the source and target labels listed for a list-labels
+// test with its expectations. Expected labels must be listed; with exact, listed
+// labels must be expected.
+func ValidateLabels(expect *config.LabelExpectations, sources, targets []string) *ValidationResult {
+	result := &ValidationResult{}
+	for _, kind := range []struct {
+		name             string
+		expected, listed []string
+	}{{"sources", expect.Sources, sources}, {"targets", expect.Targets, targets}} {
+		for idx, label := range kind.expected {
+			if !slices.Contains(kind.listed, label) {
+				result.Errors = append(result.Errors, ValidationError{
+					Path:     fmt.Sprintf("%s/%s", kind.name, label),
+					Code:     CodeMissingLabel,
+					Message:  fmt.Sprintf("Expected %s label not listed: %s", kind.name, label),
+					Expected: label,
+					Location: &Location{Section: kind.name, Index: intPtr(idx)},
+				})
+			}
+		}
+		if !expect.Exact {
+			continue
+		}
+		for idx, label := range kind.listed {
+			if !slices.Contains(kind.expected, label) {
+				result.Errors = append(result.Errors, ValidationError{
+					Path:     fmt.Sprintf("%s/%s", kind.name, label),
+					Code:     CodeUnexpectedLabel,
+					Message:  fmt.Sprintf("Unexpected %s label listed: %s", kind.name, label),
+					Actual:   label,
+					Location: &Location{Section: kind.name, Index: intPtr(idx)},
+				})
+			}
+		}
+	}
+	for i := range result.Errors {
+		result.Errors[i].Pointer = result.Errors[i].Location.Pointer()
+	}
+	result.Passed = len(result.Errors) == 0
+	return result
+}
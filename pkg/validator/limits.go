@@ -0,0 +1,118 @@
+package validator
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/parser"
+)
+
+// compareIncidentLimits reports violations and insights with more incidents, or
+// more incidents with code snips, than the analysis' limits allow
+func compareIncidentLimits(actual []konveyor.RuleSet, cfg config.ValidationConfig) []ValidationError {
+	if cfg.IncidentLimit == 0 && cfg.CodeSnipLimit == 0 {
+		return nil
+	}
+	var errors []ValidationError
+	for _, rs := range actual {
+		for _, section := range []struct {
+			name       string
+			violations map[string]konveyor.Violation
+		}{{"violations", rs.Violations}, {"insights", rs.Insights}} {
+			for _, ruleID := range slices.Sorted(maps.Keys(section.violations)) {
+				v := section.violations[ruleID]
+				location := &Location{RuleSet: rs.Name, Section: section.name, RuleID: ruleID}
+				if cfg.IncidentLimit > 0 && len(v.Incidents) > cfg.IncidentLimit {
+					errors = append(errors, ValidationError{
+						Path:     fmt.Sprintf("%s/%s/%s", rs.Name, section.name, ruleID),
+						Code:     CodeIncidentLimitExceeded,
+						Message:  fmt.Sprintf("Found %d incidents, above the incident limit of %d", len(v.Incidents), cfg.IncidentLimit),
+						Expected: cfg.IncidentLimit,
+						Actual:   len(v.Incidents),
+						Location: location,
+					})
+				}
+				snips := 0
+				for _, incident := range v.Incidents {
+					if incident.CodeSnip != "" {
+						snips++
+					}
+				}
+				if cfg.CodeSnipLimit > 0 && snips > cfg.CodeSnipLimit {
+					errors = append(errors, ValidationError{
+						Path:     fmt.Sprintf("%s/%s/%s", rs.Name, section.name, ruleID),
+						Code:     CodeCodeSnipLimitExceeded,
+						Message:  fmt.Sprintf("Found %d code snips, above the code snip limit of %d", snips, cfg.CodeSnipLimit),
+						Expected: cfg.CodeSnipLimit,
+						Actual:   snips,
+						Location: location,
+					})
+				}
+			}
+		}
+	}
+	return errors
+}
+
+// withoutTruncatedIncidents returns a copy of expected rulesets without the incidents
+// the actual output's violations and insights at the incident limit don't have.
+// Which incidents survive the cap is up to the analyzer, only the kept ones are compared.
+func withoutTruncatedIncidents(expected, actual []konveyor.RuleSet, testDir string, limit int) []konveyor.RuleSet {
+	if limit == 0 {
+		return expected
+	}
+	uris := parser.URINormalizer{TestDir: testDir}
+	actualByName := make(map[string]konveyor.RuleSet, len(actual))
+	for _, rs := range actual {
+		if _, exists := actualByName[rs.Name]; !exists {
+			actualByName[rs.Name] = rs
+		}
+	}
+	truncated := make([]konveyor.RuleSet, len(expected))
+	for i, ers := range expected {
+		rs := actualByName[ers.Name]
+		ers.Violations = truncateIncidents(ers.Violations, rs.Violations, uris, limit)
+		ers.Insights = truncateIncidents(ers.Insights, rs.Insights, uris, limit)
+		truncated[i] = ers
+	}
+	return truncated
+}
+
+func truncateIncidents(expected, actual map[string]konveyor.Violation, uris parser.URINormalizer, limit int) map[string]konveyor.Violation {
+	if expected == nil {
+		return nil
+	}
+	truncated := make(map[string]konveyor.Violation, len(expected))
+	for ruleID, v := range expected {
+		av, found := actual[ruleID]
+		if !found || len(av.Incidents) < limit || len(v.Incidents) <= len(av.Incidents) {
+			truncated[ruleID] = v
+			continue
+		}
+		kept := map[string]bool{}
+		for _, incident := range av.Incidents {
+			kept[limitKey(incident, uris)] = true
+		}
+		var incidents []konveyor.Incident
+		for _, incident := range v.Incidents {
+			if kept[limitKey(incident, uris)] {
+				incidents = append(incidents, incident)
+			}
+		}
+		v.Incidents = incidents
+		truncated[ruleID] = v
+	}
+	return truncated
+}
+
+// limitKey identifies an incident by its normalized location
+func limitKey(incident konveyor.Incident, uris parser.URINormalizer) string {
+	line := 0
+	if incident.LineNumber != nil {
+		line = *incident.LineNumber
+	}
+	return fmt.Sprintf("%s:%d", uris.NormalizePath(incident.URI), line)
+}
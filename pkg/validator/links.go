@@ -0,0 +1,148 @@
+package validator
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/util"
+)
+
+// redirectTimeout bounds each request made to resolve a link's redirects
+const redirectTimeout = 10 * time.Second
+
+// resolvedLinks caches the final URL of followed links across tests in a run
+var resolvedLinks sync.Map
+
+// resolveRedirect returns the URL a link finally redirects to, or the URL itself
+// when it cannot be fetched. It is a variable so tests can avoid the network.
+var resolveRedirect = func(link string) string {
+	if final, ok := resolvedLinks.Load(link); ok {
+		return final.(string)
+	}
+	final := link
+	client := &http.Client{Timeout: redirectTimeout}
+	resp, err := client.Head(link)
+	if err != nil {
+		util.GetLogger().Info("Failed to resolve link redirects", "url", link, "error", err.Error())
+	} else {
+		resp.Body.Close()
+		final = resp.Request.URL.String()
+	}
+	resolvedLinks.Store(link, final)
+	return final
+}
+
+// linkComparer compares violation links with normalized URLs
+type linkComparer struct {
+	config config.LinkComparison
+}
+
+// normalizeURL makes equivalent URLs compare equal: http and https, host case,
+// default ports, trailing slashes and empty queries or fragments are ignored
+func (c linkComparer) normalizeURL(link string) string {
+	link = strings.TrimSpace(link)
+	if c.config.FollowRedirects {
+		link = resolveRedirect(link)
+	}
+	if c.config.ExactURLs {
+		return link
+	}
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return strings.TrimSuffix(link, "/")
+	}
+	if u.Scheme == "http" || u.Scheme == "https" {
+		u.Scheme = "https"
+	}
+	u.Host = strings.ToLower(u.Hostname())
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		u.Host += ":" + port
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = ""
+	u.ForceQuery = false
+	return u.String()
+}
+
+// linksMatch reports whether two links have the same title and equivalent URLs
+func (c linkComparer) linksMatch(expected, actual konveyor.Link) bool {
+	return strings.TrimSpace(expected.Title) == strings.TrimSpace(actual.Title) &&
+		c.normalizeURL(expected.URL) == c.normalizeURL(actual.URL)
+}
+
+// compare reports expected links missing from the actual links and, in strict mode,
+// actual links that were not expected. A missing link is reported together with the
+// actual link that has the same title or URL, so the differing part is visible.
+func (c linkComparer) compare(expected, actual []konveyor.Link, strict bool) []ValidationError {
+	var errors []ValidationError
+	matched := make([]bool, len(actual))
+	for idx, l := range expected {
+		found := false
+		for j, al := range actual {
+			if c.linksMatch(l, al) {
+				matched[j] = true
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		verr := ValidationError{
+			Code:     CodeMissingLink,
+			Message:  fmt.Sprintf("Did not find expected link: %s (%s)", l.Title, l.URL),
+			Expected: l,
+			Location: &Location{Field: "links", Index: intPtr(idx)},
+		}
+		if closest, ok := c.closestLink(l, actual); ok {
+			verr.Message += fmt.Sprintf(", closest actual link: %s (%s)", closest.Title, closest.URL)
+			verr.Actual = closest
+		}
+		errors = append(errors, verr)
+	}
+	if !strict {
+		return errors
+	}
+	for idx, al := range actual {
+		if matched[idx] {
+			continue
+		}
+		found := false
+		for _, l := range expected {
+			if c.linksMatch(l, al) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errors = append(errors, ValidationError{
+				Code:     CodeUnexpectedLink,
+				Message:  fmt.Sprintf("Unexpected link found: %s (%s)", al.Title, al.URL),
+				Actual:   al,
+				Location: &Location{Field: "links", Index: intPtr(idx)},
+			})
+		}
+	}
+	return errors
+}
+
+// closestLink returns the actual link sharing the expected link's URL or title
+func (c linkComparer) closestLink(expected konveyor.Link, actual []konveyor.Link) (konveyor.Link, bool) {
+	for _, al := range actual {
+		if c.normalizeURL(expected.URL) == c.normalizeURL(al.URL) {
+			return al, true
+		}
+	}
+	for _, al := range actual {
+		if strings.TrimSpace(expected.Title) == strings.TrimSpace(al.Title) {
+			return al, true
+		}
+	}
+	return konveyor.Link{}, false
+}
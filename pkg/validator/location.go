@@ -0,0 +1,89 @@
+package validator
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Location is the structured location of a validation error in the output:
+// ruleset → section → rule → incident → field
+type Location struct {
+	// Application is the application of a test analyzing several whose output the
+	// location is in
+	Application string `json:"application,omitempty" yaml:"application,omitempty"`
+	RuleSet     string `json:"ruleset,omitempty" yaml:"ruleset,omitempty"`
+	// Section is violations, insights, tags, unmatched, skipped or errors, or the
+	// sources or targets of listed labels
+	Section string `json:"section,omitempty" yaml:"section,omitempty"`
+	RuleID  string `json:"ruleID,omitempty" yaml:"ruleID,omitempty"`
+	// Incident is the index of the incident in the expected output for missing
+	// incidents, and in the actual output for unexpected ones
+	Incident *int `json:"incident,omitempty" yaml:"incident,omitempty"`
+	// Field is the violation or incident field that did not match, e.g. effort or lineNumber
+	Field string `json:"field,omitempty" yaml:"field,omitempty"`
+	// Index is the position of an entry in a list such as tags, labels or links,
+	// using the same expected/actual convention as Incident
+	Index *int `json:"index,omitempty" yaml:"index,omitempty"`
+}
+
+// Pointer renders the location as a JSON pointer (RFC 6901) into the output,
+// with rulesets keyed by name, e.g. /my-ruleset/violations/rule-001/incidents/3/lineNumber
+func (l Location) Pointer() string {
+	var parts []string
+	if l.RuleSet != "" {
+		parts = append(parts, l.RuleSet)
+	}
+	if l.Section != "" {
+		parts = append(parts, l.Section)
+	}
+	if l.RuleID != "" {
+		parts = append(parts, l.RuleID)
+	}
+	if l.Incident != nil {
+		parts = append(parts, "incidents", strconv.Itoa(*l.Incident))
+	}
+	if l.Field != "" {
+		parts = append(parts, l.Field)
+	}
+	if l.Index != nil {
+		parts = append(parts, strconv.Itoa(*l.Index))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	escaper := strings.NewReplacer("~", "~0", "/", "~1")
+	for i, p := range parts {
+		parts[i] = escaper.Replace(p)
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+// locate returns an error's location, creating it if needed
+func (v *ValidationError) locate() *Location {
+	if v.Location == nil {
+		v.Location = &Location{}
+	}
+	return v.Location
+}
+
+// inRuleSet records the ruleset and section of errors returned by a comparer
+func inRuleSet(errs []ValidationError, ruleset, section string) {
+	for i := range errs {
+		loc := errs[i].locate()
+		loc.RuleSet = ruleset
+		loc.Section = section
+	}
+}
+
+// setPointers renders the JSON pointer of every located error
+func setPointers(errs []ValidationError) {
+	for i := range errs {
+		if errs[i].Location != nil {
+			errs[i].Pointer = errs[i].Location.Pointer()
+		}
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,115 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/config"
+)
+
+// ValidateProviders compares the analysis providers that ran with a test's
+// expectations. Required providers must have run; with exact, providers that ran
+// must be required. Targets that cannot tell which providers ran pass nil, then
+// providers with expected output must have produced some of it, the others are
+// reported as warnings.
+func ValidateProviders(expect *config.ProviderExpectations, ran []string, actual []konveyor.RuleSet) *ValidationResult {
+	result := &ValidationResult{}
+	if expect == nil {
+		result.Passed = true
+		return result
+	}
+
+	if ran == nil {
+		var unchecked []string
+		for _, name := range expect.Required() {
+			output, ok := expect.Output[name]
+			if !ok || countViolations(output.Result) == 0 {
+				unchecked = append(unchecked, name)
+				continue
+			}
+			if !producedAny(output.Result, actual) {
+				result.Errors = append(result.Errors, ValidationError{
+					Path:     "providers/" + name,
+					Code:     CodeProviderNotRun,
+					Message:  fmt.Sprintf("Provider %s produced none of its %d expected violation(s), it may not have run", name, countViolations(output.Result)),
+					Expected: name,
+				})
+			}
+		}
+		var skipped []string
+		if len(unchecked) > 0 {
+			skipped = append(skipped, fmt.Sprintf("whether %s ran", strings.Join(unchecked, ", ")))
+		}
+		if expect.Exact {
+			skipped = append(skipped, "that no other provider ran")
+		}
+		if len(skipped) > 0 {
+			result.Warnings = append(result.Warnings, ValidationError{
+				Path:    "providers",
+				Code:    CodeProvidersUnknown,
+				Message: fmt.Sprintf("The target cannot tell which providers ran, not checking %s", strings.Join(skipped, " or ")),
+			})
+		}
+		result.Passed = len(result.Errors) == 0
+		return result
+	}
+
+	required := expect.Required()
+	for _, name := range required {
+		if !slices.Contains(ran, name) {
+			result.Errors = append(result.Errors, ValidationError{
+				Path:     "providers/" + name,
+				Code:     CodeProviderNotRun,
+				Message:  fmt.Sprintf("Expected provider did not run: %s", name),
+				Expected: name,
+				Actual:   strings.Join(ran, ", "),
+			})
+		}
+	}
+	if expect.Exact {
+		for _, name := range ran {
+			if !slices.Contains(required, name) {
+				result.Errors = append(result.Errors, ValidationError{
+					Path:    "providers/" + name,
+					Code:    CodeUnexpectedProvider,
+					Message: fmt.Sprintf("Unexpected provider ran: %s", name),
+					Actual:  name,
+				})
+			}
+		}
+	}
+	result.Passed = len(result.Errors) == 0
+	return result
+}
+
+// countViolations returns the number of violations and insights of rulesets
+func countViolations(rulesets []konveyor.RuleSet) int {
+	count := 0
+	for _, rs := range rulesets {
+		count += len(rs.Violations) + len(rs.Insights)
+	}
+	return count
+}
+
+// producedAny reports whether any violation or insight of expected is in actual
+func producedAny(expected, actual []konveyor.RuleSet) bool {
+	for _, rs := range expected {
+		idx := slices.IndexFunc(actual, func(a konveyor.RuleSet) bool { return a.Name == rs.Name })
+		if idx < 0 {
+			continue
+		}
+		for ruleID := range rs.Violations {
+			if _, ok := actual[idx].Violations[ruleID]; ok {
+				return true
+			}
+		}
+		for ruleID := range rs.Insights {
+			if _, ok := actual[idx].Insights[ruleID]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package validator
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+// CompareRuns compares a test's output with its output in a previous run and
+// reports violations and insights that newly appeared or disappeared since then.
+// It needs no expected output, so it can gate regressions of any application.
+func CompareRuns(previous, current []konveyor.RuleSet) *ValidationResult {
+	previousByName := make(map[string]konveyor.RuleSet, len(previous))
+	for _, rs := range previous {
+		previousByName[rs.Name] = rs
+	}
+	currentByName := make(map[string]konveyor.RuleSet, len(current))
+	for _, rs := range current {
+		currentByName[rs.Name] = rs
+	}
+
+	names := make([]string, 0, len(previousByName)+len(currentByName))
+	for name := range previousByName {
+		names = append(names, name)
+	}
+	for name := range currentByName {
+		if _, ok := previousByName[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var errors []ValidationError
+	for _, name := range names {
+		prev, cur := previousByName[name], currentByName[name]
+		for _, section := range []struct {
+			name     string
+			kind     string
+			added    ErrorCode
+			removed  ErrorCode
+			previous map[string]konveyor.Violation
+			current  map[string]konveyor.Violation
+		}{
+			{"violations", "violation", CodeAddedViolation, CodeRemovedViolation, prev.Violations, cur.Violations},
+			{"insights", "insight", CodeAddedInsight, CodeRemovedInsight, prev.Insights, cur.Insights},
+		} {
+			for _, ruleID := range slices.Sorted(maps.Keys(section.current)) {
+				if _, ok := section.previous[ruleID]; !ok {
+					v := section.current[ruleID]
+					errors = append(errors, ValidationError{
+						Path:     fmt.Sprintf("%s/%s/%s", name, section.name, ruleID),
+						Code:     section.added,
+						Message:  fmt.Sprintf("Added %s: %s (%d incidents)", section.kind, ruleID, len(v.Incidents)),
+						Actual:   v,
+						Location: &Location{RuleSet: name, Section: section.name, RuleID: ruleID},
+					})
+				}
+			}
+			for _, ruleID := range slices.Sorted(maps.Keys(section.previous)) {
+				if _, ok := section.current[ruleID]; !ok {
+					v := section.previous[ruleID]
+					errors = append(errors, ValidationError{
+						Path:     fmt.Sprintf("%s/%s/%s", name, section.name, ruleID),
+						Code:     section.removed,
+						Message:  fmt.Sprintf("Removed %s: %s (%d incidents)", section.kind, ruleID, len(v.Incidents)),
+						Expected: v,
+						Location: &Location{RuleSet: name, Section: section.name, RuleID: ruleID},
+					})
+				}
+			}
+		}
+	}
+	setPointers(errors)
+
+	return &ValidationResult{
+		Passed: len(errors) == 0,
+		Errors: errors,
+	}
+}
@@ -0,0 +1,80 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+// renamedRules pairs expected rule IDs missing from the actual output with unexpected
+// actual rule IDs of the same description and category, when matchRenamedRules is set.
+// It maps expected rule IDs to the actual ones they were renamed to.
+func (b *baseValidator) renamedRules(expected, actual map[string]konveyor.Violation) map[string]string {
+	if !boolOr(b.config.MatchRenamedRules, false) {
+		return nil
+	}
+
+	var missing, unexpected []string
+	for k := range expected {
+		if _, ok := actual[k]; !ok {
+			missing = append(missing, k)
+		}
+	}
+	for k := range actual {
+		if _, ok := expected[k]; !ok {
+			unexpected = append(unexpected, k)
+		}
+	}
+	// Pair in a stable order so the same outputs always produce the same renames
+	sort.Strings(missing)
+	sort.Strings(unexpected)
+
+	renamed := map[string]string{}
+	for _, oldID := range missing {
+		exp := expected[oldID]
+		if exp.Description == "" {
+			continue
+		}
+		idx := slices.IndexFunc(unexpected, func(newID string) bool {
+			act := actual[newID]
+			return act.Description == exp.Description && categoryOf(act) == categoryOf(exp)
+		})
+		if idx < 0 {
+			continue
+		}
+		renamed[oldID] = unexpected[idx]
+		unexpected = slices.Delete(unexpected, idx, idx+1)
+	}
+	return renamed
+}
+
+// renamedTo reports whether an actual rule ID is the new name of an expected rule
+func renamedTo(renamed map[string]string, ruleID string) bool {
+	for _, newID := range renamed {
+		if newID == ruleID {
+			return true
+		}
+	}
+	return false
+}
+
+// renamedRuleWarning reports an expected rule matched under a new rule ID
+func renamedRuleWarning(oldID, newID string) ValidationError {
+	return ValidationError{
+		Path:     fmt.Sprintf("/%s", oldID),
+		Code:     CodeRenamedRule,
+		Message:  fmt.Sprintf("Rule %s was not found, matched %s by description and category (renamed?)", oldID, newID),
+		Expected: oldID,
+		Actual:   newID,
+		Location: &Location{RuleID: oldID},
+	}
+}
+
+func categoryOf(v konveyor.Violation) konveyor.Category {
+	if v.Category == nil {
+		return ""
+	}
+	return *v.Category
+}
@@ -2,38 +2,34 @@ package validator
 
 import (
 	"fmt"
-	"path/filepath"
 	"strings"
 
 	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"go.lsp.dev/uri"
 )
 
+// tackleHubValidator compares output read from the Tackle Hub API. The known
+// differences to kantra output are tolerated by the hub-vs-kantra profile.
 type tackleHubValidator struct {
 	baseValidator
 }
 
-// Don't compare - hub doesn't store this info in the API AFAICT
-func (t *tackleHubValidator) compareUnmatched(expected, actual []string) []ValidationError {
-	return nil
-}
-
-func (t *tackleHubValidator) compareSkipped(expected, actual []string) []ValidationError {
-	return nil
-}
-
-func (t *tackleHubValidator) compareTags(expected, actual []string) []ValidationError {
-	return nil
-}
-
 func (t *tackleHubValidator) compareViolations(expected, actual map[string]konveyor.Violation) []ValidationError {
 	var errors []ValidationError
+	renamed := t.renamedRules(expected, actual)
 	for k, exp := range expected {
 		act, exists := actual[k]
+		if newID, ok := renamed[k]; ok {
+			act, exists = actual[newID], true
+			errors = append(errors, renamedRuleWarning(k, newID))
+		}
 		if !exists {
 			errors = append(errors, ValidationError{
 				Path:     fmt.Sprintf("/%s", k),
+				Code:     CodeMissingViolation,
 				Message:  fmt.Sprintf("Did not find expected violation: %s", k),
 				Expected: exp,
+				Location: &Location{RuleID: k},
 			})
 			continue
 		}
@@ -41,15 +37,18 @@ func (t *tackleHubValidator) compareViolations(expected, actual map[string]konve
 		detailErrors := t.compareViolationDetails(exp, act)
 		for i := range detailErrors {
 			detailErrors[i].Path = fmt.Sprintf("/%s%s", k, detailErrors[i].Path)
+			detailErrors[i].locate().RuleID = k
 		}
 		errors = append(errors, detailErrors...)
 	}
 	for k := range actual {
-		if _, exists := expected[k]; !exists {
+		if _, exists := expected[k]; !exists && !renamedTo(renamed, k) {
 			errors = append(errors, ValidationError{
-				Path:    fmt.Sprintf("/%s", k),
-				Message: fmt.Sprintf("Unexpected violation found: %s", k),
-				Actual:  actual[k],
+				Path:     fmt.Sprintf("/%s", k),
+				Code:     CodeUnexpectedViolation,
+				Message:  fmt.Sprintf("Unexpected violation found: %s", k),
+				Actual:   actual[k],
+				Location: &Location{RuleID: k},
 			})
 		}
 	}
@@ -59,45 +58,36 @@ func (t *tackleHubValidator) compareViolations(expected, actual map[string]konve
 
 func (t *tackleHubValidator) compareViolationDetails(expected, actual konveyor.Violation) []ValidationError {
 	var errors []ValidationError
-	skipForInsight := expected.Effort == nil
+	// Insights are only compared in strict mode
+	skipForInsight := expected.Effort == nil && !t.config.Strict
 	if !skipForInsight && (expected.Effort != nil && actual.Effort != nil) && (*expected.Effort != *actual.Effort) {
 		errors = append(errors, ValidationError{
-			Message: fmt.Sprintf("Did not find expected effort: %v", expected.Effort),
+			Code:     CodeEffortMismatch,
+			Message:  fmt.Sprintf("Did not find expected effort: %v", expected.Effort),
+			Expected: *expected.Effort,
+			Actual:   *actual.Effort,
+			Location: &Location{Field: "effort"},
 		})
 	}
 	if !skipForInsight && actual.Category != nil && expected.Category != nil && *expected.Category != *actual.Category {
 		errors = append(errors, ValidationError{
-			Message: fmt.Sprintf("Did not find expected category: %v", expected.Category),
+			Code:     CodeCategoryMismatch,
+			Message:  fmt.Sprintf("Did not find expected category: %v", expected.Category),
+			Expected: *expected.Category,
+			Actual:   *actual.Category,
+			Location: &Location{Field: "category"},
 		})
 	}
 
 	// Handle Links
 	if !skipForInsight {
-		for _, l := range expected.Links {
-			found := false
-			for _, al := range actual.Links {
-				if l.Title == al.Title && l.URL == al.URL {
-					found = true
-					break
-				}
-			}
-			if !found {
-				errors = append(errors, ValidationError{
-					Message: fmt.Sprintf("Did not find expected link: %v", l),
-				})
-			}
-		}
+		errors = append(errors, t.compareLinks(expected.Links, actual.Links)...)
 		// Handle Labels
-		for _, l := range expected.Labels {
-			if !findExpectedString(l, actual.Labels) {
-				errors = append(errors, ValidationError{
-					Message: fmt.Sprintf("Did not find expected label: %v", l),
-				})
-			}
-		}
+		errors = append(errors, compareLabels(expected.Labels, actual.Labels)...)
+		errors = append(errors, t.compareStrictDetails(expected, actual)...)
 	}
 	// Handle Incidents
-	for _, i := range expected.Incidents {
+	for idx, i := range expected.Incidents {
 		found := false
 		for _, ai := range actual.Incidents {
 			if t.incidentsMatch(i, ai) {
@@ -107,11 +97,14 @@ func (t *tackleHubValidator) compareViolationDetails(expected, actual konveyor.V
 		}
 		if !found && !skipForInsight {
 			errors = append(errors, ValidationError{
-				Message: fmt.Sprintf("Did not find expected incident: %s:%d", i.URI, lineNumberOrZero(i.LineNumber)),
+				Code:     CodeMissingIncident,
+				Message:  fmt.Sprintf("Did not find expected incident: %s:%d", i.URI, lineNumberOrZero(i.LineNumber)),
+				Expected: i,
+				Location: &Location{Incident: intPtr(idx)},
 			})
 		}
 	}
-	for _, ai := range actual.Incidents {
+	for idx, ai := range actual.Incidents {
 		found := false
 		for _, i := range expected.Incidents {
 			if t.incidentsMatch(i, ai) {
@@ -121,8 +114,10 @@ func (t *tackleHubValidator) compareViolationDetails(expected, actual konveyor.V
 		}
 		if !found && !skipForInsight {
 			errors = append(errors, ValidationError{
-				Message: fmt.Sprintf("Unexpected incident found: %s:%d", ai.URI, lineNumberOrZero(ai.LineNumber)),
-				Actual:  ai,
+				Code:     CodeUnexpectedIncident,
+				Message:  fmt.Sprintf("Unexpected incident found: %s:%d", ai.URI, lineNumberOrZero(ai.LineNumber)),
+				Actual:   ai,
+				Location: &Location{Incident: intPtr(idx)},
 			})
 		}
 	}
@@ -131,25 +126,27 @@ func (t *tackleHubValidator) compareViolationDetails(expected, actual konveyor.V
 }
 
 func (t *tackleHubValidator) incidentsMatch(expected, actual konveyor.Incident) bool {
-	// For code snips, there is no way to configure them
-	// So for tackle2Hub we are going to ignore code snips
-	if string(expected.URI) != "" && string(actual.URI) != "" {
-		if expected.URI != actual.URI {
-			pathToTest, err := filepath.Rel("/source", expected.URI.Filename())
-			if err != nil {
-				return false
-			}
-			if !strings.Contains(actual.URI.Filename(), pathToTest) {
-				return false
-			}
-		}
+	if string(expected.URI) != "" && string(actual.URI) != "" && !t.urisMatch(expected.URI, actual.URI) {
+		return false
+	}
+	if !t.messagesMatch(expected.Message, actual.Message) {
+		return false
 	}
-	if expected.Message != actual.Message {
+	if !t.ignoreLineNumbers() && expected.LineNumber != nil && actual.LineNumber != nil && *expected.LineNumber != *actual.LineNumber {
 		return false
 	}
-	if expected.LineNumber != nil && actual.LineNumber != nil && *expected.LineNumber != *actual.LineNumber {
+	if !t.codeSnipsMatch(expected, actual) || !t.variablesMatch(expected.Variables, actual.Variables) {
 		return false
 	}
 
 	return true
 }
+
+// urisMatch compares normalized URIs. The hub clones applications into a directory
+// of its own below the source directory, so the actual path may carry extra leading
+// directories.
+func (t *tackleHubValidator) urisMatch(expected, actual uri.URI) bool {
+	expectedPath := t.uris.NormalizePath(expected)
+	actualPath := t.uris.NormalizePath(actual)
+	return expectedPath == actualPath || strings.HasSuffix(actualPath, "/"+strings.TrimPrefix(expectedPath, "/"))
+}
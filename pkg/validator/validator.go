@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"maps"
 	"reflect"
+	"regexp"
 
 	"github.com/fatih/color"
 	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/parser"
 )
 
 type tagCompare interface {
@@ -42,35 +45,72 @@ type comparer interface {
 	skippedCompare
 }
 
-func getComparer(targetType, testDir string) comparer {
-	base := &baseValidator{testDir: testDir}
+func getComparer(targetType, testDir string, cfg config.ValidationConfig) (comparer, error) {
+	cfg, err := cfg.WithProfile(targetType)
+	if err != nil {
+		return nil, err
+	}
+	base := &baseValidator{
+		testDir: testDir,
+		config:  cfg,
+		uris:    parser.URINormalizer{TestDir: testDir, StripPrefixes: cfg.StripURIPrefixes},
+	}
+	for _, pattern := range cfg.MessageIgnorePatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid message ignore pattern %q: %w", pattern, err)
+		}
+		base.messageIgnore = append(base.messageIgnore, re)
+	}
+	if cfg.Variables != nil && len(cfg.Variables.Patterns) > 0 {
+		base.variablePatterns = make(map[string]*regexp.Regexp, len(cfg.Variables.Patterns))
+		for key, pattern := range cfg.Variables.Patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern for variable %s %q: %w", key, pattern, err)
+			}
+			base.variablePatterns[key] = re
+		}
+	}
 	switch targetType {
 	case "kantra":
-		return &kantraValidator{baseValidator: *base}
+		return &kantraValidator{baseValidator: *base}, nil
 	case "tackle-hub":
-		return &tackleHubValidator{baseValidator: *base}
+		return &tackleHubValidator{baseValidator: *base}, nil
 	case "tackle-ui":
-		return &kantraValidator{baseValidator: *base}
+		return &kantraValidator{baseValidator: *base}, nil
 	case "kai-rpc":
-		return &kantraValidator{baseValidator: *base}
+		return &kaiRPCValidator{baseValidator: *base}, nil
 	case "vscode":
-		return &kantraValidator{baseValidator: *base}
+		return &kantraValidator{baseValidator: *base}, nil
 	}
-	return nil
+	// Unknown targets (e.g. Validate without a target) get the default behavior
+	return &kantraValidator{baseValidator: *base}, nil
 }
 
 // ValidationResult contains the result of validation
 type ValidationResult struct {
 	Passed bool
 	Errors []ValidationError
+	// Warnings are noteworthy differences that don't fail the test, e.g. renamed rules
+	Warnings []ValidationError
+	// Diffs are unified YAML diffs of the mismatching rulesets, violations and sections
+	Diffs []ValidationDiff
+	// AllowedMismatches is the number of errors tolerated by expect.allowedMismatches
+	AllowedMismatches int
 }
 
 // ValidationError represents a single validation failure
 type ValidationError struct {
-	Path     string
-	Message  string
-	Expected any
-	Actual   any
+	// Code is the stable kind of the error, e.g. MISSING_INCIDENT
+	Code     ErrorCode `json:"code,omitempty" yaml:"code,omitempty"`
+	Path     string    `json:"path" yaml:"path"`
+	Message  string    `json:"message" yaml:"message"`
+	Expected any       `json:"expected,omitempty" yaml:"expected,omitempty"`
+	Actual   any       `json:"actual,omitempty" yaml:"actual,omitempty"`
+	// Location is the structured location of the error; Pointer renders it as a JSON pointer
+	Location *Location `json:"location,omitempty" yaml:"location,omitempty"`
+	Pointer  string    `json:"pointer,omitempty" yaml:"pointer,omitempty"`
 }
 
 // Print formats and prints the validation error with colors
@@ -78,7 +118,11 @@ func (v ValidationError) Print(index int) {
 	// Print error         number and path
 	yellow := color.New(color.FgYellow, color.Bold)
 	//cyan := color.New(color.FgCyan)
-	yellow.Printf("[%d] %s\n", index, v.Path)
+	yellow.Printf("[%d] %s", index, v.Path)
+	if v.Code != "" {
+		fmt.Printf(" (%s)", v.Code)
+	}
+	fmt.Println()
 
 	// Print message if present
 	if v.Message != "" {
@@ -104,69 +148,179 @@ func Validate(actual, expected []konveyor.RuleSet) (*ValidationResult, error) {
 
 // ValidateFiles performs exact match validation by comparing YAML files directly
 func ValidateFiles(testDir, targetType string, actual, expected []konveyor.RuleSet) (*ValidationResult, error) {
+	return ValidateWithConfig(testDir, targetType, actual, expected, config.ValidationConfig{})
+}
+
+// ValidateExpectations validates actual output against a test's expectations,
+// including incidentCount expectations that the ruleset format cannot express
+// and the allowed number of mismatches
+func ValidateExpectations(testDir, targetType string, actual []konveyor.RuleSet, expect config.ExpectConfig, cfg config.ValidationConfig) (*ValidationResult, error) {
+	expected := expect.Output
+	countErrors := compareIncidentCounts(expected.IncidentCounts, actual)
+
+	// Counted violations have their incidents compared by number only
+	result, err := ValidateWithConfig(testDir, targetType,
+		withoutCountedIncidents(actual, expected.IncidentCounts),
+		withoutCountedIncidents(expected.Result, expected.IncidentCounts), cfg)
+	if err != nil {
+		return nil, err
+	}
+	result.Errors = append(result.Errors, countErrors...)
+	result.Errors = append(result.Errors, compareAbsent(expect.Absent, actual)...)
+	result.Errors = append(result.Errors, compareEffort(expect.Effort, actual)...)
+	setPointers(result.Errors)
+	result.Passed = len(result.Errors) == 0
+	if !result.Passed && expect.AllowedMismatches != nil {
+		result.AllowedMismatches = int(expect.AllowedMismatches.Allowed(countIncidents(expected)))
+		result.Passed = len(result.Errors) <= result.AllowedMismatches
+	}
+	return result, nil
+}
+
+// countIncidents returns the number of expected incidents, the base of percentage allowances.
+// Count-only violations contribute their expected count.
+func countIncidents(expected config.ExpectedOutput) int {
+	total := 0
+	for _, rs := range expected.Result {
+		for _, violations := range []map[string]konveyor.Violation{rs.Violations, rs.Insights} {
+			for ruleID, v := range violations {
+				if count, ok := expected.IncidentCounts.Get(rs.Name, ruleID); ok {
+					total += count.Count
+					continue
+				}
+				total += len(v.Incidents)
+			}
+		}
+	}
+	return total
+}
+
+// ValidateWithConfig validates actual against expected rulesets using the given validation settings
+func ValidateWithConfig(testDir, targetType string, actual, expected []konveyor.RuleSet, cfg config.ValidationConfig) (*ValidationResult, error) {
 	result := &ValidationResult{
 		Passed: true,
 		Errors: []ValidationError{},
 	}
 
-	errors := []ValidationError{}
-	comparer := getComparer(targetType, testDir)
+	actual = withoutDependencyIncidents(actual, testDir, cfg)
+	expected = withoutDependencyIncidents(expected, testDir, cfg)
+	expected = withoutTruncatedIncidents(expected, actual, testDir, cfg.IncidentLimit)
+
+	errors := compareIncidentLimits(actual, cfg)
+	var diffs []ValidationDiff
+	// Rulesets with overrides get their own comparer
+	comparers := map[string]comparer{}
+	for name := range cfg.RuleSets {
+		c, err := getComparer(targetType, testDir, cfg.ForRuleSet(name))
+		if err != nil {
+			return nil, fmt.Errorf("ruleset %s: %w", name, err)
+		}
+		comparers[name] = c
+	}
+	defaultConfig := cfg
+	defaultConfig.RuleSets = nil
+	defaultComparer, err := getComparer(targetType, testDir, defaultConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// Index actual rulesets by name; the first ruleset wins like a linear search would
+	actualByName := make(map[string]konveyor.RuleSet, len(actual))
+	for _, rs := range actual {
+		if _, exists := actualByName[rs.Name]; !exists {
+			actualByName[rs.Name] = rs
+		}
+	}
 
 	for _, ers := range expected {
-		found := false
-		for _, rs := range actual {
-			if rs.Name != ers.Name {
-				continue
+		rs, found := actualByName[ers.Name]
+		if !found {
+			errors = append(errors, ValidationError{
+				Path:     fmt.Sprintf("ruleset/%s", ers.Name),
+				Code:     CodeMissingRuleSet,
+				Message:  "Did not find a matching ruleset",
+				Location: &Location{RuleSet: ers.Name},
+			})
+			if d, ok := subtreeDiff(fmt.Sprintf("ruleset/%s", ers.Name), ers, nil); ok {
+				diffs = append(diffs, d)
 			}
-			found = true
+			continue
+		}
+		comparer, ok := comparers[rs.Name]
+		if !ok {
+			comparer = defaultComparer
+		}
 
-			if !maps.Equal(ers.Errors, rs.Errors) {
-				errs := comparer.compareErrors(ers.Errors, rs.Errors)
-				for i := range errs {
-					errs[i].Path = fmt.Sprintf("%s/error%s", rs.Name, errs[i].Path)
+		if !maps.Equal(ers.Errors, rs.Errors) {
+			errs := comparer.compareErrors(ers.Errors, rs.Errors)
+			for i := range errs {
+				errs[i].Path = fmt.Sprintf("%s/error%s", rs.Name, errs[i].Path)
+			}
+			inRuleSet(errs, rs.Name, "errors")
+			errors = append(errors, errs...)
+			if len(errs) > 0 {
+				if d, ok := subtreeDiff(fmt.Sprintf("%s/error", rs.Name), ers.Errors, rs.Errors); ok {
+					diffs = append(diffs, d)
 				}
-				errors = append(errors, errs...)
 			}
+		}
 
-			if !reflect.DeepEqual(rs.Tags, ers.Tags) {
-				errs := comparer.compareTags(ers.Tags, rs.Tags)
-				for i := range errs {
-					errs[i].Path = fmt.Sprintf("%s/tags%s", rs.Name, errs[i].Path)
-				}
-				errors = append(errors, errs...)
+		if !reflect.DeepEqual(rs.Tags, ers.Tags) {
+			errs := comparer.compareTags(ers.Tags, rs.Tags)
+			for i := range errs {
+				errs[i].Path = fmt.Sprintf("%s/tags%s", rs.Name, errs[i].Path)
 			}
-			if !reflect.DeepEqual(rs.Insights, ers.Insights) {
-				errs := comparer.compareViolations(ers.Insights, rs.Insights)
-				for i := range errs {
-					errs[i].Path = fmt.Sprintf("%s/insights%s", rs.Name, errs[i].Path)
+			inRuleSet(errs, rs.Name, "tags")
+			errors = append(errors, errs...)
+			if len(errs) > 0 {
+				if d, ok := subtreeDiff(fmt.Sprintf("%s/tags", rs.Name), ers.Tags, rs.Tags); ok {
+					diffs = append(diffs, d)
 				}
-				errors = append(errors, errs...)
 			}
-			if !reflect.DeepEqual(rs.Violations, ers.Violations) {
-				errs := comparer.compareViolations(ers.Violations, rs.Violations)
-				for i := range errs {
-					errs[i].Path = fmt.Sprintf("%s/violations%s", rs.Name, errs[i].Path)
-				}
-				errors = append(errors, errs...)
+		}
+		if !reflect.DeepEqual(rs.Insights, ers.Insights) {
+			errs := comparer.compareViolations(ers.Insights, rs.Insights)
+			for i := range errs {
+				errs[i].Path = fmt.Sprintf("%s/insights%s", rs.Name, errs[i].Path)
 			}
-			if !reflect.DeepEqual(rs.Unmatched, ers.Unmatched) {
-				errs := comparer.compareUnmatched(ers.Unmatched, rs.Unmatched)
-				for i := range errs {
-					errs[i].Path = fmt.Sprintf("%s/unmatched%s", rs.Name, errs[i].Path)
-				}
-				errors = append(errors, errs...)
+			inRuleSet(errs, rs.Name, "insights")
+			errors = append(errors, errs...)
+			diffs = append(diffs, violationDiffs(fmt.Sprintf("%s/insights", rs.Name), ers.Insights, rs.Insights, errs)...)
+		}
+		if !reflect.DeepEqual(rs.Violations, ers.Violations) {
+			errs := comparer.compareViolations(ers.Violations, rs.Violations)
+			for i := range errs {
+				errs[i].Path = fmt.Sprintf("%s/violations%s", rs.Name, errs[i].Path)
 			}
-			if !reflect.DeepEqual(rs.Skipped, ers.Skipped) {
-				errs := comparer.compareSkipped(ers.Skipped, rs.Skipped)
-				for i := range errs {
-					errs[i].Path = fmt.Sprintf("%s/skipped%s", rs.Name, errs[i].Path)
+			inRuleSet(errs, rs.Name, "violations")
+			errors = append(errors, errs...)
+			diffs = append(diffs, violationDiffs(fmt.Sprintf("%s/violations", rs.Name), ers.Violations, rs.Violations, errs)...)
+		}
+		if !reflect.DeepEqual(rs.Unmatched, ers.Unmatched) {
+			errs := comparer.compareUnmatched(ers.Unmatched, rs.Unmatched)
+			for i := range errs {
+				errs[i].Path = fmt.Sprintf("%s/unmatched%s", rs.Name, errs[i].Path)
+			}
+			inRuleSet(errs, rs.Name, "unmatched")
+			errors = append(errors, errs...)
+			if len(errs) > 0 {
+				if d, ok := subtreeDiff(fmt.Sprintf("%s/unmatched", rs.Name), ers.Unmatched, rs.Unmatched); ok {
+					diffs = append(diffs, d)
 				}
-				errors = append(errors, errs...)
 			}
-			break
 		}
-		if !found {
-			errors = append(errors, ValidationError{Path: fmt.Sprintf("ruleset/%s", ers.Name), Message: "Did not find a matching ruleset"})
+		if !reflect.DeepEqual(rs.Skipped, ers.Skipped) {
+			errs := comparer.compareSkipped(ers.Skipped, rs.Skipped)
+			for i := range errs {
+				errs[i].Path = fmt.Sprintf("%s/skipped%s", rs.Name, errs[i].Path)
+			}
+			inRuleSet(errs, rs.Name, "skipped")
+			errors = append(errors, errs...)
+			if len(errs) > 0 {
+				if d, ok := subtreeDiff(fmt.Sprintf("%s/skipped", rs.Name), ers.Skipped, rs.Skipped); ok {
+					diffs = append(diffs, d)
+				}
+			}
 		}
 	}
 
@@ -177,16 +331,40 @@ func ValidateFiles(testDir, targetType string, actual, expected []konveyor.RuleS
 	for _, rs := range actual {
 		if !expectedRulesetNames[rs.Name] {
 			errors = append(errors, ValidationError{
-				Path:    fmt.Sprintf("ruleset/%s", rs.Name),
-				Message: fmt.Sprintf("Unexpected ruleset found: %s", rs.Name),
-				Actual:  rs.Name,
+				Path:     fmt.Sprintf("ruleset/%s", rs.Name),
+				Code:     CodeUnexpectedRuleSet,
+				Message:  fmt.Sprintf("Unexpected ruleset found: %s", rs.Name),
+				Actual:   rs.Name,
+				Location: &Location{RuleSet: rs.Name},
 			})
+			if d, ok := subtreeDiff(fmt.Sprintf("ruleset/%s", rs.Name), nil, rs); ok {
+				diffs = append(diffs, d)
+			}
+		}
+	}
+
+	for _, v := range cfg.Validators {
+		errs, err := runExternalValidator(v, testDir, targetType, actual, expected)
+		if err != nil {
+			return nil, err
+		}
+		errors = append(errors, errs...)
+	}
+
+	setPointers(errors)
+
+	// Warnings are reported separately and don't fail the test
+	for _, e := range errors {
+		if e.Code.IsWarning() {
+			result.Warnings = append(result.Warnings, e)
+		} else {
+			result.Errors = append(result.Errors, e)
 		}
 	}
 
 	// If not equal, generate detailed diff
-	result.Passed = len(errors) == 0
-	result.Errors = errors
+	result.Passed = len(result.Errors) == 0
+	result.Diffs = diffs
 
 	return result, nil
 }
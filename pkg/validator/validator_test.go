@@ -1,9 +1,16 @@
 package validator
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	konveyor "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/test-harness/pkg/config"
+	"github.com/konveyor/test-harness/pkg/parser"
 	"go.lsp.dev/uri"
 )
 
@@ -242,11 +249,1546 @@ func TestValidateFiles_WithTargetType(t *testing.T) {
 }
 
 // Helper functions
-func intPtr(i int) *int {
-	return &i
-}
-
 func categoryPtr(s string) *konveyor.Category {
 	c := konveyor.Category(s)
 	return &c
 }
+
+func TestValidateWithConfig_Strict(t *testing.T) {
+	expected := []konveyor.RuleSet{
+		{
+			Name: "test-ruleset",
+			Violations: map[string]konveyor.Violation{
+				"rule1": {
+					Description: "Test",
+					Labels:      []string{"konveyor.io/target=quarkus"},
+					Incidents:   []konveyor.Incident{{URI: uri.File("/test/file.go"), Message: "Test message"}},
+				},
+			},
+		},
+	}
+	actual := []konveyor.RuleSet{
+		{
+			Name: "test-ruleset",
+			Tags: []string{"Java"},
+			Violations: map[string]konveyor.Violation{
+				"rule1": {
+					Description: "Test",
+					Labels:      []string{"konveyor.io/target=quarkus", "konveyor.io/source=java-ee"},
+					Links:       []konveyor.Link{{URL: "https://example.com", Title: "Docs"}},
+					Incidents:   []konveyor.Incident{{URI: uri.File("/test/file.go"), Message: "Test message"}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		targetType string
+		strict     bool
+		wantErrors int
+	}{
+		{"kantra tolerates extra labels and links", "kantra", false, 1},
+		{"kantra strict reports extra labels and links", "kantra", true, 3},
+		{"hub ignores tags", "tackle-hub", false, 0},
+		{"hub strict reports tags, labels and links", "tackle-hub", true, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateWithConfig("/test", tt.targetType, actual, expected, config.ValidationConfig{Strict: tt.strict})
+			if err != nil {
+				t.Fatalf("ValidateWithConfig returned error: %v", err)
+			}
+			if len(result.Errors) != tt.wantErrors {
+				t.Errorf("Expected %d errors, got %d: %v", tt.wantErrors, len(result.Errors), result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateWithConfig_Tolerances(t *testing.T) {
+	line := func(n int) *int { return &n }
+	ruleset := func(incident konveyor.Incident, links ...konveyor.Link) []konveyor.RuleSet {
+		return []konveyor.RuleSet{
+			{
+				Name: "test-ruleset",
+				Violations: map[string]konveyor.Violation{
+					"rule1": {Description: "Test", Links: links, Incidents: []konveyor.Incident{incident}},
+				},
+			},
+		}
+	}
+	yes, no := true, false
+
+	tests := []struct {
+		name       string
+		expected   []konveyor.RuleSet
+		actual     []konveyor.RuleSet
+		cfg        config.ValidationConfig
+		wantErrors int
+		wantErr    bool
+	}{
+		{
+			name:       "line number mismatch fails by default",
+			expected:   ruleset(konveyor.Incident{URI: uri.File("/test/a.java"), Message: "m", LineNumber: line(1)}),
+			actual:     ruleset(konveyor.Incident{URI: uri.File("/test/a.java"), Message: "m", LineNumber: line(2)}),
+			wantErrors: 2,
+		},
+		{
+			name:     "line numbers ignored",
+			expected: ruleset(konveyor.Incident{URI: uri.File("/test/a.java"), Message: "m", LineNumber: line(1)}),
+			actual:   ruleset(konveyor.Incident{URI: uri.File("/test/a.java"), Message: "m", LineNumber: line(2)}),
+			cfg:      config.ValidationConfig{IgnoreLineNumbers: &yes},
+		},
+		{
+			name:     "code snips ignored by default",
+			expected: ruleset(konveyor.Incident{URI: uri.File("/test/a.java"), Message: "m", CodeSnip: "1 foo"}),
+			actual:   ruleset(konveyor.Incident{URI: uri.File("/test/a.java"), Message: "m", CodeSnip: "1 bar"}),
+		},
+		{
+			name:       "code snips compared when enabled",
+			expected:   ruleset(konveyor.Incident{URI: uri.File("/test/a.java"), Message: "m", CodeSnip: "1 foo"}),
+			actual:     ruleset(konveyor.Incident{URI: uri.File("/test/a.java"), Message: "m", CodeSnip: "1 bar"}),
+			cfg:        config.ValidationConfig{IgnoreCodeSnips: &no},
+			wantErrors: 2,
+		},
+		{
+			name:       "variables compared when enabled",
+			expected:   ruleset(konveyor.Incident{URI: uri.File("/test/a.java"), Message: "m", Variables: map[string]interface{}{"name": "a"}}),
+			actual:     ruleset(konveyor.Incident{URI: uri.File("/test/a.java"), Message: "m", Variables: map[string]interface{}{"name": "b"}}),
+			cfg:        config.ValidationConfig{IgnoreVariables: &no},
+			wantErrors: 2,
+		},
+		{
+			name:     "links ignored",
+			expected: ruleset(konveyor.Incident{URI: uri.File("/test/a.java"), Message: "m"}, konveyor.Link{URL: "https://a"}),
+			actual:   ruleset(konveyor.Incident{URI: uri.File("/test/a.java"), Message: "m"}, konveyor.Link{URL: "https://b"}),
+			cfg:      config.ValidationConfig{IgnoreLinks: &yes, Strict: true},
+		},
+		{
+			name:     "message ignore patterns",
+			expected: ruleset(konveyor.Incident{URI: uri.File("/test/a.java"), Message: "upgrade to 1.2.3"}),
+			actual:   ruleset(konveyor.Incident{URI: uri.File("/test/a.java"), Message: "upgrade to 1.4.0"}),
+			cfg:      config.ValidationConfig{MessageIgnorePatterns: []string{`\d+\.\d+\.\d+`}},
+		},
+		{
+			name:     "invalid message pattern",
+			expected: ruleset(konveyor.Incident{URI: uri.File("/test/a.java"), Message: "m"}),
+			actual:   ruleset(konveyor.Incident{URI: uri.File("/test/a.java"), Message: "m"}),
+			cfg:      config.ValidationConfig{MessageIgnorePatterns: []string{"("}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateWithConfig("/test", "kantra", tt.actual, tt.expected, tt.cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateWithConfig returned error: %v", err)
+			}
+			if len(result.Errors) != tt.wantErrors {
+				t.Errorf("Expected %d errors, got %d: %v", tt.wantErrors, len(result.Errors), result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateExpectations_IncidentCount(t *testing.T) {
+	incidents := func(n int) []konveyor.Incident {
+		var out []konveyor.Incident
+		for i := 0; i < n; i++ {
+			out = append(out, konveyor.Incident{URI: uri.File(fmt.Sprintf("/test/file%d.java", i)), Message: "m"})
+		}
+		return out
+	}
+	actual := []konveyor.RuleSet{
+		{
+			Name: "test-ruleset",
+			Violations: map[string]konveyor.Violation{
+				"rule1": {Description: "Test", Incidents: incidents(11)},
+			},
+		},
+	}
+	expected := []konveyor.RuleSet{
+		{
+			Name: "test-ruleset",
+			Violations: map[string]konveyor.Violation{
+				"rule1": {Description: "Test"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		count      config.IncidentCount
+		wantErrors int
+	}{
+		{"exact count", config.IncidentCount{Count: 11}, 0},
+		{"count mismatch", config.IncidentCount{Count: 12}, 1},
+		{"within tolerance", config.IncidentCount{Count: 12, Tolerance: config.Tolerance{Value: 1}}, 0},
+		{"within percentage", config.IncidentCount{Count: 10, Tolerance: config.Tolerance{Value: 10, Percent: true}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := config.ExpectedOutput{
+				Result:         expected,
+				IncidentCounts: config.IncidentCounts{"test-ruleset": {"rule1": tt.count}},
+			}
+			result, err := ValidateExpectations("/test", "kantra", actual, config.ExpectConfig{Output: output}, config.ValidationConfig{})
+			if err != nil {
+				t.Fatalf("ValidateExpectations returned error: %v", err)
+			}
+			if len(result.Errors) != tt.wantErrors {
+				t.Errorf("Expected %d errors, got %d: %v", tt.wantErrors, len(result.Errors), result.Errors)
+			}
+			if result.Passed != (tt.wantErrors == 0) {
+				t.Errorf("Passed = %v", result.Passed)
+			}
+		})
+	}
+
+	// Without a count expectation every incident is compared
+	result, err := ValidateExpectations("/test", "kantra", actual, config.ExpectConfig{Output: config.ExpectedOutput{Result: expected}}, config.ValidationConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passed {
+		t.Error("Expected unlisted incidents to fail without a count expectation")
+	}
+}
+
+func TestValidate_Diffs(t *testing.T) {
+	expected := []konveyor.RuleSet{
+		{
+			Name: "test-ruleset",
+			Violations: map[string]konveyor.Violation{
+				"rule1": {
+					Description: "Test",
+					Incidents:   []konveyor.Incident{{URI: uri.File("/test/a.java"), Message: "old message"}},
+				},
+				"rule2": {Description: "Unchanged"},
+			},
+		},
+		{Name: "missing-ruleset", Tags: []string{"Java"}},
+	}
+	actual := []konveyor.RuleSet{
+		{
+			Name: "test-ruleset",
+			Violations: map[string]konveyor.Violation{
+				"rule1": {
+					Description: "Test",
+					Incidents:   []konveyor.Incident{{URI: uri.File("/test/a.java"), Message: "new message"}},
+				},
+				"rule2": {Description: "Unchanged"},
+			},
+		},
+	}
+
+	result, err := ValidateFiles("/test", "kantra", actual, expected)
+	if err != nil {
+		t.Fatalf("ValidateFiles returned error: %v", err)
+	}
+	if len(result.Diffs) != 2 {
+		t.Fatalf("Expected 2 diffs, got %d: %+v", len(result.Diffs), result.Diffs)
+	}
+
+	violationDiff := result.Diffs[0]
+	if violationDiff.Path != "test-ruleset/violations/rule1" {
+		t.Errorf("Expected diff for rule1, got %s", violationDiff.Path)
+	}
+	for _, want := range []string{"--- expected/test-ruleset/violations/rule1", "-  message: old message", "+  message: new message"} {
+		if !strings.Contains(violationDiff.Diff, want) {
+			t.Errorf("Expected diff to contain %q, got:\n%s", want, violationDiff.Diff)
+		}
+	}
+
+	if result.Diffs[1].Path != "ruleset/missing-ruleset" || !strings.Contains(result.Diffs[1].Diff, "-name: missing-ruleset") {
+		t.Errorf("Expected diff of missing ruleset, got %+v", result.Diffs[1])
+	}
+}
+
+func TestValidateWithConfig_RuleSetOverrides(t *testing.T) {
+	line := func(n int) *int { return &n }
+	ruleset := func(name string, lineNumber int) konveyor.RuleSet {
+		return konveyor.RuleSet{
+			Name: name,
+			Tags: []string{"Java"},
+			Violations: map[string]konveyor.Violation{
+				"rule1": {
+					Description: "Test",
+					Incidents:   []konveyor.Incident{{URI: uri.File("/test/a.java"), Message: "m", LineNumber: line(lineNumber)}},
+				},
+			},
+		}
+	}
+	expected := []konveyor.RuleSet{ruleset("noisy", 1), ruleset("strict", 1)}
+	actual := []konveyor.RuleSet{ruleset("noisy", 2), ruleset("strict", 2)}
+	yes := true
+
+	cfg := config.ValidationConfig{
+		RuleSets: map[string]config.ValidationConfig{
+			"noisy": {IgnoreLineNumbers: &yes},
+		},
+	}
+	result, err := ValidateWithConfig("/test", "kantra", actual, expected, cfg)
+	if err != nil {
+		t.Fatalf("ValidateWithConfig returned error: %v", err)
+	}
+	if len(result.Errors) != 2 {
+		t.Fatalf("Expected 2 errors, got %d: %v", len(result.Errors), result.Errors)
+	}
+	for _, e := range result.Errors {
+		if !strings.HasPrefix(e.Path, "strict/") {
+			t.Errorf("Expected only the non-overridden ruleset to fail, got %s", e.Path)
+		}
+	}
+}
+
+func TestValidateWithConfig_SkipTags(t *testing.T) {
+	expected := []konveyor.RuleSet{{Name: "discovery", Tags: []string{"Java"}}}
+	actual := []konveyor.RuleSet{{Name: "discovery", Tags: []string{"Java", "Spring"}}}
+	yes, no := true, false
+
+	tests := []struct {
+		name       string
+		targetType string
+		cfg        config.ValidationConfig
+		wantErrors int
+	}{
+		{"kantra compares tags", "kantra", config.ValidationConfig{}, 1},
+		{"kantra skips tags for ruleset", "kantra", config.ValidationConfig{RuleSets: map[string]config.ValidationConfig{"discovery": {SkipTags: &yes}}}, 0},
+		{"hub skips tags by default", "tackle-hub", config.ValidationConfig{}, 0},
+		{"hub compares tags for ruleset", "tackle-hub", config.ValidationConfig{RuleSets: map[string]config.ValidationConfig{"discovery": {SkipTags: &no}}}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateWithConfig("/test", tt.targetType, actual, expected, tt.cfg)
+			if err != nil {
+				t.Fatalf("ValidateWithConfig returned error: %v", err)
+			}
+			if len(result.Errors) != tt.wantErrors {
+				t.Errorf("Expected %d errors, got %d: %v", tt.wantErrors, len(result.Errors), result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateWithConfig_ExternalValidator(t *testing.T) {
+	dir := t.TempDir()
+	writeScript := func(name, body string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0755); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	// Fails when the input does not contain the actual ruleset
+	checker := writeScript("check.sh", `if grep -q "name: test-ruleset" ; then
+  echo '{"errors": []}'
+else
+  echo '{"errors": [{"path": "test-ruleset", "message": "ruleset not received"}]}'
+fi
+`)
+	failing := writeScript("fail.sh", `cat > /dev/null
+cat <<'OUT'
+errors:
+  - message: effort total differs from windup
+OUT
+exit 1
+`)
+	broken := writeScript("broken.sh", `cat > /dev/null
+echo "boom" >&2
+exit 2
+`)
+
+	rulesets := []konveyor.RuleSet{{Name: "test-ruleset", Tags: []string{"Java"}}}
+	tests := []struct {
+		name       string
+		validator  config.ExternalValidator
+		wantErrors []string
+		wantErr    bool
+	}{
+		{name: "passing", validator: config.ExternalValidator{Command: []string{checker}}},
+		{name: "reported errors", validator: config.ExternalValidator{Name: "windup", Command: []string{failing}}, wantErrors: []string{"validator/windup: [windup] effort total differs from windup"}},
+		{name: "relative command", validator: config.ExternalValidator{Command: []string{"./fail.sh"}}, wantErrors: []string{"validator/fail.sh: [fail.sh] effort total differs from windup"}},
+		{name: "broken validator", validator: config.ExternalValidator{Command: []string{broken}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.ValidationConfig{Validators: []config.ExternalValidator{tt.validator}}
+			result, err := ValidateWithConfig(dir, "kantra", rulesets, rulesets, cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateWithConfig returned error: %v", err)
+			}
+			var got []string
+			for _, e := range result.Errors {
+				got = append(got, e.Path+": "+e.Message)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.wantErrors, "\n") {
+				t.Errorf("Errors = %v, want %v", got, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidateExpectations_AllowedMismatches(t *testing.T) {
+	incidents := func(n int) []konveyor.Incident {
+		var out []konveyor.Incident
+		for i := 0; i < n; i++ {
+			out = append(out, konveyor.Incident{URI: uri.File(fmt.Sprintf("/test/file%d.java", i)), Message: "m"})
+		}
+		return out
+	}
+	expected := []konveyor.RuleSet{
+		{Name: "test-ruleset", Violations: map[string]konveyor.Violation{"rule1": {Description: "Test", Incidents: incidents(100)}}},
+	}
+	// Two incidents are missing
+	actual := []konveyor.RuleSet{
+		{Name: "test-ruleset", Violations: map[string]konveyor.Violation{"rule1": {Description: "Test", Incidents: incidents(98)}}},
+	}
+
+	tests := []struct {
+		name    string
+		allowed string
+		passed  bool
+	}{
+		{"no allowance", "", false},
+		{"absolute allowance", "2", true},
+		{"absolute allowance too small", "1", false},
+		{"percentage allowance", "2%", true},
+		{"percentage allowance too small", "1.5%", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expect := config.ExpectConfig{Output: config.ExpectedOutput{Result: expected}}
+			if tt.allowed != "" {
+				tol, err := config.ParseTolerance(tt.allowed)
+				if err != nil {
+					t.Fatal(err)
+				}
+				expect.AllowedMismatches = &tol
+			}
+			result, err := ValidateExpectations("/test", "kantra", actual, expect, config.ValidationConfig{})
+			if err != nil {
+				t.Fatalf("ValidateExpectations returned error: %v", err)
+			}
+			if result.Passed != tt.passed {
+				t.Errorf("Passed = %v, want %v (errors: %d, allowed: %d)", result.Passed, tt.passed, len(result.Errors), result.AllowedMismatches)
+			}
+			if len(result.Errors) != 2 {
+				t.Errorf("Expected the 2 mismatches to be reported, got %d", len(result.Errors))
+			}
+		})
+	}
+}
+
+func TestValidateExpectations_Absent(t *testing.T) {
+	actual := []konveyor.RuleSet{
+		{
+			Name: "test-ruleset",
+			Tags: []string{"Java", "EJB"},
+			Violations: map[string]konveyor.Violation{
+				"removed-rule": {Description: "Should be gone", Incidents: []konveyor.Incident{{URI: uri.File("/test/a.java"), Message: "m"}}},
+			},
+		},
+		{Name: "legacy-ruleset", Tags: []string{"Legacy"}},
+	}
+	// Expected output matches actual so only the absent expectations can fail
+	expect := config.ExpectConfig{Output: config.ExpectedOutput{Result: actual}}
+
+	tests := []struct {
+		name      string
+		absent    *config.AbsentExpectations
+		wantPaths []string
+	}{
+		{"nothing absent", nil, nil},
+		{"absent rule present", &config.AbsentExpectations{Rules: []string{"removed-rule"}}, []string{"test-ruleset/violations/removed-rule"}},
+		{"absent ruleset and tag present", &config.AbsentExpectations{RuleSets: []string{"legacy-ruleset"}, Tags: []string{"EJB"}}, []string{"test-ruleset/tags/EJB", "ruleset/legacy-ruleset"}},
+		{"absent entries missing", &config.AbsentExpectations{RuleSets: []string{"other"}, Rules: []string{"other-rule"}, Tags: []string{"Spring"}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expect.Absent = tt.absent
+			result, err := ValidateExpectations("/test", "kantra", actual, expect, config.ValidationConfig{})
+			if err != nil {
+				t.Fatalf("ValidateExpectations returned error: %v", err)
+			}
+			var paths []string
+			for _, e := range result.Errors {
+				paths = append(paths, e.Path)
+			}
+			if strings.Join(paths, ",") != strings.Join(tt.wantPaths, ",") {
+				t.Errorf("Error paths = %v, want %v", paths, tt.wantPaths)
+			}
+			if result.Passed != (len(tt.wantPaths) == 0) {
+				t.Errorf("Passed = %v", result.Passed)
+			}
+		})
+	}
+}
+
+func TestValidateWithConfig_UnmatchedAndSkipped(t *testing.T) {
+	actual := []konveyor.RuleSet{
+		{
+			Name:      "eap8",
+			Tags:      []string{"Java"},
+			Unmatched: []string{"eap8/websphere-001", "eap8/websphere-002", "eap8/jboss-001"},
+			Skipped:   []string{"eap8/skip-001", "eap8/skip-002"},
+		},
+	}
+	ruleset := func(unmatched, skipped []string) []konveyor.RuleSet {
+		return []konveyor.RuleSet{{Name: "eap8", Tags: []string{"Java"}, Unmatched: unmatched, Skipped: skipped}}
+	}
+	allSkipped := []string{"eap8/skip-001", "eap8/skip-002"}
+
+	tests := []struct {
+		name       string
+		expected   []konveyor.RuleSet
+		cfg        config.ValidationConfig
+		wantErrors int
+	}{
+		{"glob covers entries", ruleset([]string{"eap8/websphere-*", "eap8/jboss-001"}, allSkipped), config.ValidationConfig{}, 0},
+		{"glob without match", ruleset([]string{"eap8/websphere-*", "eap8/jboss-001", "eap8/tomcat-*"}, allSkipped), config.ValidationConfig{}, 1},
+		{"entry not covered by glob", ruleset([]string{"eap8/websphere-*"}, allSkipped), config.ValidationConfig{}, 1},
+		{
+			name:     "count only",
+			expected: ruleset([]string{"a", "b", "c"}, []string{"x", "y"}),
+			cfg:      config.ValidationConfig{Unmatched: &config.RuleListComparison{CountOnly: true}, Skipped: &config.RuleListComparison{CountOnly: true}},
+		},
+		{
+			name:       "count mismatch",
+			expected:   ruleset([]string{"a", "b", "c", "d"}, allSkipped),
+			cfg:        config.ValidationConfig{Unmatched: &config.RuleListComparison{CountOnly: true}},
+			wantErrors: 1,
+		},
+		{
+			name:     "count within tolerance",
+			expected: ruleset([]string{"a", "b", "c", "d"}, allSkipped),
+			cfg:      config.ValidationConfig{Unmatched: &config.RuleListComparison{CountOnly: true, CountTolerance: config.Tolerance{Value: 1}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateWithConfig("/test", "kantra", actual, tt.expected, tt.cfg)
+			if err != nil {
+				t.Fatalf("ValidateWithConfig returned error: %v", err)
+			}
+			if len(result.Errors) != tt.wantErrors {
+				t.Errorf("Expected %d errors, got %d: %v", tt.wantErrors, len(result.Errors), result.Errors)
+			}
+		})
+	}
+}
+
+func BenchmarkValidateLargeOutput(b *testing.B) {
+	// Roughly tackle-testapp scale: hundreds of rulesets with thousands of incidents
+	var rulesets []konveyor.RuleSet
+	for r := 0; r < 200; r++ {
+		rs := konveyor.RuleSet{Name: fmt.Sprintf("ruleset-%d", r), Violations: map[string]konveyor.Violation{}}
+		for v := 0; v < 5; v++ {
+			var incidents []konveyor.Incident
+			for i := 0; i < 50; i++ {
+				line := i
+				incidents = append(incidents, konveyor.Incident{URI: uri.File(fmt.Sprintf("/test/file%d.java", i)), Message: "m", LineNumber: &line})
+			}
+			rs.Violations[fmt.Sprintf("rule-%d", v)] = konveyor.Violation{Description: "d", Incidents: incidents}
+		}
+		rulesets = append(rulesets, rs)
+	}
+	// Reverse incidents so every violation goes through the detailed comparison
+	actual := make([]konveyor.RuleSet, len(rulesets))
+	for i, rs := range rulesets {
+		actual[i] = konveyor.RuleSet{Name: rs.Name, Violations: map[string]konveyor.Violation{}}
+		for k, v := range rs.Violations {
+			reversed := slices.Clone(v.Incidents)
+			slices.Reverse(reversed)
+			v.Incidents = reversed
+			actual[i].Violations[k] = v
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ValidateFiles("/test", "kantra", actual, rulesets); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestValidate_Locations(t *testing.T) {
+	line := 10
+	expected := []konveyor.RuleSet{
+		{
+			Name: "azure/springboot",
+			Tags: []string{"Java"},
+			Violations: map[string]konveyor.Violation{
+				"rule1": {
+					Description: "Test",
+					Incidents: []konveyor.Incident{
+						{URI: uri.File("/test/a.java"), LineNumber: &line, Message: "old message"},
+					},
+				},
+				"rule2": {Description: "Missing"},
+			},
+		},
+	}
+	actual := []konveyor.RuleSet{
+		{
+			Name: "azure/springboot",
+			Violations: map[string]konveyor.Violation{
+				"rule1": {
+					Description: "Test",
+					Incidents: []konveyor.Incident{
+						{URI: uri.File("/test/a.java"), LineNumber: &line, Message: "new message"},
+					},
+				},
+			},
+		},
+	}
+
+	result, err := ValidateFiles("/test", "kantra", actual, expected)
+	if err != nil {
+		t.Fatalf("ValidateFiles returned error: %v", err)
+	}
+
+	pointers := map[string]ValidationError{}
+	for _, verr := range result.Errors {
+		pointers[verr.Pointer] = verr
+	}
+	for _, want := range []string{
+		"/azure~1springboot/tags/0",
+		"/azure~1springboot/violations/rule1/incidents/0/message",
+		"/azure~1springboot/violations/rule2",
+	} {
+		if _, ok := pointers[want]; !ok {
+			t.Errorf("Expected error at %s, got %+v", want, result.Errors)
+		}
+	}
+
+	if code := pointers["/azure~1springboot/violations/rule2"].Code; code != CodeMissingViolation {
+		t.Errorf("Expected %s for the missing violation, got %s", CodeMissingViolation, code)
+	}
+	if code := pointers["/azure~1springboot/tags/0"].Code; code != CodeMissingTag {
+		t.Errorf("Expected %s for the missing tag, got %s", CodeMissingTag, code)
+	}
+
+	incidentErr := pointers["/azure~1springboot/violations/rule1/incidents/0/message"]
+	if incidentErr.Location.RuleID != "rule1" || *incidentErr.Location.Incident != 0 || incidentErr.Location.Field != "message" {
+		t.Errorf("Unexpected incident location: %+v", incidentErr.Location)
+	}
+	if incidentErr.Expected == nil && incidentErr.Actual == nil {
+		t.Error("Expected incident error to carry the mismatched incident")
+	}
+}
+
+func TestValidateWithConfig_Links(t *testing.T) {
+	ruleset := func(links ...konveyor.Link) []konveyor.RuleSet {
+		return []konveyor.RuleSet{{
+			Name:       "test-ruleset",
+			Violations: map[string]konveyor.Violation{"rule1": {Description: "Test", Links: links}},
+		}}
+	}
+	docs := konveyor.Link{Title: "Docs", URL: "https://example.com/docs/"}
+
+	redirects := map[string]string{"https://example.com/old": "https://example.com/docs"}
+	resolve := resolveRedirect
+	resolveRedirect = func(link string) string {
+		if final, ok := redirects[link]; ok {
+			return final
+		}
+		return link
+	}
+	defer func() { resolveRedirect = resolve }()
+
+	tests := []struct {
+		name     string
+		actual   konveyor.Link
+		cfg      config.ValidationConfig
+		wantErrs int
+	}{
+		{
+			name:   "normalized URL matches",
+			actual: konveyor.Link{Title: "Docs", URL: "http://EXAMPLE.com:443/docs"},
+		},
+		{
+			name:     "exact URLs",
+			actual:   konveyor.Link{Title: "Docs", URL: "http://example.com/docs"},
+			cfg:      config.ValidationConfig{Links: &config.LinkComparison{ExactURLs: true}},
+			wantErrs: 1,
+		},
+		{
+			name:     "different title",
+			actual:   konveyor.Link{Title: "Documentation", URL: "https://example.com/docs"},
+			wantErrs: 1,
+		},
+		{
+			name:     "redirects not followed by default",
+			actual:   konveyor.Link{Title: "Docs", URL: "https://example.com/old"},
+			wantErrs: 1,
+		},
+		{
+			name:   "redirect followed",
+			actual: konveyor.Link{Title: "Docs", URL: "https://example.com/old"},
+			cfg:    config.ValidationConfig{Links: &config.LinkComparison{FollowRedirects: true}},
+		},
+		{
+			name:     "strict reports the unexpected link too",
+			actual:   konveyor.Link{Title: "Documentation", URL: "https://example.com/docs"},
+			cfg:      config.ValidationConfig{Strict: true},
+			wantErrs: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateWithConfig("/test", "kantra", ruleset(tt.actual), ruleset(docs), tt.cfg)
+			if err != nil {
+				t.Fatalf("ValidateWithConfig returned error: %v", err)
+			}
+			if len(result.Errors) != tt.wantErrs {
+				t.Fatalf("Expected %d errors, got %+v", tt.wantErrs, result.Errors)
+			}
+			if tt.wantErrs > 0 && result.Errors[0].Actual != tt.actual {
+				t.Errorf("Expected missing link to report the closest actual link, got %+v", result.Errors[0].Actual)
+			}
+		})
+	}
+}
+
+func TestValidateWithConfig_CodeSnips(t *testing.T) {
+	line := 68
+	ruleset := func(snip string) []konveyor.RuleSet {
+		return []konveyor.RuleSet{{
+			Name: "test-ruleset",
+			Violations: map[string]konveyor.Violation{"rule1": {
+				Description: "Test",
+				Incidents:   []konveyor.Incident{{URI: uri.File("/test/pom.xml"), LineNumber: &line, CodeSnip: snip}},
+			}},
+		}}
+	}
+	expected := "67  <dependency>\n68  \t<groupId>com.example</groupId>\n69  </dependency>"
+	compare := false
+
+	tests := []struct {
+		name   string
+		actual string
+		mode   string
+		want   bool
+	}{
+		{name: "exact match", actual: expected, want: true},
+		{name: "exact rejects tabs", actual: "67  <dependency>\n68      <groupId>com.example</groupId>\n69  </dependency>"},
+		{name: "whitespace normalized", actual: "67  <dependency>\n68      <groupId>com.example</groupId>\n69  </dependency>", mode: config.CodeSnipWhitespace, want: true},
+		{name: "whitespace rejects other context", actual: "68  <groupId>com.example</groupId>", mode: config.CodeSnipWhitespace},
+		{name: "flagged line ignores context", actual: "66  <!-- deps -->\n67  <dependency>\n68  <groupId>com.example</groupId>", mode: config.CodeSnipFlaggedLine, want: true},
+		{name: "flagged line differs", actual: "68  <groupId>org.example</groupId>", mode: config.CodeSnipFlaggedLine},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.ValidationConfig{IgnoreCodeSnips: &compare, CodeSnips: tt.mode}
+			result, err := ValidateWithConfig("/test", "kantra", ruleset(tt.actual), ruleset(expected), cfg)
+			if err != nil {
+				t.Fatalf("ValidateWithConfig returned error: %v", err)
+			}
+			if result.Passed != tt.want {
+				t.Errorf("Expected passed=%v, got errors %+v", tt.want, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateWithConfig_URINormalization(t *testing.T) {
+	effort := 1
+	ruleset := func(u string) []konveyor.RuleSet {
+		return []konveyor.RuleSet{{
+			Name: "test-ruleset",
+			Violations: map[string]konveyor.Violation{"rule1": {
+				Description: "Test",
+				Effort:      &effort,
+				Incidents:   []konveyor.Incident{{URI: uri.URI(u), Message: "msg"}},
+			}},
+		}}
+	}
+	expected := "file:///source/src/main/java/App.java"
+
+	tests := []struct {
+		name       string
+		targetType string
+		actual     string
+		cfg        config.ValidationConfig
+		want       bool
+	}{
+		{name: "windows drive and container dir", targetType: "kantra", actual: `file:///C:\opt\input\source\src\main\java\App.java`, want: true},
+		{name: "maven cache", targetType: "kantra", actual: "file:///root/.m2/repository/src/main/java/App.java"},
+		{name: "hub clone dir", targetType: "tackle-hub", actual: "file:///addon/source/my-app/src/main/java/App.java", want: true},
+		{name: "hub different file", targetType: "tackle-hub", actual: "file:///addon/source/my-app/src/main/java/Other.java"},
+		{name: "local clone without prefix", targetType: "kantra", actual: "file:///work/clone/src/main/java/App.java"},
+		{
+			name:       "local clone with stripped prefixes",
+			targetType: "kantra",
+			actual:     "file:///work/clone/src/main/java/App.java",
+			cfg:        config.ValidationConfig{StripURIPrefixes: []string{"/source", "/work/clone/"}},
+			want:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateWithConfig("/test", tt.targetType, ruleset(tt.actual), ruleset(expected), tt.cfg)
+			if err != nil {
+				t.Fatalf("ValidateWithConfig returned error: %v", err)
+			}
+			if result.Passed != tt.want {
+				t.Errorf("Expected passed=%v, got errors %+v", tt.want, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateWithConfig_DependencyIncidents(t *testing.T) {
+	source := konveyor.Incident{URI: "file:///source/src/main/java/App.java", Message: "msg"}
+	hubDependency := konveyor.Incident{URI: "file:///cache/m2/org/lib/1.0/lib/Util.java", Message: "msg"}
+	kantraDependency := konveyor.Incident{URI: "file:///root/.m2/repository/org/lib/1.0/lib/Other.java", Message: "msg"}
+	ruleset := func(name string, incidents ...konveyor.Incident) konveyor.RuleSet {
+		return konveyor.RuleSet{
+			Name:       name,
+			Violations: map[string]konveyor.Violation{"rule1": {Description: "Test", Incidents: incidents}},
+		}
+	}
+	expected := []konveyor.RuleSet{ruleset("app", source, hubDependency)}
+	actual := []konveyor.RuleSet{ruleset("app", source, kantraDependency), ruleset("deps-only", kantraDependency)}
+
+	tests := []struct {
+		name string
+		cfg  config.ValidationConfig
+		want bool
+	}{
+		{name: "compared by default"},
+		{name: "ignored", cfg: config.ValidationConfig{DependencyIncidents: config.DependencyIncidentsIgnore}, want: true},
+		{
+			name: "ignored for one ruleset",
+			cfg: config.ValidationConfig{RuleSets: map[string]config.ValidationConfig{
+				"app": {DependencyIncidents: config.DependencyIncidentsIgnore},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateWithConfig("/test", "kantra", actual, expected, tt.cfg)
+			if err != nil {
+				t.Fatalf("ValidateWithConfig returned error: %v", err)
+			}
+			if result.Passed != tt.want {
+				t.Errorf("Expected passed=%v, got errors %+v", tt.want, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateWithConfig_IncidentLimits(t *testing.T) {
+	incident := func(line int, snip string) konveyor.Incident {
+		return konveyor.Incident{URI: "file:///source/App.java", Message: "msg", LineNumber: &line, CodeSnip: snip}
+	}
+	ruleset := func(incidents ...konveyor.Incident) []konveyor.RuleSet {
+		return []konveyor.RuleSet{{
+			Name:       "app",
+			Violations: map[string]konveyor.Violation{"rule1": {Description: "Test", Incidents: incidents}},
+		}}
+	}
+	// Generated without limits
+	expected := ruleset(incident(1, ""), incident(2, ""), incident(3, ""))
+
+	tests := []struct {
+		name      string
+		actual    []konveyor.RuleSet
+		cfg       config.ValidationConfig
+		wantCodes []ErrorCode
+	}{
+		{name: "capped", actual: ruleset(incident(1, ""), incident(3, "")), cfg: config.ValidationConfig{IncidentLimit: 2}},
+		{name: "not capped without limit", actual: ruleset(incident(1, ""), incident(3, "")), wantCodes: []ErrorCode{CodeMissingIncident}},
+		{name: "over the limit", actual: ruleset(incident(1, ""), incident(2, ""), incident(3, "")), cfg: config.ValidationConfig{IncidentLimit: 2},
+			wantCodes: []ErrorCode{CodeIncidentLimitExceeded}},
+		{name: "over the code snip limit", actual: ruleset(incident(1, "a"), incident(2, "b"), incident(3, "")), cfg: config.ValidationConfig{CodeSnipLimit: 1},
+			wantCodes: []ErrorCode{CodeCodeSnipLimitExceeded}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateWithConfig("/test", "kantra", tt.actual, expected, tt.cfg)
+			if err != nil {
+				t.Fatalf("ValidateWithConfig returned error: %v", err)
+			}
+			var codes []ErrorCode
+			for _, e := range result.Errors {
+				codes = append(codes, e.Code)
+			}
+			if !slices.Equal(codes, tt.wantCodes) {
+				t.Errorf("got error codes %v, want %v: %+v", codes, tt.wantCodes, result.Errors)
+			}
+		})
+	}
+}
+
+func TestCompareRuns(t *testing.T) {
+	previous := []konveyor.RuleSet{{
+		Name: "test-ruleset",
+		Violations: map[string]konveyor.Violation{
+			"kept":    {Description: "Kept"},
+			"removed": {Description: "Removed"},
+		},
+	}}
+	current := []konveyor.RuleSet{
+		{
+			Name:       "test-ruleset",
+			Violations: map[string]konveyor.Violation{"kept": {Description: "Kept"}},
+		},
+		{
+			Name:     "new-ruleset",
+			Insights: map[string]konveyor.Violation{"added": {Description: "Added"}},
+		},
+	}
+
+	if result := CompareRuns(previous, previous); !result.Passed {
+		t.Errorf("Expected identical runs to pass, got %+v", result.Errors)
+	}
+
+	result := CompareRuns(previous, current)
+	if result.Passed || len(result.Errors) != 2 {
+		t.Fatalf("Expected 2 errors, got %+v", result.Errors)
+	}
+	if result.Errors[0].Pointer != "/new-ruleset/insights/added" || !strings.HasPrefix(result.Errors[0].Message, "Added insight") {
+		t.Errorf("Expected new insight first, got %+v", result.Errors[0])
+	}
+	if result.Errors[1].Pointer != "/test-ruleset/violations/removed" || !strings.HasPrefix(result.Errors[1].Message, "Removed violation") {
+		t.Errorf("Expected removed violation, got %+v", result.Errors[1])
+	}
+}
+
+func TestValidateExpectations_Effort(t *testing.T) {
+	effort := func(n int) *int { return &n }
+	incidents := func(n int) []konveyor.Incident {
+		var out []konveyor.Incident
+		for i := 0; i < n; i++ {
+			out = append(out, konveyor.Incident{URI: uri.File(fmt.Sprintf("/test/f%d.java", i))})
+		}
+		return out
+	}
+	actual := []konveyor.RuleSet{
+		{
+			Name: "ruleset-a",
+			Violations: map[string]konveyor.Violation{
+				"rule1": {Description: "a", Effort: effort(3), Incidents: incidents(2)},
+				"rule2": {Description: "b", Effort: effort(1), Incidents: incidents(4)},
+			},
+			Insights: map[string]konveyor.Violation{"info": {Description: "i", Incidents: incidents(5)}},
+		},
+		{
+			Name:       "ruleset-b",
+			Violations: map[string]konveyor.Violation{"rule3": {Description: "c", Effort: effort(5), Incidents: incidents(1)}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		effort   config.EffortExpectations
+		wantErrs int
+	}{
+		{name: "matching totals", effort: config.EffortExpectations{Total: effort(15), RuleSets: map[string]int{"ruleset-a": 10, "ruleset-b": 5}}},
+		{name: "total regression", effort: config.EffortExpectations{Total: effort(20)}, wantErrs: 1},
+		{name: "ruleset regression", effort: config.EffortExpectations{RuleSets: map[string]int{"ruleset-a": 8, "missing": 1}}, wantErrs: 2},
+		{name: "within tolerance", effort: config.EffortExpectations{Total: effort(16), Tolerance: config.Tolerance{Value: 10, Percent: true}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expect := config.ExpectConfig{Output: config.ExpectedOutput{Result: actual}, Effort: &tt.effort}
+			result, err := ValidateExpectations("/test", "kantra", actual, expect, config.ValidationConfig{})
+			if err != nil {
+				t.Fatalf("ValidateExpectations returned error: %v", err)
+			}
+			if len(result.Errors) != tt.wantErrs {
+				t.Errorf("Expected %d errors, got %+v", tt.wantErrs, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateWithConfig_Variables(t *testing.T) {
+	ruleset := func(vars map[string]interface{}) []konveyor.RuleSet {
+		return []konveyor.RuleSet{{
+			Name: "test-ruleset",
+			Violations: map[string]konveyor.Violation{"rule1": {
+				Description: "Test",
+				Incidents:   []konveyor.Incident{{URI: uri.File("/test/pom.xml"), Variables: vars}},
+			}},
+		}}
+	}
+	expected := map[string]interface{}{"name": "spring-core", "version": "5.3.1"}
+	compare := false
+	ignore := true
+
+	tests := []struct {
+		name   string
+		actual map[string]interface{}
+		cfg    config.ValidationConfig
+		want   bool
+	}{
+		{name: "ignored by default", actual: map[string]interface{}{"other": 1}, want: true},
+		{name: "explicitly ignored", actual: map[string]interface{}{}, cfg: config.ValidationConfig{IgnoreVariables: &ignore}, want: true},
+		{name: "extra key fails deep equality", actual: map[string]interface{}{"name": "spring-core", "version": "5.3.1", "extra": true}, cfg: config.ValidationConfig{IgnoreVariables: &compare}},
+		{
+			name:   "listed keys only",
+			actual: map[string]interface{}{"name": "spring-core", "version": "6.0.0", "extra": true},
+			cfg:    config.ValidationConfig{IgnoreVariables: &compare, Variables: &config.VariableComparison{Keys: []string{"name"}}},
+			want:   true,
+		},
+		{
+			name:   "listed key differs",
+			actual: map[string]interface{}{"name": "spring-web", "version": "5.3.1"},
+			cfg:    config.ValidationConfig{IgnoreVariables: &compare, Variables: &config.VariableComparison{Keys: []string{"name"}}},
+		},
+		{
+			name:   "value matches pattern",
+			actual: map[string]interface{}{"name": "spring-core", "version": "5.3.27"},
+			cfg:    config.ValidationConfig{IgnoreVariables: &compare, Variables: &config.VariableComparison{Keys: []string{"name"}, Patterns: map[string]string{"version": `^5\.3\.\d+$`}}},
+			want:   true,
+		},
+		{
+			name:   "value does not match pattern",
+			actual: map[string]interface{}{"name": "spring-core", "version": "6.0.0"},
+			cfg:    config.ValidationConfig{IgnoreVariables: &compare, Variables: &config.VariableComparison{Patterns: map[string]string{"version": `^5\.`}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateWithConfig("/test", "kantra", ruleset(tt.actual), ruleset(expected), tt.cfg)
+			if err != nil {
+				t.Fatalf("ValidateWithConfig returned error: %v", err)
+			}
+			if result.Passed != tt.want {
+				t.Errorf("Expected passed=%v, got errors %+v", tt.want, result.Errors)
+			}
+		})
+	}
+
+	bad := config.ValidationConfig{Variables: &config.VariableComparison{Patterns: map[string]string{"version": "("}}}
+	if _, err := ValidateWithConfig("/test", "kantra", nil, nil, bad); err == nil {
+		t.Error("Expected invalid variable pattern to be rejected")
+	}
+}
+
+func TestValidateWithConfig_Errors(t *testing.T) {
+	ruleset := func(errs map[string]string) []konveyor.RuleSet {
+		return []konveyor.RuleSet{{Name: "test-ruleset", Tags: []string{"Java"}, Errors: errs}}
+	}
+	actual := map[string]string{"rule-1": "failed to parse /tmp/java-bin-123/pom.xml at 2025-01-01T10:00:00Z"}
+
+	tests := []struct {
+		name     string
+		expected map[string]string
+		actual   map[string]string
+		cfg      *config.ErrorComparison
+		want     bool
+	}{
+		{name: "exact mismatch", expected: map[string]string{"rule-1": "failed to parse pom.xml"}, actual: actual},
+		{name: "substring", expected: map[string]string{"rule-1": "pom.xml at"}, actual: actual, cfg: &config.ErrorComparison{Match: config.ErrorMatchSubstring}, want: true},
+		{name: "regex", expected: map[string]string{"rule-1": `^failed to parse .*/pom\.xml at \S+$`}, actual: actual, cfg: &config.ErrorComparison{Match: config.ErrorMatchRegex}, want: true},
+		{name: "regex mismatch", expected: map[string]string{"rule-1": `^timeout`}, actual: actual, cfg: &config.ErrorComparison{Match: config.ErrorMatchRegex}},
+		{name: "presence of other rule errors", expected: map[string]string{"rule-2": "anything"}, actual: actual, cfg: &config.ErrorComparison{PresenceOnly: true}, want: true},
+		{name: "presence but no errors", expected: map[string]string{"rule-2": "anything"}, cfg: &config.ErrorComparison{PresenceOnly: true}},
+		{name: "no errors expected", actual: actual, cfg: &config.ErrorComparison{PresenceOnly: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.ValidationConfig{Errors: tt.cfg}
+			result, err := ValidateWithConfig("/test", "kantra", ruleset(tt.actual), ruleset(tt.expected), cfg)
+			if err != nil {
+				t.Fatalf("ValidateWithConfig returned error: %v", err)
+			}
+			if result.Passed != tt.want {
+				t.Errorf("Expected passed=%v, got errors %+v", tt.want, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateWithConfig_KaiRPC(t *testing.T) {
+	line := 3
+	expected := []konveyor.RuleSet{{
+		Name:      "test-ruleset",
+		Unmatched: []string{"rule-unmatched"},
+		Skipped:   []string{"rule-skipped"},
+		Violations: map[string]konveyor.Violation{"rule1": {
+			Description: "Test",
+			Incidents: []konveyor.Incident{{
+				URI: uri.File("/test/App.java"), LineNumber: &line, Message: "msg",
+				CodeSnip: "3  import javax.ejb.Stateless;", Variables: map[string]interface{}{"package": "javax.ejb"},
+			}},
+		}},
+	}}
+	actual := []konveyor.RuleSet{{
+		Name: "test-ruleset",
+		Violations: map[string]konveyor.Violation{"rule1": {
+			Description: "Test",
+			Incidents:   []konveyor.Incident{{URI: uri.File("/test/App.java"), LineNumber: &line, Message: "msg"}},
+		}},
+	}}
+	compare := false
+	cfg := config.ValidationConfig{IgnoreCodeSnips: &compare, IgnoreVariables: &compare}
+
+	result, err := ValidateWithConfig("/test", "kai-rpc", actual, expected, cfg)
+	if err != nil {
+		t.Fatalf("ValidateWithConfig returned error: %v", err)
+	}
+	if !result.Passed {
+		t.Errorf("Expected kai-rpc diagnostics to match, got %+v", result.Errors)
+	}
+
+	result, err = ValidateWithConfig("/test", "kantra", actual, expected, cfg)
+	if err != nil {
+		t.Fatalf("ValidateWithConfig returned error: %v", err)
+	}
+	if result.Passed {
+		t.Error("Expected kantra to compare unmatched, skipped, code snips and variables")
+	}
+
+	// Locations and messages still matter
+	other := 4
+	actual[0].Violations["rule1"].Incidents[0].LineNumber = &other
+	result, err = ValidateWithConfig("/test", "kai-rpc", actual, expected, cfg)
+	if err != nil {
+		t.Fatalf("ValidateWithConfig returned error: %v", err)
+	}
+	if result.Passed {
+		t.Error("Expected kai-rpc to report a moved diagnostic")
+	}
+}
+
+func TestValidateWithConfig_Profiles(t *testing.T) {
+	expected := []konveyor.RuleSet{{Name: "discovery", Tags: []string{"Java"}, Unmatched: []string{"rule-001"}}}
+	actual := []konveyor.RuleSet{{Name: "discovery", Tags: []string{"Java"}}}
+
+	tests := []struct {
+		name       string
+		targetType string
+		profile    string
+		wantErrors int
+	}{
+		{"kantra compares unmatched", "kantra", "", 1},
+		{"kantra with hub profile", "kantra", config.ProfileHubVsKantra, 0},
+		{"hub ignores unmatched", "tackle-hub", "", 0},
+		{"hub with default profile", "tackle-hub", config.ProfileDefault, 1},
+		{"kai-rpc ignores unmatched", "kai-rpc", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateWithConfig("/test", tt.targetType, actual, expected, config.ValidationConfig{Profile: tt.profile})
+			if err != nil {
+				t.Fatalf("ValidateWithConfig returned error: %v", err)
+			}
+			if len(result.Errors) != tt.wantErrors {
+				t.Errorf("Expected %d errors, got %d: %v", tt.wantErrors, len(result.Errors), result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateWithConfig_RenamedRules(t *testing.T) {
+	mandatory, optional := konveyor.Mandatory, konveyor.Optional
+	violation := func(description string, category *konveyor.Category, file string) konveyor.Violation {
+		return konveyor.Violation{
+			Description: description,
+			Category:    category,
+			Incidents:   []konveyor.Incident{{URI: uri.File(file), Message: "m"}},
+		}
+	}
+	expected := []konveyor.RuleSet{{Name: "rs", Violations: map[string]konveyor.Violation{
+		"old-001": violation("Replace javax", &mandatory, "/test/A.java"),
+		"old-002": violation("Remove EJB", &mandatory, "/test/B.java"),
+	}}}
+	yes := true
+
+	tests := []struct {
+		name         string
+		actual       map[string]konveyor.Violation
+		cfg          config.ValidationConfig
+		wantErrors   int
+		wantWarnings int
+	}{
+		{
+			name:       "renames are errors by default",
+			actual:     map[string]konveyor.Violation{"new-001": violation("Replace javax", &mandatory, "/test/A.java"), "old-002": violation("Remove EJB", &mandatory, "/test/B.java")},
+			wantErrors: 2,
+		},
+		{
+			name:         "rename matched by description and category",
+			actual:       map[string]konveyor.Violation{"new-001": violation("Replace javax", &mandatory, "/test/A.java"), "old-002": violation("Remove EJB", &mandatory, "/test/B.java")},
+			cfg:          config.ValidationConfig{MatchRenamedRules: &yes},
+			wantWarnings: 1,
+		},
+		{
+			name:         "renamed rule still compares incidents",
+			actual:       map[string]konveyor.Violation{"new-001": violation("Replace javax", &mandatory, "/test/C.java"), "old-002": violation("Remove EJB", &mandatory, "/test/B.java")},
+			cfg:          config.ValidationConfig{MatchRenamedRules: &yes},
+			wantErrors:   2,
+			wantWarnings: 1,
+		},
+		{
+			name:       "different category is not a rename",
+			actual:     map[string]konveyor.Violation{"new-001": violation("Replace javax", &optional, "/test/A.java"), "old-002": violation("Remove EJB", &mandatory, "/test/B.java")},
+			cfg:        config.ValidationConfig{MatchRenamedRules: &yes},
+			wantErrors: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := []konveyor.RuleSet{{Name: "rs", Violations: tt.actual}}
+			result, err := ValidateWithConfig("/test", "kantra", actual, expected, tt.cfg)
+			if err != nil {
+				t.Fatalf("ValidateWithConfig returned error: %v", err)
+			}
+			if len(result.Errors) != tt.wantErrors || len(result.Warnings) != tt.wantWarnings {
+				t.Errorf("Expected %d errors and %d warnings, got %v and %v", tt.wantErrors, tt.wantWarnings, result.Errors, result.Warnings)
+			}
+			if result.Passed != (tt.wantErrors == 0) {
+				t.Errorf("Passed = %v with %d errors", result.Passed, len(result.Errors))
+			}
+			for _, w := range result.Warnings {
+				if w.Code != CodeRenamedRule || w.Pointer != "/rs/violations/old-001" {
+					t.Errorf("Unexpected warning %+v", w)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeApplicationResults(t *testing.T) {
+	passed := &ValidationResult{Passed: true, Errors: []ValidationError{}}
+	failed := &ValidationResult{
+		Errors: []ValidationError{{
+			Path:     "rs/tags/EJB",
+			Message:  "Unexpected tag found: EJB",
+			Location: &Location{RuleSet: "rs", Section: "tags"},
+		}},
+		Warnings: []ValidationError{{Path: "rs/violation/old", Message: "renamed"}},
+	}
+
+	merged := MergeApplicationResults([]string{"coolstore", "daytrader"}, []*ValidationResult{passed, failed})
+	if merged.Passed || len(merged.Errors) != 1 || len(merged.Warnings) != 1 {
+		t.Fatalf("expected one failing application, got %+v", merged)
+	}
+	e := merged.Errors[0]
+	if e.Path != "application/daytrader/rs/tags/EJB" || e.Message != "[daytrader] Unexpected tag found: EJB" || e.Location.Application != "daytrader" || e.Location.RuleSet != "rs" {
+		t.Errorf("unexpected merged error %+v", e)
+	}
+	if failed.Errors[0].Location.Application != "" {
+		t.Error("expected the application results to be left alone")
+	}
+	if w := merged.Warnings[0]; w.Location == nil || w.Location.Application != "daytrader" {
+		t.Errorf("unexpected merged warning %+v", w)
+	}
+}
+
+func TestValidateLabels(t *testing.T) {
+	sources := []string{"java-ee", "springboot"}
+	targets := []string{"cloud-readiness", "quarkus"}
+	tests := []struct {
+		name      string
+		expect    config.LabelExpectations
+		wantCodes []ErrorCode
+	}{
+		{"subset", config.LabelExpectations{Targets: []string{"quarkus"}}, nil},
+		{"missing", config.LabelExpectations{Sources: []string{"eap"}, Targets: []string{"quarkus"}}, []ErrorCode{CodeMissingLabel}},
+		{"exact", config.LabelExpectations{Sources: sources, Targets: []string{"quarkus"}, Exact: true}, []ErrorCode{CodeUnexpectedLabel}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateLabels(&tt.expect, sources, targets)
+			var codes []ErrorCode
+			for _, err := range result.Errors {
+				codes = append(codes, err.Code)
+			}
+			if !slices.Equal(codes, tt.wantCodes) || result.Passed != (len(tt.wantCodes) == 0) {
+				t.Errorf("got %v (passed %v), want %v", codes, result.Passed, tt.wantCodes)
+			}
+		})
+	}
+	if pointer := ValidateLabels(&config.LabelExpectations{Targets: []string{"eap"}}, nil, nil).Errors[0].Pointer; pointer != "/targets/0" {
+		t.Errorf("Pointer = %q, want /targets/0", pointer)
+	}
+}
+
+func TestValidateProviders(t *testing.T) {
+	javaOutput := config.ExpectedOutput{Result: []konveyor.RuleSet{{Name: "rs", Violations: map[string]konveyor.Violation{"java-001": {}}}}}
+	nodejsOutput := config.ExpectedOutput{Result: []konveyor.RuleSet{{Name: "rs", Violations: map[string]konveyor.Violation{"nodejs-001": {}}}}}
+	actual := []konveyor.RuleSet{{Name: "rs", Violations: map[string]konveyor.Violation{"java-001": {}}}}
+	tests := []struct {
+		name         string
+		expect       *config.ProviderExpectations
+		ran          []string
+		wantCodes    []ErrorCode
+		wantWarnings bool
+	}{
+		{"no expectations", nil, nil, nil, false},
+		{"all ran", &config.ProviderExpectations{Ran: []string{"java", "nodejs"}}, []string{"builtin", "java", "nodejs"}, nil, false},
+		{"skipped", &config.ProviderExpectations{Ran: []string{"java", "nodejs"}}, []string{"builtin", "java"}, []ErrorCode{CodeProviderNotRun}, false},
+		{"skipped with output", &config.ProviderExpectations{Output: map[string]config.ExpectedOutput{"nodejs": nodejsOutput}}, []string{"java"}, []ErrorCode{CodeProviderNotRun}, false},
+		{"exact", &config.ProviderExpectations{Ran: []string{"java"}, Exact: true}, []string{"builtin", "java"}, []ErrorCode{CodeUnexpectedProvider}, false},
+		{"unknown with output produced", &config.ProviderExpectations{Output: map[string]config.ExpectedOutput{"java": javaOutput}}, nil, nil, false},
+		{"unknown with output missing", &config.ProviderExpectations{Output: map[string]config.ExpectedOutput{"java": javaOutput, "nodejs": nodejsOutput}}, nil, []ErrorCode{CodeProviderNotRun}, false},
+		{"unknown without output", &config.ProviderExpectations{Ran: []string{"java"}}, nil, nil, true},
+		{"unknown exact", &config.ProviderExpectations{Output: map[string]config.ExpectedOutput{"java": javaOutput}, Exact: true}, nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateProviders(tt.expect, tt.ran, actual)
+			var codes []ErrorCode
+			for _, err := range result.Errors {
+				codes = append(codes, err.Code)
+			}
+			if !slices.Equal(codes, tt.wantCodes) || result.Passed != (len(tt.wantCodes) == 0) {
+				t.Errorf("got %v (passed %v), want %v", codes, result.Passed, tt.wantCodes)
+			}
+			if warned := len(result.Warnings) > 0; warned != tt.wantWarnings {
+				t.Errorf("warnings = %v, want warnings %v", result.Warnings, tt.wantWarnings)
+			}
+			for _, warning := range result.Warnings {
+				if !warning.Code.IsWarning() {
+					t.Errorf("warning of code %s is not a warning code", warning.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateIssues(t *testing.T) {
+	mandatory := konveyor.Mandatory
+	effort := 3
+	expected := config.ExpectedOutput{
+		Result: []konveyor.RuleSet{{
+			Name: "rs",
+			Violations: map[string]konveyor.Violation{
+				"rule-001": {Category: &mandatory, Effort: &effort, Incidents: []konveyor.Incident{{URI: "file:///a"}, {URI: "file:///b"}}},
+				"rule-002": {Category: &mandatory, Effort: &effort},
+			},
+			Insights: map[string]konveyor.Violation{"insight-001": {}},
+		}},
+		IncidentCounts: config.IncidentCounts{"rs": {"rule-002": {Count: 4}}},
+	}
+	shown := func(changes ...func(*[]parser.Issue)) []parser.Issue {
+		issues := []parser.Issue{
+			{RuleSet: "rs", Rule: "rule-001", Category: "mandatory", Effort: 3, Incidents: 2},
+			{RuleSet: "rs", Rule: "rule-002", Category: "mandatory", Effort: 3, Incidents: 4},
+		}
+		for _, change := range changes {
+			change(&issues)
+		}
+		return issues
+	}
+	tests := []struct {
+		name      string
+		shown     []parser.Issue
+		wantCodes []ErrorCode
+	}{
+		{"matching", shown(), nil},
+		{"missing", shown(func(i *[]parser.Issue) { *i = (*i)[:1] }), []ErrorCode{CodeMissingIssue}},
+		{"unexpected", shown(func(i *[]parser.Issue) { *i = append(*i, parser.Issue{RuleSet: "rs", Rule: "insight-001"}) }), []ErrorCode{CodeUnexpectedIssue}},
+		{"duplicate", shown(func(i *[]parser.Issue) { *i = append(*i, (*i)[0]) }), []ErrorCode{CodeUnexpectedIssue}},
+		{"miscounted", shown(func(i *[]parser.Issue) { (*i)[0].Incidents = 1 }), []ErrorCode{CodeIssueMismatch}},
+		{"wrong category and effort", shown(func(i *[]parser.Issue) { (*i)[1].Category, (*i)[1].Effort = "optional", 1 }), []ErrorCode{CodeIssueMismatch, CodeIssueMismatch}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateIssues(expected, tt.shown)
+			var codes []ErrorCode
+			for _, err := range result.Errors {
+				codes = append(codes, err.Code)
+			}
+			if !slices.Equal(codes, tt.wantCodes) || result.Passed != (len(tt.wantCodes) == 0) {
+				t.Errorf("got %v (passed %v), want %v", codes, result.Passed, tt.wantCodes)
+			}
+		})
+	}
+	if pointer := ValidateIssues(expected, shown(func(i *[]parser.Issue) { (*i)[0].Effort = 1 })).Errors[0].Pointer; pointer != "/rs/violations/rule-001/effort" {
+		t.Errorf("Pointer = %q, want /rs/violations/rule-001/effort", pointer)
+	}
+}
+
+func TestValidateDiagnostics(t *testing.T) {
+	line := func(n int) *int { return &n }
+	expected := config.ExpectedOutput{
+		Result: []konveyor.RuleSet{{
+			Name: "rs",
+			Violations: map[string]konveyor.Violation{
+				"rule-001": {Incidents: []konveyor.Incident{
+					{URI: "file:///source/A.java", LineNumber: line(3), Message: "Replace javax"},
+					{URI: "file:///m2/org/lib/Lib.java", LineNumber: line(7), Message: "In a dependency"},
+				}},
+				"rule-002": {Incidents: []konveyor.Incident{{URI: "file:///source/pom.xml", Message: "No line"}}},
+				"rule-003": {},
+			},
+			Insights: map[string]konveyor.Violation{
+				"insight-001": {Incidents: []konveyor.Incident{{URI: "file:///source/A.java", LineNumber: line(1)}}},
+			},
+		}},
+		IncidentCounts: config.IncidentCounts{"rs": {"rule-003": {Count: 2}}},
+	}
+	shown := func(changes ...func(*[]parser.Diagnostic)) []parser.Diagnostic {
+		diagnostics := []parser.Diagnostic{
+			{URI: "file:///shared/source/A.java", Line: 3, Rule: "rule-001", Message: "Replace javax"},
+			{URI: "file:///shared/source/pom.xml", Line: 1, Rule: "rule-002", Message: "No line"},
+			{URI: "file:///shared/source/B.java", Line: 1, Rule: "rule-003"},
+			{URI: "file:///shared/source/C.java", Line: 1, Rule: "rule-003"},
+		}
+		for _, change := range changes {
+			change(&diagnostics)
+		}
+		return diagnostics
+	}
+	tests := []struct {
+		name        string
+		diagnostics []parser.Diagnostic
+		wantCodes   []ErrorCode
+	}{
+		{"matching", shown(), nil},
+		{"filtered out", shown(func(d *[]parser.Diagnostic) { *d = (*d)[1:] }), []ErrorCode{CodeMissingDiagnostic}},
+		{"wrong line", shown(func(d *[]parser.Diagnostic) { (*d)[0].Line = 4 }), []ErrorCode{CodeMissingDiagnostic, CodeUnexpectedDiagnostic}},
+		{"shown twice", shown(func(d *[]parser.Diagnostic) { *d = append(*d, (*d)[0]) }), []ErrorCode{CodeUnexpectedDiagnostic}},
+		{"insight shown", shown(func(d *[]parser.Diagnostic) {
+			*d = append(*d, parser.Diagnostic{URI: "file:///shared/source/A.java", Line: 1, Rule: "insight-001"})
+		}), []ErrorCode{CodeUnexpectedDiagnostic}},
+		{"rendered message", shown(func(d *[]parser.Diagnostic) { (*d)[0].Message = "Replace &lt;javax&gt;" }), []ErrorCode{CodeDiagnosticMismatch}},
+		{"counted rule miscounted", shown(func(d *[]parser.Diagnostic) { *d = (*d)[:3] }), []ErrorCode{CodeIncidentCountMismatch}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateDiagnostics(expected, tt.diagnostics, "")
+			var codes []ErrorCode
+			for _, err := range result.Errors {
+				codes = append(codes, err.Code)
+			}
+			if !slices.Equal(codes, tt.wantCodes) || result.Passed != (len(tt.wantCodes) == 0) {
+				t.Errorf("got %v (passed %v), want %v", codes, result.Passed, tt.wantCodes)
+			}
+		})
+	}
+}
+
+func TestValidateFixes(t *testing.T) {
+	actual := []konveyor.RuleSet{{
+		Name: "rs",
+		Violations: map[string]konveyor.Violation{
+			"rule-001": {Incidents: []konveyor.Incident{{URI: "file:///app/B.java"}, {URI: "file:///app/A.java"}}},
+		},
+	}}
+	compiles, fails := true, false
+	diff := "--- a/A.java\n+++ b/A.java\n@@ -1 +1 @@\n-import javax.ejb.Stateless;\n+import jakarta.ejb.Stateless;\n"
+	tests := []struct {
+		name            string
+		selected        config.FixIncident
+		suggestion      parser.FixSuggestion
+		requireCompiles bool
+		wantCodes       []ErrorCode
+	}{
+		{"valid", config.FixIncident{RuleSet: "rs", Rule: "rule-001"}, parser.FixSuggestion{File: "file:///app/A.java", Diff: diff, Compiles: &compiles}, true, nil},
+		{"path of the incident's file", config.FixIncident{RuleSet: "rs", Rule: "rule-001"}, parser.FixSuggestion{File: "/app/A.java", Diff: diff}, false, nil},
+		{"second incident", config.FixIncident{RuleSet: "rs", Rule: "rule-001", Incident: 1}, parser.FixSuggestion{File: "file:///app/A.java", Diff: diff}, false, []ErrorCode{CodeFixFileMismatch}},
+		{"incident not found", config.FixIncident{RuleSet: "rs", Rule: "rule-001", Incident: 2}, parser.FixSuggestion{}, false, []ErrorCode{CodeFixIncidentNotFound}},
+		{"rule not found", config.FixIncident{RuleSet: "rs", Rule: "rule-002"}, parser.FixSuggestion{}, false, []ErrorCode{CodeFixIncidentNotFound}},
+		{"empty diff", config.FixIncident{RuleSet: "rs", Rule: "rule-001"}, parser.FixSuggestion{File: "file:///app/A.java", Diff: "\n"}, false, []ErrorCode{CodeEmptyFix}},
+		{"not compiling", config.FixIncident{RuleSet: "rs", Rule: "rule-001"}, parser.FixSuggestion{File: "file:///app/A.java", Diff: diff, Compiles: &fails}, true, []ErrorCode{CodeFixNotCompiling}},
+		{"compiling unknown", config.FixIncident{RuleSet: "rs", Rule: "rule-001"}, parser.FixSuggestion{File: "file:///app/A.java", Diff: diff}, true, []ErrorCode{CodeFixNotCompiling}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requested []string
+			suggest := func(ruleset, rule string, incident konveyor.Incident) (*parser.FixSuggestion, error) {
+				requested = append(requested, string(incident.URI))
+				return &tt.suggestion, nil
+			}
+			fixes := &config.FixConfig{Incidents: []config.FixIncident{tt.selected}, RequireCompiles: tt.requireCompiles}
+			result, err := ValidateFixes(fixes, actual, suggest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var codes []ErrorCode
+			for _, err := range result.Errors {
+				codes = append(codes, err.Code)
+			}
+			if !slices.Equal(codes, tt.wantCodes) || result.Passed != (len(tt.wantCodes) == 0) {
+				t.Errorf("got %v (passed %v), want %v", codes, result.Passed, tt.wantCodes)
+			}
+			if tt.selected.Incident == 0 && tt.selected.Rule == "rule-001" && !slices.Equal(requested, []string{"file:///app/A.java"}) {
+				t.Errorf("expected a fix of the first incident by URI to be requested, got %v", requested)
+			}
+		})
+	}
+
+	failing := func(ruleset, rule string, incident konveyor.Incident) (*parser.FixSuggestion, error) {
+		return nil, fmt.Errorf("connection refused")
+	}
+	if _, err := ValidateFixes(&config.FixConfig{Incidents: []config.FixIncident{{RuleSet: "rs", Rule: "rule-001"}}}, actual, failing); err == nil {
+		t.Error("expected failed suggestions to be returned as errors")
+	}
+}
+
+func TestValidateTransformedFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(path, content string) {
+		t.Helper()
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("expected/rules/01-rules.yaml", "- ruleID: rule-001\n")
+	write("expected/src/App.java", "import jakarta.inject.Inject;\n")
+	write("actual/rules/01-rules.yaml", "- ruleID: rule-001\n")
+	write("actual/src/App.java", "import javax.inject.Inject;\n")
+	write("actual/pom.xml", "<project/>\n")
+
+	tests := []struct {
+		name      string
+		exact     bool
+		wantCodes []ErrorCode
+	}{
+		{"produced files compared", false, []ErrorCode{CodeFileMismatch}},
+		{"exact", true, []ErrorCode{CodeFileMismatch, CodeUnexpectedFile}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateTransformedFiles(filepath.Join(dir, "expected"), filepath.Join(dir, "actual"), tt.exact)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var codes []ErrorCode
+			for _, e := range result.Errors {
+				codes = append(codes, e.Code)
+			}
+			if !slices.Equal(codes, tt.wantCodes) || result.Passed {
+				t.Errorf("got error codes %v, want %v", codes, tt.wantCodes)
+			}
+			if len(result.Diffs) != 1 || !strings.Contains(result.Diffs[0].Diff, "+import javax.inject.Inject;") {
+				t.Errorf("expected a diff of src/App.java, got %+v", result.Diffs)
+			}
+		})
+	}
+
+	write("expected/missing.yaml", "[]\n")
+	result, err := ValidateTransformedFiles(filepath.Join(dir, "expected"), filepath.Join(dir, "actual"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Errors) != 2 || result.Errors[0].Code != CodeMissingFile {
+		t.Errorf("expected a missing file, got %+v", result.Errors)
+	}
+}